@@ -36,9 +36,9 @@ var branchProtectListCmd = &cobra.Command{
 		}
 		patterns := make([][]interface{}, len(*resp.JSON200))
 		for i, rule := range *resp.JSON200 {
-			patterns[i] = []interface{}{rule.Pattern}
+			patterns[i] = []interface{}{rule.Pattern, swag.BoolValue(rule.RequireSignedCommits)}
 		}
-		PrintTable(patterns, []interface{}{"Branch Name Pattern"}, &apigen.Pagination{
+		PrintTable(patterns, []interface{}{"Branch Name Pattern", "Require Signed Commits"}, &apigen.Pagination{
 			HasMore: false,
 			Results: len(patterns),
 		}, len(patterns))
@@ -53,15 +53,20 @@ var branchProtectAddCmd = &cobra.Command{
 	Args:              cobra.ExactArgs(branchProtectAddCmdArgs),
 	ValidArgsFunction: ValidArgsRepository,
 	Run: func(cmd *cobra.Command, args []string) {
+		requireSignedCommits := Must(cmd.Flags().GetBool("require-signed-commits"))
 		client := getClient()
 		u := MustParseRepoURI("repository URI", args[0])
 		resp, err := client.GetBranchProtectionRulesWithResponse(cmd.Context(), u.Repository)
 
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
 		rules := *resp.JSON200
-		rules = append(rules, apigen.BranchProtectionRule{
+		rule := apigen.BranchProtectionRule{
 			Pattern: args[1],
-		})
+		}
+		if requireSignedCommits {
+			rule.RequireSignedCommits = swag.Bool(true)
+		}
+		rules = append(rules, rule)
 		params := &apigen.SetBranchProtectionRulesParams{}
 		etag := swag.String(resp.HTTPResponse.Header.Get("ETag"))
 		if etag != nil && *etag != "" {
@@ -106,6 +111,7 @@ var branchProtectDeleteCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(branchProtectCmd)
 	branchProtectCmd.AddCommand(branchProtectAddCmd)
+	branchProtectAddCmd.Flags().Bool("require-signed-commits", false, "also reject unsigned commits created on matching branches")
 	branchProtectCmd.AddCommand(branchProtectListCmd)
 	branchProtectCmd.AddCommand(branchProtectDeleteCmd)
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const branchRebaseCmdArgs = 2
+
+// lakectl branch rebase lakefs://myrepo/feature lakefs://myrepo/main
+var branchRebaseCmd = &cobra.Command{
+	Use:   "rebase <branch URI> <onto ref URI>",
+	Short: "Replay the branch commits on top of another ref",
+	Long: `Replay the commits made on the branch since its merge base with the given ref on top of that ref, one new commit per replayed commit.
+The branch must not have uncommitted changes. In case of a conflict the rebase fails and the branch is left unchanged.`,
+	Example: "lakectl branch rebase " + myRepoExample + "/" + myBranchExample + " " + myRepoExample + "/main",
+	Args:    cobra.ExactArgs(branchRebaseCmdArgs),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validRepositoryToComplete(cmd.Context(), toComplete)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		branch := MustParseBranchURI("branch URI", args[0])
+		onto := MustParseRefURI("onto ref URI", args[1])
+		if branch.Repository != onto.Repository {
+			Die("both references must belong to the same repository", 1)
+		}
+
+		clt := getClient()
+		resp, err := clt.RebaseBranchWithResponse(cmd.Context(), branch.Repository, branch.Ref, apigen.RebaseBranchJSONRequestBody{
+			Ref: onto.Ref,
+		})
+		if resp != nil && resp.JSON409 != nil {
+			Die("Conflict found.", 1)
+		}
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+
+		Write(commitCreateTemplate, struct {
+			Branch *uri.URI
+			Commit *apigen.Commit
+		}{Branch: branch, Commit: resp.JSON201})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchCmd.AddCommand(branchRebaseCmd)
+}
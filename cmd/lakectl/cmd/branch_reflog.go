@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const branchReflogRestoreFlagName = "restore"
+
+// lakectl branch reflog lakefs://myrepo/main
+var branchReflogCmd = &cobra.Command{
+	Use:   "reflog <branch URI> [--restore <entry ID>]",
+	Short: "Show the history of the branch head, or restore a previous head",
+	Long: `List every movement of the branch head, newest first: the operation that moved it, the user and the commit IDs before and after.
+Use --restore with an entry ID to point the branch back at the head it had before that entry.
+Garbage collection does not keep the commits of the reflog, objects of a restored head may be gone.`,
+	Example: `lakectl branch reflog ` + myRepoExample + `/` + myBranchExample + `
+	lakectl branch reflog ` + myRepoExample + `/` + myBranchExample + ` --restore 7fe6f3b0b3c23c7fcs2qgmi0o7d1k1kdtg80`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseBranchURI("branch URI", args[0])
+		restore := Must(cmd.Flags().GetString(branchReflogRestoreFlagName))
+		client := getClient()
+		if restore != "" {
+			confirmation, err := Confirm(cmd.Flags(), fmt.Sprintf("Are you sure you want to restore branch %s to its head before reflog entry %s", u.Ref, restore))
+			if err != nil || !confirmation {
+				Die("Restore aborted", 1)
+			}
+			resp, err := client.RestoreBranchReflogWithResponse(cmd.Context(), u.Repository, u.Ref, apigen.RestoreBranchReflogJSONRequestBody{
+				EntryId: restore,
+			})
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				Die("Bad response from server", 1)
+			}
+			fmt.Printf("Branch %s restored to commit %s\n", u, resp.JSON200.CommitId)
+			return
+		}
+
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+		resp, err := client.ListBranchReflogWithResponse(cmd.Context(), u.Repository, u.Ref, &apigen.ListBranchReflogParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		entries := resp.JSON200.Results
+		rows := make([][]interface{}, len(entries))
+		for i, e := range entries {
+			rows[i] = []interface{}{e.Id, time.Unix(e.CreationDate, 0).Format(time.RFC3339), e.Operation, apiutil.Value(e.User), e.OldCommitId, e.NewCommitId}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"ID", "Date", "Operation", "User", "Old Commit ID", "New Commit ID"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	AssignAutoConfirmFlag(branchReflogCmd.Flags())
+	branchReflogCmd.Flags().Int("amount", defaultAmountArgumentValue, "number of results to return")
+	branchReflogCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	branchReflogCmd.Flags().String(branchReflogRestoreFlagName, "", "restore the branch to its head before this reflog entry ID")
+
+	branchCmd.AddCommand(branchReflogCmd)
+}
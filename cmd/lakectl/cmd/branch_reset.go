@@ -8,15 +8,19 @@ import (
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 )
 
+const branchResetIfHeadFlagName = "if-head"
+
 // lakectl branch reset lakefs://myrepo/main --commit commitId --prefix path --object path
 var branchResetCmd = &cobra.Command{
-	Use:     "reset <branch URI> [--prefix|--object]",
+	Use:     "reset <branch URI> [--prefix|--object] [--if-head <commit ID>]",
 	Example: "lakectl branch reset " + myRepoExample + "/" + myBranchExample,
 	Short:   "Reset uncommitted changes - all of them, or by path",
 	Long: `reset changes.  There are four different ways to reset changes:
   1. reset all uncommitted changes - reset lakefs://myrepo/main 
   2. reset uncommitted changes under specific path - reset lakefs://myrepo/main --prefix path
-  3. reset uncommitted changes for specific object - reset lakefs://myrepo/main --object path`,
+  3. reset uncommitted changes for specific object - reset lakefs://myrepo/main --object path
+
+Use --if-head to reset only if the branch head is the given commit.`,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: ValidArgsRepository,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -31,6 +35,10 @@ var branchResetCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		ifHead := Must(cmd.Flags().GetString(branchResetIfHeadFlagName))
+		if ifHead != "" && len(object) > 0 {
+			Die("--if-head is not supported when resetting a single object", 1)
+		}
 
 		var reset apigen.ResetCreation
 		var confirmationMsg string
@@ -54,12 +62,19 @@ var branchResetCmd = &cobra.Command{
 			}
 		}
 
+		if ifHead != "" {
+			reset.ExpectedCommitId = &ifHead
+		}
+
 		confirmation, err := Confirm(cmd.Flags(), confirmationMsg)
 		if err != nil || !confirmation {
 			Die("Reset aborted", 1)
 			return
 		}
 		resp, err := clt.ResetBranchWithResponse(cmd.Context(), u.Repository, u.Ref, apigen.ResetBranchJSONRequestBody(reset))
+		if resp != nil && resp.StatusCode() == http.StatusPreconditionFailed {
+			Die("Branch head is not "+ifHead+", reset aborted", 1)
+		}
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
 	},
 }
@@ -70,6 +85,7 @@ func init() {
 
 	branchResetCmd.Flags().String("prefix", "", "prefix of the objects to be reset")
 	branchResetCmd.Flags().String("object", "", "path to object to be reset")
+	branchResetCmd.Flags().String(branchResetIfHeadFlagName, "", "reset only if the branch head is this commit ID")
 
 	branchCmd.AddCommand(branchResetCmd)
 }
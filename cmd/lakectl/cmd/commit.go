@@ -15,6 +15,7 @@ var errInvalidKeyValueFormat = errors.New(`invalid key/value pair - should be se
 const (
 	dateFlagName         = "epoch-time-seconds"
 	allowEmptyCommit     = "allow-empty-commit"
+	commitPrefixFlagName = "prefix"
 	commitCreateTemplate = `Commit for branch "{{.Branch.Ref}}" completed.
 
 ID: {{.Commit.Id|yellow}}
@@ -34,6 +35,7 @@ var commitCmd = &cobra.Command{
 		message, kvPairs := getCommitFlags(cmd)
 		date := Must(cmd.Flags().GetInt64(dateFlagName))
 		emptyCommitBool := Must(cmd.Flags().GetBool(allowEmptyCommit))
+		prefixes := Must(cmd.Flags().GetStringSlice(commitPrefixFlagName))
 		datePtr := &date
 		if date < 0 {
 			datePtr = nil
@@ -46,13 +48,17 @@ var commitCmd = &cobra.Command{
 		metadata := apigen.CommitCreation_Metadata{
 			AdditionalProperties: kvPairs,
 		}
-		client := getClient()
-		resp, err := client.CommitWithResponse(cmd.Context(), branchURI.Repository, branchURI.Ref, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+		body := apigen.CommitJSONRequestBody{
 			Message:    message,
 			Metadata:   &metadata,
 			Date:       datePtr,
 			AllowEmpty: &emptyCommitBool,
-		})
+		}
+		if len(prefixes) > 0 {
+			body.Paths = &prefixes
+		}
+		client := getClient()
+		resp, err := client.CommitWithResponse(cmd.Context(), branchURI.Repository, branchURI.Ref, &apigen.CommitParams{}, body)
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
 		if resp.JSON201 == nil {
 			Die("Bad response from server", 1)
@@ -70,6 +76,7 @@ var commitCmd = &cobra.Command{
 func init() {
 	commitCmd.Flags().Int64(dateFlagName, -1, "create commit with a custom unix epoch date in seconds")
 	commitCmd.Flags().Bool(allowEmptyCommit, false, "allow a commit with no changes")
+	commitCmd.Flags().StringSlice(commitPrefixFlagName, nil, "commit only the changes under this path prefix, other changes remain uncommitted (can be repeated)")
 	if err := commitCmd.Flags().MarkHidden(dateFlagName); err != nil {
 		DieErr(err)
 	}
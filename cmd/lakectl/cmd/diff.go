@@ -21,7 +21,13 @@ const (
 	minDiffPageSize = 50
 	maxDiffPageSize = 1000
 
-	twoWayFlagName = "two-way"
+	twoWayFlagName    = "two-way"
+	statFlagName      = "stat"
+	statDepthFlagName = "stat-depth"
+
+	diffStatTemplate = `{{ range $s := .Summary.Prefixes }} {{ $s.Path|printf "%-*s" $.Width }} | {{ printf "+%d" $s.Added|green }} {{ printf "-%d" $s.Removed|red }} {{ printf "~%d" $s.Changed|yellow }}
+{{ end }} {{ len .Summary.Prefixes }} prefixes changed, {{ .Summary.Total.Added }} added ({{ .Summary.Total.AddedBytes|human_bytes }}), {{ .Summary.Total.Removed }} removed ({{ .Summary.Total.RemovedBytes|human_bytes }}), {{ .Summary.Total.Changed }} changed ({{ .Summary.Total.ChangedBytes|human_bytes }})
+`
 )
 
 var diffCmd = &cobra.Command{
@@ -42,7 +48,10 @@ var diffCmd = &cobra.Command{
 	Uncommitted changes are not shown.
 
 	lakectl diff --%s lakefs://example-repo/main lakefs://example-repo/dev$
-	Show changes between the tip of the main and the dev branch, including uncommitted changes on dev.`, twoWayFlagName, twoWayFlagName),
+	Show changes between the tip of the main and the dev branch, including uncommitted changes on dev.
+
+	lakectl diff --%s --%s 2 lakefs://example-repo/main lakefs://example-repo/dev
+	Show the number and size of changed objects, grouped by the first two levels of their path.`, twoWayFlagName, twoWayFlagName, statFlagName, statDepthFlagName),
 
 	Args: cobra.RangeArgs(diffCmdMinArgs, diffCmdMaxArgs),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -53,10 +62,16 @@ var diffCmd = &cobra.Command{
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
+		stat := Must(cmd.Flags().GetBool(statFlagName))
+		statDepth := Must(cmd.Flags().GetInt(statDepthFlagName))
 		if len(args) == diffCmdMinArgs {
 			// got one arg ref: uncommitted changes diff
 			branchURI := MustParseBranchURI("branch URI", args[0])
 			fmt.Println("Ref:", branchURI)
+			if stat {
+				printDiffSummary(cmd.Context(), client, branchURI.Repository, branchURI.Ref, branchURI.Ref+"$", true, statDepth)
+				return
+			}
 			printDiffBranch(cmd.Context(), client, branchURI.Repository, branchURI.Ref)
 			return
 		}
@@ -68,6 +83,10 @@ var diffCmd = &cobra.Command{
 		if leftRefURI.Repository != rightRefURI.Repository {
 			Die("both references must belong to the same repository", 1)
 		}
+		if stat {
+			printDiffSummary(cmd.Context(), client, leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref, twoWay, statDepth)
+			return
+		}
 		printDiffRefs(cmd.Context(), client, leftRefURI, rightRefURI, twoWay)
 	},
 }
@@ -123,6 +142,33 @@ func printDiffRefs(ctx context.Context, client apigen.ClientWithResponsesInterfa
 	}
 }
 
+func printDiffSummary(ctx context.Context, client apigen.ClientWithResponsesInterface, repository, left, right string, twoDot bool, depth int) {
+	diffType := "three_dot"
+	if twoDot {
+		diffType = "two_dot"
+	}
+	resp, err := client.DiffRefsSummaryWithResponse(ctx, repository, left, right, &apigen.DiffRefsSummaryParams{
+		Depth: apiutil.Ptr(depth),
+		Type:  apiutil.Ptr(diffType),
+	})
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+	if resp.JSON200 == nil {
+		Die("Bad response from server", 1)
+	}
+	width := 0
+	for i, s := range resp.JSON200.Prefixes {
+		if s.Path == "" {
+			// changes directly under the root
+			resp.JSON200.Prefixes[i].Path = "/"
+		}
+		width = max(width, len(resp.JSON200.Prefixes[i].Path))
+	}
+	Write(diffStatTemplate, struct {
+		Summary *apigen.DiffSummary
+		Width   int
+	}{Summary: resp.JSON200, Width: width})
+}
+
 func FmtDiff(d apigen.Diff, withDirection bool) {
 	action, color := diff.Fmt(d.Type)
 
@@ -140,6 +186,8 @@ func FmtDiff(d apigen.Diff, withDirection bool) {
 //nolint:gochecknoinits
 func init() {
 	diffCmd.Flags().Bool(twoWayFlagName, false, "Use two-way diff: show difference between the given refs, regardless of a common ancestor.")
+	diffCmd.Flags().Bool(statFlagName, false, "Show the number and size of changed objects by path prefix instead of the changed objects")
+	diffCmd.Flags().Int(statDepthFlagName, 1, "Number of path levels used to group the changes shown by --"+statFlagName)
 
 	rootCmd.AddCommand(diffCmd)
 }
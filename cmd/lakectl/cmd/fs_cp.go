@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	fsCpSummaryTemplate = `Copied {{ .CopiedObjects | yellow }} object(s) ({{ .CopiedBytes | human_bytes }}) to {{ .Destination | yellow }}
+`
+	fsCpStatusPollInterval = 2 * time.Second
+)
+
+var fsCpCmd = &cobra.Command{
+	Use:   "cp <source path URI> <destination path URI>",
+	Short: "Copy objects, possibly across repositories",
+	Long: `Copy objects on the lakeFS server, possibly across repositories.
+Copying recursively runs on the server as a background task, the command reports its progress until it completes.
+A shallow copy references the data of the source objects instead of copying it.`,
+	Example: "lakectl fs cp --recursive lakefs://source-repo/main/data/ lakefs://dest-repo/main/data/",
+	Args:    cobra.ExactArgs(2), //nolint:gomnd
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		recursive := Must(flags.GetBool(recursiveFlagName))
+		shallow := Must(flags.GetBool("shallow"))
+		force := Must(flags.GetBool("force"))
+		noProgress := Must(flags.GetBool("no-progress"))
+		srcURI := MustParsePathURI("source path URI", args[0])
+		destURI := MustParsePathURI("destination path URI", args[1])
+
+		ctx := cmd.Context()
+		client := getClient()
+		if !recursive {
+			resp, err := client.CopyObjectWithResponse(ctx, destURI.Repository, destURI.Ref, &apigen.CopyObjectParams{
+				DestPath: apiutil.Value(destURI.Path),
+			}, apigen.CopyObjectJSONRequestBody{
+				SrcPath:       apiutil.Value(srcURI.Path),
+				SrcRef:        apiutil.Ptr(srcURI.Ref),
+				SrcRepository: apiutil.Ptr(srcURI.Repository),
+				Shallow:       apiutil.Ptr(shallow),
+				Force:         apiutil.Ptr(force),
+			})
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+			if resp.JSON201 == nil {
+				Die("Bad response from server", 1)
+			}
+			Write(fsStatTemplate, resp.JSON201)
+			return
+		}
+
+		// copy the "directories" of the paths
+		srcPrefix := apiutil.Value(srcURI.Path)
+		destPrefix := apiutil.Value(destURI.Path)
+		if srcPrefix != "" && !strings.HasSuffix(srcPrefix, uri.PathSeparator) {
+			srcPrefix += uri.PathSeparator
+		}
+		if destPrefix != "" && !strings.HasSuffix(destPrefix, uri.PathSeparator) {
+			destPrefix += uri.PathSeparator
+		}
+		startResp, err := client.CopyPrefixStartWithResponse(ctx, destURI.Repository, destURI.Ref, apigen.CopyPrefixStartJSONRequestBody{
+			SrcRepository: apiutil.Ptr(srcURI.Repository),
+			SrcRef:        apiutil.Ptr(srcURI.Ref),
+			SrcPrefix:     srcPrefix,
+			DestPrefix:    destPrefix,
+			Shallow:       apiutil.Ptr(shallow),
+			Force:         apiutil.Ptr(force),
+		})
+		DieOnErrorOrUnexpectedStatusCode(startResp, err, http.StatusAccepted)
+		if startResp.JSON202 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		bar := newCopyProgressBar(!noProgress)
+		ticker := time.NewTicker(fsCpStatusPollInterval)
+		defer ticker.Stop()
+		var status *apigen.PrefixCopyStatus
+		for range ticker.C {
+			statusResp, err := client.CopyPrefixStatusWithResponse(ctx, destURI.Repository, destURI.Ref, &apigen.CopyPrefixStatusParams{Id: startResp.JSON202.Id})
+			DieOnErrorOrUnexpectedStatusCode(statusResp, err, http.StatusOK)
+			status = statusResp.JSON200
+			if status == nil {
+				Die("Bad response from server", 1)
+			}
+			if status.Error != nil {
+				_ = bar.Clear()
+				DieFmt("Copy failed after %d object(s): %s", status.CopiedObjects, *status.Error)
+			}
+			if status.TotalObjects > 0 {
+				bar.ChangeMax64(status.TotalObjects)
+			}
+			_ = bar.Set64(status.CopiedObjects)
+			if status.Done {
+				break
+			}
+		}
+		_ = bar.Clear()
+
+		Write(fsCpSummaryTemplate, struct {
+			CopiedObjects int64
+			CopiedBytes   int64
+			Destination   string
+		}{
+			CopiedObjects: status.CopiedObjects,
+			CopiedBytes:   status.CopiedBytes,
+			Destination:   destURI.String(),
+		})
+	},
+}
+
+func newCopyProgressBar(visible bool) *progressbar.ProgressBar {
+	const (
+		barWidth    = 10
+		barThrottle = 65 * time.Millisecond
+	)
+	bar := progressbar.NewOptions64(
+		-1,
+		progressbar.OptionSetDescription("Copying"),
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionSetWidth(barWidth),
+		progressbar.OptionThrottle(barThrottle),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetItsString("object"),
+		progressbar.OptionOnCompletion(func() {
+			_, _ = fmt.Fprint(os.Stderr, "\n")
+		}),
+		progressbar.OptionFullWidth(),
+		progressbar.OptionSetVisibility(visible),
+	)
+	_ = bar.RenderBlank()
+	return bar
+}
+
+//nolint:gochecknoinits
+func init() {
+	withRecursiveFlag(fsCpCmd, "recursively copy all objects under the source path")
+	fsCpCmd.Flags().Bool("shallow", false, "reference the data of the source objects instead of copying it")
+	fsCpCmd.Flags().Bool("force", false, "copy into a read-only destination repository")
+	fsCpCmd.Flags().Bool("no-progress", false, "switch off the progress output")
+
+	fsCmd.AddCommand(fsCpCmd)
+}
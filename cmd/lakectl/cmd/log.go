@@ -68,10 +68,13 @@ func (d *dotWriter) Write(commits []apigen.Commit) {
 
 // logCmd represents the log command
 var logCmd = &cobra.Command{
-	Use:               "log <branch URI>",
-	Short:             "Show log of commits",
-	Long:              "Show log of commits for a given branch",
-	Example:           "lakectl log --dot lakefs://example-repository/main | dot -Tsvg > graph.svg",
+	Use:   "log <branch URI>",
+	Short: "Show log of commits",
+	Long:  "Show log of commits for a given branch",
+	Example: `lakectl log --dot lakefs://example-repository/main | dot -Tsvg > graph.svg
+
+	lakectl log --author airflow --meta job_id=123 lakefs://example-repository/main
+	Show commits made by airflow with metadata job_id=123`,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: ValidArgsRepository,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -84,6 +87,9 @@ var logCmd = &cobra.Command{
 		objects := Must(cmd.Flags().GetStringSlice("objects"))
 		prefixes := Must(cmd.Flags().GetStringSlice("prefixes"))
 		stopAt := Must(cmd.Flags().GetString("stop-at"))
+		until := Must(cmd.Flags().GetString("until"))
+		author := Must(cmd.Flags().GetString("author"))
+		meta := Must(cmd.Flags().GetStringArray("meta"))
 
 		if slices.Contains(objects, "") {
 			Die("Objects list contains empty string!", 1)
@@ -91,6 +97,11 @@ var logCmd = &cobra.Command{
 		if slices.Contains(prefixes, "") {
 			Die("Prefixes list contains empty string!", 1)
 		}
+		for _, kv := range meta {
+			if k, _, found := strings.Cut(kv, "="); !found || k == "" {
+				DieFmt("Invalid metadata predicate '%s', expected key=value", kv)
+			}
+		}
 
 		pagination := apigen.Pagination{HasMore: true}
 		showMetaRangeID := Must(cmd.Flags().GetBool("show-meta-range-id"))
@@ -120,6 +131,19 @@ var logCmd = &cobra.Command{
 			}
 			logCommitsParams.Since = &sinceParsed
 		}
+		if until != "" {
+			untilParsed, err := time.Parse(time.RFC3339, until)
+			if err != nil {
+				DieFmt("Failed to parse 'until' - %s", err)
+			}
+			logCommitsParams.Until = &untilParsed
+		}
+		if author != "" {
+			logCommitsParams.Committer = &author
+		}
+		if len(meta) > 0 {
+			logCommitsParams.Metadata = &meta
+		}
 
 		graph := &dotWriter{
 			w:            os.Stdout,
@@ -182,4 +206,7 @@ func init() {
 	logCmd.Flags().StringSlice("prefixes", nil, "show results that contains changes to at least one path in that list of prefixes. Use comma separator to pass all prefixes together")
 	logCmd.Flags().String("since", "", "show results since this date-time (RFC3339 format)")
 	logCmd.Flags().String("stop-at", "", "a Ref to stop at (included in results)")
+	logCmd.Flags().String("until", "", "show results until this date-time (RFC3339 format)")
+	logCmd.Flags().String("author", "", "show only commits made by this committer")
+	logCmd.Flags().StringArray("meta", nil, "show only commits with this metadata key=value, can be repeated to match all of the pairs")
 }
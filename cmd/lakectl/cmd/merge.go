@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/uri"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -13,9 +17,17 @@ const (
 	mergeCmdMaxArgs = 5
 
 	mergeCreateTemplate = `Merged "{{.Merge.FromRef|yellow}}" into "{{.Merge.ToRef|yellow}}" to get "{{.Result.Reference|green}}".
+`
+	mergeConflictTemplate = `{{ "Conflict:" | red }} {{ .Path | yellow }}
+  base:        {{ with .Base }}{{ .Checksum }} {{ .SizeBytes | human_bytes }} {{ .PhysicalAddress }}{{ else }}-{{ end }}
+  source:      {{ with .Source }}{{ .Checksum }} {{ .SizeBytes | human_bytes }} {{ .PhysicalAddress }}{{ else }}-{{ end }}
+  destination: {{ with .Destination }}{{ .Checksum }} {{ .SizeBytes | human_bytes }} {{ .PhysicalAddress }}{{ else }}-{{ end }}
 `
 )
 
+// mergeStrategies lists the valid values of the merge strategy flag
+var mergeStrategies = []string{"", "dest-wins", "source-wins", "newest-wins", "larger-wins"}
+
 type FromTo struct {
 	FromRef string
 	ToRef   string
@@ -45,14 +57,21 @@ var mergeCmd = &cobra.Command{
 			Die("both references must belong to the same repository", 1)
 		}
 
-		if strategy != "dest-wins" && strategy != "source-wins" && strategy != "" {
-			Die("Invalid strategy value. Expected \"dest-wins\" or \"source-wins\"", 1)
+		if !slices.Contains(mergeStrategies, strategy) {
+			Die("Invalid strategy value. Expected \"dest-wins\", \"source-wins\", \"newest-wins\" or \"larger-wins\"", 1)
+		}
+
+		if Must(cmd.Flags().GetBool("dry-run")) {
+			printMergeConflicts(cmd.Context(), client, sourceRef, destinationRef, strategy)
+			return
 		}
 
+		squash := Must(cmd.Flags().GetBool("squash"))
 		body := apigen.MergeIntoBranchJSONRequestBody{
-			Message:  &message,
-			Metadata: &apigen.Merge_Metadata{AdditionalProperties: kvPairs},
-			Strategy: &strategy,
+			Message:     &message,
+			Metadata:    &apigen.Merge_Metadata{AdditionalProperties: kvPairs},
+			Strategy:    &strategy,
+			SquashMerge: &squash,
 		}
 		resp, err := client.MergeIntoBranchWithResponse(cmd.Context(), destinationRef.Repository, sourceRef.Ref, destinationRef.Ref, body)
 		if resp != nil && resp.JSON409 != nil {
@@ -76,9 +95,44 @@ var mergeCmd = &cobra.Command{
 	},
 }
 
+func printMergeConflicts(ctx context.Context, client apigen.ClientWithResponsesInterface, sourceRef, destinationRef *uri.URI, strategy string) {
+	var (
+		after     string
+		conflicts int
+	)
+	pageSize := pageSize(minDiffPageSize)
+	for {
+		resp, err := client.ListMergeConflictsWithResponse(ctx, destinationRef.Repository, sourceRef.Ref, destinationRef.Ref, &apigen.ListMergeConflictsParams{
+			After:    apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount:   apiutil.Ptr(apigen.PaginationAmount(pageSize)),
+			Strategy: &strategy,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		for _, conflict := range resp.JSON200.Results {
+			Write(mergeConflictTemplate, conflict)
+		}
+		conflicts += len(resp.JSON200.Results)
+		pagination := resp.JSON200.Pagination
+		if !pagination.HasMore {
+			break
+		}
+		after = pagination.NextOffset
+		pageSize.Next()
+	}
+	if conflicts > 0 {
+		Die(fmt.Sprintf("Found %d conflicts.", conflicts), 1)
+	}
+	fmt.Println("No conflicts found.")
+}
+
 //nolint:gochecknoinits
 func init() {
-	mergeCmd.Flags().String("strategy", "", "In case of a merge conflict, this option will force the merge process to automatically favor changes from the dest branch (\"dest-wins\") or from the source branch(\"source-wins\"). In case no selection is made, the merge process will fail in case of a conflict")
+	mergeCmd.Flags().String("strategy", "", "In case of a merge conflict, this option will force the merge process to automatically favor changes from the dest branch (\"dest-wins\") or from the source branch(\"source-wins\"), or resolve each conflicting object by favoring the most recently modified (\"newest-wins\") or the larger (\"larger-wins\") version. In case no selection is made, or the conflict cannot be resolved, the merge process will fail in case of a conflict")
+	mergeCmd.Flags().Bool("dry-run", false, "List the conflicts of the merge without merging")
+	mergeCmd.Flags().Bool("squash", false, "Squash all changes from source into a single commit on destination, with destination as its only parent")
 	withCommitFlags(mergeCmd, true)
 	rootCmd.AddCommand(mergeCmd)
 }
@@ -29,10 +29,16 @@ var repoCreateCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		storageID := Must(cmd.Flags().GetString("storage-id"))
+		var storageIDParam *string
+		if storageID != "" {
+			storageIDParam = &storageID
+		}
 		resp, err := clt.CreateRepositoryWithResponse(cmd.Context(),
 			&apigen.CreateRepositoryParams{},
 			apigen.CreateRepositoryJSONRequestBody{
 				Name:             u.Repository,
+				StorageId:        storageIDParam,
 				StorageNamespace: args[1],
 				DefaultBranch:    &defaultBranch,
 			})
@@ -48,6 +54,7 @@ var repoCreateCmd = &cobra.Command{
 //nolint:gochecknoinits
 func init() {
 	repoCreateCmd.Flags().StringP("default-branch", "d", DefaultBranch, "the default branch of this repository")
+	repoCreateCmd.Flags().String("storage-id", "", "the ID of the blockstore holding the repository data, the default blockstore if not set")
 
 	repoCmd.AddCommand(repoCreateCmd)
 }
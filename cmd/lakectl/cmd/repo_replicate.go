@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var repoReplicateCmd = &cobra.Command{
+	Use:   "replicate",
+	Short: "Manage the replication of a repository to a secondary storage namespace",
+	Long:  "Replicates the data of each commit of the repository: the objects under the repository storage namespace, and the ranges and metaranges of the commit. Commit, branch and tag metadata are not replicated, nor are objects outside the storage namespace, such as imported objects.",
+}
+
+//nolint:gochecknoinits
+func init() {
+	repoCmd.AddCommand(repoReplicateCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+const replicationStatusTemplate = `Pending Commits: {{ .PendingCommits }}
+Lag: {{ .LagSeconds }}s
+{{ if .LastCommitId }}Last Replicated Commit: {{ .LastCommitId }} ({{ .LastCommitCreationDate | date }})
+{{ end }}Replicated Objects: {{ .ReplicatedObjects }}
+Replicated Ranges: {{ .ReplicatedRanges }}
+{{ if .Error }}Error: {{ .Error }}
+{{ end }}`
+
+var repoReplicateStatusCmd = &cobra.Command{
+	Use:               "status <repository URI>",
+	Short:             "Show the replication status of the repository",
+	Example:           "lakectl repo replicate status " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+		isJSON := Must(cmd.Flags().GetBool(jsonFlagName))
+		client := getClient()
+		resp, err := client.GetReplicationStatusWithResponse(cmd.Context(), u.Repository)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		if isJSON {
+			Write("{{ . | json }}", resp.JSON200)
+		} else {
+			Write(replicationStatusTemplate, resp.JSON200)
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	repoReplicateStatusCmd.Flags().BoolP(jsonFlagName, "p", false, "get status as JSON")
+
+	repoReplicateCmd.AddCommand(repoReplicateStatusCmd)
+}
@@ -3,10 +3,14 @@ package cmd
 import (
 	"net/http"
 
+	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 )
 
+const commitVerifiedTemplate = `Signature:     {{ "verified"|green }}
+`
+
 // showCommitCmd represents the show command
 var showCommitCmd = &cobra.Command{
 	Use:               "commit <commit URI>",
@@ -16,6 +20,7 @@ var showCommitCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		commitURI := MustParseRefURI("commit URI", args[0])
 		showMetaRangeID := Must(cmd.Flags().GetBool("show-meta-range-id"))
+		verify := Must(cmd.Flags().GetBool("verify"))
 
 		ctx := cmd.Context()
 		client := getClient()
@@ -36,12 +41,23 @@ var showCommitCmd = &cobra.Command{
 		}
 
 		Write(commitsTemplate, commits)
+		if !verify {
+			return
+		}
+		switch {
+		case commit.Signature == nil:
+			DieFmt("Commit %s is not signed", commit.Id)
+		case !swag.BoolValue(commit.Verified):
+			DieFmt("Commit %s signature was not made by a trusted key", commit.Id)
+		}
+		Write(commitVerifiedTemplate, nil)
 	},
 }
 
 //nolint:gochecknoinits
 func init() {
 	showCommitCmd.Flags().Bool("show-meta-range-id", false, "show meta range ID")
+	showCommitCmd.Flags().Bool("verify", false, "verify the commit signature, fail if the commit is not signed by a trusted key")
 
 	showCmd.AddCommand(showCommitCmd)
 }
@@ -166,7 +166,7 @@ var runCmd = &cobra.Command{
 			stats.WithLogger(logger.WithField("service", "stats_collector")))
 
 		// init block store
-		blockStore, err := factory.BuildBlockAdapter(ctx, bufferedCollector, cfg)
+		blockStore, err := factory.BuildStorageAdapter(ctx, bufferedCollector, cfg.StorageConfigs())
 		if err != nil {
 			logger.WithError(err).Fatal("Failed to create block adapter")
 		}
@@ -198,6 +198,10 @@ var runCmd = &cobra.Command{
 		if err != nil {
 			logger.WithError(err).Fatal("Failed to schedule cleanup jobs")
 		}
+		err = scheduleMultipartUploadsExpiry(ctx, deleteScheduler, c, multipartTracker, blockStore, cfg.Gateways.S3.MultipartUploadExpiry)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to schedule multipart uploads expiry")
+		}
 		deleteScheduler.StartAsync()
 
 		// initial setup - support only when a local database is configured.
@@ -406,8 +410,11 @@ func checkRepos(ctx context.Context, logger logging.Logger, authMetadataManager
 				logger.WithError(err).Fatal("Checking existing repositories failed")
 			}
 
-			adapterStorageType := blockStore.BlockstoreType()
 			for _, repo := range repos {
+				adapter, err := block.GetStorageAdapter(blockStore, repo.StorageID)
+				if err != nil {
+					logger.WithError(err).Fatalf("Repository %s blockstore is not configured", repo.Name)
+				}
 				nsURL, err := url.Parse(repo.StorageNamespace)
 				if err != nil {
 					logger.WithError(err).Fatalf("Failed to parse repository %s namespace '%s'", repo.Name, repo.StorageNamespace)
@@ -417,7 +424,7 @@ func checkRepos(ctx context.Context, logger logging.Logger, authMetadataManager
 					logger.WithError(err).Fatalf("Failed to parse to parse storage type '%s'", nsURL)
 				}
 
-				checkForeignRepo(repoStorageType, logger, adapterStorageType, repo.Name)
+				checkForeignRepo(repoStorageType, logger, adapter.BlockstoreType(), repo.Name)
 				next = repo.Name
 			}
 		}
@@ -462,6 +469,30 @@ func scheduleCleanupJobs(ctx context.Context, s *gocron.Scheduler, c *catalog.Ca
 	return nil
 }
 
+// scheduleMultipartUploadsExpiry schedules aborting the multipart uploads of the S3 gateway older than expiry, unless
+// expiry is 0
+func scheduleMultipartUploadsExpiry(ctx context.Context, s *gocron.Scheduler, c *catalog.Catalog, tracker multipart.Tracker, adapter block.Adapter, expiry time.Duration) error {
+	const expireMultipartUploadsInterval = time.Hour
+	if expiry <= 0 {
+		return nil
+	}
+	job, err := s.Every(expireMultipartUploadsInterval).Do(func(ctx context.Context) {
+		log := logging.FromContext(ctx).WithField("service", "multipart_expiry")
+		expired, err := multipart.ExpireUploads(ctx, tracker, c, adapter, time.Now().Add(-expiry))
+		if err != nil {
+			log.WithError(err).Error("Failed to expire multipart uploads")
+		}
+		if expired > 0 {
+			log.WithField("expired", expired).Info("Expired multipart uploads")
+		}
+	}, ctx)
+	if err != nil {
+		return fmt.Errorf("schedule expire multipart uploads failed: %w", err)
+	}
+	job.SingletonMode()
+	return nil
+}
+
 // checkForeignRepo checks whether a repo storage namespace matches the block adapter.
 // A foreign repo is a repository which namespace doesn't match the current block adapter.
 // A foreign repo might exist if the lakeFS instance configuration changed after a repository was
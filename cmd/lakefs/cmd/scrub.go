@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvparams"
+	"github.com/treeverse/lakefs/pkg/upload"
+	"go.uber.org/ratelimit"
+)
+
+const ScrubCmdNumArgs = 2
+
+var errScrubProblems = errors.New("scrub found problems")
+
+var scrubCmd = &cobra.Command{
+	Use:   "scrub <repository> <ref>",
+	Short: "Verify the objects of a ref against their size and checksum, and the metaranges and ranges of all commits",
+	Long: `Verify the objects of a ref against their size and checksum, and the metaranges and ranges of all commits.
+Missing, truncated or corrupted objects and missing metaranges or ranges are reported, and the command exits with
+status 1 in case any were found. An interrupted scrub is resumed by passing the last path it reported to --after,
+together with --skip-ranges in case it already verified the ranges.`,
+	Args: cobra.ExactArgs(ScrubCmdNumArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := loadConfig()
+		flags := cmd.Flags()
+		after, err := flags.GetString("after")
+		if err != nil {
+			return err
+		}
+		skipRanges, err := flags.GetBool("skip-ranges")
+		if err != nil {
+			return err
+		}
+		rateLimit, err := flags.GetInt("rate-limit")
+		if err != nil {
+			return err
+		}
+
+		// problems found are reported by an error, which is not a usage error
+		cmd.SilenceUsage = true
+
+		ctx := cmd.Context()
+		kvParams, err := kvparams.NewConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("KV params: %w", err)
+		}
+		kvStore, err := kv.Open(ctx, kvParams)
+		if err != nil {
+			return fmt.Errorf("failed to open KV store: %w", err)
+		}
+		defer kvStore.Close()
+
+		c, err := catalog.New(ctx, catalog.Config{
+			Config:       cfg,
+			KVStore:      kvStore,
+			PathProvider: upload.DefaultPathProvider,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create catalog: %w", err)
+		}
+		defer func() { _ = c.Close() }()
+
+		limiter := c.BackgroundLimiter
+		if rateLimit > 0 {
+			limiter = ratelimit.New(rateLimit)
+		}
+		status := &catalog.ScrubStatus{
+			Ref:            args[1],
+			RangesVerified: skipRanges,
+			LastPath:       after,
+		}
+		err = c.Scrub(ctx, args[0], status, limiter, func(_ context.Context, status *catalog.ScrubStatus) error {
+			fmt.Fprintf(os.Stderr, "verified %d ranges and %d objects, last path: %s\n", status.VerifiedRanges, status.VerifiedObjects, status.LastPath)
+			return nil
+		})
+		for _, problem := range status.Problems {
+			fmt.Printf("%s\t%s\t%s\t%s\n", problem.Kind, problem.Path, problem.PhysicalAddress, problem.Details)
+		}
+		if status.ProblemsCount > int64(len(status.Problems)) {
+			fmt.Printf("... and %d more problems\n", status.ProblemsCount-int64(len(status.Problems)))
+		}
+		if err != nil {
+			return fmt.Errorf("scrub failed after path '%s': %w", status.LastPath, err)
+		}
+		if status.ProblemsCount > 0 {
+			return fmt.Errorf("%w: %d", errScrubProblems, status.ProblemsCount)
+		}
+		fmt.Fprintln(os.Stderr, "scrub completed, no problems found")
+		return nil
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(scrubCmd)
+	f := scrubCmd.Flags()
+	f.String("after", "", "verify only objects after this path, resuming an interrupted scrub")
+	f.Bool("skip-ranges", false, "skip verifying the metaranges and ranges of commits")
+	f.Int("rate-limit", 0, "maximal number of objects verified per second, defaults to the background rate limit")
+}
@@ -7,6 +7,7 @@ import (
 )
 
 type HookOutputWriter struct {
+	StorageID        string
 	StorageNamespace string
 	RunID            string
 	HookRunID        string
@@ -23,7 +24,7 @@ const (
 
 func (h *HookOutputWriter) OutputWrite(ctx context.Context, reader io.Reader, size int64) error {
 	name := FormatHookOutputPath(h.RunID, h.HookRunID)
-	return h.Writer.OutputWrite(ctx, h.StorageNamespace, name, reader, size)
+	return h.Writer.OutputWrite(ctx, h.StorageID, h.StorageNamespace, name, reader, size)
 }
 
 func FormatHookOutputPath(runID, hookRunID string) string {
@@ -21,14 +21,16 @@ func TestHookWriter_OutputWritePath(t *testing.T) {
 
 	const hookID = "hookID"
 	const actionName = "actionName"
+	const storageID = "storageID"
 	const storageNamespace = "storageNamespace"
 	hooks := graveler.HooksNoOp{}
 	runID := hooks.NewRunID()
 	hookRunID := hooks.NewRunID()
 	writer := mock.NewMockOutputWriter(ctrl)
-	writer.EXPECT().OutputWrite(ctx, storageNamespace, actions.FormatHookOutputPath(runID, hookRunID), contentReader, int64(len(content))).Return(nil)
+	writer.EXPECT().OutputWrite(ctx, storageID, storageNamespace, actions.FormatHookOutputPath(runID, hookRunID), contentReader, int64(len(content))).Return(nil)
 
 	w := &actions.HookOutputWriter{
+		StorageID:        storageID,
 		StorageNamespace: storageNamespace,
 		RunID:            runID,
 		HookID:           hookID,
@@ -52,7 +54,7 @@ func TestHookWriter_OutputWriteError(t *testing.T) {
 	hookRunID := hooks.NewRunID()
 	errSomeError := errors.New("some error")
 	writer := mock.NewMockOutputWriter(ctrl)
-	writer.EXPECT().OutputWrite(ctx, "storageNamespace", actions.FormatHookOutputPath(runID, hookRunID), gomock.Any(), gomock.Any()).Return(errSomeError)
+	writer.EXPECT().OutputWrite(ctx, "", "storageNamespace", actions.FormatHookOutputPath(runID, hookRunID), gomock.Any(), gomock.Any()).Return(errSomeError)
 
 	w := &actions.HookOutputWriter{
 		RunID:            runID,
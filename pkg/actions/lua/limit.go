@@ -0,0 +1,48 @@
+package lua
+
+import (
+	"context"
+
+	"github.com/Shopify/go-lua"
+)
+
+// limitCheckInterval is the number of instructions run between checks of the run limit
+const limitCheckInterval = 1000
+
+// RunLimit bounds the Lua code run on a state: each run started by Start fails once its context is done, or once it
+// runs more than the maximal number of instructions.
+type RunLimit struct {
+	ctx             context.Context
+	maxInstructions int
+	instructions    int
+}
+
+// SetRunLimit sets a run limit of maxInstructions on l. Scripts cannot remove the limit, as 'debug.sethook' is
+// removed from the state.
+func SetRunLimit(l *lua.State, maxInstructions int) *RunLimit {
+	r := &RunLimit{ctx: context.Background(), maxInstructions: maxInstructions}
+	lua.SetDebugHook(l, r.check, lua.MaskCount, limitCheckInterval)
+	l.Global("debug")
+	if l.IsTable(-1) {
+		l.PushNil()
+		l.SetField(-2, "sethook")
+	}
+	l.Pop(1)
+	return r
+}
+
+// Start starts a run bounded by ctx and the maximal number of instructions
+func (r *RunLimit) Start(ctx context.Context) {
+	r.ctx = ctx
+	r.instructions = 0
+}
+
+func (r *RunLimit) check(l *lua.State, _ lua.Debug) {
+	if err := r.ctx.Err(); err != nil {
+		lua.Errorf(l, "run stopped: %s", err.Error())
+	}
+	r.instructions += limitCheckInterval
+	if r.instructions > r.maxInstructions {
+		lua.Errorf(l, "run exceeded %d instructions", r.maxInstructions)
+	}
+}
@@ -90,15 +90,15 @@ func (m *MockOutputWriter) EXPECT() *MockOutputWriterMockRecorder {
 }
 
 // OutputWrite mocks base method.
-func (m *MockOutputWriter) OutputWrite(arg0 context.Context, arg1, arg2 string, arg3 io.Reader, arg4 int64) error {
+func (m *MockOutputWriter) OutputWrite(arg0 context.Context, arg1, arg2, arg3 string, arg4 io.Reader, arg5 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "OutputWrite", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "OutputWrite", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // OutputWrite indicates an expected call of OutputWrite.
-func (mr *MockOutputWriterMockRecorder) OutputWrite(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockOutputWriterMockRecorder) OutputWrite(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutputWrite", reflect.TypeOf((*MockOutputWriter)(nil).OutputWrite), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutputWrite", reflect.TypeOf((*MockOutputWriter)(nil).OutputWrite), arg0, arg1, arg2, arg3, arg4, arg5)
 }
@@ -6,5 +6,5 @@ import (
 )
 
 type OutputWriter interface {
-	OutputWrite(ctx context.Context, storageNamespace, name string, reader io.Reader, size int64) error
+	OutputWrite(ctx context.Context, storageID, storageNamespace, name string, reader io.Reader, size int64) error
 }
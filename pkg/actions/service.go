@@ -210,7 +210,7 @@ type TaskResultIterator interface {
 type Service interface {
 	Stop()
 	Run(ctx context.Context, record graveler.HookRecord) error
-	UpdateCommitID(ctx context.Context, repositoryID string, storageNamespace string, runID string, commitID string) error
+	UpdateCommitID(ctx context.Context, repositoryID string, storageID string, storageNamespace string, runID string, commitID string) error
 	GetRunResult(ctx context.Context, repositoryID string, runID string) (*RunResult, error)
 	GetTaskResult(ctx context.Context, repositoryID string, runID string, hookRunID string) (*TaskResult, error)
 	ListRunResults(ctx context.Context, repositoryID string, branchID, commitID string, after string) (RunResultIterator, error)
@@ -347,6 +347,7 @@ func (s *StoreService) runTasks(ctx context.Context, record graveler.HookRecord,
 			for _, task := range actionTasks {
 				hookOutputWriter := &HookOutputWriter{
 					Writer:           s.Writer,
+					StorageID:        record.StorageID.String(),
 					StorageNamespace: record.StorageNamespace.String(),
 					RunID:            task.RunID,
 					HookRunID:        task.HookRunID,
@@ -431,10 +432,10 @@ func (s *StoreService) saveRunInformation(ctx context.Context, record graveler.H
 		return fmt.Errorf("insert run information: %w", err)
 	}
 
-	return s.saveRunManifestObjectStore(ctx, manifest, record.StorageNamespace.String(), record.RunID)
+	return s.saveRunManifestObjectStore(ctx, manifest, record.StorageID.String(), record.StorageNamespace.String(), record.RunID)
 }
 
-func (s *StoreService) saveRunManifestObjectStore(ctx context.Context, manifest RunManifest, storageNamespace string, runID string) error {
+func (s *StoreService) saveRunManifestObjectStore(ctx context.Context, manifest RunManifest, storageID, storageNamespace string, runID string) error {
 	manifestJSON, err := json.Marshal(manifest)
 	if err != nil {
 		return fmt.Errorf("marshal run manifest: %w", err)
@@ -442,7 +443,7 @@ func (s *StoreService) saveRunManifestObjectStore(ctx context.Context, manifest
 	runManifestPath := FormatRunManifestOutputPath(runID)
 	manifestReader := bytes.NewReader(manifestJSON)
 	manifestSize := int64(len(manifestJSON))
-	return s.Writer.OutputWrite(ctx, storageNamespace, runManifestPath, manifestReader, manifestSize)
+	return s.Writer.OutputWrite(ctx, storageID, storageNamespace, runManifestPath, manifestReader, manifestSize)
 }
 
 func (s *StoreService) saveRunManifestDB(ctx context.Context, repositoryID graveler.RepositoryID, manifest RunManifest) error {
@@ -490,7 +491,7 @@ func buildRunManifestFromTasks(record graveler.HookRecord, tasks [][]*Task) RunM
 }
 
 // UpdateCommitID assume record is a post event, we use the PreRunID to update the commit_id and save the run manifest again
-func (s *StoreService) UpdateCommitID(ctx context.Context, repositoryID string, storageNamespace string, runID string, commitID string) error {
+func (s *StoreService) UpdateCommitID(ctx context.Context, repositoryID string, storageID string, storageNamespace string, runID string, commitID string) error {
 	manifest, err := s.Store.UpdateCommitID(ctx, repositoryID, runID, commitID)
 	if err != nil {
 		return fmt.Errorf("updating commit ID: %w", err)
@@ -500,7 +501,7 @@ func (s *StoreService) UpdateCommitID(ctx context.Context, repositoryID string,
 	}
 
 	// update manifest
-	return s.saveRunManifestObjectStore(ctx, *manifest, storageNamespace, runID)
+	return s.saveRunManifestObjectStore(ctx, *manifest, storageID, storageNamespace, runID)
 }
 
 func (s *StoreService) GetRunResult(ctx context.Context, repositoryID string, runID string) (*RunResult, error) {
@@ -525,7 +526,7 @@ func (s *StoreService) PreCommitHook(ctx context.Context, record graveler.HookRe
 
 func (s *StoreService) PostCommitHook(ctx context.Context, record graveler.HookRecord) error {
 	// update pre-commit with commit ID if needed
-	err := s.UpdateCommitID(ctx, record.RepositoryID.String(), record.StorageNamespace.String(), record.PreRunID, record.CommitID.String())
+	err := s.UpdateCommitID(ctx, record.RepositoryID.String(), record.StorageID.String(), record.StorageNamespace.String(), record.PreRunID, record.CommitID.String())
 	if err != nil {
 		return err
 	}
@@ -540,7 +541,7 @@ func (s *StoreService) PreMergeHook(ctx context.Context, record graveler.HookRec
 
 func (s *StoreService) PostMergeHook(ctx context.Context, record graveler.HookRecord) error {
 	// update pre-merge with commit ID if needed
-	err := s.UpdateCommitID(ctx, record.RepositoryID.String(), record.StorageNamespace.String(), record.PreRunID, record.CommitID.String())
+	err := s.UpdateCommitID(ctx, record.RepositoryID.String(), record.StorageID.String(), record.StorageNamespace.String(), record.PreRunID, record.CommitID.String())
 	if err != nil {
 		return err
 	}
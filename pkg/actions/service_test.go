@@ -122,32 +122,32 @@ hooks:
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			testOutputWriter.EXPECT().
-				OutputWrite(ctx, record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, expectedWebhookRunID), gomock.Any(), gomock.Any()).
+				OutputWrite(ctx, record.StorageID.String(), record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, expectedWebhookRunID), gomock.Any(), gomock.Any()).
 				Return(nil).
-				DoAndReturn(func(ctx context.Context, storageNamespace, name string, reader io.Reader, size int64) error {
+				DoAndReturn(func(ctx context.Context, storageID, storageNamespace, name string, reader io.Reader, size int64) error {
 					var err error
 					writerBytes, err = io.ReadAll(reader)
 					return err
 				})
 			testOutputWriter.EXPECT().
-				OutputWrite(ctx, record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, expectedAirflowHookRunIDWithConf), gomock.Any(), gomock.Any()).
+				OutputWrite(ctx, record.StorageID.String(), record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, expectedAirflowHookRunIDWithConf), gomock.Any(), gomock.Any()).
 				Return(nil).
-				DoAndReturn(func(ctx context.Context, storageNamespace, name string, reader io.Reader, size int64) error {
+				DoAndReturn(func(ctx context.Context, storageID, storageNamespace, name string, reader io.Reader, size int64) error {
 					var err error
 					writerBytes, err = io.ReadAll(reader)
 					return err
 				})
 			testOutputWriter.EXPECT().
-				OutputWrite(ctx, record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, expectedAirflowHookRunIDWithoutConf), gomock.Any(), gomock.Any()).
+				OutputWrite(ctx, record.StorageID.String(), record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, expectedAirflowHookRunIDWithoutConf), gomock.Any(), gomock.Any()).
 				Return(nil).
-				DoAndReturn(func(ctx context.Context, storageNamespace, name string, reader io.Reader, size int64) error {
+				DoAndReturn(func(ctx context.Context, storageID, storageNamespace, name string, reader io.Reader, size int64) error {
 					var err error
 					writerBytes, err = io.ReadAll(reader)
 					return err
 				})
 			testOutputWriter.EXPECT().
-				OutputWrite(ctx, record.StorageNamespace.String(), actions.FormatRunManifestOutputPath(record.RunID), gomock.Any(), gomock.Any()).
-				DoAndReturn(func(ctx context.Context, storageNamespace, name string, reader io.Reader, size int64) error {
+				OutputWrite(ctx, record.StorageID.String(), record.StorageNamespace.String(), actions.FormatRunManifestOutputPath(record.RunID), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, storageID, storageNamespace, name string, reader io.Reader, size int64) error {
 					data, err := io.ReadAll(reader)
 					if err != nil {
 						return err
@@ -193,7 +193,7 @@ hooks:
 			lastManifest = nil
 
 			// update commit using post event record
-			err = actionsService.UpdateCommitID(ctx, record.RepositoryID.String(), record.StorageNamespace.String(), record.RunID, "commit1")
+			err = actionsService.UpdateCommitID(ctx, record.RepositoryID.String(), record.StorageID.String(), record.StorageNamespace.String(), record.RunID, "commit1")
 			if err != nil {
 				t.Fatalf("UpdateCommitID() failed with err=%s", err)
 			}
@@ -459,19 +459,19 @@ hooks:
 		t.Run(tt.Name, func(t *testing.T) {
 			testOutputWriter, ctrl, _, record := setupTest(t)
 			defer ctrl.Finish()
-			outputWriteReturn := func(ctx context.Context, storageNamespace, name string, reader io.Reader, size int64) error {
+			outputWriteReturn := func(ctx context.Context, storageID, storageNamespace, name string, reader io.Reader, size int64) error {
 				_, err := io.ReadAll(reader)
 				return err
 			}
 			for _, idx := range tt.ExpectedHookIndexes {
 				hookRunID := actions.NewHookRunID(0, idx)
 				testOutputWriter.EXPECT().
-					OutputWrite(gomock.Any(), record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, hookRunID), gomock.Any(), gomock.Any()).
+					OutputWrite(gomock.Any(), record.StorageID.String(), record.StorageNamespace.String(), actions.FormatHookOutputPath(record.RunID, hookRunID), gomock.Any(), gomock.Any()).
 					Return(nil).
 					DoAndReturn(outputWriteReturn)
 			}
 			testOutputWriter.EXPECT().
-				OutputWrite(gomock.Any(), record.StorageNamespace.String(), actions.FormatRunManifestOutputPath(record.RunID), gomock.Any(), gomock.Any()).
+				OutputWrite(gomock.Any(), record.StorageID.String(), record.StorageNamespace.String(), actions.FormatRunManifestOutputPath(record.RunID), gomock.Any(), gomock.Any()).
 				Return(nil).
 				DoAndReturn(outputWriteReturn)
 
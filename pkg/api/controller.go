@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -46,6 +47,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/upload"
 	"github.com/treeverse/lakefs/pkg/validator"
 	"github.com/treeverse/lakefs/pkg/version"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -180,7 +182,7 @@ func (c *Controller) CreatePresignMultipartUpload(w http.ResponseWriter, r *http
 
 	// generate a new address for the object we like to upload
 	address := c.PathProvider.NewPath()
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, address, block.IdentifierTypeRelative)
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, address, block.IdentifierTypeRelative)
 	if err != nil {
 		writeError(w, r, http.StatusInternalServerError, err)
 		return
@@ -192,6 +194,7 @@ func (c *Controller) CreatePresignMultipartUpload(w http.ResponseWriter, r *http
 
 	// create a new multipart upload
 	mpuResp, err := c.BlockAdapter.CreateMultiPartUpload(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       address,
@@ -205,6 +208,7 @@ func (c *Controller) CreatePresignMultipartUpload(w http.ResponseWriter, r *http
 	for i := 0; i < swag.IntValue(params.Parts); i++ {
 		// generate a pre-signed PUT url for the given request
 		preSignedURL, err := c.BlockAdapter.GetPresignUploadPartURL(ctx, block.ObjectPointer{
+			StorageID:        repo.StorageID,
 			StorageNamespace: repo.StorageNamespace,
 			Identifier:       address,
 			IdentifierType:   block.IdentifierTypeRelative,
@@ -276,6 +280,7 @@ func (c *Controller) AbortPresignMultipartUpload(w http.ResponseWriter, r *http.
 	}
 
 	if err := c.BlockAdapter.AbortMultiPartUpload(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       physicalAddress,
@@ -321,6 +326,9 @@ func (c *Controller) CompletePresignMultipartUpload(w http.ResponseWriter, r *ht
 		writeError(w, r, http.StatusBadRequest, "parts are required")
 		return
 	}
+	if body.UserMetadata != nil && c.handleAPIError(ctx, w, r, catalog.ValidateUserMetadata(body.UserMetadata.AdditionalProperties)) {
+		return
+	}
 
 	// verify physical address
 	repo, err := c.Catalog.GetRepository(ctx, repository)
@@ -349,6 +357,7 @@ func (c *Controller) CompletePresignMultipartUpload(w http.ResponseWriter, r *ht
 	}
 
 	mpuResp, err := c.BlockAdapter.CompleteMultiPartUpload(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       physicalAddress,
@@ -379,7 +388,7 @@ func (c *Controller) CompletePresignMultipartUpload(w http.ResponseWriter, r *ht
 		return
 	}
 
-	metadata := apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata}
+	metadata := apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata.UserMetadata()}
 	response := apigen.ObjectStats{
 		Checksum:        entry.Checksum,
 		ContentType:     swag.String(entry.ContentType),
@@ -653,7 +662,7 @@ func (c *Controller) GetPhysicalAddress(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	address := c.PathProvider.NewPath()
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, address, block.IdentifierTypeRelative)
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, address, block.IdentifierTypeRelative)
 	if err != nil {
 		writeError(w, r, http.StatusInternalServerError, err)
 		return
@@ -671,6 +680,7 @@ func (c *Controller) GetPhysicalAddress(w http.ResponseWriter, r *http.Request,
 	if swag.BoolValue(params.Presign) {
 		// generate a pre-signed PUT url for the given request
 		preSignedURL, expiry, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+			StorageID:        repo.StorageID,
 			StorageNamespace: repo.StorageNamespace,
 			Identifier:       address,
 			IdentifierType:   block.IdentifierTypeRelative,
@@ -700,6 +710,9 @@ func (c *Controller) LinkPhysicalAddress(w http.ResponseWriter, r *http.Request,
 
 	ctx := r.Context()
 	c.LogAction(ctx, "stage_object", r, repository, branch, "")
+	if body.UserMetadata != nil && c.handleAPIError(ctx, w, r, catalog.ValidateUserMetadata(body.UserMetadata.AdditionalProperties)) {
+		return
+	}
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if errors.Is(err, graveler.ErrNotFound) {
@@ -711,7 +724,7 @@ func (c *Controller) LinkPhysicalAddress(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	// write metadata
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, params.Path, block.IdentifierTypeRelative)
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, params.Path, block.IdentifierTypeRelative)
 	if err != nil {
 		writeError(w, r, http.StatusInternalServerError, err)
 		return
@@ -774,7 +787,7 @@ func (c *Controller) LinkPhysicalAddress(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	metadata := apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata}
+	metadata := apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata.UserMetadata()}
 	response := apigen.ObjectStats{
 		Checksum:        entry.Checksum,
 		ContentType:     swag.String(entry.ContentType),
@@ -1781,7 +1794,10 @@ func (c *Controller) GetConfig(w http.ResponseWriter, r *http.Request) {
 		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
 		return
 	}
-	var storageCfg apigen.StorageConfig
+	var (
+		storageCfg     apigen.StorageConfig
+		storageCfgList *[]apigen.StorageConfig
+	)
 	internalError := false
 	if !c.authorizeCallback(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -1802,10 +1818,11 @@ func (c *Controller) GetConfig(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		storageCfg = c.getStorageConfig()
+		storageCfgList = apiutil.Ptr(c.getStorageConfigList())
 	}
 
 	versionConfig := c.getVersionConfig()
-	writeResponse(w, r, http.StatusOK, apigen.Config{StorageConfig: &storageCfg, VersionConfig: &versionConfig})
+	writeResponse(w, r, http.StatusOK, apigen.Config{StorageConfig: &storageCfg, StorageConfigList: storageCfgList, VersionConfig: &versionConfig})
 }
 
 func (c *Controller) GetStorageConfig(w http.ResponseWriter, r *http.Request) {
@@ -1822,13 +1839,33 @@ func (c *Controller) GetStorageConfig(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c *Controller) getStorageConfig() apigen.StorageConfig {
-	info := c.BlockAdapter.GetStorageNamespaceInfo()
+	return c.getBlockstoreConfig(c.BlockAdapter, &c.Config.Blockstore)
+}
+
+// getStorageConfigList returns the configuration of all the blockstores, the default blockstore first
+func (c *Controller) getStorageConfigList() []apigen.StorageConfig {
+	storages := block.GetStorages(c.BlockAdapter)
+	storageConfigs := make([]apigen.StorageConfig, 0, len(storages))
+	for _, storage := range storages {
+		blockstoreCfg, ok := c.Config.BlockstoreByID(storage.ID)
+		if !ok {
+			blockstoreCfg = &config.Blockstore{ID: storage.ID, Type: storage.Adapter.BlockstoreType()}
+		}
+		storageConfigs = append(storageConfigs, c.getBlockstoreConfig(storage.Adapter, blockstoreCfg))
+	}
+	return storageConfigs
+}
+
+func (c *Controller) getBlockstoreConfig(adapter block.Adapter, blockstoreCfg *config.Blockstore) apigen.StorageConfig {
+	info := adapter.GetStorageNamespaceInfo()
 	defaultNamespacePrefix := swag.String(info.DefaultNamespacePrefix)
-	if c.Config.Blockstore.DefaultNamespacePrefix != nil {
-		defaultNamespacePrefix = c.Config.Blockstore.DefaultNamespacePrefix
+	if blockstoreCfg.DefaultNamespacePrefix != nil {
+		defaultNamespacePrefix = blockstoreCfg.DefaultNamespacePrefix
 	}
 	return apigen.StorageConfig{
-		BlockstoreType:                   c.Config.Blockstore.Type,
+		BlockstoreType:                   blockstoreCfg.Type,
+		BlockstoreId:                     apiutil.Ptr(blockstoreCfg.ID),
+		BlockstoreDescription:            apiutil.Ptr(blockstoreCfg.Description),
 		BlockstoreNamespaceValidityRegex: info.ValidityRegex,
 		BlockstoreNamespaceExample:       info.Example,
 		DefaultNamespacePrefix:           defaultNamespacePrefix,
@@ -1865,6 +1902,7 @@ func (c *Controller) ListRepositories(w http.ResponseWriter, r *http.Request, pa
 		creationDate := repo.CreationDate.Unix()
 		r := apigen.Repository{
 			Id:               repo.Name,
+			StorageId:        apiutil.Ptr(repo.StorageID),
 			StorageNamespace: repo.StorageNamespace,
 			CreationDate:     creationDate,
 			DefaultBranch:    repo.DefaultBranch,
@@ -1916,7 +1954,13 @@ func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, bo
 		c.LogAction(ctx, "repo_sample_data", r, body.Name, "", "")
 	}
 
-	if err := c.validateStorageNamespace(body.StorageNamespace); err != nil {
+	storageID := swag.StringValue(body.StorageId)
+	storageAdapter, err := block.GetStorageAdapter(c.BlockAdapter, storageID)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateStorageNamespace(storageAdapter, body.StorageNamespace); err != nil {
 		writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
@@ -1929,7 +1973,7 @@ func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, bo
 	if swag.BoolValue(params.Bare) {
 		// create a bare repository. This is useful in conjunction with refs-restore to create a copy
 		// of another repository by e.g. copying the _lakefs/ directory and restoring its refs
-		repo, err := c.Catalog.CreateBareRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
+		repo, err := c.Catalog.CreateBareRepository(ctx, body.Name, storageID, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
 		if c.handleAPIError(ctx, w, r, err) {
 			return
 		}
@@ -1937,6 +1981,7 @@ func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, bo
 			CreationDate:     repo.CreationDate.Unix(),
 			DefaultBranch:    repo.DefaultBranch,
 			Id:               repo.Name,
+			StorageId:        apiutil.Ptr(repo.StorageID),
 			StorageNamespace: repo.StorageNamespace,
 		}
 		writeResponse(w, r, http.StatusCreated, response)
@@ -1944,7 +1989,7 @@ func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, bo
 	}
 
 	if !swag.BoolValue(body.ReadOnly) {
-		if err := c.ensureStorageNamespace(ctx, body.StorageNamespace); err != nil {
+		if err := c.ensureStorageNamespace(ctx, storageID, body.StorageNamespace); err != nil {
 			var (
 				reason string
 				retErr error
@@ -1955,7 +2000,7 @@ func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, bo
 				retErr = err
 				reason = "bad_url"
 			case errors.Is(err, block.ErrInvalidAddress):
-				retErr = fmt.Errorf("%w, must match: %s", err, c.BlockAdapter.BlockstoreType())
+				retErr = fmt.Errorf("%w, must match: %s", err, storageAdapter.BlockstoreType())
 				reason = "invalid_namespace"
 			case errors.Is(err, ErrStorageNamespaceInUse):
 				retErr = err
@@ -1974,7 +2019,7 @@ func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, bo
 		}
 	}
 
-	newRepo, err := c.Catalog.CreateRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
+	newRepo, err := c.Catalog.CreateRepository(ctx, body.Name, storageID, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
 	if err != nil {
 		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", err))
 		return
@@ -2005,14 +2050,15 @@ func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, bo
 		CreationDate:     newRepo.CreationDate.Unix(),
 		DefaultBranch:    newRepo.DefaultBranch,
 		Id:               newRepo.Name,
+		StorageId:        apiutil.Ptr(newRepo.StorageID),
 		StorageNamespace: newRepo.StorageNamespace,
 		ReadOnly:         swag.Bool(newRepo.ReadOnly),
 	}
 	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) validateStorageNamespace(storageNamespace string) error {
-	validRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
+func validateStorageNamespace(adapter block.Adapter, storageNamespace string) error {
+	validRegex := adapter.GetStorageNamespaceInfo().ValidityRegex
 	storagePrefixRegex, err := regexp.Compile(validRegex)
 	if err != nil {
 		return fmt.Errorf("failed to compile validity regex %s: %w", validRegex, block.ErrInvalidNamespace)
@@ -2023,7 +2069,7 @@ func (c *Controller) validateStorageNamespace(storageNamespace string) error {
 	return nil
 }
 
-func (c *Controller) ensureStorageNamespace(ctx context.Context, storageNamespace string) error {
+func (c *Controller) ensureStorageNamespace(ctx context.Context, storageID, storageNamespace string) error {
 	const (
 		dummyData    = "this is dummy data - created by lakeFS to check accessibility"
 		dummyObjName = "dummy"
@@ -2037,6 +2083,7 @@ func (c *Controller) ensureStorageNamespace(ctx context.Context, storageNamespac
 	// second, in scenarios where lakeFS shouldn't have access to the root namespace (i.e pre-sign URL only).
 	if c.Config.Graveler.EnsureReadableRootNamespace {
 		rootObj := block.ObjectPointer{
+			StorageID:        storageID,
 			StorageNamespace: storageNamespace,
 			IdentifierType:   block.IdentifierTypeRelative,
 			Identifier:       dummyObjName,
@@ -2053,6 +2100,7 @@ func (c *Controller) ensureStorageNamespace(ctx context.Context, storageNamespac
 
 	// check if the dummy file exists
 	obj := block.ObjectPointer{
+		StorageID:        storageID,
 		StorageNamespace: storageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       dummyKey,
@@ -2110,6 +2158,7 @@ func (c *Controller) GetRepository(w http.ResponseWriter, r *http.Request, repos
 			CreationDate:     repo.CreationDate.Unix(),
 			DefaultBranch:    repo.DefaultBranch,
 			Id:               repo.Name,
+			StorageId:        apiutil.Ptr(repo.StorageID),
 			StorageNamespace: repo.StorageNamespace,
 			ReadOnly:         swag.Bool(repo.ReadOnly),
 		}
@@ -2195,10 +2244,14 @@ func (c *Controller) GetBranchProtectionRules(w http.ResponseWriter, r *http.Req
 		return
 	}
 	resp := make([]*apigen.BranchProtectionRule, 0, len(rules.BranchPatternToBlockedActions))
-	for pattern := range rules.BranchPatternToBlockedActions {
-		resp = append(resp, &apigen.BranchProtectionRule{
+	for pattern, blockedActions := range rules.BranchPatternToBlockedActions {
+		rule := &apigen.BranchProtectionRule{
 			Pattern: pattern,
-		})
+		}
+		if slices.Contains(blockedActions.GetValue(), graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT) {
+			rule.RequireSignedCommits = apiutil.Ptr(true)
+		}
+		resp = append(resp, rule)
 	}
 	w.Header().Set("ETag", swag.StringValue(eTag))
 	writeResponse(w, r, http.StatusOK, resp)
@@ -2216,16 +2269,11 @@ func (c *Controller) SetBranchProtectionRules(w http.ResponseWriter, r *http.Req
 	ctx := r.Context()
 	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
 
-	// For now, all protected branches use the same default set of blocked actions. In the future this set will be user configurable.
-	blockedActions := []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT}
-
 	rules := &graveler.BranchProtectionRules{
 		BranchPatternToBlockedActions: make(map[string]*graveler.BranchProtectionBlockedActions),
 	}
 	for _, r := range body {
-		rules.BranchPatternToBlockedActions[r.Pattern] = &graveler.BranchProtectionBlockedActions{
-			Value: blockedActions,
-		}
+		rules.BranchPatternToBlockedActions[r.Pattern] = branchProtectionBlockedActions(r)
 	}
 	err := c.Catalog.SetBranchProtectionRules(ctx, repository, rules, params.IfMatch)
 	if c.handleAPIError(ctx, w, r, err) {
@@ -2234,6 +2282,16 @@ func (c *Controller) SetBranchProtectionRules(w http.ResponseWriter, r *http.Req
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
+// branchProtectionBlockedActions returns the actions blocked on branches matching the rule
+func branchProtectionBlockedActions(rule apigen.BranchProtectionRule) *graveler.BranchProtectionBlockedActions {
+	// For now, all protected branches use the same default set of blocked actions. In the future this set will be user configurable.
+	blockedActions := []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT}
+	if swag.BoolValue(rule.RequireSignedCommits) {
+		blockedActions = append(blockedActions, graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT)
+	}
+	return &graveler.BranchProtectionBlockedActions{Value: blockedActions}
+}
+
 func (c *Controller) DeleteGCRules(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -2297,313 +2355,696 @@ func (c *Controller) SetGCRules(w http.ResponseWriter, r *http.Request, body api
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) ListRepositoryRuns(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListRepositoryRunsParams) {
+func (c *Controller) GetLifecycleRules(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
+			Action:   permissions.ReadRepositoryAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_repository_runs", r, repository, "", "")
-
-	_, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-
-	branchName := swag.StringValue(params.Branch)
-	commitID := swag.StringValue(params.Commit)
-	runsIter, err := c.Actions.ListRunResults(ctx, repository, branchName, commitID, paginationAfter(params.After))
+	rules, err := c.Catalog.GetLifecycleRules(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	defer runsIter.Close()
-
-	response := apigen.ActionRunList{
-		Pagination: apigen.Pagination{
-			MaxPerPage: DefaultMaxPerPage,
-		},
-		Results: make([]apigen.ActionRun, 0),
-	}
-	amount := paginationAmount(params.Amount)
-	for len(response.Results) < amount && runsIter.Next() {
-		val := runsIter.Value()
-		response.Results = append(response.Results, runResultToActionRun(val))
-	}
-	response.Pagination.Results = len(response.Results)
-	if runsIter.Next() {
-		response.Pagination.HasMore = true
-		if len(response.Results) > 0 {
-			lastRun := response.Results[len(response.Results)-1]
-			response.Pagination.NextOffset = lastRun.RunId
-		}
-	}
-	if err := runsIter.Err(); err != nil {
-		writeResponse(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	writeResponse(w, r, http.StatusOK, response)
-}
-
-func runResultToActionRun(val *actions.RunResult) apigen.ActionRun {
-	runResult := apigen.ActionRun{
-		Branch:    val.BranchID,
-		CommitId:  val.CommitID,
-		RunId:     val.RunID,
-		StartTime: val.StartTime,
-		EndTime:   &val.EndTime,
-		EventType: val.EventType,
+	resp := apigen.LifecycleRules{
+		DefaultTransitionDays: int(rules.DefaultTransitionDays),
+		Branches:              make([]apigen.LifecycleRule, 0, len(rules.Branches)),
+		StorageClass:          rules.StorageClass,
 	}
-	if val.Passed {
-		runResult.Status = actionStatusCompleted
-	} else {
-		runResult.Status = actionStatusFailed
+	for _, rule := range rules.Branches {
+		resp.Branches = append(resp.Branches, apigen.LifecycleRule{BranchId: rule.BranchId, TransitionDays: int(rule.TransitionDays)})
 	}
-	return runResult
+	writeResponse(w, r, http.StatusOK, resp)
 }
 
-func (c *Controller) GetRun(w http.ResponseWriter, r *http.Request, repository, runID string) {
+func (c *Controller) SetLifecycleRules(w http.ResponseWriter, r *http.Request, body apigen.SetLifecycleRulesJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
+			Action:   permissions.UpdateRepositoryAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_get_run", r, repository, "", "")
-	_, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	c.LogAction(ctx, "set_lifecycle_rules", r, repository, "", "")
+	rules := &catalog.LifecycleRules{
+		DefaultTransitionDays: int32(body.DefaultTransitionDays),
+		StorageClass:          body.StorageClass,
 	}
-
-	runResult, err := c.Actions.GetRunResult(ctx, repository, runID)
+	for _, rule := range body.Branches {
+		rules.Branches = append(rules.Branches, &catalog.LifecycleRule{BranchId: rule.BranchId, TransitionDays: int32(rule.TransitionDays)})
+	}
+	err := c.Catalog.SetLifecycleRules(ctx, repository, rules)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	var status string
-	if runResult.Passed {
-		status = actionStatusCompleted
-	} else {
-		status = actionStatusFailed
-	}
-	response := apigen.ActionRun{
-		RunId:     runResult.RunID,
-		EventType: runResult.EventType,
-		StartTime: runResult.StartTime,
-		EndTime:   &runResult.EndTime,
-		Status:    status,
-		Branch:    runResult.BranchID,
-		CommitId:  runResult.CommitID,
-	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) ListRunHooks(w http.ResponseWriter, r *http.Request, repository, runID string, params apigen.ListRunHooksParams) {
+func (c *Controller) DeleteLifecycleRules(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
+			Action:   permissions.UpdateRepositoryAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_list_run_hooks", r, repository, "", "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-
-	tasksIter, err := c.Actions.ListRunTaskResults(ctx, repo.Name, runID, paginationAfter(params.After))
+	c.LogAction(ctx, "delete_lifecycle_rules", r, repository, "", "")
+	err := c.Catalog.DeleteLifecycleRules(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	defer tasksIter.Close()
-
-	response := apigen.HookRunList{
-		Results: make([]apigen.HookRun, 0),
-		Pagination: apigen.Pagination{
-			MaxPerPage: DefaultMaxPerPage,
-		},
-	}
-	amount := paginationAmount(params.Amount)
-	for len(response.Results) < amount && tasksIter.Next() {
-		val := tasksIter.Value()
-		hookRun := apigen.HookRun{
-			HookRunId: val.HookRunID,
-			Action:    val.ActionName,
-			HookId:    val.HookID,
-			StartTime: val.StartTime,
-			EndTime:   &val.EndTime,
-		}
-		switch {
-		case val.Passed:
-			hookRun.Status = actionStatusCompleted
-		case val.StartTime.IsZero(): // assumes that database values are only stored after run is finished
-			hookRun.Status = actionStatusSkipped
-			hookRun.EndTime = nil
-		default:
-			hookRun.Status = actionStatusFailed
-		}
-		response.Results = append(response.Results, hookRun)
-	}
-	if tasksIter.Next() {
-		response.Pagination.HasMore = true
-		if len(response.Results) > 0 {
-			lastHookRun := response.Results[len(response.Results)-1]
-			response.Pagination.NextOffset = lastHookRun.HookRunId
-		}
-	}
-	if err := tasksIter.Err(); err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	response.Pagination.Results = len(response.Results)
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetRunHookOutput(w http.ResponseWriter, r *http.Request, repository, runID, hookRunID string) {
+func (c *Controller) LifecycleRunSubmit(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
+			Action:   permissions.UpdateRepositoryAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_run_hook_output", r, repository, "", "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-
-	taskResult, err := c.Actions.GetTaskResult(ctx, repo.Name, runID, hookRunID)
+	c.LogAction(ctx, "lifecycle_run", r, repository, "", "")
+	taskID, err := c.Catalog.LifecycleRunSubmit(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
+}
 
-	if taskResult.StartTime.IsZero() { // skipped task
-		writeResponse(w, r, http.StatusOK, nil)
+func (c *Controller) LifecycleRunStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.LifecycleRunStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
 		return
 	}
-
-	logPath := taskResult.LogPath()
-	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       logPath,
-	}, -1)
+	ctx := r.Context()
+	status, err := c.Catalog.LifecycleRunStatus(ctx, repository, params.TaskId)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	defer func() {
-		_ = reader.Close()
-	}()
-
-	cd := mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(logPath)})
-	w.Header().Set("Content-Disposition", cd)
-	w.Header().Set("Content-Type", "application/octet-stream")
-	_, err = io.Copy(w, reader)
-	if err != nil {
-		c.Logger.WithError(err).WithField("log_path", logPath).Warn("Write run hook output")
+	response := &apigen.LifecycleRunStatus{
+		Id:                  params.TaskId,
+		Done:                status.Task.Done,
+		UpdateTime:          status.Task.UpdatedAt.AsTime(),
+		TransitionedObjects: status.TransitionedObjects,
 	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ListBranches(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListBranchesParams) {
+func (c *Controller) GetReplicationSettings(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListBranchesAction,
+			Action:   permissions.ReadRepositoryAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "list_branches", r, repository, "", "")
-
-	res, hasMore, err := c.Catalog.ListBranches(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
+	settings, err := c.Catalog.GetReplicationSettings(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	refs := make([]apigen.Ref, 0, len(res))
-	for _, branch := range res {
-		refs = append(refs, apigen.Ref{
-			CommitId: branch.Reference,
-			Id:       branch.Name,
-		})
+	resp := apigen.ReplicationSettings{
+		StorageNamespace: settings.StorageNamespace,
+		Synchronous:      apiutil.Ptr(settings.Synchronous),
 	}
-	response := apigen.RefList{
-		Results:    refs,
-		Pagination: paginationFor(hasMore, refs, "Id"),
+	if settings.StorageId != "" {
+		resp.StorageId = apiutil.Ptr(settings.StorageId)
 	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusOK, resp)
 }
 
-func (c *Controller) CreateBranch(w http.ResponseWriter, r *http.Request, body apigen.CreateBranchJSONRequestBody, repository string) {
+func (c *Controller) SetReplicationSettings(w http.ResponseWriter, r *http.Request, body apigen.SetReplicationSettingsJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.CreateBranchAction,
-			Resource: permissions.BranchArn(repository, body.Name),
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_branch", r, repository, body.Name, "")
-
-	commitLog, err := c.Catalog.CreateBranch(ctx, repository, body.Name, body.Source, graveler.WithForce(swag.BoolValue(body.Force)))
+	c.LogAction(ctx, "set_replication_settings", r, repository, "", "")
+	err := c.Catalog.SetReplicationSettings(ctx, repository, &catalog.ReplicationSettings{
+		StorageId:        apiutil.Value(body.StorageId),
+		StorageNamespace: body.StorageNamespace,
+		Synchronous:      apiutil.Value(body.Synchronous),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	w.WriteHeader(http.StatusCreated)
-	_, _ = io.WriteString(w, commitLog.Reference)
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) DeleteBranch(w http.ResponseWriter, r *http.Request, repository, branch string, body apigen.DeleteBranchParams) {
+func (c *Controller) DeleteReplicationSettings(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DeleteBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_branch", r, repository, branch, "")
-
-	err := c.Catalog.DeleteBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
+	c.LogAction(ctx, "delete_replication_settings", r, repository, "", "")
+	err := c.Catalog.DeleteReplicationSettings(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
+func (c *Controller) GetReplicationStatus(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_branch", r, repository, branch, "")
-	reference, err := c.Catalog.GetBranchReference(ctx, repository, branch)
+	status, err := c.Catalog.GetReplicationStatus(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	response := apigen.Ref{
+	resp := apigen.ReplicationStatus{
+		PendingCommits:    len(status.Pending),
+		LagSeconds:        int64(status.Lag(time.Now()).Seconds()),
+		ReplicatedObjects: status.ReplicatedObjects,
+		ReplicatedRanges:  status.ReplicatedRanges,
+	}
+	if status.LastCommitId != "" {
+		resp.LastCommitId = apiutil.Ptr(status.LastCommitId)
+		resp.LastCommitCreationDate = apiutil.Ptr(status.LastCommitCreationDate.AsTime().Unix())
+	}
+	if status.UpdatedAt != nil {
+		resp.UpdateTime = apiutil.Ptr(status.UpdatedAt.AsTime())
+	}
+	if status.Error != "" {
+		resp.Error = apiutil.Ptr(status.Error)
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) ScrubSubmit(w http.ResponseWriter, r *http.Request, body apigen.ScrubSubmitJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "scrub", r, repository, body.Ref, "")
+	taskID, err := c.Catalog.ScrubSubmit(ctx, repository, body.Ref, apiutil.Value(body.ResumeTaskId), apiutil.Value(body.RateLimit))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
+}
+
+func (c *Controller) ScrubStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.ScrubStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	status, err := c.Catalog.ScrubStatus(ctx, repository, params.TaskId)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := &apigen.ScrubStatus{
+		Id:              params.TaskId,
+		Done:            status.Task.Done,
+		UpdateTime:      status.Task.UpdatedAt.AsTime(),
+		Ref:             status.Ref,
+		VerifiedObjects: status.VerifiedObjects,
+		VerifiedRanges:  status.VerifiedRanges,
+		Problems:        make([]apigen.ScrubProblem, 0, len(status.Problems)),
+		ProblemsCount:   status.ProblemsCount,
+	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	if status.LastPath != "" {
+		response.LastPath = apiutil.Ptr(status.LastPath)
+	}
+	for _, problem := range status.Problems {
+		p := apigen.ScrubProblem{
+			Kind: problem.Kind,
+			Path: problem.Path,
+		}
+		if problem.PhysicalAddress != "" {
+			p.PhysicalAddress = apiutil.Ptr(problem.PhysicalAddress)
+		}
+		if problem.Details != "" {
+			p.Details = apiutil.Ptr(problem.Details)
+		}
+		response.Problems = append(response.Problems, p)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) GetCompressionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	rules, err := c.Catalog.GetCompressionRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.CompressionRules{Rules: make([]apigen.CompressionRule, 0, len(rules.Rules))}
+	for _, rule := range rules.Rules {
+		respRule := apigen.CompressionRule{Codec: rule.Codec}
+		if len(rule.ContentTypes) > 0 {
+			respRule.ContentTypes = apiutil.Ptr(rule.ContentTypes)
+		}
+		if len(rule.Extensions) > 0 {
+			respRule.Extensions = apiutil.Ptr(rule.Extensions)
+		}
+		resp.Rules = append(resp.Rules, respRule)
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetCompressionRules(w http.ResponseWriter, r *http.Request, body apigen.SetCompressionRulesJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_compression_rules", r, repository, "", "")
+	rules := &catalog.CompressionRules{}
+	for _, rule := range body.Rules {
+		rules.Rules = append(rules.Rules, &catalog.CompressionRule{
+			ContentTypes: apiutil.Value(rule.ContentTypes),
+			Extensions:   apiutil.Value(rule.Extensions),
+			Codec:        rule.Codec,
+		})
+	}
+	err := c.Catalog.SetCompressionRules(ctx, repository, rules)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetMergeResolverRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	rules, err := c.Catalog.GetMergeResolverRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.MergeResolverRules{Rules: make([]apigen.MergeResolverRule, 0, len(rules.Rules))}
+	for _, rule := range rules.Rules {
+		respRule := apigen.MergeResolverRule{Path: rule.Path, Strategy: rule.Strategy}
+		if rule.ScriptPath != "" {
+			respRule.ScriptPath = apiutil.Ptr(rule.ScriptPath)
+		}
+		resp.Rules = append(resp.Rules, respRule)
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetMergeResolverRules(w http.ResponseWriter, r *http.Request, body apigen.SetMergeResolverRulesJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_merge_resolver_rules", r, repository, "", "")
+	rules := &catalog.MergeResolverRules{}
+	for _, rule := range body.Rules {
+		rules.Rules = append(rules.Rules, &catalog.MergeResolverRule{
+			Path:       rule.Path,
+			Strategy:   rule.Strategy,
+			ScriptPath: apiutil.Value(rule.ScriptPath),
+		})
+	}
+	err := c.Catalog.SetMergeResolverRules(ctx, repository, rules)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) ListRepositoryRuns(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListRepositoryRunsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_repository_runs", r, repository, "", "")
+
+	_, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	branchName := swag.StringValue(params.Branch)
+	commitID := swag.StringValue(params.Commit)
+	runsIter, err := c.Actions.ListRunResults(ctx, repository, branchName, commitID, paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer runsIter.Close()
+
+	response := apigen.ActionRunList{
+		Pagination: apigen.Pagination{
+			MaxPerPage: DefaultMaxPerPage,
+		},
+		Results: make([]apigen.ActionRun, 0),
+	}
+	amount := paginationAmount(params.Amount)
+	for len(response.Results) < amount && runsIter.Next() {
+		val := runsIter.Value()
+		response.Results = append(response.Results, runResultToActionRun(val))
+	}
+	response.Pagination.Results = len(response.Results)
+	if runsIter.Next() {
+		response.Pagination.HasMore = true
+		if len(response.Results) > 0 {
+			lastRun := response.Results[len(response.Results)-1]
+			response.Pagination.NextOffset = lastRun.RunId
+		}
+	}
+	if err := runsIter.Err(); err != nil {
+		writeResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func runResultToActionRun(val *actions.RunResult) apigen.ActionRun {
+	runResult := apigen.ActionRun{
+		Branch:    val.BranchID,
+		CommitId:  val.CommitID,
+		RunId:     val.RunID,
+		StartTime: val.StartTime,
+		EndTime:   &val.EndTime,
+		EventType: val.EventType,
+	}
+	if val.Passed {
+		runResult.Status = actionStatusCompleted
+	} else {
+		runResult.Status = actionStatusFailed
+	}
+	return runResult
+}
+
+func (c *Controller) GetRun(w http.ResponseWriter, r *http.Request, repository, runID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_get_run", r, repository, "", "")
+	_, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	runResult, err := c.Actions.GetRunResult(ctx, repository, runID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	var status string
+	if runResult.Passed {
+		status = actionStatusCompleted
+	} else {
+		status = actionStatusFailed
+	}
+	response := apigen.ActionRun{
+		RunId:     runResult.RunID,
+		EventType: runResult.EventType,
+		StartTime: runResult.StartTime,
+		EndTime:   &runResult.EndTime,
+		Status:    status,
+		Branch:    runResult.BranchID,
+		CommitId:  runResult.CommitID,
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) ListRunHooks(w http.ResponseWriter, r *http.Request, repository, runID string, params apigen.ListRunHooksParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_list_run_hooks", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	tasksIter, err := c.Actions.ListRunTaskResults(ctx, repo.Name, runID, paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer tasksIter.Close()
+
+	response := apigen.HookRunList{
+		Results: make([]apigen.HookRun, 0),
+		Pagination: apigen.Pagination{
+			MaxPerPage: DefaultMaxPerPage,
+		},
+	}
+	amount := paginationAmount(params.Amount)
+	for len(response.Results) < amount && tasksIter.Next() {
+		val := tasksIter.Value()
+		hookRun := apigen.HookRun{
+			HookRunId: val.HookRunID,
+			Action:    val.ActionName,
+			HookId:    val.HookID,
+			StartTime: val.StartTime,
+			EndTime:   &val.EndTime,
+		}
+		switch {
+		case val.Passed:
+			hookRun.Status = actionStatusCompleted
+		case val.StartTime.IsZero(): // assumes that database values are only stored after run is finished
+			hookRun.Status = actionStatusSkipped
+			hookRun.EndTime = nil
+		default:
+			hookRun.Status = actionStatusFailed
+		}
+		response.Results = append(response.Results, hookRun)
+	}
+	if tasksIter.Next() {
+		response.Pagination.HasMore = true
+		if len(response.Results) > 0 {
+			lastHookRun := response.Results[len(response.Results)-1]
+			response.Pagination.NextOffset = lastHookRun.HookRunId
+		}
+	}
+	if err := tasksIter.Err(); err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	response.Pagination.Results = len(response.Results)
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) GetRunHookOutput(w http.ResponseWriter, r *http.Request, repository, runID, hookRunID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_run_hook_output", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	taskResult, err := c.Actions.GetTaskResult(ctx, repo.Name, runID, hookRunID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	if taskResult.StartTime.IsZero() { // skipped task
+		writeResponse(w, r, http.StatusOK, nil)
+		return
+	}
+
+	logPath := taskResult.LogPath()
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       logPath,
+	}, -1)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	cd := mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(logPath)})
+	w.Header().Set("Content-Disposition", cd)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		c.Logger.WithError(err).WithField("log_path", logPath).Warn("Write run hook output")
+	}
+}
+
+func (c *Controller) ListBranches(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListBranchesParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListBranchesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_branches", r, repository, "", "")
+
+	res, hasMore, err := c.Catalog.ListBranches(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	refs := make([]apigen.Ref, 0, len(res))
+	for _, branch := range res {
+		refs = append(refs, apigen.Ref{
+			CommitId: branch.Reference,
+			Id:       branch.Name,
+		})
+	}
+	response := apigen.RefList{
+		Results:    refs,
+		Pagination: paginationFor(hasMore, refs, "Id"),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) CreateBranch(w http.ResponseWriter, r *http.Request, body apigen.CreateBranchJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateBranchAction,
+			Resource: permissions.BranchArn(repository, body.Name),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_branch", r, repository, body.Name, "")
+
+	commitLog, err := c.Catalog.CreateBranch(ctx, repository, body.Name, body.Source, graveler.WithForce(swag.BoolValue(body.Force)), withRequestUser(ctx))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_, _ = io.WriteString(w, commitLog.Reference)
+}
+
+func (c *Controller) DeleteBranch(w http.ResponseWriter, r *http.Request, repository, branch string, body apigen.DeleteBranchParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_branch", r, repository, branch, "")
+
+	err := c.Catalog.DeleteBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)), withRequestUser(ctx))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_branch", r, repository, branch, "")
+	reference, err := c.Catalog.GetBranchReference(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.Ref{
 		CommitId: reference,
 		Id:       branch,
 	}
@@ -2669,6 +3110,7 @@ func (c *Controller) handleAPIErrorCallback(ctx context.Context, w http.Response
 		errors.Is(err, graveler.ErrDereferenceCommitWithStaging),
 		errors.Is(err, graveler.ErrParentOutOfRange),
 		errors.Is(err, graveler.ErrCherryPickMergeNoParent),
+		errors.Is(err, graveler.ErrRebaseMergeBase),
 		errors.Is(err, graveler.ErrInvalidMergeStrategy),
 		errors.Is(err, block.ErrInvalidAddress),
 		errors.Is(err, block.ErrOperationNotSupported),
@@ -2678,7 +3120,8 @@ func (c *Controller) handleAPIErrorCallback(ctx context.Context, w http.Response
 
 	case errors.Is(err, graveler.ErrNotUnique),
 		errors.Is(err, graveler.ErrConflictFound),
-		errors.Is(err, graveler.ErrRevertMergeNoParent):
+		errors.Is(err, graveler.ErrRevertMergeNoParent),
+		errors.Is(err, block.ErrRestoreRequired):
 		log.Debug("Conflict")
 		cb(w, r, http.StatusConflict, err)
 
@@ -2697,6 +3140,9 @@ func (c *Controller) handleAPIErrorCallback(ctx context.Context, w http.Response
 	case errors.Is(err, graveler.ErrTooManyTries):
 		log.Debug("Retried too many times")
 		cb(w, r, http.StatusLocked, "Too many attempts, try again later")
+	case errors.Is(err, graveler.ErrBranchHeadMismatch):
+		log.Debug("Branch head mismatch")
+		cb(w, r, http.StatusPreconditionFailed, err)
 	case errors.Is(err, graveler.ErrPreconditionFailed):
 		log.Debug("Precondition failed")
 		cb(w, r, http.StatusPreconditionFailed, "Precondition failed")
@@ -2734,14 +3180,23 @@ func (c *Controller) ResetBranch(w http.ResponseWriter, r *http.Request, body ap
 	c.LogAction(ctx, "reset_branch", r, repository, branch, "")
 
 	var err error
+	opts := []graveler.SetOptionsFunc{
+		graveler.WithForce(swag.BoolValue(body.Force)),
+		graveler.WithExpectedCommitID(graveler.CommitID(swag.StringValue(body.ExpectedCommitId))),
+		withRequestUser(ctx),
+	}
 
 	switch body.Type {
 	case entryTypeCommonPrefix:
-		err = c.Catalog.ResetEntries(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
+		err = c.Catalog.ResetEntries(ctx, repository, branch, swag.StringValue(body.Path), opts...)
 	case "reset":
-		err = c.Catalog.ResetBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
+		err = c.Catalog.ResetBranch(ctx, repository, branch, opts...)
 	case entryTypeObject:
-		err = c.Catalog.ResetEntry(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
+		if body.ExpectedCommitId != nil {
+			writeError(w, r, http.StatusBadRequest, "expected commit id is not supported for object reset")
+			return
+		}
+		err = c.Catalog.ResetEntry(ctx, repository, branch, swag.StringValue(body.Path), opts...)
 	default:
 		writeError(w, r, http.StatusBadRequest, "unknown reset type")
 		return
@@ -2767,7 +3222,10 @@ func (c *Controller) HardResetBranch(w http.ResponseWriter, r *http.Request, rep
 
 	c.LogAction(ctx, "hard_reset_branch", r, repository, branch, "")
 
-	err := c.Catalog.HardResetBranch(ctx, repository, branch, params.Ref, graveler.WithForce(swag.BoolValue(params.Force)))
+	err := c.Catalog.HardResetBranch(ctx, repository, branch, params.Ref,
+		graveler.WithForce(swag.BoolValue(params.Force)),
+		graveler.WithExpectedCommitID(graveler.CommitID(swag.StringValue(params.ExpectedCommitId))),
+		withRequestUser(ctx))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
@@ -2943,7 +3401,21 @@ func (c *Controller) Commit(w http.ResponseWriter, r *http.Request, body apigen.
 		metadata = body.Metadata.AdditionalProperties
 	}
 
-	newCommit, err := c.Catalog.Commit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, params.SourceMetarange, swag.BoolValue(body.AllowEmpty), graveler.WithForce(swag.BoolValue(body.Force)))
+	var prefixes []string
+	if body.Paths != nil {
+		prefixes = *body.Paths
+	}
+	var signature []byte
+	if body.Signature != nil {
+		signature, err = base64.StdEncoding.DecodeString(*body.Signature)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid signature encoding")
+			return
+		}
+	}
+	newCommit, err := c.Catalog.Commit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, params.SourceMetarange, swag.BoolValue(body.AllowEmpty), prefixes, signature,
+		graveler.WithForce(swag.BoolValue(body.Force)),
+		graveler.WithExpectedCommitID(graveler.CommitID(swag.StringValue(body.ExpectedCommitId))))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
@@ -2986,6 +3458,7 @@ func commitResponse(w http.ResponseWriter, r *http.Request, newCommit *catalog.C
 		Version:      apiutil.Ptr(int(newCommit.Version)),
 		Generation:   apiutil.Ptr(int64(newCommit.Generation)),
 	}
+	setCommitSignature(&response, newCommit)
 	writeResponse(w, r, http.StatusCreated, response)
 }
 
@@ -3082,6 +3555,10 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 	}
 	ctx := r.Context()
 	c.LogAction(ctx, "put_object", r, repository, branch, "")
+	userMetadata := extractLakeFSMetadata(r.Header)
+	if c.handleAPIError(ctx, w, r, catalog.ValidateUserMetadata(userMetadata)) {
+		return
+	}
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
@@ -3119,6 +3596,29 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 		}
 		allowOverwrite = false
 	}
+	setOpts := []graveler.SetOptionsFunc{graveler.WithIfAbsent(!allowOverwrite), graveler.WithForce(swag.BoolValue(params.Force))}
+	if params.IfMatch != nil {
+		ifMatch := httputil.ParseETagList(swag.StringValue((*string)(params.IfMatch)))
+		if len(ifMatch) == 0 {
+			writeError(w, r, http.StatusBadRequest, "If-Match requires at least one ETag or \"*\"")
+			return
+		}
+		// check the current object matches
+		entry, err := c.Catalog.GetEntry(ctx, repo.Name, branch, params.Path, catalog.GetEntryParams{})
+		if errors.Is(err, graveler.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "path not found")
+			return
+		}
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !slices.Contains(ifMatch, catalog.ETagMatchAny) && !slices.Contains(ifMatch, entry.Checksum) {
+			writeError(w, r, http.StatusPreconditionFailed, "path ETag does not match")
+			return
+		}
+		setOpts = append(setOpts, catalog.WithIfMatch(ifMatch...))
+	}
 
 	// read request body parse multipart for "content" and upload the data
 	contentType := catalog.ContentTypeOrDefault(r.Header.Get("Content-Type"))
@@ -3131,9 +3631,13 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 	var blob *upload.Blob
 	if mediaType != "multipart/form-data" {
 		// handle non-multipart, direct content upload
+		codec, err := c.Catalog.CompressionCodec(ctx, repo.Name, params.Path, contentType)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
 		address := c.PathProvider.NewPath()
-		blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, r.Body, r.ContentLength,
-			block.PutOpts{StorageClass: params.StorageClass})
+		blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageID, repo.StorageNamespace, address, r.Body, r.ContentLength,
+			block.PutOpts{StorageClass: params.StorageClass, Compression: codec})
 		if err != nil {
 			writeError(w, r, http.StatusInternalServerError, err)
 			return
@@ -3161,8 +3665,15 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 			partName := part.FormName()
 			if partName == "content" {
 				// upload the first "content" and exit the loop
+				codec, err := c.Catalog.CompressionCodec(ctx, repo.Name, params.Path, contentType)
+				if err != nil {
+					_ = part.Close()
+					c.handleAPIError(ctx, w, r, err)
+					return
+				}
 				address := c.PathProvider.NewPath()
-				blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, part, -1, block.PutOpts{StorageClass: params.StorageClass})
+				blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageID, repo.StorageNamespace, address, part, -1,
+					block.PutOpts{StorageClass: params.StorageClass, Compression: codec})
 				if err != nil {
 					_ = part.Close()
 					writeError(w, r, http.StatusInternalServerError, err)
@@ -3178,6 +3689,13 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 			return
 		}
 	}
+	// objects written with a storage class keep their own copy
+	if params.StorageClass == nil {
+		blob, err = c.Catalog.DedupBlob(ctx, repo.Name, params.Path, blob)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+	}
 	// write metadata
 	writeTime := time.Now()
 	entryBuilder := catalog.NewDBEntryBuilder().
@@ -3192,15 +3710,19 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 	} else {
 		entryBuilder.AddressType(catalog.AddressTypeFull)
 	}
-	meta := extractLakeFSMetadata(r.Header)
+	meta := catalog.Metadata(userMetadata).WithCompression(blob.Compression)
 	if len(meta) > 0 {
 		entryBuilder.Metadata(meta)
 	}
 	entry := entryBuilder.Build()
 
-	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithIfAbsent(!allowOverwrite), graveler.WithForce(swag.BoolValue(params.Force)))
+	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, setOpts...)
 	if errors.Is(err, graveler.ErrPreconditionFailed) {
-		writeError(w, r, http.StatusPreconditionFailed, "path already exists")
+		if params.IfMatch != nil {
+			writeError(w, r, http.StatusPreconditionFailed, "path ETag does not match")
+		} else {
+			writeError(w, r, http.StatusPreconditionFailed, "path already exists")
+		}
 		return
 	}
 	if c.handleAPIError(ctx, w, r, err) {
@@ -3212,7 +3734,7 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 		identifierType = block.IdentifierTypeRelative
 	}
 
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, blob.PhysicalAddress, identifierType)
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, blob.PhysicalAddress, identifierType)
 	if err != nil {
 		writeError(w, r, http.StatusInternalServerError, err)
 		return
@@ -3226,7 +3748,7 @@ func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, reposi
 		PhysicalAddress: qk.Format(),
 		SizeBytes:       swag.Int64(blob.Size),
 		ContentType:     &contentType,
-		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: meta},
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: meta.UserMetadata()},
 	}
 	writeResponse(w, r, http.StatusCreated, response)
 }
@@ -3242,13 +3764,16 @@ func (c *Controller) StageObject(w http.ResponseWriter, r *http.Request, body ap
 	}
 	ctx := r.Context()
 	c.LogAction(ctx, "stage_object", r, repository, branch, "")
+	if body.Metadata != nil && c.handleAPIError(ctx, w, r, catalog.ValidateUserMetadata(body.Metadata.AdditionalProperties)) {
+		return
+	}
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	// write metadata
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, body.PhysicalAddress, block.IdentifierTypeFull)
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, body.PhysicalAddress, block.IdentifierTypeFull)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
@@ -3261,154 +3786,466 @@ func (c *Controller) StageObject(w http.ResponseWriter, r *http.Request, body ap
 		))
 		return
 	}
-
-	// take mtime from request, if any
-	writeTime := time.Now()
-	if body.Mtime != nil {
-		writeTime = time.Unix(*body.Mtime, 0)
+
+	// take mtime from request, if any
+	writeTime := time.Now()
+	if body.Mtime != nil {
+		writeTime = time.Unix(*body.Mtime, 0)
+	}
+
+	physicalAddress, addressType := normalizePhysicalAddress(repo.StorageNamespace, body.PhysicalAddress)
+
+	entryBuilder := catalog.NewDBEntryBuilder().
+		CommonLevel(false).
+		Path(params.Path).
+		PhysicalAddress(physicalAddress).
+		AddressType(addressType).
+		CreationDate(writeTime).
+		Size(body.SizeBytes).
+		Checksum(body.Checksum).
+		ContentType(swag.StringValue(body.ContentType))
+	if body.Metadata != nil {
+		entryBuilder.Metadata(body.Metadata.AdditionalProperties)
+	}
+	entry := entryBuilder.Build()
+
+	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) CopyObject(w http.ResponseWriter, r *http.Request, body apigen.CopyObjectJSONRequestBody, repository, branch string, params apigen.CopyObjectParams) {
+	srcPath := body.SrcPath
+	destPath := params.DestPath
+	srcRepository := swag.StringValue(body.SrcRepository)
+	if srcRepository == "" {
+		srcRepository = repository
+	}
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(srcRepository, srcPath),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, destPath),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "copy_object", r, repository, branch, destPath)
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// verify destination is a branch (and exists)
+	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !branchExists {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
+		return
+	}
+
+	// use destination branch as source if not specified, the branch exists only on the destination repository
+	srcRef := swag.StringValue(body.SrcRef)
+	if srcRef == "" {
+		if srcRepository != repository {
+			writeError(w, r, http.StatusBadRequest, "src_ref is required when copying from another repository")
+			return
+		}
+		srcRef = branch
+	}
+
+	// copy entry
+	entry, err := c.Catalog.CopyEntry(ctx, srcRepository, srcRef, srcPath, repository, branch, destPath, catalog.CopyEntryParams{
+		Shallow: swag.BoolValue(body.Shallow),
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	var metadata map[string]string
+	if entry.Metadata != nil {
+		metadata = entry.Metadata.UserMetadata()
+	} else {
+		metadata = map[string]string{}
+	}
+
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: metadata},
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) CopyPrefixStart(w http.ResponseWriter, r *http.Request, body apigen.CopyPrefixStartJSONRequestBody, repository, branch string) {
+	srcRepository := swag.StringValue(body.SrcRepository)
+	if srcRepository == "" {
+		srcRepository = repository
+	}
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(srcRepository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(srcRepository, body.SrcPrefix),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, body.DestPrefix),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "copy_prefix", r, repository, branch, body.DestPrefix)
+
+	// use destination branch as source if not specified, the branch exists only on the destination repository
+	srcRef := swag.StringValue(body.SrcRef)
+	if srcRef == "" {
+		if srcRepository != repository {
+			writeError(w, r, http.StatusBadRequest, "src_ref is required when copying from another repository")
+			return
+		}
+		srcRef = branch
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
+		return
+	}
+	// the permissions above cover the prefixes, each copied object is authorized by its own path
+	authorizeEntry := func(ctx context.Context, srcPath, destPath string) error {
+		resp, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: user.Username,
+			RequiredPermissions: permissions.Node{
+				Type: permissions.NodeTypeAnd,
+				Nodes: []permissions.Node{
+					{
+						Permission: permissions.Permission{
+							Action:   permissions.ReadObjectAction,
+							Resource: permissions.ObjectArn(srcRepository, srcPath),
+						},
+					},
+					{
+						Permission: permissions.Permission{
+							Action:   permissions.WriteObjectAction,
+							Resource: permissions.ObjectArn(repository, destPath),
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if !resp.Allowed {
+			return auth.ErrInsufficientPermissions
+		}
+		return nil
+	}
+	taskID, err := c.Catalog.CopyPrefixSubmit(ctx, srcRepository, srcRef, body.SrcPrefix, repository, branch, body.DestPrefix, catalog.CopyEntryParams{
+		Shallow: swag.BoolValue(body.Shallow),
+	}, authorizeEntry, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
+}
+
+func (c *Controller) CopyPrefixStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.CopyPrefixStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	status, err := c.Catalog.CopyPrefixStatus(ctx, repository, params.Id)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := &apigen.PrefixCopyStatus{
+		Id:            params.Id,
+		Done:          status.Task.Done,
+		UpdateTime:    status.Task.UpdatedAt.AsTime(),
+		TotalObjects:  status.TotalObjects,
+		CopiedObjects: status.CopiedObjects,
+		CopiedBytes:   status.CopiedBytes,
+	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	if status.LastPath != "" {
+		response.LastPath = apiutil.Ptr(status.LastPath)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) RevertBranch(w http.ResponseWriter, r *http.Request, body apigen.RevertBranchJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.RevertBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "revert_branch", r, repository, branch, "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "user not found")
+		return
+	}
+	err = c.Catalog.Revert(ctx, repository, branch, catalog.RevertParams{
+		Reference:    body.Ref,
+		Committer:    user.Committer(),
+		ParentNumber: body.ParentNumber,
+		AllowEmpty:   swag.BoolValue(body.AllowEmpty),
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) ListBranchReflog(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ListBranchReflogParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_branch_reflog", r, repository, branch, "")
+
+	entries, hasMore, err := c.Catalog.ListReflog(ctx, repository, branch, paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.ReflogEntry, 0, len(entries))
+	for _, entry := range entries {
+		result := apigen.ReflogEntry{
+			Id:           entry.ID,
+			OldCommitId:  entry.OldCommitID,
+			NewCommitId:  entry.NewCommitID,
+			Operation:    entry.Operation,
+			CreationDate: entry.CreationDate.Unix(),
+		}
+		if entry.User != "" {
+			result.User = swag.String(entry.User)
+		}
+		results = append(results, result)
+	}
+	response := apigen.ReflogEntryList{
+		Pagination: paginationFor(hasMore, results, "Id"),
+		Results:    results,
 	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	physicalAddress, addressType := normalizePhysicalAddress(repo.StorageNamespace, body.PhysicalAddress)
-
-	entryBuilder := catalog.NewDBEntryBuilder().
-		CommonLevel(false).
-		Path(params.Path).
-		PhysicalAddress(physicalAddress).
-		AddressType(addressType).
-		CreationDate(writeTime).
-		Size(body.SizeBytes).
-		Checksum(body.Checksum).
-		ContentType(swag.StringValue(body.ContentType))
-	if body.Metadata != nil {
-		entryBuilder.Metadata(body.Metadata.AdditionalProperties)
+func (c *Controller) RestoreBranchReflog(w http.ResponseWriter, r *http.Request, body apigen.RestoreBranchReflogJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.RevertBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
 	}
-	entry := entryBuilder.Build()
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_branch_reflog", r, repository, branch, "")
 
-	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithForce(swag.BoolValue(body.Force)))
+	commitID, err := c.Catalog.RestoreReflog(ctx, repository, branch, body.EntryId, graveler.WithForce(swag.BoolValue(body.Force)), withRequestUser(ctx))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	response := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
-	}
-	writeResponse(w, r, http.StatusCreated, response)
+	writeResponse(w, r, http.StatusOK, apigen.Ref{
+		Id:       branch,
+		CommitId: commitID,
+	})
 }
 
-func (c *Controller) CopyObject(w http.ResponseWriter, r *http.Request, body apigen.CopyObjectJSONRequestBody, repository, branch string, params apigen.CopyObjectParams) {
-	srcPath := body.SrcPath
-	destPath := params.DestPath
+func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body apigen.CherryPickJSONRequestBody, repository string, branch string) {
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.ReadObjectAction,
-					Resource: permissions.ObjectArn(repository, srcPath),
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.BranchArn(repository, branch),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.WriteObjectAction,
-					Resource: permissions.ObjectArn(repository, destPath),
+					Action:   permissions.ReadCommitAction,
+					Resource: permissions.RepoArn(repository),
 				},
 			},
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "copy_object", r, repository, branch, destPath)
+	c.LogAction(ctx, "cherry_pick", r, repository, branch, body.Ref)
 
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "user not found")
 		return
 	}
-
-	// verify destination is a branch (and exists)
-	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
+	newCommit, err := c.Catalog.CherryPick(ctx, repository, branch, catalog.CherryPickParams{
+		Reference:    body.Ref,
+		Committer:    user.Committer(),
+		ParentNumber: body.ParentNumber,
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	if !branchExists {
-		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
-		return
-	}
-
-	// use destination branch as source if not specified
-	srcRef := swag.StringValue(body.SrcRef)
-	if srcRef == "" {
-		srcRef = branch
-	}
 
-	// copy entry
-	entry, err := c.Catalog.CopyEntry(ctx, repository, srcRef, srcPath, repository, branch, destPath, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
+	commitResponse(w, r, newCommit)
+}
 
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, block.IdentifierTypeRelative)
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
-		return
+func (c *Controller) ApplyTransaction(w http.ResponseWriter, r *http.Request, body apigen.ApplyTransactionJSONRequestBody, repository string) {
+	var preconditions []apigen.TransactionPrecondition
+	if body.Preconditions != nil {
+		preconditions = *body.Preconditions
 	}
-
-	var metadata map[string]string
-	if entry.Metadata != nil {
-		metadata = entry.Metadata
-	} else {
-		metadata = map[string]string{}
+	nodes := make([]permissions.Node, 0, len(preconditions)+len(body.Operations))
+	for _, precondition := range preconditions {
+		nodes = append(nodes, permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(repository, precondition.Branch),
+			},
+		})
 	}
-
-	response := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
-		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: metadata},
+	for _, op := range body.Operations {
+		action := permissions.CreateCommitAction
+		if op.Type == string(graveler.TransactionOperationReset) {
+			action = permissions.RevertBranchAction
+		}
+		nodes = append(nodes, permissions.Node{
+			Permission: permissions.Permission{
+				Action:   action,
+				Resource: permissions.BranchArn(repository, op.Branch),
+			},
+		})
 	}
-	writeResponse(w, r, http.StatusCreated, response)
-}
-
-func (c *Controller) RevertBranch(w http.ResponseWriter, r *http.Request, body apigen.RevertBranchJSONRequestBody, repository, branch string) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.RevertBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
-		},
+		Type:  permissions.NodeTypeAnd,
+		Nodes: nodes,
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "revert_branch", r, repository, branch, "")
+	c.LogAction(ctx, "apply_transaction", r, repository, "", "")
 
 	user, err := auth.GetUser(ctx)
 	if err != nil {
 		writeError(w, r, http.StatusUnauthorized, "user not found")
 		return
 	}
-	err = c.Catalog.Revert(ctx, repository, branch, catalog.RevertParams{
-		Reference:    body.Ref,
-		Committer:    user.Committer(),
-		ParentNumber: body.ParentNumber,
-		AllowEmpty:   swag.BoolValue(body.AllowEmpty),
-	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	params := catalog.TransactionParams{
+		Preconditions: make(map[string]string, len(preconditions)),
+		Operations:    make([]catalog.TransactionOperation, 0, len(body.Operations)),
+		Committer:     user.Committer(),
+	}
+	for _, precondition := range preconditions {
+		params.Preconditions[precondition.Branch] = precondition.CommitId
+	}
+	for _, op := range body.Operations {
+		var metadata map[string]string
+		if op.Metadata != nil {
+			metadata = op.Metadata.AdditionalProperties
+		}
+		params.Operations = append(params.Operations, catalog.TransactionOperation{
+			Type:       op.Type,
+			Branch:     op.Branch,
+			Reference:  swag.StringValue(op.Ref),
+			Message:    swag.StringValue(op.Message),
+			Metadata:   metadata,
+			Strategy:   swag.StringValue(op.Strategy),
+			AllowEmpty: swag.BoolValue(op.AllowEmpty),
+		})
+	}
+	heads, err := c.Catalog.Transaction(ctx, repository, params, graveler.WithForce(swag.BoolValue(body.Force)), withRequestUser(ctx))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+
+	response := apigen.TransactionResult{
+		Branches: make([]apigen.TransactionBranch, 0, len(heads)),
+	}
+	for branch, commitID := range heads {
+		response.Branches = append(response.Branches, apigen.TransactionBranch{Branch: branch, CommitId: commitID})
+	}
+	sort.Slice(response.Branches, func(i, j int) bool {
+		return response.Branches[i].Branch < response.Branches[j].Branch
+	})
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body apigen.CherryPickJSONRequestBody, repository string, branch string) {
+func (c *Controller) RebaseBranch(w http.ResponseWriter, r *http.Request, body apigen.RebaseBranchJSONRequestBody, repository string, branch string) {
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
@@ -3429,18 +4266,15 @@ func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body api
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "cherry_pick", r, repository, branch, body.Ref)
+	c.LogAction(ctx, "rebase_branch", r, repository, branch, body.Ref)
 
 	user, err := auth.GetUser(ctx)
 	if err != nil {
 		writeError(w, r, http.StatusUnauthorized, "user not found")
 		return
 	}
-	newCommit, err := c.Catalog.CherryPick(ctx, repository, branch, catalog.CherryPickParams{
-		Reference:    body.Ref,
-		Committer:    user.Committer(),
-		ParentNumber: body.ParentNumber,
-	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	newCommit, err := c.Catalog.Rebase(ctx, repository, branch, body.Ref, user.Committer(),
+		graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
@@ -3483,9 +4317,19 @@ func (c *Controller) GetCommit(w http.ResponseWriter, r *http.Request, repositor
 		Generation:   apiutil.Ptr(int64(commit.Generation)),
 		Version:      apiutil.Ptr(int(commit.Version)),
 	}
+	setCommitSignature(&response, commit)
 	writeResponse(w, r, http.StatusOK, response)
 }
 
+// setCommitSignature reports the commit signature on the response, and whether it was made by a trusted key
+func setCommitSignature(response *apigen.Commit, commit *catalog.CommitLog) {
+	if commit.Signature == nil {
+		return
+	}
+	response.Signature = apiutil.Ptr(base64.StdEncoding.EncodeToString(commit.Signature))
+	response.Verified = apiutil.Ptr(commit.Verified)
+}
+
 func (c *Controller) InternalGetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
 	c.GetGCRules(w, r, repository)
 }
@@ -3525,11 +4369,16 @@ func (c *Controller) PrepareGarbageCollectionCommits(w http.ResponseWriter, r *h
 	}
 	ctx := r.Context()
 	c.LogAction(ctx, "prepare_garbage_collection_commits", r, repository, "", "")
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 	gcRunMetadata, err := c.Catalog.PrepareExpiredCommits(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	presignedURL, _, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+		StorageID:      repo.StorageID,
 		Identifier:     gcRunMetadata.CommitsCSVLocation,
 		IdentifierType: block.IdentifierTypeFull,
 	}, block.PreSignModeRead)
@@ -3617,10 +4466,7 @@ func (c *Controller) InternalCreateBranchProtectionRule(w http.ResponseWriter, r
 	if rules.BranchPatternToBlockedActions == nil {
 		rules.BranchPatternToBlockedActions = make(map[string]*graveler.BranchProtectionBlockedActions)
 	}
-	blockedActions := &graveler.BranchProtectionBlockedActions{
-		Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT},
-	}
-	rules.BranchPatternToBlockedActions[body.Pattern] = blockedActions
+	rules.BranchPatternToBlockedActions[body.Pattern] = branchProtectionBlockedActions(apigen.BranchProtectionRule(body))
 	err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
@@ -3759,6 +4605,7 @@ func (c *Controller) DumpRefs(w http.ResponseWriter, r *http.Request, repository
 		return
 	}
 	err = c.BlockAdapter.Put(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       fmt.Sprintf("%s/refs_manifest.json", c.Config.Committed.BlockStoragePrefix),
@@ -3821,7 +4668,7 @@ func (c *Controller) RestoreRefs(w http.ResponseWriter, r *http.Request, body ap
 		return
 	}
 
-	err = c.Catalog.LoadBranches(ctx, repo.Name, body.BranchesMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
+	err = c.Catalog.LoadBranches(ctx, repo.Name, body.BranchesMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)), withRequestUser(ctx))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
@@ -4056,7 +4903,7 @@ func (c *Controller) CreateSymlinkFile(w http.ResponseWriter, r *http.Request, r
 		}
 		// loop all entries enter to map[path] physicalAddress
 		for _, entry := range entries {
-			qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+			qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
 			if err != nil {
 				writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while resolving address: %s", err))
 				return
@@ -4099,6 +4946,7 @@ func writeSymlink(ctx context.Context, repo *catalog.Repository, branch, path st
 	address := fmt.Sprintf("%s/%s/%s/%s/symlink.txt", lakeFSPrefix, repo.Name, branch, path)
 	data := strings.Join(addresses, "\n")
 	err := adapter.Put(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       address,
@@ -4155,6 +5003,51 @@ func (c *Controller) DiffRefs(w http.ResponseWriter, r *http.Request, repository
 	writeResponse(w, r, http.StatusOK, response)
 }
 
+func (c *Controller) DiffRefsSummary(w http.ResponseWriter, r *http.Request, repository, leftRef, rightRef string, params apigen.DiffRefsSummaryParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "diff_refs_summary", r, repository, rightRef, leftRef)
+	depth := catalog.DiffSummaryDefaultDepth
+	if params.Depth != nil {
+		depth = *params.Depth
+	}
+	summary, err := c.Catalog.DiffSummary(ctx, repository, leftRef, rightRef, catalog.DiffSummaryParams{
+		Prefix:   paginationPrefix(params.Prefix),
+		Depth:    depth,
+		ThreeDot: params.Type == nil || *params.Type != "two_dot", // default diff type is three-dot
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.DiffSummary{
+		Total:    diffStatsResponse(summary.Total),
+		Prefixes: make([]apigen.DiffStats, 0, len(summary.Prefixes)),
+	}
+	for _, s := range summary.Prefixes {
+		response.Prefixes = append(response.Prefixes, diffStatsResponse(s))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func diffStatsResponse(s catalog.DiffStats) apigen.DiffStats {
+	return apigen.DiffStats{
+		Path:         s.Path,
+		Added:        s.Added,
+		Removed:      s.Removed,
+		Changed:      s.Changed,
+		AddedBytes:   s.AddedBytes,
+		RemovedBytes: s.RemovedBytes,
+		ChangedBytes: s.ChangedBytes,
+	}
+}
+
 func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.LogCommitsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -4167,6 +5060,25 @@ func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, reposito
 	ctx := r.Context()
 	c.LogAction(ctx, "get_branch_commit_log", r, repository, ref, "")
 
+	var metadata map[string]string
+	if params.Metadata != nil {
+		metadata = make(map[string]string, len(*params.Metadata))
+		for _, kv := range *params.Metadata {
+			k, v, found := strings.Cut(kv, "=")
+			if !found || k == "" {
+				writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid metadata predicate '%s', expected key=value", kv))
+				return
+			}
+			metadata[k] = v
+		}
+	}
+
+	// the request binding sets an empty time in case the parameter is missing
+	until := params.Until
+	if until != nil && until.IsZero() {
+		until = nil
+	}
+
 	// get commit log
 	commitLog, hasMore, err := c.Catalog.ListCommits(ctx, repository, ref, catalog.LogParams{
 		PathList:      resolvePathList(params.Objects, params.Prefixes),
@@ -4175,7 +5087,10 @@ func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, reposito
 		Limit:         swag.BoolValue(params.Limit),
 		FirstParent:   swag.BoolValue(params.FirstParent),
 		Since:         params.Since,
+		Until:         until,
 		StopAt:        swag.StringValue(params.StopAt),
+		Committer:     swag.StringValue(params.Committer),
+		Metadata:      metadata,
 	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
@@ -4186,7 +5101,7 @@ func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, reposito
 		metadata := apigen.Commit_Metadata{
 			AdditionalProperties: commit.Metadata,
 		}
-		serializedCommits = append(serializedCommits, apigen.Commit{
+		serializedCommit := apigen.Commit{
 			Committer:    commit.Committer,
 			CreationDate: commit.CreationDate.Unix(),
 			Id:           commit.Reference,
@@ -4196,7 +5111,9 @@ func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, reposito
 			Parents:      commit.Parents,
 			Generation:   apiutil.Ptr(int64(commit.Generation)),
 			Version:      apiutil.Ptr(int(commit.Version)),
-		})
+		}
+		setCommitSignature(&serializedCommit, commit)
+		serializedCommits = append(serializedCommits, serializedCommit)
 	}
 
 	response := apigen.CommitList{
@@ -4297,9 +5214,11 @@ func (c *Controller) GetObject(w http.ResponseWriter, r *http.Request, repositor
 
 	// if pre-sign, return a redirect
 	pointer := block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   entry.AddressType.ToIdentifierType(),
 		Identifier:       entry.PhysicalAddress,
+		Compression:      entry.Metadata.Compression(),
 	}
 	if swag.BoolValue(params.Presign) {
 		location, _, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
@@ -4398,7 +5317,7 @@ func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, reposit
 
 	objList := make([]apigen.ObjectStats, 0, len(res))
 	for _, entry := range res {
-		qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+		qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
 		if err != nil {
 			writeError(w, r, http.StatusInternalServerError, err)
 			return
@@ -4424,7 +5343,7 @@ func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, reposit
 				ContentType:     swag.String(entry.ContentType),
 			}
 			if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
-				objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata}
+				objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata.UserMetadata()}
 			}
 			if swag.BoolValue(params.Presign) {
 				// check if the user has read permissions for this object
@@ -4443,6 +5362,7 @@ func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, reposit
 				if authResponse.Allowed {
 					var expiry time.Time
 					objStat.PhysicalAddress, expiry, err = c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+						StorageID:        repo.StorageID,
 						StorageNamespace: repo.StorageNamespace,
 						IdentifierType:   entry.AddressType.ToIdentifierType(),
 						Identifier:       entry.PhysicalAddress,
@@ -4473,6 +5393,24 @@ func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, reposit
 	writeResponse(w, r, http.StatusOK, response)
 }
 
+func (c *Controller) RestoreObject(w http.ResponseWriter, r *http.Request, body apigen.RestoreObjectJSONRequestBody, repository, ref string, params apigen.RestoreObjectParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_object", r, repository, ref, "")
+	err := c.Catalog.RestoreObject(ctx, repository, ref, params.Path, body.Days)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, nil)
+}
+
 func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.StatObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -4495,7 +5433,7 @@ func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, reposito
 		return
 	}
 
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
@@ -4513,7 +5451,7 @@ func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, reposito
 	// add metadata if requested
 	var metadata map[string]string
 	if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
-		metadata = entry.Metadata
+		metadata = entry.Metadata.UserMetadata()
 	} else {
 		metadata = map[string]string{}
 	}
@@ -4525,6 +5463,7 @@ func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, reposito
 	} else if swag.BoolValue(params.Presign) {
 		// need to pre-sign the physical address
 		preSignedURL, expiry, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+			StorageID:        repo.StorageID,
 			StorageNamespace: repo.StorageNamespace,
 			IdentifierType:   entry.AddressType.ToIdentifierType(),
 			Identifier:       entry.PhysicalAddress,
@@ -4565,6 +5504,7 @@ func (c *Controller) GetUnderlyingProperties(w http.ResponseWriter, r *http.Requ
 
 	// read object properties from underlying storage
 	properties, err := c.BlockAdapter.GetProperties(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   entry.AddressType.ToIdentifierType(),
 		Identifier:       entry.PhysicalAddress,
@@ -4607,7 +5547,9 @@ func (c *Controller) MergeIntoBranch(w http.ResponseWriter, r *http.Request, bod
 		swag.StringValue(body.Message),
 		metadata,
 		swag.StringValue(body.Strategy),
-		graveler.WithForce(swag.BoolValue(body.Force)))
+		swag.BoolValue(body.SquashMerge),
+		graveler.WithForce(swag.BoolValue(body.Force)),
+		graveler.WithExpectedCommitID(graveler.CommitID(swag.StringValue(body.ExpectedCommitId))))
 
 	if errors.Is(err, graveler.ErrConflictFound) {
 		writeResponse(w, r, http.StatusConflict, apigen.MergeResult{
@@ -4646,6 +5588,76 @@ func (c *Controller) FindMergeBase(w http.ResponseWriter, r *http.Request, repos
 	})
 }
 
+func (c *Controller) ListMergeConflicts(w http.ResponseWriter, r *http.Request, repository, sourceRef, destinationBranch string, params apigen.ListMergeConflictsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_merge_conflicts", r, repository, destinationBranch, sourceRef)
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	conflicts, hasMore, err := c.Catalog.ListMergeConflicts(ctx, repository, destinationBranch, sourceRef,
+		swag.StringValue(params.Strategy),
+		paginationAmount(params.Amount),
+		paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	results := make([]apigen.MergeConflict, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		result := apigen.MergeConflict{Path: conflict.Path}
+		if result.Base, err = c.mergeConflictObjectStats(repo, conflict.Base); err != nil {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if result.Source, err = c.mergeConflictObjectStats(repo, conflict.Source); err != nil {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if result.Destination, err = c.mergeConflictObjectStats(repo, conflict.Destination); err != nil {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		results = append(results, result)
+	}
+	writeResponse(w, r, http.StatusOK, apigen.MergeConflictList{
+		Pagination: paginationFor(hasMore, results, "Path"),
+		Results:    results,
+	})
+}
+
+func (c *Controller) mergeConflictObjectStats(repo *catalog.Repository, entry *catalog.DBEntry) (*apigen.ObjectStats, error) {
+	if entry == nil {
+		return nil, nil
+	}
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageID, repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if err != nil {
+		return nil, err
+	}
+	objStat := &apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PhysicalAddress: qk.Format(),
+		PathType:        entryTypeObject,
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+	}
+	if entry.Metadata != nil {
+		objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata.UserMetadata()}
+	}
+	return objStat, nil
+}
+
 func (c *Controller) ListTags(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListTagsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -5173,6 +6185,15 @@ func (c *Controller) LogAction(ctx context.Context, action string, r *http.Reque
 	usageCounter.Add(1)
 }
 
+// withRequestUser returns the option recording the user of the request in the reflog of the branches it moves
+func withRequestUser(ctx context.Context) graveler.SetOptionsFunc {
+	var username string
+	if user, _ := auth.GetUser(ctx); user != nil {
+		username = user.Username
+	}
+	return graveler.WithUser(username)
+}
+
 func paginationFor(hasMore bool, results interface{}, fieldName string) apigen.Pagination {
 	pagination := apigen.Pagination{
 		HasMore:    hasMore,
@@ -3,6 +3,8 @@ package api_test
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -39,10 +41,12 @@ import (
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/ingest/store"
+	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/treeverse/lakefs/pkg/upload"
 	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const DefaultUserID = "example_user"
@@ -99,11 +103,11 @@ func TestController_ListRepositoriesHandler(t *testing.T) {
 	t.Run("list some repos", func(t *testing.T) {
 		// write some repos
 		ctx := context.Background()
-		_, err := deps.catalog.CreateRepository(ctx, "foo1", onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, "foo1", "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
-		_, err = deps.catalog.CreateRepository(ctx, "foo2", onBlock(deps, "foo1"), "main", false)
+		_, err = deps.catalog.CreateRepository(ctx, "foo2", "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
-		_, err = deps.catalog.CreateRepository(ctx, "foo3", onBlock(deps, "foo1"), "main", false)
+		_, err = deps.catalog.CreateRepository(ctx, "foo3", "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 
 		resp, err := clt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{})
@@ -184,7 +188,7 @@ func TestController_GetRepoHandler(t *testing.T) {
 
 	t.Run("get existing repo", func(t *testing.T) {
 		const testBranchName = "non-default"
-		_, err := deps.catalog.CreateRepository(context.Background(), "foo1", onBlock(deps, "foo1"), testBranchName, false)
+		_, err := deps.catalog.CreateRepository(context.Background(), "foo1", "", onBlock(deps, "foo1"), testBranchName, false)
 		testutil.Must(t, err)
 
 		resp, err := clt.GetRepositoryWithResponse(ctx, "foo1")
@@ -226,7 +230,7 @@ func testCommitEntries(t *testing.T, ctx context.Context, cat *catalog.Catalog,
 			})
 		testutil.MustDo(t, "create entry "+p, err)
 	}
-	commit, err := cat.Commit(ctx, params.repo, params.branch, "commit"+params.commitName, params.user, nil, nil, nil, false)
+	commit, err := cat.Commit(ctx, params.repo, params.branch, "commit"+params.commitName, params.user, nil, nil, nil, false, nil, nil)
 	testutil.MustDo(t, "commit", err)
 	return commit.Reference
 }
@@ -236,7 +240,7 @@ func TestController_LogCommitsMissingBranch(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "ns1"), "main", false)
 	testutil.Must(t, err)
 
 	resp, err := clt.LogCommitsWithResponse(ctx, repo, "otherbranch", &apigen.LogCommitsParams{})
@@ -257,6 +261,8 @@ func TestController_LogCommitsHandler(t *testing.T) {
 		expectedCommits int
 		objects         []string
 		prefixes        []string
+		committer       string
+		metadata        []string
 	}{
 		{
 			name:            "log",
@@ -287,13 +293,38 @@ func TestController_LogCommitsHandler(t *testing.T) {
 			expectedCommits: 10,
 			prefixes:        []string{"foo/bar"},
 		},
+		{
+			name:            "log-with-committer",
+			commits:         10,
+			expectedCommits: 5,
+			committer:       "other_user",
+		},
+		{
+			name:            "log-with-metadata",
+			commits:         10,
+			expectedCommits: 1,
+			metadata:        []string{"job_id=3"},
+		},
+		{
+			name:            "log-with-metadata-no-match",
+			commits:         10,
+			expectedCommits: 0,
+			metadata:        []string{"job_id=3", "job_id2=3"},
+		},
+		{
+			name:            "log-with-objects-and-committer",
+			commits:         10,
+			expectedCommits: 1,
+			objects:         []string{"foo/bar3", "foo/bar4"},
+			committer:       "some_user",
+		},
 	}
 
 	for _, ttt := range tests {
 		tt := ttt
 		t.Run(tt.name, func(t *testing.T) {
 			repo := testUniqueRepoName()
-			_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+			_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 			testutil.Must(t, err)
 
 			const prefix = "foo/bar"
@@ -302,10 +333,20 @@ func TestController_LogCommitsHandler(t *testing.T) {
 				p := prefix + n
 				err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n})
 				testutil.MustDo(t, "create entry "+p, err)
-				_, err = deps.catalog.Commit(ctx, repo, "main", "commit"+n, "some_user", nil, nil, nil, false)
+				committer := "some_user"
+				if i%2 == 1 {
+					committer = "other_user"
+				}
+				_, err = deps.catalog.Commit(ctx, repo, "main", "commit"+n, committer, catalog.Metadata{"job_id": n}, nil, nil, false, nil, nil)
 				testutil.MustDo(t, "commit "+p, err)
 			}
 			params := &apigen.LogCommitsParams{}
+			if tt.committer != "" {
+				params.Committer = &tt.committer
+			}
+			if tt.metadata != nil {
+				params.Metadata = &tt.metadata
+			}
 			if tt.objects != nil {
 				params.Objects = &tt.objects
 			}
@@ -327,6 +368,23 @@ func TestController_LogCommitsHandler(t *testing.T) {
 	}
 }
 
+func TestController_LogCommitsInvalidMetadata(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "ns1"), "main", false)
+	testutil.Must(t, err)
+
+	resp, err := clt.LogCommitsWithResponse(ctx, repo, "main", &apigen.LogCommitsParams{
+		Metadata: &[]string{"job_id"},
+	})
+	testutil.Must(t, err)
+	if resp.JSON400 == nil {
+		t.Fatalf("expected bad request on invalid metadata predicate, got %s", resp.Status())
+	}
+}
+
 // TestController_LogCommitsParallelHandler sends concurrent requests to LogCommits.
 // LogCommits uses shared work pool, checking correctness for concurrent work is important.
 func TestController_LogCommitsParallelHandler(t *testing.T) {
@@ -334,7 +392,7 @@ func TestController_LogCommitsParallelHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 
 	commits := 100
@@ -345,7 +403,7 @@ func TestController_LogCommitsParallelHandler(t *testing.T) {
 		p := prefix + n
 		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n})
 		testutil.MustDo(t, "create entry "+p, err)
-		log, err := deps.catalog.Commit(ctx, repo, "main", t.Name()+" commit"+n, "some_user", nil, nil, nil, false)
+		log, err := deps.catalog.Commit(ctx, repo, "main", t.Name()+" commit"+n, "some_user", nil, nil, nil, false, nil, nil)
 		testutil.MustDo(t, "commit "+p, err)
 		if i%4 == 0 {
 			commitsToLook[p] = log
@@ -382,7 +440,7 @@ func TestController_LogCommitsPredefinedData(t *testing.T) {
 
 	// prepare test data
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 	const prefix = "foo/bar"
 	const totalCommits = 10
@@ -392,7 +450,7 @@ func TestController_LogCommitsPredefinedData(t *testing.T) {
 		p := prefix + n
 		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n})
 		testutil.MustDo(t, "create entry "+p, err)
-		commit, err := deps.catalog.Commit(ctx, repo, "main", "commit"+n, "some_user", nil, nil, nil, false)
+		commit, err := deps.catalog.Commit(ctx, repo, "main", "commit"+n, "some_user", nil, nil, nil, false, nil, nil)
 		testutil.MustDo(t, "commit "+p, err)
 		commits[i] = commit
 	}
@@ -532,6 +590,45 @@ func TestController_LogCommitsPredefinedData(t *testing.T) {
 	}
 }
 
+func TestController_LogCommitsUntilStopAt(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	now := time.Now()
+	commits := make([]*catalog.CommitLog, 3)
+	for i := range commits {
+		n := strconv.Itoa(i + 1)
+		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar" + n, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: now, Size: 1, Checksum: "cksum" + n})
+		testutil.MustDo(t, "create entry", err)
+		date := now.Add(time.Duration(i-len(commits)) * time.Hour).Unix()
+		commits[i], err = deps.catalog.Commit(ctx, repo, "main", "commit"+n, "some_user", nil, &date, nil, false, nil, nil)
+		testutil.MustDo(t, "commit", err)
+	}
+
+	// stop at a commit newer than until, the commits older than it are not listed
+	until := now.Add(-150 * time.Minute)
+	resp, err := clt.LogCommitsWithResponse(ctx, repo, "main", &apigen.LogCommitsParams{
+		Until:  &until,
+		StopAt: &commits[1].Reference,
+	})
+	verifyResponseOK(t, resp, err)
+	if len(resp.JSON200.Results) != 0 {
+		t.Fatalf("Log expected no commits, got %d: first %s", len(resp.JSON200.Results), resp.JSON200.Results[0].Message)
+	}
+
+	resp, err = clt.LogCommitsWithResponse(ctx, repo, "main", &apigen.LogCommitsParams{
+		Until:  &until,
+		StopAt: &commits[0].Reference,
+	})
+	verifyResponseOK(t, resp, err)
+	if len(resp.JSON200.Results) != 1 || resp.JSON200.Results[0].Message != "commit1" {
+		t.Fatalf("Log expected commit1 only, got %d commits", len(resp.JSON200.Results))
+	}
+}
+
 func TestController_CommitsGetBranchCommitLogByPath(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -576,7 +673,7 @@ func TestController_CommitsGetBranchCommitLogByPath(t *testing.T) {
 	*/
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "ns1"), "main", false)
 	testutil.Must(t, err)
 
 	commitsMap := make(map[string]string)
@@ -632,7 +729,7 @@ func TestController_CommitsGetBranchCommitLogByPath(t *testing.T) {
 		user:         "user3",
 		commitName:   "P",
 	})
-	mergeCommit, err := deps.catalog.Merge(ctx, repo, "main", "branch-b", "user3", "commitR", catalog.Metadata{}, "")
+	mergeCommit, err := deps.catalog.Merge(ctx, repo, "main", "branch-b", "user3", "commitR", catalog.Metadata{}, "", false)
 	testutil.Must(t, err)
 	commitsMap["commitR"] = mergeCommit
 	commitsMap["commitM"] = testCommitEntries(t, ctx, deps.catalog, deps, commitEntriesParams{
@@ -643,7 +740,7 @@ func TestController_CommitsGetBranchCommitLogByPath(t *testing.T) {
 		user:         "user2",
 		commitName:   "M",
 	})
-	mergeCommit, err = deps.catalog.Merge(ctx, repo, "main", "branch-a", "user2", "commitN", catalog.Metadata{}, "")
+	mergeCommit, err = deps.catalog.Merge(ctx, repo, "main", "branch-a", "user2", "commitN", catalog.Metadata{}, "", false)
 	testutil.Must(t, err)
 	commitsMap["commitN"] = mergeCommit
 	commitsMap["commitX"] = testCommitEntries(t, ctx, deps.catalog, deps, commitEntriesParams{
@@ -739,10 +836,10 @@ func TestController_GetCommitHandler(t *testing.T) {
 
 	t.Run("get existing commit", func(t *testing.T) {
 		ctx := context.Background()
-		_, err := deps.catalog.CreateRepository(ctx, "foo1", onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, "foo1", "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 		testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, "foo1", "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
-		commit1, err := deps.catalog.Commit(ctx, "foo1", "main", "some message", DefaultUserID, nil, nil, nil, false)
+		commit1, err := deps.catalog.Commit(ctx, "foo1", "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 		reference1, err := deps.catalog.GetBranchReference(ctx, "foo1", "main")
 		if err != nil {
@@ -763,10 +860,10 @@ func TestController_GetCommitHandler(t *testing.T) {
 	t.Run("branch commit", func(t *testing.T) {
 		ctx := context.Background()
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
-		commit1, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false)
+		commit1, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 		reference1, err := deps.catalog.GetBranchReference(ctx, repo, "main")
 		if err != nil {
@@ -791,10 +888,10 @@ func TestController_GetCommitHandler(t *testing.T) {
 	t.Run("tag commit", func(t *testing.T) {
 		ctx := context.Background()
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
-		commit1, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false)
+		commit1, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 		_, err = deps.catalog.CreateTag(ctx, repo, "tag1", commit1.Reference)
 		if err != nil {
@@ -816,7 +913,7 @@ func TestController_GetCommitHandler(t *testing.T) {
 	t.Run("initial commit", func(t *testing.T) {
 		// validate a new repository's initial commit existence and structure
 		ctx := context.Background()
-		_, err := deps.catalog.CreateRepository(ctx, "foo2", onBlock(deps, "foo2"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, "foo2", "", onBlock(deps, "foo2"), "main", false)
 		testutil.Must(t, err)
 		resp, err := clt.GetCommitWithResponse(ctx, "foo2", "main")
 		verifyResponseOK(t, resp, err)
@@ -867,7 +964,7 @@ func TestController_CommitHandler(t *testing.T) {
 
 	t.Run("commit success", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
 		testutil.MustDo(t, fmt.Sprintf("commit bar on %s", repo), deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar", PhysicalAddress: "pa", CreationDate: time.Now(), Size: 666, Checksum: "cs", Metadata: nil}))
 		resp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
@@ -878,7 +975,7 @@ func TestController_CommitHandler(t *testing.T) {
 
 	t.Run("commit success with source metarange", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
 
 		_, err = deps.catalog.CreateBranch(ctx, repo, "foo-branch", "main")
@@ -897,7 +994,7 @@ func TestController_CommitHandler(t *testing.T) {
 
 	t.Run("commit failure with source metarange and dirty branch", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
 
 		_, err = deps.catalog.CreateBranch(ctx, repo, "foo-branch", "main")
@@ -920,9 +1017,52 @@ func TestController_CommitHandler(t *testing.T) {
 		require.Contains(t, resp.JSON400.Message, graveler.ErrCommitMetaRangeDirtyBranch.Error())
 	})
 
+	t.Run("commit success with paths", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
+		for _, p := range []string{"job1/a", "job1/b", "job2/a", "other"} {
+			testutil.MustDo(t, fmt.Sprintf("create %s on %s", p, repo), deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: "pa", CreationDate: time.Now(), Size: 666, Checksum: "cs", Metadata: nil}))
+		}
+		resp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "job1 commit",
+			Paths:   &[]string{"job1/", "other"},
+		})
+		verifyResponseOK(t, resp, err)
+
+		diffResp, err := clt.DiffRefsWithResponse(ctx, repo, "main~1", "main", &apigen.DiffRefsParams{})
+		verifyResponseOK(t, diffResp, err)
+		committed := make([]string, 0, len(diffResp.JSON200.Results))
+		for _, d := range diffResp.JSON200.Results {
+			committed = append(committed, d.Path)
+		}
+		require.Equal(t, []string{"job1/a", "job1/b", "other"}, committed)
+
+		uncommittedResp, err := clt.DiffBranchWithResponse(ctx, repo, "main", &apigen.DiffBranchParams{})
+		verifyResponseOK(t, uncommittedResp, err)
+		require.Len(t, uncommittedResp.JSON200.Results, 1)
+		require.Equal(t, "job2/a", uncommittedResp.JSON200.Results[0].Path)
+
+		resp, err = clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "job1 empty commit",
+			Paths:   &[]string{"job1/"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+		resp, err = clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "job2 commit",
+			Paths:   &[]string{"job2/"},
+		})
+		verifyResponseOK(t, resp, err)
+		uncommittedResp, err = clt.DiffBranchWithResponse(ctx, repo, "main", &apigen.DiffBranchParams{})
+		verifyResponseOK(t, uncommittedResp, err)
+		require.Empty(t, uncommittedResp.JSON200.Results)
+	})
+
 	t.Run("commit failure empty branch", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
 
 		_, err = deps.catalog.CreateBranch(ctx, repo, "foo-branch", "main")
@@ -940,7 +1080,7 @@ func TestController_CommitHandler(t *testing.T) {
 
 	t.Run("commit success - with creation date", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
 		testutil.MustDo(t, fmt.Sprintf("commit bar on %s", repo), deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar", PhysicalAddress: "pa", CreationDate: time.Now(), Size: 666, Checksum: "cs", Metadata: nil}))
 		date := int64(1642626109)
@@ -956,7 +1096,7 @@ func TestController_CommitHandler(t *testing.T) {
 
 	t.Run("protected branch", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, "create repository", err)
 		rules := map[string]*graveler.BranchProtectionBlockedActions{
 			"main": {
@@ -981,7 +1121,7 @@ func TestController_CommitHandler(t *testing.T) {
 	})
 	t.Run("read only repo", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", true)
 		testutil.MustDo(t, "create repository", err)
 		err = deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar", PhysicalAddress: "pa", CreationDate: time.Now(), Size: 666, Checksum: "cs", Metadata: nil})
 		require.Error(t, err, "read-only repository")
@@ -1048,7 +1188,7 @@ func TestController_CreateRepositoryHandler(t *testing.T) {
 
 	t.Run("create repo duplicate", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1167,6 +1307,22 @@ func TestController_CreateRepositoryHandler(t *testing.T) {
 			t.Fatal("expected status code 400 for invalid namespace, got", resp.StatusCode())
 		}
 	})
+
+	t.Run("create repo with unknown storage id", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		resp, _ := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			DefaultBranch:    apiutil.Ptr("main"),
+			Name:             repo,
+			StorageId:        apiutil.Ptr("unknown"),
+			StorageNamespace: onBlock(deps, "foo-bucket-2"),
+		})
+		if resp == nil {
+			t.Fatal("CreateRepository missing response")
+		}
+		if resp.JSON400 == nil {
+			t.Fatal("expected status code 400 for unknown storage id, got", resp.StatusCode())
+		}
+	})
 }
 
 func TestController_DeleteRepositoryHandler(t *testing.T) {
@@ -1175,7 +1331,7 @@ func TestController_DeleteRepositoryHandler(t *testing.T) {
 
 	t.Run("delete repo success", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 
 		resp, err := clt.DeleteRepositoryWithResponse(ctx, repo, &apigen.DeleteRepositoryParams{})
@@ -1201,7 +1357,7 @@ func TestController_DeleteRepositoryHandler(t *testing.T) {
 	t.Run("delete repo doesnt delete other repos", func(t *testing.T) {
 		names := []string{"rr0", "rr1", "rr11", "rr2"}
 		for _, name := range names {
-			_, err := deps.catalog.CreateRepository(ctx, name, onBlock(deps, "foo1"), "main", false)
+			_, err := deps.catalog.CreateRepository(ctx, name, "", onBlock(deps, "foo1"), "main", false)
 			testutil.Must(t, err)
 		}
 
@@ -1221,7 +1377,7 @@ func TestController_DeleteRepositoryHandler(t *testing.T) {
 
 	t.Run("delete read-only repository", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", true)
 		testutil.Must(t, err)
 
 		resp, err := clt.DeleteRepositoryWithResponse(ctx, repo, &apigen.DeleteRepositoryParams{})
@@ -1533,7 +1689,7 @@ func TestController_ListBranchesHandler(t *testing.T) {
 	t.Run("list branches only default", func(t *testing.T) {
 		ctx := context.Background()
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 		resp, err := clt.ListBranchesWithResponse(ctx, repo, &apigen.ListBranchesParams{
 			Amount: apiutil.Ptr(apigen.PaginationAmount(-1)),
@@ -1550,12 +1706,12 @@ func TestController_ListBranchesHandler(t *testing.T) {
 	t.Run("list branches pagination", func(t *testing.T) {
 		ctx := context.Background()
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo2"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo2"), "main", false)
 		testutil.Must(t, err)
 
 		// create the first "dummy" commit on main so that we can create branches from it
 		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "a/b"}))
-		_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 
 		for i := 0; i < 7; i++ {
@@ -1607,10 +1763,10 @@ func TestController_ListTagsHandler(t *testing.T) {
 
 	// setup test data
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 	testutil.Must(t, err)
 	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "obj1"}))
-	commitLog, err := deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false)
+	commitLog, err := deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 	const createTagLen = 7
 	var createdTags []apigen.Ref
@@ -1689,13 +1845,13 @@ func TestController_GetBranchHandler(t *testing.T) {
 
 	const testBranch = "main"
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), testBranch, false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), testBranch, false)
 	testutil.Must(t, err)
 
 	t.Run("get default branch", func(t *testing.T) {
 		// create the first "dummy" commit on main so that we can create branches from it
 		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, testBranch, catalog.DBEntry{Path: "a/b"}))
-		_, err = deps.catalog.Commit(ctx, repo, testBranch, "first commit", "test", nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, repo, testBranch, "first commit", "test", nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 
 		resp, err := clt.GetBranchWithResponse(ctx, repo, testBranch)
@@ -1733,7 +1889,7 @@ func TestController_BranchesDiffBranchHandler(t *testing.T) {
 	ctx := context.Background()
 	const testBranch = "main"
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), testBranch, false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), testBranch, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1783,10 +1939,10 @@ func TestController_CreateBranchHandler(t *testing.T) {
 	ctx := context.Background()
 	t.Run("create branch and diff refs success", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "a/b"}))
-		_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 
 		const newBranchName = "main2"
@@ -1805,7 +1961,7 @@ func TestController_CreateBranchHandler(t *testing.T) {
 		uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(content), repo, newBranchName)
 		verifyResponseOK(t, uploadResp, err)
 
-		if _, err := deps.catalog.Commit(ctx, repo, "main2", "commit 1", "some_user", nil, nil, nil, false); err != nil {
+		if _, err := deps.catalog.Commit(ctx, repo, "main2", "commit 1", "some_user", nil, nil, nil, false, nil, nil); err != nil {
 			t.Fatalf("failed to commit 'repo1': %s", err)
 		}
 		resp2, err := clt.DiffRefsWithResponse(ctx, repo, "main", newBranchName, &apigen.DiffRefsParams{})
@@ -1821,7 +1977,7 @@ func TestController_CreateBranchHandler(t *testing.T) {
 
 	t.Run("create branch missing commit", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 		resp, err := clt.CreateBranchWithResponse(ctx, repo, apigen.CreateBranchJSONRequestBody{
 			Name:   "main3",
@@ -1851,7 +2007,7 @@ func TestController_CreateBranchHandler(t *testing.T) {
 
 	t.Run("create branch conflict with branch", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 
 		resp, err := clt.CreateBranchWithResponse(ctx, repo, apigen.CreateBranchJSONRequestBody{
@@ -1868,7 +2024,7 @@ func TestController_CreateBranchHandler(t *testing.T) {
 
 	t.Run("create branch conflict with tag", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 
 		name := "tag123"
@@ -1889,7 +2045,7 @@ func TestController_CreateBranchHandler(t *testing.T) {
 
 	t.Run("create branch conflict with commit", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 
 		log, err := deps.catalog.GetCommit(ctx, repo, "main")
@@ -1909,10 +2065,10 @@ func TestController_CreateBranchHandler(t *testing.T) {
 
 	t.Run("read-only repository", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "foo1"), "main", true)
 		testutil.Must(t, err)
 		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "a/b"}, graveler.WithForce(true)))
-		_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false, graveler.WithForce(true))
+		_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false, nil, nil, graveler.WithForce(true))
 		testutil.Must(t, err)
 
 		const newBranchName = "main2"
@@ -1940,7 +2096,7 @@ func TestController_DiffRefsHandler(t *testing.T) {
 	t.Run("diff prefix with and without delimiter", func(t *testing.T) {
 		repoName := testUniqueRepoName()
 		const newBranchName = "main2"
-		_, err := deps.catalog.CreateRepository(ctx, repoName, onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repoName, "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 
 		resp, err := clt.CreateBranchWithResponse(ctx, repoName, apigen.CreateBranchJSONRequestBody{
@@ -1960,7 +2116,7 @@ func TestController_DiffRefsHandler(t *testing.T) {
 		uploadResp, err := uploadObjectHelper(t, ctx, clt, fullPath, strings.NewReader(content), repoName, newBranchName)
 		verifyResponseOK(t, uploadResp, err)
 
-		if _, err := deps.catalog.Commit(ctx, repoName, newBranchName, "commit 1", "some_user", nil, nil, nil, false); err != nil {
+		if _, err := deps.catalog.Commit(ctx, repoName, newBranchName, "commit 1", "some_user", nil, nil, nil, false, nil, nil); err != nil {
 			t.Fatalf("failed to commit 'repo1': %s", err)
 		}
 		resp2, err := clt.DiffRefsWithResponse(ctx, repoName, "main", newBranchName, &apigen.DiffRefsParams{})
@@ -2026,7 +2182,7 @@ func TestController_UploadObjectHandler(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
-	_, err := deps.catalog.CreateRepository(ctx, "my-new-repo", onBlock(deps, "foo1"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, "my-new-repo", "", onBlock(deps, "foo1"), "main", false)
 	testutil.Must(t, err)
 
 	t.Run("upload object", func(t *testing.T) {
@@ -2106,7 +2262,7 @@ func TestController_UploadObjectHandler(t *testing.T) {
 		}
 
 		// commit
-		_, err = deps.catalog.Commit(ctx, "my-new-repo", "another-branch", "a commit!", "user1", nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, "my-new-repo", "another-branch", "a commit!", "user1", nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 
 		// overwrite after commit
@@ -2153,6 +2309,53 @@ func TestController_UploadObjectHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("overwrite with if-match", func(t *testing.T) {
+		contentType, buf := writeMultipart("content", "baz5", "hello world!")
+		resp, err := clt.UploadObjectWithBodyWithResponse(ctx, "my-new-repo", "main", &apigen.UploadObjectParams{
+			Path: "foo/baz5",
+		}, contentType, buf)
+		testutil.Must(t, err)
+		if resp.JSON201 == nil {
+			t.Fatalf("UploadObject status code=%d, expected 201", resp.StatusCode())
+		}
+		ifMatch := apigen.IfMatch(`"` + resp.JSON201.Checksum + `"`)
+
+		// overwrite the object with its current ETag
+		contentType, buf = writeMultipart("content", "baz5", "something else!")
+		resp, err = clt.UploadObjectWithBodyWithResponse(ctx, "my-new-repo", "main", &apigen.UploadObjectParams{
+			Path:    "foo/baz5",
+			IfMatch: &ifMatch,
+		}, contentType, buf)
+		testutil.Must(t, err)
+		if resp.JSON201 == nil {
+			t.Fatalf("UploadObject status code=%d, expected 201", resp.StatusCode())
+		}
+
+		// the previous ETag no longer matches
+		contentType, buf = writeMultipart("content", "baz5", "third time")
+		resp, err = clt.UploadObjectWithBodyWithResponse(ctx, "my-new-repo", "main", &apigen.UploadObjectParams{
+			Path:    "foo/baz5",
+			IfMatch: &ifMatch,
+		}, contentType, buf)
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusPreconditionFailed {
+			t.Fatalf("UploadObject status code=%d, expected 412", resp.StatusCode())
+		}
+	})
+
+	t.Run("if-match (no entry)", func(t *testing.T) {
+		ifMatch := apigen.IfMatch("*")
+		contentType, buf := writeMultipart("content", "baz6", "hello world!")
+		resp, err := clt.UploadObjectWithBodyWithResponse(ctx, "my-new-repo", "main", &apigen.UploadObjectParams{
+			Path:    "foo/baz6",
+			IfMatch: &ifMatch,
+		}, contentType, buf)
+		testutil.Must(t, err)
+		if resp.JSON404 == nil {
+			t.Fatalf("UploadObject status code=%d, expected 404", resp.StatusCode())
+		}
+	})
+
 	t.Run("upload object missing 'content' key", func(t *testing.T) {
 		// write
 		contentType, buf := writeMultipart("this-is-not-content", "bar", "hello world!")
@@ -2171,7 +2374,7 @@ func TestController_UploadObjectHandler(t *testing.T) {
 
 	t.Run("read-only repository", func(t *testing.T) {
 		repoName := "my-new-read-only-repo"
-		_, err := deps.catalog.CreateRepository(ctx, repoName, onBlock(deps, "foo2"), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repoName, "", onBlock(deps, "foo2"), "main", true)
 		testutil.Must(t, err)
 		// write
 		contentType, buf := writeMultipart("content", "bar", "hello world!")
@@ -2202,10 +2405,10 @@ func TestController_DeleteBranchHandler(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("delete branch success", func(t *testing.T) {
-		_, err := deps.catalog.CreateRepository(ctx, "my-new-repo", onBlock(deps, "foo1"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, "my-new-repo", "", onBlock(deps, "foo1"), "main", false)
 		testutil.Must(t, err)
 		testutil.Must(t, deps.catalog.CreateEntry(ctx, "my-new-repo", "main", catalog.DBEntry{Path: "a/b"}))
-		_, err = deps.catalog.Commit(ctx, "my-new-repo", "main", "first commit", "test", nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, "my-new-repo", "main", "first commit", "test", nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 
 		_, err = deps.catalog.CreateBranch(ctx, "my-new-repo", "main2", "main")
@@ -2223,7 +2426,7 @@ func TestController_DeleteBranchHandler(t *testing.T) {
 	})
 
 	t.Run("delete default branch", func(t *testing.T) {
-		_, err := deps.catalog.CreateRepository(ctx, "my-new-repo2", onBlock(deps, "foo2"), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, "my-new-repo2", "", onBlock(deps, "foo2"), "main", false)
 		testutil.Must(t, err)
 		resp, err := clt.DeleteBranchWithResponse(ctx, "my-new-repo2", "main", &apigen.DeleteBranchParams{})
 		if err != nil {
@@ -2246,10 +2449,10 @@ func TestController_DeleteBranchHandler(t *testing.T) {
 
 	t.Run("read-only repository", func(t *testing.T) {
 		repoName := "read-only-repo"
-		_, err := deps.catalog.CreateRepository(ctx, repoName, onBlock(deps, "foo1"), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repoName, "", onBlock(deps, "foo1"), "main", true)
 		testutil.Must(t, err)
 		testutil.Must(t, deps.catalog.CreateEntry(ctx, repoName, "main", catalog.DBEntry{Path: "a/b"}, graveler.WithForce(true)))
-		_, err = deps.catalog.Commit(ctx, repoName, "main", "first commit", "test", nil, nil, nil, false, graveler.WithForce(true))
+		_, err = deps.catalog.Commit(ctx, repoName, "main", "first commit", "test", nil, nil, nil, false, nil, nil, graveler.WithForce(true))
 		testutil.Must(t, err)
 
 		_, err = deps.catalog.CreateBranch(ctx, repoName, "main2", "main", graveler.WithForce(true))
@@ -2277,7 +2480,7 @@ func TestController_ObjectsStatObjectHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "some-bucket"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "some-bucket"), "main", false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2374,7 +2577,7 @@ func TestController_ObjectsListObjectsHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "bucket/prefix"), "main", false)
 	testutil.Must(t, err)
 	dbEntries := []catalog.DBEntry{
 		{
@@ -2469,7 +2672,7 @@ func TestController_ObjectsHeadObjectHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "ns1"), "main", false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2479,7 +2682,7 @@ func TestController_ObjectsHeadObjectHandler(t *testing.T) {
 	buf := new(bytes.Buffer)
 	buf.WriteString("this is file content made up of bytes")
 	address := upload.DefaultPathProvider.NewPath()
-	blob, err := upload.WriteBlob(context.Background(), deps.blocks, onBlock(deps, "ns1"), address, buf, 37, block.PutOpts{StorageClass: &expensiveString})
+	blob, err := upload.WriteBlob(context.Background(), deps.blocks, "", onBlock(deps, "ns1"), address, buf, 37, block.PutOpts{StorageClass: &expensiveString})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2545,7 +2748,7 @@ func TestController_ObjectsGetObjectHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "ns1"), "main", false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2555,7 +2758,7 @@ func TestController_ObjectsGetObjectHandler(t *testing.T) {
 	buf := new(bytes.Buffer)
 	buf.WriteString("this is file content made up of bytes")
 	address := upload.DefaultPathProvider.NewPath()
-	blob, err := upload.WriteBlob(context.Background(), deps.blocks, onBlock(deps, "ns1"), address, buf, 37, block.PutOpts{StorageClass: &expensiveString})
+	blob, err := upload.WriteBlob(context.Background(), deps.blocks, "", onBlock(deps, "ns1"), address, buf, 37, block.PutOpts{StorageClass: &expensiveString})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2705,7 +2908,7 @@ func TestController_ObjectsUploadObjectHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "bucket/prefix"), "main", false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2766,7 +2969,7 @@ func TestController_ObjectsUploadObjectHandler(t *testing.T) {
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepo := testUniqueRepoName()
 		path := "foo/bar"
-		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, onBlock(deps, "bucket/prefix"), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, "", onBlock(deps, "bucket/prefix"), "main", true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -2839,7 +3042,7 @@ func TestController_ObjectsStageObjectHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "bucket/prefix"), "main", false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2931,7 +3134,7 @@ func TestController_ObjectsStageObjectHandler(t *testing.T) {
 
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, onBlock(deps, "bucket/prefix"), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, "", onBlock(deps, "bucket/prefix"), "main", true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -2975,7 +3178,7 @@ func TestController_LinkPhysicalAddressHandler(t *testing.T) {
 
 	repo := testUniqueRepoName()
 	ns := onBlock(deps, "bucket/prefix")
-	_, err := deps.catalog.CreateRepository(ctx, repo, ns, "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", ns, "main", false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -3155,7 +3358,7 @@ func TestController_LinkPhysicalAddressHandler(t *testing.T) {
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepo := testUniqueRepoName()
 		readOnlyns := onBlock(deps, "bucket/prefix2")
-		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, readOnlyns, "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, "", readOnlyns, "main", true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -3197,13 +3400,98 @@ func TestController_LinkPhysicalAddressHandler(t *testing.T) {
 	})
 }
 
+func TestController_ReservedMetadata(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
+
+	reserved := map[string]string{
+		catalog.CompressionMetadataKey:          "gzip",
+		catalog.ObjectTagMetadataPrefix + "env": "prod",
+	}
+	requireNotFound := func(t *testing.T, path string) {
+		t.Helper()
+		_, err := deps.catalog.GetEntry(ctx, repo, "main", path, catalog.GetEntryParams{})
+		require.ErrorIs(t, err, graveler.ErrNotFound)
+	}
+
+	t.Run("upload_object", func(t *testing.T) {
+		const path = "upload/object"
+		contentType, buf := writeMultipart("content", "object", "data")
+		resp, err := clt.UploadObjectWithBodyWithResponse(ctx, repo, "main", &apigen.UploadObjectParams{Path: path}, contentType, buf,
+			func(_ context.Context, req *http.Request) error {
+				req.Header.Set(apiutil.LakeFSHeaderInternalPrefix+"compression", "gzip")
+				return nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		requireNotFound(t, path)
+	})
+
+	for key, value := range reserved {
+		t.Run("link_physical_address "+key, func(t *testing.T) {
+			const path = "link/object"
+			linkResp, err := clt.GetPhysicalAddressWithResponse(ctx, repo, "main", &apigen.GetPhysicalAddressParams{Path: path})
+			verifyResponseOK(t, linkResp, err)
+			resp, err := clt.LinkPhysicalAddressWithResponse(ctx, repo, "main", &apigen.LinkPhysicalAddressParams{Path: path}, apigen.LinkPhysicalAddressJSONRequestBody{
+				Checksum:     "afb0689fe58b82c5f762991453edbbec",
+				SizeBytes:    38,
+				Staging:      apigen.StagingLocation{PhysicalAddress: linkResp.JSON200.PhysicalAddress},
+				UserMetadata: &apigen.StagingMetadata_UserMetadata{AdditionalProperties: map[string]string{key: value}},
+			})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+			requireNotFound(t, path)
+		})
+
+		t.Run("stage_object "+key, func(t *testing.T) {
+			const path = "stage/object"
+			resp, err := clt.StageObjectWithResponse(ctx, repo, "main", &apigen.StageObjectParams{Path: path}, apigen.StageObjectJSONRequestBody{
+				Checksum:        "afb0689fe58b82c5f762991453edbbec",
+				PhysicalAddress: onBlock(deps, "another-bucket/some/location"),
+				SizeBytes:       38,
+				Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: map[string]string{key: value}},
+			})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+			requireNotFound(t, path)
+		})
+	}
+
+	t.Run("filtered", func(t *testing.T) {
+		const path = "filtered/object"
+		metadata := catalog.Metadata{"color": "red"}.WithCompression("gzip").WithObjectTags(map[string]string{"env": "prod"})
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: "filtered_address",
+			CreationDate:    time.Now(),
+			Size:            4,
+			Checksum:        "filtered_checksum",
+			Metadata:        metadata,
+		}))
+		expected := map[string]string{"color": "red"}
+
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: path})
+		verifyResponseOK(t, statResp, err)
+		require.Equal(t, expected, statResp.JSON200.Metadata.AdditionalProperties)
+
+		listResp, err := clt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{Prefix: apiutil.Ptr(apigen.PaginationPrefix("filtered/"))})
+		verifyResponseOK(t, listResp, err)
+		require.Len(t, listResp.JSON200.Results, 1)
+		require.Equal(t, expected, listResp.JSON200.Results[0].Metadata.AdditionalProperties)
+	})
+}
+
 func TestController_ObjectsDeleteObjectHandler(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
 	const branch = "main"
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "some-bucket/prefix"), branch, false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "some-bucket/prefix"), branch, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -3366,7 +3654,7 @@ func TestController_ObjectsDeleteObjectHandler(t *testing.T) {
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepo := testUniqueRepoName()
 		const branch = "main"
-		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, onBlock(deps, "some-bucket/prefix2"), branch, true)
+		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, "", onBlock(deps, "some-bucket/prefix2"), branch, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -3922,19 +4210,60 @@ func TestController_MergeDiffWithParent(t *testing.T) {
 	}
 }
 
+func TestController_SquashMerge(t *testing.T) {
+	clt, _ := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repoName := testUniqueRepoName()
+	repoResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+		DefaultBranch:    apiutil.Ptr("main"),
+		Name:             repoName,
+		StorageNamespace: "mem://",
+	})
+	verifyResponseOK(t, repoResp, err)
+
+	branchResp, err := clt.CreateBranchWithResponse(ctx, repoName, apigen.CreateBranchJSONRequestBody{Name: "work", Source: "main"})
+	verifyResponseOK(t, branchResp, err)
+
+	for _, name := range []string{"file1", "file2"} {
+		resp, err := uploadObjectHelper(t, ctx, clt, name, strings.NewReader(name), repoName, "work")
+		verifyResponseOK(t, resp, err)
+		commitResp, err := clt.CommitWithResponse(ctx, repoName, "work", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: name + " commit to work"})
+		verifyResponseOK(t, commitResp, err)
+	}
+
+	mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repoName, "work", "main", apigen.MergeIntoBranchJSONRequestBody{
+		Message:     apiutil.Ptr("squash work to main"),
+		SquashMerge: swag.Bool(true),
+	})
+	verifyResponseOK(t, mergeResp, err)
+
+	commitResp, err := clt.GetCommitWithResponse(ctx, repoName, mergeResp.JSON200.Reference)
+	verifyResponseOK(t, commitResp, err)
+	if len(commitResp.JSON200.Parents) != 1 {
+		t.Fatalf("expected squash merge commit with a single parent, got %v", commitResp.JSON200.Parents)
+	}
+
+	diffResp, err := clt.DiffRefsWithResponse(ctx, repoName, "main~1", "main", &apigen.DiffRefsParams{})
+	verifyResponseOK(t, diffResp, err)
+	if len(diffResp.JSON200.Results) != 2 {
+		t.Fatalf("expected squash merge commit to include 2 changes, got %+v", diffResp.JSON200.Results)
+	}
+}
+
 func TestController_MergeIntoExplicitBranch(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
 	// setup env
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
 	testutil.Must(t, err)
 	err = deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"})
 	testutil.Must(t, err)
-	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	// test branch with mods
@@ -3963,7 +4292,7 @@ func TestController_MergeDirtyBranch(t *testing.T) {
 
 	// setup env
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 	err = deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"})
 	testutil.Must(t, err)
@@ -3971,7 +4300,7 @@ func TestController_MergeDirtyBranch(t *testing.T) {
 	testutil.Must(t, err)
 	err = deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum2"})
 	testutil.Must(t, err)
-	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	// merge branch1 to main (dirty)
@@ -3982,15 +4311,170 @@ func TestController_MergeDirtyBranch(t *testing.T) {
 	}
 }
 
+func TestController_ListMergeConflicts(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	// setup env - change foo/bar1 on both branches and delete foo/bar2 on one of them
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	now := time.Now()
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: now, Size: 1, Checksum: "cksum1"}))
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: now, Size: 1, Checksum: "cksum2"}))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "base", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr-source", CreationDate: now.Add(time.Minute), Size: 2, Checksum: "cksum1-source"}))
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr-source", CreationDate: now.Add(time.Minute), Size: 2, Checksum: "cksum2-source"}))
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "source changes", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr-dest", CreationDate: now, Size: 3, Checksum: "cksum1-dest"}))
+	testutil.Must(t, deps.catalog.DeleteEntry(ctx, repo, "main", "foo/bar2"))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "destination changes", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+
+	t.Run("all", func(t *testing.T) {
+		resp, err := clt.ListMergeConflictsWithResponse(ctx, repo, "branch1", "main", &apigen.ListMergeConflictsParams{})
+		verifyResponseOK(t, resp, err)
+		results := resp.JSON200.Results
+		if len(results) != 2 {
+			t.Fatalf("ListMergeConflicts got %d results, expected 2: %+v", len(results), results)
+		}
+		if results[0].Path != "foo/bar1" || results[0].Base.Checksum != "cksum1" || results[0].Source.Checksum != "cksum1-source" || results[0].Destination.Checksum != "cksum1-dest" {
+			t.Errorf("ListMergeConflicts unexpected conflict %+v", results[0])
+		}
+		if results[1].Path != "foo/bar2" || results[1].Source.Checksum != "cksum2-source" || results[1].Destination != nil {
+			t.Errorf("ListMergeConflicts unexpected conflict %+v", results[1])
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		resp, err := clt.ListMergeConflictsWithResponse(ctx, repo, "branch1", "main", &apigen.ListMergeConflictsParams{
+			Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+		})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Results) != 1 || !resp.JSON200.Pagination.HasMore || resp.JSON200.Pagination.NextOffset != "foo/bar1" {
+			t.Fatalf("ListMergeConflicts unexpected first page %+v", resp.JSON200)
+		}
+		resp, err = clt.ListMergeConflictsWithResponse(ctx, repo, "branch1", "main", &apigen.ListMergeConflictsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter("foo/bar1")),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+		})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Results) != 1 || resp.JSON200.Pagination.HasMore || resp.JSON200.Results[0].Path != "foo/bar2" {
+			t.Fatalf("ListMergeConflicts unexpected second page %+v", resp.JSON200)
+		}
+	})
+
+	t.Run("strategy", func(t *testing.T) {
+		resp, err := clt.ListMergeConflictsWithResponse(ctx, repo, "branch1", "main", &apigen.ListMergeConflictsParams{
+			Strategy: apiutil.Ptr(catalog.MergeStrategyNewestWinsStr),
+		})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Results) != 1 || resp.JSON200.Results[0].Path != "foo/bar2" {
+			t.Fatalf("ListMergeConflicts with newest-wins expected only foo/bar2, got %+v", resp.JSON200.Results)
+		}
+
+		resp, err = clt.ListMergeConflictsWithResponse(ctx, repo, "branch1", "main", &apigen.ListMergeConflictsParams{
+			Strategy: apiutil.Ptr("source-wins"),
+		})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Results) != 0 {
+			t.Fatalf("ListMergeConflicts with source-wins expected no conflicts, got %+v", resp.JSON200.Results)
+		}
+	})
+}
+
+func TestController_MergeResolverRules(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.MustDo(t, "create repository", err)
+
+	upload := func(branch string, objects map[string]string) {
+		t.Helper()
+		for path, data := range objects {
+			resp, err := uploadObjectHelper(t, ctx, clt, path, strings.NewReader(data), repo, branch)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusCreated, resp.StatusCode(), string(resp.Body))
+		}
+		_, err := deps.catalog.Commit(ctx, repo, branch, "changes", DefaultUserID, nil, nil, nil, false, nil, nil)
+		require.NoError(t, err)
+	}
+	// the script picks the larger version of an object
+	upload("main", map[string]string{
+		"scripts/resolve.lua": `if source ~= nil and dest ~= nil and source.size > dest.size then return "source" end
+return "dest"`,
+		"a/1": "base",
+		"b/1": "base",
+		"c/1": "base",
+	})
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	require.NoError(t, err)
+	upload("branch1", map[string]string{"a/1": "source larger", "b/1": "source", "c/1": "source"})
+	upload("main", map[string]string{"a/1": "dest", "b/1": "dest", "c/1": "dest"})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, rule := range []apigen.MergeResolverRule{
+			{Path: "a/", Strategy: "lua-hook"},
+			{Path: "a/", Strategy: "source-wins", ScriptPath: apiutil.Ptr("scripts/resolve.lua")},
+			{Path: "a/", Strategy: "unknown-wins"},
+		} {
+			resp, err := clt.SetMergeResolverRulesWithResponse(ctx, repo, apigen.SetMergeResolverRulesJSONRequestBody{Rules: []apigen.MergeResolverRule{rule}})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		}
+	})
+
+	rules := apigen.MergeResolverRules{
+		Rules: []apigen.MergeResolverRule{
+			{Path: "a/", Strategy: "lua-hook", ScriptPath: apiutil.Ptr("scripts/resolve.lua")},
+			{Path: "b/", Strategy: "source-wins"},
+		},
+	}
+	setResp, err := clt.SetMergeResolverRulesWithResponse(ctx, repo, apigen.SetMergeResolverRulesJSONRequestBody(rules))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+	getResp, err := clt.GetMergeResolverRulesWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, getResp.StatusCode())
+	require.Equal(t, rules.Rules, getResp.JSON200.Rules)
+
+	conflictsResp, err := clt.ListMergeConflictsWithResponse(ctx, repo, "branch1", "main", &apigen.ListMergeConflictsParams{})
+	verifyResponseOK(t, conflictsResp, err)
+	require.Len(t, conflictsResp.JSON200.Results, 1)
+	require.Equal(t, "c/1", conflictsResp.JSON200.Results[0].Path)
+
+	// conflicts no rule matches fail the merge without a strategy
+	mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "main", apigen.MergeIntoBranchJSONRequestBody{})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, mergeResp.StatusCode())
+
+	mergeResp, err = clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "main", apigen.MergeIntoBranchJSONRequestBody{
+		Strategy: apiutil.Ptr("dest-wins"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, mergeResp.StatusCode(), string(mergeResp.Body))
+	for path, expected := range map[string]string{"a/1": "source larger", "b/1": "source", "c/1": "dest"} {
+		objResp, err := clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: path})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, objResp.StatusCode())
+		require.Equal(t, expected, string(objResp.Body), path)
+	}
+}
+
 func TestController_CreateTag(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 	// setup env
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 	testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
-	commit1, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false)
+	commit1, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	t.Run("ref", func(t *testing.T) {
@@ -4097,10 +4581,10 @@ func TestController_CreateTag(t *testing.T) {
 
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, onBlock(deps, readOnlyRepo), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepo, "", onBlock(deps, readOnlyRepo), "main", true)
 		testutil.Must(t, err)
 		testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, readOnlyRepo, "main", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}, graveler.WithForce(true)))
-		commit1, err := deps.catalog.Commit(ctx, readOnlyRepo, "main", "some message", DefaultUserID, nil, nil, nil, false, graveler.WithForce(true))
+		commit1, err := deps.catalog.Commit(ctx, readOnlyRepo, "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil, graveler.WithForce(true))
 		testutil.Must(t, err)
 		tagResp, err := clt.CreateTagWithResponse(ctx, readOnlyRepo, apigen.CreateTagJSONRequestBody{
 			Id:  "tag1",
@@ -4128,10 +4612,10 @@ func TestController_Revert(t *testing.T) {
 	ctx := context.Background()
 	// setup env
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 	testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
-	_, err = deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	t.Run("ref", func(t *testing.T) {
@@ -4177,21 +4661,21 @@ func TestController_Revert(t *testing.T) {
 	t.Run("revert_no_parent", func(t *testing.T) {
 		repo := testUniqueRepoName()
 		// setup data - repo with one object committed
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		err = deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "merge/foo/bar1", PhysicalAddress: "merge1bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"})
 		testutil.Must(t, err)
-		_, err = deps.catalog.Commit(ctx, repo, "main", "first", DefaultUserID, nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, repo, "main", "first", DefaultUserID, nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 		// create branch with one entry committed
 		_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
 		testutil.Must(t, err)
 		err = deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "merge/foo/bar2", PhysicalAddress: "merge2bar2addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum2"})
 		testutil.Must(t, err)
-		_, err = deps.catalog.Commit(ctx, repo, "branch1", "second", DefaultUserID, nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, repo, "branch1", "second", DefaultUserID, nil, nil, nil, false, nil, nil)
 		testutil.Must(t, err)
 		// merge branch1 to main
-		mergeRef, err := deps.catalog.Merge(ctx, repo, "main", "branch1", DefaultUserID, "merge to main", catalog.Metadata{}, "")
+		mergeRef, err := deps.catalog.Merge(ctx, repo, "main", "branch1", DefaultUserID, "merge to main", catalog.Metadata{}, "", false)
 		testutil.Must(t, err)
 
 		// revert changes should fail
@@ -4204,10 +4688,10 @@ func TestController_Revert(t *testing.T) {
 
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepository := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepository, onBlock(deps, readOnlyRepository), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepository, "", onBlock(deps, readOnlyRepository), "main", true)
 		testutil.Must(t, err)
 		testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, readOnlyRepository, "main", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}, graveler.WithForce(true)))
-		_, err = deps.catalog.Commit(ctx, readOnlyRepository, "main", "some message", DefaultUserID, nil, nil, nil, false, graveler.WithForce(true))
+		_, err = deps.catalog.Commit(ctx, readOnlyRepository, "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil, graveler.WithForce(true))
 		testutil.Must(t, err)
 		revertResp, err := clt.RevertBranchWithResponse(ctx, readOnlyRepository, "main", apigen.RevertBranchJSONRequestBody{Ref: "main"})
 		testutil.Must(t, err)
@@ -4224,13 +4708,13 @@ func TestController_RevertConflict(t *testing.T) {
 	ctx := context.Background()
 	// setup env
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 	testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
-	firstCommit, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false)
+	firstCommit, err := deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 	testutil.MustDo(t, "overriding entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum2"}))
-	_, err = deps.catalog.Commit(ctx, repo, "main", "some other message", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "main", "some other message", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	resp, err := clt.RevertBranchWithResponse(ctx, repo, "main", apigen.RevertBranchJSONRequestBody{Ref: firstCommit.Reference})
@@ -4245,10 +4729,10 @@ func TestController_CherryPick(t *testing.T) {
 	ctx := context.Background()
 	// setup env
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 	testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
-	_, err = deps.catalog.Commit(ctx, repo, "main", "message1", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "main", "message1", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	for _, name := range []string{"branch1", "branch2", "branch3", "branch4", "dest-branch1", "dest-branch2", "dest-branch3", "dest-branch4"} {
@@ -4257,29 +4741,29 @@ func TestController_CherryPick(t *testing.T) {
 	}
 
 	testutil.MustDo(t, "create entry bar2", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 2, Checksum: "cksum2"}))
-	commit2, err := deps.catalog.Commit(ctx, repo, "branch1", "message2", DefaultUserID, nil, nil, nil, false)
+	commit2, err := deps.catalog.Commit(ctx, repo, "branch1", "message2", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	testutil.MustDo(t, "create entry bar3", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar3", PhysicalAddress: "bar3addr", CreationDate: time.Now(), Size: 3, Checksum: "cksum3"}))
 	testutil.MustDo(t, "create entry bar4", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar4", PhysicalAddress: "bar4addr", CreationDate: time.Now(), Size: 4, Checksum: "cksum4"}))
-	_, err = deps.catalog.Commit(ctx, repo, "branch1", "message34", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "message34", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	testutil.MustDo(t, "create entry bar6", deps.catalog.CreateEntry(ctx, repo, "branch2", catalog.DBEntry{Path: "foo/bar6", PhysicalAddress: "bar6addr", CreationDate: time.Now(), Size: 6, Checksum: "cksum6"}))
 	testutil.MustDo(t, "create entry bar7", deps.catalog.CreateEntry(ctx, repo, "branch2", catalog.DBEntry{Path: "foo/bar7", PhysicalAddress: "bar7addr", CreationDate: time.Now(), Size: 7, Checksum: "cksum7"}))
-	_, err = deps.catalog.Commit(ctx, repo, "branch2", "message34", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "branch2", "message34", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	testutil.MustDo(t, "create entry bar8", deps.catalog.CreateEntry(ctx, repo, "branch3", catalog.DBEntry{Path: "foo/bar8", PhysicalAddress: "bar8addr", CreationDate: time.Now(), Size: 8, Checksum: "cksum8"}))
-	_, err = deps.catalog.Commit(ctx, repo, "branch3", "message8", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "branch3", "message8", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	testutil.MustDo(t, "create entry bar2", deps.catalog.CreateEntry(ctx, repo, "branch4", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr4", CreationDate: time.Now(), Size: 24, Checksum: "cksum24"}))
-	_, err = deps.catalog.Commit(ctx, repo, "branch4", "message4", DefaultUserID, nil, nil, nil, false)
+	_, err = deps.catalog.Commit(ctx, repo, "branch4", "message4", DefaultUserID, nil, nil, nil, false, nil, nil)
 	testutil.Must(t, err)
 
 	_, err = deps.catalog.Merge(ctx, repo, "branch3", "branch1", DefaultUserID,
-		"merge message", catalog.Metadata{"foo": "bar"}, "")
+		"merge message", catalog.Metadata{"foo": "bar"}, "", false)
 	testutil.Must(t, err)
 
 	t.Run("from branch", func(t *testing.T) {
@@ -4403,14 +4887,14 @@ func TestController_CherryPick(t *testing.T) {
 
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepository := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepository, onBlock(deps, readOnlyRepository), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, readOnlyRepository, "", onBlock(deps, readOnlyRepository), "main", true)
 		testutil.Must(t, err)
 		for _, name := range []string{"branch1", "dest-branch1"} {
 			_, err = deps.catalog.CreateBranch(ctx, readOnlyRepository, name, "main", graveler.WithForce(true))
 			testutil.Must(t, err)
 		}
 		testutil.MustDo(t, "create entry bar2", deps.catalog.CreateEntry(ctx, readOnlyRepository, "branch1", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 2, Checksum: "cksum2"}, graveler.WithForce(true)))
-		_, err = deps.catalog.Commit(ctx, readOnlyRepository, "branch1", "message2", DefaultUserID, nil, nil, nil, false, graveler.WithForce(true))
+		_, err = deps.catalog.Commit(ctx, readOnlyRepository, "branch1", "message2", DefaultUserID, nil, nil, nil, false, nil, nil, graveler.WithForce(true))
 		testutil.Must(t, err)
 
 		cherryResponse, err := clt.CherryPickWithResponse(ctx, readOnlyRepository, "dest-branch1", apigen.CherryPickJSONRequestBody{Ref: "branch1"})
@@ -4423,29 +4907,342 @@ func TestController_CherryPick(t *testing.T) {
 	})
 }
 
-func TestController_UpdatePolicy(t *testing.T) {
-	clt, _ := setupClientWithAdmin(t)
+func TestController_RebaseBranch(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
-
-	// test policy
-	now := apiutil.Ptr(time.Now().Unix())
-	const existingPolicyID = "TestUpdatePolicy"
-	response, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
-		CreationDate: now,
-		Id:           existingPolicyID,
-		Statement: []apigen.Statement{
-			{
-				Action: []string{
-					"fs:Read*",
-					"fs:List*",
-				},
-				Effect:   "deny",
-				Resource: "*",
-			},
-		},
-	})
+	// setup env
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
-	if response.JSON201 == nil {
+	testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "message1", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+
+	for _, name := range []string{"feature", "conflict", "up-to-date"} {
+		_, err = deps.catalog.CreateBranch(ctx, repo, name, "main")
+		testutil.Must(t, err)
+	}
+
+	testutil.MustDo(t, "create entry bar2", deps.catalog.CreateEntry(ctx, repo, "feature", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 2, Checksum: "cksum2"}))
+	_, err = deps.catalog.Commit(ctx, repo, "feature", "message2", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+	testutil.MustDo(t, "create entry bar3", deps.catalog.CreateEntry(ctx, repo, "feature", catalog.DBEntry{Path: "foo/bar3", PhysicalAddress: "bar3addr", CreationDate: time.Now(), Size: 3, Checksum: "cksum3"}))
+	_, err = deps.catalog.Commit(ctx, repo, "feature", "message3", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+
+	testutil.MustDo(t, "create conflicting entry bar2", deps.catalog.CreateEntry(ctx, repo, "conflict", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2conflict", CreationDate: time.Now(), Size: 22, Checksum: "cksum22"}))
+	_, err = deps.catalog.Commit(ctx, repo, "conflict", "message22", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+
+	testutil.MustDo(t, "create entry bar4", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar4", PhysicalAddress: "bar4addr", CreationDate: time.Now(), Size: 4, Checksum: "cksum4"}))
+	mainCommit, err := deps.catalog.Commit(ctx, repo, "main", "message4", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+
+	t.Run("rebase", func(t *testing.T) {
+		resp, err := clt.RebaseBranchWithResponse(ctx, repo, "feature", apigen.RebaseBranchJSONRequestBody{Ref: "main"})
+		verifyResponseOK(t, resp, err)
+
+		for _, p := range []string{"foo/bar2", "foo/bar3", "foo/bar4"} {
+			respStat, err := clt.StatObjectWithResponse(ctx, repo, "feature", &apigen.StatObjectParams{Path: p})
+			verifyResponseOK(t, respStat, err)
+		}
+
+		logResp, err := clt.LogCommitsWithResponse(ctx, repo, "feature", &apigen.LogCommitsParams{Amount: apiutil.Ptr(apigen.PaginationAmount(3))})
+		verifyResponseOK(t, logResp, err)
+		messages := make([]string, 0, len(logResp.JSON200.Results))
+		for _, commit := range logResp.JSON200.Results {
+			messages = append(messages, commit.Message)
+		}
+		if diff := deep.Equal(messages, []string{"message3", "message2", "message4"}); diff != nil {
+			t.Fatal("rebased log not as expected:", diff)
+		}
+		if parents := logResp.JSON200.Results[1].Parents; len(parents) != 1 || parents[0] != mainCommit.Reference {
+			t.Fatalf("expected first replayed commit parent to be %s, got %v", mainCommit.Reference, parents)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		branchResp, err := clt.GetBranchWithResponse(ctx, repo, "feature")
+		verifyResponseOK(t, branchResp, err)
+		resp, err := clt.RebaseBranchWithResponse(ctx, repo, "feature", apigen.RebaseBranchJSONRequestBody{Ref: "main"})
+		verifyResponseOK(t, resp, err)
+		if resp.JSON201.Id != branchResp.JSON200.CommitId {
+			t.Fatalf("expected rebase of up to date branch to keep head %s, got %s", branchResp.JSON200.CommitId, resp.JSON201.Id)
+		}
+	})
+
+	t.Run("fast forward", func(t *testing.T) {
+		resp, err := clt.RebaseBranchWithResponse(ctx, repo, "up-to-date", apigen.RebaseBranchJSONRequestBody{Ref: "main"})
+		verifyResponseOK(t, resp, err)
+		if resp.JSON201.Id != mainCommit.Reference {
+			t.Fatalf("expected rebase to fast-forward to %s, got %s", mainCommit.Reference, resp.JSON201.Id)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		resp, err := clt.RebaseBranchWithResponse(ctx, repo, "conflict", apigen.RebaseBranchJSONRequestBody{Ref: "feature"})
+		testutil.Must(t, err)
+		if resp.JSON409 == nil {
+			t.Fatalf("expected to get a conflict, got %d", resp.StatusCode())
+		}
+	})
+
+	t.Run("dirty branch", func(t *testing.T) {
+		testutil.MustDo(t, "create entry bar5", deps.catalog.CreateEntry(ctx, repo, "conflict", catalog.DBEntry{Path: "foo/bar5", PhysicalAddress: "bar5addr", CreationDate: time.Now(), Size: 5, Checksum: "cksum5"}))
+		resp, err := clt.RebaseBranchWithResponse(ctx, repo, "conflict", apigen.RebaseBranchJSONRequestBody{Ref: "main"})
+		testutil.Must(t, err)
+		if resp.JSON400 == nil || resp.JSON400.Message != graveler.ErrDirtyBranch.Error() {
+			t.Errorf("Rebase dirty branch should fail with ErrDirtyBranch, got %+v", resp)
+		}
+	})
+
+	t.Run("protected branch", func(t *testing.T) {
+		_, err := deps.catalog.CreateBranch(ctx, repo, "protected", "feature")
+		testutil.Must(t, err)
+		err = deps.catalog.SetBranchProtectionRules(ctx, repo, &graveler.BranchProtectionRules{
+			BranchPatternToBlockedActions: map[string]*graveler.BranchProtectionBlockedActions{
+				"protected": {Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_COMMIT}},
+			},
+		}, nil)
+		testutil.MustDo(t, "protection rule", err)
+		testutil.MustDo(t, "create entry bar6", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar6", PhysicalAddress: "bar6addr", CreationDate: time.Now(), Size: 6, Checksum: "cksum6"}))
+		_, err = deps.catalog.Commit(ctx, repo, "main", "message6", DefaultUserID, nil, nil, nil, false, nil, nil)
+		testutil.Must(t, err)
+
+		resp, err := clt.RebaseBranchWithResponse(ctx, repo, "protected", apigen.RebaseBranchJSONRequestBody{Ref: "main"})
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusForbidden {
+			t.Fatalf("Rebase of protected branch should be forbidden (403), got %s", resp.Status())
+		}
+	})
+}
+
+func TestController_ExpectedCommitID(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	// setup env
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	testutil.MustDo(t, "create entry bar1", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
+	staleCommit, err := deps.catalog.Commit(ctx, repo, "branch1", "message1", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+	testutil.MustDo(t, "create entry bar2", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 2, Checksum: "cksum2"}))
+	headCommit, err := deps.catalog.Commit(ctx, repo, "branch1", "message2", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+	mainResp, err := clt.GetBranchWithResponse(ctx, repo, "main")
+	verifyResponseOK(t, mainResp, err)
+	mainHead := mainResp.JSON200.CommitId
+
+	t.Run("commit", func(t *testing.T) {
+		testutil.MustDo(t, "create entry bar3", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar3", PhysicalAddress: "bar3addr", CreationDate: time.Now(), Size: 3, Checksum: "cksum3"}))
+		resp, err := clt.CommitWithResponse(ctx, repo, "branch1", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:          "stale commit",
+			ExpectedCommitId: apiutil.Ptr(staleCommit.Reference),
+		})
+		testutil.Must(t, err)
+		if resp.JSON412 == nil {
+			t.Fatalf("expected precondition failed, got %d", resp.StatusCode())
+		}
+		resp, err = clt.CommitWithResponse(ctx, repo, "branch1", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:          "commit",
+			ExpectedCommitId: apiutil.Ptr(headCommit.Reference),
+		})
+		verifyResponseOK(t, resp, err)
+		headCommit.Reference = resp.JSON201.Id
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		resp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "main", apigen.MergeIntoBranchJSONRequestBody{
+			ExpectedCommitId: apiutil.Ptr(headCommit.Reference),
+		})
+		testutil.Must(t, err)
+		if resp.JSON412 == nil {
+			t.Fatalf("expected precondition failed, got %d", resp.StatusCode())
+		}
+		resp, err = clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "main", apigen.MergeIntoBranchJSONRequestBody{
+			ExpectedCommitId: apiutil.Ptr(mainHead),
+		})
+		verifyResponseOK(t, resp, err)
+	})
+
+	t.Run("hard reset", func(t *testing.T) {
+		resp, err := clt.HardResetBranchWithResponse(ctx, repo, "branch1", &apigen.HardResetBranchParams{
+			Ref:              staleCommit.Reference,
+			ExpectedCommitId: apiutil.Ptr(staleCommit.Reference),
+		})
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusPreconditionFailed {
+			t.Fatalf("expected precondition failed, got %d", resp.StatusCode())
+		}
+		resp, err = clt.HardResetBranchWithResponse(ctx, repo, "branch1", &apigen.HardResetBranchParams{
+			Ref:              staleCommit.Reference,
+			ExpectedCommitId: apiutil.Ptr(headCommit.Reference),
+		})
+		verifyResponseOK(t, resp, err)
+		branchResp, err := clt.GetBranchWithResponse(ctx, repo, "branch1")
+		verifyResponseOK(t, branchResp, err)
+		if branchResp.JSON200.CommitId != staleCommit.Reference {
+			t.Fatalf("expected branch head %s after hard reset, got %s", staleCommit.Reference, branchResp.JSON200.CommitId)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		testutil.MustDo(t, "create entry bar4", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar4", PhysicalAddress: "bar4addr", CreationDate: time.Now(), Size: 4, Checksum: "cksum4"}))
+		resp, err := clt.ResetBranchWithResponse(ctx, repo, "branch1", apigen.ResetBranchJSONRequestBody{
+			Type:             "reset",
+			ExpectedCommitId: apiutil.Ptr(headCommit.Reference),
+		})
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusPreconditionFailed {
+			t.Fatalf("expected precondition failed, got %d", resp.StatusCode())
+		}
+		resp, err = clt.ResetBranchWithResponse(ctx, repo, "branch1", apigen.ResetBranchJSONRequestBody{
+			Type:             "reset",
+			ExpectedCommitId: apiutil.Ptr(staleCommit.Reference),
+		})
+		verifyResponseOK(t, resp, err)
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "branch1", &apigen.StatObjectParams{Path: "foo/bar4"})
+		testutil.Must(t, err)
+		if statResp.JSON404 == nil {
+			t.Fatalf("expected foo/bar4 to be reset, got %d", statResp.StatusCode())
+		}
+	})
+}
+
+func TestController_ApplyTransaction(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	// setup env
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	for _, name := range []string{"raw", "curated", "features"} {
+		_, err = deps.catalog.CreateBranch(ctx, repo, name, "main")
+		testutil.Must(t, err)
+	}
+	getHead := func(t *testing.T, branch string) string {
+		t.Helper()
+		resp, err := clt.GetBranchWithResponse(ctx, repo, branch)
+		verifyResponseOK(t, resp, err)
+		return resp.JSON200.CommitId
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		testutil.MustDo(t, "create entry raw", deps.catalog.CreateEntry(ctx, repo, "raw", catalog.DBEntry{Path: "raw/1", PhysicalAddress: "raw1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
+		testutil.MustDo(t, "create entry curated", deps.catalog.CreateEntry(ctx, repo, "curated", catalog.DBEntry{Path: "curated/1", PhysicalAddress: "curated1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum2"}))
+		rawHead := getHead(t, "raw")
+
+		resp, err := clt.ApplyTransactionWithResponse(ctx, repo, apigen.ApplyTransactionJSONRequestBody{
+			Preconditions: &[]apigen.TransactionPrecondition{{Branch: "raw", CommitId: rawHead}},
+			Operations: []apigen.TransactionOperation{
+				{Type: "commit", Branch: "raw", Message: apiutil.Ptr("raw data")},
+				{Type: "commit", Branch: "curated", Message: apiutil.Ptr("curated data")},
+				{Type: "merge", Branch: "features", Ref: apiutil.Ptr("raw")},
+			},
+		})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Branches) != 3 {
+			t.Fatalf("expected 3 changed branches, got %+v", resp.JSON200.Branches)
+		}
+		for _, b := range resp.JSON200.Branches {
+			if head := getHead(t, b.Branch); head != b.CommitId {
+				t.Errorf("branch %s head %s, expected %s", b.Branch, head, b.CommitId)
+			}
+		}
+		// merge source refers to the raw branch commit created by the transaction
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "features", &apigen.StatObjectParams{Path: "raw/1"})
+		verifyResponseOK(t, statResp, err)
+		diffResp, err := clt.DiffBranchWithResponse(ctx, repo, "curated", &apigen.DiffBranchParams{})
+		verifyResponseOK(t, diffResp, err)
+		if len(diffResp.JSON200.Results) != 0 {
+			t.Errorf("expected no uncommitted changes on curated, got %+v", diffResp.JSON200.Results)
+		}
+	})
+
+	t.Run("precondition failed", func(t *testing.T) {
+		testutil.MustDo(t, "create entry raw", deps.catalog.CreateEntry(ctx, repo, "raw", catalog.DBEntry{Path: "raw/2", PhysicalAddress: "raw2addr", CreationDate: time.Now(), Size: 2, Checksum: "cksum3"}))
+		rawHead := getHead(t, "raw")
+		resp, err := clt.ApplyTransactionWithResponse(ctx, repo, apigen.ApplyTransactionJSONRequestBody{
+			Preconditions: &[]apigen.TransactionPrecondition{{Branch: "curated", CommitId: rawHead}},
+			Operations: []apigen.TransactionOperation{
+				{Type: "commit", Branch: "raw", Message: apiutil.Ptr("raw data")},
+			},
+		})
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusPreconditionFailed {
+			t.Fatalf("expected status %d, got %d", http.StatusPreconditionFailed, resp.StatusCode())
+		}
+		if head := getHead(t, "raw"); head != rawHead {
+			t.Errorf("raw branch moved to %s on failed transaction", head)
+		}
+	})
+
+	t.Run("all or nothing", func(t *testing.T) {
+		testutil.MustDo(t, "create entry main", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "raw/1", PhysicalAddress: "main1addr", CreationDate: time.Now(), Size: 3, Checksum: "cksum4"}))
+		_, err := deps.catalog.Commit(ctx, repo, "main", "conflicting change", DefaultUserID, nil, nil, nil, false, nil, nil)
+		testutil.Must(t, err)
+		rawHead := getHead(t, "raw")
+		curatedHead := getHead(t, "curated")
+
+		resp, err := clt.ApplyTransactionWithResponse(ctx, repo, apigen.ApplyTransactionJSONRequestBody{
+			Operations: []apigen.TransactionOperation{
+				{Type: "commit", Branch: "raw", Message: apiutil.Ptr("raw data")},
+				{Type: "reset", Branch: "curated", Ref: apiutil.Ptr("raw")},
+				{Type: "merge", Branch: "features", Ref: apiutil.Ptr("main")},
+			},
+		})
+		testutil.Must(t, err)
+		if resp.JSON409 == nil {
+			t.Fatalf("expected conflict, got %d", resp.StatusCode())
+		}
+		if head := getHead(t, "raw"); head != rawHead {
+			t.Errorf("raw branch moved to %s on failed transaction", head)
+		}
+		if head := getHead(t, "curated"); head != curatedHead {
+			t.Errorf("curated branch moved to %s on failed transaction", head)
+		}
+		// uncommitted changes are kept
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "raw", &apigen.StatObjectParams{Path: "raw/2"})
+		verifyResponseOK(t, statResp, err)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		resp, err := clt.ApplyTransactionWithResponse(ctx, repo, apigen.ApplyTransactionJSONRequestBody{
+			Operations: []apigen.TransactionOperation{},
+		})
+		testutil.Must(t, err)
+		if resp.JSON400 == nil {
+			t.Fatalf("expected bad request, got %d", resp.StatusCode())
+		}
+	})
+}
+
+func TestController_UpdatePolicy(t *testing.T) {
+	clt, _ := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	// test policy
+	now := apiutil.Ptr(time.Now().Unix())
+	const existingPolicyID = "TestUpdatePolicy"
+	response, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+		CreationDate: now,
+		Id:           existingPolicyID,
+		Statement: []apigen.Statement{
+			{
+				Action: []string{
+					"fs:Read*",
+					"fs:List*",
+				},
+				Effect:   "deny",
+				Resource: "*",
+			},
+		},
+	})
+	testutil.Must(t, err)
+	if response.JSON201 == nil {
 		t.Fatal("Failed to create test policy", response.Status())
 	}
 
@@ -4523,7 +5320,7 @@ func TestController_GetPhysicalAddress(t *testing.T) {
 			ns     = "s3://foo-bucket1"
 			branch = "main"
 		)
-		_, err := deps.catalog.CreateRepository(ctx, repo, ns, branch, false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", ns, branch, false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -4634,7 +5431,7 @@ func TestController_PrepareGarbageCollectionUncommitted(t *testing.T) {
 
 	t.Run("uncommitted_data", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		const items = 3
 		for i := 0; i < items; i++ {
@@ -4647,7 +5444,7 @@ func TestController_PrepareGarbageCollectionUncommitted(t *testing.T) {
 
 	t.Run("committed_data", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		const items = 3
 		for i := 0; i < items; i++ {
@@ -4655,7 +5452,7 @@ func TestController_PrepareGarbageCollectionUncommitted(t *testing.T) {
 			uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(objPath), repo, "main")
 			verifyResponseOK(t, uploadResp, err)
 		}
-		if _, err := deps.catalog.Commit(ctx, repo, "main", "committed objects", "some_user", nil, nil, nil, false); err != nil {
+		if _, err := deps.catalog.Commit(ctx, repo, "main", "committed objects", "some_user", nil, nil, nil, false, nil, nil); err != nil {
 			t.Fatalf("failed to commit objects: %s", err)
 		}
 		verifyPrepareGarbageCollection(t, repo, 1, false)
@@ -4663,7 +5460,7 @@ func TestController_PrepareGarbageCollectionUncommitted(t *testing.T) {
 
 	t.Run("uncommitted_copy", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		const items = 3
 		for i := 0; i < items; i++ {
@@ -4683,7 +5480,7 @@ func TestController_PrepareGarbageCollectionUncommitted(t *testing.T) {
 
 	t.Run("read_only_repo", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", true)
 		testutil.Must(t, err)
 		resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, repo, apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{})
 		if err != nil {
@@ -4701,7 +5498,7 @@ func TestController_PrepareGarbageCollectionCommitted(t *testing.T) {
 
 	t.Run("read_only_repo", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", true)
 		testutil.Must(t, err)
 		resp, err := clt.PrepareGarbageCollectionCommitsWithResponse(ctx, repo)
 		if err != nil {
@@ -4724,7 +5521,7 @@ func TestController_ClientDisconnect(t *testing.T) {
 	// setup repository
 	ctx := context.Background()
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 
 	// prepare a client that will not wait for a response and timeout
@@ -5004,7 +5801,7 @@ func TestController_CopyObjectHandler(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "bucket/prefix"), "main", false)
 	require.NoError(t, err)
 	_, err = deps.catalog.CreateBranch(ctx, repo, "alt", "main")
 	require.NoError(t, err)
@@ -5149,9 +5946,58 @@ func TestController_CopyObjectHandler(t *testing.T) {
 		require.NotNil(t, resp.JSON400)
 	})
 
+	otherRepo := testUniqueRepoName()
+	_, err = deps.catalog.CreateRepository(ctx, otherRepo, "", onBlock(deps, otherRepo), "main", false)
+	require.NoError(t, err)
+
+	t.Run("cross_repository", func(t *testing.T) {
+		const (
+			srcPath  = "foo/bar-cross"
+			destPath = "foo/bar-full-from-repository"
+		)
+		objStat := uploadContent(t, repo, "main", srcPath)
+		copyResp, err := clt.CopyObjectWithResponse(ctx, otherRepo, "main", &apigen.CopyObjectParams{
+			DestPath: destPath,
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath:       srcPath,
+			SrcRef:        apiutil.Ptr("main"),
+			SrcRepository: apiutil.Ptr(repo),
+		})
+		verifyResponseOK(t, copyResp, err)
+
+		copyStat := copyResp.JSON201
+		require.NotNil(t, copyStat)
+		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
+		require.Equal(t, objStat.Checksum, copyStat.Checksum)
+		require.Equal(t, destPath, copyStat.Path)
+	})
+
+	t.Run("shallow_cross_repository", func(t *testing.T) {
+		const (
+			srcPath  = "foo/bar-shallow-cross"
+			destPath = "foo/bar-shallow-from-repository"
+		)
+		objStat := uploadContent(t, repo, "main", srcPath)
+		copyResp, err := clt.CopyObjectWithResponse(ctx, otherRepo, "main", &apigen.CopyObjectParams{
+			DestPath: destPath,
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath:       srcPath,
+			SrcRef:        apiutil.Ptr("main"),
+			SrcRepository: apiutil.Ptr(repo),
+			Shallow:       apiutil.Ptr(true),
+		})
+		verifyResponseOK(t, copyResp, err)
+
+		// the copy references the object of the source repository
+		copyStat := copyResp.JSON201
+		require.NotNil(t, copyStat)
+		require.Equal(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
+		require.Equal(t, destPath, copyStat.Path)
+	})
+
 	t.Run("read-only repository", func(t *testing.T) {
 		readOnlyRepository := testUniqueRepoName()
-		_, err = deps.catalog.CreateRepository(ctx, readOnlyRepository, onBlock(deps, "bucket/prefix"), "main", true)
+		_, err = deps.catalog.CreateRepository(ctx, readOnlyRepository, "", onBlock(deps, "bucket/prefix"), "main", true)
 		require.NoError(t, err)
 		_, err = deps.catalog.CreateBranch(ctx, readOnlyRepository, "alt", "main", graveler.WithForce(true))
 		require.NoError(t, err)
@@ -5217,6 +6063,196 @@ func TestController_CopyObjectHandler(t *testing.T) {
 	})
 }
 
+func TestController_CopyPrefix(t *testing.T) {
+	// garbage collection should notice the deletion of the destination repository right away
+	const repositoryCacheSizeKey = "graveler.repository_cache.size"
+	repositoryCacheSize := viper.Get(repositoryCacheSizeKey)
+	viper.Set(repositoryCacheSizeKey, 0)
+	t.Cleanup(func() { viper.Set(repositoryCacheSizeKey, repositoryCacheSize) })
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	srcRepo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, srcRepo, "", onBlock(deps, srcRepo), "main", false)
+	require.NoError(t, err)
+	destRepo := testUniqueRepoName()
+	_, err = deps.catalog.CreateRepository(ctx, destRepo, "", onBlock(deps, destRepo), "main", false)
+	require.NoError(t, err)
+
+	var totalBytes int64
+	for _, objPath := range []string{"data/a", "data/b", "other/c"} {
+		content := "content of " + objPath
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(content), srcRepo, "main")
+		verifyResponseOK(t, uploadResp, err)
+		if strings.HasPrefix(objPath, "data/") {
+			totalBytes += int64(len(content))
+		}
+	}
+	commitResp, err := clt.CommitWithResponse(ctx, srcRepo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: "data"})
+	verifyResponseOK(t, commitResp, err)
+
+	prepareGCLocation := func(t *testing.T) string {
+		t.Helper()
+		resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, srcRepo, apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{})
+		verifyResponseOK(t, resp, err)
+		require.NotNil(t, resp.JSON201)
+		return resp.JSON201.GcUncommittedLocation
+	}
+	// all objects are committed
+	require.Empty(t, prepareGCLocation(t))
+
+	t.Run("source_not_found", func(t *testing.T) {
+		resp, err := clt.CopyPrefixStartWithResponse(ctx, destRepo, "main", apigen.CopyPrefixStartJSONRequestBody{
+			SrcRepository: apiutil.Ptr("no-such-repository"),
+			SrcRef:        apiutil.Ptr("main"),
+			SrcPrefix:     "data/",
+			DestPrefix:    "copy/",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.JSON404)
+	})
+
+	t.Run("source_ref_missing", func(t *testing.T) {
+		resp, err := clt.CopyPrefixStartWithResponse(ctx, destRepo, "main", apigen.CopyPrefixStartJSONRequestBody{
+			SrcRepository: apiutil.Ptr(srcRepo),
+			SrcPrefix:     "data/",
+			DestPrefix:    "copy/",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.JSON400)
+	})
+
+	t.Run("shallow", func(t *testing.T) {
+		startResp, err := clt.CopyPrefixStartWithResponse(ctx, destRepo, "main", apigen.CopyPrefixStartJSONRequestBody{
+			SrcRepository: apiutil.Ptr(srcRepo),
+			SrcRef:        apiutil.Ptr("main"),
+			SrcPrefix:     "data/",
+			DestPrefix:    "copy/",
+			Shallow:       apiutil.Ptr(true),
+		})
+		verifyResponseOK(t, startResp, err)
+		require.NotNil(t, startResp.JSON202)
+
+		var status *apigen.PrefixCopyStatus
+		require.Eventually(t, func() bool {
+			statusResp, err := clt.CopyPrefixStatusWithResponse(ctx, destRepo, "main", &apigen.CopyPrefixStatusParams{Id: startResp.JSON202.Id})
+			verifyResponseOK(t, statusResp, err)
+			status = statusResp.JSON200
+			return status.Done
+		}, 5*time.Second, 50*time.Millisecond)
+		require.Nil(t, status.Error)
+		require.Equal(t, int64(2), status.TotalObjects)
+		require.Equal(t, int64(2), status.CopiedObjects)
+		require.Equal(t, totalBytes, status.CopiedBytes)
+		require.Equal(t, "data/b", apiutil.Value(status.LastPath))
+
+		for _, name := range []string{"a", "b"} {
+			srcStat, err := clt.StatObjectWithResponse(ctx, srcRepo, "main", &apigen.StatObjectParams{Path: "data/" + name})
+			verifyResponseOK(t, srcStat, err)
+			destStat, err := clt.StatObjectWithResponse(ctx, destRepo, "main", &apigen.StatObjectParams{Path: "copy/" + name})
+			verifyResponseOK(t, destStat, err)
+			require.Equal(t, srcStat.JSON200.PhysicalAddress, destStat.JSON200.PhysicalAddress)
+		}
+		statResp, err := clt.StatObjectWithResponse(ctx, destRepo, "main", &apigen.StatObjectParams{Path: "copy/c"})
+		require.NoError(t, err)
+		require.NotNil(t, statResp.JSON404)
+
+		countShared := func(t *testing.T) int {
+			t.Helper()
+			it, err := deps.catalog.KVStore.Scan(ctx, []byte("shared-addresses"), kv.ScanOptions{})
+			require.NoError(t, err)
+			defer it.Close()
+			count := 0
+			for it.Next() {
+				count++
+			}
+			require.NoError(t, it.Err())
+			return count
+		}
+		require.Equal(t, 2, countShared(t))
+
+		// a shared object no branch references is released after the retention of the destination
+		require.NoError(t, deps.catalog.SetGarbageCollectionRules(ctx, destRepo, &graveler.GarbageCollectionRules{DefaultRetentionDays: 1}))
+		deleteResp, err := clt.DeleteObjectWithResponse(ctx, destRepo, "main", &apigen.DeleteObjectParams{Path: "copy/a"})
+		verifyResponseOK(t, deleteResp, err)
+		require.NotEmpty(t, prepareGCLocation(t))
+		require.Equal(t, 2, countShared(t))
+		// pass the retention since the object was found unreferenced
+		it, err := kv.NewPrimaryIterator(ctx, deps.catalog.KVStore, (&catalog.SharedAddressData{}).ProtoReflect().Type(), "shared-addresses", nil, kv.IteratorOptionsFrom(nil))
+		require.NoError(t, err)
+		for it.Next() {
+			data := it.Entry().Value.(*catalog.SharedAddressData)
+			if data.UnreferencedSince != nil {
+				data.UnreferencedSince = timestamppb.New(time.Now().Add(-48 * time.Hour))
+				require.NoError(t, kv.SetMsg(ctx, deps.catalog.KVStore, "shared-addresses", it.Entry().Key, data))
+			}
+		}
+		require.NoError(t, it.Err())
+		it.Close()
+		require.NotEmpty(t, prepareGCLocation(t))
+		require.Equal(t, 1, countShared(t))
+
+		// the shared objects are kept by garbage collection of the source repository, until the destination is deleted
+		require.NotEmpty(t, prepareGCLocation(t))
+		require.NoError(t, deps.catalog.DeleteRepository(ctx, destRepo))
+		require.Empty(t, prepareGCLocation(t))
+	})
+}
+
+func TestController_CopyPrefixDeniedObject(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	require.NoError(t, err)
+	for _, objPath := range []string{"data/a", "data/b"} {
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader("content of "+objPath), repo, "main")
+		verifyResponseOK(t, uploadResp, err)
+	}
+
+	// reading the prefix is allowed, reading one of the objects under it is denied
+	const policyID = "DenyReadDataB"
+	policyResp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+		Id: policyID,
+		Statement: []apigen.Statement{
+			{
+				Action:   []string{"fs:ReadObject"},
+				Effect:   "deny",
+				Resource: "arn:lakefs:fs:::repository/" + repo + "/object/data/b",
+			},
+		},
+	})
+	verifyResponseOK(t, policyResp, err)
+	attachResp, err := clt.AttachPolicyToUserWithResponse(ctx, "admin", policyID)
+	verifyResponseOK(t, attachResp, err)
+
+	startResp, err := clt.CopyPrefixStartWithResponse(ctx, repo, "main", apigen.CopyPrefixStartJSONRequestBody{
+		SrcPrefix:  "data/",
+		DestPrefix: "copy/",
+	})
+	verifyResponseOK(t, startResp, err)
+	require.NotNil(t, startResp.JSON202)
+
+	var status *apigen.PrefixCopyStatus
+	require.Eventually(t, func() bool {
+		statusResp, err := clt.CopyPrefixStatusWithResponse(ctx, repo, "main", &apigen.CopyPrefixStatusParams{Id: startResp.JSON202.Id})
+		verifyResponseOK(t, statusResp, err)
+		status = statusResp.JSON200
+		return status.Done
+	}, 5*time.Second, 50*time.Millisecond)
+	require.NotNil(t, status.Error)
+	require.Contains(t, *status.Error, "data/b")
+	require.Zero(t, status.CopiedObjects)
+
+	// the batch of the denied object is not copied
+	for _, name := range []string{"a", "b"} {
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "copy/" + name})
+		require.NoError(t, err)
+		require.NotNil(t, statResp.JSON404, "copy/%s should not exist", name)
+	}
+}
+
 func TestController_LocalAdapter_StageObject(t *testing.T) {
 	p := t.TempDir()
 	forbiddenPath := "local:///not_allowed"
@@ -5226,7 +6262,7 @@ func TestController_LocalAdapter_StageObject(t *testing.T) {
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, "bucket/prefix"), "main", false)
 	require.NoError(t, err)
 	_, err = deps.catalog.CreateBranch(ctx, repo, "alt", "main")
 	require.NoError(t, err)
@@ -5269,7 +6305,7 @@ func TestController_BranchProtectionRules(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			currCtx := context.Background()
 			repo := testUniqueRepoName()
-			_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", false)
+			_, err := deps.catalog.CreateRepository(currCtx, repo, "", onBlock(deps, repo), "main", false)
 			testutil.MustDo(t, "create repository", err)
 
 			respPreflight, err := tc.clt.CreateBranchProtectionRulePreflightWithResponse(currCtx, repo)
@@ -5302,7 +6338,7 @@ func TestController_BranchProtectionRules(t *testing.T) {
 	t.Run("read-only repo", func(t *testing.T) {
 		currCtx := context.Background()
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", true)
+		_, err := deps.catalog.CreateRepository(currCtx, repo, "", onBlock(deps, repo), "main", true)
 		testutil.MustDo(t, "create repository", err)
 
 		resp, err := adminClt.SetBranchProtectionRulesWithResponse(currCtx, repo, &apigen.SetBranchProtectionRulesParams{}, apigen.SetBranchProtectionRulesJSONRequestBody{})
@@ -5318,6 +6354,371 @@ func TestController_BranchProtectionRules(t *testing.T) {
 	})
 }
 
+func TestController_CompressionRules(t *testing.T) {
+	viper.Set("blockstore.compression.enabled", true)
+	t.Cleanup(func() { viper.Set("blockstore.compression.enabled", false) })
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.MustDo(t, "create repository", err)
+
+	getResp, err := clt.GetCompressionRulesWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, getResp.StatusCode())
+	require.Empty(t, getResp.JSON200.Rules)
+
+	rules := apigen.CompressionRules{
+		Rules: []apigen.CompressionRule{
+			{ContentTypes: &[]string{"text/*"}, Codec: "zstd"},
+			{Extensions: &[]string{".log", "csv"}, Codec: "gzip"},
+		},
+	}
+	setResp, err := clt.SetCompressionRulesWithResponse(ctx, repo, apigen.SetCompressionRulesJSONRequestBody(rules))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+
+	getResp, err = clt.GetCompressionRulesWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, getResp.StatusCode())
+	require.Equal(t, rules.Rules, getResp.JSON200.Rules)
+
+	codec, err := deps.catalog.CompressionCodec(ctx, repo, "data/file.CSV", "application/octet-stream")
+	require.NoError(t, err)
+	require.Equal(t, "gzip", codec)
+	codec, err = deps.catalog.CompressionCodec(ctx, repo, "data/file.log", "text/plain; charset=utf-8")
+	require.NoError(t, err)
+	require.Equal(t, "zstd", codec)
+	codec, err = deps.catalog.CompressionCodec(ctx, repo, "data/file.parquet", "application/octet-stream")
+	require.NoError(t, err)
+	require.Empty(t, codec)
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, rule := range []apigen.CompressionRule{
+			{Extensions: &[]string{".log"}, Codec: "lz4"},
+			{Codec: "gzip"},
+		} {
+			resp, err := clt.SetCompressionRulesWithResponse(ctx, repo, apigen.SetCompressionRulesJSONRequestBody{Rules: []apigen.CompressionRule{rule}})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		}
+	})
+
+	t.Run("upload", func(t *testing.T) {
+		data := strings.Repeat("compressible log line\n", 100)
+		resp, err := uploadObjectHelper(t, ctx, clt, "data/file.log", strings.NewReader(data), repo, "main")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode(), string(resp.Body))
+
+		entry, err := deps.catalog.GetEntry(ctx, repo, "main", "data/file.log", catalog.GetEntryParams{})
+		require.NoError(t, err)
+		require.Equal(t, "gzip", entry.Metadata.Compression())
+		require.Equal(t, int64(len(data)), entry.Size)
+
+		getResp, err := clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: "data/file.log"})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, getResp.StatusCode())
+		require.Equal(t, data, string(getResp.Body))
+
+		getResp, err = clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: "data/file.log", Range: apiutil.Ptr("bytes=22-43")})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusPartialContent, getResp.StatusCode())
+		require.Equal(t, data[22:44], string(getResp.Body))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		viper.Set("blockstore.compression.enabled", false)
+		clt, deps := setupClientWithAdmin(t)
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+		testutil.MustDo(t, "create repository", err)
+		resp, err := clt.SetCompressionRulesWithResponse(ctx, repo, apigen.SetCompressionRulesJSONRequestBody(rules))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		// clearing the rules is allowed
+		resp, err = clt.SetCompressionRulesWithResponse(ctx, repo, apigen.SetCompressionRulesJSONRequestBody{Rules: []apigen.CompressionRule{}})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+	})
+}
+
+func TestController_Lifecycle(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.MustDo(t, "create repository", err)
+
+	getResp, err := clt.GetLifecycleRulesWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, getResp.StatusCode())
+
+	rules := apigen.LifecycleRules{
+		DefaultTransitionDays: 30,
+		Branches:              []apigen.LifecycleRule{{BranchId: "main", TransitionDays: 90}},
+		StorageClass:          block.StorageClassGlacier,
+	}
+	setResp, err := clt.SetLifecycleRulesWithResponse(ctx, repo, apigen.SetLifecycleRulesJSONRequestBody(rules))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+
+	getResp, err = clt.GetLifecycleRulesWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, getResp.StatusCode())
+	require.Equal(t, rules, *getResp.JSON200)
+
+	invalidResp, err := clt.SetLifecycleRulesWithResponse(ctx, repo, apigen.SetLifecycleRulesJSONRequestBody{StorageClass: block.StorageClassGlacier})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, invalidResp.StatusCode())
+
+	deleteResp, err := clt.DeleteLifecycleRulesWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, deleteResp.StatusCode())
+	getResp, err = clt.GetLifecycleRulesWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, getResp.StatusCode())
+
+	t.Run("restore", func(t *testing.T) {
+		if deps.blocks.BlockstoreType() != block.BlockstoreTypeMem {
+			t.Skip("archive storage classes are simulated only by the mem blockstore")
+		}
+		const objPath = "archived"
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader("data"), repo, "main")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, uploadResp.StatusCode())
+		entry, err := deps.catalog.GetEntry(ctx, repo, "main", objPath, catalog.GetEntryParams{})
+		require.NoError(t, err)
+		obj := block.ObjectPointer{
+			StorageNamespace: onBlock(deps, repo),
+			Identifier:       entry.PhysicalAddress,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+		}
+		require.NoError(t, deps.blocks.SetStorageClass(ctx, obj, block.StorageClassGlacier))
+
+		objResp, err := clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: objPath})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusConflict, objResp.StatusCode())
+
+		restoreResp, err := clt.RestoreObjectWithResponse(ctx, repo, "main", &apigen.RestoreObjectParams{Path: objPath}, apigen.RestoreObjectJSONRequestBody{Days: 1})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusAccepted, restoreResp.StatusCode())
+
+		objResp, err = clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: objPath})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, objResp.StatusCode())
+		require.Equal(t, "data", string(objResp.Body))
+	})
+}
+
+func TestController_Replication(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.MustDo(t, "create repository", err)
+	replicaNamespace := onBlock(deps, repo+"-replica")
+
+	statusResp, err := clt.GetReplicationStatusWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, statusResp.StatusCode())
+
+	invalidResp, err := clt.SetReplicationSettingsWithResponse(ctx, repo, apigen.SetReplicationSettingsJSONRequestBody{StorageNamespace: onBlock(deps, repo)})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, invalidResp.StatusCode())
+
+	settings := apigen.ReplicationSettings{StorageNamespace: replicaNamespace, Synchronous: apiutil.Ptr(true)}
+	setResp, err := clt.SetReplicationSettingsWithResponse(ctx, repo, apigen.SetReplicationSettingsJSONRequestBody(settings))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+	getResp, err := clt.GetReplicationSettingsWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, getResp.StatusCode())
+	require.Equal(t, settings, *getResp.JSON200)
+
+	commitObject := func(path string) string {
+		t.Helper()
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, path, strings.NewReader(path), repo, "main")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, uploadResp.StatusCode())
+		commitResp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: path})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, commitResp.StatusCode())
+		return commitResp.JSON201.Id
+	}
+	requireReplicated := func(paths ...string) {
+		t.Helper()
+		for _, path := range paths {
+			entry, err := deps.catalog.GetEntry(ctx, repo, "main", path, catalog.GetEntryParams{})
+			require.NoError(t, err)
+			found, err := deps.blocks.Exists(ctx, block.ObjectPointer{
+				StorageNamespace: replicaNamespace,
+				Identifier:       entry.PhysicalAddress,
+				IdentifierType:   block.IdentifierTypeRelative,
+			})
+			require.NoError(t, err)
+			require.True(t, found, "object %s replicated", path)
+		}
+	}
+
+	// synchronous replication completes with the commit
+	commitID := commitObject("a")
+	statusResp, err = clt.GetReplicationStatusWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusResp.StatusCode())
+	status := statusResp.JSON200
+	require.Equal(t, 0, status.PendingCommits)
+	require.Equal(t, int64(0), status.LagSeconds)
+	require.Equal(t, commitID, apiutil.Value(status.LastCommitId))
+	require.Equal(t, int64(1), status.ReplicatedObjects)
+	require.Equal(t, int64(1), status.ReplicatedRanges)
+	require.Nil(t, status.Error)
+	requireReplicated("a")
+
+	t.Run("rebase", func(t *testing.T) {
+		_, err := deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+		require.NoError(t, err)
+		for _, path := range []string{"feature-1", "feature-2"} {
+			uploadResp, err := uploadObjectHelper(t, ctx, clt, path, strings.NewReader(path), repo, "feature")
+			require.NoError(t, err)
+			require.Equal(t, http.StatusCreated, uploadResp.StatusCode())
+			commitResp, err := clt.CommitWithResponse(ctx, repo, "feature", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: path})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusCreated, commitResp.StatusCode())
+		}
+		commitObject("main-1")
+
+		rebaseResp, err := clt.RebaseBranchWithResponse(ctx, repo, "feature", apigen.RebaseBranchJSONRequestBody{Ref: "main"})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, rebaseResp.StatusCode())
+		// both replayed commits are replicated
+		commits, _, err := deps.catalog.ListCommits(ctx, repo, "feature", catalog.LogParams{Amount: 2, Limit: true, FirstParent: true})
+		require.NoError(t, err)
+		require.Len(t, commits, 2)
+		repository, err := deps.catalog.Store.GetRepository(ctx, graveler.RepositoryID(repo))
+		require.NoError(t, err)
+		for _, commit := range commits {
+			metaRangeAddress, err := deps.catalog.Store.GetMetaRange(ctx, repository, graveler.MetaRangeID(commit.MetaRangeID))
+			require.NoError(t, err)
+			found, err := deps.blocks.Exists(ctx, block.ObjectPointer{
+				StorageNamespace: replicaNamespace,
+				Identifier:       string(metaRangeAddress),
+				IdentifierType:   block.IdentifierTypeRelative,
+			})
+			require.NoError(t, err)
+			require.True(t, found, "commit %s replicated", commit.Reference)
+		}
+	})
+
+	t.Run("async", func(t *testing.T) {
+		setResp, err := clt.SetReplicationSettingsWithResponse(ctx, repo, apigen.SetReplicationSettingsJSONRequestBody{StorageNamespace: replicaNamespace})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+
+		commitID := commitObject("b")
+		require.Eventually(t, func() bool {
+			statusResp, err := clt.GetReplicationStatusWithResponse(ctx, repo)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, statusResp.StatusCode())
+			return statusResp.JSON200.PendingCommits == 0 && apiutil.Value(statusResp.JSON200.LastCommitId) == commitID
+		}, 5*time.Second, 10*time.Millisecond)
+		requireReplicated("a", "b")
+	})
+
+	deleteResp, err := clt.DeleteReplicationSettingsWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, deleteResp.StatusCode())
+	statusResp, err = clt.GetReplicationStatusWithResponse(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, statusResp.StatusCode())
+}
+
+func TestController_Scrub(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	storageNamespace := onBlock(deps, repo)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", storageNamespace, "main", false)
+	testutil.MustDo(t, "create repository", err)
+
+	var metaRangeID string
+	for _, path := range []string{"a", "b", "c", "d"} {
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, path, strings.NewReader("data-"+path), repo, "main")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, uploadResp.StatusCode())
+		if path == "c" {
+			commitResp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: "scrub"})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusCreated, commitResp.StatusCode())
+			metaRangeID = commitResp.JSON201.MetaRangeId
+		}
+	}
+
+	scrub := func(body apigen.ScrubSubmitJSONRequestBody) *apigen.ScrubStatus {
+		t.Helper()
+		submitResp, err := clt.ScrubSubmitWithResponse(ctx, repo, body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusAccepted, submitResp.StatusCode())
+		var status *apigen.ScrubStatus
+		require.Eventually(t, func() bool {
+			statusResp, err := clt.ScrubStatusWithResponse(ctx, repo, &apigen.ScrubStatusParams{TaskId: submitResp.JSON202.Id})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, statusResp.StatusCode())
+			status = statusResp.JSON200
+			return status.Done
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Nil(t, status.Error)
+		return status
+	}
+
+	status := scrub(apigen.ScrubSubmitJSONRequestBody{Ref: "main"})
+	require.Equal(t, "main", status.Ref)
+	require.Equal(t, "d", apiutil.Value(status.LastPath))
+	require.Equal(t, int64(4), status.VerifiedObjects)
+	require.Positive(t, status.VerifiedRanges)
+	require.Empty(t, status.Problems)
+	require.Equal(t, int64(0), status.ProblemsCount)
+
+	// a completed scrub cannot be resumed
+	submitResp, err := clt.ScrubSubmitWithResponse(ctx, repo, apigen.ScrubSubmitJSONRequestBody{Ref: "main", ResumeTaskId: &status.Id})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, submitResp.StatusCode())
+
+	pointer := func(path string) block.ObjectPointer {
+		t.Helper()
+		entry, err := deps.catalog.GetEntry(ctx, repo, "main", path, catalog.GetEntryParams{})
+		require.NoError(t, err)
+		return block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			Identifier:       entry.PhysicalAddress,
+			IdentifierType:   block.IdentifierTypeRelative,
+		}
+	}
+	require.NoError(t, deps.blocks.Put(ctx, pointer("b"), 6, strings.NewReader("DATA-b"), block.PutOpts{}))
+	require.NoError(t, deps.blocks.Put(ctx, pointer("c"), 3, strings.NewReader("dat"), block.PutOpts{}))
+	require.NoError(t, deps.blocks.Remove(ctx, pointer("d")))
+	metaRangeAddress, err := deps.catalog.GetMetaRange(ctx, repo, metaRangeID)
+	require.NoError(t, err)
+	require.NoError(t, deps.blocks.Remove(ctx, block.ObjectPointer{
+		StorageNamespace: storageNamespace,
+		Identifier:       string(metaRangeAddress),
+		IdentifierType:   block.IdentifierTypeRelative,
+	}))
+
+	status = scrub(apigen.ScrubSubmitJSONRequestBody{Ref: "main", RateLimit: apiutil.Ptr(100)})
+	require.Equal(t, int64(4), status.VerifiedObjects)
+	require.Equal(t, int64(4), status.ProblemsCount)
+	kinds := make(map[string]string)
+	for _, problem := range status.Problems {
+		kinds[problem.Path] = problem.Kind
+	}
+	require.Equal(t, map[string]string{
+		"b":         catalog.ScrubProblemCorrupted,
+		"c":         catalog.ScrubProblemTruncated,
+		"d":         catalog.ScrubProblemMissing,
+		metaRangeID: catalog.ScrubProblemMissingMetaRange,
+	}, kinds)
+}
+
 func TestController_GarbageCollectionRules(t *testing.T) {
 	adminClt, deps := setupClientWithAdmin(t)
 	creds := createUserWithDefaultGroup(t, adminClt)
@@ -5345,7 +6746,7 @@ func TestController_GarbageCollectionRules(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			currCtx := context.Background()
 			repo := testUniqueRepoName()
-			_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", false)
+			_, err := deps.catalog.CreateRepository(currCtx, repo, "", onBlock(deps, repo), "main", false)
 			testutil.MustDo(t, "create repository", err)
 
 			respPreflight, err := tc.clt.SetGarbageCollectionRulesPreflightWithResponse(currCtx, repo)
@@ -5379,7 +6780,7 @@ func TestController_GarbageCollectionRules(t *testing.T) {
 	t.Run("read-only repo", func(t *testing.T) {
 		currCtx := context.Background()
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", true)
+		_, err := deps.catalog.CreateRepository(currCtx, repo, "", onBlock(deps, repo), "main", true)
 		testutil.MustDo(t, "create repository", err)
 
 		resp, err := adminClt.SetGCRulesWithResponse(currCtx, repo, apigen.SetGCRulesJSONRequestBody{
@@ -5403,7 +6804,7 @@ func TestController_DumpRestoreRepository(t *testing.T) {
 
 	// setup repository with some commits
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 	testutil.Must(t, err)
 
 	const commits = 3
@@ -5412,7 +6813,7 @@ func TestController_DumpRestoreRepository(t *testing.T) {
 		p := "foo/bar" + n
 		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n})
 		testutil.MustDo(t, "create entry "+p, err)
-		_, err = deps.catalog.Commit(ctx, repo, "main", "commit"+n, "tester", nil, nil, nil, false)
+		_, err = deps.catalog.Commit(ctx, repo, "main", "commit"+n, "tester", nil, nil, nil, false, nil, nil)
 		testutil.MustDo(t, "commit "+p, err)
 	}
 
@@ -5466,7 +6867,7 @@ func TestController_DumpRestoreRepository(t *testing.T) {
 		}
 
 		newRepo := testUniqueRepoName()
-		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, onBlock(deps, repo), "main", false)
+		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, "create bare repository", err)
 
 		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, newRepo, apigen.RestoreSubmitJSONRequestBody{
@@ -5491,7 +6892,7 @@ func TestController_DumpRestoreRepository(t *testing.T) {
 	t.Run("restore_invalid_refs", func(t *testing.T) {
 		// delete and recreate repository as bare for restore
 		newRepo := testUniqueRepoName()
-		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, onBlock(deps, repo), "main", false)
+		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, "", onBlock(deps, repo), "main", false)
 		testutil.MustDo(t, "create bare repository", err)
 
 		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, newRepo, apigen.RestoreSubmitJSONRequestBody{
@@ -5544,7 +6945,7 @@ func TestController_CreateCommitRecord(t *testing.T) {
 
 	t.Run("create commit record", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
 		testutil.MustDo(t, "create commit record", err)
@@ -5571,7 +6972,7 @@ func TestController_CreateCommitRecord(t *testing.T) {
 
 	t.Run("create commit record with wrong commitID", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		bodyCpy := body
 		bodyCpy.CommitId = "wrong"
@@ -5584,7 +6985,7 @@ func TestController_CreateCommitRecord(t *testing.T) {
 
 	t.Run("read only repository", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", true)
 		testutil.Must(t, err)
 		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
 		testutil.Must(t, err)
@@ -5601,7 +7002,7 @@ func TestController_CreateCommitRecord(t *testing.T) {
 
 	t.Run("already existing commit", func(t *testing.T) {
 		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
 		testutil.Must(t, err)
 		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
 		testutil.MustDo(t, "create commit record", err)
@@ -5638,3 +7039,289 @@ func pollRestoreStatus(t *testing.T, clt apigen.ClientWithResponsesInterface, re
 	}
 	return nil
 }
+
+func TestController_CommitSigning(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "stable", "main")
+	testutil.Must(t, err)
+	protectResp, err := clt.SetBranchProtectionRulesWithResponse(ctx, repo, &apigen.SetBranchProtectionRulesParams{}, []apigen.BranchProtectionRule{
+		{Pattern: "stable", RequireSignedCommits: swag.Bool(true)},
+	})
+	verifyResponseOK(t, protectResp, err)
+
+	serverPublicKey, serverPrivateKey, err := ed25519.GenerateKey(nil)
+	testutil.Must(t, err)
+	clientPublicKey, clientPrivateKey, err := ed25519.GenerateKey(nil)
+	testutil.Must(t, err)
+	gStore := deps.catalog.Store.(*graveler.Graveler)
+
+	t.Run("protection rule", func(t *testing.T) {
+		resp, err := clt.GetBranchProtectionRulesWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		if len(*resp.JSON200) != 1 || !swag.BoolValue((*resp.JSON200)[0].RequireSignedCommits) {
+			t.Fatalf("expected a single rule requiring signed commits, got %+v", *resp.JSON200)
+		}
+	})
+
+	t.Run("reject unsigned merge", func(t *testing.T) {
+		testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
+		_, err := deps.catalog.Commit(ctx, repo, "branch1", "unsigned", DefaultUserID, nil, nil, nil, false, nil, nil)
+		testutil.Must(t, err)
+		resp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "stable", apigen.MergeIntoBranchJSONRequestBody{})
+		testutil.Must(t, err)
+		if resp.JSON403 == nil {
+			t.Fatalf("expected forbidden merging unsigned commit, got %d", resp.StatusCode())
+		}
+	})
+
+	t.Run("server signing", func(t *testing.T) {
+		gStore.SetCommitSigner(graveler.NewEd25519CommitSigner(serverPrivateKey, nil))
+		resp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "stable", apigen.MergeIntoBranchJSONRequestBody{})
+		verifyResponseOK(t, resp, err)
+		commitResp, err := clt.GetCommitWithResponse(ctx, repo, resp.JSON200.Reference)
+		verifyResponseOK(t, commitResp, err)
+		commit := commitResp.JSON200
+		if commit.Signature == nil || !swag.BoolValue(commit.Verified) {
+			t.Fatalf("expected verified signature, got signature=%v verified=%v", commit.Signature, commit.Verified)
+		}
+		signature, err := base64.StdEncoding.DecodeString(*commit.Signature)
+		testutil.Must(t, err)
+		repository, err := deps.catalog.Store.GetRepository(ctx, graveler.RepositoryID(repo))
+		testutil.Must(t, err)
+		gCommit, err := deps.catalog.Store.GetCommit(ctx, repository, graveler.CommitID(commit.Id))
+		testutil.Must(t, err)
+		if !ed25519.Verify(serverPublicKey, gCommit.Identity(), signature) {
+			t.Fatal("signature does not match the server key")
+		}
+	})
+
+	t.Run("client signature", func(t *testing.T) {
+		gStore.SetCommitSigner(graveler.NewEd25519CommitSigner(nil, []ed25519.PublicKey{clientPublicKey}))
+		unsignedResp, err := clt.CommitWithResponse(ctx, repo, "branch1", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:    "unsigned",
+			AllowEmpty: swag.Bool(true),
+		})
+		verifyResponseOK(t, unsignedResp, err)
+		headResp, err := clt.GetCommitWithResponse(ctx, repo, unsignedResp.JSON201.Id)
+		verifyResponseOK(t, headResp, err)
+		head := headResp.JSON200
+		if head.Signature != nil || swag.BoolValue(head.Verified) {
+			t.Fatal("expected unsigned commit")
+		}
+
+		date := time.Now().Unix()
+		commit := graveler.Commit{
+			Committer:    head.Committer,
+			Message:      "signed by client",
+			MetaRangeID:  graveler.MetaRangeID(head.MetaRangeId),
+			CreationDate: time.Unix(date, 0),
+			Parents:      graveler.CommitParents{graveler.CommitID(head.Id)},
+		}
+		commitWithSignature := func(privateKey ed25519.PrivateKey) (*apigen.CommitResponse, error) {
+			signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, commit.Identity()))
+			return clt.CommitWithResponse(ctx, repo, "branch1", &apigen.CommitParams{SourceMetarange: apiutil.Ptr(head.MetaRangeId)}, apigen.CommitJSONRequestBody{
+				Message:    commit.Message,
+				Date:       &date,
+				AllowEmpty: swag.Bool(true),
+				Signature:  &signature,
+			})
+		}
+
+		resp, err := commitWithSignature(serverPrivateKey)
+		testutil.Must(t, err)
+		if resp.JSON400 == nil {
+			t.Fatalf("expected bad request for untrusted signature, got %d", resp.StatusCode())
+		}
+		// the commit content is not known in advance without a source metarange
+		signature := base64.StdEncoding.EncodeToString(ed25519.Sign(clientPrivateKey, commit.Identity()))
+		resp, err = clt.CommitWithResponse(ctx, repo, "branch1", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:    commit.Message,
+			Date:       &date,
+			AllowEmpty: swag.Bool(true),
+			Signature:  &signature,
+		})
+		testutil.Must(t, err)
+		if resp.JSON400 == nil {
+			t.Fatalf("expected bad request for signature without source metarange, got %d", resp.StatusCode())
+		}
+		resp, err = commitWithSignature(clientPrivateKey)
+		verifyResponseOK(t, resp, err)
+		if !swag.BoolValue(resp.JSON201.Verified) {
+			t.Fatal("expected client signed commit to be verified")
+		}
+		commitResp, err := clt.GetCommitWithResponse(ctx, repo, resp.JSON201.Id)
+		verifyResponseOK(t, commitResp, err)
+		if !swag.BoolValue(commitResp.JSON200.Verified) {
+			t.Fatal("expected client signed commit to be verified")
+		}
+
+		logResp, err := clt.LogCommitsWithResponse(ctx, repo, "branch1", &apigen.LogCommitsParams{Amount: apiutil.Ptr(apigen.PaginationAmount(2))})
+		verifyResponseOK(t, logResp, err)
+		results := logResp.JSON200.Results
+		if len(results) != 2 || !swag.BoolValue(results[0].Verified) || results[1].Signature != nil {
+			t.Fatalf("expected a verified commit followed by an unsigned commit, got %+v", results)
+		}
+	})
+}
+
+func TestController_DiffRefsSummary(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "data/a/removed", PhysicalAddress: "addr1", CreationDate: time.Now(), Size: 10, Checksum: "cksum1"}))
+	testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "data/b/changed", PhysicalAddress: "addr2", CreationDate: time.Now(), Size: 20, Checksum: "cksum2"}))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "base", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	testutil.MustDo(t, "delete entry", deps.catalog.DeleteEntry(ctx, repo, "branch1", "data/a/removed"))
+	testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "data/b/changed", PhysicalAddress: "addr3", CreationDate: time.Now(), Size: 25, Checksum: "cksum3"}))
+	testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "data/c/added1", PhysicalAddress: "addr4", CreationDate: time.Now(), Size: 30, Checksum: "cksum4"}))
+	testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "data/c/added2", PhysicalAddress: "addr5", CreationDate: time.Now(), Size: 40, Checksum: "cksum5"}))
+	testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "top", PhysicalAddress: "addr6", CreationDate: time.Now(), Size: 50, Checksum: "cksum6"}))
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "changes", DefaultUserID, nil, nil, nil, false, nil, nil)
+	testutil.Must(t, err)
+
+	t.Run("depth", func(t *testing.T) {
+		resp, err := clt.DiffRefsSummaryWithResponse(ctx, repo, "main", "branch1", &apigen.DiffRefsSummaryParams{
+			Depth: apiutil.Ptr(2),
+		})
+		verifyResponseOK(t, resp, err)
+		expected := &apigen.DiffSummary{
+			Total: apigen.DiffStats{Path: "", Added: 3, Removed: 1, Changed: 1, AddedBytes: 120, RemovedBytes: 10, ChangedBytes: 25},
+			Prefixes: []apigen.DiffStats{
+				{Path: "", Added: 1, AddedBytes: 50},
+				{Path: "data/a/", Removed: 1, RemovedBytes: 10},
+				{Path: "data/b/", Changed: 1, ChangedBytes: 25},
+				{Path: "data/c/", Added: 2, AddedBytes: 70},
+			},
+		}
+		if diff := deep.Equal(resp.JSON200, expected); diff != nil {
+			t.Fatalf("unexpected diff summary: %s", diff)
+		}
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		resp, err := clt.DiffRefsSummaryWithResponse(ctx, repo, "main", "branch1", &apigen.DiffRefsSummaryParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("data/")),
+			Type:   apiutil.Ptr("two_dot"),
+		})
+		verifyResponseOK(t, resp, err)
+		if resp.JSON200.Total.Added != 2 || len(resp.JSON200.Prefixes) != 3 {
+			t.Fatalf("unexpected diff summary under prefix: %+v", resp.JSON200)
+		}
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		resp, err := clt.DiffRefsSummaryWithResponse(ctx, repo, "main", "branch1", &apigen.DiffRefsSummaryParams{
+			Depth: apiutil.Ptr(-1),
+		})
+		testutil.Must(t, err)
+		if resp.JSON400 == nil {
+			t.Fatalf("expected bad request, got %d", resp.StatusCode())
+		}
+	})
+}
+
+func TestController_BranchReflog(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, "", onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	branchResp, err := clt.CreateBranchWithResponse(ctx, repo, apigen.CreateBranchJSONRequestBody{
+		Name:   "branch1",
+		Source: "main",
+	})
+	verifyResponseOK(t, branchResp, err)
+
+	commitIDs := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar" + strconv.Itoa(i), PhysicalAddress: "bar_address", Checksum: "cksum"}))
+		resp, err := clt.CommitWithResponse(ctx, repo, "branch1", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "commit " + strconv.Itoa(i),
+		})
+		verifyResponseOK(t, resp, err)
+		commitIDs = append(commitIDs, resp.JSON201.Id)
+	}
+
+	listReflog := func(t *testing.T) []apigen.ReflogEntry {
+		t.Helper()
+		resp, err := clt.ListBranchReflogWithResponse(ctx, repo, "branch1", &apigen.ListBranchReflogParams{})
+		verifyResponseOK(t, resp, err)
+		return resp.JSON200.Results
+	}
+
+	t.Run("list", func(t *testing.T) {
+		entries := listReflog(t)
+		operations := make([]string, 0, len(entries))
+		for _, e := range entries {
+			operations = append(operations, e.Operation)
+			if swag.StringValue(e.User) != "admin" {
+				t.Errorf("Reflog entry %s user=%s, expected admin", e.Id, swag.StringValue(e.User))
+			}
+		}
+		if diff := deep.Equal(operations, []string{"commit", "commit", "create_branch"}); diff != nil {
+			t.Fatal("Reflog operations diff:", diff)
+		}
+		if entries[0].NewCommitId != commitIDs[1] || entries[0].OldCommitId != commitIDs[0] {
+			t.Fatalf("Latest reflog entry moved %s to %s, expected %s to %s", entries[0].OldCommitId, entries[0].NewCommitId, commitIDs[0], commitIDs[1])
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		entries := listReflog(t)
+		resp, err := clt.ListBranchReflogWithResponse(ctx, repo, "branch1", &apigen.ListBranchReflogParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(entries[0].Id)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+		})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Results) != 1 || resp.JSON200.Results[0].Id != entries[1].Id || !resp.JSON200.Pagination.HasMore {
+			t.Fatalf("Reflog page %+v, expected entry %s with more results", resp.JSON200, entries[1].Id)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		entries := listReflog(t)
+		resp, err := clt.RestoreBranchReflogWithResponse(ctx, repo, "branch1", apigen.RestoreBranchReflogJSONRequestBody{
+			EntryId: entries[0].Id,
+		})
+		verifyResponseOK(t, resp, err)
+		if resp.JSON200.CommitId != commitIDs[0] {
+			t.Fatalf("Restored branch to %s, expected %s", resp.JSON200.CommitId, commitIDs[0])
+		}
+		restored := listReflog(t)
+		if restored[0].Operation != "restore" || restored[0].OldCommitId != commitIDs[1] || restored[0].NewCommitId != commitIDs[0] {
+			t.Fatalf("Restore reflog entry %+v, expected restore from %s to %s", restored[0], commitIDs[1], commitIDs[0])
+		}
+	})
+
+	t.Run("restore created branch", func(t *testing.T) {
+		entries := listReflog(t)
+		resp, err := clt.RestoreBranchReflogWithResponse(ctx, repo, "branch1", apigen.RestoreBranchReflogJSONRequestBody{
+			EntryId: entries[len(entries)-1].Id,
+		})
+		testutil.Must(t, err)
+		if resp.JSON400 == nil {
+			t.Fatalf("expected bad request restoring branch creation, got %d", resp.StatusCode())
+		}
+	})
+
+	t.Run("restore missing entry", func(t *testing.T) {
+		resp, err := clt.RestoreBranchReflogWithResponse(ctx, repo, "branch1", apigen.RestoreBranchReflogJSONRequestBody{
+			EntryId: "missing",
+		})
+		testutil.Must(t, err)
+		if resp.JSON404 == nil {
+			t.Fatalf("expected not found, got %d", resp.StatusCode())
+		}
+	})
+}
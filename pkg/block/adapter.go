@@ -63,12 +63,18 @@ const DefaultPreSignExpiryDuration = 15 * time.Minute
 // ObjectPointer is a unique identifier of an object in the object
 // store: the store is a 1:1 mapping between pointers and objects.
 type ObjectPointer struct {
+	// StorageID identifies the blockstore holding the object, empty for the default blockstore.
+	StorageID        string
 	StorageNamespace string
 	Identifier       string
 
 	// Indicates whether the Identifier is relative to the StorageNamespace,
 	// full address to an object, or unknown.
 	IdentifierType IdentifierType
+
+	// Compression is the codec the object was compressed with by a compressing adapter, as marked on its entry.
+	// Empty for objects stored as is.
+	Compression string
 }
 
 // PutOpts contains optional arguments for Put.  These should be
@@ -81,6 +87,24 @@ type ObjectPointer struct {
 // value is retained.
 type PutOpts struct {
 	StorageClass *string // S3 storage class
+	Compression  string  // Codec compressing the object, applied by a compressing adapter
+}
+
+// Archive storage classes hold objects which must be restored before they are read
+const (
+	StorageClassGlacier      = "GLACIER"
+	StorageClassDeepArchive  = "DEEP_ARCHIVE"
+	StorageClassAzureArchive = "Archive"
+)
+
+// IsArchiveStorageClass returns true in case objects of storageClass must be restored before they are read
+func IsArchiveStorageClass(storageClass string) bool {
+	switch storageClass {
+	case StorageClassGlacier, StorageClassDeepArchive, StorageClassAzureArchive:
+		return true
+	default:
+		return false
+	}
 }
 
 // WalkOpts is a unique identifier of a prefix in the object store.
@@ -140,13 +164,14 @@ type ListPartsOpts struct {
 // Refer to the actual underlying Adapter for which properties are
 // actually reported.
 type Properties struct {
-	StorageClass *string
+	StorageClass  *string
+	ContentLength int64
 }
 
 type Adapter interface {
 	Put(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, opts PutOpts) error
 	Get(ctx context.Context, obj ObjectPointer, expectedSize int64) (io.ReadCloser, error)
-	GetWalker(uri *url.URL) (Walker, error)
+	GetWalker(storageID string, uri *url.URL) (Walker, error)
 
 	// GetPreSignedURL returns a pre-signed URL for accessing obj with mode, and the
 	// expiry time for this URL.  The expiry time IsZero() if reporting
@@ -159,6 +184,12 @@ type Adapter interface {
 	GetProperties(ctx context.Context, obj ObjectPointer) (Properties, error)
 	Remove(ctx context.Context, obj ObjectPointer) error
 	Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) error
+	// SetStorageClass transitions obj to storageClass. Objects in an archive storage class must be restored before
+	// they are read, reading them fails with ErrRestoreRequired.
+	SetStorageClass(ctx context.Context, obj ObjectPointer, storageClass string) error
+	// Restore makes archived obj readable for the given number of days. Restoring is asynchronous on most
+	// blockstores, obj is readable once the restore completes.
+	Restore(ctx context.Context, obj ObjectPointer, days int) error
 	CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (*CreateMultiPartUploadResponse, error)
 	UploadPart(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*UploadPartResponse, error)
 	ListParts(ctx context.Context, obj ObjectPointer, uploadID string, opts ListPartsOpts) (*ListPartsResponse, error)
@@ -168,6 +199,6 @@ type Adapter interface {
 	CompleteMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string, multipartList *MultipartUploadCompletion) (*CompleteMultiPartUploadResponse, error)
 	BlockstoreType() string
 	GetStorageNamespaceInfo() StorageNamespaceInfo
-	ResolveNamespace(storageNamespace, key string, identifierType IdentifierType) (QualifiedKey, error)
+	ResolveNamespace(storageID, storageNamespace, key string, identifierType IdentifierType) (QualifiedKey, error)
 	RuntimeStats() map[string]string
 }
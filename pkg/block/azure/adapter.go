@@ -217,7 +217,7 @@ func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io
 	return a.Download(ctx, obj, 0, blockblob.CountToEnd)
 }
 
-func (a *Adapter) GetWalker(uri *url.URL) (block.Walker, error) {
+func (a *Adapter) GetWalker(_ string, uri *url.URL) (block.Walker, error) {
 	if err := block.ValidateStorageType(uri, block.StorageTypeAzure); err != nil {
 		return nil, err
 	}
@@ -341,6 +341,9 @@ func (a *Adapter) Download(ctx context.Context, obj block.ObjectPointer, offset,
 	if bloberror.HasCode(err, bloberror.BlobNotFound) {
 		return nil, block.ErrDataNotFound
 	}
+	if bloberror.HasCode(err, bloberror.BlobArchived, bloberror.BlobBeingRehydrated) {
+		return nil, block.ErrRestoreRequired
+	}
 	if err != nil {
 		a.log(ctx).WithError(err).Errorf("failed to get azure blob from container %s key %s", container, blobURL)
 		return nil, err
@@ -393,7 +396,11 @@ func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (b
 	if err != nil {
 		return block.Properties{}, err
 	}
-	return block.Properties{StorageClass: props.AccessTier}, nil
+	properties := block.Properties{StorageClass: props.AccessTier}
+	if props.ContentLength != nil {
+		properties.ContentLength = *props.ContentLength
+	}
+	return properties, nil
 }
 
 func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
@@ -413,6 +420,60 @@ func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
 	return err
 }
 
+func (a *Adapter) blobClient(obj block.ObjectPointer) (*blob.Client, error) {
+	qualifiedKey, err := resolveBlobURLInfo(obj)
+	if err != nil {
+		return nil, err
+	}
+	containerClient, err := a.clientCache.NewContainerClient(qualifiedKey.StorageAccountName, qualifiedKey.ContainerName)
+	if err != nil {
+		return nil, err
+	}
+	return containerClient.NewBlobClient(qualifiedKey.BlobURL), nil
+}
+
+func (a *Adapter) SetStorageClass(ctx context.Context, obj block.ObjectPointer, storageClass string) error {
+	var err error
+	defer reportMetrics("SetStorageClass", time.Now(), nil, &err)
+	blobClient, err := a.blobClient(obj)
+	if err != nil {
+		return err
+	}
+	_, err = blobClient.SetTier(ctx, blob.AccessTier(storageClass), nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return block.ErrDataNotFound
+	}
+	return err
+}
+
+// Restore rehydrates an archived blob to the hot tier. Rehydrated blobs stay in the hot tier, days is ignored.
+func (a *Adapter) Restore(ctx context.Context, obj block.ObjectPointer, _ int) error {
+	var err error
+	defer reportMetrics("Restore", time.Now(), nil, &err)
+	blobClient, err := a.blobClient(obj)
+	if err != nil {
+		return err
+	}
+	props, err := blobClient.GetProperties(ctx, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return block.ErrDataNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if props.AccessTier == nil || *props.AccessTier != string(blob.AccessTierArchive) || props.ArchiveStatus != nil {
+		// not archived, or already being rehydrated
+		return nil
+	}
+	_, err = blobClient.SetTier(ctx, blob.AccessTierHot, &blob.SetTierOptions{
+		RehydratePriority: to.Ptr(blob.RehydratePriorityStandard),
+	})
+	if bloberror.HasCode(err, bloberror.BlobBeingRehydrated) {
+		return nil
+	}
+	return err
+}
+
 func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.ObjectPointer) error {
 	var err error
 	defer reportMetrics("Copy", time.Now(), nil, &err)
@@ -614,7 +675,7 @@ func (a *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
 	return info
 }
 
-func (a *Adapter) ResolveNamespace(storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+func (a *Adapter) ResolveNamespace(_, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
 	return block.DefaultResolveNamespace(storageNamespace, key, identifierType)
 }
 
@@ -65,6 +65,10 @@ func testAdapterPutGet(t *testing.T, adapter block.Adapter, storageNamespace, ex
 			got, err := io.ReadAll(reader)
 			require.NoError(t, err)
 			require.Equal(t, contents, string(got))
+
+			props, err := adapter.GetProperties(ctx, obj)
+			require.NoError(t, err)
+			require.Equal(t, size, props.ContentLength)
 		})
 	}
 }
@@ -155,7 +159,7 @@ func testAdapterRemove(t *testing.T, adapter block.Adapter, storageNamespace str
 				t.Errorf("Remove() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
-			qk, err := adapter.ResolveNamespace(storageNamespace, tt.name, block.IdentifierTypeRelative)
+			qk, err := adapter.ResolveNamespace("", storageNamespace, tt.name, block.IdentifierTypeRelative)
 			require.NoError(t, err)
 
 			tree := dumpPathTree(t, ctx, adapter, qk)
@@ -173,7 +177,7 @@ func dumpPathTree(t testing.TB, ctx context.Context, adapter block.Adapter, qk b
 	uri, err := url.Parse(qk.Format())
 	require.NoError(t, err)
 
-	w, err := adapter.GetWalker(uri)
+	w, err := adapter.GetWalker("", uri)
 	require.NoError(t, err)
 
 	walker := store.NewWrapper(w, uri)
@@ -425,12 +429,12 @@ func testAdapterWalker(t *testing.T, adapter block.Adapter, storageNamespace str
 		},
 	}
 	for _, tt := range cases {
-		qk, err := adapter.ResolveNamespace(storageNamespace, filepath.Join(testPrefix, tt.prefix), block.IdentifierTypeRelative)
+		qk, err := adapter.ResolveNamespace("", storageNamespace, filepath.Join(testPrefix, tt.prefix), block.IdentifierTypeRelative)
 		require.NoError(t, err)
 		uri, err := url.Parse(qk.Format())
 		require.NoError(t, err)
 		t.Run(tt.name, func(t *testing.T) {
-			reader, err := adapter.GetWalker(uri)
+			reader, err := adapter.GetWalker("", uri)
 			require.NoError(t, err)
 
 			var results []string
@@ -0,0 +1,379 @@
+package compression
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+var ErrInvalidFormat = errors.New("invalid compressed object")
+
+// Adapter compresses objects written with a compression codec in their PutOpts, and decompresses objects read with
+// the codec marked on their ObjectPointer. Objects written without a codec, by multipart uploads or directly to the
+// underlying storage are stored as is, and objects read without a codec are read as is, with no additional requests.
+// Compressed objects are readable only through the Adapter, so pre-signed URLs are not supported.
+type Adapter struct {
+	adapter   block.Adapter
+	frameSize int
+}
+
+type AdapterOption func(a *Adapter)
+
+// WithFrameSize sets the size of the data compressed separately, reading a range decompresses only the frames
+// holding it
+func WithFrameSize(frameSize int) AdapterOption {
+	return func(a *Adapter) {
+		if frameSize > 0 && frameSize <= MaxFrameSize {
+			a.frameSize = frameSize
+		}
+	}
+}
+
+func NewAdapter(adapter block.Adapter, opts ...AdapterOption) *Adapter {
+	a := &Adapter{
+		adapter:   adapter,
+		frameSize: DefaultFrameSize,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *Adapter) Put(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
+	if opts.Compression == "" {
+		return a.adapter.Put(ctx, obj, sizeBytes, reader, opts)
+	}
+	c, err := codecByName(opts.Compression)
+	if err != nil {
+		return err
+	}
+	h := &header{codec: c, frameSize: a.frameSize}
+	// the compressed size is unknown until the data is compressed
+	return a.adapter.Put(ctx, obj, -1, newCompressReader(reader, h), opts)
+}
+
+func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, expectedSize int64) (io.ReadCloser, error) {
+	if obj.Compression == "" {
+		return a.adapter.Get(ctx, obj, expectedSize)
+	}
+	// the stored size differs from the size of the data
+	body, err := a.adapter.Get(ctx, obj, 0)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, headerSize)
+	if _, err := io.ReadFull(body, buf); err != nil {
+		_ = body.Close()
+		return nil, truncated(err)
+	}
+	h, err := parseHeader(buf)
+	if err != nil {
+		_ = body.Close()
+		return nil, err
+	}
+	return &objectReader{
+		dec:    &frameDecoder{src: body, h: h},
+		closer: body,
+	}, nil
+}
+
+func (a *Adapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	if obj.Compression == "" {
+		return a.adapter.GetRange(ctx, obj, startPosition, endPosition)
+	}
+	if startPosition < 0 || endPosition < startPosition {
+		return nil, block.ErrBadIndex
+	}
+	idx, err := a.index(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if startPosition >= idx.dataSize {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	endPosition = min(endPosition, idx.dataSize-1)
+	frameSize := int64(idx.frameSize)
+	first := startPosition / frameSize
+	last := endPosition / frameSize
+	body, err := a.adapter.GetRange(ctx, obj, idx.offsets[first], idx.offsets[last+1]-1)
+	if err != nil {
+		return nil, err
+	}
+	return &rangeReader{
+		dec:    &frameDecoder{src: body, h: &idx.header},
+		closer: body,
+		skip:   startPosition - first*frameSize,
+		remain: endPosition - startPosition + 1,
+	}, nil
+}
+
+// index reads the index of a compressed object
+func (a *Adapter) index(ctx context.Context, obj block.ObjectPointer) (*index, error) {
+	props, err := a.adapter.GetProperties(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return a.readIndex(ctx, obj, props.ContentLength)
+}
+
+// readIndex reads the index of a compressed object of size stored bytes
+func (a *Adapter) readIndex(ctx context.Context, obj block.ObjectPointer, size int64) (*index, error) {
+	if size < headerSize+frameLengthSize+footerSize {
+		return nil, fmt.Errorf("%w: truncated object", ErrInvalidFormat)
+	}
+	buf, err := a.readRange(ctx, obj, 0, headerSize-1)
+	if err != nil {
+		return nil, err
+	}
+	h, err := parseHeader(buf)
+	if err != nil {
+		return nil, err
+	}
+	footer, err := a.readRange(ctx, obj, size-footerSize, size-1)
+	if err != nil {
+		return nil, err
+	}
+	tableCRC, count, dataSize, err := parseFooter(footer)
+	if err != nil {
+		return nil, err
+	}
+	tableStart := size - footerSize - int64(count)*frameLengthSize
+	if tableStart < headerSize+frameLengthSize {
+		return nil, fmt.Errorf("%w: bad footer", ErrInvalidFormat)
+	}
+	var table []byte
+	if count > 0 {
+		table, err = a.readRange(ctx, obj, tableStart, size-footerSize-1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if crc32.ChecksumIEEE(table) != tableCRC {
+		return nil, fmt.Errorf("%w: bad seek table", ErrInvalidFormat)
+	}
+	return newIndex(h, size, table, dataSize)
+}
+
+// readRange reads the range [start, end] of the stored object, the returned data is shorter in case the object ends
+// before the end of the range
+func (a *Adapter) readRange(ctx context.Context, obj block.ObjectPointer, start, end int64) ([]byte, error) {
+	r, err := a.adapter.GetRange(ctx, obj, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	return io.ReadAll(io.LimitReader(r, end-start+1))
+}
+
+func (a *Adapter) GetWalker(storageID string, uri *url.URL) (block.Walker, error) {
+	return a.adapter.GetWalker(storageID, uri)
+}
+
+func (a *Adapter) GetPreSignedURL(_ context.Context, _ block.ObjectPointer, _ block.PreSignMode) (string, time.Time, error) {
+	return "", time.Time{}, fmt.Errorf("compressed blockstore: %w", block.ErrOperationNotSupported)
+}
+
+func (a *Adapter) GetPresignUploadPartURL(_ context.Context, _ block.ObjectPointer, _ string, _ int) (string, error) {
+	return "", fmt.Errorf("compressed blockstore: %w", block.ErrOperationNotSupported)
+}
+
+func (a *Adapter) Exists(ctx context.Context, obj block.ObjectPointer) (bool, error) {
+	return a.adapter.Exists(ctx, obj)
+}
+
+// GetProperties reports the size of the data of a compressed object, rather than its stored size
+func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (block.Properties, error) {
+	props, err := a.adapter.GetProperties(ctx, obj)
+	if err != nil || obj.Compression == "" {
+		return props, err
+	}
+	idx, err := a.readIndex(ctx, obj, props.ContentLength)
+	if err != nil {
+		return block.Properties{}, err
+	}
+	props.ContentLength = idx.dataSize
+	return props, nil
+}
+
+func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
+	return a.adapter.Remove(ctx, obj)
+}
+
+// Copy copies the stored object as is
+func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.ObjectPointer) error {
+	return a.adapter.Copy(ctx, sourceObj, destinationObj)
+}
+
+func (a *Adapter) SetStorageClass(ctx context.Context, obj block.ObjectPointer, storageClass string) error {
+	return a.adapter.SetStorageClass(ctx, obj, storageClass)
+}
+
+func (a *Adapter) Restore(ctx context.Context, obj block.ObjectPointer, days int) error {
+	return a.adapter.Restore(ctx, obj, days)
+}
+
+func (a *Adapter) CreateMultiPartUpload(ctx context.Context, obj block.ObjectPointer, r *http.Request, opts block.CreateMultiPartUploadOpts) (*block.CreateMultiPartUploadResponse, error) {
+	return a.adapter.CreateMultiPartUpload(ctx, obj, r, opts)
+}
+
+func (a *Adapter) UploadPart(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+	return a.adapter.UploadPart(ctx, obj, sizeBytes, reader, uploadID, partNumber)
+}
+
+func (a *Adapter) ListParts(ctx context.Context, obj block.ObjectPointer, uploadID string, opts block.ListPartsOpts) (*block.ListPartsResponse, error) {
+	return a.adapter.ListParts(ctx, obj, uploadID, opts)
+}
+
+// UploadCopyPart copies an uncompressed source object as is, and uploads the data of a compressed one
+func (a *Adapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj block.ObjectPointer, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+	if sourceObj.Compression == "" {
+		return a.adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
+	}
+	idx, err := a.index(ctx, sourceObj)
+	if err != nil {
+		return nil, err
+	}
+	r, err := a.Get(ctx, sourceObj, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	return a.adapter.UploadPart(ctx, destinationObj, idx.dataSize, r, uploadID, partNumber)
+}
+
+// UploadCopyPartRange decompresses the range of the source object and uploads it as a part
+func (a *Adapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj block.ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*block.UploadPartResponse, error) {
+	if sourceObj.Compression == "" {
+		return a.adapter.UploadCopyPartRange(ctx, sourceObj, destinationObj, uploadID, partNumber, startPosition, endPosition)
+	}
+	idx, err := a.index(ctx, sourceObj)
+	if err != nil {
+		return nil, err
+	}
+	r, err := a.GetRange(ctx, sourceObj, startPosition, endPosition)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	size := max(min(endPosition+1, idx.dataSize)-startPosition, 0)
+	return a.adapter.UploadPart(ctx, destinationObj, size, r, uploadID, partNumber)
+}
+
+func (a *Adapter) AbortMultiPartUpload(ctx context.Context, obj block.ObjectPointer, uploadID string) error {
+	return a.adapter.AbortMultiPartUpload(ctx, obj, uploadID)
+}
+
+func (a *Adapter) CompleteMultiPartUpload(ctx context.Context, obj block.ObjectPointer, uploadID string, multipartList *block.MultipartUploadCompletion) (*block.CompleteMultiPartUploadResponse, error) {
+	return a.adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+}
+
+func (a *Adapter) BlockstoreType() string {
+	return a.adapter.BlockstoreType()
+}
+
+func (a *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
+	info := a.adapter.GetStorageNamespaceInfo()
+	info.PreSignSupport = false
+	info.PreSignSupportUI = false
+	info.PreSignSupportMultipart = false
+	return info
+}
+
+func (a *Adapter) ResolveNamespace(storageID, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+	return a.adapter.ResolveNamespace(storageID, storageNamespace, key, identifierType)
+}
+
+func (a *Adapter) RuntimeStats() map[string]string {
+	return a.adapter.RuntimeStats()
+}
+
+// objectReader decompresses a complete object, and verifies its seek table and footer after the last frame
+type objectReader struct {
+	dec     *frameDecoder
+	closer  io.Closer
+	pending []byte
+	err     error
+}
+
+func (r *objectReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.pending, r.err = r.dec.next()
+		if errors.Is(r.err, io.EOF) {
+			r.err = r.verifyFooter()
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *objectReader) verifyFooter() error {
+	rest, err := io.ReadAll(r.dec.src)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(rest, encodeFooter(r.dec.lengths, r.dec.size)) {
+		return fmt.Errorf("%w: bad footer", ErrInvalidFormat)
+	}
+	return io.EOF
+}
+
+func (r *objectReader) Close() error {
+	return r.closer.Close()
+}
+
+// rangeReader decompresses the frames holding a range, skipping the data before the range and after its end
+type rangeReader struct {
+	dec     *frameDecoder
+	closer  io.Closer
+	skip    int64
+	remain  int64
+	pending []byte
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.remain == 0 {
+			return 0, io.EOF
+		}
+		plain, err := r.dec.next()
+		if errors.Is(err, io.EOF) {
+			return 0, fmt.Errorf("%w: truncated object", ErrInvalidFormat)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if r.skip >= int64(len(plain)) {
+			return 0, fmt.Errorf("%w: bad frame size", ErrInvalidFormat)
+		}
+		plain = plain[r.skip:]
+		r.skip = 0
+		r.pending = plain[:min(int64(len(plain)), r.remain)]
+		r.remain -= int64(len(r.pending))
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *rangeReader) Close() error {
+	return r.closer.Close()
+}
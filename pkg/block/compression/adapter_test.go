@@ -0,0 +1,246 @@
+package compression_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/blocktest"
+	"github.com/treeverse/lakefs/pkg/block/compression"
+	"github.com/treeverse/lakefs/pkg/block/local"
+)
+
+const (
+	testStorageNamespace = "local://test"
+	testFrameSize        = 1024
+)
+
+func newLocalAdapter(t *testing.T) (*local.Adapter, string) {
+	t.Helper()
+	localPath := path.Join(t.TempDir(), "lakefs")
+	adapter, err := local.NewAdapter(localPath, local.WithRemoveEmptyDir(false))
+	require.NoError(t, err)
+	return adapter, localPath
+}
+
+// codecAdapter compresses every object it writes using codec, and marks the objects it wrote compressed when reading
+// them, as lakeFS marks them on their entries
+type codecAdapter struct {
+	*compression.Adapter
+	codec      string
+	mu         sync.Mutex
+	compressed map[string]bool
+}
+
+func (a *codecAdapter) setCompressed(obj block.ObjectPointer, compressed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.compressed[obj.StorageNamespace+"/"+obj.Identifier] = compressed
+}
+
+// mark returns obj marked with the codec in case it was written compressed
+func (a *codecAdapter) mark(obj block.ObjectPointer) block.ObjectPointer {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.compressed[obj.StorageNamespace+"/"+obj.Identifier] {
+		obj.Compression = a.codec
+	}
+	return obj
+}
+
+func (a *codecAdapter) Put(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
+	opts.Compression = a.codec
+	err := a.Adapter.Put(ctx, obj, sizeBytes, reader, opts)
+	if err == nil {
+		a.setCompressed(obj, a.codec != "")
+	}
+	return err
+}
+
+func (a *codecAdapter) Get(ctx context.Context, obj block.ObjectPointer, expectedSize int64) (io.ReadCloser, error) {
+	return a.Adapter.Get(ctx, a.mark(obj), expectedSize)
+}
+
+func (a *codecAdapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	return a.Adapter.GetRange(ctx, a.mark(obj), startPosition, endPosition)
+}
+
+func (a *codecAdapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (block.Properties, error) {
+	return a.Adapter.GetProperties(ctx, a.mark(obj))
+}
+
+func (a *codecAdapter) Copy(ctx context.Context, sourceObj, destinationObj block.ObjectPointer) error {
+	err := a.Adapter.Copy(ctx, sourceObj, destinationObj)
+	if err == nil {
+		a.setCompressed(destinationObj, a.mark(sourceObj).Compression != "")
+	}
+	return err
+}
+
+func (a *codecAdapter) CompleteMultiPartUpload(ctx context.Context, obj block.ObjectPointer, uploadID string, multipartList *block.MultipartUploadCompletion) (*block.CompleteMultiPartUploadResponse, error) {
+	resp, err := a.Adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+	if err == nil {
+		a.setCompressed(obj, false)
+	}
+	return resp, err
+}
+
+func TestCompressionAdapter(t *testing.T) {
+	for _, codec := range append(compression.Codecs(), "") {
+		name := codec
+		if name == "" {
+			name = "none"
+		}
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			localPath := path.Join(tmpDir, "lakefs")
+			externalPath := block.BlockstoreTypeLocal + "://" + path.Join(tmpDir, "lakefs", "external")
+			adapter, err := local.NewAdapter(localPath, local.WithRemoveEmptyDir(false))
+			require.NoError(t, err)
+			blocktest.AdapterTest(t, &codecAdapter{Adapter: compression.NewAdapter(adapter), codec: codec, compressed: make(map[string]bool)}, testStorageNamespace, externalPath)
+		})
+	}
+}
+
+func TestCompressionAdapter_StoredCompressed(t *testing.T) {
+	ctx := context.Background()
+	localAdapter, localPath := newLocalAdapter(t)
+	adapter := compression.NewAdapter(localAdapter)
+	data := bytes.Repeat([]byte("2023-10-01T00:00:00Z INFO compressible log line\n"), 1000)
+
+	compressed := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "compressed", IdentifierType: block.IdentifierTypeRelative, Compression: compression.CodecZstd}
+	require.NoError(t, adapter.Put(ctx, compressed, int64(len(data)), bytes.NewReader(data), block.PutOpts{Compression: compression.CodecZstd}))
+	stored, err := os.ReadFile(filepath.Join(localPath, "test", "compressed"))
+	require.NoError(t, err)
+	require.Less(t, len(stored), len(data)/10)
+
+	props, err := adapter.GetProperties(ctx, compressed)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), props.ContentLength)
+
+	// objects not marked compressed are read as is
+	raw := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "raw", IdentifierType: block.IdentifierTypeRelative}
+	require.NoError(t, localAdapter.Put(ctx, raw, int64(len(data)), bytes.NewReader(data), block.PutOpts{}))
+	unmarked := compressed
+	unmarked.Compression = ""
+	for _, tt := range []struct {
+		obj      block.ObjectPointer
+		expected []byte
+	}{
+		{compressed, data},
+		{raw, data},
+		{unmarked, stored},
+	} {
+		r, err := adapter.Get(ctx, tt.obj, 0)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, got)
+	}
+
+	// objects marked compressed must be compressed
+	marked := raw
+	marked.Compression = compression.CodecZstd
+	_, err = adapter.Get(ctx, marked, 0)
+	require.ErrorIs(t, err, compression.ErrInvalidFormat)
+
+	require.ErrorIs(t, adapter.Put(ctx, compressed, int64(len(data)), bytes.NewReader(data), block.PutOpts{Compression: "lz4"}), compression.ErrUnknownCodec)
+
+	// truncate the compressed object
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "test", "compressed"), stored[:len(stored)-30], 0o600))
+	r, err := adapter.Get(ctx, compressed, 0)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, compression.ErrInvalidFormat)
+	_, err = adapter.GetRange(ctx, compressed, 0, 10)
+	require.ErrorIs(t, err, compression.ErrInvalidFormat)
+}
+
+func TestCompressionAdapter_GetRange(t *testing.T) {
+	ctx := context.Background()
+	localAdapter, _ := newLocalAdapter(t)
+	adapter := compression.NewAdapter(localAdapter, compression.WithFrameSize(testFrameSize))
+
+	for _, codec := range compression.Codecs() {
+		// mix random and compressible data
+		data := make([]byte, 5*testFrameSize+100)
+		_, err := rand.Read(data[:2*testFrameSize])
+		require.NoError(t, err)
+		size := int64(len(data))
+		obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: codec, IdentifierType: block.IdentifierTypeRelative, Compression: codec}
+		require.NoError(t, adapter.Put(ctx, obj, size, bytes.NewReader(data), block.PutOpts{Compression: codec}))
+
+		cases := []struct {
+			name       string
+			start, end int64
+		}{
+			{"first_byte", 0, 0},
+			{"within_frame", 10, 100},
+			{"frame_boundary", testFrameSize - 1, testFrameSize},
+			{"across_frames", testFrameSize + 10, 4*testFrameSize + 30},
+			{"last_frame", 5 * testFrameSize, 5*testFrameSize + 10},
+			{"last_byte", size - 1, size - 1},
+			{"out_of_bounds", size - 5, size + 100},
+			{"all", 0, size - 1},
+		}
+		for _, tt := range cases {
+			t.Run(fmt.Sprintf("%s_%s", codec, tt.name), func(t *testing.T) {
+				r, err := adapter.GetRange(ctx, obj, tt.start, tt.end)
+				require.NoError(t, err)
+				got, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.NoError(t, r.Close())
+				require.Equal(t, data[tt.start:min(tt.end+1, size)], got)
+			})
+		}
+	}
+}
+
+// countingAdapter counts the requests reading objects
+type countingAdapter struct {
+	block.Adapter
+	requests int
+}
+
+func (a *countingAdapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	a.requests++
+	return a.Adapter.GetRange(ctx, obj, startPosition, endPosition)
+}
+
+func (a *countingAdapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (block.Properties, error) {
+	a.requests++
+	return a.Adapter.GetProperties(ctx, obj)
+}
+
+func TestCompressionAdapter_UncompressedRequests(t *testing.T) {
+	ctx := context.Background()
+	localAdapter, _ := newLocalAdapter(t)
+	counting := &countingAdapter{Adapter: localAdapter}
+	adapter := compression.NewAdapter(counting)
+	data := []byte("uncompressed data")
+	obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "raw", IdentifierType: block.IdentifierTypeRelative}
+	require.NoError(t, adapter.Put(ctx, obj, int64(len(data)), bytes.NewReader(data), block.PutOpts{}))
+
+	// objects not marked compressed are read by a single request
+	r, err := adapter.GetRange(ctx, obj, 2, 5)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, data[2:6], got)
+	require.Equal(t, 1, counting.requests)
+
+	props, err := adapter.GetProperties(ctx, obj)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), props.ContentLength)
+	require.Equal(t, 2, counting.requests)
+}
@@ -0,0 +1,129 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	CodecGzip = "gzip"
+	CodecZstd = "zstd"
+)
+
+// codec compresses and decompresses the frames of an object
+type codec interface {
+	id() byte
+	compress(dst, src []byte) ([]byte, error)
+	// decompress decompresses src, failing in case its data is larger than maxSize
+	decompress(dst, src []byte, maxSize int) ([]byte, error)
+}
+
+var ErrUnknownCodec = errors.New("unknown compression codec")
+
+// Codecs returns the names of the supported codecs
+func Codecs() []string {
+	return []string{CodecGzip, CodecZstd}
+}
+
+// IsValidCodec returns true in case name is a supported codec
+func IsValidCodec(name string) bool {
+	_, err := codecByName(name)
+	return err == nil
+}
+
+func codecByName(name string) (codec, error) {
+	switch name {
+	case CodecGzip:
+		return gzipCodec{}, nil
+	case CodecZstd:
+		return zstdCodec{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCodec, name)
+	}
+}
+
+func codecByID(id byte) (codec, error) {
+	switch id {
+	case gzipCodec{}.id():
+		return gzipCodec{}, nil
+	case zstdCodec{}.id():
+		return zstdCodec{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, id)
+	}
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) id() byte { return 1 }
+
+func (gzipCodec) compress(dst, src []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) decompress(dst, src []byte, maxSize int) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+	buf := bytes.NewBuffer(dst)
+	n, err := io.Copy(buf, io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+	if n > int64(maxSize) {
+		return nil, fmt.Errorf("%w: frame too large", ErrInvalidFormat)
+	}
+	return buf.Bytes(), nil
+}
+
+// the zstd encoder and decoder are safe for concurrent use of EncodeAll and DecodeAll
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(MaxFrameSize))
+	})
+)
+
+type zstdCodec struct{}
+
+func (zstdCodec) id() byte { return 2 }
+
+func (zstdCodec) compress(dst, src []byte) ([]byte, error) {
+	enc, err := zstdEncoder()
+	if err != nil {
+		return nil, err
+	}
+	return enc.EncodeAll(src, dst), nil
+}
+
+func (zstdCodec) decompress(dst, src []byte, maxSize int) ([]byte, error) {
+	dec, err := zstdDecoder()
+	if err != nil {
+		return nil, err
+	}
+	out, err := dec.DecodeAll(src, dst)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+	if len(out)-len(dst) > maxSize {
+		return nil, fmt.Errorf("%w: frame too large", ErrInvalidFormat)
+	}
+	return out, nil
+}
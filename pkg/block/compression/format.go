@@ -0,0 +1,236 @@
+package compression
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// A compressed object starts with a header, followed by the frames of its data. Each frame holds frameSize bytes
+// of data compressed separately, but the last frame which holds less. A zero length marks the end of the frames,
+// and is followed by a seek table holding the compressed length of every frame and a footer.
+//
+//	header:     magic(4) | version(1) | codec(1) | frame size(4) | header CRC(4)
+//	frame:      compressed length(4) | compressed data
+//	end:        zero length(4)
+//	seek table: compressed length(4) per frame
+//	footer:     seek table CRC(4) | frame count(4) | data size(8) | magic(4)
+//
+// Objects are read sequentially by following the frame lengths. A range is read by locating the footer using the
+// object size, and reading only the frames holding the range. Whether an object is compressed is marked on its
+// entry, the format is not used to detect compressed objects.
+
+const (
+	DefaultFrameSize = 1024 * 1024
+	MaxFrameSize     = 16 * 1024 * 1024
+
+	formatMagic   = "LFSZ"
+	formatVersion = 1
+
+	headerSize      = 14
+	footerSize      = 20
+	frameLengthSize = 4
+)
+
+type header struct {
+	codec     codec
+	frameSize int
+}
+
+func (h *header) encode() []byte {
+	buf := make([]byte, headerSize)
+	copy(buf, formatMagic)
+	buf[4] = formatVersion
+	buf[5] = h.codec.id()
+	binary.BigEndian.PutUint32(buf[6:10], uint32(h.frameSize))
+	binary.BigEndian.PutUint32(buf[10:14], crc32.ChecksumIEEE(buf[:10]))
+	return buf
+}
+
+// parseHeader returns the header encoded in buf
+func parseHeader(buf []byte) (*header, error) {
+	if len(buf) < headerSize || string(buf[:4]) != formatMagic ||
+		binary.BigEndian.Uint32(buf[10:14]) != crc32.ChecksumIEEE(buf[:10]) {
+		return nil, fmt.Errorf("%w: bad header", ErrInvalidFormat)
+	}
+	if buf[4] != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidFormat, buf[4])
+	}
+	c, err := codecByID(buf[5])
+	if err != nil {
+		return nil, err
+	}
+	frameSize := int(binary.BigEndian.Uint32(buf[6:10]))
+	if frameSize <= 0 || frameSize > MaxFrameSize {
+		return nil, fmt.Errorf("%w: bad frame size", ErrInvalidFormat)
+	}
+	return &header{codec: c, frameSize: frameSize}, nil
+}
+
+// index locates the frames of a compressed object
+type index struct {
+	header
+	dataSize int64
+	// offsets holds the offset of every frame, followed by the offset of the end of the frames
+	offsets []int64
+}
+
+func encodeFooter(lengths []uint32, dataSize int64) []byte {
+	buf := make([]byte, 0, len(lengths)*frameLengthSize+footerSize)
+	for _, l := range lengths {
+		buf = binary.BigEndian.AppendUint32(buf, l)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(lengths)))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(dataSize))
+	return append(buf, formatMagic...)
+}
+
+// parseFooter parses the footer, returning the CRC of the seek table, the frame count and the data size
+func parseFooter(buf []byte) (uint32, int, int64, error) {
+	if len(buf) != footerSize || string(buf[16:]) != formatMagic {
+		return 0, 0, 0, fmt.Errorf("%w: missing footer", ErrInvalidFormat)
+	}
+	return binary.BigEndian.Uint32(buf[:4]), int(binary.BigEndian.Uint32(buf[4:8])), int64(binary.BigEndian.Uint64(buf[8:16])), nil
+}
+
+// newIndex builds the index of an object of objectSize bytes from its seek table
+func newIndex(h *header, objectSize int64, table []byte, dataSize int64) (*index, error) {
+	count := len(table) / frameLengthSize
+	idx := &index{
+		header:   *h,
+		dataSize: dataSize,
+		offsets:  make([]int64, count+1),
+	}
+	idx.offsets[0] = headerSize
+	for i := 0; i < count; i++ {
+		l := binary.BigEndian.Uint32(table[i*frameLengthSize:])
+		if l == 0 {
+			return nil, fmt.Errorf("%w: bad seek table", ErrInvalidFormat)
+		}
+		idx.offsets[i+1] = idx.offsets[i] + frameLengthSize + int64(l)
+	}
+	framesEnd := idx.offsets[count] + frameLengthSize
+	if framesEnd+int64(len(table))+footerSize != objectSize ||
+		dataSize > int64(count)*int64(h.frameSize) || dataSize <= int64(count-1)*int64(h.frameSize) && count > 0 {
+		return nil, fmt.Errorf("%w: bad seek table", ErrInvalidFormat)
+	}
+	return idx, nil
+}
+
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: truncated object", ErrInvalidFormat)
+	}
+	return err
+}
+
+// compressReader reads data from src and returns it as a compressed object
+type compressReader struct {
+	src     io.Reader
+	h       *header
+	plain   []byte
+	buf     []byte
+	out     []byte
+	lengths []uint32
+	size    int64
+	done    bool
+}
+
+func newCompressReader(src io.Reader, h *header) *compressReader {
+	return &compressReader{
+		src:   src,
+		h:     h,
+		plain: make([]byte, h.frameSize),
+		out:   h.encode(),
+	}
+}
+
+func (r *compressReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *compressReader) nextFrame() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !last {
+		return err
+	}
+	r.buf = r.buf[:0]
+	if n > 0 {
+		r.buf = append(r.buf, 0, 0, 0, 0)
+		r.buf, err = r.h.codec.compress(r.buf, r.plain[:n])
+		if err != nil {
+			return err
+		}
+		length := uint32(len(r.buf) - frameLengthSize)
+		binary.BigEndian.PutUint32(r.buf, length)
+		r.lengths = append(r.lengths, length)
+		r.size += int64(n)
+	}
+	if last {
+		// end of frames, seek table and footer
+		r.buf = binary.BigEndian.AppendUint32(r.buf, 0)
+		r.buf = append(r.buf, encodeFooter(r.lengths, r.size)...)
+		r.done = true
+	}
+	r.out = r.buf
+	return nil
+}
+
+// frameDecoder decompresses consecutive frames read from src
+type frameDecoder struct {
+	src        io.Reader
+	h          *header
+	compressed []byte
+	plain      []byte
+	// lengths holds the lengths of the decoded frames
+	lengths []uint32
+	size    int64
+}
+
+// next returns the data of the next frame, valid until the following call, or io.EOF after the last frame
+func (d *frameDecoder) next() ([]byte, error) {
+	var length [frameLengthSize]byte
+	if _, err := io.ReadFull(d.src, length[:]); err != nil {
+		return nil, truncated(err)
+	}
+	l := binary.BigEndian.Uint32(length[:])
+	if l == 0 {
+		return nil, io.EOF
+	}
+	if int64(l) > int64(MaxFrameSize)*2 {
+		return nil, fmt.Errorf("%w: bad frame length", ErrInvalidFormat)
+	}
+	if cap(d.compressed) < int(l) {
+		d.compressed = make([]byte, l)
+	}
+	d.compressed = d.compressed[:l]
+	if _, err := io.ReadFull(d.src, d.compressed); err != nil {
+		return nil, truncated(err)
+	}
+	var err error
+	d.plain, err = d.h.codec.decompress(d.plain[:0], d.compressed, d.h.frameSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.plain) == 0 || d.size%int64(d.h.frameSize) != 0 {
+		// only the last frame may be partial
+		return nil, fmt.Errorf("%w: bad frame size", ErrInvalidFormat)
+	}
+	d.lengths = append(d.lengths, l)
+	d.size += int64(len(d.plain))
+	return d.plain, nil
+}
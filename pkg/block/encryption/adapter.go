@@ -0,0 +1,283 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid encrypted object")
+	ErrInvalidKey    = errors.New("invalid encryption key")
+	ErrUnknownKey    = errors.New("unknown encryption key")
+	ErrDecrypt       = errors.New("decrypt failed")
+	ErrSizeMismatch  = errors.New("size mismatch")
+)
+
+// Adapter encrypts the data of objects stored by an underlying adapter, using envelope encryption: each object is
+// encrypted with its own data key, which is stored with the object wrapped by a master key of the KeyProvider.
+// Objects are readable only through the Adapter, so pre-signed URLs and imports of external data are not
+// supported. ListParts reports the stored sizes of the encrypted parts.
+// Everything lakeFS stores through the Adapter is encrypted, including the range and metarange SSTs it keeps under
+// the _lakefs/ prefix of the storage namespace, so these are readable only through lakeFS as well.
+type Adapter struct {
+	adapter   block.Adapter
+	keys      KeyProvider
+	chunkSize int
+}
+
+type AdapterOption func(a *Adapter)
+
+// WithChunkSize sets the size of the chunks encrypted separately, reading a range decrypts only the chunks
+// holding it
+func WithChunkSize(chunkSize int) AdapterOption {
+	return func(a *Adapter) {
+		if chunkSize > 0 && chunkSize <= MaxChunkSize {
+			a.chunkSize = chunkSize
+		}
+	}
+}
+
+func NewAdapter(adapter block.Adapter, keys KeyProvider, opts ...AdapterOption) *Adapter {
+	a := &Adapter{
+		adapter:   adapter,
+		keys:      keys,
+		chunkSize: DefaultChunkSize,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *Adapter) Put(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
+	h, aead, err := newHeader(a.keys, a.chunkSize, sizeBytes)
+	if err != nil {
+		return err
+	}
+	return a.adapter.Put(ctx, obj, h.encryptedSize(), newEncryptReader(reader, h, aead), opts)
+}
+
+func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io.ReadCloser, error) {
+	// the stored size differs from the size of the data
+	body, err := a.adapter.Get(ctx, obj, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{
+		src:    streamSource{Reader: body},
+		closer: body,
+		keys:   a.keys,
+		end:    -1,
+	}, nil
+}
+
+func (a *Adapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	if startPosition < 0 || endPosition < startPosition {
+		return nil, block.ErrBadIndex
+	}
+	src := newRangeStream(ctx, a.adapter, obj)
+	r := &reader{
+		src:    src,
+		closer: src,
+		keys:   a.keys,
+		start:  startPosition,
+		end:    endPosition,
+	}
+	// read ahead to report a missing object or bad format on the call itself, as the underlying adapters do
+	if err := r.fill(); err != nil {
+		if !errors.Is(err, io.EOF) {
+			_ = src.Close()
+			return nil, err
+		}
+		r.err = err
+	}
+	return r, nil
+}
+
+func (a *Adapter) GetWalker(storageID string, uri *url.URL) (block.Walker, error) {
+	return a.adapter.GetWalker(storageID, uri)
+}
+
+func (a *Adapter) GetPreSignedURL(_ context.Context, _ block.ObjectPointer, _ block.PreSignMode) (string, time.Time, error) {
+	return "", time.Time{}, fmt.Errorf("encrypted blockstore: %w", block.ErrOperationNotSupported)
+}
+
+func (a *Adapter) GetPresignUploadPartURL(_ context.Context, _ block.ObjectPointer, _ string, _ int) (string, error) {
+	return "", fmt.Errorf("encrypted blockstore: %w", block.ErrOperationNotSupported)
+}
+
+func (a *Adapter) Exists(ctx context.Context, obj block.ObjectPointer) (bool, error) {
+	return a.adapter.Exists(ctx, obj)
+}
+
+// GetProperties reports the size of the data of an object stored as a single segment of known size, recorded in its
+// header, rather than its stored size. The data size of other objects, uploaded in parts or without a known size, is
+// reported as unknown (-1): finding it requires decrypting their segments, and the data size of objects uploaded in
+// parts is reported once by CompleteMultiPartUpload to be recorded on their entry.
+func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (block.Properties, error) {
+	props, err := a.adapter.GetProperties(ctx, obj)
+	if err != nil {
+		return block.Properties{}, err
+	}
+	props.ContentLength, err = a.segmentDataSize(ctx, obj, props.ContentLength)
+	if err != nil {
+		return block.Properties{}, err
+	}
+	return props, nil
+}
+
+// segmentDataSize returns the size of the data of an object of storedSize bytes, read from the header of its first
+// segment, or -1 in case the object is not a single segment of known size
+func (a *Adapter) segmentDataSize(ctx context.Context, obj block.ObjectPointer, storedSize int64) (int64, error) {
+	src := newRangeStream(ctx, a.adapter, obj)
+	defer func() {
+		_ = src.Close()
+	}()
+	src.Expect(maxHeaderSize)
+	h, err := readHeader(src)
+	if errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("%w: empty object", ErrInvalidFormat)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if h.size == unknownSize || h.encryptedSize() != storedSize {
+		return unknownSize, nil
+	}
+	return h.size, nil
+}
+
+func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
+	return a.adapter.Remove(ctx, obj)
+}
+
+// Copy copies the encrypted object as is, data keys are not bound to the object location
+func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.ObjectPointer) error {
+	return a.adapter.Copy(ctx, sourceObj, destinationObj)
+}
+
+func (a *Adapter) SetStorageClass(ctx context.Context, obj block.ObjectPointer, storageClass string) error {
+	return a.adapter.SetStorageClass(ctx, obj, storageClass)
+}
+
+func (a *Adapter) Restore(ctx context.Context, obj block.ObjectPointer, days int) error {
+	return a.adapter.Restore(ctx, obj, days)
+}
+
+func (a *Adapter) CreateMultiPartUpload(ctx context.Context, obj block.ObjectPointer, r *http.Request, opts block.CreateMultiPartUploadOpts) (*block.CreateMultiPartUploadResponse, error) {
+	return a.adapter.CreateMultiPartUpload(ctx, obj, r, opts)
+}
+
+func (a *Adapter) UploadPart(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+	h, aead, err := newHeader(a.keys, a.chunkSize, sizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return a.adapter.UploadPart(ctx, obj, h.encryptedSize(), newEncryptReader(reader, h, aead), uploadID, partNumber)
+}
+
+func (a *Adapter) ListParts(ctx context.Context, obj block.ObjectPointer, uploadID string, opts block.ListPartsOpts) (*block.ListPartsResponse, error) {
+	return a.adapter.ListParts(ctx, obj, uploadID, opts)
+}
+
+// UploadCopyPart copies the encrypted source object as is, its segments become segments of the uploaded object
+func (a *Adapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj block.ObjectPointer, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+	return a.adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
+}
+
+// UploadCopyPartRange decrypts the range of the source object and uploads it as a new encrypted part
+func (a *Adapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj block.ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*block.UploadPartResponse, error) {
+	r, err := a.GetRange(ctx, sourceObj, startPosition, endPosition)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	return a.UploadPart(ctx, destinationObj, endPosition-startPosition+1, r, uploadID, partNumber)
+}
+
+func (a *Adapter) AbortMultiPartUpload(ctx context.Context, obj block.ObjectPointer, uploadID string) error {
+	return a.adapter.AbortMultiPartUpload(ctx, obj, uploadID)
+}
+
+func (a *Adapter) CompleteMultiPartUpload(ctx context.Context, obj block.ObjectPointer, uploadID string, multipartList *block.MultipartUploadCompletion) (*block.CompleteMultiPartUploadResponse, error) {
+	resp, err := a.adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+	if err != nil {
+		return nil, err
+	}
+	// the data size is not recorded on an object uploaded in parts, it is computed once here to be recorded on its
+	// entry
+	size, err := a.dataSize(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("completed object size: %w", err)
+	}
+	resp.ContentLength = size
+	return resp, nil
+}
+
+// dataSize returns the size of the data of an encrypted object, by reading its segment headers. Segments written
+// without a known size are decrypted to find their size.
+func (a *Adapter) dataSize(ctx context.Context, obj block.ObjectPointer) (int64, error) {
+	src := newRangeStream(ctx, a.adapter, obj)
+	defer func() {
+		_ = src.Close()
+	}()
+	var size int64
+	for {
+		src.Expect(maxHeaderSize)
+		h, err := readHeader(src)
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if h.size != unknownSize {
+			size += h.size
+			if err := src.Skip(h.bodySize()); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		dec, err := newSegmentDecoder(src, a.keys, h, 0)
+		if err != nil {
+			return 0, err
+		}
+		for !dec.done {
+			plain, err := dec.next()
+			if err != nil {
+				return 0, err
+			}
+			size += int64(len(plain))
+		}
+	}
+}
+
+func (a *Adapter) BlockstoreType() string {
+	return a.adapter.BlockstoreType()
+}
+
+func (a *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
+	info := a.adapter.GetStorageNamespaceInfo()
+	info.PreSignSupport = false
+	info.PreSignSupportUI = false
+	info.PreSignSupportMultipart = false
+	info.ImportSupport = false
+	return info
+}
+
+func (a *Adapter) ResolveNamespace(storageID, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+	return a.adapter.ResolveNamespace(storageID, storageNamespace, key, identifierType)
+}
+
+func (a *Adapter) RuntimeStats() map[string]string {
+	return a.adapter.RuntimeStats()
+}
@@ -0,0 +1,219 @@
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/blocktest"
+	"github.com/treeverse/lakefs/pkg/block/encryption"
+	"github.com/treeverse/lakefs/pkg/block/local"
+)
+
+const (
+	testStorageNamespace = "local://test"
+	testChunkSize        = 1024
+)
+
+func newTestKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, encryption.KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func newLocalAdapter(t *testing.T) (*local.Adapter, string) {
+	t.Helper()
+	localPath := path.Join(t.TempDir(), "lakefs")
+	adapter, err := local.NewAdapter(localPath, local.WithRemoveEmptyDir(false))
+	require.NoError(t, err)
+	return adapter, localPath
+}
+
+func newTestAdapter(t *testing.T, adapter block.Adapter, opts ...encryption.AdapterOption) *encryption.Adapter {
+	t.Helper()
+	keys, err := encryption.NewStaticKeyProvider(map[string][]byte{"key": newTestKey(t)}, "key")
+	require.NoError(t, err)
+	return encryption.NewAdapter(adapter, keys, opts...)
+}
+
+func TestEncryptionAdapter(t *testing.T) {
+	tmpDir := t.TempDir()
+	localPath := path.Join(tmpDir, "lakefs")
+	externalPath := block.BlockstoreTypeLocal + "://" + path.Join(tmpDir, "lakefs", "external")
+	adapter, err := local.NewAdapter(localPath, local.WithRemoveEmptyDir(false))
+	require.NoError(t, err)
+	blocktest.AdapterTest(t, newTestAdapter(t, adapter), testStorageNamespace, externalPath)
+}
+
+func TestEncryptionAdapter_StoredEncrypted(t *testing.T) {
+	ctx := context.Background()
+	localAdapter, localPath := newLocalAdapter(t)
+	adapter := newTestAdapter(t, localAdapter)
+	data := bytes.Repeat([]byte("plaintext data "), 100)
+	obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	require.NoError(t, adapter.Put(ctx, obj, int64(len(data)), bytes.NewReader(data), block.PutOpts{}))
+
+	stored, err := os.ReadFile(filepath.Join(localPath, "test", "obj"))
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(stored, []byte("plaintext")), "stored object holds plaintext")
+
+	// flip a byte of the last chunk
+	stored[len(stored)-1] ^= 1
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "test", "obj"), stored, 0o600))
+	r, err := adapter.Get(ctx, obj, 0)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, encryption.ErrDecrypt)
+
+	// truncate the object
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "test", "obj"), stored[:len(stored)-20], 0o600))
+	r, err = adapter.Get(ctx, obj, 0)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, encryption.ErrInvalidFormat)
+}
+
+func TestEncryptionAdapter_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	localAdapter, _ := newLocalAdapter(t)
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	oldKeys, err := encryption.NewStaticKeyProvider(map[string][]byte{"old": oldKey}, "old")
+	require.NoError(t, err)
+	rotatedKeys, err := encryption.NewStaticKeyProvider(map[string][]byte{"old": oldKey, "new": newKey}, "new")
+	require.NoError(t, err)
+	newKeys, err := encryption.NewStaticKeyProvider(map[string][]byte{"new": newKey}, "new")
+	require.NoError(t, err)
+
+	const data = "data written before rotation"
+	obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	require.NoError(t, encryption.NewAdapter(localAdapter, oldKeys).Put(ctx, obj, int64(len(data)), bytes.NewReader([]byte(data)), block.PutOpts{}))
+
+	r, err := encryption.NewAdapter(localAdapter, rotatedKeys).Get(ctx, obj, 0)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, data, string(got))
+
+	r, err = encryption.NewAdapter(localAdapter, newKeys).Get(ctx, obj, 0)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, encryption.ErrUnknownKey)
+}
+
+func TestEncryptionAdapter_GetRange(t *testing.T) {
+	ctx := context.Background()
+	localAdapter, _ := newLocalAdapter(t)
+	adapter := newTestAdapter(t, localAdapter, encryption.WithChunkSize(testChunkSize))
+
+	// parts of different sizes, one of them uploaded without a known size
+	partSizes := []int{3*testChunkSize + 17, testChunkSize, 0, 2*testChunkSize - 1}
+	var (
+		data  []byte
+		parts []block.MultipartPart
+	)
+	obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "multipart", IdentifierType: block.IdentifierTypeRelative}
+	resp, err := adapter.CreateMultiPartUpload(ctx, obj, nil, block.CreateMultiPartUploadOpts{})
+	require.NoError(t, err)
+	for i, partSize := range partSizes {
+		part := make([]byte, partSize)
+		_, err := rand.Read(part)
+		require.NoError(t, err)
+		size := int64(partSize)
+		if i == 1 {
+			size = -1
+		}
+		partResp, err := adapter.UploadPart(ctx, obj, size, bytes.NewReader(part), resp.UploadID, i+1)
+		require.NoError(t, err)
+		parts = append(parts, block.MultipartPart{PartNumber: i + 1, ETag: partResp.ETag})
+		data = append(data, part...)
+	}
+	completeResp, err := adapter.CompleteMultiPartUpload(ctx, obj, resp.UploadID, &block.MultipartUploadCompletion{Part: parts})
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), completeResp.ContentLength)
+
+	r, err := adapter.Get(ctx, obj, 0)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	size := int64(len(data))
+	cases := []struct {
+		name       string
+		start, end int64
+	}{
+		{"first_byte", 0, 0},
+		{"within_chunk", 10, 100},
+		{"chunk_boundary", testChunkSize - 1, testChunkSize},
+		{"across_parts", 3*testChunkSize + 10, 4*testChunkSize + 30},
+		{"unknown_size_part", 3*testChunkSize + 17, 4*testChunkSize + 16},
+		{"after_empty_part", 4*testChunkSize + 17, 4*testChunkSize + 20},
+		{"last_byte", size - 1, size - 1},
+		{"out_of_bounds", size - 5, size + 100},
+		{"all", 0, size - 1},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := adapter.GetRange(ctx, obj, tt.start, tt.end)
+			require.NoError(t, err)
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			require.Equal(t, data[tt.start:min(tt.end+1, size)], got)
+		})
+	}
+}
+
+func TestEncryptionAdapter_GetProperties(t *testing.T) {
+	ctx := context.Background()
+	localAdapter, _ := newLocalAdapter(t)
+	adapter := newTestAdapter(t, localAdapter, encryption.WithChunkSize(testChunkSize))
+	data := make([]byte, 3*testChunkSize+17)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		size         int64
+		expectedSize int64
+	}{
+		{"known_size", int64(len(data)), int64(len(data))},
+		{"unknown_size", -1, -1},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: tt.name, IdentifierType: block.IdentifierTypeRelative}
+			require.NoError(t, adapter.Put(ctx, obj, tt.size, bytes.NewReader(data), block.PutOpts{}))
+			props, err := adapter.GetProperties(ctx, obj)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSize, props.ContentLength)
+		})
+	}
+
+	t.Run("multipart", func(t *testing.T) {
+		obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "multipart", IdentifierType: block.IdentifierTypeRelative}
+		resp, err := adapter.CreateMultiPartUpload(ctx, obj, nil, block.CreateMultiPartUploadOpts{})
+		require.NoError(t, err)
+		var parts []block.MultipartPart
+		for i := 0; i < 2; i++ {
+			partResp, err := adapter.UploadPart(ctx, obj, int64(len(data)), bytes.NewReader(data), resp.UploadID, i+1)
+			require.NoError(t, err)
+			parts = append(parts, block.MultipartPart{PartNumber: i + 1, ETag: partResp.ETag})
+		}
+		completeResp, err := adapter.CompleteMultiPartUpload(ctx, obj, resp.UploadID, &block.MultipartUploadCompletion{Part: parts})
+		require.NoError(t, err)
+		require.Equal(t, int64(2*len(data)), completeResp.ContentLength)
+		props, err := adapter.GetProperties(ctx, obj)
+		require.NoError(t, err)
+		require.Equal(t, int64(-1), props.ContentLength)
+	})
+}
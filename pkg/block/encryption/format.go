@@ -0,0 +1,272 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// An encrypted object is a sequence of segments. A simple upload writes a single segment, while each part of a
+// multipart upload is a segment of its own, as parts are encrypted independently.
+//
+// Each segment starts with a header holding its data key, wrapped by a master key, followed by the encrypted
+// chunks of its data. Each chunk is encrypted with AES-GCM using the data key, the chunk index as nonce and the
+// segment header as additional data. Every chunk but the last holds exactly chunkSize bytes of data, the last chunk
+// holds less (possibly none), which marks the end of the segment and detects truncation.
+//
+//	header: magic(4) | version(1) | chunk size(4) | data size(8) | key ID length(1) | key ID | wrapped key length(2) | wrapped key
+//	chunk:  sealed length(4) | sealed data
+//
+// The data size is -1 when unknown at the time of writing. Chunk offsets are computed from the chunk size, which
+// allows decrypting a range of the data without reading the preceding chunks.
+
+const (
+	DefaultChunkSize = 64 * 1024
+	MaxChunkSize     = 16 * 1024 * 1024
+
+	formatMagic   = "LFSE"
+	formatVersion = 1
+
+	headerFixedSize     = 18
+	maxKeyIDLength      = 255
+	maxWrappedKeyLength = 512
+	maxHeaderSize       = headerFixedSize + maxKeyIDLength + 2 + maxWrappedKeyLength
+
+	chunkLengthSize = 4
+	tagSize         = 16
+	nonceSize       = 12
+
+	unknownSize = -1
+)
+
+type header struct {
+	chunkSize  int
+	size       int64
+	keyID      string
+	wrappedKey []byte
+	// raw is the encoded header, authenticated with every chunk of the segment
+	raw []byte
+}
+
+// newHeader generates a data key for a new segment holding size bytes of data, and returns the segment header and
+// the cipher encrypting the segment chunks
+func newHeader(keys KeyProvider, chunkSize int, size int64) (*header, cipher.AEAD, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyID, wrappedKey, err := keys.WrapKey(dataKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("wrap data key: %w", err)
+	}
+	if len(keyID) > maxKeyIDLength || len(wrappedKey) > maxWrappedKeyLength {
+		return nil, nil, fmt.Errorf("wrapped data key too long: %w", ErrInvalidKey)
+	}
+	if size < 0 {
+		size = unknownSize
+	}
+	h := &header{
+		chunkSize:  chunkSize,
+		size:       size,
+		keyID:      keyID,
+		wrappedKey: wrappedKey,
+	}
+	h.raw = h.encode()
+	return h, aead, nil
+}
+
+func (h *header) encode() []byte {
+	buf := make([]byte, headerFixedSize, headerFixedSize+len(h.keyID)+2+len(h.wrappedKey))
+	copy(buf, formatMagic)
+	buf[4] = formatVersion
+	binary.BigEndian.PutUint32(buf[5:9], uint32(h.chunkSize))
+	binary.BigEndian.PutUint64(buf[9:17], uint64(h.size))
+	buf[17] = byte(len(h.keyID))
+	buf = append(buf, h.keyID...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(h.wrappedKey)))
+	return append(buf, h.wrappedKey...)
+}
+
+// readHeader reads a segment header from r, returns io.EOF in case r holds no more segments
+func readHeader(r io.Reader) (*header, error) {
+	fixed := make([]byte, headerFixedSize)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, truncated(err)
+	}
+	if string(fixed[:4]) != formatMagic {
+		return nil, fmt.Errorf("%w: missing encryption header", ErrInvalidFormat)
+	}
+	if fixed[4] != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidFormat, fixed[4])
+	}
+	h := &header{
+		chunkSize: int(binary.BigEndian.Uint32(fixed[5:9])),
+		size:      int64(binary.BigEndian.Uint64(fixed[9:17])),
+	}
+	if h.chunkSize <= 0 || h.chunkSize > MaxChunkSize || h.size < unknownSize {
+		return nil, fmt.Errorf("%w: bad header", ErrInvalidFormat)
+	}
+	keyID := make([]byte, int(fixed[17])+2)
+	if _, err := io.ReadFull(r, keyID); err != nil {
+		return nil, truncated(err)
+	}
+	h.keyID = string(keyID[:len(keyID)-2])
+	h.wrappedKey = make([]byte, binary.BigEndian.Uint16(keyID[len(keyID)-2:]))
+	if _, err := io.ReadFull(r, h.wrappedKey); err != nil {
+		return nil, truncated(err)
+	}
+	h.raw = h.encode()
+	return h, nil
+}
+
+// chunkStride is the encrypted size of a full chunk
+func (h *header) chunkStride() int64 {
+	return int64(chunkLengthSize + h.chunkSize + tagSize)
+}
+
+// bodySize is the encrypted size of the segment chunks, valid only for segments of a known size
+func (h *header) bodySize() int64 {
+	chunks := h.size/int64(h.chunkSize) + 1
+	return chunks*(chunkLengthSize+tagSize) + h.size
+}
+
+// encryptedSize is the size of the segment, or -1 if the size of its data is unknown
+func (h *header) encryptedSize() int64 {
+	if h.size == unknownSize {
+		return unknownSize
+	}
+	return int64(len(h.raw)) + h.bodySize()
+}
+
+func chunkNonce(index uint64) []byte {
+	nonce := make([]byte, nonceSize)
+	binary.BigEndian.PutUint64(nonce[nonceSize-8:], index)
+	return nonce
+}
+
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: truncated segment", ErrInvalidFormat)
+	}
+	return err
+}
+
+// encryptReader reads data from src and returns it as a single encrypted segment
+type encryptReader struct {
+	src   io.Reader
+	h     *header
+	aead  cipher.AEAD
+	index uint64
+	read  int64
+	plain []byte
+	buf   []byte
+	out   []byte
+	done  bool
+}
+
+func newEncryptReader(src io.Reader, h *header, aead cipher.AEAD) *encryptReader {
+	return &encryptReader{
+		src:   src,
+		h:     h,
+		aead:  aead,
+		plain: make([]byte, h.chunkSize),
+		buf:   make([]byte, h.chunkStride()),
+		out:   h.raw,
+	}
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *encryptReader) sealChunk() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !last {
+		return err
+	}
+	r.read += int64(n)
+	if r.h.size != unknownSize && (r.read > r.h.size || last && r.read != r.h.size) {
+		return fmt.Errorf("%w: read %d bytes, expected %d", ErrSizeMismatch, r.read, r.h.size)
+	}
+	sealed := r.aead.Seal(r.buf[chunkLengthSize:chunkLengthSize], chunkNonce(r.index), r.plain[:n], r.h.raw)
+	binary.BigEndian.PutUint32(r.buf, uint32(len(sealed)))
+	r.out = r.buf[:chunkLengthSize+len(sealed)]
+	r.index++
+	r.done = last
+	return nil
+}
+
+// segmentDecoder decrypts the chunks of a single segment, starting at chunk index
+type segmentDecoder struct {
+	src   io.Reader
+	h     *header
+	aead  cipher.AEAD
+	index uint64
+	buf   []byte
+	done  bool
+}
+
+func newSegmentDecoder(src io.Reader, keys KeyProvider, h *header, index uint64) (*segmentDecoder, error) {
+	dataKey, err := keys.UnwrapKey(h.keyID, h.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &segmentDecoder{
+		src:   src,
+		h:     h,
+		aead:  aead,
+		index: index,
+		buf:   make([]byte, h.chunkStride()-chunkLengthSize),
+	}, nil
+}
+
+// next returns the data of the next chunk, valid until the following call
+func (d *segmentDecoder) next() ([]byte, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+	var length [chunkLengthSize]byte
+	if _, err := io.ReadFull(d.src, length[:]); err != nil {
+		return nil, truncated(err)
+	}
+	sealedSize := int(binary.BigEndian.Uint32(length[:]))
+	if sealedSize < tagSize || sealedSize > len(d.buf) {
+		return nil, fmt.Errorf("%w: bad chunk length", ErrInvalidFormat)
+	}
+	sealed := d.buf[:sealedSize]
+	if _, err := io.ReadFull(d.src, sealed); err != nil {
+		return nil, truncated(err)
+	}
+	plain, err := d.aead.Open(sealed[:0], chunkNonce(d.index), sealed, d.h.raw)
+	if err != nil {
+		return nil, fmt.Errorf("chunk %d: %w", d.index, ErrDecrypt)
+	}
+	d.index++
+	d.done = sealedSize < len(d.buf)
+	return plain, nil
+}
@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// KeySize is the size of master keys and data keys, both are AES-256 keys
+const KeySize = 32
+
+// KeyProvider wraps the data key of each encrypted object using a master key. Only the wrapped data key is stored
+// with the object, the master keys never leave the provider.
+type KeyProvider interface {
+	// WrapKey encrypts dataKey using the current master key, and returns the ID of the master key used
+	WrapKey(dataKey []byte) (keyID string, wrappedKey []byte, err error)
+	// UnwrapKey decrypts a data key wrapped by the master key identified by keyID
+	UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// StaticKeyProvider wraps data keys with AES-GCM using master keys set on creation. Keys other than the current
+// key are used only to unwrap data keys of existing objects, which allows rotating the master key.
+type StaticKeyProvider struct {
+	keyID string
+	keys  map[string]cipher.AEAD
+}
+
+// NewStaticKeyProvider returns a provider wrapping new data keys using the key identified by keyID
+func NewStaticKeyProvider(keys map[string][]byte, keyID string) (*StaticKeyProvider, error) {
+	if _, ok := keys[keyID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	p := &StaticKeyProvider{
+		keyID: keyID,
+		keys:  make(map[string]cipher.AEAD, len(keys)),
+	}
+	for id, key := range keys {
+		if len(id) > maxKeyIDLength {
+			return nil, fmt.Errorf("key ID '%s' longer than %d bytes: %w", id, maxKeyIDLength, ErrInvalidKey)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key '%s': %w", id, err)
+		}
+		p.keys[id] = aead
+	}
+	return p, nil
+}
+
+func (p *StaticKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+	aead := p.keys[p.keyID]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", nil, err
+	}
+	return p.keyID, aead.Seal(nonce, nonce, dataKey, []byte(p.keyID)), nil
+}
+
+func (p *StaticKeyProvider) UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, ErrInvalidFormat
+	}
+	nonce, sealed := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", ErrDecrypt)
+	}
+	return dataKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key size %d, expected %d: %w", len(key), KeySize, ErrInvalidKey)
+	}
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(c)
+}
@@ -0,0 +1,226 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+const minFetchSize = 64 * 1024
+
+// segmentSource is the stream of encrypted segments read by a reader
+type segmentSource interface {
+	io.Reader
+	// Skip moves n bytes forward without reading them
+	Skip(n int64) error
+	// Expect hints that the next n bytes are about to be read
+	Expect(n int64)
+}
+
+// streamSource reads the segments of a complete object
+type streamSource struct {
+	io.Reader
+}
+
+func (s streamSource) Skip(n int64) error {
+	_, err := io.CopyN(io.Discard, s.Reader, n)
+	return truncated(err)
+}
+
+func (s streamSource) Expect(int64) {}
+
+// rangeStream reads an object from an offset using ranged reads, and skips forward by starting a new range. Each
+// range starts one byte before the offset, which keeps it within the object also when positioned at its end.
+type rangeStream struct {
+	ctx     context.Context
+	adapter block.Adapter
+	obj     block.ObjectPointer
+	pos     int64
+	expect  int64
+	end     int64
+	body    io.ReadCloser
+	eof     bool
+}
+
+func newRangeStream(ctx context.Context, adapter block.Adapter, obj block.ObjectPointer) *rangeStream {
+	return &rangeStream{
+		ctx:     ctx,
+		adapter: adapter,
+		obj:     obj,
+	}
+}
+
+func (s *rangeStream) Read(p []byte) (int, error) {
+	for {
+		if s.eof {
+			return 0, io.EOF
+		}
+		if s.body == nil {
+			if err := s.fetch(); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		n, err := s.body.Read(p)
+		s.pos += int64(n)
+		if errors.Is(err, io.EOF) {
+			s.closeBody()
+			// the object ended in case the range ended early
+			s.eof = s.pos <= s.end
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+func (s *rangeStream) fetch() error {
+	from := s.pos
+	if from > 0 {
+		from--
+	}
+	s.end = s.pos + max(s.expect-s.pos, minFetchSize) - 1
+	body, err := s.adapter.GetRange(s.ctx, s.obj, from, s.end)
+	if err != nil {
+		return err
+	}
+	if from < s.pos {
+		var b [1]byte
+		if _, err := io.ReadFull(body, b[:]); err != nil {
+			_ = body.Close()
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+			s.eof = true
+			return nil
+		}
+	}
+	s.body = body
+	return nil
+}
+
+func (s *rangeStream) Skip(n int64) error {
+	if n == 0 {
+		return nil
+	}
+	if s.body != nil && s.pos+n <= s.end+1 && n < minFetchSize {
+		_, err := io.CopyN(io.Discard, s, n)
+		return truncated(err)
+	}
+	s.closeBody()
+	s.pos += n
+	s.eof = false
+	return nil
+}
+
+func (s *rangeStream) Expect(n int64) {
+	s.expect = s.pos + n
+}
+
+func (s *rangeStream) closeBody() {
+	if s.body != nil {
+		_ = s.body.Close()
+		s.body = nil
+	}
+}
+
+func (s *rangeStream) Close() error {
+	s.closeBody()
+	return nil
+}
+
+// reader decrypts the data range [start, end] of an encrypted object, an end of -1 reads up to the end of the
+// object. Segments and chunks before the range are skipped without reading them, when their size is known.
+type reader struct {
+	src          segmentSource
+	closer       io.Closer
+	keys         KeyProvider
+	start        int64
+	end          int64
+	offset       int64
+	segmentStart int64
+	dec          *segmentDecoder
+	pending      []byte
+	err          error
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.fill()
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *reader) Close() error {
+	return r.closer.Close()
+}
+
+// fill decrypts the next chunk and sets the part of it within the range as pending
+func (r *reader) fill() error {
+	if r.end >= 0 && r.offset > r.end {
+		return io.EOF
+	}
+	if r.dec == nil {
+		if err := r.nextSegment(); err != nil || r.dec == nil {
+			return err
+		}
+	}
+	chunkStart := r.offset
+	plain, err := r.dec.next()
+	if err != nil {
+		return err
+	}
+	r.offset += int64(len(plain))
+	if r.dec.done {
+		if r.dec.h.size != unknownSize && r.offset-r.segmentStart != r.dec.h.size {
+			return ErrSizeMismatch
+		}
+		r.dec = nil
+	}
+	lo := max(r.start-chunkStart, 0)
+	hi := int64(len(plain))
+	if r.end >= 0 {
+		hi = min(hi, r.end+1-chunkStart)
+	}
+	if lo < hi {
+		r.pending = plain[lo:hi]
+	}
+	return nil
+}
+
+// nextSegment reads the next segment header, and either skips the segment in case it ends before the range or
+// positions a decoder at the first chunk within the range
+func (r *reader) nextSegment() error {
+	r.src.Expect(maxHeaderSize)
+	h, err := readHeader(r.src)
+	if err != nil {
+		return err
+	}
+	r.segmentStart = r.offset
+	if h.size != unknownSize && r.offset+h.size <= r.start {
+		r.offset += h.size
+		return r.src.Skip(h.bodySize())
+	}
+	var first int64
+	if h.size != unknownSize && r.start > r.offset {
+		first = (r.start - r.offset) / int64(h.chunkSize)
+	}
+	if err := r.src.Skip(first * h.chunkStride()); err != nil {
+		return err
+	}
+	r.offset += first * int64(h.chunkSize)
+	if r.end >= 0 {
+		last := (r.end - r.offset) / int64(h.chunkSize)
+		r.src.Expect((last+1)*h.chunkStride() + maxHeaderSize)
+	}
+	r.dec, err = newSegmentDecoder(r.src, r.keys, h, uint64(first))
+	return err
+}
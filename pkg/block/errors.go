@@ -10,4 +10,6 @@ var (
 	ErrForbidden             = errors.New("forbidden")
 	ErrInvalidAddress        = errors.New("invalid address")
 	ErrInvalidNamespace      = errors.New("invalid namespace")
+	ErrUnknownStorageID      = errors.New("unknown storage ID")
+	ErrRestoreRequired       = errors.New("object is archived, restore required")
 )
@@ -8,9 +8,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/block/azure"
+	"github.com/treeverse/lakefs/pkg/block/compression"
+	"github.com/treeverse/lakefs/pkg/block/encryption"
 	"github.com/treeverse/lakefs/pkg/block/gs"
 	"github.com/treeverse/lakefs/pkg/block/local"
 	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/block/multi"
 	"github.com/treeverse/lakefs/pkg/block/params"
 	s3a "github.com/treeverse/lakefs/pkg/block/s3"
 	"github.com/treeverse/lakefs/pkg/block/transient"
@@ -65,6 +68,66 @@ func BuildBlockAdapter(ctx context.Context, statsCollector stats.Collector, c pa
 	}
 }
 
+// BuildStorageAdapter builds an adapter serving all the configured blockstores, the default blockstore first. A
+// single blockstore without an ID is served by its own adapter, otherwise objects are routed by their storage ID.
+func BuildStorageAdapter(ctx context.Context, statsCollector stats.Collector, configs []params.StorageConfig) (block.Adapter, error) {
+	storages := make([]block.Storage, 0, len(configs))
+	for _, c := range configs {
+		adapter, err := buildStorageAdapter(ctx, statsCollector, c)
+		if err != nil {
+			return nil, fmt.Errorf("blockstore '%s': %w", c.BlockstoreID(), err)
+		}
+		storages = append(storages, block.Storage{
+			ID:          c.BlockstoreID(),
+			Description: c.BlockstoreDescription(),
+			Adapter:     adapter,
+		})
+	}
+	if len(storages) == 1 && storages[0].ID == "" {
+		return storages[0].Adapter, nil
+	}
+	return multi.NewAdapter(storages)
+}
+
+// buildStorageAdapter builds the adapter of a single blockstore, compressing and encrypting its data if configured.
+// Data is compressed before it is encrypted.
+func buildStorageAdapter(ctx context.Context, statsCollector stats.Collector, c params.StorageConfig) (block.Adapter, error) {
+	adapter, err := buildEncryptedAdapter(ctx, statsCollector, c)
+	if err != nil {
+		return nil, err
+	}
+	p := c.BlockstoreCompressionParams()
+	if !p.Enabled {
+		return adapter, nil
+	}
+	logging.FromContext(ctx).
+		WithFields(logging.Fields{"type": adapter.BlockstoreType()}).
+		Info("initialized blockstore compression")
+	return compression.NewAdapter(adapter, compression.WithFrameSize(p.FrameSize)), nil
+}
+
+func buildEncryptedAdapter(ctx context.Context, statsCollector stats.Collector, c params.StorageConfig) (block.Adapter, error) {
+	adapter, err := BuildBlockAdapter(ctx, statsCollector, c)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.BlockstoreEncryptionParams()
+	if err != nil {
+		return nil, err
+	}
+	if !p.Enabled {
+		return adapter, nil
+	}
+	keys, err := encryption.NewStaticKeyProvider(p.Keys, p.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("blockstore encryption: %w", err)
+	}
+	logging.FromContext(ctx).
+		WithFields(logging.Fields{"type": adapter.BlockstoreType(), "key_id": p.KeyID}).
+		Info("initialized blockstore encryption")
+	return encryption.NewAdapter(adapter, keys, encryption.WithChunkSize(p.ChunkSize)), nil
+}
+
 func buildLocalAdapter(ctx context.Context, params params.Local) (*local.Adapter, error) {
 	adapter, err := local.NewAdapter(params.Path,
 		local.WithAllowedExternalPrefixes(params.AllowedExternalPrefixes),
@@ -122,7 +122,7 @@ func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io
 	return r, nil
 }
 
-func (a *Adapter) GetWalker(uri *url.URL) (block.Walker, error) {
+func (a *Adapter) GetWalker(_ string, uri *url.URL) (block.Walker, error) {
 	if err := block.ValidateStorageType(uri, block.StorageTypeGS); err != nil {
 		return nil, err
 	}
@@ -206,10 +206,11 @@ func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (b
 	if err != nil {
 		return props, err
 	}
-	_, err = a.client.Bucket(bucket).Object(key).Attrs(ctx)
+	attrs, err := a.client.Bucket(bucket).Object(key).Attrs(ctx)
 	if err != nil {
 		return props, err
 	}
+	props.ContentLength = attrs.Size
 	return props, nil
 }
 
@@ -247,6 +248,32 @@ func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.Obje
 	return nil
 }
 
+// SetStorageClass rewrites obj with the new storage class, the only way to change the storage class of an object
+func (a *Adapter) SetStorageClass(ctx context.Context, obj block.ObjectPointer, storageClass string) error {
+	var err error
+	defer reportMetrics("SetStorageClass", time.Now(), nil, &err)
+	bucket, key, err := a.extractParamsFromObj(obj)
+	if err != nil {
+		return err
+	}
+	handle := a.client.Bucket(bucket).Object(key)
+	copier := handle.CopierFrom(handle)
+	copier.StorageClass = storageClass
+	_, err = copier.Run(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return block.ErrDataNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("set storage class: %w", err)
+	}
+	return nil
+}
+
+// Restore is a no-op, objects of every storage class are readable
+func (a *Adapter) Restore(_ context.Context, _ block.ObjectPointer, _ int) error {
+	return nil
+}
+
 func (a *Adapter) CreateMultiPartUpload(ctx context.Context, obj block.ObjectPointer, _ *http.Request, _ block.CreateMultiPartUploadOpts) (*block.CreateMultiPartUploadResponse, error) {
 	var err error
 	defer reportMetrics("CreateMultiPartUpload", time.Now(), nil, &err)
@@ -548,7 +575,7 @@ func (a *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
 }
 
 func (a *Adapter) extractParamsFromObj(obj block.ObjectPointer) (string, string, error) {
-	qk, err := a.ResolveNamespace(obj.StorageNamespace, obj.Identifier, obj.IdentifierType)
+	qk, err := a.ResolveNamespace(obj.StorageID, obj.StorageNamespace, obj.Identifier, obj.IdentifierType)
 	if err != nil {
 		return "", "", err
 	}
@@ -560,7 +587,7 @@ func (a *Adapter) extractParamsFromObj(obj block.ObjectPointer) (string, string,
 	return bucket, key, nil
 }
 
-func (a *Adapter) ResolveNamespace(storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+func (a *Adapter) ResolveNamespace(_, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
 	qualifiedKey, err := block.DefaultResolveNamespace(storageNamespace, key, identifierType)
 	if err != nil {
 		return qualifiedKey, err
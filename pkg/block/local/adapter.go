@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"net/url"
@@ -23,19 +24,33 @@ import (
 	"golang.org/x/exp/slices"
 )
 
-const DefaultNamespacePrefix = block.BlockstoreTypeLocal + "://"
+const (
+	DefaultNamespacePrefix = block.BlockstoreTypeLocal + "://"
 
+	// tempFileSuffix ends the names of temporary files written before being renamed to their final name. Their
+	// names start with a dot, hiding them from the walker.
+	tempFileSuffix = ".tmp"
+	// mkdirAttempts bounds the attempts to create a file in a directory removed concurrently as empty
+	mkdirAttempts = 3
+)
+
+// Adapter stores objects as files under a directory, which may be on a file system shared by several lakeFS
+// instances, such as an NFS mount. Files are written to temporary files, synced to disk and renamed to their final
+// name, so readers never observe partially written objects. Multipart uploads keep their parts in files, and are
+// completed under a lock file excluding other instances.
 type Adapter struct {
 	path                    string
 	removeEmptyDir          bool
 	allowedExternalPrefixes []string
 	importEnabled           bool
+	locks                   *pathLocks
 }
 
 var (
 	ErrPathNotWritable       = errors.New("path provided is not writable")
 	ErrInvalidUploadIDFormat = errors.New("invalid upload id format")
 	ErrBadPath               = errors.New("bad path traversal blocked")
+	ErrInvalidPart           = errors.New("invalid part")
 )
 
 type QualifiedKey struct {
@@ -91,6 +106,7 @@ func NewAdapter(path string, opts ...func(a *Adapter)) (*Adapter, error) {
 	localAdapter := &Adapter{
 		path:           path,
 		removeEmptyDir: true,
+		locks:          newPathLocks(),
 	}
 	for _, opt := range opts {
 		opt(localAdapter)
@@ -131,21 +147,64 @@ func (l *Adapter) extractParamsFromObj(ptr block.ObjectPointer) (string, error)
 	return p, nil
 }
 
-// maybeMkdir verifies path is allowed and runs f(path), but if f fails due to file-not-found
-// MkdirAll's its dir and then runs it again.
-func (l *Adapter) maybeMkdir(path string, f func(p string) (*os.File, error)) (*os.File, error) {
+// retryMkdir runs f, creating the directory dir and running it again in case it fails due to file-not-found. The
+// directory may be removed as empty by another instance after it was created, so f runs a bounded number of times.
+func retryMkdir[T any](dir string, f func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		ret, err := f()
+		if !errors.Is(err, os.ErrNotExist) || attempt == mkdirAttempts {
+			return ret, err
+		}
+		if err := os.MkdirAll(dir, 0o750); err != nil { //nolint: gomnd
+			return ret, err
+		}
+	}
+}
+
+func openFileRetryMkdir(path string, flag int) (*os.File, error) {
+	return retryMkdir(filepath.Dir(path), func() (*os.File, error) {
+		return os.OpenFile(path, flag, 0o600) //nolint: gomnd
+	})
+}
+
+// writeFile verifies path is allowed and atomically replaces the file at path by the content of reader. The content
+// is written to a temporary file in the same directory, synced to disk and renamed to path, so readers on any
+// instance find either the previous file or the complete new one. Returns the number of bytes written.
+func (l *Adapter) writeFile(path string, reader io.Reader) (int64, error) {
+	path = filepath.Clean(path)
 	if err := l.verifyRelPath(path); err != nil {
-		return nil, err
+		return 0, err
 	}
-	ret, err := f(path)
-	if !errors.Is(err, os.ErrNotExist) {
-		return ret, err
+	dir := filepath.Dir(path)
+	f, err := retryMkdir(dir, func() (*os.File, error) {
+		return os.CreateTemp(dir, "."+filepath.Base(path)+".*"+tempFileSuffix)
+	})
+	if err != nil {
+		return 0, err
 	}
-	d := filepath.Dir(filepath.Clean(path))
-	if err = os.MkdirAll(d, 0o750); err != nil { //nolint: gomnd
-		return nil, err
+	tempPath := f.Name()
+	renamed := false
+	defer func() {
+		_ = f.Close()
+		if !renamed {
+			_ = os.Remove(tempPath)
+		}
+	}()
+	n, err := io.Copy(f, reader)
+	if err != nil {
+		return n, err
+	}
+	if err := f.Sync(); err != nil {
+		return n, err
+	}
+	if err := f.Close(); err != nil {
+		return n, err
 	}
-	return f(path)
+	if err := os.Rename(tempPath, path); err != nil {
+		return n, err
+	}
+	renamed = true
+	return n, syncDir(dir)
 }
 
 func (l *Adapter) Path() string {
@@ -157,15 +216,7 @@ func (l *Adapter) Put(_ context.Context, obj block.ObjectPointer, _ int64, reade
 	if err != nil {
 		return err
 	}
-	p = filepath.Clean(p)
-	f, err := l.maybeMkdir(p, os.Create)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = f.Close()
-	}()
-	_, err = io.Copy(f, reader)
+	_, err = l.writeFile(p, reader)
 	return err
 }
 
@@ -176,6 +227,10 @@ func (l *Adapter) Remove(_ context.Context, obj block.ObjectPointer) error {
 	}
 	p = filepath.Clean(p)
 	err = os.Remove(p)
+	if errors.Is(err, os.ErrNotExist) {
+		// removed by another instance
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -212,27 +267,28 @@ func (l *Adapter) Copy(_ context.Context, sourceObj, destinationObj block.Object
 		return err
 	}
 	sourceFile, err := os.Open(filepath.Clean(source))
-	defer func() {
-		_ = sourceFile.Close()
-	}()
 	if err != nil {
 		return err
 	}
+	defer func() {
+		_ = sourceFile.Close()
+	}()
 	dest, err := l.extractParamsFromObj(destinationObj)
 	if err != nil {
 		return err
 	}
-	destinationFile, err := l.maybeMkdir(dest, os.Create)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = destinationFile.Close()
-	}()
-	_, err = io.Copy(destinationFile, sourceFile)
+	_, err = l.writeFile(dest, sourceFile)
 	return err
 }
 
+func (l *Adapter) SetStorageClass(_ context.Context, _ block.ObjectPointer, _ string) error {
+	return fmt.Errorf("local adapter storage class: %w", block.ErrOperationNotSupported)
+}
+
+func (l *Adapter) Restore(_ context.Context, _ block.ObjectPointer, _ int) error {
+	return fmt.Errorf("local adapter restore: %w", block.ErrOperationNotSupported)
+}
+
 func (l *Adapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj block.ObjectPointer, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
 	if err := isValidUploadID(uploadID); err != nil {
 		return nil, err
@@ -242,8 +298,7 @@ func (l *Adapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj
 		return nil, fmt.Errorf("copy get: %w", err)
 	}
 	md5Read := block.NewHashingReader(r, block.HashFunctionMD5)
-	fName := uploadID + fmt.Sprintf("-%05d", partNumber)
-	err = l.Put(ctx, block.ObjectPointer{StorageNamespace: destinationObj.StorageNamespace, Identifier: fName}, -1, md5Read, block.PutOpts{})
+	err = l.Put(ctx, block.ObjectPointer{StorageNamespace: destinationObj.StorageNamespace, Identifier: partFileName(uploadID, partNumber)}, -1, md5Read, block.PutOpts{})
 	if err != nil {
 		return nil, fmt.Errorf("copy put: %w", err)
 	}
@@ -262,8 +317,7 @@ func (l *Adapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinatio
 		return nil, fmt.Errorf("copy range get: %w", err)
 	}
 	md5Read := block.NewHashingReader(r, block.HashFunctionMD5)
-	fName := uploadID + fmt.Sprintf("-%05d", partNumber)
-	err = l.Put(ctx, block.ObjectPointer{StorageNamespace: destinationObj.StorageNamespace, Identifier: fName}, -1, md5Read, block.PutOpts{})
+	err = l.Put(ctx, block.ObjectPointer{StorageNamespace: destinationObj.StorageNamespace, Identifier: partFileName(uploadID, partNumber)}, -1, md5Read, block.PutOpts{})
 	if err != nil {
 		return nil, fmt.Errorf("copy range put: %w", err)
 	}
@@ -288,7 +342,7 @@ func (l *Adapter) Get(_ context.Context, obj block.ObjectPointer, _ int64) (read
 	return f, nil
 }
 
-func (l *Adapter) GetWalker(uri *url.URL) (block.Walker, error) {
+func (l *Adapter) GetWalker(_ string, uri *url.URL) (block.Walker, error) {
 	if err := block.ValidateStorageType(uri, block.StorageTypeLocal); err != nil {
 		return nil, err
 	}
@@ -348,12 +402,11 @@ func (l *Adapter) GetProperties(_ context.Context, obj block.ObjectPointer) (blo
 	if err != nil {
 		return block.Properties{}, err
 	}
-	_, err = os.Stat(p)
+	info, err := os.Stat(p)
 	if err != nil {
 		return block.Properties{}, err
 	}
-	// No properties, just return that it exists
-	return block.Properties{}, nil
+	return block.Properties{ContentLength: info.Size()}, nil
 }
 
 // isDirectoryWritable tests that pth, which must not be controllable by user input, is a
@@ -394,8 +447,7 @@ func (l *Adapter) UploadPart(ctx context.Context, obj block.ObjectPointer, _ int
 		return nil, err
 	}
 	md5Read := block.NewHashingReader(reader, block.HashFunctionMD5)
-	fName := uploadID + fmt.Sprintf("-%05d", partNumber)
-	err := l.Put(ctx, block.ObjectPointer{StorageNamespace: obj.StorageNamespace, Identifier: fName}, -1, md5Read, block.PutOpts{})
+	err := l.Put(ctx, block.ObjectPointer{StorageNamespace: obj.StorageNamespace, Identifier: partFileName(uploadID, partNumber)}, -1, md5Read, block.PutOpts{})
 	etag := hex.EncodeToString(md5Read.Md5.Sum(nil))
 	return &block.UploadPartResponse{
 		ETag: etag,
@@ -406,6 +458,11 @@ func (l *Adapter) AbortMultiPartUpload(_ context.Context, obj block.ObjectPointe
 	if err := isValidUploadID(uploadID); err != nil {
 		return err
 	}
+	unlock, err := l.lockUpload(obj, uploadID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	files, err := l.getPartFiles(uploadID, obj)
 	if err != nil {
 		return err
@@ -416,19 +473,26 @@ func (l *Adapter) AbortMultiPartUpload(_ context.Context, obj block.ObjectPointe
 	return nil
 }
 
+// CompleteMultiPartUpload unites the parts listed in multipartList to the object, and removes all parts of the
+// upload. The upload is locked while completing it, so it is completed or aborted by a single instance.
 func (l *Adapter) CompleteMultiPartUpload(_ context.Context, obj block.ObjectPointer, uploadID string, multipartList *block.MultipartUploadCompletion) (*block.CompleteMultiPartUploadResponse, error) {
 	if err := isValidUploadID(uploadID); err != nil {
 		return nil, err
 	}
-	etag := computeETag(multipartList.Part) + "-" + strconv.Itoa(len(multipartList.Part))
-	partFiles, err := l.getPartFiles(uploadID, obj)
+	unlock, err := l.lockUpload(obj, uploadID)
 	if err != nil {
-		return nil, fmt.Errorf("part files not found for %s: %w", uploadID, err)
+		return nil, err
 	}
-	size, err := l.unitePartFiles(obj, partFiles)
+	defer unlock()
+	etag := computeETag(multipartList.Part) + "-" + strconv.Itoa(len(multipartList.Part))
+	size, err := l.unitePartFiles(obj, uploadID, multipartList.Part)
 	if err != nil {
 		return nil, fmt.Errorf("multipart upload unite for %s: %w", uploadID, err)
 	}
+	partFiles, err := l.getPartFiles(uploadID, obj)
+	if err != nil {
+		return nil, fmt.Errorf("part files not found for %s: %w", uploadID, err)
+	}
 	if err = l.removePartFiles(partFiles); err != nil {
 		return nil, err
 	}
@@ -451,39 +515,75 @@ func computeETag(parts []block.MultipartPart) string {
 	return csm
 }
 
-func (l *Adapter) unitePartFiles(identifier block.ObjectPointer, filenames []string) (int64, error) {
-	p, err := l.extractParamsFromObj(identifier)
+func partFileName(uploadID string, partNumber int) string {
+	return uploadID + fmt.Sprintf("-%05d", partNumber)
+}
+
+// lockUpload locks the multipart upload for all lakeFS instances sharing the file system
+func (l *Adapter) lockUpload(obj block.ObjectPointer, uploadID string) (func(), error) {
+	lockPath, err := l.extractParamsFromObj(block.ObjectPointer{
+		StorageNamespace: obj.StorageNamespace,
+		Identifier:       "." + uploadID + ".lock",
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	return l.lockFile(lockPath)
+}
+
+// partReader reads a part file, failing at its end in case its content does not match the ETag of the part
+type partReader struct {
+	reader     io.Reader
+	hash       hash.Hash
+	partNumber int
+	etag       string
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	_, _ = r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) && hex.EncodeToString(r.hash.Sum(nil)) != r.etag {
+		return n, fmt.Errorf("part %d etag mismatch: %w", r.partNumber, ErrInvalidPart)
 	}
-	unitedFile, err := os.Create(p)
+	return n, err
+}
+
+func (l *Adapter) unitePartFiles(identifier block.ObjectPointer, uploadID string, parts []block.MultipartPart) (int64, error) {
+	p, err := l.extractParamsFromObj(identifier)
 	if err != nil {
-		return 0, fmt.Errorf("create path %s: %w", p, err)
+		return 0, err
 	}
-	files := make([]*os.File, 0, len(filenames))
+	files := make([]*os.File, 0, len(parts))
 	defer func() {
-		_ = unitedFile.Close()
 		for _, f := range files {
 			_ = f.Close()
 		}
 	}()
-	for _, name := range filenames {
-		if err := l.verifyRelPath(name); err != nil {
+	readers := make([]io.Reader, 0, len(parts))
+	for _, part := range parts {
+		name, err := l.extractParamsFromObj(block.ObjectPointer{
+			StorageNamespace: identifier.StorageNamespace,
+			Identifier:       partFileName(uploadID, part.PartNumber),
+		})
+		if err != nil {
 			return 0, err
 		}
 		f, err := os.Open(filepath.Clean(name))
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("part %d not found: %w", part.PartNumber, ErrInvalidPart)
+		}
 		if err != nil {
 			return 0, fmt.Errorf("open file %s: %w", name, err)
 		}
 		files = append(files, f)
+		readers = append(readers, &partReader{
+			reader:     f,
+			hash:       md5.New(), //nolint:gosec
+			partNumber: part.PartNumber,
+			etag:       strings.Trim(part.ETag, `"`),
+		})
 	}
-	// convert slice file files to readers
-	readers := make([]io.Reader, len(files))
-	for i := range files {
-		readers[i] = files[i]
-	}
-	unitedReader := io.MultiReader(readers...)
-	return io.Copy(unitedFile, unitedReader)
+	return l.writeFile(p, io.MultiReader(readers...))
 }
 
 func (l *Adapter) removePartFiles(files []string) error {
@@ -509,7 +609,7 @@ func (l *Adapter) getPartFiles(uploadID string, obj block.ObjectPointer) ([]stri
 	if err != nil {
 		return nil, err
 	}
-	globPathPattern += "*"
+	globPathPattern += "-*"
 	names, err := filepath.Glob(globPathPattern)
 	if err != nil {
 		return nil, err
@@ -530,7 +630,7 @@ func (l *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
 	return info
 }
 
-func (l *Adapter) ResolveNamespace(storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+func (l *Adapter) ResolveNamespace(_, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
 	qk, err := block.DefaultResolveNamespace(storageNamespace, key, identifierType)
 	if err != nil {
 		return nil, err
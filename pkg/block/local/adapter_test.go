@@ -1,9 +1,18 @@
 package local_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
 	"path"
+	"path/filepath"
 	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 	"github.com/treeverse/lakefs/pkg/block"
@@ -64,3 +73,97 @@ func TestAdapterNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapterPutAtomic(t *testing.T) {
+	ctx := context.Background()
+	localPath := path.Join(t.TempDir(), "lakefs")
+	adapter, err := local.NewAdapter(localPath, local.WithRemoveEmptyDir(false))
+	require.NoError(t, err)
+	obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "dir/obj", IdentifierType: block.IdentifierTypeRelative}
+	require.NoError(t, adapter.Put(ctx, obj, 3, strings.NewReader("old"), block.PutOpts{}))
+
+	// a failed write keeps the previous content
+	errWrite := errors.New("write failed")
+	reader := io.MultiReader(strings.NewReader("new"), iotest.ErrReader(errWrite))
+	require.ErrorIs(t, adapter.Put(ctx, obj, 6, reader, block.PutOpts{}), errWrite)
+	r, err := adapter.Get(ctx, obj, 0)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "old", string(data))
+
+	// no temporary files are left behind
+	entries, err := os.ReadDir(filepath.Join(localPath, "test", "dir"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "obj", entries[0].Name())
+
+	// removing a removed object succeeds
+	require.NoError(t, adapter.Remove(ctx, obj))
+	require.NoError(t, adapter.Remove(ctx, obj))
+}
+
+func TestAdapterMultipartComplete(t *testing.T) {
+	ctx := context.Background()
+	localPath := path.Join(t.TempDir(), "lakefs")
+	adapter, err := local.NewAdapter(localPath, local.WithRemoveEmptyDir(false))
+	require.NoError(t, err)
+	obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "multipart", IdentifierType: block.IdentifierTypeRelative}
+	parts := [][]byte{bytes.Repeat([]byte("a"), 100), bytes.Repeat([]byte("b"), 10), []byte("unlisted")}
+
+	resp, err := adapter.CreateMultiPartUpload(ctx, obj, nil, block.CreateMultiPartUploadOpts{})
+	require.NoError(t, err)
+	var completion block.MultipartUploadCompletion
+	for i, part := range parts {
+		partResp, err := adapter.UploadPart(ctx, obj, int64(len(part)), bytes.NewReader(part), resp.UploadID, i+1)
+		require.NoError(t, err)
+		if i < 2 {
+			completion.Part = append(completion.Part, block.MultipartPart{PartNumber: i + 1, ETag: `"` + partResp.ETag + `"`})
+		}
+	}
+
+	t.Run("etag_mismatch", func(t *testing.T) {
+		mismatch := block.MultipartUploadCompletion{Part: []block.MultipartPart{{PartNumber: 1, ETag: completion.Part[1].ETag}}}
+		_, err := adapter.CompleteMultiPartUpload(ctx, obj, resp.UploadID, &mismatch)
+		require.ErrorIs(t, err, local.ErrInvalidPart)
+		found, err := adapter.Exists(ctx, obj)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	// concurrent completions of the upload, only one of them finds the parts
+	const completions = 5
+	var wg sync.WaitGroup
+	errs := make([]error, completions)
+	for i := 0; i < completions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = adapter.CompleteMultiPartUpload(ctx, obj, resp.UploadID, &completion)
+		}(i)
+	}
+	wg.Wait()
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			require.ErrorIs(t, err, local.ErrInvalidPart)
+		}
+	}
+	require.Equal(t, 1, succeeded)
+
+	r, err := adapter.Get(ctx, obj, 0)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, append(parts[0], parts[1]...), data)
+
+	// parts and lock files are removed
+	entries, err := os.ReadDir(filepath.Join(localPath, "test"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "multipart", entries[0].Name())
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package local
+
+import "os"
+
+// lockFileHandle does not lock f on platforms without POSIX record locks, so locks exclude only the goroutines of
+// this process
+func lockFileHandle(_ *os.File) error {
+	return nil
+}
+
+func unlockFileHandle(_ *os.File) error {
+	return nil
+}
+
+// syncDir does nothing, directories cannot be synced on platforms without POSIX semantics
+func syncDir(_ string) error {
+	return nil
+}
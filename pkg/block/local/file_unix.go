@@ -0,0 +1,34 @@
+//go:build unix
+
+package local
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFileHandle takes an exclusive POSIX record lock on f, waiting for it to be released by other processes.
+// Record locks are supported by NFS, unlike flock(2) locks.
+func lockFileHandle(f *os.File) error {
+	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLKW, &syscall.Flock_t{
+		Type:   syscall.F_WRLCK,
+		Whence: 0,
+	})
+}
+
+func unlockFileHandle(f *os.File) error {
+	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLK, &syscall.Flock_t{
+		Type:   syscall.F_UNLCK,
+		Whence: 0,
+	})
+}
+
+// syncDir flushes the entries of the directory, making files renamed into it durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
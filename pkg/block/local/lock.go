@@ -0,0 +1,86 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// pathLocks serializes the goroutines of this process locking the same path. Locks on files are held by processes,
+// so they do not exclude goroutines of the same process.
+type pathLocks struct {
+	mu    sync.Mutex
+	locks map[string]*pathLock
+}
+
+type pathLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newPathLocks() *pathLocks {
+	return &pathLocks{locks: make(map[string]*pathLock)}
+}
+
+func (p *pathLocks) lock(path string) func() {
+	p.mu.Lock()
+	l, ok := p.locks[path]
+	if !ok {
+		l = &pathLock{}
+		p.locks[path] = l
+	}
+	l.refs++
+	p.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		p.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(p.locks, path)
+		}
+		p.mu.Unlock()
+	}
+}
+
+// lockFile takes an exclusive lock on the lock file at path, excluding other goroutines of this process as well as
+// other lakeFS instances sharing the file system. Returns a function removing the lock file and releasing the lock.
+func (l *Adapter) lockFile(path string) (func(), error) {
+	if err := l.verifyRelPath(path); err != nil {
+		return nil, err
+	}
+	unlockPath := l.locks.lock(path)
+	for {
+		f, err := openFileRetryMkdir(path, os.O_CREATE|os.O_RDWR)
+		if err != nil {
+			unlockPath()
+			return nil, err
+		}
+		if err := lockFileHandle(f); err != nil {
+			_ = f.Close()
+			unlockPath()
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		// the lock file may have been removed by its previous holder while waiting for the lock, in which case the
+		// lock is held on a file no other instance will lock
+		locked, err := f.Stat()
+		if err != nil {
+			_ = unlockFileHandle(f)
+			_ = f.Close()
+			unlockPath()
+			return nil, err
+		}
+		current, err := os.Stat(path)
+		if err == nil && os.SameFile(locked, current) {
+			return func() {
+				_ = os.Remove(path)
+				_ = unlockFileHandle(f)
+				_ = f.Close()
+				unlockPath()
+			}, nil
+		}
+		_ = unlockFileHandle(f)
+		_ = f.Close()
+	}
+}
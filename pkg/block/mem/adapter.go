@@ -54,7 +54,9 @@ type Adapter struct {
 	data       map[string][]byte
 	mpu        map[string]*mpu
 	properties map[string]block.Properties
-	mutex      *sync.RWMutex
+	// restored holds the keys of restored archived objects
+	restored map[string]struct{}
+	mutex    *sync.RWMutex
 }
 
 func New(_ context.Context, opts ...func(a *Adapter)) *Adapter {
@@ -62,6 +64,7 @@ func New(_ context.Context, opts ...func(a *Adapter)) *Adapter {
 		data:       make(map[string][]byte),
 		mpu:        make(map[string]*mpu),
 		properties: make(map[string]block.Properties),
+		restored:   make(map[string]struct{}),
 		mutex:      &sync.RWMutex{},
 	}
 	for _, opt := range opts {
@@ -90,7 +93,8 @@ func (a *Adapter) Put(_ context.Context, obj block.ObjectPointer, _ int64, reade
 	}
 	key := getKey(obj)
 	a.data[key] = data
-	a.properties[key] = block.Properties(opts)
+	a.properties[key] = block.Properties{StorageClass: opts.StorageClass}
+	delete(a.restored, key)
 	return nil
 }
 
@@ -105,9 +109,24 @@ func (a *Adapter) Get(_ context.Context, obj block.ObjectPointer, _ int64) (io.R
 	if !ok {
 		return nil, ErrNoDataForKey
 	}
+	if err := a.verifyReadable(key); err != nil {
+		return nil, err
+	}
 	return io.NopCloser(bytes.NewReader(data)), nil
 }
 
+// verifyReadable fails reading archived objects which were not restored
+func (a *Adapter) verifyReadable(key string) error {
+	storageClass := a.properties[key].StorageClass
+	if storageClass == nil || !block.IsArchiveStorageClass(*storageClass) {
+		return nil
+	}
+	if _, ok := a.restored[key]; !ok {
+		return block.ErrRestoreRequired
+	}
+	return nil
+}
+
 func verifyObjectPointer(obj block.ObjectPointer) error {
 	const prefix = "mem://"
 	if obj.StorageNamespace == "" {
@@ -120,7 +139,7 @@ func verifyObjectPointer(obj block.ObjectPointer) error {
 	return nil
 }
 
-func (a *Adapter) GetWalker(_ *url.URL) (block.Walker, error) {
+func (a *Adapter) GetWalker(_ string, _ *url.URL) (block.Walker, error) {
 	return nil, fmt.Errorf("mem block adapter: %w", block.ErrOperationNotSupported)
 }
 
@@ -147,10 +166,14 @@ func (a *Adapter) GetRange(_ context.Context, obj block.ObjectPointer, startPosi
 	}
 	a.mutex.RLock()
 	defer a.mutex.RUnlock()
-	data, ok := a.data[getKey(obj)]
+	key := getKey(obj)
+	data, ok := a.data[key]
 	if !ok {
 		return nil, ErrNoDataForKey
 	}
+	if err := a.verifyReadable(key); err != nil {
+		return nil, err
+	}
 	return io.NopCloser(io.NewSectionReader(bytes.NewReader(data), startPosition, endPosition-startPosition+1)), nil
 }
 
@@ -164,6 +187,7 @@ func (a *Adapter) GetProperties(_ context.Context, obj block.ObjectPointer) (blo
 	if !ok {
 		return block.Properties{}, ErrNoPropertiesForKey
 	}
+	props.ContentLength = int64(len(a.data[getKey(obj)]))
 	return props, nil
 }
 
@@ -173,7 +197,9 @@ func (a *Adapter) Remove(_ context.Context, obj block.ObjectPointer) error {
 	}
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
-	delete(a.data, getKey(obj))
+	key := getKey(obj)
+	delete(a.data, key)
+	delete(a.restored, key)
 	return nil
 }
 
@@ -193,6 +219,37 @@ func (a *Adapter) Copy(_ context.Context, sourceObj, destinationObj block.Object
 	return nil
 }
 
+func (a *Adapter) SetStorageClass(_ context.Context, obj block.ObjectPointer, storageClass string) error {
+	if err := verifyObjectPointer(obj); err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	key := getKey(obj)
+	if _, ok := a.data[key]; !ok {
+		return ErrNoDataForKey
+	}
+	props := a.properties[key]
+	props.StorageClass = &storageClass
+	a.properties[key] = props
+	delete(a.restored, key)
+	return nil
+}
+
+func (a *Adapter) Restore(_ context.Context, obj block.ObjectPointer, _ int) error {
+	if err := verifyObjectPointer(obj); err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	key := getKey(obj)
+	if _, ok := a.data[key]; !ok {
+		return ErrNoDataForKey
+	}
+	a.restored[key] = struct{}{}
+	return nil
+}
+
 func (a *Adapter) UploadCopyPart(ctx context.Context, sourceObj, _ block.ObjectPointer, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
 	if err := verifyObjectPointer(sourceObj); err != nil {
 		return nil, err
@@ -346,7 +403,7 @@ func (a *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
 	return info
 }
 
-func (a *Adapter) ResolveNamespace(storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+func (a *Adapter) ResolveNamespace(_, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
 	return block.DefaultResolveNamespace(storageNamespace, key, identifierType)
 }
 
@@ -0,0 +1,241 @@
+package multi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+var (
+	ErrNoStorage          = errors.New("no blockstore")
+	ErrDuplicateStorageID = errors.New("duplicate storage ID")
+)
+
+// Adapter routes each object to one of multiple blockstores, by the StorageID of its ObjectPointer or the storage ID
+// passed to the operation. Operations that are not bound to a storage are served by the default blockstore.
+type Adapter struct {
+	storages []block.Storage
+	adapters map[string]block.Adapter
+}
+
+// NewAdapter returns an adapter serving the given blockstores. The first blockstore is the default one, it also
+// serves objects with an empty storage ID.
+func NewAdapter(storages []block.Storage) (*Adapter, error) {
+	if len(storages) == 0 {
+		return nil, ErrNoStorage
+	}
+	adapters := make(map[string]block.Adapter, len(storages)+1)
+	adapters[""] = storages[0].Adapter
+	for i, s := range storages {
+		if _, ok := adapters[s.ID]; ok && (i > 0 || s.ID != "") {
+			return nil, fmt.Errorf("%w: '%s'", ErrDuplicateStorageID, s.ID)
+		}
+		adapters[s.ID] = s.Adapter
+	}
+	return &Adapter{
+		storages: storages,
+		adapters: adapters,
+	}, nil
+}
+
+func (a *Adapter) Storages() []block.Storage {
+	return a.storages
+}
+
+func (a *Adapter) StorageAdapter(storageID string) (block.Adapter, error) {
+	adapter, ok := a.adapters[storageID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", block.ErrUnknownStorageID, storageID)
+	}
+	return adapter, nil
+}
+
+func (a *Adapter) defaultAdapter() block.Adapter {
+	return a.storages[0].Adapter
+}
+
+// sameStorageAdapter returns the adapter of objects which are expected to be on the same blockstore
+func (a *Adapter) sameStorageAdapter(sourceObj, destinationObj block.ObjectPointer) (block.Adapter, error) {
+	if sourceObj.StorageID != destinationObj.StorageID {
+		return nil, fmt.Errorf("copy between storage '%s' and '%s': %w", sourceObj.StorageID, destinationObj.StorageID, block.ErrOperationNotSupported)
+	}
+	return a.StorageAdapter(sourceObj.StorageID)
+}
+
+func (a *Adapter) Put(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return err
+	}
+	return adapter.Put(ctx, obj, sizeBytes, reader, opts)
+}
+
+func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, expectedSize int64) (io.ReadCloser, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.Get(ctx, obj, expectedSize)
+}
+
+func (a *Adapter) GetWalker(storageID string, uri *url.URL) (block.Walker, error) {
+	adapter, err := a.StorageAdapter(storageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetWalker(storageID, uri)
+}
+
+func (a *Adapter) GetPreSignedURL(ctx context.Context, obj block.ObjectPointer, mode block.PreSignMode) (string, time.Time, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return adapter.GetPreSignedURL(ctx, obj, mode)
+}
+
+func (a *Adapter) GetPresignUploadPartURL(ctx context.Context, obj block.ObjectPointer, uploadID string, partNumber int) (string, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return "", err
+	}
+	return adapter.GetPresignUploadPartURL(ctx, obj, uploadID, partNumber)
+}
+
+func (a *Adapter) Exists(ctx context.Context, obj block.ObjectPointer) (bool, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return false, err
+	}
+	return adapter.Exists(ctx, obj)
+}
+
+func (a *Adapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetRange(ctx, obj, startPosition, endPosition)
+}
+
+func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (block.Properties, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return block.Properties{}, err
+	}
+	return adapter.GetProperties(ctx, obj)
+}
+
+func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return err
+	}
+	return adapter.Remove(ctx, obj)
+}
+
+func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.ObjectPointer) error {
+	adapter, err := a.sameStorageAdapter(sourceObj, destinationObj)
+	if err != nil {
+		return err
+	}
+	return adapter.Copy(ctx, sourceObj, destinationObj)
+}
+
+func (a *Adapter) SetStorageClass(ctx context.Context, obj block.ObjectPointer, storageClass string) error {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return err
+	}
+	return adapter.SetStorageClass(ctx, obj, storageClass)
+}
+
+func (a *Adapter) Restore(ctx context.Context, obj block.ObjectPointer, days int) error {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return err
+	}
+	return adapter.Restore(ctx, obj, days)
+}
+
+func (a *Adapter) CreateMultiPartUpload(ctx context.Context, obj block.ObjectPointer, r *http.Request, opts block.CreateMultiPartUploadOpts) (*block.CreateMultiPartUploadResponse, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.CreateMultiPartUpload(ctx, obj, r, opts)
+}
+
+func (a *Adapter) UploadPart(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.UploadPart(ctx, obj, sizeBytes, reader, uploadID, partNumber)
+}
+
+func (a *Adapter) ListParts(ctx context.Context, obj block.ObjectPointer, uploadID string, opts block.ListPartsOpts) (*block.ListPartsResponse, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.ListParts(ctx, obj, uploadID, opts)
+}
+
+func (a *Adapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj block.ObjectPointer, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+	adapter, err := a.sameStorageAdapter(sourceObj, destinationObj)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
+}
+
+func (a *Adapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj block.ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*block.UploadPartResponse, error) {
+	adapter, err := a.sameStorageAdapter(sourceObj, destinationObj)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.UploadCopyPartRange(ctx, sourceObj, destinationObj, uploadID, partNumber, startPosition, endPosition)
+}
+
+func (a *Adapter) AbortMultiPartUpload(ctx context.Context, obj block.ObjectPointer, uploadID string) error {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return err
+	}
+	return adapter.AbortMultiPartUpload(ctx, obj, uploadID)
+}
+
+func (a *Adapter) CompleteMultiPartUpload(ctx context.Context, obj block.ObjectPointer, uploadID string, multipartList *block.MultipartUploadCompletion) (*block.CompleteMultiPartUploadResponse, error) {
+	adapter, err := a.StorageAdapter(obj.StorageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+}
+
+func (a *Adapter) BlockstoreType() string {
+	return a.defaultAdapter().BlockstoreType()
+}
+
+func (a *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
+	return a.defaultAdapter().GetStorageNamespaceInfo()
+}
+
+func (a *Adapter) ResolveNamespace(storageID, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+	adapter, err := a.StorageAdapter(storageID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.ResolveNamespace(storageID, storageNamespace, key, identifierType)
+}
+
+func (a *Adapter) RuntimeStats() map[string]string {
+	return a.defaultAdapter().RuntimeStats()
+}
@@ -0,0 +1,102 @@
+package multi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/block/multi"
+)
+
+func TestAdapter_Routing(t *testing.T) {
+	ctx := context.Background()
+	defaultStore := mem.New(ctx)
+	otherStore := mem.New(ctx)
+	adapter, err := multi.NewAdapter([]block.Storage{
+		{ID: "main", Adapter: defaultStore},
+		{ID: "other", Description: "other blockstore", Adapter: otherStore},
+	})
+	if err != nil {
+		t.Fatalf("NewAdapter() failed: %s", err)
+	}
+
+	const data = "data"
+	for _, storageID := range []string{"", "main", "other"} {
+		obj := block.ObjectPointer{
+			StorageID:        storageID,
+			StorageNamespace: "mem://ns",
+			Identifier:       "obj-" + storageID,
+			IdentifierType:   block.IdentifierTypeRelative,
+		}
+		if err := adapter.Put(ctx, obj, int64(len(data)), strings.NewReader(data), block.PutOpts{}); err != nil {
+			t.Fatalf("Put(%s) failed: %s", storageID, err)
+		}
+		expected := defaultStore
+		if storageID == "other" {
+			expected = otherStore
+		}
+		obj.StorageID = ""
+		found, err := expected.Exists(ctx, obj)
+		if err != nil {
+			t.Fatalf("Exists(%s) failed: %s", storageID, err)
+		}
+		if !found {
+			t.Errorf("Put(%s) object not found on the expected blockstore", storageID)
+		}
+	}
+
+	unknown := block.ObjectPointer{StorageID: "unknown", StorageNamespace: "mem://ns", Identifier: "obj"}
+	if _, err := adapter.Exists(ctx, unknown); !errors.Is(err, block.ErrUnknownStorageID) {
+		t.Errorf("Exists() on unknown storage error=%v, expected=%s", err, block.ErrUnknownStorageID)
+	}
+
+	if _, err := adapter.ResolveNamespace("other", "mem://ns", "obj", block.IdentifierTypeRelative); err != nil {
+		t.Errorf("ResolveNamespace(other) failed: %s", err)
+	}
+	if _, err := adapter.ResolveNamespace("unknown", "mem://ns", "obj", block.IdentifierTypeRelative); !errors.Is(err, block.ErrUnknownStorageID) {
+		t.Errorf("ResolveNamespace() on unknown storage error=%v, expected=%s", err, block.ErrUnknownStorageID)
+	}
+
+	err = adapter.Copy(ctx,
+		block.ObjectPointer{StorageNamespace: "mem://ns", Identifier: "obj-", IdentifierType: block.IdentifierTypeRelative},
+		block.ObjectPointer{StorageID: "other", StorageNamespace: "mem://ns", Identifier: "copy", IdentifierType: block.IdentifierTypeRelative})
+	if !errors.Is(err, block.ErrOperationNotSupported) {
+		t.Errorf("Copy() between blockstores error=%v, expected=%s", err, block.ErrOperationNotSupported)
+	}
+
+	storages := block.GetStorages(adapter)
+	if len(storages) != 2 || storages[0].ID != "main" || storages[1].ID != "other" {
+		t.Errorf("GetStorages() = %+v, expected main and other", storages)
+	}
+	if a, err := block.GetStorageAdapter(adapter, "other"); err != nil || a != otherStore {
+		t.Errorf("GetStorageAdapter(other) = %v, %v, expected the other blockstore", a, err)
+	}
+}
+
+func TestNewAdapter_DuplicateStorageID(t *testing.T) {
+	ctx := context.Background()
+	_, err := multi.NewAdapter([]block.Storage{
+		{ID: "main", Adapter: mem.New(ctx)},
+		{ID: "main", Adapter: mem.New(ctx)},
+	})
+	if !errors.Is(err, multi.ErrDuplicateStorageID) {
+		t.Errorf("NewAdapter() error=%v, expected=%s", err, multi.ErrDuplicateStorageID)
+	}
+	_, err = multi.NewAdapter(nil)
+	if !errors.Is(err, multi.ErrNoStorage) {
+		t.Errorf("NewAdapter() error=%v, expected=%s", err, multi.ErrNoStorage)
+	}
+}
+
+func TestGetStorageAdapter_SingleStorage(t *testing.T) {
+	adapter := mem.New(context.Background())
+	if a, err := block.GetStorageAdapter(adapter, ""); err != nil || a != adapter {
+		t.Errorf("GetStorageAdapter() = %v, %v, expected the adapter itself", a, err)
+	}
+	if _, err := block.GetStorageAdapter(adapter, "other"); !errors.Is(err, block.ErrUnknownStorageID) {
+		t.Errorf("GetStorageAdapter(other) error=%v, expected=%s", err, block.ErrUnknownStorageID)
+	}
+}
@@ -13,6 +13,31 @@ type AdapterConfig interface {
 	BlockstoreAzureParams() (Azure, error)
 }
 
+// StorageConfig configures a block adapter of one of the blockstores of an installation
+type StorageConfig interface {
+	AdapterConfig
+	// BlockstoreID identifies the blockstore, empty for the default blockstore
+	BlockstoreID() string
+	BlockstoreDescription() string
+	BlockstoreEncryptionParams() (Encryption, error)
+	BlockstoreCompressionParams() Compression
+}
+
+// Compression configures compression of objects written with a compression codec
+type Compression struct {
+	Enabled   bool
+	FrameSize int
+}
+
+// Encryption configures encryption of the blockstore data by lakeFS, using master keys held by lakeFS
+type Encryption struct {
+	Enabled bool
+	// KeyID identifies the key used to encrypt new data, other keys only decrypt existing data
+	KeyID     string
+	Keys      map[string][]byte
+	ChunkSize int
+}
+
 type Mem struct{}
 
 type Local struct {
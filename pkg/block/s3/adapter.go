@@ -20,6 +20,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/middleware"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/treeverse/lakefs/pkg/block"
@@ -28,6 +29,14 @@ import (
 	"github.com/treeverse/lakefs/pkg/stats"
 )
 
+const (
+	// maxCopyObjectSize is the size of the largest object copied by a single CopyObject
+	maxCopyObjectSize = 5 * 1024 * 1024 * 1024
+	// copyPartSize is the part size of multipart copies, larger for objects that do not fit the maximal number of
+	// parts
+	copyPartSize = 512 * 1024 * 1024
+)
+
 var (
 	ErrS3          = errors.New("s3 error")
 	ErrMissingETag = fmt.Errorf("%w: missing ETag", ErrS3)
@@ -323,6 +332,12 @@ func isErrNotFound(err error) bool {
 	return errors.As(err, &errNoSuchKey) || errors.As(err, &errNotFound)
 }
 
+// isErrInvalidObjectState returns true for errors reading archived objects, or restoring objects which are not archived
+func isErrInvalidObjectState(err error) bool {
+	var errInvalidObjectState *types.InvalidObjectState
+	return errors.As(err, &errInvalidObjectState)
+}
+
 func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io.ReadCloser, error) {
 	var err error
 	var sizeBytes int64
@@ -342,6 +357,9 @@ func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io
 	if isErrNotFound(err) {
 		return nil, block.ErrDataNotFound
 	}
+	if isErrInvalidObjectState(err) {
+		return nil, block.ErrRestoreRequired
+	}
 	if err != nil {
 		log.WithError(err).Errorf("failed to get S3 object bucket %s key %s", qualifiedKey.GetStorageNamespace(), qualifiedKey.GetKey())
 		return nil, err
@@ -350,7 +368,7 @@ func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io
 	return objectOutput.Body, nil
 }
 
-func (a *Adapter) GetWalker(uri *url.URL) (block.Walker, error) {
+func (a *Adapter) GetWalker(_ string, uri *url.URL) (block.Walker, error) {
 	if err := block.ValidateStorageType(uri, block.StorageTypeS3); err != nil {
 		return nil, err
 	}
@@ -511,6 +529,9 @@ func (a *Adapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPo
 	if isErrNotFound(err) {
 		return nil, block.ErrDataNotFound
 	}
+	if isErrInvalidObjectState(err) {
+		return nil, block.ErrRestoreRequired
+	}
 	if err != nil {
 		log.WithError(err).WithFields(logging.Fields{
 			"start_position": startPosition,
@@ -540,7 +561,8 @@ func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (b
 		return block.Properties{}, err
 	}
 	return block.Properties{
-		StorageClass: aws.String(string(s3Props.StorageClass)),
+		StorageClass:  aws.String(string(s3Props.StorageClass)),
+		ContentLength: aws.ToInt64(s3Props.ContentLength),
 	}, nil
 }
 
@@ -654,6 +676,150 @@ func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.Obje
 	return err
 }
 
+// SetStorageClass copies obj onto itself with the new storage class, keeping its metadata. Objects larger than a
+// single copy allows are copied in parts.
+func (a *Adapter) SetStorageClass(ctx context.Context, obj block.ObjectPointer, storageClass string) error {
+	var err error
+	defer reportMetrics("SetStorageClass", time.Now(), nil, &err)
+	bucket, key, qualifiedKey, err := a.extractParamsFromObj(obj)
+	if err != nil {
+		return err
+	}
+	client := a.clients.Get(ctx, bucket)
+	copySource := qualifiedKey.GetStorageNamespace() + "/" + qualifiedKey.GetKey()
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil && aws.ToInt64(head.ContentLength) > maxCopyObjectSize {
+		err = a.setStorageClassMultipart(ctx, client, bucket, key, copySource, head, storageClass)
+	} else if err == nil {
+		copyObjectInput := &s3.CopyObjectInput{
+			Bucket:            aws.String(bucket),
+			Key:               aws.String(key),
+			CopySource:        aws.String(copySource),
+			MetadataDirective: types.MetadataDirectiveCopy,
+			StorageClass:      types.StorageClass(storageClass),
+		}
+		if a.ServerSideEncryption != "" {
+			copyObjectInput.ServerSideEncryption = types.ServerSideEncryption(a.ServerSideEncryption)
+		}
+		if a.ServerSideEncryptionKmsKeyID != "" {
+			copyObjectInput.SSEKMSKeyId = aws.String(a.ServerSideEncryptionKmsKeyID)
+		}
+		_, err = client.CopyObject(ctx, copyObjectInput)
+	}
+	if isErrNotFound(err) {
+		return block.ErrDataNotFound
+	}
+	if err != nil {
+		a.log(ctx).WithError(err).WithField("storage_class", storageClass).Error("failed to set S3 object storage class")
+	}
+	return err
+}
+
+// setStorageClassMultipart copies the object described by head onto itself in parts, with the new storage class. A
+// multipart copy does not copy the object metadata, which is set on the upload instead.
+func (a *Adapter) setStorageClassMultipart(ctx context.Context, client *s3.Client, bucket, key, copySource string, head *s3.HeadObjectOutput, storageClass string) error {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		StorageClass:       types.StorageClass(storageClass),
+		Metadata:           head.Metadata,
+		ContentType:        head.ContentType,
+		ContentEncoding:    head.ContentEncoding,
+		ContentDisposition: head.ContentDisposition,
+		ContentLanguage:    head.ContentLanguage,
+		CacheControl:       head.CacheControl,
+	}
+	if a.ServerSideEncryption != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(a.ServerSideEncryption)
+	}
+	if a.ServerSideEncryptionKmsKeyID != "" {
+		input.SSEKMSKeyId = aws.String(a.ServerSideEncryptionKmsKeyID)
+	}
+	resp, err := client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return err
+	}
+	uploadID := resp.UploadId
+
+	size := aws.ToInt64(head.ContentLength)
+	partSize := max(copyPartSize, (size+int64(manager.MaxUploadParts)-1)/int64(manager.MaxUploadParts))
+	var parts []types.CompletedPart
+	for start, partNumber := int64(0), int32(1); start < size; start, partNumber = start+partSize, partNumber+1 {
+		end := min(start+partSize, size) - 1
+		var partResp *s3.UploadPartCopyOutput
+		partResp, err = client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+			Bucket:          aws.String(bucket),
+			Key:             aws.String(key),
+			PartNumber:      aws.Int32(partNumber),
+			UploadId:        uploadID,
+			CopySource:      aws.String(copySource),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err == nil && (partResp.CopyPartResult == nil || partResp.CopyPartResult.ETag == nil) {
+			err = ErrMissingETag
+		}
+		if err != nil {
+			break
+		}
+		parts = append(parts, types.CompletedPart{
+			ETag:       partResp.CopyPartResult.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+	}
+	if err == nil {
+		_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(bucket),
+			Key:             aws.String(key),
+			UploadId:        uploadID,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+	if err != nil {
+		_, abortErr := client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: uploadID,
+		})
+		if abortErr != nil {
+			a.log(ctx).WithError(abortErr).WithField("upload_id", aws.ToString(uploadID)).Warn("Failed to abort storage class copy")
+		}
+	}
+	return err
+}
+
+func (a *Adapter) Restore(ctx context.Context, obj block.ObjectPointer, days int) error {
+	var err error
+	defer reportMetrics("Restore", time.Now(), nil, &err)
+	bucket, key, _, err := a.extractParamsFromObj(obj)
+	if err != nil {
+		return err
+	}
+	restoreObjectInput := &s3.RestoreObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		RestoreRequest: &types.RestoreRequest{
+			Days: aws.Int32(int32(days)),
+		},
+	}
+	_, err = a.clients.Get(ctx, bucket).RestoreObject(ctx, restoreObjectInput)
+	var apiErr smithy.APIError
+	switch {
+	case isErrNotFound(err):
+		return block.ErrDataNotFound
+	case isErrInvalidObjectState(err):
+		// the object is not archived, it is readable as is
+		return nil
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "RestoreAlreadyInProgress":
+		return nil
+	case err != nil:
+		a.log(ctx).WithError(err).Error("failed to restore S3 object")
+	}
+	return err
+}
+
 func (a *Adapter) CreateMultiPartUpload(ctx context.Context, obj block.ObjectPointer, _ *http.Request, opts block.CreateMultiPartUploadOpts) (*block.CreateMultiPartUploadResponse, error) {
 	var err error
 	defer reportMetrics("CreateMultiPartUpload", time.Now(), nil, &err)
@@ -854,7 +1020,7 @@ func resolveNamespace(obj block.ObjectPointer) (block.CommonQualifiedKey, error)
 	return qualifiedKey, nil
 }
 
-func (a *Adapter) ResolveNamespace(storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+func (a *Adapter) ResolveNamespace(_, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
 	return block.DefaultResolveNamespace(storageNamespace, key, identifierType)
 }
 
@@ -902,7 +1068,7 @@ func (a *Adapter) managerUpload(ctx context.Context, obj block.ObjectPointer, re
 }
 
 func (a *Adapter) extractParamsFromObj(obj block.ObjectPointer) (string, string, block.QualifiedKey, error) {
-	qk, err := a.ResolveNamespace(obj.StorageNamespace, obj.Identifier, obj.IdentifierType)
+	qk, err := a.ResolveNamespace(obj.StorageID, obj.StorageNamespace, obj.Identifier, obj.IdentifierType)
 	if err != nil {
 		return "", "", nil, err
 	}
@@ -0,0 +1,44 @@
+package block
+
+import (
+	"fmt"
+)
+
+// Storage describes one of the blockstores served by an Adapter
+type Storage struct {
+	// ID identifies the blockstore, empty for the default blockstore
+	ID          string
+	Description string
+	Adapter     Adapter
+}
+
+// StorageRouter is implemented by adapters serving multiple blockstores. Each object is routed to the blockstore
+// identified by the StorageID of its ObjectPointer.
+type StorageRouter interface {
+	Adapter
+	// Storages returns the blockstores served by the adapter, the default blockstore first
+	Storages() []Storage
+	// StorageAdapter returns the adapter of the blockstore identified by storageID
+	StorageAdapter(storageID string) (Adapter, error)
+}
+
+// GetStorages returns the blockstores served by adapter. An adapter that doesn't route between multiple blockstores
+// serves only the default blockstore.
+func GetStorages(adapter Adapter) []Storage {
+	if router, ok := adapter.(StorageRouter); ok {
+		return router.Storages()
+	}
+	return []Storage{{Adapter: adapter}}
+}
+
+// GetStorageAdapter returns the adapter of the blockstore identified by storageID. An adapter that doesn't route
+// between multiple blockstores serves only the default blockstore, identified by an empty storage ID.
+func GetStorageAdapter(adapter Adapter, storageID string) (Adapter, error) {
+	if router, ok := adapter.(StorageRouter); ok {
+		return router.StorageAdapter(storageID)
+	}
+	if storageID != "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStorageID, storageID)
+	}
+	return adapter, nil
+}
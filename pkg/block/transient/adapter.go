@@ -32,7 +32,7 @@ func (a *Adapter) Get(_ context.Context, _ block.ObjectPointer, expectedSize int
 	return io.NopCloser(&io.LimitedReader{R: rand.Reader, N: expectedSize}), nil
 }
 
-func (a *Adapter) GetWalker(_ *url.URL) (block.Walker, error) {
+func (a *Adapter) GetWalker(_ string, _ *url.URL) (block.Walker, error) {
 	return nil, block.ErrOperationNotSupported
 }
 
@@ -68,6 +68,14 @@ func (a *Adapter) Copy(_ context.Context, _, _ block.ObjectPointer) error {
 	return nil
 }
 
+func (a *Adapter) SetStorageClass(_ context.Context, _ block.ObjectPointer, _ string) error {
+	return nil
+}
+
+func (a *Adapter) Restore(_ context.Context, _ block.ObjectPointer, _ int) error {
+	return nil
+}
+
 func (a *Adapter) UploadCopyPart(_ context.Context, _, _ block.ObjectPointer, _ string, _ int) (*block.UploadPartResponse, error) {
 	h := sha256.New()
 	code := h.Sum(nil)
@@ -151,7 +159,7 @@ func (a *Adapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
 	return info
 }
 
-func (a *Adapter) ResolveNamespace(storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+func (a *Adapter) ResolveNamespace(_, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
 	return block.DefaultResolveNamespace(storageNamespace, key, identifierType)
 }
 
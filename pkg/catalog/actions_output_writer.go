@@ -17,8 +17,9 @@ func NewActionsOutputWriter(blockAdapter block.Adapter) *ActionsOutputWriter {
 	}
 }
 
-func (o *ActionsOutputWriter) OutputWrite(ctx context.Context, storageNamespace, name string, reader io.Reader, size int64) error {
+func (o *ActionsOutputWriter) OutputWrite(ctx context.Context, storageID, storageNamespace, name string, reader io.Reader, size int64) error {
 	return o.adapter.Put(ctx, block.ObjectPointer{
+		StorageID:        storageID,
 		StorageNamespace: storageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       name,
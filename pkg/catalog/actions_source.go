@@ -93,9 +93,11 @@ func (s *ActionsSource) load(ctx context.Context, record graveler.HookRecord, na
 	// get action address
 	blockAdapter := s.catalog.BlockAdapter
 	reader, err := blockAdapter.Get(ctx, block.ObjectPointer{
+		StorageID:        repo.StorageID,
 		StorageNamespace: repo.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       ent.PhysicalAddress,
+		Compression:      ent.Metadata.Compression(),
 	}, 0)
 	if err != nil {
 		return nil, fmt.Errorf("getting action file %s: %w", name, err)
@@ -5,6 +5,7 @@ import (
 	"container/heap"
 	"context"
 	"crypto"
+	"crypto/ed25519"
 	_ "crypto/sha256"
 	"errors"
 	"fmt"
@@ -12,7 +13,9 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alitto/pond"
@@ -136,6 +139,9 @@ const (
 	DefaultPathDelimiter = "/"
 )
 
+// ETagMatchAny is the If-Match and If-None-Match value matching any entry
+const ETagMatchAny = "*"
+
 type DiffParams struct {
 	Limit            int
 	After            string
@@ -157,6 +163,23 @@ type CherryPickParams struct {
 	Committer    string
 }
 
+// TransactionOperation is a commit, merge or reset operation of a transaction
+type TransactionOperation struct {
+	Type       string   // one of "commit", "merge" or "reset"
+	Branch     string   // the branch to change
+	Reference  string   // the merge source or reset target
+	Message    string   // commit message of commit and merge operations
+	Metadata   Metadata // commit metadata of commit and merge operations
+	Strategy   string   // merge strategy of merge operations
+	AllowEmpty bool     // allow an empty commit
+}
+
+type TransactionParams struct {
+	Preconditions map[string]string // branch name to expected head commit ID
+	Operations    []TransactionOperation
+	Committer     string
+}
+
 type PathRecord struct {
 	Path     Path
 	IsPrefix bool
@@ -169,7 +192,28 @@ type LogParams struct {
 	Limit         bool
 	FirstParent   bool
 	Since         *time.Time
+	Until         *time.Time
 	StopAt        string
+	// Committer lists only commits made by the committer
+	Committer string
+	// Metadata lists only commits whose metadata includes all the key/value pairs
+	Metadata map[string]string
+}
+
+// match reports whether the commit passes the until, committer and metadata filters
+func (p LogParams) match(commit *graveler.CommitRecord) bool {
+	if p.Until != nil && commit.CreationDate.After(*p.Until) {
+		return false
+	}
+	if p.Committer != "" && commit.Committer != p.Committer {
+		return false
+	}
+	for k, v := range p.Metadata {
+		if value, ok := commit.Metadata[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
 }
 
 type ExpireResult struct {
@@ -225,12 +269,19 @@ type Catalog struct {
 	deleteSensor          *graveler.DeleteSensor
 	UGCPrepareMaxFileSize int64
 	UGCPrepareInterval    time.Duration
+	DedupUploads          bool
+	settingManager        *settings.Manager
+	// compressionStorages holds whether compression is enabled on each blockstore, see compressionStorages
+	compressionStorages map[string]bool
+	// replicationLocks holds the *replicationLock of each repository
+	replicationLocks sync.Map
 }
 
 const (
 	ListRepositoriesLimitMax = 1000
 	ListBranchesLimitMax     = 1000
 	ListTagsLimitMax         = 1000
+	ListReflogLimitMax       = 1000
 	DiffLimitMax             = 1000
 	ListEntriesLimitMax      = 10000
 	sharedWorkers            = 30
@@ -282,9 +333,34 @@ func (c *ctxCloser) Close() error {
 	return nil
 }
 
+// newCommitSigner returns a signer based on the commit signing configuration, or nil in case signing is not configured
+func newCommitSigner(cfg *config.Config) (graveler.CommitSigner, error) {
+	signing := cfg.Graveler.CommitSigning
+	if signing.PrivateKey == "" && len(signing.TrustedKeys) == 0 {
+		return nil, nil
+	}
+	var privateKey ed25519.PrivateKey
+	if signing.PrivateKey != "" {
+		var err error
+		privateKey, err = graveler.ParseEd25519PrivateKey(signing.PrivateKey.SecureValue())
+		if err != nil {
+			return nil, err
+		}
+	}
+	trustedKeys := make([]ed25519.PublicKey, 0, len(signing.TrustedKeys))
+	for _, k := range signing.TrustedKeys {
+		key, err := graveler.ParseEd25519PublicKey(k)
+		if err != nil {
+			return nil, err
+		}
+		trustedKeys = append(trustedKeys, key)
+	}
+	return graveler.NewEd25519CommitSigner(privateKey, trustedKeys), nil
+}
+
 func New(ctx context.Context, cfg Config) (*Catalog, error) {
 	ctx, cancelFn := context.WithCancel(ctx)
-	adapter, err := factory.BuildBlockAdapter(ctx, nil, cfg.Config)
+	adapter, err := factory.BuildStorageAdapter(ctx, nil, cfg.Config.StorageConfigs())
 	if err != nil {
 		cancelFn()
 		return nil, fmt.Errorf("build block adapter: %w", err)
@@ -292,6 +368,11 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 	if cfg.WalkerFactory == nil {
 		cfg.WalkerFactory = store.NewFactory(cfg.Config)
 	}
+	commitSigner, err := newCommitSigner(cfg.Config)
+	if err != nil {
+		cancelFn()
+		return nil, fmt.Errorf("configure commit signing: %w", err)
+	}
 
 	tierFSParams, err := pyramidparams.NewCommittedTierFSParams(cfg.Config, adapter)
 	if err != nil {
@@ -358,6 +439,7 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 			AddressProvider:       addressProvider,
 			RepositoryCacheConfig: ref.CacheConfig(cfg.Config.Graveler.RepositoryCache),
 			CommitCacheConfig:     ref.CacheConfig(cfg.Config.Graveler.CommitCache),
+			ReflogRetention:       cfg.Config.Graveler.Reflog.Retention,
 		})
 	gcManager := retention.NewGarbageCollectionManager(tierFSParams.Adapter, refManager, cfg.Config.Committed.BlockStoragePrefix)
 	settingManager := settings.NewManager(refManager, cfg.KVStore)
@@ -380,6 +462,10 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 		deleteSensor = graveler.NewDeleteSensor(cfg.Config.Graveler.CompactionSensorThreshold, cb)
 	}
 	gStore := graveler.NewGraveler(committedManager, stagingManager, refManager, gcManager, protectedBranchesManager, deleteSensor)
+	gStore.SetBranchLocker(ref.NewBranchLocker(cfg.KVStore))
+	if commitSigner != nil {
+		gStore.SetCommitSigner(commitSigner)
+	}
 
 	// The size of the workPool is determined by the number of workers and the number of desired pending tasks for each worker.
 	workPool := pond.New(sharedWorkers, sharedWorkers*pendingTasksPerWorker, pond.Context(ctx))
@@ -389,6 +475,7 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 		Store:                 gStore,
 		UGCPrepareMaxFileSize: cfg.Config.UGC.PrepareMaxFileSize,
 		UGCPrepareInterval:    cfg.Config.UGC.PrepareInterval,
+		DedupUploads:          cfg.Config.Graveler.DedupUploads,
 		PathProvider:          cfg.PathProvider,
 		BackgroundLimiter:     limiter,
 		walkerFactory:         cfg.WalkerFactory,
@@ -398,6 +485,8 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 		KVStoreLimited:        storeLimiter,
 		addressProvider:       addressProvider,
 		deleteSensor:          deleteSensor,
+		settingManager:        settingManager,
+		compressionStorages:   compressionStorages(cfg.Config),
 	}, nil
 }
 
@@ -419,8 +508,9 @@ func (c *Catalog) log(ctx context.Context) logging.Logger {
 	return logging.FromContext(ctx).WithField("service_name", "entry_catalog")
 }
 
-// CreateRepository create a new repository pointing to 'storageNamespace' (ex: s3://bucket1/repo) with default branch name 'branch'
-func (c *Catalog) CreateRepository(ctx context.Context, repository string, storageNamespace string, branch string, readOnly bool) (*Repository, error) {
+// CreateRepository create a new repository pointing to 'storageNamespace' (ex: s3://bucket1/repo) with default branch name 'branch'.
+// The storage namespace is on the blockstore of 'storageID', empty for the default blockstore.
+func (c *Catalog) CreateRepository(ctx context.Context, repository string, storageID string, storageNamespace string, branch string, readOnly bool) (*Repository, error) {
 	repositoryID := graveler.RepositoryID(repository)
 	storageNS := graveler.StorageNamespace(storageNamespace)
 	branchID := graveler.BranchID(branch)
@@ -430,12 +520,16 @@ func (c *Catalog) CreateRepository(ctx context.Context, repository string, stora
 	}); err != nil {
 		return nil, err
 	}
-	repo, err := c.Store.CreateRepository(ctx, repositoryID, storageNS, branchID, readOnly)
+	if err := c.validateStorageID(storageID); err != nil {
+		return nil, err
+	}
+	repo, err := c.Store.CreateRepository(ctx, repositoryID, graveler.StorageID(storageID), storageNS, branchID, readOnly)
 	if err != nil {
 		return nil, err
 	}
 	catalogRepo := &Repository{
 		Name:             repositoryID.String(),
+		StorageID:        repo.StorageID.String(),
 		StorageNamespace: storageNS.String(),
 		DefaultBranch:    branchID.String(),
 		CreationDate:     repo.CreationDate,
@@ -446,7 +540,7 @@ func (c *Catalog) CreateRepository(ctx context.Context, repository string, stora
 
 // CreateBareRepository create a new repository pointing to 'storageNamespace' (ex: s3://bucket1/repo) with no initial branch or commit
 // defaultBranchID will point to a non-existent branch on creation, it is up to the caller to eventually create it.
-func (c *Catalog) CreateBareRepository(ctx context.Context, repository string, storageNamespace string, defaultBranchID string, readOnly bool) (*Repository, error) {
+func (c *Catalog) CreateBareRepository(ctx context.Context, repository string, storageID string, storageNamespace string, defaultBranchID string, readOnly bool) (*Repository, error) {
 	repositoryID := graveler.RepositoryID(repository)
 	storageNS := graveler.StorageNamespace(storageNamespace)
 	branchID := graveler.BranchID(defaultBranchID)
@@ -456,12 +550,16 @@ func (c *Catalog) CreateBareRepository(ctx context.Context, repository string, s
 	}); err != nil {
 		return nil, err
 	}
-	repo, err := c.Store.CreateBareRepository(ctx, repositoryID, storageNS, branchID, readOnly)
+	if err := c.validateStorageID(storageID); err != nil {
+		return nil, err
+	}
+	repo, err := c.Store.CreateBareRepository(ctx, repositoryID, graveler.StorageID(storageID), storageNS, branchID, readOnly)
 	if err != nil {
 		return nil, err
 	}
 	catalogRepo := &Repository{
 		Name:             repositoryID.String(),
+		StorageID:        repo.StorageID.String(),
 		StorageNamespace: storageNS.String(),
 		DefaultBranch:    branchID.String(),
 		CreationDate:     repo.CreationDate,
@@ -470,6 +568,14 @@ func (c *Catalog) CreateBareRepository(ctx context.Context, repository string, s
 	return catalogRepo, nil
 }
 
+// validateStorageID verifies the storage ID identifies one of the configured blockstores
+func (c *Catalog) validateStorageID(storageID string) error {
+	if _, err := block.GetStorageAdapter(c.BlockAdapter, storageID); err != nil {
+		return fmt.Errorf("%w: %s", graveler.ErrInvalidValue, err)
+	}
+	return nil
+}
+
 func (c *Catalog) getRepository(ctx context.Context, repository string) (*graveler.RepositoryRecord, error) {
 	repositoryID := graveler.RepositoryID(repository)
 	return c.Store.GetRepository(ctx, repositoryID)
@@ -490,6 +596,7 @@ func (c *Catalog) GetRepository(ctx context.Context, repository string) (*Reposi
 
 	catalogRepository := &Repository{
 		Name:             repositoryID.String(),
+		StorageID:        repo.StorageID.String(),
 		StorageNamespace: repo.StorageNamespace.String(),
 		DefaultBranch:    repo.DefaultBranchID.String(),
 		CreationDate:     repo.CreationDate,
@@ -594,6 +701,7 @@ func (c *Catalog) ListRepositories(ctx context.Context, limit int, prefix, after
 		}
 		repos = append(repos, &Repository{
 			Name:             record.RepositoryID.String(),
+			StorageID:        record.StorageID.String(),
 			StorageNamespace: record.StorageNamespace.String(),
 			DefaultBranch:    record.DefaultBranchID.String(),
 			CreationDate:     record.CreationDate,
@@ -937,6 +1045,82 @@ func (c *Catalog) ListTags(ctx context.Context, repositoryID string, prefix stri
 	return tags, hasMore, nil
 }
 
+// ListReflog lists the movements of the branch head, newest first. The reflog of a deleted branch is kept and can be
+// listed too.
+func (c *Catalog) ListReflog(ctx context.Context, repositoryID string, branch string, limit int, after string) ([]*ReflogEntry, bool, error) {
+	if limit < 0 || limit > ListReflogLimitMax {
+		limit = ListReflogLimitMax
+	}
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	it, err := c.Store.ListReflog(ctx, repository, branchID)
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	if after != "" {
+		it.SeekGE(after)
+	}
+	var entries []*ReflogEntry
+	for it.Next() {
+		v := it.Value()
+		if v.ID == after {
+			continue
+		}
+		entries = append(entries, &ReflogEntry{
+			ID:           v.ID,
+			OldCommitID:  v.OldCommitID.String(),
+			NewCommitID:  v.NewCommitID.String(),
+			Operation:    v.Operation,
+			User:         v.User,
+			CreationDate: v.CreationDate,
+		})
+		if len(entries) >= limit+1 {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	// return results (optionally trimmed) and hasMore
+	hasMore := false
+	if len(entries) > limit {
+		hasMore = true
+		entries = entries[:limit]
+	}
+	return entries, hasMore, nil
+}
+
+// RestoreReflog points the branch back at the head it had before the movement recorded by the reflog entry, and
+// returns the restored commit ID
+func (c *Catalog) RestoreReflog(ctx context.Context, repositoryID string, branch string, entryID string, opts ...graveler.SetOptionsFunc) (string, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return "", err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	b, err := c.Store.RestoreReflog(ctx, repository, branchID, entryID, opts...)
+	if err != nil {
+		return "", err
+	}
+	return b.CommitID.String(), nil
+}
+
 func (c *Catalog) GetTag(ctx context.Context, repositoryID string, tagID string) (string, error) {
 	tag := graveler.TagID(tagID)
 	if err := validator.Validate([]validator.ValidateArg{
@@ -1045,6 +1229,27 @@ func (c *Catalog) CreateEntry(ctx context.Context, repositoryID string, branch s
 	return c.Store.Set(ctx, repository, branchID, key, *value, opts...)
 }
 
+// WithIfMatch returns a set option failing CreateEntry with graveler.ErrPreconditionFailed unless the current entry
+// of the path, staged or committed, has one of etags, or with graveler.ErrNotFound if the path has no entry. The ETag
+// ETagMatchAny matches any entry.
+func WithIfMatch(etags ...string) graveler.SetOptionsFunc {
+	return graveler.WithCondition(func(currentValue *graveler.Value) error {
+		if currentValue == nil {
+			return graveler.ErrNotFound
+		}
+		ent, err := ValueToEntry(currentValue)
+		if err != nil {
+			return err
+		}
+		for _, etag := range etags {
+			if etag == ETagMatchAny || etag == ent.ETag {
+				return nil
+			}
+		}
+		return graveler.ErrPreconditionFailed
+	})
+}
+
 func (c *Catalog) DeleteEntry(ctx context.Context, repositoryID string, branch string, path string, opts ...graveler.SetOptionsFunc) error {
 	branchID := graveler.BranchID(branch)
 	p := Path(path)
@@ -1182,7 +1387,9 @@ func (c *Catalog) ResetEntries(ctx context.Context, repositoryID string, branch
 	return c.Store.ResetPrefix(ctx, repository, branchID, keyPrefix, opts...)
 }
 
-func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, committer string, metadata Metadata, date *int64, sourceMetarange *string, allowEmpty bool, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
+// Commit commits the staged changes of the branch. Committing with 'prefixes' commits only the changes under these
+// path prefixes, the rest of the changes remain staged. The commit is signed by 'signature', if set.
+func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, committer string, metadata Metadata, date *int64, sourceMetarange *string, allowEmpty bool, prefixes []string, signature []byte, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
 	branchID := graveler.BranchID(branch)
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
@@ -1202,6 +1409,10 @@ func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, com
 		Date:       date,
 		Metadata:   map[string]string(metadata),
 		AllowEmpty: allowEmpty,
+		Signature:  signature,
+	}
+	for _, prefix := range prefixes {
+		p.Prefixes = append(p.Prefixes, graveler.Prefix(prefix))
 	}
 	if sourceMetarange != nil {
 		x := graveler.MetaRangeID(*sourceMetarange)
@@ -1211,6 +1422,7 @@ func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, com
 	if err != nil {
 		return nil, err
 	}
+	c.replicateCommits(ctx, repository, commitID)
 	catalogCommitLog := &CommitLog{
 		Reference: commitID.String(),
 		Committer: committer,
@@ -1229,6 +1441,7 @@ func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, com
 	catalogCommitLog.MetaRangeID = string(commit.MetaRangeID)
 	catalogCommitLog.Version = CommitVersion(commit.Version)
 	catalogCommitLog.Generation = CommitGeneration(commit.Generation)
+	c.setCommitSignature(catalogCommitLog, commit)
 	return catalogCommitLog, nil
 }
 
@@ -1251,7 +1464,11 @@ func (c *Catalog) CreateCommitRecord(ctx context.Context, repositoryID string, c
 		Metadata:     metadata,
 		Generation:   graveler.CommitGeneration(generation),
 	}
-	return c.Store.CreateCommitRecord(ctx, repository, graveler.CommitID(commitID), commit, opts...)
+	if err := c.Store.CreateCommitRecord(ctx, repository, graveler.CommitID(commitID), commit, opts...); err != nil {
+		return err
+	}
+	c.replicateCommits(ctx, repository, graveler.CommitID(commitID))
+	return nil
 }
 
 func (c *Catalog) GetCommit(ctx context.Context, repositoryID string, reference string) (*CommitLog, error) {
@@ -1286,6 +1503,7 @@ func (c *Catalog) GetCommit(ctx context.Context, repositoryID string, reference
 	for _, parent := range commit.Parents {
 		catalogCommitLog.Parents = append(catalogCommitLog.Parents, string(parent))
 	}
+	c.setCommitSignature(catalogCommitLog, commit)
 	return catalogCommitLog, nil
 }
 
@@ -1316,7 +1534,8 @@ func (c *Catalog) ListCommits(ctx context.Context, repositoryID string, branch s
 		}
 		params.StopAt = stopAtCommitID.String()
 	}
-	it, err := c.Store.Log(ctx, repository, commitID, params.FirstParent, params.Since)
+	// the until filter is applied while listing, after checking for stop at, a commit newer than until still stops it
+	it, err := c.Store.Log(ctx, repository, commitID, params.FirstParent, params.Since, nil)
 	if err != nil {
 		return nil, false, err
 	}
@@ -1339,7 +1558,7 @@ func (c *Catalog) ListCommits(ctx context.Context, repositoryID string, branch s
 
 	paths := params.PathList
 	if len(paths) == 0 {
-		return listCommitsWithoutPaths(it, params)
+		return c.listCommitsWithoutPaths(it, params)
 	}
 
 	return c.listCommitsWithPaths(ctx, repository, it, params)
@@ -1388,6 +1607,12 @@ func (c *Catalog) listCommitsWithPaths(ctx context.Context, repository *graveler
 			if len(commitRecord.Parents) != NumberOfParentsOfNonMergeCommit {
 				continue
 			}
+			if !params.match(commitRecord) {
+				if commitRecord.CommitID.String() == params.StopAt {
+					break readLoop
+				}
+				continue
+			}
 
 			// submit work to the pool
 			commitOrder := current
@@ -1399,7 +1624,7 @@ func (c *Catalog) listCommitsWithPaths(ctx context.Context, repository *graveler
 				}
 				job := &commitLogJob{order: commitOrder}
 				if pathInCommit {
-					job.log = CommitRecordToLog(commitRecord)
+					job.log = c.commitRecordToLog(commitRecord)
 				}
 				outCh <- job
 				return nil
@@ -1465,13 +1690,19 @@ func (c *Catalog) listCommitsWithPaths(ctx context.Context, repository *graveler
 	return logCommitsResult(commits, params)
 }
 
-func listCommitsWithoutPaths(it graveler.CommitIterator, params LogParams) ([]*CommitLog, bool, error) {
+func (c *Catalog) listCommitsWithoutPaths(it graveler.CommitIterator, params LogParams) ([]*CommitLog, bool, error) {
 	// no need to parallelize here - just read the commits
 	var commits []*CommitLog
 	for it.Next() {
 		val := it.Value()
+		if !params.match(val) {
+			if val.CommitID.String() == params.StopAt {
+				break
+			}
+			continue
+		}
 
-		commits = append(commits, CommitRecordToLog(val))
+		commits = append(commits, c.commitRecordToLog(val))
 		if foundAllCommits(params, commits) {
 			// All results returned until the last commit found
 			// and the number of commits found is as expected.
@@ -1505,6 +1736,7 @@ func CommitRecordToLog(val *graveler.CommitRecord) *CommitLog {
 		Parents:      make([]string, 0, len(val.Parents)),
 		Version:      CommitVersion(val.Version),
 		Generation:   CommitGeneration(val.Generation),
+		Signature:    val.Signature,
 	}
 	for _, parent := range val.Parents {
 		commit.Parents = append(commit.Parents, parent.String())
@@ -1512,6 +1744,24 @@ func CommitRecordToLog(val *graveler.CommitRecord) *CommitLog {
 	return commit
 }
 
+// commitRecordToLog returns the commit log of the record, reporting whether its signature was made by a trusted key
+func (c *Catalog) commitRecordToLog(val *graveler.CommitRecord) *CommitLog {
+	commit := CommitRecordToLog(val)
+	if commit != nil {
+		c.setCommitSignature(commit, val.Commit)
+	}
+	return commit
+}
+
+// setCommitSignature sets the commit signature on the commit log, and whether it was made by a trusted key
+func (c *Catalog) setCommitSignature(commitLog *CommitLog, commit *graveler.Commit) {
+	if commit.Signature == nil {
+		return
+	}
+	commitLog.Signature = commit.Signature
+	commitLog.Verified = c.Store.VerifyCommitSignature(*commit)
+}
+
 func logCommitsResult(commits []*CommitLog, params LogParams) ([]*CommitLog, bool, error) {
 	hasMore := false
 	if len(commits) > params.Amount {
@@ -1673,8 +1923,12 @@ func (c *Catalog) Revert(ctx context.Context, repositoryID string, branch string
 	if err != nil {
 		return err
 	}
-	_, err = c.Store.Revert(ctx, repository, branchID, reference, parentNumber, commitParams, opts...)
-	return err
+	commitID, err := c.Store.Revert(ctx, repository, branchID, reference, parentNumber, commitParams, opts...)
+	if err != nil {
+		return err
+	}
+	c.replicateCommits(ctx, repository, commitID)
+	return nil
 }
 
 func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch string, params CherryPickParams, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
@@ -1699,6 +1953,7 @@ func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch st
 	if err != nil {
 		return nil, err
 	}
+	c.replicateCommits(ctx, repository, commitID)
 
 	// in order to return commit log we need the commit creation time and parents
 	commit, err := c.Store.GetCommit(ctx, repository, commitID)
@@ -1719,9 +1974,91 @@ func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch st
 	for _, parent := range commit.Parents {
 		catalogCommitLog.Parents = append(catalogCommitLog.Parents, parent.String())
 	}
+	c.setCommitSignature(catalogCommitLog, commit)
 	return catalogCommitLog, nil
 }
 
+// Rebase replays the commits of 'branch' since its merge base with 'ontoRef' on top of 'ontoRef', and returns the
+// new head commit of the branch.
+func (c *Catalog) Rebase(ctx context.Context, repositoryID string, branch string, ontoRef string, committer string, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
+	branchID := graveler.BranchID(branch)
+	onto := graveler.Ref(ontoRef)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "ref", Value: onto, Fn: graveler.ValidateRef},
+		{Name: "committer", Value: committer, Fn: validator.ValidateRequiredString},
+	}); err != nil {
+		return nil, err
+	}
+
+	// disabling batching for this flow. See #3935 for more details
+	ctx = context.WithValue(ctx, batch.SkipBatchContextKey, struct{}{})
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	branchRecord, err := c.Store.GetBranch(ctx, repository, branchID)
+	if err != nil {
+		return nil, err
+	}
+	ontoCommitID, err := c.dereferenceCommitID(ctx, repository, onto)
+	if err != nil {
+		return nil, err
+	}
+	commitID, err := c.Store.Rebase(ctx, repository, branchID, onto, committer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if commitID != branchRecord.CommitID {
+		c.replicateCommits(ctx, repository, c.rebasedCommits(ctx, repository, commitID, ontoCommitID)...)
+	}
+
+	commit, err := c.Store.GetCommit(ctx, repository, commitID)
+	if err != nil {
+		return nil, graveler.ErrCommitNotFound
+	}
+
+	catalogCommitLog := &CommitLog{
+		Reference:    commitID.String(),
+		Committer:    commit.Committer,
+		Message:      commit.Message,
+		CreationDate: commit.CreationDate.UTC(),
+		MetaRangeID:  string(commit.MetaRangeID),
+		Metadata:     Metadata(commit.Metadata),
+		Version:      CommitVersion(commit.Version),
+		Generation:   CommitGeneration(commit.Generation),
+	}
+	for _, parent := range commit.Parents {
+		catalogCommitLog.Parents = append(catalogCommitLog.Parents, parent.String())
+	}
+	c.setCommitSignature(catalogCommitLog, commit)
+	return catalogCommitLog, nil
+}
+
+// rebasedCommits returns the commits replayed by a rebase onto ontoCommitID, the first-parent history of headCommitID
+// down to ontoCommitID, oldest first. It returns only the head in case the history cannot be read.
+func (c *Catalog) rebasedCommits(ctx context.Context, repository *graveler.RepositoryRecord, headCommitID, ontoCommitID graveler.CommitID) []graveler.CommitID {
+	it, err := c.Store.Log(ctx, repository, headCommitID, true, nil, nil)
+	if err != nil {
+		c.log(ctx).WithError(err).WithField("commit_id", headCommitID).Warn("Failed to list rebased commits")
+		return []graveler.CommitID{headCommitID}
+	}
+	defer it.Close()
+	var commitIDs []graveler.CommitID
+	for it.Next() && it.Value().CommitID != ontoCommitID {
+		commitIDs = append(commitIDs, it.Value().CommitID)
+	}
+	if err := it.Err(); err != nil {
+		c.log(ctx).WithError(err).WithField("commit_id", headCommitID).Warn("Failed to list rebased commits")
+		return []graveler.CommitID{headCommitID}
+	}
+	slices.Reverse(commitIDs)
+	return commitIDs
+}
+
 func (c *Catalog) Diff(ctx context.Context, repositoryID string, leftReference string, rightReference string, params DiffParams) (Differences, bool, error) {
 	left := graveler.Ref(leftReference)
 	right := graveler.Ref(rightReference)
@@ -1770,6 +2107,40 @@ func (c *Catalog) Compare(ctx context.Context, repositoryID, leftReference strin
 	return listDiffHelper(it, params.Prefix, params.Delimiter, params.Limit, params.After)
 }
 
+// DiffSummary returns the number and size of the objects changed between the references, grouped by path prefixes
+func (c *Catalog) DiffSummary(ctx context.Context, repositoryID, leftReference, rightReference string, params DiffSummaryParams) (*DiffSummary, error) {
+	left := graveler.Ref(leftReference)
+	right := graveler.Ref(rightReference)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "left", Value: left, Fn: graveler.ValidateRef},
+		{Name: "right", Value: right, Fn: graveler.ValidateRef},
+		{Name: "depth", Value: params.Depth, Fn: validator.ValidateNonNegativeInt},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	diffFunc := c.Store.Diff
+	if params.ThreeDot {
+		diffFunc = c.Store.Compare
+	}
+	iter, err := diffFunc(ctx, repository, left, right)
+	if err != nil {
+		return nil, err
+	}
+	it := NewEntryDiffIterator(iter)
+	defer it.Close()
+	depth := params.Depth
+	if depth > DiffSummaryMaxDepth {
+		depth = DiffSummaryMaxDepth
+	}
+	return summarizeDiff(it, params.Prefix, depth)
+}
+
 func (c *Catalog) DiffUncommitted(ctx context.Context, repositoryID, branch, prefix, delimiter string, limit int, after string) (Differences, bool, error) {
 	branchID := graveler.BranchID(branch)
 	if err := validator.Validate([]validator.ValidateArg{
@@ -1882,7 +2253,9 @@ func listDiffHelper(it EntryDiffIterator, prefix, delimiter string, limit int, a
 	return diffs, hasMore, nil
 }
 
-func (c *Catalog) Merge(ctx context.Context, repositoryID string, destinationBranch string, sourceRef string, committer string, message string, metadata Metadata, strategy string, opts ...graveler.SetOptionsFunc) (string, error) {
+// Merge merges 'sourceRef' into 'destinationBranch' using 'strategy'. A squash merge creates a merge commit with the
+// destination as its only parent.
+func (c *Catalog) Merge(ctx context.Context, repositoryID string, destinationBranch string, sourceRef string, committer string, message string, metadata Metadata, strategy string, squash bool, opts ...graveler.SetOptionsFunc) (string, error) {
 	destination := graveler.BranchID(destinationBranch)
 	source := graveler.Ref(sourceRef)
 	meta := graveler.Metadata(metadata)
@@ -1900,7 +2273,7 @@ func (c *Catalog) Merge(ctx context.Context, repositoryID string, destinationBra
 		{Name: "source", Value: source, Fn: graveler.ValidateRef},
 		{Name: "committer", Value: commitParams.Committer, Fn: validator.ValidateRequiredString},
 		{Name: "message", Value: commitParams.Message, Fn: validator.ValidateRequiredString},
-		{Name: "strategy", Value: strategy, Fn: graveler.ValidateRequiredStrategy},
+		{Name: "strategy", Value: strategy, Fn: validateMergeStrategy},
 	}); err != nil {
 		return "", err
 	}
@@ -1913,13 +2286,159 @@ func (c *Catalog) Merge(ctx context.Context, repositoryID string, destinationBra
 		return "", err
 	}
 
-	commitID, err := c.Store.Merge(ctx, repository, destination, source, commitParams, strategy, opts...)
+	resolver, err := c.mergeResolver(ctx, repository, destination, strategy)
 	if err != nil {
 		return "", err
 	}
+	mergeOptions := graveler.MergeOptions{
+		Resolver: resolver,
+		Squash:   squash,
+	}
+
+	commitID, err := c.Store.Merge(ctx, repository, destination, source, commitParams, strategy, mergeOptions, opts...)
+	if err != nil {
+		return "", err
+	}
+	c.replicateCommits(ctx, repository, commitID)
 	return commitID.String(), nil
 }
 
+// Transaction applies the operations on the branches of the repository all together, or none of them in case of a
+// failure or an unmet precondition. It returns the new head commit ID of each changed branch.
+func (c *Catalog) Transaction(ctx context.Context, repositoryID string, params TransactionParams, opts ...graveler.SetOptionsFunc) (map[string]string, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "committer", Value: params.Committer, Fn: validator.ValidateRequiredString},
+	}); err != nil {
+		return nil, err
+	}
+	txParams := graveler.TransactionParams{
+		Preconditions: make(map[graveler.BranchID]graveler.CommitID, len(params.Preconditions)),
+		Operations:    make([]graveler.TransactionOperation, 0, len(params.Operations)),
+	}
+	for branch, commitID := range params.Preconditions {
+		branchID := graveler.BranchID(branch)
+		if err := validator.Validate([]validator.ValidateArg{
+			{Name: "precondition branch", Value: branchID, Fn: graveler.ValidateBranchID},
+			{Name: "precondition commit", Value: commitID, Fn: validator.ValidateRequiredString},
+		}); err != nil {
+			return nil, err
+		}
+		txParams.Preconditions[branchID] = graveler.CommitID(commitID)
+	}
+	for _, op := range params.Operations {
+		if op.Type == string(graveler.TransactionOperationMerge) {
+			if err := validator.Validate([]validator.ValidateArg{
+				{Name: "strategy", Value: op.Strategy, Fn: validateMergeStrategy},
+			}); err != nil {
+				return nil, err
+			}
+		}
+		txParams.Operations = append(txParams.Operations, graveler.TransactionOperation{
+			Type:     graveler.TransactionOperationType(op.Type),
+			BranchID: graveler.BranchID(op.Branch),
+			Ref:      graveler.Ref(op.Reference),
+			CommitParams: graveler.CommitParams{
+				Committer:  params.Committer,
+				Message:    op.Message,
+				Metadata:   graveler.Metadata(op.Metadata),
+				AllowEmpty: op.AllowEmpty,
+			},
+			Strategy: op.Strategy,
+		})
+	}
+
+	// disabling batching for this flow. See #3935 for more details
+	ctx = context.WithValue(ctx, batch.SkipBatchContextKey, struct{}{})
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range txParams.Operations {
+		if op.Type != graveler.TransactionOperationMerge {
+			continue
+		}
+		txParams.Operations[i].MergeResolver, err = c.mergeResolver(ctx, repository, op.BranchID, op.Strategy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	heads, err := c.Store.Transaction(ctx, repository, txParams, opts...)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(heads))
+	commitIDs := make([]graveler.CommitID, 0, len(heads))
+	for branchID, commitID := range heads {
+		result[branchID.String()] = commitID.String()
+		commitIDs = append(commitIDs, commitID)
+	}
+	c.replicateCommits(ctx, repository, commitIDs...)
+	return result, nil
+}
+
+// ListMergeConflicts lists the objects conflicting when merging 'sourceRef' into 'destinationBranch' using 'strategy',
+// without performing the merge.
+func (c *Catalog) ListMergeConflicts(ctx context.Context, repositoryID string, destinationBranch string, sourceRef string, strategy string, limit int, after string) ([]*MergeConflict, bool, error) {
+	destination := graveler.BranchID(destinationBranch)
+	source := graveler.Ref(sourceRef)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "destination", Value: destination, Fn: graveler.ValidateBranchID},
+		{Name: "source", Value: source, Fn: graveler.ValidateRef},
+		{Name: "strategy", Value: strategy, Fn: validateMergeStrategy},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > DiffLimitMax {
+		limit = DiffLimitMax
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resolver, err := c.mergeResolver(ctx, repository, destination, strategy)
+	if err != nil {
+		return nil, false, err
+	}
+
+	conflicts, hasMore, err := c.Store.MergeConflicts(ctx, repository, destination, source, strategy, resolver, graveler.Key(after), limit)
+	if err != nil {
+		return nil, false, err
+	}
+	res := make([]*MergeConflict, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		path := conflict.Key.String()
+		mergeConflict := &MergeConflict{Path: path}
+		if mergeConflict.Base, err = conflictEntry(path, conflict.Base); err != nil {
+			return nil, false, err
+		}
+		if mergeConflict.Source, err = conflictEntry(path, conflict.Source); err != nil {
+			return nil, false, err
+		}
+		if mergeConflict.Destination, err = conflictEntry(path, conflict.Destination); err != nil {
+			return nil, false, err
+		}
+		res = append(res, mergeConflict)
+	}
+	return res, hasMore, nil
+}
+
+func conflictEntry(path string, value *graveler.Value) (*DBEntry, error) {
+	if value == nil {
+		return nil, nil
+	}
+	ent, err := ValueToEntry(value)
+	if err != nil {
+		return nil, fmt.Errorf("entry %s: %w", path, err)
+	}
+	entry := newCatalogEntryFromEntry(false, path, ent)
+	return &entry, nil
+}
+
 func (c *Catalog) FindMergeBase(ctx context.Context, repositoryID string, destinationRef string, sourceRef string) (string, string, string, error) {
 	destination := graveler.Ref(destinationRef)
 	source := graveler.Ref(sourceRef)
@@ -2320,6 +2839,7 @@ func (c *Catalog) importAsync(repository *graveler.RepositoryRecord, branchID, i
 		importManager.SetError(importError)
 		return importError
 	}
+	c.replicateCommits(ctx, repository, commitID)
 
 	commit, err := c.Store.GetCommit(ctx, repository, commitID)
 	if err != nil {
@@ -2534,6 +3054,7 @@ type GCUncommittedMark struct {
 	Path     Path              `json:"path"`
 	RunID    string            `json:"run_id"`
 	Key      string            `json:"key"`
+	DedupKey string            `json:"dedup_key"`
 }
 
 type PrepareGCUncommittedInfo struct {
@@ -2548,7 +3069,7 @@ type UncommittedParquetObject struct {
 	CreationDate    int64  `parquet:"name=creation_date, type=INT64, convertedtype=INT_64"`
 }
 
-func (c *Catalog) uploadFile(ctx context.Context, ns graveler.StorageNamespace, location string, fd *os.File, size int64) (string, error) {
+func (c *Catalog) uploadFile(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, location string, fd *os.File, size int64) (string, error) {
 	_, err := fd.Seek(0, 0)
 	if err != nil {
 		return "", err
@@ -2560,6 +3081,7 @@ func (c *Catalog) uploadFile(ctx context.Context, ns graveler.StorageNamespace,
 		return "", err
 	}
 	obj := block.ObjectPointer{
+		StorageID:        storageID.String(),
 		StorageNamespace: ns.String(),
 		Identifier:       identifier,
 		IdentifierType:   block.IdentifierTypeFull,
@@ -2607,7 +3129,7 @@ func (c *Catalog) PrepareGCUncommitted(ctx context.Context, repositoryID string,
 	uw := NewUncommittedWriter(fd)
 
 	// Write parquet to local storage
-	newMark, hasData, err := gcWriteUncommitted(ctx, c.Store, repository, uw, mark, runID, c.UGCPrepareMaxFileSize, c.UGCPrepareInterval)
+	newMark, hasData, err := gcWriteUncommitted(ctx, c.Store, c.KVStore, repository, uw, mark, runID, c.UGCPrepareMaxFileSize, c.UGCPrepareInterval)
 	if err != nil {
 		return nil, err
 	}
@@ -2623,7 +3145,7 @@ func (c *Catalog) PrepareGCUncommitted(ctx context.Context, repositoryID string,
 			return nil, err
 		}
 
-		name, err = c.uploadFile(ctx, repository.StorageNamespace, uncommittedLocation, fd, uw.Size())
+		name, err = c.uploadFile(ctx, repository.StorageID, repository.StorageNamespace, uncommittedLocation, fd, uw.Size())
 		if err != nil {
 			return nil, err
 		}
@@ -2637,8 +3159,16 @@ func (c *Catalog) PrepareGCUncommitted(ctx context.Context, repositoryID string,
 	}, nil
 }
 
+// CopyEntryParams controls how CopyEntry copies the data of an entry
+type CopyEntryParams struct {
+	// Shallow references the data of the source entry from the destination entry, rather than copying it to a new
+	// physical address
+	Shallow bool
+}
+
 // CopyEntry copy entry information by using the block adapter to make a copy of the data to a new physical address.
-func (c *Catalog) CopyEntry(ctx context.Context, srcRepository, srcRef, srcPath, destRepository, destBranch, destPath string, opts ...graveler.SetOptionsFunc) (*DBEntry, error) {
+// A shallow copy references the physical address of the source entry instead, see shareEntryAddress.
+func (c *Catalog) CopyEntry(ctx context.Context, srcRepository, srcRef, srcPath, destRepository, destBranch, destPath string, params CopyEntryParams, opts ...graveler.SetOptionsFunc) (*DBEntry, error) {
 	// copyObjectFull copy data from srcEntry's physical address (if set) or srcPath into destPath
 	// fetch src entry if needed - optimization in case we already have the entry
 	srcEntry, err := c.GetEntry(ctx, srcRepository, srcRef, srcPath, GetEntryParams{})
@@ -2647,42 +3177,53 @@ func (c *Catalog) CopyEntry(ctx context.Context, srcRepository, srcRef, srcPath,
 	}
 
 	// load repositories information for storage namespace
-	destRepo, err := c.GetRepository(ctx, destRepository)
+	destRepo, err := c.getRepository(ctx, destRepository)
 	if err != nil {
 		return nil, err
 	}
 
 	srcRepo := destRepo
 	if srcRepository != destRepository {
-		srcRepo, err = c.GetRepository(ctx, srcRepository)
+		srcRepo, err = c.getRepository(ctx, srcRepository)
 		if err != nil {
 			return nil, err
 		}
 	}
+	return c.copyEntry(ctx, srcRepo, srcEntry, destRepo, destBranch, destPath, params, opts...)
+}
 
-	// copy data to a new physical address
+func (c *Catalog) copyEntry(ctx context.Context, srcRepo *graveler.RepositoryRecord, srcEntry *DBEntry, destRepo *graveler.RepositoryRecord, destBranch, destPath string, params CopyEntryParams, opts ...graveler.SetOptionsFunc) (*DBEntry, error) {
 	dstEntry := *srcEntry
 	dstEntry.CreationDate = time.Now()
 	dstEntry.Path = destPath
-	dstEntry.AddressType = AddressTypeRelative
-	dstEntry.PhysicalAddress = c.PathProvider.NewPath()
-	srcObject := block.ObjectPointer{
-		StorageNamespace: srcRepo.StorageNamespace,
-		IdentifierType:   srcEntry.AddressType.ToIdentifierType(),
-		Identifier:       srcEntry.PhysicalAddress,
-	}
-	destObj := block.ObjectPointer{
-		StorageNamespace: destRepo.StorageNamespace,
-		IdentifierType:   dstEntry.AddressType.ToIdentifierType(),
-		Identifier:       dstEntry.PhysicalAddress,
-	}
-	err = c.BlockAdapter.Copy(ctx, srcObject, destObj)
-	if err != nil {
-		return nil, err
+	if params.Shallow {
+		if err := c.shareEntryAddress(ctx, srcRepo, destRepo, &dstEntry); err != nil {
+			return nil, err
+		}
+	} else {
+		// copy data to a new physical address
+		dstEntry.AddressType = AddressTypeRelative
+		dstEntry.PhysicalAddress = c.PathProvider.NewPath()
+		srcObject := block.ObjectPointer{
+			StorageID:        srcRepo.StorageID.String(),
+			StorageNamespace: srcRepo.StorageNamespace.String(),
+			IdentifierType:   srcEntry.AddressType.ToIdentifierType(),
+			Identifier:       srcEntry.PhysicalAddress,
+		}
+		destObj := block.ObjectPointer{
+			StorageID:        destRepo.StorageID.String(),
+			StorageNamespace: destRepo.StorageNamespace.String(),
+			IdentifierType:   dstEntry.AddressType.ToIdentifierType(),
+			Identifier:       dstEntry.PhysicalAddress,
+		}
+		err := c.BlockAdapter.Copy(ctx, srcObject, destObj)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// create entry for the final copy
-	err = c.CreateEntry(ctx, destRepository, destBranch, dstEntry, opts...)
+	err := c.CreateEntry(ctx, destRepo.RepositoryID.String(), destBranch, dstEntry, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -18,6 +18,7 @@ import (
 	cUtils "github.com/treeverse/lakefs/pkg/catalog/testutils"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	gUtils "github.com/treeverse/lakefs/pkg/graveler/testutil"
+	kvmock "github.com/treeverse/lakefs/pkg/kv/mock"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/xitongsys/parquet-go-source/buffer"
 	"github.com/xitongsys/parquet-go/reader"
@@ -710,11 +711,25 @@ func createPrepareUncommittedTestScenario(t *testing.T, repositoryID string, num
 		test.StagingManager.EXPECT().List(gomock.Any(), branches[i].StagingToken, gomock.Any()).AnyTimes().Return(cUtils.NewFakeValueIterator(records[i]))
 	}
 
+	// no addresses are shared with other repositories
+	sharedIt := kvmock.NewMockEntriesIterator(test.Controller)
+	sharedIt.EXPECT().Next().AnyTimes().Return(false)
+	sharedIt.EXPECT().Err().AnyTimes().Return(nil)
+	sharedIt.EXPECT().Close().AnyTimes()
+	test.KVStore.EXPECT().Scan(gomock.Any(), []byte("shared-addresses"), gomock.Any()).Times(1).Return(sharedIt, nil)
+
+	// no uploaded objects were deduplicated
+	dedupIt := kvmock.NewMockEntriesIterator(test.Controller)
+	dedupIt.EXPECT().Next().AnyTimes().Return(false)
+	dedupIt.EXPECT().Err().AnyTimes().Return(nil)
+	dedupIt.EXPECT().Close().AnyTimes()
+	test.KVStore.EXPECT().Scan(gomock.Any(), []byte(graveler.RepoPartition(repository)), gomock.Any()).Times(1).Return(dedupIt, nil)
+
 	if numRecords > 0 {
 		test.GarbageCollectionManager.EXPECT().
-			GetUncommittedLocation(gomock.Any(), gomock.Any()).
+			GetUncommittedLocation(gomock.Any(), gomock.Any(), gomock.Any()).
 			Times(expectedCalls).
-			DoAndReturn(func(runID string, sn graveler.StorageNamespace) (string, error) {
+			DoAndReturn(func(runID string, storageID graveler.StorageID, sn graveler.StorageNamespace) (string, error) {
 				return fmt.Sprintf("%s/retention/gc/uncommitted/%s/uncommitted/", "_lakefs", runID), nil
 			})
 	}
@@ -752,3 +767,27 @@ func readPhysicalAddressesFromParquetObject(t *testing.T, repositoryID string, c
 	}
 	return records
 }
+
+func TestWithIfMatch(t *testing.T) {
+	value := catalog.MustEntryToValue(&catalog.Entry{Address: "a", ETag: "etag1", Size: 1})
+	cases := []struct {
+		name        string
+		etags       []string
+		value       *graveler.Value
+		expectedErr error
+	}{
+		{name: "match", etags: []string{"etag1"}, value: value},
+		{name: "match_list", etags: []string{"etag0", "etag1"}, value: value},
+		{name: "match_any", etags: []string{catalog.ETagMatchAny}, value: value},
+		{name: "mismatch", etags: []string{"etag2"}, value: value, expectedErr: graveler.ErrPreconditionFailed},
+		{name: "no_entry", etags: []string{"etag1"}, expectedErr: graveler.ErrNotFound},
+		{name: "no_entry_any", etags: []string{catalog.ETagMatchAny}, expectedErr: graveler.ErrNotFound},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts graveler.SetOptions
+			catalog.WithIfMatch(tt.etags...)(&opts)
+			require.ErrorIs(t, opts.Condition(tt.value), tt.expectedErr)
+		})
+	}
+}
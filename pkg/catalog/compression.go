@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/block/compression"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const compressionSettingKey = "compression"
+
+// CompressionMetadataKey is the entry metadata key marking an object stored compressed, its value is the codec the
+// object was compressed with. The codec is passed to the block adapter on the ObjectPointer of the object to read it.
+const CompressionMetadataKey = "::lakefs::compression"
+
+// Compression returns the codec marked in the metadata, empty for objects stored as is
+func (m Metadata) Compression() string {
+	return m[CompressionMetadataKey]
+}
+
+// WithCompression returns a copy of the metadata marked with codec, or not marked for an empty codec
+func (m Metadata) WithCompression(codec string) Metadata {
+	metadata := make(Metadata, len(m)+1)
+	for k, v := range m {
+		metadata[k] = v
+	}
+	if codec == "" {
+		delete(metadata, CompressionMetadataKey)
+	} else {
+		metadata[CompressionMetadataKey] = codec
+	}
+	return metadata
+}
+
+// compressionStorages returns whether compression is enabled on each blockstore by its ID, also on the default
+// blockstore by an empty ID
+func compressionStorages(cfg *config.Config) map[string]bool {
+	storages := make(map[string]bool)
+	for i, storage := range cfg.StorageConfigs() {
+		enabled := storage.BlockstoreCompressionParams().Enabled
+		storages[storage.BlockstoreID()] = enabled
+		if i == 0 {
+			storages[""] = enabled
+		}
+	}
+	return storages
+}
+
+// GetCompressionRules returns the compression rules of the repository, no rules in case none were set
+func (c *Catalog) GetCompressionRules(ctx context.Context, repositoryID string) (*CompressionRules, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	rules := &CompressionRules{}
+	_, err = c.settingManager.GetLatest(ctx, repository, compressionSettingKey, rules)
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SetCompressionRules sets the compression rules of the repository. Rules are rejected in case compression is
+// disabled on the blockstore of the repository, as objects would be stored as is.
+func (c *Catalog) SetCompressionRules(ctx context.Context, repositoryID string, rules *CompressionRules) error {
+	for i, rule := range rules.Rules {
+		if !compression.IsValidCodec(rule.Codec) {
+			return fmt.Errorf("rule %d codec '%s': %w", i, rule.Codec, graveler.ErrInvalidValue)
+		}
+		if len(rule.ContentTypes) == 0 && len(rule.Extensions) == 0 {
+			return fmt.Errorf("rule %d matches no objects: %w", i, graveler.ErrInvalidValue)
+		}
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if repository.ReadOnly {
+		return graveler.ErrReadOnlyRepository
+	}
+	if len(rules.Rules) > 0 && !c.compressionStorages[repository.StorageID.String()] {
+		return fmt.Errorf("blockstore compression is disabled: %w", graveler.ErrInvalidValue)
+	}
+	return c.settingManager.Save(ctx, repository, compressionSettingKey, rules, nil)
+}
+
+// CompressionCodec returns the codec compressing an object uploaded to the repository, selected by the first
+// compression rule matching its content type or the extension of its path. Returns an empty string in case no rule
+// matches, or compression is disabled on the blockstore of the repository.
+func (c *Catalog) CompressionCodec(ctx context.Context, repositoryID, objectPath, contentType string) (string, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	if !c.compressionStorages[repository.StorageID.String()] {
+		return "", nil
+	}
+	rules := &CompressionRules{}
+	err = c.settingManager.Get(ctx, repository, compressionSettingKey, rules)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(contentType)
+	}
+	ext := strings.ToLower(path.Ext(objectPath))
+	for _, rule := range rules.Rules {
+		if matchCompressionRule(rule, mediaType, ext) {
+			return rule.Codec, nil
+		}
+	}
+	return "", nil
+}
+
+// matchCompressionRule matches the content types of the rule, either exactly or by a "type/*" pattern, and its
+// extensions, given with or without a leading dot
+func matchCompressionRule(rule *CompressionRule, mediaType, ext string) bool {
+	for _, ct := range rule.ContentTypes {
+		ct = strings.ToLower(ct)
+		if ct == mediaType || strings.HasSuffix(ct, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(ct, "*")) {
+			return true
+		}
+	}
+	if ext == "" {
+		return false
+	}
+	for _, e := range rule.Extensions {
+		if "."+strings.TrimPrefix(strings.ToLower(e), ".") == ext {
+			return true
+		}
+	}
+	return false
+}
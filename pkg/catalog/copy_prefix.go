@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	CopyPrefixTaskIDPrefix = "CP"
+
+	// copyPrefixListLimit is the number of entries listed and copied at once, progress is updated after each batch
+	copyPrefixListLimit = 1000
+	// copyPrefixConcurrency is the number of entries of a batch copied concurrently
+	copyPrefixConcurrency = 16
+)
+
+// CopyPrefixAuthorizeFunc returns an error if the entry at srcPath may not be copied to destPath
+type CopyPrefixAuthorizeFunc func(ctx context.Context, srcPath, destPath string) error
+
+// CopyPrefixSubmit starts a background copy of the entries under srcPrefix of srcRef to destPrefix of destBranch,
+// which may be on another repository. Each entry is copied as by CopyEntry, and its path under srcPrefix is kept
+// under destPrefix. The status of the copy is kept on the destination repository.
+// When authorize is set, every entry of a batch is authorized before the batch is copied, and the copy fails on the
+// first entry that is not authorized.
+func (c *Catalog) CopyPrefixSubmit(ctx context.Context, srcRepository, srcRef, srcPrefix, destRepository, destBranch, destPrefix string, params CopyEntryParams, authorize CopyPrefixAuthorizeFunc, opts ...graveler.SetOptionsFunc) (string, error) {
+	srcRepo, err := c.getRepository(ctx, srcRepository)
+	if err != nil {
+		return "", err
+	}
+	destRepo, err := c.getRepository(ctx, destRepository)
+	if err != nil {
+		return "", err
+	}
+	options := &graveler.SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if destRepo.ReadOnly && !options.Force {
+		return "", graveler.ErrReadOnlyRepository
+	}
+	if _, err := c.Store.GetBranch(ctx, destRepo, graveler.BranchID(destBranch)); err != nil {
+		return "", err
+	}
+
+	taskStatus := &CopyPrefixStatus{}
+	taskSteps := []taskStep{
+		{
+			Name: "count objects",
+			Func: func(ctx context.Context) error {
+				return c.listPrefix(ctx, srcRepository, srcRef, srcPrefix, func(entries []*DBEntry) error {
+					taskStatus.TotalObjects += int64(len(entries))
+					return nil
+				})
+			},
+		},
+		{
+			Name: "copy objects",
+			Func: func(ctx context.Context) error {
+				return c.listPrefix(ctx, srcRepository, srcRef, srcPrefix, func(entries []*DBEntry) error {
+					destPaths := make([]string, len(entries))
+					for i, entry := range entries {
+						destPaths[i] = destPrefix + strings.TrimPrefix(entry.Path, srcPrefix)
+						if authorize == nil {
+							continue
+						}
+						if err := authorize(ctx, entry.Path, destPaths[i]); err != nil {
+							return fmt.Errorf("copy %s: %w", entry.Path, err)
+						}
+					}
+					g, gctx := errgroup.WithContext(ctx)
+					g.SetLimit(copyPrefixConcurrency)
+					for i, entry := range entries {
+						entry := entry
+						destPath := destPaths[i]
+						g.Go(func() error {
+							if _, err := c.copyEntry(gctx, srcRepo, entry, destRepo, destBranch, destPath, params, opts...); err != nil {
+								return fmt.Errorf("copy %s: %w", entry.Path, err)
+							}
+							return nil
+						})
+					}
+					if err := g.Wait(); err != nil {
+						return err
+					}
+					for _, entry := range entries {
+						taskStatus.CopiedBytes += entry.Size
+					}
+					taskStatus.CopiedObjects += int64(len(entries))
+					taskStatus.LastPath = entries[len(entries)-1].Path
+					taskStatus.Task.UpdatedAt = timestamppb.Now()
+					return UpdateTaskStatus(ctx, c.KVStore, destRepo, taskStatus.Task.Id, taskStatus)
+				})
+			},
+		},
+	}
+	taskID := NewTaskID(CopyPrefixTaskIDPrefix)
+	if err := c.runBackgroundTaskSteps(destRepo, taskID, taskSteps, taskStatus); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+func (c *Catalog) CopyPrefixStatus(ctx context.Context, repositoryID string, id string) (*CopyPrefixStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTaskID(CopyPrefixTaskIDPrefix, id) {
+		return nil, graveler.ErrNotFound
+	}
+	var status CopyPrefixStatus
+	err = GetTaskStatus(ctx, c.KVStore, repository, id, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// listPrefix calls fn with each batch of the entries under the prefix of the ref
+func (c *Catalog) listPrefix(ctx context.Context, repositoryID, ref, prefix string, fn func(entries []*DBEntry) error) error {
+	after := ""
+	for {
+		entries, hasMore, err := c.ListEntries(ctx, repositoryID, ref, prefix, after, "", copyPrefixListLimit)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			if err := fn(entries); err != nil {
+				return err
+			}
+			after = entries[len(entries)-1].Path
+		}
+		if !hasMore {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/upload"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	dedupPrefix = "dedup"
+	// dedupIndexAttempts bounds the attempts to update an index entry changed concurrently
+	dedupIndexAttempts = 3
+	// dedupReuseRetention is the time garbage collection keeps a reused address, for the entry referencing it to be
+	// staged
+	dedupReuseRetention = ref.LinkAddressTime
+)
+
+// dedupPath is the index key of the content with the sha256 digest, stored compressed by codec or as is for an empty
+// codec. Content stored compressed is indexed apart, as the entries referencing it are marked by its codec.
+func dedupPath(codec, sha256 string) string {
+	if codec == "" {
+		return kv.FormatPath(dedupPrefix, sha256)
+	}
+	return kv.FormatPath(dedupPrefix, codec, sha256)
+}
+
+// DedupBlob looks up the content of a blob uploaded to the repository for the entry at path in the repository dedup
+// index. In case an existing object of the repository holds the same content, the uploaded blob is removed and the
+// returned blob points to the existing object. Otherwise, the blob is indexed and returned as is.
+// An existing object is reused only while the entry it was uploaded for still references it on a branch, staged or
+// committed on the branch head, and it is not archived by the lifecycle rules of the repository. Garbage collection
+// and lifecycle keep the objects of branch heads and staging areas, including runs prepared before the reuse, so a
+// reuse never links an object they may remove. Until the entry referencing the reused address is staged, the address
+// is also kept by the reuse time recorded in the index, see gcWriteDeduped. Index entries of objects that can no longer be reused are replaced on their next lookup.
+func (c *Catalog) DedupBlob(ctx context.Context, repositoryID, path string, blob *upload.Blob) (*upload.Blob, error) {
+	if !c.DedupUploads || !blob.RelativePath || blob.Sha256 == "" {
+		return blob, nil
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	partition := graveler.RepoPartition(repository)
+	key := []byte(dedupPath(blob.Compression, blob.Sha256))
+	data := &DedupAddressData{
+		Address: blob.PhysicalAddress,
+		Size:    blob.Size,
+		Path:    path,
+	}
+	var predicate kv.Predicate
+	for i := 0; i < dedupIndexAttempts; i++ {
+		err := kv.SetMsgIf(ctx, c.KVStore, partition, key, data, predicate)
+		if err == nil {
+			return blob, nil
+		}
+		if !errors.Is(err, kv.ErrPredicateFailed) {
+			return nil, fmt.Errorf("index uploaded object: %w", err)
+		}
+
+		var existing DedupAddressData
+		predicate, err = kv.GetMsg(ctx, c.KVStore, partition, key, &existing)
+		if errors.Is(err, kv.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("lookup uploaded object: %w", err)
+		}
+		if existing.Address == blob.PhysicalAddress {
+			return blob, nil
+		}
+		if existing.Size != blob.Size {
+			continue
+		}
+		reusable, err := c.dedupReusable(ctx, repository, &existing)
+		if err != nil {
+			return nil, err
+		}
+		if !reusable {
+			// the indexed object is no longer referenced or was collected, replace it by the uploaded blob
+			continue
+		}
+		// record the reuse before the entry referencing the address is staged, garbage collection keeps
+		// recently reused addresses
+		existing.ReusedAt = timestamppb.Now()
+		if err := kv.SetMsgIf(ctx, c.KVStore, partition, key, &existing, predicate); errors.Is(err, kv.ErrPredicateFailed) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("index reused object: %w", err)
+		}
+		if err := c.BlockAdapter.Remove(ctx, block.ObjectPointer{
+			StorageID:        repository.StorageID.String(),
+			StorageNamespace: repository.StorageNamespace.String(),
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       blob.PhysicalAddress,
+		}); err != nil {
+			// the duplicate is not referenced, garbage collection removes it
+			c.log(ctx).WithError(err).WithField("physical_address", blob.PhysicalAddress).Warn("Failed to remove duplicate uploaded object")
+		}
+		deduped := *blob
+		deduped.PhysicalAddress = existing.Address
+		return &deduped, nil
+	}
+	// the index entry keeps changing, keep the uploaded blob without indexing it
+	return blob, nil
+}
+
+// dedupReusable reports whether the indexed object may be reused: the entry at its path on any branch references it,
+// the object exists, and it is not archived to the storage class of the lifecycle rules.
+func (c *Catalog) dedupReusable(ctx context.Context, repository *graveler.RepositoryRecord, data *DedupAddressData) (bool, error) {
+	if data.Path == "" {
+		// indexed before the path of its entry was recorded
+		return false, nil
+	}
+	linked, err := c.dedupAddressLinked(ctx, repository, data)
+	if err != nil || !linked {
+		return false, err
+	}
+	obj := block.ObjectPointer{
+		StorageID:        repository.StorageID.String(),
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       data.Address,
+	}
+	found, err := c.BlockAdapter.Exists(ctx, obj)
+	if err != nil || !found {
+		return false, err
+	}
+	rules, err := c.GetLifecycleRules(ctx, repository.RepositoryID.String())
+	if errors.Is(err, graveler.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	props, err := c.BlockAdapter.GetProperties(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	return props.StorageClass == nil || *props.StorageClass != rules.StorageClass, nil
+}
+
+// dedupAddressLinked reports whether the entry at the path of the indexed object references it on any branch, staged
+// or committed on the branch head
+func (c *Catalog) dedupAddressLinked(ctx context.Context, repository *graveler.RepositoryRecord, data *DedupAddressData) (bool, error) {
+	branches, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return false, err
+	}
+	defer branches.Close()
+	for branches.Next() {
+		value, err := c.Store.Get(ctx, repository, graveler.Ref(branches.Value().BranchID), graveler.Key(data.Path))
+		if errors.Is(err, graveler.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		entry, err := ValueToEntry(value)
+		if err != nil {
+			return false, err
+		}
+		if address, ok := relativeAddress(repository, entry); ok && address == data.Address {
+			return true, nil
+		}
+	}
+	return false, branches.Err()
+}
@@ -0,0 +1,205 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+	gUtils "github.com/treeverse/lakefs/pkg/graveler/testutil"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	_ "github.com/treeverse/lakefs/pkg/kv/mem"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+type dedupGraveler struct {
+	*FakeGraveler
+	repository *graveler.RepositoryRecord
+}
+
+func (g *dedupGraveler) GetRepository(_ context.Context, _ graveler.RepositoryID) (*graveler.RepositoryRecord, error) {
+	return g.repository, nil
+}
+
+func TestCatalog_DedupBlob(t *testing.T) {
+	ctx := context.Background()
+	const (
+		repositoryID     = "repo1"
+		storageNamespace = "mem://" + repositoryID
+	)
+	repository := &graveler.RepositoryRecord{
+		RepositoryID: repositoryID,
+		Repository: &graveler.Repository{
+			StorageNamespace: storageNamespace,
+			CreationDate:     time.Now(),
+			DefaultBranchID:  "main",
+		},
+	}
+	store := &dedupGraveler{
+		FakeGraveler: &FakeGraveler{
+			KeyValue: map[string]*graveler.Value{},
+			BranchIteratorFactory: gUtils.NewFakeBranchIteratorFactory([]*graveler.BranchRecord{
+				{BranchID: "main", Branch: &graveler.Branch{}},
+				{BranchID: "dev", Branch: &graveler.Branch{}},
+			}),
+		},
+		repository: repository,
+	}
+	blockAdapter := mem.New(ctx)
+	kvStore := kvtest.GetStore(ctx, t)
+	c := &Catalog{
+		Store:          store,
+		BlockAdapter:   blockAdapter,
+		KVStore:        kvStore,
+		DedupUploads:   true,
+		settingManager: settings.NewManager(nil, kvStore),
+	}
+
+	pointer := func(address string) block.ObjectPointer {
+		return block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       address,
+		}
+	}
+	exists := func(address string) bool {
+		t.Helper()
+		found, err := blockAdapter.Exists(ctx, pointer(address))
+		require.NoError(t, err)
+		return found
+	}
+	// link sets the entry at path of the branch to the address, staged or committed on the branch head
+	link := func(branchID, path, address string) {
+		t.Helper()
+		require.NoError(t, store.Set(ctx, repository, graveler.BranchID(branchID), graveler.Key(path), *MustEntryToValue(&Entry{
+			Address:     address,
+			AddressType: Entry_RELATIVE,
+		})))
+	}
+	unlink := func(branchID, path string) {
+		delete(store.KeyValue, fakeGravelerBuildKey(repository.RepositoryID, graveler.Ref(branchID), graveler.Key(path)))
+	}
+	put := func(path, data string) (*upload.Blob, *upload.Blob) {
+		t.Helper()
+		blob, err := upload.WriteBlob(ctx, blockAdapter, "", storageNamespace, upload.DefaultPathProvider.NewPath(), strings.NewReader(data), int64(len(data)), block.PutOpts{})
+		require.NoError(t, err)
+		deduped, err := c.DedupBlob(ctx, repositoryID, path, blob)
+		require.NoError(t, err)
+		return blob, deduped
+	}
+	indexed := func(sha256 string) *DedupAddressData {
+		t.Helper()
+		var data DedupAddressData
+		_, err := kv.GetMsg(ctx, kvStore, graveler.RepoPartition(repository), []byte(dedupPath("", sha256)), &data)
+		require.NoError(t, err)
+		return &data
+	}
+
+	first, deduped := put("data/first", "data")
+	require.Equal(t, first, deduped)
+	require.Equal(t, "data/first", indexed(first.Sha256).Path)
+	link("main", "data/first", first.PhysicalAddress)
+
+	t.Run("duplicate", func(t *testing.T) {
+		blob, deduped := put("data/second", "data")
+		require.Equal(t, first.PhysicalAddress, deduped.PhysicalAddress)
+		require.Equal(t, blob.Checksum, deduped.Checksum)
+		require.Equal(t, blob.Size, deduped.Size)
+		require.False(t, exists(blob.PhysicalAddress), "duplicate object was not removed")
+		require.True(t, exists(first.PhysicalAddress))
+
+		// the reuse is recorded for garbage collection to keep the address
+		data := indexed(blob.Sha256)
+		require.Equal(t, first.PhysicalAddress, data.Address)
+		require.Equal(t, "data/first", data.Path)
+		require.NotNil(t, data.ReusedAt, "reuse time was not recorded")
+	})
+
+	t.Run("different_content", func(t *testing.T) {
+		blob, deduped := put("data/other", "other data")
+		require.Equal(t, blob, deduped)
+		require.True(t, exists(blob.PhysicalAddress))
+	})
+
+	t.Run("linked_on_other_branch", func(t *testing.T) {
+		// the object is referenced only by the staging area or head of another branch
+		unlink("main", "data/first")
+		link("dev", "data/first", first.PhysicalAddress)
+		defer func() {
+			unlink("dev", "data/first")
+			link("main", "data/first", first.PhysicalAddress)
+		}()
+		_, deduped := put("data/second", "data")
+		require.Equal(t, first.PhysicalAddress, deduped.PhysicalAddress)
+	})
+
+	t.Run("gc_prepared_before_reuse", func(t *testing.T) {
+		const path = "data/gc"
+		indexedBlob, _ := put(path, "gc data")
+		link("main", path, indexedBlob.PhysicalAddress)
+
+		// the entry is overwritten, and a garbage collection run is prepared: the object is no longer referenced
+		// by any branch head or staging area, and the run will remove it
+		link("main", path, "data/overwritten")
+		expired := []string{indexedBlob.PhysicalAddress}
+
+		// the same content is uploaded before the run removes the expired object
+		blob, deduped := put("data/gc-new", "gc data")
+		require.Equal(t, blob, deduped, "reused an object garbage collection may remove")
+		data := indexed(blob.Sha256)
+		require.Equal(t, blob.PhysicalAddress, data.Address, "index kept an object garbage collection may remove")
+		require.Equal(t, "data/gc-new", data.Path)
+		link("main", "data/gc-new", deduped.PhysicalAddress)
+
+		for _, address := range expired {
+			require.NoError(t, blockAdapter.Remove(ctx, pointer(address)))
+		}
+		require.True(t, exists(deduped.PhysicalAddress), "uploaded object removed by garbage collection")
+	})
+
+	t.Run("archived", func(t *testing.T) {
+		require.NoError(t, c.SetLifecycleRules(ctx, repositoryID, &LifecycleRules{
+			DefaultTransitionDays: 30,
+			StorageClass:          block.StorageClassGlacier,
+		}))
+		defer func() {
+			require.NoError(t, c.DeleteLifecycleRules(ctx, repositoryID))
+		}()
+		const path = "data/archived"
+		archived, _ := put(path, "archived data")
+		link("main", path, archived.PhysicalAddress)
+
+		// lifecycle rules are set, objects are reused until they are archived
+		_, deduped := put("data/archived-copy", "archived data")
+		require.Equal(t, archived.PhysicalAddress, deduped.PhysicalAddress)
+		require.NoError(t, blockAdapter.SetStorageClass(ctx, pointer(archived.PhysicalAddress), block.StorageClassGlacier))
+		blob, deduped := put("data/archived-copy", "archived data")
+		require.Equal(t, blob, deduped, "reused an archived object")
+	})
+
+	t.Run("collected_object", func(t *testing.T) {
+		require.NoError(t, blockAdapter.Remove(ctx, pointer(first.PhysicalAddress)))
+		blob, deduped := put("data/third", "data")
+		require.Equal(t, blob, deduped)
+		require.True(t, exists(blob.PhysicalAddress))
+		link("main", "data/third", blob.PhysicalAddress)
+
+		// the uploaded object replaced the collected one in the index
+		_, deduped = put("data/fourth", "data")
+		require.Equal(t, blob.PhysicalAddress, deduped.PhysicalAddress)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c.DedupUploads = false
+		defer func() { c.DedupUploads = true }()
+		blob, deduped := put("data/other", "other data")
+		require.Equal(t, blob, deduped)
+		require.True(t, exists(blob.PhysicalAddress))
+	})
+}
@@ -58,3 +58,12 @@ func (d Differences) Equal(other Differences) bool {
 	}
 	return true
 }
+
+// MergeConflict describes an object conflicting between the merge source and destination.
+// Base, Source and Destination are nil when the object does not exist on that side.
+type MergeConflict struct {
+	Path        string
+	Base        *DBEntry
+	Source      *DBEntry
+	Destination *DBEntry
+}
@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const (
+	DiffSummaryDefaultDepth = 1
+	DiffSummaryMaxDepth     = 16
+)
+
+type DiffSummaryParams struct {
+	Prefix string
+	// Depth is the number of path levels under Prefix used to group the changes
+	Depth int
+	// ThreeDot compares the right reference with the common ancestor instead of the left reference
+	ThreeDot bool
+}
+
+// DiffStats holds the number of changed objects and their total size under a path prefix. Sizes of changed objects
+// are the sizes of their new version.
+type DiffStats struct {
+	Path         string
+	Added        int64
+	Removed      int64
+	Changed      int64
+	AddedBytes   int64
+	RemovedBytes int64
+	ChangedBytes int64
+}
+
+func (s *DiffStats) add(d *EntryDiff) {
+	var size int64
+	if d.Entry != nil {
+		size = d.Entry.GetSize()
+	}
+	switch d.Type {
+	case graveler.DiffTypeAdded:
+		s.Added++
+		s.AddedBytes += size
+	case graveler.DiffTypeRemoved:
+		s.Removed++
+		s.RemovedBytes += size
+	case graveler.DiffTypeChanged:
+		s.Changed++
+		s.ChangedBytes += size
+	}
+}
+
+type DiffSummary struct {
+	Total DiffStats
+	// Prefixes holds the stats of each prefix with changes, sorted by path
+	Prefixes []DiffStats
+}
+
+// diffSummaryPath returns the prefix grouping the path, up to depth levels under prefix. Objects found in shallower
+// levels are grouped under their parent.
+func diffSummaryPath(path, prefix string, depth int) string {
+	parts := strings.SplitAfterN(strings.TrimPrefix(path, prefix), DefaultPathDelimiter, depth+1)
+	if len(parts) > depth {
+		parts = parts[:depth]
+	} else {
+		parts = parts[:len(parts)-1]
+	}
+	return prefix + strings.Join(parts, "")
+}
+
+// summarizeDiff walks the differences under prefix and sums them by path prefixes
+func summarizeDiff(it EntryDiffIterator, prefix string, depth int) (*DiffSummary, error) {
+	summary := &DiffSummary{
+		Total: DiffStats{Path: prefix},
+	}
+	stats := make(map[string]*DiffStats)
+	it.SeekGE(Path(prefix))
+	for it.Next() {
+		v := it.Value()
+		path := v.Path.String()
+		if !strings.HasPrefix(path, prefix) {
+			break
+		}
+		p := diffSummaryPath(path, prefix, depth)
+		s, ok := stats[p]
+		if !ok {
+			s = &DiffStats{Path: p}
+			stats[p] = s
+		}
+		s.add(v)
+		summary.Total.add(v)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	summary.Prefixes = make([]DiffStats, 0, len(stats))
+	for _, s := range stats {
+		summary.Prefixes = append(summary.Prefixes, *s)
+	}
+	sort.Slice(summary.Prefixes, func(i, j int) bool {
+		return summary.Prefixes[i].Path < summary.Prefixes[j].Path
+	})
+	return summary, nil
+}
@@ -0,0 +1,66 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/testutil"
+)
+
+func TestDiffSummaryPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		prefix   string
+		depth    int
+		expected string
+	}{
+		{path: "a/b/c/file", depth: 0, expected: ""},
+		{path: "a/b/c/file", depth: 1, expected: "a/"},
+		{path: "a/b/c/file", depth: 2, expected: "a/b/"},
+		{path: "a/b/c/file", depth: 5, expected: "a/b/c/"},
+		{path: "file", depth: 2, expected: ""},
+		{path: "a/b/c/file", prefix: "a/", depth: 1, expected: "a/b/"},
+		{path: "a/file", prefix: "a/", depth: 1, expected: "a/"},
+		{path: "ab/file", prefix: "a", depth: 1, expected: "ab/"},
+	}
+	for _, tt := range tests {
+		if p := diffSummaryPath(tt.path, tt.prefix, tt.depth); p != tt.expected {
+			t.Errorf("diffSummaryPath(%s, %s, %d) = %s, expected %s", tt.path, tt.prefix, tt.depth, p, tt.expected)
+		}
+	}
+}
+
+func TestSummarizeDiff(t *testing.T) {
+	diff := func(typ graveler.DiffType, path string, size int64) graveler.Diff {
+		return graveler.Diff{
+			Type:  typ,
+			Key:   graveler.Key(path),
+			Value: MustEntryToValue(&Entry{Address: path, Size: size}),
+		}
+	}
+	it := NewEntryDiffIterator(testutil.NewDiffIter([]graveler.Diff{
+		diff(graveler.DiffTypeAdded, "a/b/file1", 10),
+		diff(graveler.DiffTypeAdded, "a/b/file2", 20),
+		diff(graveler.DiffTypeChanged, "a/c/file3", 30),
+		diff(graveler.DiffTypeRemoved, "a/file4", 40),
+		diff(graveler.DiffTypeRemoved, "b/file5", 50),
+	}))
+	defer it.Close()
+
+	summary, err := summarizeDiff(it, "a/", 1)
+	if err != nil {
+		t.Fatalf("summarizeDiff() error: %s", err)
+	}
+	expected := &DiffSummary{
+		Total: DiffStats{Path: "a/", Added: 2, Removed: 1, Changed: 1, AddedBytes: 30, RemovedBytes: 40, ChangedBytes: 30},
+		Prefixes: []DiffStats{
+			{Path: "a/", Removed: 1, RemovedBytes: 40},
+			{Path: "a/b/", Added: 2, AddedBytes: 30},
+			{Path: "a/c/", Changed: 1, ChangedBytes: 30},
+		},
+	}
+	if diff := deep.Equal(summary, expected); diff != nil {
+		t.Fatalf("summarizeDiff() diff: %s", diff)
+	}
+}
@@ -1,10 +1,12 @@
 package catalog
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/graveler"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -36,3 +38,26 @@ func TestEntryToValueAndBack(t *testing.T) {
 		t.Fatal("Entry convert to value and back failed:", diff)
 	}
 }
+
+func TestWithSameObject(t *testing.T) {
+	entry := &DBEntry{PhysicalAddress: "a", Checksum: "etag1"}
+	cases := []struct {
+		name        string
+		value       *graveler.Value
+		expectedErr error
+	}{
+		{name: "same", value: MustEntryToValue(&Entry{Address: "a", ETag: "etag1", Metadata: map[string]string{"k": "v"}})},
+		{name: "replaced", value: MustEntryToValue(&Entry{Address: "b", ETag: "etag2"}), expectedErr: graveler.ErrPreconditionFailed},
+		{name: "same_address", value: MustEntryToValue(&Entry{Address: "a", ETag: "etag2"}), expectedErr: graveler.ErrPreconditionFailed},
+		{name: "deleted", expectedErr: graveler.ErrPreconditionFailed},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts graveler.SetOptions
+			withSameObject(entry)(&opts)
+			if err := opts.Condition(tt.value); !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("condition: got error %v, expected %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -52,7 +52,7 @@ func (g *FakeGraveler) SetGarbageCollectionRules(_ context.Context, _ *graveler.
 	panic("implement me")
 }
 
-func (g *FakeGraveler) CreateBareRepository(_ context.Context, _ graveler.RepositoryID, _ graveler.StorageNamespace, _ graveler.BranchID, _ bool) (*graveler.RepositoryRecord, error) {
+func (g *FakeGraveler) CreateBareRepository(_ context.Context, _ graveler.RepositoryID, _ graveler.StorageID, _ graveler.StorageNamespace, _ graveler.BranchID, _ bool) (*graveler.RepositoryRecord, error) {
 	panic("implement me")
 }
 
@@ -88,6 +88,18 @@ func (g *FakeGraveler) GetRange(_ context.Context, _ *graveler.RepositoryRecord,
 	panic("implement me")
 }
 
+func (g *FakeGraveler) ListRanges(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) ListRange(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.RangeID) (graveler.ValueIterator, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) ListCommits(_ context.Context, _ *graveler.RepositoryRecord) (graveler.CommitIterator, error) {
+	panic("implement me")
+}
+
 func fakeGravelerBuildKey(repositoryID graveler.RepositoryID, ref graveler.Ref, key graveler.Key) string {
 	return strings.Join([]string{repositoryID.String(), ref.String(), key.String()}, "/")
 }
@@ -143,7 +155,7 @@ func (g *FakeGraveler) GetRepository(ctx context.Context, repositoryID graveler.
 	return &graveler.RepositoryRecord{RepositoryID: repositoryID}, nil
 }
 
-func (g *FakeGraveler) CreateRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageNamespace graveler.StorageNamespace, branchID graveler.BranchID, readOnly bool) (*graveler.RepositoryRecord, error) {
+func (g *FakeGraveler) CreateRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageID graveler.StorageID, storageNamespace graveler.StorageNamespace, branchID graveler.BranchID, readOnly bool) (*graveler.RepositoryRecord, error) {
 	panic("implement me")
 }
 
@@ -205,7 +217,7 @@ func (g *FakeGraveler) ListTags(ctx context.Context, repository *graveler.Reposi
 	return g.TagIteratorFactory(), nil
 }
 
-func (g *FakeGraveler) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since *time.Time) (graveler.CommitIterator, error) {
+func (g *FakeGraveler) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since, until *time.Time) (graveler.CommitIterator, error) {
 	panic("implement me")
 }
 
@@ -260,7 +272,7 @@ func (g *FakeGraveler) Revert(_ context.Context, _ *graveler.RepositoryRecord, _
 	panic("implement me")
 }
 
-func (g *FakeGraveler) Merge(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, source graveler.Ref, _ graveler.CommitParams, strategy string, _ ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
+func (g *FakeGraveler) Merge(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, source graveler.Ref, _ graveler.CommitParams, strategy string, _ graveler.MergeOptions, _ ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
 	panic("implement me")
 }
 
@@ -6,21 +6,58 @@ import (
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/xitongsys/parquet-go/parquet"
 	"github.com/xitongsys/parquet-go/writer"
 )
 
-func gcWriteUncommitted(ctx context.Context, store Store, repository *graveler.RepositoryRecord, w *UncommittedWriter, mark *GCUncommittedMark, runID string, maxFileSize int64, prepareDuration time.Duration) (*GCUncommittedMark, bool, error) {
+// gcWriteUncommitted writes the addresses of uncommitted objects of the repository, followed by the addresses of its
+// objects shared with other repositories and by its recently deduplicated addresses. A mark with a Key continues from
+// the shared addresses, a mark with a DedupKey continues from the deduplicated addresses.
+func gcWriteUncommitted(ctx context.Context, store Store, kvStore kv.Store, repository *graveler.RepositoryRecord, w *UncommittedWriter, mark *GCUncommittedMark, runID string, maxFileSize int64, prepareDuration time.Duration) (*GCUncommittedMark, bool, error) {
 	pw, err := writer.NewParquetWriterFromWriter(w, new(UncommittedParquetObject), gcParquetParallelNum)
 	if err != nil {
 		return nil, false, err
 	}
 	pw.CompressionType = parquet.CompressionCodec_GZIP
 
+	count := 0
+	startTime := time.Now()
+	var nextMark *GCUncommittedMark
+	if mark == nil || (mark.Key == "" && mark.DedupKey == "") {
+		nextMark, err = gcWriteStaged(ctx, store, repository, pw, w, mark, runID, maxFileSize, prepareDuration, startTime, &count)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	// Finished reading all staging area - continue with addresses shared with other repositories
+	if nextMark == nil && (mark == nil || mark.DedupKey == "") {
+		nextMark, err = gcWriteShared(ctx, store, kvStore, repository, pw, w, mark, runID, maxFileSize, prepareDuration, startTime, &count)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	// Finished reading shared addresses - continue with recently deduplicated addresses
+	if nextMark == nil {
+		nextMark, err = gcWriteDeduped(ctx, kvStore, repository, pw, w, mark, runID, maxFileSize, prepareDuration, startTime, &count)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	// stop writer before we return
+	if err := pw.WriteStop(); err != nil {
+		return nil, false, err
+	}
+
+	hasData := count > 0
+	return nextMark, hasData, nil
+}
+
+func gcWriteStaged(ctx context.Context, store Store, repository *graveler.RepositoryRecord, pw *writer.ParquetWriter, w *UncommittedWriter, mark *GCUncommittedMark, runID string, maxFileSize int64, prepareDuration time.Duration, startTime time.Time, count *int) (*GCUncommittedMark, error) {
 	// write uncommitted data from branches
 	it, err := NewUncommittedIterator(ctx, store, repository)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 	defer it.Close()
 
@@ -33,8 +70,6 @@ func gcWriteUncommitted(ctx context.Context, store Store, repository *graveler.R
 		normalizedStorageNamespace += DefaultPathDelimiter
 	}
 
-	count := 0
-	startTime := time.Now()
 	var nextMark *GCUncommittedMark
 	for it.Next() {
 		entry := it.Value()
@@ -51,10 +86,10 @@ func gcWriteUncommitted(ctx context.Context, store Store, repository *graveler.R
 			entryAddress = entryAddress[len(normalizedStorageNamespace):]
 		}
 
-		count += 1
-		if count%gcPeriodicCheckSize == 0 {
+		*count += 1
+		if *count%gcPeriodicCheckSize == 0 {
 			if err := pw.Flush(true); err != nil {
-				return nil, false, err
+				return nil, err
 			}
 		}
 		// check if we need to stop - based on max file size or prepare duration.
@@ -73,18 +108,112 @@ func gcWriteUncommitted(ctx context.Context, store Store, repository *graveler.R
 			PhysicalAddress: entryAddress,
 			CreationDate:    entry.LastModified.AsTime().Unix(),
 		}); err != nil {
-			return nil, false, err
+			return nil, err
 		}
 	}
 	if err := it.Err(); err != nil {
-		return nil, false, err
+		return nil, err
 	}
-	// stop writer before we return
-	if err := pw.WriteStop(); err != nil {
-		return nil, false, err
+	return nextMark, nil
+}
+
+// gcWriteShared writes the addresses of objects of the repository shared with other repositories and still kept, see
+// sharedAddressChecker
+func gcWriteShared(ctx context.Context, store Store, kvStore kv.Store, repository *graveler.RepositoryRecord, pw *writer.ParquetWriter, w *UncommittedWriter, mark *GCUncommittedMark, runID string, maxFileSize int64, prepareDuration time.Duration, startTime time.Time, count *int) (*GCUncommittedMark, error) {
+	normalizedStorageNamespace := string(repository.StorageNamespace)
+	if !strings.HasSuffix(normalizedStorageNamespace, DefaultPathDelimiter) {
+		normalizedStorageNamespace += DefaultPathDelimiter
+	}
+	prefix := sharedAddressPrefix(repository.StorageID.String(), normalizedStorageNamespace)
+	var start []byte
+	if mark != nil {
+		start = []byte(mark.Key)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, kvStore, (&SharedAddressData{}).ProtoReflect().Type(), sharedAddressesPartition, []byte(prefix), kv.IteratorOptionsFrom(start))
+	if err != nil {
+		return nil, err
 	}
+	defer it.Close()
 
-	// Finished reading all staging area - return marker to switch processing tracked physical addresses
-	hasData := count > 0
-	return nextMark, hasData, nil
+	checker := newSharedAddressChecker(store, kvStore, repository)
+	for it.Next() {
+		entry := it.Entry()
+		data, ok := entry.Value.(*SharedAddressData)
+		if !ok {
+			return nil, graveler.ErrReadingFromStore
+		}
+		keep, err := checker.keep(ctx, entry.Key, data)
+		if err != nil {
+			return nil, err
+		}
+		if !keep {
+			continue
+		}
+
+		*count += 1
+		if *count%gcPeriodicCheckSize == 0 {
+			if err := pw.Flush(true); err != nil {
+				return nil, err
+			}
+		}
+		if w.Size() > maxFileSize || (prepareDuration > 0 && time.Since(startTime) > prepareDuration) {
+			return &GCUncommittedMark{
+				RunID: runID,
+				Key:   string(entry.Key),
+			}, nil
+		}
+		if err = pw.Write(UncommittedParquetObject{
+			PhysicalAddress: strings.TrimPrefix(data.Address, normalizedStorageNamespace),
+			CreationDate:    data.CreationDate.AsTime().Unix(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return nil, it.Err()
+}
+
+// gcWriteDeduped writes the addresses of objects of the repository recently reused by DedupBlob. An uploaded object
+// deduplicated to an existing address is staged only after the reuse, and the existing object may be older than any
+// uncommitted object garbage collection keeps.
+func gcWriteDeduped(ctx context.Context, kvStore kv.Store, repository *graveler.RepositoryRecord, pw *writer.ParquetWriter, w *UncommittedWriter, mark *GCUncommittedMark, runID string, maxFileSize int64, prepareDuration time.Duration, startTime time.Time, count *int) (*GCUncommittedMark, error) {
+	var start []byte
+	if mark != nil {
+		start = []byte(mark.DedupKey)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, kvStore, (&DedupAddressData{}).ProtoReflect().Type(), graveler.RepoPartition(repository), []byte(dedupPath("", "")), kv.IteratorOptionsFrom(start))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		entry := it.Entry()
+		data, ok := entry.Value.(*DedupAddressData)
+		if !ok {
+			return nil, graveler.ErrReadingFromStore
+		}
+		if data.ReusedAt == nil || time.Since(data.ReusedAt.AsTime()) > dedupReuseRetention {
+			continue
+		}
+
+		*count += 1
+		if *count%gcPeriodicCheckSize == 0 {
+			if err := pw.Flush(true); err != nil {
+				return nil, err
+			}
+		}
+		if w.Size() > maxFileSize || (prepareDuration > 0 && time.Since(startTime) > prepareDuration) {
+			return &GCUncommittedMark{
+				RunID:    runID,
+				DedupKey: string(entry.Key),
+			}, nil
+		}
+		if err = pw.Write(UncommittedParquetObject{
+			PhysicalAddress: data.Address,
+			CreationDate:    data.ReusedAt.AsTime().Unix(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return nil, it.Err()
 }
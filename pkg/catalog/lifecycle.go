@@ -0,0 +1,504 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	lifecycleSettingKey = "lifecycle"
+
+	LifecycleTaskIDPrefix = "LC"
+
+	// lifecycleTransitionBatchSize is the number of expired addresses transitioned between checks of the addresses
+	// linked since they were marked
+	lifecycleTransitionBatchSize = 1000
+)
+
+// GetLifecycleRules returns the lifecycle rules of the repository, graveler.ErrNotFound in case none were set
+func (c *Catalog) GetLifecycleRules(ctx context.Context, repositoryID string) (*LifecycleRules, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	rules := &LifecycleRules{}
+	if _, err := c.settingManager.GetLatest(ctx, repository, lifecycleSettingKey, rules); err != nil {
+		return nil, err
+	}
+	if rules.StorageClass == "" {
+		return nil, graveler.ErrNotFound
+	}
+	return rules, nil
+}
+
+func (c *Catalog) SetLifecycleRules(ctx context.Context, repositoryID string, rules *LifecycleRules) error {
+	if rules.StorageClass == "" {
+		return fmt.Errorf("storage class: %w", graveler.ErrInvalidValue)
+	}
+	if rules.DefaultTransitionDays <= 0 {
+		return fmt.Errorf("default transition days: %w", graveler.ErrInvalidValue)
+	}
+	for _, rule := range rules.Branches {
+		if rule.TransitionDays <= 0 {
+			return fmt.Errorf("branch '%s' transition days: %w", rule.BranchId, graveler.ErrInvalidValue)
+		}
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if repository.ReadOnly {
+		return graveler.ErrReadOnlyRepository
+	}
+	return c.settingManager.Save(ctx, repository, lifecycleSettingKey, rules, nil)
+}
+
+func (c *Catalog) DeleteLifecycleRules(ctx context.Context, repositoryID string) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if repository.ReadOnly {
+		return graveler.ErrReadOnlyRepository
+	}
+	return c.settingManager.Save(ctx, repository, lifecycleSettingKey, &LifecycleRules{}, nil)
+}
+
+// LifecycleRunSubmit starts a background lifecycle run of the repository. The run transitions objects that are
+// reachable only from commits expired by the lifecycle rules to the storage class of the rules.
+func (c *Catalog) LifecycleRunSubmit(ctx context.Context, repositoryID string) (string, error) {
+	rules, err := c.GetLifecycleRules(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	if repository.ReadOnly {
+		return "", graveler.ErrReadOnlyRepository
+	}
+
+	taskStatus := &LifecycleRunStatus{}
+	runStart := time.Now()
+	var addresses *lifecycleAddresses
+	taskSteps := []taskStep{
+		{
+			Name: "find expired objects",
+			Func: func(ctx context.Context) error {
+				var err error
+				addresses, err = c.lifecycleExpiredAddresses(ctx, repository, rules, runStart)
+				return err
+			},
+		},
+		{
+			Name: "transition objects",
+			Func: func(ctx context.Context) error {
+				defer addresses.close()
+				return c.lifecycleTransitionExpired(ctx, repository, rules.StorageClass, addresses, runStart, taskStatus)
+			},
+		},
+	}
+	taskID := NewTaskID(LifecycleTaskIDPrefix)
+	if err := c.runBackgroundTaskSteps(repository, taskID, taskSteps, taskStatus); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+func (c *Catalog) LifecycleRunStatus(ctx context.Context, repositoryID string, id string) (*LifecycleRunStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTaskID(LifecycleTaskIDPrefix, id) {
+		return nil, graveler.ErrNotFound
+	}
+	var status LifecycleRunStatus
+	err = GetTaskStatus(ctx, c.KVStore, repository, id, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// lifecycleAddresses holds the addresses found by a lifecycle run in a temporary local database, marked active or
+// expired, and the ranges whose addresses were all marked active
+type lifecycleAddresses struct {
+	db           *pebble.DB
+	dbPath       string
+	activeRanges map[graveler.RangeID]struct{}
+}
+
+var (
+	lifecycleActive  = []byte{'a'}
+	lifecycleExpired = []byte{'e'}
+)
+
+func newLifecycleAddresses() (*lifecycleAddresses, error) {
+	dbPath, err := os.MkdirTemp("", "lifecycle_")
+	if err != nil {
+		return nil, err
+	}
+	db, err := pebble.Open(dbPath, nil)
+	if err != nil {
+		_ = os.RemoveAll(dbPath)
+		return nil, err
+	}
+	return &lifecycleAddresses{db: db, dbPath: dbPath, activeRanges: make(map[graveler.RangeID]struct{})}, nil
+}
+
+func (l *lifecycleAddresses) setActive(address string) error {
+	return l.db.Set([]byte(address), lifecycleActive, pebble.NoSync)
+}
+
+// setExpired marks the address expired, unless it was marked active. All active addresses are marked first, addresses
+// marked expired may be marked active later on.
+func (l *lifecycleAddresses) setExpired(address string) error {
+	_, closer, err := l.db.Get([]byte(address))
+	if err == nil {
+		return closer.Close()
+	}
+	if !errors.Is(err, pebble.ErrNotFound) {
+		return err
+	}
+	return l.db.Set([]byte(address), lifecycleExpired, pebble.NoSync)
+}
+
+// isExpired reports whether the address is still marked expired
+func (l *lifecycleAddresses) isExpired(address string) (bool, error) {
+	value, closer, err := l.db.Get([]byte(address))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	expired := bytes.Equal(value, lifecycleExpired)
+	return expired, closer.Close()
+}
+
+// forEachExpired calls fn with each expired address, ordered by address
+func (l *lifecycleAddresses) forEachExpired(fn func(address string) error) error {
+	return l.forEachExpiredBatch(1, func(batch []string) error {
+		return fn(batch[0])
+	})
+}
+
+// forEachExpiredBatch calls fn with batches of up to size expired addresses, ordered by address
+func (l *lifecycleAddresses) forEachExpiredBatch(size int, fn func(batch []string) error) error {
+	it := l.db.NewIter(nil)
+	batch := make([]string, 0, size)
+	for it.First(); it.Valid(); it.Next() {
+		if !bytes.Equal(it.Value(), lifecycleExpired) {
+			continue
+		}
+		batch = append(batch, string(it.Key()))
+		if len(batch) < size {
+			continue
+		}
+		if err := fn(batch); err != nil {
+			_ = it.Close()
+			return err
+		}
+		batch = batch[:0]
+	}
+	if err := it.Close(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func (l *lifecycleAddresses) close() {
+	_ = l.db.Close()
+	_ = os.RemoveAll(l.dbPath)
+}
+
+// lifecycleExpiredAddresses finds the relative addresses of objects reachable from expired commits, which are not
+// reachable from active commits or linked since runStart, see lifecycleMarkLinked. Commits are scanned range by
+// range, skipping ranges already scanned: every object of a range of an active commit is active.
+func (c *Catalog) lifecycleExpiredAddresses(ctx context.Context, repository *graveler.RepositoryRecord, rules *LifecycleRules, runStart time.Time) (*lifecycleAddresses, error) {
+	gcRules := &graveler.GarbageCollectionRules{
+		DefaultRetentionDays: rules.DefaultTransitionDays,
+		BranchRetentionDays:  make(map[string]int32, len(rules.Branches)),
+	}
+	for _, rule := range rules.Branches {
+		gcRules.BranchRetentionDays[rule.BranchId] = rule.TransitionDays
+	}
+	active, expired, err := c.Store.GetExpiredCommits(ctx, repository, gcRules)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := newLifecycleAddresses()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.lifecycleMarkAddresses(ctx, repository, addresses, active, expired, runStart); err != nil {
+		addresses.close()
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (c *Catalog) lifecycleMarkAddresses(ctx context.Context, repository *graveler.RepositoryRecord, addresses *lifecycleAddresses, active, expired map[graveler.CommitID]graveler.MetaRangeID, runStart time.Time) error {
+	for _, metaRangeID := range active {
+		if err := c.lifecycleScanMetaRange(ctx, repository, metaRangeID, addresses.activeRanges, addresses.setActive); err != nil {
+			return err
+		}
+	}
+	if err := c.lifecycleMarkLinked(ctx, repository, addresses, runStart); err != nil {
+		return err
+	}
+
+	visited := make(map[graveler.RangeID]struct{}, len(addresses.activeRanges))
+	for rangeID := range addresses.activeRanges {
+		visited[rangeID] = struct{}{}
+	}
+	for _, metaRangeID := range expired {
+		if err := c.lifecycleScanMetaRange(ctx, repository, metaRangeID, visited, addresses.setExpired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lifecycleMarkLinked marks active the addresses currently linked to the repository apart from its active commits:
+// committed on a branch head, staged, shared with another repository, or reused by DedupBlob since runStart for an
+// entry not staged yet. Addresses are linked to any of them while the run goes on, so they are marked before the
+// expired addresses are marked, and again before each batch of expired addresses is transitioned.
+func (c *Catalog) lifecycleMarkLinked(ctx context.Context, repository *graveler.RepositoryRecord, addresses *lifecycleAddresses, runStart time.Time) error {
+	branches, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return err
+	}
+	defer branches.Close()
+	for branches.Next() {
+		commit, err := c.Store.GetCommit(ctx, repository, branches.Value().CommitID)
+		if err != nil {
+			return err
+		}
+		if err := c.lifecycleScanMetaRange(ctx, repository, commit.MetaRangeID, addresses.activeRanges, addresses.setActive); err != nil {
+			return err
+		}
+	}
+	if err := branches.Err(); err != nil {
+		return err
+	}
+
+	it, err := NewUncommittedIterator(ctx, c.Store, repository)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		if address, ok := relativeAddress(repository, it.Value().Entry); ok {
+			if err := addresses.setActive(address); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	if err := c.lifecycleScanShared(ctx, repository, addresses.setActive); err != nil {
+		return err
+	}
+	return c.lifecycleScanDeduped(ctx, repository, runStart.Add(-dedupReuseRetention), addresses.setActive)
+}
+
+// lifecycleScanMetaRange calls fn with the address of every object of the ranges of the metarange not visited yet
+func (c *Catalog) lifecycleScanMetaRange(ctx context.Context, repository *graveler.RepositoryRecord, metaRangeID graveler.MetaRangeID, visited map[graveler.RangeID]struct{}, fn func(address string) error) error {
+	if metaRangeID == "" {
+		return nil
+	}
+	ranges, err := c.Store.ListRanges(ctx, repository, metaRangeID)
+	if err != nil {
+		return err
+	}
+	for _, rng := range ranges {
+		if _, ok := visited[rng.ID]; ok {
+			continue
+		}
+		visited[rng.ID] = struct{}{}
+		if err := c.lifecycleScanRange(ctx, repository, rng.ID, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) lifecycleScanRange(ctx context.Context, repository *graveler.RepositoryRecord, rangeID graveler.RangeID, fn func(address string) error) error {
+	values, err := c.Store.ListRange(ctx, repository, rangeID)
+	if err != nil {
+		return err
+	}
+	it := NewValueToEntryIterator(values)
+	defer it.Close()
+	for it.Next() {
+		if address, ok := relativeAddress(repository, it.Value().Entry); ok {
+			if err := fn(address); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Err()
+}
+
+// lifecycleScanShared calls fn with the address of every object of the repository shared with another repository,
+// see shareEntryAddress
+func (c *Catalog) lifecycleScanShared(ctx context.Context, repository *graveler.RepositoryRecord, fn func(address string) error) error {
+	normalizedStorageNamespace := string(repository.StorageNamespace)
+	if !strings.HasSuffix(normalizedStorageNamespace, DefaultPathDelimiter) {
+		normalizedStorageNamespace += DefaultPathDelimiter
+	}
+	prefix := sharedAddressPrefix(repository.StorageID.String(), normalizedStorageNamespace)
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&SharedAddressData{}).ProtoReflect().Type(), sharedAddressesPartition, []byte(prefix), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		data, ok := it.Entry().Value.(*SharedAddressData)
+		if !ok {
+			return graveler.ErrReadingFromStore
+		}
+		if err := fn(strings.TrimPrefix(data.Address, normalizedStorageNamespace)); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// lifecycleScanDeduped calls fn with the address of every object of the repository reused by DedupBlob after since,
+// see gcWriteDeduped
+func (c *Catalog) lifecycleScanDeduped(ctx context.Context, repository *graveler.RepositoryRecord, since time.Time, fn func(address string) error) error {
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&DedupAddressData{}).ProtoReflect().Type(), graveler.RepoPartition(repository), []byte(dedupPath("", "")), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		data, ok := it.Entry().Value.(*DedupAddressData)
+		if !ok {
+			return graveler.ErrReadingFromStore
+		}
+		if data.ReusedAt == nil || data.ReusedAt.AsTime().Before(since) {
+			continue
+		}
+		if err := fn(data.Address); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// relativeAddress returns the address of the entry relative to the storage namespace of the repository, or false in
+// case the entry points to an object outside of it
+func relativeAddress(repository *graveler.RepositoryRecord, entry *Entry) (string, bool) {
+	if entry == nil {
+		return "", false
+	}
+	if entry.AddressType == Entry_RELATIVE {
+		return entry.Address, true
+	}
+	storageNamespace := string(repository.StorageNamespace)
+	if !strings.HasSuffix(storageNamespace, DefaultPathDelimiter) {
+		storageNamespace += DefaultPathDelimiter
+	}
+	if !strings.HasPrefix(entry.Address, storageNamespace) {
+		return "", false
+	}
+	return entry.Address[len(storageNamespace):], true
+}
+
+// lifecycleTransitionExpired transitions the objects of the expired addresses to storageClass. Before each batch of
+// addresses is transitioned, the addresses linked meanwhile are marked active and skipped, see lifecycleMarkLinked.
+func (c *Catalog) lifecycleTransitionExpired(ctx context.Context, repository *graveler.RepositoryRecord, storageClass string, addresses *lifecycleAddresses, runStart time.Time, status *LifecycleRunStatus) error {
+	return addresses.forEachExpiredBatch(lifecycleTransitionBatchSize, func(batch []string) error {
+		if err := c.lifecycleMarkLinked(ctx, repository, addresses, runStart); err != nil {
+			return err
+		}
+		for _, address := range batch {
+			expired, err := addresses.isExpired(address)
+			if err != nil {
+				return err
+			}
+			if !expired {
+				continue
+			}
+			if err := c.lifecycleTransition(ctx, repository, storageClass, address, status); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lifecycleTransition transitions the object to storageClass, skipping objects already there or already deleted
+func (c *Catalog) lifecycleTransition(ctx context.Context, repository *graveler.RepositoryRecord, storageClass string, address string, status *LifecycleRunStatus) error {
+	obj := block.ObjectPointer{
+		StorageID:        repository.StorageID.String(),
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       address,
+		IdentifierType:   block.IdentifierTypeRelative,
+	}
+	c.BackgroundLimiter.Take()
+	props, err := c.BlockAdapter.GetProperties(ctx, obj)
+	if err != nil {
+		// objects may be deleted by garbage collection in the meantime
+		c.log(ctx).WithError(err).WithFields(logging.Fields{
+			"repository":    repository.RepositoryID,
+			"storage_class": storageClass,
+			"address":       address,
+		}).Warn("Lifecycle skipped object")
+		return nil
+	}
+	if props.StorageClass != nil && *props.StorageClass == storageClass {
+		return nil
+	}
+	err = c.BlockAdapter.SetStorageClass(ctx, obj, storageClass)
+	if errors.Is(err, block.ErrDataNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("transition %s: %w", address, err)
+	}
+	status.TransitionedObjects++
+	return nil
+}
+
+// RestoreObject starts restoring the archived object of the entry at path, making it readable for the given days
+func (c *Catalog) RestoreObject(ctx context.Context, repositoryID, ref, path string, days int) error {
+	if days <= 0 {
+		return fmt.Errorf("days: %w", graveler.ErrInvalidValue)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	entry, err := c.GetEntry(ctx, repositoryID, ref, path, GetEntryParams{})
+	if err != nil {
+		return err
+	}
+	return c.BlockAdapter.Restore(ctx, block.ObjectPointer{
+		StorageID:        repository.StorageID.String(),
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       entry.PhysicalAddress,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+	}, days)
+}
@@ -0,0 +1,258 @@
+package catalog
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	gUtils "github.com/treeverse/lakefs/pkg/graveler/testutil"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"go.uber.org/ratelimit"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// lifecycleGraveler lists the ranges of each metarange and the entries of each range, and splits commits into fixed
+// active and expired sets
+type lifecycleGraveler struct {
+	*FakeGraveler
+	ranges  map[graveler.MetaRangeID][]graveler.RangeID
+	entries map[graveler.RangeID][]*graveler.ValueRecord
+	active  map[graveler.CommitID]graveler.MetaRangeID
+	expired map[graveler.CommitID]graveler.MetaRangeID
+}
+
+func (g *lifecycleGraveler) GetCommit(_ context.Context, _ *graveler.RepositoryRecord, commitID graveler.CommitID) (*graveler.Commit, error) {
+	if metaRangeID, ok := g.active[commitID]; ok {
+		return &graveler.Commit{MetaRangeID: metaRangeID}, nil
+	}
+	if metaRangeID, ok := g.expired[commitID]; ok {
+		return &graveler.Commit{MetaRangeID: metaRangeID}, nil
+	}
+	return nil, graveler.ErrCommitNotFound
+}
+
+func (g *lifecycleGraveler) ListRanges(_ context.Context, _ *graveler.RepositoryRecord, metaRangeID graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	var ranges []*graveler.RangeInfo
+	for _, rangeID := range g.ranges[metaRangeID] {
+		ranges = append(ranges, &graveler.RangeInfo{ID: rangeID})
+	}
+	return ranges, nil
+}
+
+func (g *lifecycleGraveler) ListRange(_ context.Context, _ *graveler.RepositoryRecord, rangeID graveler.RangeID) (graveler.ValueIterator, error) {
+	return NewFakeValueIterator(g.entries[rangeID]), nil
+}
+
+func (g *lifecycleGraveler) GetExpiredCommits(_ context.Context, _ *graveler.RepositoryRecord, _ *graveler.GarbageCollectionRules) (map[graveler.CommitID]graveler.MetaRangeID, map[graveler.CommitID]graveler.MetaRangeID, error) {
+	return g.active, g.expired, nil
+}
+
+func lifecycleRecord(path, address string, addressType Entry_AddressType) *graveler.ValueRecord {
+	return &graveler.ValueRecord{
+		Key:   graveler.Key(path),
+		Value: MustEntryToValue(&Entry{Address: address, AddressType: addressType}),
+	}
+}
+
+func TestCatalog_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	const storageNamespace = "mem://lifecycle"
+	repository := &graveler.RepositoryRecord{
+		RepositoryID: "lifecycle",
+		Repository:   &graveler.Repository{StorageNamespace: storageNamespace},
+	}
+	store := &lifecycleGraveler{
+		FakeGraveler: &FakeGraveler{
+			BranchIteratorFactory: gUtils.NewFakeBranchIteratorFactory([]*graveler.BranchRecord{
+				{BranchID: "main", Branch: &graveler.Branch{CommitID: "c2", StagingToken: "st"}},
+			}),
+			ListStagingIteratorFactory: NewFakeStagingIteratorFactory(map[graveler.StagingToken][]*graveler.ValueRecord{
+				"st": {lifecycleRecord("staged", "data/staged", Entry_RELATIVE)},
+			}),
+		},
+		ranges: map[graveler.MetaRangeID][]graveler.RangeID{
+			"m1": {"r1", "r-shared"},
+			"m2": {"r2", "r-shared"},
+		},
+		entries: map[graveler.RangeID][]*graveler.ValueRecord{
+			"r1": {
+				lifecycleRecord("copy", "data/new", Entry_RELATIVE),
+				lifecycleRecord("imported", "mem://elsewhere/data", Entry_FULL),
+				lifecycleRecord("lent", "data/lent", Entry_RELATIVE),
+				lifecycleRecord("old", "data/old", Entry_RELATIVE),
+				lifecycleRecord("old-full", storageNamespace+"/data/old-full", Entry_FULL),
+				lifecycleRecord("staged", "data/staged", Entry_RELATIVE),
+			},
+			"r2": {
+				lifecycleRecord("new", "data/new", Entry_RELATIVE),
+			},
+			"r-shared": {
+				lifecycleRecord("shared", "data/shared", Entry_RELATIVE),
+			},
+		},
+		active:  map[graveler.CommitID]graveler.MetaRangeID{"c2": "m2"},
+		expired: map[graveler.CommitID]graveler.MetaRangeID{"c1": "m1", "c3": "m2"},
+	}
+	kvStore := kvtest.GetStore(ctx, t)
+	// data/lent is shared with another repository
+	require.NoError(t, kv.SetMsg(ctx, kvStore, sharedAddressesPartition, []byte(sharedAddressPath("", storageNamespace+"/data/lent", "other", "lent")), &SharedAddressData{
+		Address:      storageNamespace + "/data/lent",
+		RepositoryId: "other",
+	}))
+	adapter := mem.New(ctx)
+	c := &Catalog{
+		Store:             store,
+		BlockAdapter:      adapter,
+		KVStore:           kvStore,
+		BackgroundLimiter: ratelimit.NewUnlimited(),
+	}
+
+	expired, err := c.lifecycleExpiredAddresses(ctx, repository, &LifecycleRules{DefaultTransitionDays: 30, StorageClass: block.StorageClassGlacier}, time.Now())
+	require.NoError(t, err)
+	defer expired.close()
+	var addresses []string
+	require.NoError(t, expired.forEachExpired(func(address string) error {
+		addresses = append(addresses, address)
+		return nil
+	}))
+	require.Equal(t, []string{"data/old", "data/old-full"}, addresses)
+
+	pointer := func(address string) block.ObjectPointer {
+		return block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: address, IdentifierType: block.IdentifierTypeRelative}
+	}
+	for _, address := range []string{"data/old", "data/new"} {
+		require.NoError(t, adapter.Put(ctx, pointer(address), 4, strings.NewReader("data"), block.PutOpts{}))
+	}
+	transition := func(status *LifecycleRunStatus) {
+		t.Helper()
+		for _, address := range addresses {
+			require.NoError(t, c.lifecycleTransition(ctx, repository, block.StorageClassGlacier, address, status))
+		}
+	}
+	status := &LifecycleRunStatus{}
+	transition(status)
+	// data/old-full was already deleted
+	require.Equal(t, int64(1), status.TransitionedObjects)
+
+	_, err = adapter.Get(ctx, pointer("data/old"), 0)
+	require.ErrorIs(t, err, block.ErrRestoreRequired)
+	_, err = adapter.Get(ctx, pointer("data/new"), 0)
+	require.NoError(t, err)
+
+	// objects already in the storage class are skipped
+	status = &LifecycleRunStatus{}
+	transition(status)
+	require.Equal(t, int64(0), status.TransitionedObjects)
+
+	require.NoError(t, adapter.Restore(ctx, pointer("data/old"), 1))
+	r, err := adapter.Get(ctx, pointer("data/old"), 0)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+}
+
+// TestCatalog_LifecycleLinked verifies that addresses linked to the repository while a lifecycle run goes on are not
+// transitioned
+func TestCatalog_LifecycleLinked(t *testing.T) {
+	ctx := context.Background()
+	const storageNamespace = "mem://lifecycle"
+	repository := &graveler.RepositoryRecord{
+		RepositoryID: "lifecycle",
+		Repository:   &graveler.Repository{StorageNamespace: storageNamespace},
+	}
+	staging := map[graveler.StagingToken][]*graveler.ValueRecord{}
+	main := &graveler.BranchRecord{BranchID: "main", Branch: &graveler.Branch{CommitID: "c2", StagingToken: "st"}}
+	store := &lifecycleGraveler{
+		FakeGraveler: &FakeGraveler{
+			BranchIteratorFactory:      gUtils.NewFakeBranchIteratorFactory([]*graveler.BranchRecord{main}),
+			ListStagingIteratorFactory: NewFakeStagingIteratorFactory(staging),
+		},
+		ranges: map[graveler.MetaRangeID][]graveler.RangeID{
+			"m1": {"r1"},
+			"m2": {"r2"},
+			"m3": {"r3"},
+		},
+		entries: map[graveler.RangeID][]*graveler.ValueRecord{
+			"r1": {
+				lifecycleRecord("committed", "data/committed", Entry_RELATIVE),
+				lifecycleRecord("deduped", "data/deduped", Entry_RELATIVE),
+				lifecycleRecord("expired", "data/expired", Entry_RELATIVE),
+				lifecycleRecord("reused", "data/reused", Entry_RELATIVE),
+				lifecycleRecord("shared", "data/shared", Entry_RELATIVE),
+				lifecycleRecord("staged", "data/staged", Entry_RELATIVE),
+			},
+			"r2": {
+				lifecycleRecord("new", "data/new", Entry_RELATIVE),
+			},
+			"r3": {
+				lifecycleRecord("committed", "data/committed", Entry_RELATIVE),
+			},
+		},
+		active:  map[graveler.CommitID]graveler.MetaRangeID{"c2": "m2"},
+		expired: map[graveler.CommitID]graveler.MetaRangeID{"c1": "m1", "c3": "m3"},
+	}
+	kvStore := kvtest.GetStore(ctx, t)
+	adapter := mem.New(ctx)
+	c := &Catalog{
+		Store:             store,
+		BlockAdapter:      adapter,
+		KVStore:           kvStore,
+		BackgroundLimiter: ratelimit.NewUnlimited(),
+	}
+	reuse := func(address string, reusedAt time.Time) {
+		t.Helper()
+		require.NoError(t, kv.SetMsg(ctx, kvStore, graveler.RepoPartition(repository), []byte(dedupPath("", address)), &DedupAddressData{
+			Address:  address,
+			ReusedAt: timestamppb.New(reusedAt),
+		}))
+	}
+	pointer := func(address string) block.ObjectPointer {
+		return block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: address, IdentifierType: block.IdentifierTypeRelative}
+	}
+
+	// data/reused was reused before the run started, its entry is not staged yet
+	runStart := time.Now()
+	reuse("data/reused", runStart.Add(-time.Hour))
+	expired, err := c.lifecycleExpiredAddresses(ctx, repository, &LifecycleRules{DefaultTransitionDays: 30, StorageClass: block.StorageClassGlacier}, runStart)
+	require.NoError(t, err)
+	defer expired.close()
+	var addresses []string
+	require.NoError(t, expired.forEachExpired(func(address string) error {
+		addresses = append(addresses, address)
+		return nil
+	}))
+	require.Equal(t, []string{"data/committed", "data/deduped", "data/expired", "data/shared", "data/staged"}, addresses)
+	for _, address := range addresses {
+		require.NoError(t, adapter.Put(ctx, pointer(address), 4, strings.NewReader("data"), block.PutOpts{}))
+	}
+
+	// link expired addresses after they were marked
+	staging["st"] = []*graveler.ValueRecord{lifecycleRecord("staged", "data/staged", Entry_RELATIVE)}
+	reuse("data/deduped", time.Now())
+	require.NoError(t, kv.SetMsg(ctx, kvStore, sharedAddressesPartition, []byte(sharedAddressPath("", storageNamespace+"/data/shared", "other", "shared")), &SharedAddressData{
+		Address:      storageNamespace + "/data/shared",
+		RepositoryId: "other",
+	}))
+	// main is reset to an expired commit, committed on it since the run started
+	main.CommitID = "c3"
+
+	status := &LifecycleRunStatus{}
+	require.NoError(t, c.lifecycleTransitionExpired(ctx, repository, block.StorageClassGlacier, expired, runStart, status))
+	require.Equal(t, int64(1), status.TransitionedObjects)
+	for _, address := range addresses {
+		_, err := adapter.Get(ctx, pointer(address), 0)
+		if address == "data/expired" {
+			require.ErrorIs(t, err, block.ErrRestoreRequired)
+		} else {
+			require.NoError(t, err, "address %s", address)
+		}
+	}
+}
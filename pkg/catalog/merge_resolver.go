@@ -0,0 +1,331 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Shopify/go-lua"
+	lualibs "github.com/treeverse/lakefs/pkg/actions/lua"
+	luautil "github.com/treeverse/lakefs/pkg/actions/lua/util"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const (
+	MergeStrategyNewestWinsStr = "newest-wins"
+	MergeStrategyLargerWinsStr = "larger-wins"
+	MergeStrategyLuaHookStr    = "lua-hook"
+)
+
+const (
+	mergeResolversSettingKey = "merge_resolvers"
+
+	// maxMergeResolverScriptSize limits the size of a Lua script resolving merge conflicts
+	maxMergeResolverScriptSize = 1024 * 1024
+
+	// maxMergeResolverInstructions limits the instructions a Lua script runs to resolve a single conflict
+	maxMergeResolverInstructions = 10_000_000
+)
+
+// entryCompareFunc compares source and destination entries, returns a positive value to pick the source, a negative
+// value to pick the destination and zero in case it cannot decide.
+type entryCompareFunc func(source, dest *Entry) int
+
+// entryMergeResolver resolves merge conflicts between two versions of an object by comparing their entries.
+// Conflicts where one of the sides deleted the object are not resolved.
+type entryMergeResolver struct {
+	compare entryCompareFunc
+}
+
+func (r *entryMergeResolver) Resolve(_ context.Context, _, source, dest *graveler.ValueRecord) (*graveler.ValueRecord, error) {
+	if source == nil || dest == nil || source.Value == nil || dest.Value == nil {
+		return nil, graveler.ErrConflictFound
+	}
+	sourceEntry, err := ValueToEntry(source.Value)
+	if err != nil {
+		return nil, fmt.Errorf("source entry %s: %w", source.Key, err)
+	}
+	destEntry, err := ValueToEntry(dest.Value)
+	if err != nil {
+		return nil, fmt.Errorf("destination entry %s: %w", dest.Key, err)
+	}
+	c := r.compare(sourceEntry, destEntry)
+	switch {
+	case c > 0:
+		return source, nil
+	case c < 0:
+		return dest, nil
+	default:
+		return nil, graveler.ErrConflictFound
+	}
+}
+
+func compareLastModified(source, dest *Entry) int {
+	return source.GetLastModified().AsTime().Compare(dest.GetLastModified().AsTime())
+}
+
+func compareSize(source, dest *Entry) int {
+	switch {
+	case source.GetSize() > dest.GetSize():
+		return 1
+	case source.GetSize() < dest.GetSize():
+		return -1
+	default:
+		return 0
+	}
+}
+
+// mergeResolvers holds the content-aware merge strategies provided by the catalog, by strategy name
+var mergeResolvers = map[string]graveler.MergeResolver{
+	MergeStrategyNewestWinsStr: &entryMergeResolver{compare: compareLastModified},
+	MergeStrategyLargerWinsStr: &entryMergeResolver{compare: compareSize},
+}
+
+// luaMergeResolver resolves merge conflicts by running a Lua script. The script sees the 'base', 'source' and 'dest'
+// entries of the conflicting object, nil for a side the object does not exist on, and returns "source" or "dest" to
+// pick that side, or nil to leave the conflict unresolved. The script is loaded and compiled on the first conflict
+// passed to it, into a Lua state kept for the rest of the merge. Each run is bounded by the context of its conflict
+// and by maxMergeResolverInstructions.
+type luaMergeResolver struct {
+	scriptPath string
+	load       func(ctx context.Context) (string, error)
+
+	mu    sync.Mutex
+	l     *lua.State
+	limit *lualibs.RunLimit
+	err   error
+}
+
+// discardOutput drops the output printed by merge resolver scripts
+type discardOutput struct{}
+
+func (discardOutput) WriteString(s string) (int, error) {
+	return len(s), nil
+}
+
+// state returns the Lua state of the merge, with the compiled script at the bottom of its stack
+func (r *luaMergeResolver) state(ctx context.Context) (*lua.State, error) {
+	if r.l != nil || r.err != nil {
+		return r.l, r.err
+	}
+	script, err := r.load(ctx)
+	if err != nil {
+		r.err = fmt.Errorf("load merge resolver script %s: %w", r.scriptPath, err)
+		return nil, r.err
+	}
+	l := lua.NewState()
+	lualibs.OpenSafe(l, ctx, lualibs.OpenSafeConfig{}, discardOutput{})
+	if err := lua.LoadBuffer(l, script, r.scriptPath, ""); err != nil {
+		r.err = fmt.Errorf("merge resolver script %s: %s: %w", r.scriptPath, err, graveler.ErrInvalidValue)
+		return nil, r.err
+	}
+	r.limit = lualibs.SetRunLimit(l, maxMergeResolverInstructions)
+	r.l = l
+	return l, nil
+}
+
+func (r *luaMergeResolver) Resolve(ctx context.Context, base, source, dest *graveler.ValueRecord) (*graveler.ValueRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, err := r.state(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// keep only the compiled script once resolved
+	defer l.SetTop(1)
+
+	for _, side := range []struct {
+		name   string
+		record *graveler.ValueRecord
+	}{{"base", base}, {"source", source}, {"dest", dest}} {
+		if err := pushConflictEntry(l, side.record); err != nil {
+			return nil, fmt.Errorf("%s entry: %w", side.name, err)
+		}
+		l.SetGlobal(side.name)
+	}
+	l.PushValue(1)
+	r.limit.Start(ctx)
+	if err := l.ProtectedCall(0, 1, 0); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("merge resolver script %s: %s: %w", r.scriptPath, err, graveler.ErrInvalidValue)
+	}
+	if l.IsNil(-1) {
+		return nil, graveler.ErrConflictFound
+	}
+	switch result, _ := l.ToString(-1); result {
+	case "source":
+		return source, nil
+	case "dest":
+		return dest, nil
+	default:
+		return nil, fmt.Errorf("merge resolver script %s returned '%s': %w", r.scriptPath, result, graveler.ErrInvalidValue)
+	}
+}
+
+// pushConflictEntry pushes the entry of record as a table, or nil if there is no record
+func pushConflictEntry(l *lua.State, record *graveler.ValueRecord) error {
+	if record == nil || record.Value == nil {
+		l.PushNil()
+		return nil
+	}
+	entry, err := ValueToEntry(record.Value)
+	if err != nil {
+		return err
+	}
+	metadata := make(map[string]interface{}, len(entry.Metadata))
+	for k, v := range entry.Metadata {
+		metadata[k] = v
+	}
+	luautil.DeepPush(l, map[string]interface{}{
+		"path":             record.Key.String(),
+		"physical_address": entry.Address,
+		"checksum":         entry.ETag,
+		"size":             entry.Size,
+		"content_type":     entry.ContentType,
+		"last_modified":    entry.GetLastModified().AsTime().Format(time.RFC3339),
+		"metadata":         metadata,
+	})
+	return nil
+}
+
+// GetMergeResolverRules returns the merge resolver rules of the repository, no rules in case none were set
+func (c *Catalog) GetMergeResolverRules(ctx context.Context, repositoryID string) (*MergeResolverRules, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	rules := &MergeResolverRules{}
+	_, err = c.settingManager.GetLatest(ctx, repository, mergeResolversSettingKey, rules)
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SetMergeResolverRules sets the merge resolver rules of the repository. Conflicts found while merging into any
+// branch of the repository are resolved by the strategy of the first rule matching their path, before falling back
+// to the strategy of the merge.
+func (c *Catalog) SetMergeResolverRules(ctx context.Context, repositoryID string, rules *MergeResolverRules) error {
+	for i, rule := range rules.Rules {
+		switch rule.Strategy {
+		case graveler.MergeStrategyDestWinsStr, graveler.MergeStrategySrcWinsStr, MergeStrategyNewestWinsStr, MergeStrategyLargerWinsStr:
+			if rule.ScriptPath != "" {
+				return fmt.Errorf("rule %d script path of strategy '%s': %w", i, rule.Strategy, graveler.ErrInvalidValue)
+			}
+		case MergeStrategyLuaHookStr:
+			if rule.ScriptPath == "" {
+				return fmt.Errorf("rule %d script path is required: %w", i, graveler.ErrInvalidValue)
+			}
+		default:
+			return fmt.Errorf("rule %d strategy '%s': %w", i, rule.Strategy, graveler.ErrInvalidValue)
+		}
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if repository.ReadOnly {
+		return graveler.ErrReadOnlyRepository
+	}
+	return c.settingManager.Save(ctx, repository, mergeResolversSettingKey, rules, nil)
+}
+
+// mergeResolver returns the resolver of conflicts found while merging into destination using strategy. Conflicts are
+// passed to the first merge resolver rule of the repository matching their path, and to the strategy in case no rule
+// matches. Returns nil in case the repository has no rules and the strategy is resolved by graveler.
+func (c *Catalog) mergeResolver(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, strategy string) (graveler.MergeResolver, error) {
+	strategyResolver, ok := mergeResolvers[strategy]
+	if !ok {
+		strategyResolver = graveler.NewStrategyMergeResolver(strategy)
+	}
+	rules := &MergeResolverRules{}
+	err := c.settingManager.Get(ctx, repository, mergeResolversSettingKey, rules)
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		return nil, err
+	}
+	if len(rules.Rules) == 0 {
+		if !ok {
+			return nil, nil
+		}
+		return strategyResolver, nil
+	}
+
+	resolvers := make(graveler.MergeResolverRules, 0, len(rules.Rules)+1)
+	for _, rule := range rules.Rules {
+		var resolver graveler.MergeResolver
+		switch rule.Strategy {
+		case MergeStrategyLuaHookStr:
+			scriptPath := rule.ScriptPath
+			resolver = &luaMergeResolver{
+				scriptPath: scriptPath,
+				load: func(ctx context.Context) (string, error) {
+					return c.loadMergeResolverScript(ctx, repository, destination, scriptPath)
+				},
+			}
+		default:
+			resolver = mergeResolvers[rule.Strategy]
+			if resolver == nil {
+				resolver = graveler.NewStrategyMergeResolver(rule.Strategy)
+			}
+		}
+		if resolver == nil {
+			return nil, fmt.Errorf("merge resolver rule strategy '%s': %w", rule.Strategy, graveler.ErrInvalidMergeStrategy)
+		}
+		resolvers = append(resolvers, graveler.MergeResolverRule{Prefix: graveler.Key(rule.Path), Resolver: resolver})
+	}
+	if strategyResolver != nil {
+		resolvers = append(resolvers, graveler.MergeResolverRule{Resolver: strategyResolver})
+	}
+	return resolvers, nil
+}
+
+// loadMergeResolverScript reads the Lua script at scriptPath on the destination branch of the merge, so that the
+// merged source cannot change how its own conflicts are resolved
+func (c *Catalog) loadMergeResolverScript(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, scriptPath string) (string, error) {
+	value, err := c.Store.Get(ctx, repository, graveler.Ref(destination), graveler.Key(scriptPath))
+	if err != nil {
+		return "", err
+	}
+	entry, err := ValueToEntry(value)
+	if err != nil {
+		return "", err
+	}
+	if entry.Size > maxMergeResolverScriptSize {
+		return "", fmt.Errorf("script size %d exceeds %d: %w", entry.Size, maxMergeResolverScriptSize, graveler.ErrInvalidValue)
+	}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageID:        repository.StorageID.String(),
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   addressTypeToCatalog(entry.AddressType).ToIdentifierType(),
+		Identifier:       entry.Address,
+		Compression:      Metadata(entry.Metadata).Compression(),
+	}, entry.Size)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	script, err := io.ReadAll(io.LimitReader(reader, maxMergeResolverScriptSize))
+	if err != nil {
+		return "", err
+	}
+	return string(script), nil
+}
+
+func validateMergeStrategy(v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		panic(graveler.ErrInvalidType)
+	}
+	if _, ok := mergeResolvers[s]; ok {
+		return nil
+	}
+	return graveler.ValidateRequiredStrategy(s)
+}
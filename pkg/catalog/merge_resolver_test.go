@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMergeResolvers(t *testing.T) {
+	now := time.Now()
+	older := &graveler.ValueRecord{
+		Key: graveler.Key("older"),
+		Value: MustEntryToValue(&Entry{
+			Address:      "older",
+			LastModified: timestamppb.New(now.Add(-time.Hour)),
+			Size:         200,
+			ETag:         "older",
+		}),
+	}
+	newer := &graveler.ValueRecord{
+		Key: graveler.Key("newer"),
+		Value: MustEntryToValue(&Entry{
+			Address:      "newer",
+			LastModified: timestamppb.New(now),
+			Size:         100,
+			ETag:         "newer",
+		}),
+	}
+
+	tests := []struct {
+		name         string
+		strategy     string
+		source, dest *graveler.ValueRecord
+		expected     *graveler.ValueRecord
+		expectedErr  error
+	}{
+		{name: "newest_source", strategy: MergeStrategyNewestWinsStr, source: newer, dest: older, expected: newer},
+		{name: "newest_dest", strategy: MergeStrategyNewestWinsStr, source: older, dest: newer, expected: newer},
+		{name: "newest_same", strategy: MergeStrategyNewestWinsStr, source: newer, dest: newer, expectedErr: graveler.ErrConflictFound},
+		{name: "newest_deleted", strategy: MergeStrategyNewestWinsStr, source: nil, dest: newer, expectedErr: graveler.ErrConflictFound},
+		{name: "larger_source", strategy: MergeStrategyLargerWinsStr, source: older, dest: newer, expected: older},
+		{name: "larger_dest", strategy: MergeStrategyLargerWinsStr, source: newer, dest: older, expected: older},
+		{name: "larger_same", strategy: MergeStrategyLargerWinsStr, source: older, dest: older, expectedErr: graveler.ErrConflictFound},
+		{name: "larger_deleted", strategy: MergeStrategyLargerWinsStr, source: older, dest: nil, expectedErr: graveler.ErrConflictFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := mergeResolvers[tt.strategy]
+			result, err := resolver.Resolve(context.Background(), nil, tt.source, tt.dest)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Resolve() error=%v, expected=%v", err, tt.expectedErr)
+			}
+			if result != tt.expected {
+				t.Fatalf("Resolve() result=%v, expected=%v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateMergeStrategy(t *testing.T) {
+	for _, strategy := range []string{"", "dest-wins", "source-wins", MergeStrategyNewestWinsStr, MergeStrategyLargerWinsStr} {
+		if err := validateMergeStrategy(strategy); err != nil {
+			t.Errorf("validateMergeStrategy(%s) unexpected error: %s", strategy, err)
+		}
+	}
+	if err := validateMergeStrategy("unknown-wins"); !errors.Is(err, graveler.ErrInvalidValue) {
+		t.Errorf("validateMergeStrategy(unknown-wins) error=%v, expected=%s", err, graveler.ErrInvalidValue)
+	}
+}
+
+func TestLuaMergeResolver(t *testing.T) {
+	source := &graveler.ValueRecord{Key: graveler.Key("a/1"), Value: MustEntryToValue(&Entry{Address: "source", Size: 2, LastModified: timestamppb.Now()})}
+	dest := &graveler.ValueRecord{Key: graveler.Key("a/1"), Value: MustEntryToValue(&Entry{Address: "dest", Size: 1, LastModified: timestamppb.Now()})}
+
+	tests := []struct {
+		name        string
+		script      string
+		expected    *graveler.ValueRecord
+		expectedErr error
+	}{
+		{name: "source", script: `if source.size > dest.size then return "source" end return "dest"`, expected: source},
+		{name: "dest", script: `if base == nil and dest.physical_address == "dest" then return "dest" end`, expected: dest},
+		{name: "unresolved", script: `return nil`, expectedErr: graveler.ErrConflictFound},
+		{name: "invalid_result", script: `return "base"`, expectedErr: graveler.ErrInvalidValue},
+		{name: "script_error", script: `error("failed")`, expectedErr: graveler.ErrInvalidValue},
+		{name: "instruction_limit", script: `while true do end`, expectedErr: graveler.ErrInvalidValue},
+		{name: "remove_limit", script: `debug.sethook() while true do end`, expectedErr: graveler.ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &luaMergeResolver{
+				scriptPath: "resolve.lua",
+				load: func(context.Context) (string, error) {
+					return tt.script, nil
+				},
+			}
+			result, err := resolver.Resolve(context.Background(), nil, source, dest)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Resolve() error=%v, expected=%v", err, tt.expectedErr)
+			}
+			if result != tt.expected {
+				t.Fatalf("Resolve() result=%v, expected=%v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLuaMergeResolver_Merge(t *testing.T) {
+	source := &graveler.ValueRecord{Key: graveler.Key("a/1"), Value: MustEntryToValue(&Entry{Address: "source", LastModified: timestamppb.Now()})}
+	dest := &graveler.ValueRecord{Key: graveler.Key("a/1"), Value: MustEntryToValue(&Entry{Address: "dest", LastModified: timestamppb.Now()})}
+	newResolver := func(script string, loads *int) *luaMergeResolver {
+		return &luaMergeResolver{
+			scriptPath: "resolve.lua",
+			load: func(context.Context) (string, error) {
+				*loads++
+				return script, nil
+			},
+		}
+	}
+
+	t.Run("reuse_state", func(t *testing.T) {
+		// the state is kept for all the conflicts of the merge
+		loads := 0
+		resolver := newResolver(`resolved = (resolved or 0) + 1 if resolved == 1 then return "source" end return "dest"`, &loads)
+		for _, expected := range []*graveler.ValueRecord{source, dest, dest} {
+			result, err := resolver.Resolve(context.Background(), nil, source, dest)
+			if err != nil {
+				t.Fatalf("Resolve() error=%v", err)
+			}
+			if result != expected {
+				t.Fatalf("Resolve() result=%v, expected=%v", result, expected)
+			}
+		}
+		if loads != 1 {
+			t.Fatalf("script loaded %d times, expected once", loads)
+		}
+	})
+
+	t.Run("limit_per_conflict", func(t *testing.T) {
+		// each conflict runs more than half of the instructions limit
+		loads := 0
+		resolver := newResolver(`for i = 1, 6000000 do end return "dest"`, &loads)
+		for i := 0; i < 3; i++ {
+			result, err := resolver.Resolve(context.Background(), nil, source, dest)
+			if err != nil {
+				t.Fatalf("Resolve() %d error=%v", i, err)
+			}
+			if result != dest {
+				t.Fatalf("Resolve() %d result=%v, expected=%v", i, result, dest)
+			}
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		loads := 0
+		resolver := newResolver(`if source.physical_address == "dest" then return "dest" end while true do end`, &loads)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := resolver.Resolve(ctx, nil, source, dest)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Resolve() error=%v, expected=%v", err, context.Canceled)
+		}
+		// the state is still usable by the next conflicts
+		result, err := resolver.Resolve(context.Background(), nil, dest, dest)
+		if err != nil || result != dest {
+			t.Fatalf("Resolve() result=%v error=%v, expected=%v", result, err, dest)
+		}
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
 )
 
 const (
@@ -19,6 +21,7 @@ type CommitGeneration int64
 
 type Repository struct {
 	Name             string
+	StorageID        string
 	StorageNamespace string
 	DefaultBranch    string
 	CreationDate     time.Time
@@ -48,6 +51,9 @@ type CommitLog struct {
 	Parents      []string
 	Generation   CommitGeneration
 	Version      CommitVersion
+	Signature    []byte
+	// Verified is set when the commit signature was made by a trusted key
+	Verified bool
 }
 
 type Branch struct {
@@ -60,6 +66,16 @@ type Tag struct {
 	CommitID string
 }
 
+// ReflogEntry records a movement of a branch head
+type ReflogEntry struct {
+	ID           string
+	OldCommitID  string
+	NewCommitID  string
+	Operation    string
+	User         string
+	CreationDate time.Time
+}
+
 // AddressType is the type of an entry address
 type AddressType int32
 
@@ -109,6 +125,36 @@ func (j *Metadata) Scan(src interface{}) error {
 	return json.Unmarshal(data, j)
 }
 
+// IsReservedMetadataKey reports whether the metadata key is reserved for the catalog: the compression marker and the
+// object tags. Reserved keys are set only by the catalog helpers owning them, never by user metadata.
+func IsReservedMetadataKey(key string) bool {
+	return key == CompressionMetadataKey || strings.HasPrefix(key, ObjectTagMetadataPrefix)
+}
+
+// ValidateUserMetadata fails with graveler.ErrInvalidValue in case the user metadata sets a reserved key
+func ValidateUserMetadata(metadata map[string]string) error {
+	for k := range metadata {
+		if IsReservedMetadataKey(k) {
+			return fmt.Errorf("metadata key '%s' is reserved: %w", k, graveler.ErrInvalidValue)
+		}
+	}
+	return nil
+}
+
+// UserMetadata returns a copy of the metadata without its reserved keys, nil for nil metadata
+func (j Metadata) UserMetadata() Metadata {
+	if j == nil {
+		return nil
+	}
+	metadata := make(Metadata, len(j))
+	for k, v := range j {
+		if !IsReservedMetadataKey(k) {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
 func ContentTypeOrDefault(ct string) string {
 	if ct == "" {
 		return DefaultContentType
@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// ObjectTagMetadataPrefix is the prefix of the entry metadata keys reserved for object tags. Object tags are kept in
+// the metadata of the entry, so they are versioned with it.
+const ObjectTagMetadataPrefix = "::lakefs::tag::"
+
+// ObjectTags returns the object tags kept in the metadata
+func (m Metadata) ObjectTags() map[string]string {
+	tags := make(map[string]string)
+	for k, v := range m {
+		if tag, ok := strings.CutPrefix(k, ObjectTagMetadataPrefix); ok {
+			tags[tag] = v
+		}
+	}
+	return tags
+}
+
+// WithObjectTags returns a copy of the metadata with its object tags replaced by tags
+func (m Metadata) WithObjectTags(tags map[string]string) Metadata {
+	metadata := make(Metadata, len(m)+len(tags))
+	for k, v := range m {
+		if !strings.HasPrefix(k, ObjectTagMetadataPrefix) {
+			metadata[k] = v
+		}
+	}
+	for k, v := range tags {
+		metadata[ObjectTagMetadataPrefix+k] = v
+	}
+	return metadata
+}
+
+// setEntryTagsAttempts bounds the attempts to set the tags of an entry replaced concurrently
+const setEntryTagsAttempts = 3
+
+// SetEntryTags replaces the object tags of the entry of path on branch. The entry keeps its address and creation
+// date, the change of its tags is committed like any other change of the entry.
+// The entry is written only if it still points to the object read, in case the object was replaced concurrently the
+// tags are set on the replacing object. Fails with graveler.ErrPreconditionFailed if the object keeps changing.
+func (c *Catalog) SetEntryTags(ctx context.Context, repositoryID, branch, path string, tags map[string]string, opts ...graveler.SetOptionsFunc) error {
+	for i := 0; i < setEntryTagsAttempts; i++ {
+		entry, err := c.GetEntry(ctx, repositoryID, branch, path, GetEntryParams{})
+		if err != nil {
+			return err
+		}
+		entry.Metadata = entry.Metadata.WithObjectTags(tags)
+		err = c.CreateEntry(ctx, repositoryID, branch, *entry, append(opts, withSameObject(entry))...)
+		if !errors.Is(err, graveler.ErrPreconditionFailed) {
+			return err
+		}
+	}
+	return graveler.ErrPreconditionFailed
+}
+
+// withSameObject returns a set option failing with graveler.ErrPreconditionFailed unless the current entry of the
+// path points to the address and checksum of entry
+func withSameObject(entry *DBEntry) graveler.SetOptionsFunc {
+	return graveler.WithCondition(func(currentValue *graveler.Value) error {
+		if currentValue == nil {
+			return graveler.ErrPreconditionFailed
+		}
+		current, err := ValueToEntry(currentValue)
+		if err != nil {
+			return err
+		}
+		if current.Address != entry.PhysicalAddress || current.ETag != entry.Checksum {
+			return graveler.ErrPreconditionFailed
+		}
+		return nil
+	})
+}
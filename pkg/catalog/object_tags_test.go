@@ -0,0 +1,41 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestMetadata_ObjectTags(t *testing.T) {
+	metadata := catalog.Metadata{
+		"X-Amz-Meta-Owner":                      "data",
+		catalog.ObjectTagMetadataPrefix + "env": "dev",
+		catalog.ObjectTagMetadataPrefix + "tmp": "",
+	}
+	require.Equal(t, map[string]string{"env": "dev", "tmp": ""}, metadata.ObjectTags())
+
+	tagged := metadata.WithObjectTags(map[string]string{"env": "prod"})
+	require.Equal(t, catalog.Metadata{
+		"X-Amz-Meta-Owner":                      "data",
+		catalog.ObjectTagMetadataPrefix + "env": "prod",
+	}, tagged)
+	// the original metadata is kept
+	require.Len(t, metadata, 3)
+
+	require.Empty(t, tagged.WithObjectTags(nil).ObjectTags())
+	require.Empty(t, catalog.Metadata(nil).ObjectTags())
+}
+
+func TestMetadata_UserMetadata(t *testing.T) {
+	metadata := catalog.Metadata{"X-Amz-Meta-Owner": "data"}.
+		WithCompression("gzip").
+		WithObjectTags(map[string]string{"env": "dev"})
+	require.Equal(t, catalog.Metadata{"X-Amz-Meta-Owner": "data"}, metadata.UserMetadata())
+	require.Nil(t, catalog.Metadata(nil).UserMetadata())
+
+	require.NoError(t, catalog.ValidateUserMetadata(map[string]string{"X-Amz-Meta-Owner": "data", "::lakefs::client-mtime": "1"}))
+	require.ErrorIs(t, catalog.ValidateUserMetadata(metadata), graveler.ErrInvalidValue)
+	require.ErrorIs(t, catalog.ValidateUserMetadata(map[string]string{catalog.ObjectTagMetadataPrefix + "env": "dev"}), graveler.ErrInvalidValue)
+}
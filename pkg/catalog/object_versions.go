@@ -0,0 +1,271 @@
+package catalog
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// UncommittedVersionID is the version ID of the uncommitted changes of an object on a branch
+const UncommittedVersionID = "uncommitted"
+
+// ObjectVersion is a version of an object, the commit in which the object changed, or a common prefix of objects
+type ObjectVersion struct {
+	Path string
+	// VersionID is the ID of the commit in which the object changed, or UncommittedVersionID
+	VersionID string
+	// IsLatest is set on the version of the object on the listed reference
+	IsLatest bool
+	// Entry is the object of the version, nil in case the version deleted the object
+	Entry *DBEntry
+	// CreationDate is the creation date of the version commit
+	CreationDate time.Time
+	CommonLevel  bool
+}
+
+type ListObjectVersionsParams struct {
+	Prefix    string
+	Delimiter string
+	// After lists the objects following this path, or the versions of this path following AfterVersionID in case it
+	// is set
+	After          string
+	AfterVersionID string
+	// Limit is the number of versions and common prefixes listed
+	Limit int
+}
+
+// ListObjectVersions lists the versions of the objects under a prefix of a reference, sorted by path and newest first.
+// The versions of an object are the commits of the first-parent history of the reference in which it changed,
+// including the commits which deleted it, and its uncommitted changes in case the reference is a branch.
+// The history is walked once, diffing every commit with its parent, so listing costs the size of the changes of the
+// history rather than a lookup per object and commit.
+func (c *Catalog) ListObjectVersions(ctx context.Context, repositoryID string, reference string, params ListObjectVersionsParams) ([]ObjectVersion, bool, error) {
+	ref := graveler.Ref(reference)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: ref, Fn: graveler.ValidateRef},
+		{Name: "prefix", Value: Path(params.Prefix), Fn: ValidatePathOptional},
+		{Name: "delimiter", Value: Path(params.Delimiter), Fn: ValidatePathOptional},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	resolvedRef, err := c.Store.Dereference(ctx, repository, ref)
+	if err != nil {
+		return nil, false, err
+	}
+
+	collector := newObjectVersionsCollector(params)
+	if resolvedRef.Type == graveler.ReferenceTypeBranch && resolvedRef.ResolvedBranchModifier == graveler.ResolvedBranchModifierNone {
+		it, err := c.Store.DiffUncommitted(ctx, repository, resolvedRef.BranchID)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := collector.collectDiff(it, UncommittedVersionID, time.Now()); err != nil {
+			return nil, false, err
+		}
+	}
+
+	commits, err := c.Store.Log(ctx, repository, resolvedRef.CommitID, true, nil, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer commits.Close()
+	for commits.Next() {
+		commit := commits.Value()
+		if len(commit.Parents) == 0 {
+			it, err := c.Store.List(ctx, repository, graveler.Ref(commit.CommitID), ListEntriesLimitMax)
+			if err != nil {
+				return nil, false, err
+			}
+			err = collector.collectValues(it, commit.CommitID.String(), commit.CreationDate)
+			if err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		it, err := c.Store.Diff(ctx, repository, graveler.Ref(commit.Parents[0]), graveler.Ref(commit.CommitID))
+		if err != nil {
+			return nil, false, err
+		}
+		if err := collector.collectDiff(it, commit.CommitID.String(), commit.CreationDate); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := commits.Err(); err != nil {
+		return nil, false, err
+	}
+	versions, hasMore := collector.result()
+	return versions, hasMore, nil
+}
+
+// objectVersionsCollector collects the versions of the first objects and common prefixes of a listing, which are all
+// that is needed to fill it as each of them adds at least one version to the listing
+type objectVersionsCollector struct {
+	params ListObjectVersionsParams
+	limit  int
+	// capacity is the number of objects and common prefixes collected
+	capacity int
+	// paths are the paths of the collected objects and common prefixes, sorted
+	paths    []string
+	versions map[string][]ObjectVersion
+}
+
+func newObjectVersionsCollector(params ListObjectVersionsParams) *objectVersionsCollector {
+	limit := params.Limit
+	if limit < 0 || limit > ListEntriesLimitMax {
+		limit = ListEntriesLimitMax
+	}
+	capacity := limit + 1
+	if params.AfterVersionID != "" {
+		// all the remaining versions of the After object may already be listed
+		capacity++
+	}
+	return &objectVersionsCollector{
+		params:   params,
+		limit:    limit,
+		capacity: capacity,
+		versions: make(map[string][]ObjectVersion),
+	}
+}
+
+func (v *objectVersionsCollector) start() graveler.Key {
+	if v.params.After > v.params.Prefix {
+		return graveler.Key(v.params.After)
+	}
+	return graveler.Key(v.params.Prefix)
+}
+
+// collectDiff adds the versions of the objects changed by the diff
+func (v *objectVersionsCollector) collectDiff(it graveler.DiffIterator, versionID string, creationDate time.Time) error {
+	defer it.Close()
+	it.SeekGE(v.start())
+	for it.Next() {
+		diff := it.Value()
+		var entry *Entry
+		if diff.Type != graveler.DiffTypeRemoved {
+			var err error
+			entry, err = ValueToEntry(diff.Value)
+			if err != nil {
+				return err
+			}
+		}
+		seekTo, done := v.add(string(diff.Key), entry, versionID, creationDate)
+		if done {
+			break
+		}
+		if seekTo != "" {
+			it.SeekGE(graveler.Key(seekTo))
+		}
+	}
+	return it.Err()
+}
+
+// collectValues adds the versions of the objects added by a commit with no parents
+func (v *objectVersionsCollector) collectValues(it graveler.ValueIterator, versionID string, creationDate time.Time) error {
+	defer it.Close()
+	it.SeekGE(v.start())
+	for it.Next() {
+		value := it.Value()
+		entry, err := ValueToEntry(value.Value)
+		if err != nil {
+			return err
+		}
+		seekTo, done := v.add(string(value.Key), entry, versionID, creationDate)
+		if done {
+			break
+		}
+		if seekTo != "" {
+			it.SeekGE(graveler.Key(seekTo))
+		}
+	}
+	return it.Err()
+}
+
+// add adds a version of the object at p. It returns the path to seek to in case the following paths belong to the
+// same common prefix, and done once the following paths are not part of the listing.
+func (v *objectVersionsCollector) add(p string, entry *Entry, versionID string, creationDate time.Time) (string, bool) {
+	if !strings.HasPrefix(p, v.params.Prefix) {
+		return "", true
+	}
+	listedPath := p
+	commonLevel := false
+	if v.params.Delimiter != "" {
+		if i := strings.Index(p[len(v.params.Prefix):], v.params.Delimiter); i >= 0 {
+			listedPath = p[:len(v.params.Prefix)+i+len(v.params.Delimiter)]
+			commonLevel = true
+		}
+	}
+	seekTo := ""
+	if commonLevel {
+		seekTo = string(graveler.UpperBoundForPrefix([]byte(listedPath)))
+	}
+	if listedPath < v.params.After || listedPath == v.params.After && (commonLevel || v.params.AfterVersionID == "") {
+		return seekTo, false
+	}
+	if len(v.paths) >= v.capacity && listedPath > v.paths[len(v.paths)-1] {
+		return "", true
+	}
+
+	i, found := slices.BinarySearch(v.paths, listedPath)
+	if !found {
+		v.paths = slices.Insert(v.paths, i, listedPath)
+		if len(v.paths) > v.capacity {
+			delete(v.versions, v.paths[len(v.paths)-1])
+			v.paths = v.paths[:len(v.paths)-1]
+		}
+	}
+	if commonLevel {
+		return seekTo, false
+	}
+	version := ObjectVersion{
+		Path:         p,
+		VersionID:    versionID,
+		CreationDate: creationDate,
+	}
+	if entry != nil {
+		dbEntry := newCatalogEntryFromEntry(false, p, entry)
+		version.Entry = &dbEntry
+	}
+	v.versions[p] = append(v.versions[p], version)
+	return "", false
+}
+
+// result returns the collected versions and common prefixes, up to the limit, and whether there are more
+func (v *objectVersionsCollector) result() ([]ObjectVersion, bool) {
+	results := make([]ObjectVersion, 0, v.limit+1)
+	for _, p := range v.paths {
+		versions, ok := v.versions[p]
+		if !ok {
+			results = append(results, ObjectVersion{Path: p, CommonLevel: true})
+			continue
+		}
+		// versions are collected newest first
+		versions[0].IsLatest = true
+		if p == v.params.After {
+			i := slices.IndexFunc(versions, func(version ObjectVersion) bool {
+				return version.VersionID == v.params.AfterVersionID
+			})
+			if i < 0 {
+				// an unknown version marker lists none of the versions of the object
+				i = len(versions) - 1
+			}
+			versions = versions[i+1:]
+		}
+		results = append(results, versions...)
+		if len(results) > v.limit {
+			break
+		}
+	}
+	if len(results) > v.limit {
+		return results[:v.limit], true
+	}
+	return results, false
+}
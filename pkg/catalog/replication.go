@@ -0,0 +1,369 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	replicationSettingKey = "replication"
+	replicationStatusPath = "replication"
+	// replicationStatusAttempts bounds the attempts to update a replication status changed concurrently
+	replicationStatusAttempts = 5
+)
+
+// Lag returns the time passed since the creation of the oldest commit not replicated yet, zero in case all commits
+// were replicated
+func (x *ReplicationStatus) Lag(now time.Time) time.Duration {
+	var oldest time.Time
+	for _, p := range x.GetPending() {
+		if t := p.GetCreationDate().AsTime(); oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	if oldest.IsZero() || now.Before(oldest) {
+		return 0
+	}
+	return now.Sub(oldest)
+}
+
+// GetReplicationSettings returns the replication settings of the repository, graveler.ErrNotFound in case replication
+// is not configured
+func (c *Catalog) GetReplicationSettings(ctx context.Context, repositoryID string) (*ReplicationSettings, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	settings := &ReplicationSettings{}
+	if _, err := c.settingManager.GetLatest(ctx, repository, replicationSettingKey, settings); err != nil {
+		return nil, err
+	}
+	if settings.StorageNamespace == "" {
+		return nil, graveler.ErrNotFound
+	}
+	return settings, nil
+}
+
+func (c *Catalog) SetReplicationSettings(ctx context.Context, repositoryID string, settings *ReplicationSettings) error {
+	if settings.StorageNamespace == "" {
+		return fmt.Errorf("storage namespace: %w", graveler.ErrInvalidValue)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if settings.StorageId == repository.StorageID.String() && settings.StorageNamespace == repository.StorageNamespace.String() {
+		return fmt.Errorf("storage namespace is the repository storage namespace: %w", graveler.ErrInvalidValue)
+	}
+	if repository.ReadOnly {
+		return graveler.ErrReadOnlyRepository
+	}
+	return c.settingManager.Save(ctx, repository, replicationSettingKey, settings, nil)
+}
+
+func (c *Catalog) DeleteReplicationSettings(ctx context.Context, repositoryID string) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if repository.ReadOnly {
+		return graveler.ErrReadOnlyRepository
+	}
+	return c.settingManager.Save(ctx, repository, replicationSettingKey, &ReplicationSettings{}, nil)
+}
+
+// GetReplicationStatus returns the replication status of the repository, graveler.ErrNotFound in case replication is
+// not configured
+func (c *Catalog) GetReplicationStatus(ctx context.Context, repositoryID string) (*ReplicationStatus, error) {
+	if _, err := c.GetReplicationSettings(ctx, repositoryID); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	status := &ReplicationStatus{}
+	_, err = kv.GetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(replicationStatusPath), status)
+	if err != nil && !errors.Is(err, kv.ErrNotFound) {
+		return nil, err
+	}
+	return status, nil
+}
+
+// replicateCommits queues the commits for replication in case replication of the repository is configured. Queued
+// commits are replicated right away on synchronous replication, and in the background otherwise. Failing to
+// replicate does not fail the operation creating the commits, the commits stay queued and are retried by the next
+// replication. Only the data of commits is replicated, see replicateCommit: commit, branch and tag metadata are not.
+func (c *Catalog) replicateCommits(ctx context.Context, repository *graveler.RepositoryRecord, commitIDs ...graveler.CommitID) {
+	log := c.log(ctx).WithField("repository", repository.RepositoryID)
+	settings := &ReplicationSettings{}
+	err := c.settingManager.Get(ctx, repository, replicationSettingKey, settings)
+	if errors.Is(err, graveler.ErrNotFound) || err == nil && settings.StorageNamespace == "" {
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to get replication settings")
+		return
+	}
+
+	pending := make([]*ReplicationPendingCommit, 0, len(commitIDs))
+	for _, commitID := range commitIDs {
+		commit, err := c.Store.GetCommit(ctx, repository, commitID)
+		if err != nil {
+			log.WithError(err).WithField("commit_id", commitID).Error("Failed to queue commit for replication")
+			return
+		}
+		pending = append(pending, &ReplicationPendingCommit{
+			CommitId:     commitID.String(),
+			CreationDate: timestamppb.New(commit.CreationDate),
+		})
+	}
+	err = c.updateReplicationStatus(ctx, repository, func(status *ReplicationStatus) {
+		for _, p := range pending {
+			if !replicationPending(status, p.CommitId) {
+				status.Pending = append(status.Pending, p)
+			}
+		}
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to queue commits for replication")
+		return
+	}
+
+	lock := c.replicationLock(repository.RepositoryID)
+	if settings.Synchronous {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+		if err := c.replicate(ctx, repository, settings); err != nil {
+			log.WithError(err).Error("Replication failed")
+		}
+		return
+	}
+	if !lock.queued.CompareAndSwap(false, true) {
+		// the queued replication did not start yet, and replicates the commits queued now too
+		return
+	}
+	// use background context, replication continues after the request is done
+	bgCtx := logging.AddFields(context.Background(), logging.Fields{"repository": repository.RepositoryID})
+	c.workPool.Submit(func() {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+		lock.queued.Store(false)
+		if err := c.replicate(bgCtx, repository, settings); err != nil {
+			c.log(bgCtx).WithError(err).Error("Replication failed")
+		}
+	})
+}
+
+// replicationLock serializes the replications of a repository. At most one background replication waits for the
+// running one, replicating all the commits queued until it starts.
+type replicationLock struct {
+	mu     sync.Mutex
+	queued atomic.Bool
+}
+
+func (c *Catalog) replicationLock(repositoryID graveler.RepositoryID) *replicationLock {
+	lock, _ := c.replicationLocks.LoadOrStore(repositoryID, &replicationLock{})
+	return lock.(*replicationLock)
+}
+
+func replicationPending(status *ReplicationStatus, commitID string) bool {
+	for _, p := range status.Pending {
+		if p.CommitId == commitID {
+			return true
+		}
+	}
+	return false
+}
+
+// updateReplicationStatus applies fn to the replication status of the repository, retrying on concurrent updates
+func (c *Catalog) updateReplicationStatus(ctx context.Context, repository *graveler.RepositoryRecord, fn func(status *ReplicationStatus)) error {
+	partition := graveler.RepoPartition(repository)
+	key := []byte(replicationStatusPath)
+	for i := 0; i < replicationStatusAttempts; i++ {
+		status := &ReplicationStatus{}
+		predicate, err := kv.GetMsg(ctx, c.KVStore, partition, key, status)
+		if err != nil && !errors.Is(err, kv.ErrNotFound) {
+			return err
+		}
+		fn(status)
+		status.UpdatedAt = timestamppb.Now()
+		err = kv.SetMsgIf(ctx, c.KVStore, partition, key, status, predicate)
+		if !errors.Is(err, kv.ErrPredicateFailed) {
+			return err
+		}
+	}
+	return fmt.Errorf("update replication status: %w", kv.ErrPredicateFailed)
+}
+
+// replicate replicates the queued commits of the repository, and removes them from the queue. It stops on the first
+// commit failing to replicate, recording the error in the replication status.
+func (c *Catalog) replicate(ctx context.Context, repository *graveler.RepositoryRecord, settings *ReplicationSettings) error {
+	status := &ReplicationStatus{}
+	_, err := kv.GetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(replicationStatusPath), status)
+	if err != nil {
+		if errors.Is(err, kv.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	for _, pending := range status.Pending {
+		var objects, ranges int64
+		err := c.replicateCommit(ctx, repository, settings, graveler.CommitID(pending.CommitId), &objects, &ranges)
+		updateErr := c.updateReplicationStatus(ctx, repository, func(status *ReplicationStatus) {
+			status.ReplicatedObjects += objects
+			status.ReplicatedRanges += ranges
+			if err != nil {
+				status.Error = fmt.Sprintf("replicate commit %s: %s", pending.CommitId, err)
+				return
+			}
+			status.Error = ""
+			for i, p := range status.Pending {
+				if p.CommitId == pending.CommitId {
+					status.Pending = append(status.Pending[:i], status.Pending[i+1:]...)
+					break
+				}
+			}
+			if status.LastCommitCreationDate == nil || !pending.CreationDate.AsTime().Before(status.LastCommitCreationDate.AsTime()) {
+				status.LastCommitId = pending.CommitId
+				status.LastCommitCreationDate = pending.CreationDate
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("replicate commit %s: %w", pending.CommitId, err)
+		}
+		if updateErr != nil {
+			return updateErr
+		}
+	}
+	return nil
+}
+
+// replicateCommit copies the metarange of the commit, its ranges and the objects of its entries to the secondary
+// storage namespace. Ranges and metaranges are identified by their content, so a range already found in the secondary
+// storage namespace was replicated together with all of its objects, and is skipped. Objects are copied before the
+// ranges pointing to them, and ranges before their metarange, keeping the secondary storage namespace consistent in
+// case of a failure. Objects outside the storage namespace of the repository are not replicated.
+func (c *Catalog) replicateCommit(ctx context.Context, repository *graveler.RepositoryRecord, settings *ReplicationSettings, commitID graveler.CommitID, objects, ranges *int64) error {
+	commit, err := c.Store.GetCommit(ctx, repository, commitID)
+	if err != nil {
+		return err
+	}
+	if commit.MetaRangeID == "" {
+		return nil
+	}
+	metaRangeAddress, err := c.Store.GetMetaRange(ctx, repository, commit.MetaRangeID)
+	if err != nil {
+		return err
+	}
+	found, err := c.BlockAdapter.Exists(ctx, replicaPointer(settings, string(metaRangeAddress)))
+	if err != nil || found {
+		return err
+	}
+
+	rangeInfos, err := c.Store.ListRanges(ctx, repository, commit.MetaRangeID)
+	if err != nil {
+		return err
+	}
+	for _, rangeInfo := range rangeInfos {
+		rangeAddress, err := c.Store.GetRange(ctx, repository, rangeInfo.ID)
+		if err != nil {
+			return err
+		}
+		found, err := c.BlockAdapter.Exists(ctx, replicaPointer(settings, string(rangeAddress)))
+		if err != nil {
+			return err
+		}
+		if found {
+			continue
+		}
+		if err := c.replicateRange(ctx, repository, settings, rangeInfo.ID, objects); err != nil {
+			return fmt.Errorf("range %s: %w", rangeInfo.ID, err)
+		}
+		if err := c.replicateObject(ctx, repository, settings, string(rangeAddress)); err != nil {
+			return fmt.Errorf("range %s: %w", rangeInfo.ID, err)
+		}
+		*ranges++
+	}
+	return c.replicateObject(ctx, repository, settings, string(metaRangeAddress))
+}
+
+// replicateRange copies the objects of the entries of the range
+func (c *Catalog) replicateRange(ctx context.Context, repository *graveler.RepositoryRecord, settings *ReplicationSettings, rangeID graveler.RangeID, objects *int64) error {
+	values, err := c.Store.ListRange(ctx, repository, rangeID)
+	if err != nil {
+		return err
+	}
+	it := NewValueToEntryIterator(values)
+	defer it.Close()
+	var skipped int
+	for it.Next() {
+		address, ok := relativeAddress(repository, it.Value().Entry)
+		if !ok {
+			skipped++
+			continue
+		}
+		c.BackgroundLimiter.Take()
+		err := c.replicateObject(ctx, repository, settings, address)
+		if errors.Is(err, block.ErrDataNotFound) {
+			// objects may be deleted by garbage collection in the meantime
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("object %s: %w", address, err)
+		}
+		*objects++
+	}
+	if skipped > 0 {
+		c.log(ctx).WithFields(logging.Fields{
+			"repository": repository.RepositoryID,
+			"range_id":   rangeID,
+			"skipped":    skipped,
+		}).Warn("Replication skipped objects outside the storage namespace")
+	}
+	return it.Err()
+}
+
+// replicateObject copies the object at the relative address to the secondary storage namespace. Objects are copied
+// by the blockstore when both storage namespaces are on the same blockstore, and streamed through lakeFS otherwise.
+func (c *Catalog) replicateObject(ctx context.Context, repository *graveler.RepositoryRecord, settings *ReplicationSettings, address string) error {
+	src := block.ObjectPointer{
+		StorageID:        repository.StorageID.String(),
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       address,
+		IdentifierType:   block.IdentifierTypeRelative,
+	}
+	dst := replicaPointer(settings, address)
+	if src.StorageID == dst.StorageID {
+		return c.BlockAdapter.Copy(ctx, src, dst)
+	}
+	props, err := c.BlockAdapter.GetProperties(ctx, src)
+	if err != nil {
+		return err
+	}
+	reader, err := c.BlockAdapter.Get(ctx, src, props.ContentLength)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	return c.BlockAdapter.Put(ctx, dst, props.ContentLength, reader, block.PutOpts{})
+}
+
+func replicaPointer(settings *ReplicationSettings, address string) block.ObjectPointer {
+	return block.ObjectPointer{
+		StorageID:        settings.StorageId,
+		StorageNamespace: settings.StorageNamespace,
+		Identifier:       address,
+		IdentifierType:   block.IdentifierTypeRelative,
+	}
+}
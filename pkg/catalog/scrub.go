@@ -0,0 +1,382 @@
+package catalog
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/compression"
+	"github.com/treeverse/lakefs/pkg/block/encryption"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"go.uber.org/ratelimit"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	ScrubTaskIDPrefix = "SC"
+
+	ScrubProblemMissing          = "missing"
+	ScrubProblemTruncated        = "truncated"
+	ScrubProblemCorrupted        = "corrupted"
+	ScrubProblemMissingMetaRange = "missing_metarange"
+	ScrubProblemMissingRange     = "missing_range"
+
+	// scrubMaxProblems limits the problems kept in the status of a scrub, the rest are only counted
+	scrubMaxProblems = 1000
+	// scrubListLimit is the number of entries listed at once while verifying objects
+	scrubListLimit = 1000
+	// scrubCheckpointInterval is the interval between updates of the progress of a running scrub
+	scrubCheckpointInterval = 30 * time.Second
+	// scrubStaleTimeout is the time without progress updates after which a scrub that is not done is considered
+	// interrupted, and can be resumed
+	scrubStaleTimeout = 5 * time.Minute
+	// scrubRateUnitSize is the size of data read from an object counted as one object by the rate limit
+	scrubRateUnitSize = 1024 * 1024
+)
+
+// md5ChecksumRegexp matches checksums holding the MD5 of the whole object. Other checksums, such as the ETag of
+// multipart uploads, cannot be verified by reading the object.
+var md5ChecksumRegexp = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
+// ScrubCheckpointFunc is called with the progress of a scrub, periodically and after each of its phases
+type ScrubCheckpointFunc func(ctx context.Context, status *ScrubStatus) error
+
+// ScrubSubmit starts a background scrub of the ref. The scrub verifies that the SSTs referenced by the commits of the
+// repository exist, and that the objects of the entries of the ref match their size and checksum. A failed or
+// interrupted scrub is resumed from its progress by passing its task ID as resumeTaskID. rateLimit limits the
+// objects verified per second, an object counts once per scrubRateUnitSize read from it. The background limit of the
+// catalog applies in case it is not positive.
+func (c *Catalog) ScrubSubmit(ctx context.Context, repositoryID, ref, resumeTaskID string, rateLimit int) (string, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	if ref == "" {
+		return "", fmt.Errorf("ref: %w", graveler.ErrInvalidValue)
+	}
+	taskStatus := &ScrubStatus{Ref: ref}
+	if resumeTaskID != "" {
+		taskStatus, err = c.scrubResumeStatus(ctx, repository, ref, resumeTaskID)
+		if err != nil {
+			return "", err
+		}
+	}
+	limiter := c.BackgroundLimiter
+	if rateLimit > 0 {
+		limiter = ratelimit.New(rateLimit)
+	}
+
+	taskID := NewTaskID(ScrubTaskIDPrefix)
+	checkpoint := func(ctx context.Context, status *ScrubStatus) error {
+		status.Task.UpdatedAt = timestamppb.Now()
+		return UpdateTaskStatus(ctx, c.KVStore, repository, taskID, status)
+	}
+	taskSteps := []taskStep{
+		{
+			Name: "verify ranges",
+			Func: func(ctx context.Context) error {
+				return c.scrubRanges(ctx, repository, taskStatus, limiter, checkpoint)
+			},
+		},
+		{
+			Name: "verify objects",
+			Func: func(ctx context.Context) error {
+				return c.scrubObjects(ctx, repository, taskStatus, limiter, checkpoint)
+			},
+		},
+	}
+	if err := c.runBackgroundTaskSteps(repository, taskID, taskSteps, taskStatus); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+func (c *Catalog) ScrubStatus(ctx context.Context, repositoryID string, id string) (*ScrubStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTaskID(ScrubTaskIDPrefix, id) {
+		return nil, graveler.ErrNotFound
+	}
+	var status ScrubStatus
+	err = GetTaskStatus(ctx, c.KVStore, repository, id, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// scrubResumeStatus returns the progress of a failed or interrupted scrub of the ref
+func (c *Catalog) scrubResumeStatus(ctx context.Context, repository *graveler.RepositoryRecord, ref, taskID string) (*ScrubStatus, error) {
+	status, err := c.ScrubStatus(ctx, repository.RepositoryID.String(), taskID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Ref != ref {
+		return nil, fmt.Errorf("scrub %s verified ref '%s': %w", taskID, status.Ref, graveler.ErrInvalidValue)
+	}
+	task := status.Task
+	switch {
+	case task.Done && task.Error == "":
+		return nil, fmt.Errorf("scrub %s completed: %w", taskID, graveler.ErrInvalidValue)
+	case !task.Done && time.Since(task.UpdatedAt.AsTime()) < scrubStaleTimeout:
+		return nil, fmt.Errorf("scrub %s is running: %w", taskID, graveler.ErrConflictFound)
+	}
+	status.Task = nil
+	return status, nil
+}
+
+// Scrub verifies the SSTs referenced by the commits of the repository and the objects of the ref of the status,
+// continuing from its progress. Problems found are added to the status, errors reaching the object store or reading
+// the metadata fail the scrub.
+func (c *Catalog) Scrub(ctx context.Context, repositoryID string, status *ScrubStatus, limiter ratelimit.Limiter, checkpoint ScrubCheckpointFunc) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if err := c.scrubRanges(ctx, repository, status, limiter, checkpoint); err != nil {
+		return err
+	}
+	return c.scrubObjects(ctx, repository, status, limiter, checkpoint)
+}
+
+// scrubRanges verifies that the metarange of every commit of the repository, and the ranges of the metarange, exist.
+// Ranges of a missing metarange cannot be listed and are not verified. Commits are verified in ID order, continuing
+// after the last commit verified. SSTs shared by commits verified before a resume are verified again.
+func (c *Catalog) scrubRanges(ctx context.Context, repository *graveler.RepositoryRecord, status *ScrubStatus, limiter ratelimit.Limiter, checkpoint ScrubCheckpointFunc) error {
+	if status.RangesVerified {
+		return nil
+	}
+	it, err := c.Store.ListCommits(ctx, repository)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	if status.LastCommitId != "" {
+		it.SeekGE(graveler.CommitID(status.LastCommitId))
+	}
+	lastCheckpoint := time.Now()
+	metaRanges := make(map[graveler.MetaRangeID]struct{})
+	ranges := make(map[graveler.RangeID]struct{})
+	for it.Next() {
+		commit := it.Value()
+		if commit.CommitID.String() == status.LastCommitId {
+			continue
+		}
+		if err := c.scrubCommitRanges(ctx, repository, commit.MetaRangeID, status, limiter, metaRanges, ranges); err != nil {
+			return err
+		}
+		status.LastCommitId = commit.CommitID.String()
+		if time.Since(lastCheckpoint) >= scrubCheckpointInterval {
+			if err := checkpoint(ctx, status); err != nil {
+				return err
+			}
+			lastCheckpoint = time.Now()
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	status.RangesVerified = true
+	return checkpoint(ctx, status)
+}
+
+// scrubCommitRanges verifies the metarange of a commit and its ranges, skipping the SSTs already verified
+func (c *Catalog) scrubCommitRanges(ctx context.Context, repository *graveler.RepositoryRecord, metaRangeID graveler.MetaRangeID, status *ScrubStatus, limiter ratelimit.Limiter, metaRanges map[graveler.MetaRangeID]struct{}, ranges map[graveler.RangeID]struct{}) error {
+	if metaRangeID == "" {
+		return nil
+	}
+	if _, ok := metaRanges[metaRangeID]; ok {
+		return nil
+	}
+	metaRanges[metaRangeID] = struct{}{}
+	address, err := c.Store.GetMetaRange(ctx, repository, metaRangeID)
+	if err != nil {
+		return err
+	}
+	found, err := c.scrubExists(ctx, repository, string(address), limiter)
+	if err != nil {
+		return fmt.Errorf("metarange %s: %w", metaRangeID, err)
+	}
+	status.VerifiedRanges++
+	if !found {
+		scrubAddProblem(status, &ScrubProblem{Kind: ScrubProblemMissingMetaRange, Path: metaRangeID.String(), PhysicalAddress: string(address)})
+		return nil
+	}
+
+	rangeInfos, err := c.Store.ListRanges(ctx, repository, metaRangeID)
+	if err != nil {
+		return fmt.Errorf("metarange %s: %w", metaRangeID, err)
+	}
+	for _, rangeInfo := range rangeInfos {
+		if _, ok := ranges[rangeInfo.ID]; ok {
+			continue
+		}
+		ranges[rangeInfo.ID] = struct{}{}
+		address, err := c.Store.GetRange(ctx, repository, rangeInfo.ID)
+		if err != nil {
+			return err
+		}
+		found, err := c.scrubExists(ctx, repository, string(address), limiter)
+		if err != nil {
+			return fmt.Errorf("range %s: %w", rangeInfo.ID, err)
+		}
+		status.VerifiedRanges++
+		if !found {
+			scrubAddProblem(status, &ScrubProblem{Kind: ScrubProblemMissingRange, Path: string(rangeInfo.ID), PhysicalAddress: string(address)})
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) scrubExists(ctx context.Context, repository *graveler.RepositoryRecord, address string, limiter ratelimit.Limiter) (bool, error) {
+	limiter.Take()
+	return c.BlockAdapter.Exists(ctx, block.ObjectPointer{
+		StorageID:        repository.StorageID.String(),
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       address,
+		IdentifierType:   block.IdentifierTypeRelative,
+	})
+}
+
+// scrubObjects verifies the objects of the entries of the ref in path order, continuing after the last path verified
+func (c *Catalog) scrubObjects(ctx context.Context, repository *graveler.RepositoryRecord, status *ScrubStatus, limiter ratelimit.Limiter, checkpoint ScrubCheckpointFunc) error {
+	log := c.log(ctx).WithFields(logging.Fields{"repository": repository.RepositoryID, "ref": status.Ref})
+	lastCheckpoint := time.Now()
+	for {
+		entries, hasMore, err := c.ListEntries(ctx, repository.RepositoryID.String(), status.Ref, "", status.LastPath, "", scrubListLimit)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			limiter.Take()
+			problem, err := c.scrubObject(ctx, repository, entry, limiter)
+			switch {
+			case errors.Is(err, block.ErrRestoreRequired):
+				log.WithField("path", entry.Path).Debug("Scrub skipped archived object")
+			case err != nil:
+				return fmt.Errorf("object %s: %w", entry.Path, err)
+			case problem != nil:
+				scrubAddProblem(status, problem)
+			}
+			status.LastPath = entry.Path
+			status.VerifiedObjects++
+			if time.Since(lastCheckpoint) >= scrubCheckpointInterval {
+				if err := checkpoint(ctx, status); err != nil {
+					return err
+				}
+				lastCheckpoint = time.Now()
+			}
+		}
+		if !hasMore {
+			break
+		}
+	}
+	return checkpoint(ctx, status)
+}
+
+// scrubObject reads the object of the entry, and returns a problem in case it is missing, cannot be decoded or does
+// not match the size or the checksum of the entry. It fails only in case the object store cannot be reached.
+func (c *Catalog) scrubObject(ctx context.Context, repository *graveler.RepositoryRecord, entry *DBEntry, limiter ratelimit.Limiter) (*ScrubProblem, error) {
+	problem := &ScrubProblem{Path: entry.Path, PhysicalAddress: entry.PhysicalAddress}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageID:        repository.StorageID.String(),
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       entry.PhysicalAddress,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Compression:      entry.Metadata.Compression(),
+	}, entry.Size)
+	switch {
+	case errors.Is(err, block.ErrDataNotFound):
+		problem.Kind = ScrubProblemMissing
+		return problem, nil
+	case scrubDecodeError(err):
+		problem.Kind = ScrubProblemCorrupted
+		problem.Details = err.Error()
+		return problem, nil
+	case err != nil:
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	hashingReader := block.NewHashingReader(&scrubRateReader{reader: reader, limiter: limiter}, block.HashFunctionMD5)
+	if _, err := io.Copy(io.Discard, hashingReader); err != nil {
+		if ctx.Err() != nil || scrubTransportError(err) {
+			return nil, err
+		}
+		problem.Kind = ScrubProblemCorrupted
+		problem.Details = err.Error()
+		return problem, nil
+	}
+
+	checksum := strings.Trim(entry.Checksum, `"`)
+	switch {
+	case hashingReader.CopiedSize < entry.Size:
+		problem.Kind = ScrubProblemTruncated
+		problem.Details = fmt.Sprintf("read %d bytes, expected %d", hashingReader.CopiedSize, entry.Size)
+	case hashingReader.CopiedSize > entry.Size:
+		problem.Kind = ScrubProblemCorrupted
+		problem.Details = fmt.Sprintf("read %d bytes, expected %d", hashingReader.CopiedSize, entry.Size)
+	case md5ChecksumRegexp.MatchString(checksum):
+		actual := hex.EncodeToString(hashingReader.Md5.Sum(nil))
+		if !strings.EqualFold(actual, checksum) {
+			problem.Kind = ScrubProblemCorrupted
+			problem.Details = fmt.Sprintf("checksum %s, expected %s", actual, checksum)
+		}
+	}
+	if problem.Kind == "" {
+		return nil, nil
+	}
+	return problem, nil
+}
+
+// scrubDecodeError reports whether err is the failure to decode the stored data of an object
+func scrubDecodeError(err error) bool {
+	return errors.Is(err, encryption.ErrDecrypt) ||
+		errors.Is(err, encryption.ErrInvalidFormat) ||
+		errors.Is(err, encryption.ErrSizeMismatch) ||
+		errors.Is(err, compression.ErrInvalidFormat)
+}
+
+// scrubTransportError reports whether err is the failure to reach the object store, rather than a problem with the
+// data of the object
+func scrubTransportError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// scrubRateReader takes the rate limit once per scrubRateUnitSize read beyond the first, the object itself takes
+// the first
+type scrubRateReader struct {
+	reader  io.Reader
+	limiter ratelimit.Limiter
+	read    int64
+}
+
+func (r *scrubRateReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	before := r.read / scrubRateUnitSize
+	r.read += int64(n)
+	for i := before; i < r.read/scrubRateUnitSize; i++ {
+		r.limiter.Take()
+	}
+	return n, err
+}
+
+func scrubAddProblem(status *ScrubStatus, problem *ScrubProblem) {
+	status.ProblemsCount++
+	if len(status.Problems) < scrubMaxProblems {
+		status.Problems = append(status.Problems, problem)
+	}
+}
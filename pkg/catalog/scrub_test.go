@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/encryption"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"go.uber.org/ratelimit"
+)
+
+// countingLimiter counts the times the rate limit is taken
+type countingLimiter struct {
+	taken int
+}
+
+func (l *countingLimiter) Take() time.Time {
+	l.taken++
+	return time.Now()
+}
+
+func TestCatalog_ScrubObjectUndecodable(t *testing.T) {
+	ctx := context.Background()
+	const storageNamespace = "mem://scrub"
+	repository := &graveler.RepositoryRecord{
+		RepositoryID: "scrub",
+		Repository:   &graveler.Repository{StorageNamespace: storageNamespace},
+	}
+	store := mem.New(ctx)
+	keys, err := encryption.NewStaticKeyProvider(map[string][]byte{"k": bytes.Repeat([]byte("k"), 32)}, "k")
+	require.NoError(t, err)
+	c := &Catalog{BlockAdapter: encryption.NewAdapter(store, keys)}
+
+	const data = "scrubbed data"
+	obj := block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	require.NoError(t, c.BlockAdapter.Put(ctx, obj, int64(len(data)), strings.NewReader(data), block.PutOpts{}))
+	entry := &DBEntry{Path: "obj", PhysicalAddress: "obj", AddressType: AddressTypeRelative, Size: int64(len(data))}
+
+	problem, err := c.scrubObject(ctx, repository, entry, ratelimit.NewUnlimited())
+	require.NoError(t, err)
+	require.Nil(t, problem)
+
+	// overwrite the stored data, which no longer decrypts
+	stored, err := store.Get(ctx, obj, 0)
+	require.NoError(t, err)
+	raw, err := io.ReadAll(stored)
+	require.NoError(t, err)
+	raw[len(raw)-1] ^= 0xff
+	require.NoError(t, store.Put(ctx, obj, int64(len(raw)), bytes.NewReader(raw), block.PutOpts{}))
+
+	problem, err = c.scrubObject(ctx, repository, entry, ratelimit.NewUnlimited())
+	require.NoError(t, err)
+	require.NotNil(t, problem)
+	require.Equal(t, ScrubProblemCorrupted, problem.Kind)
+}
+
+func TestScrubRateReader(t *testing.T) {
+	limiter := &countingLimiter{}
+	reader := &scrubRateReader{reader: bytes.NewReader(make([]byte, 3*scrubRateUnitSize+1)), limiter: limiter}
+	n, err := io.Copy(io.Discard, reader)
+	require.NoError(t, err)
+	require.Equal(t, int64(3*scrubRateUnitSize+1), n)
+	// the first unit is taken by the object
+	require.Equal(t, 3, limiter.taken)
+}
@@ -0,0 +1,217 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const sharedAddressesPartition = "shared-addresses"
+
+// sharedAddressPath is the key of an address shared with a path of a repository. Keys of the addresses of a storage
+// namespace share the prefix sharedAddressPrefix of the storage namespace.
+func sharedAddressPath(storageID, address, repositoryID, path string) string {
+	return kv.FormatPath(storageID, address, repositoryID, path)
+}
+
+func sharedAddressPrefix(storageID, normalizedStorageNamespace string) string {
+	return kv.FormatPath(storageID, normalizedStorageNamespace)
+}
+
+// shareEntryAddress points the entry copied from srcRepo to destRepo at the object of the source entry.
+// Entries copied within a repository keep their address, which garbage collection of the repository keeps as long as
+// any of them is referenced. Entries copied across repositories point at the full address of the object, and the
+// address is recorded as shared with destRepo: garbage collection of destRepo ignores objects outside its storage
+// namespace, and garbage collection of the repository owning the object keeps shared addresses until destRepo is
+// deleted or no longer references the address, see sharedAddressChecker.
+func (c *Catalog) shareEntryAddress(ctx context.Context, srcRepo, destRepo *graveler.RepositoryRecord, entry *DBEntry) error {
+	if srcRepo.RepositoryID == destRepo.RepositoryID {
+		return nil
+	}
+	if srcRepo.StorageID != destRepo.StorageID {
+		return fmt.Errorf("shallow copy from storage '%s' to '%s': %w", srcRepo.StorageID, destRepo.StorageID, graveler.ErrInvalidValue)
+	}
+	qk, err := c.BlockAdapter.ResolveNamespace(srcRepo.StorageID.String(), srcRepo.StorageNamespace.String(), entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if err != nil {
+		return err
+	}
+	address := qk.Format()
+	err = kv.SetMsg(ctx, c.KVStore, sharedAddressesPartition, []byte(sharedAddressPath(destRepo.StorageID.String(), address, destRepo.RepositoryID.String(), entry.Path)), &SharedAddressData{
+		Address:      address,
+		RepositoryId: destRepo.RepositoryID.String(),
+		InstanceUid:  destRepo.InstanceUID,
+		CreationDate: timestamppb.Now(),
+		Path:         entry.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("share address: %w", err)
+	}
+	entry.AddressType = AddressTypeFull
+	entry.PhysicalAddress = address
+	return nil
+}
+
+// sharedAddressReferrer holds what garbage collection needs of a repository objects are shared with
+type sharedAddressReferrer struct {
+	// repository is nil once the repository is deleted
+	repository *graveler.RepositoryRecord
+	branches   []graveler.BranchID
+	// retention is how long an address no branch references is kept for the commits of the repository, unless
+	// expires is false and it is kept as long as the repository exists
+	retention time.Duration
+	expires   bool
+}
+
+// sharedAddressChecker decides which shared addresses garbage collection of the repository owning the objects keeps.
+// An address is kept while the entry it was shared with is referenced by a branch of the referencing repository,
+// staged or committed. Once no branch references it, the address is kept for the retention of the referencing
+// repository, which keeps its commits referencing the address for as long, and then released.
+type sharedAddressChecker struct {
+	store     Store
+	kvStore   kv.Store
+	owner     *graveler.RepositoryRecord
+	referrers map[string]*sharedAddressReferrer
+}
+
+func newSharedAddressChecker(store Store, kvStore kv.Store, owner *graveler.RepositoryRecord) *sharedAddressChecker {
+	return &sharedAddressChecker{
+		store:     store,
+		kvStore:   kvStore,
+		owner:     owner,
+		referrers: make(map[string]*sharedAddressReferrer),
+	}
+}
+
+// keep returns whether the address is kept. Released addresses, and addresses shared with deleted repositories, or
+// repositories being deleted, are removed.
+func (s *sharedAddressChecker) keep(ctx context.Context, key []byte, data *SharedAddressData) (bool, error) {
+	referrer, err := s.referrer(ctx, data)
+	if err != nil {
+		return false, err
+	}
+	if referrer.repository == nil {
+		return false, s.release(ctx, key)
+	}
+	referenced, err := s.referenced(ctx, referrer, data)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case referenced && data.UnreferencedSince != nil:
+		return true, s.update(ctx, key, nil)
+	case referenced:
+		return true, nil
+	case data.UnreferencedSince == nil:
+		return true, s.update(ctx, key, timestamppb.Now())
+	case referrer.expires && time.Since(data.UnreferencedSince.AsTime()) > referrer.retention:
+		return false, s.release(ctx, key)
+	default:
+		return true, nil
+	}
+}
+
+func (s *sharedAddressChecker) referrer(ctx context.Context, data *SharedAddressData) (*sharedAddressReferrer, error) {
+	if referrer, ok := s.referrers[data.RepositoryId+"/"+data.InstanceUid]; ok {
+		return referrer, nil
+	}
+	referrer := &sharedAddressReferrer{}
+	repository, err := s.store.GetRepository(ctx, graveler.RepositoryID(data.RepositoryId))
+	if err != nil && !errors.Is(err, graveler.ErrRepositoryNotFound) && !errors.Is(err, graveler.ErrRepositoryInDeletion) {
+		return nil, err
+	}
+	if err == nil && repository.InstanceUID == data.InstanceUid {
+		referrer.repository = repository
+		if referrer.branches, err = s.listBranches(ctx, repository); err != nil {
+			return nil, err
+		}
+		if referrer.retention, referrer.expires, err = s.retention(ctx, repository); err != nil {
+			return nil, err
+		}
+	}
+	s.referrers[data.RepositoryId+"/"+data.InstanceUid] = referrer
+	return referrer, nil
+}
+
+func (s *sharedAddressChecker) listBranches(ctx context.Context, repository *graveler.RepositoryRecord) ([]graveler.BranchID, error) {
+	it, err := s.store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var branches []graveler.BranchID
+	for it.Next() {
+		branches = append(branches, it.Value().BranchID)
+	}
+	return branches, it.Err()
+}
+
+// retention returns the longest retention of the garbage collection rules of the repository, or of the owning
+// repository if it has none. Without rules commits never expire, and neither do the addresses.
+func (s *sharedAddressChecker) retention(ctx context.Context, repository *graveler.RepositoryRecord) (time.Duration, bool, error) {
+	for _, r := range []*graveler.RepositoryRecord{repository, s.owner} {
+		rules, err := s.store.GetGarbageCollectionRules(ctx, r)
+		if errors.Is(err, graveler.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		days := rules.DefaultRetentionDays
+		for _, branchDays := range rules.BranchRetentionDays {
+			days = max(days, branchDays)
+		}
+		return time.Duration(days) * 24 * time.Hour, true, nil
+	}
+	return 0, false, nil
+}
+
+// referenced returns whether a branch of the referencing repository has the address at the path of the entry
+func (s *sharedAddressChecker) referenced(ctx context.Context, referrer *sharedAddressReferrer, data *SharedAddressData) (bool, error) {
+	for _, branchID := range referrer.branches {
+		value, err := s.store.Get(ctx, referrer.repository, graveler.Ref(branchID), graveler.Key(data.Path))
+		if errors.Is(err, graveler.ErrNotFound) || errors.Is(err, graveler.ErrBranchNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		entry, err := ValueToEntry(value)
+		if err != nil {
+			return false, err
+		}
+		if entry.Address == data.Address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// update sets the time the address was first found unreferenced, unless the address was shared again meanwhile
+func (s *sharedAddressChecker) update(ctx context.Context, key []byte, unreferencedSince *timestamppb.Timestamp) error {
+	var data SharedAddressData
+	predicate, err := kv.GetMsg(ctx, s.kvStore, sharedAddressesPartition, key, &data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data.UnreferencedSince = unreferencedSince
+	err = kv.SetMsgIf(ctx, s.kvStore, sharedAddressesPartition, key, &data, predicate)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil
+	}
+	return err
+}
+
+func (s *sharedAddressChecker) release(ctx context.Context, key []byte) error {
+	if err := s.kvStore.Delete(ctx, []byte(sharedAddressesPartition), key); err != nil && !errors.Is(err, kv.ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"reflect"
@@ -17,9 +18,10 @@ import (
 )
 
 var (
-	ErrBadConfiguration    = errors.New("bad configuration")
-	ErrBadDomainNames      = fmt.Errorf("%w: domain names are prefixes", ErrBadConfiguration)
-	ErrMissingRequiredKeys = fmt.Errorf("%w: missing required keys", ErrBadConfiguration)
+	ErrBadConfiguration      = errors.New("bad configuration")
+	ErrBadDomainNames        = fmt.Errorf("%w: domain names are prefixes", ErrBadConfiguration)
+	ErrMissingRequiredKeys   = fmt.Errorf("%w: missing required keys", ErrBadConfiguration)
+	ErrDuplicateBlockstoreID = fmt.Errorf("%w: duplicate blockstore ID", ErrBadConfiguration)
 )
 
 // UseLocalConfiguration set to true will add defaults that enable a lakeFS run
@@ -68,6 +70,78 @@ type S3AuthInfo struct {
 	}
 }
 
+// Blockstore configures a blockstore. The blockstore section configures the default blockstore, additional
+// blockstores are identified by their ID.
+type Blockstore struct {
+	ID                     string  `mapstructure:"id"`
+	Description            string  `mapstructure:"description"`
+	Type                   string  `mapstructure:"type" validate:"required"`
+	DefaultNamespacePrefix *string `mapstructure:"default_namespace_prefix"`
+	Local                  *struct {
+		Path                    string   `mapstructure:"path"`
+		ImportEnabled           bool     `mapstructure:"import_enabled"`
+		ImportHidden            bool     `mapstructure:"import_hidden"`
+		AllowedExternalPrefixes []string `mapstructure:"allowed_external_prefixes"`
+	} `mapstructure:"local"`
+	S3 *struct {
+		S3AuthInfo                    `mapstructure:",squash"`
+		Region                        string        `mapstructure:"region"`
+		Endpoint                      string        `mapstructure:"endpoint"`
+		MaxRetries                    int           `mapstructure:"max_retries"`
+		ForcePathStyle                bool          `mapstructure:"force_path_style"`
+		DiscoverBucketRegion          bool          `mapstructure:"discover_bucket_region"`
+		SkipVerifyCertificateTestOnly bool          `mapstructure:"skip_verify_certificate_test_only"`
+		ServerSideEncryption          string        `mapstructure:"server_side_encryption"`
+		ServerSideEncryptionKmsKeyID  string        `mapstructure:"server_side_encryption_kms_key_id"`
+		PreSignedExpiry               time.Duration `mapstructure:"pre_signed_expiry"`
+		DisablePreSigned              bool          `mapstructure:"disable_pre_signed"`
+		DisablePreSignedUI            bool          `mapstructure:"disable_pre_signed_ui"`
+		DisablePreSignedMultipart     bool          `mapstructure:"disable_pre_signed_multipart"`
+		ClientLogRetries              bool          `mapstructure:"client_log_retries"`
+		ClientLogRequest              bool          `mapstructure:"client_log_request"`
+		WebIdentity                   *struct {
+			SessionDuration     time.Duration `mapstructure:"session_duration"`
+			SessionExpiryWindow time.Duration `mapstructure:"session_expiry_window"`
+		} `mapstructure:"web_identity"`
+	} `mapstructure:"s3"`
+	Azure *struct {
+		TryTimeout       time.Duration `mapstructure:"try_timeout"`
+		StorageAccount   string        `mapstructure:"storage_account"`
+		StorageAccessKey string        `mapstructure:"storage_access_key"`
+		// Deprecated: Value ignored
+		AuthMethod         string        `mapstructure:"auth_method"`
+		PreSignedExpiry    time.Duration `mapstructure:"pre_signed_expiry"`
+		DisablePreSigned   bool          `mapstructure:"disable_pre_signed"`
+		DisablePreSignedUI bool          `mapstructure:"disable_pre_signed_ui"`
+		// Deprecated: Value ignored
+		ChinaCloudDeprecated bool   `mapstructure:"china_cloud"`
+		TestEndpointURL      string `mapstructure:"test_endpoint_url"`
+		// Domain by default points to Azure default domain blob.core.windows.net, can be set to other Azure domains (China/Gov)
+		Domain string `mapstructure:"domain"`
+	} `mapstructure:"azure"`
+	GS *struct {
+		S3Endpoint         string        `mapstructure:"s3_endpoint"`
+		CredentialsFile    string        `mapstructure:"credentials_file"`
+		CredentialsJSON    string        `mapstructure:"credentials_json"`
+		PreSignedExpiry    time.Duration `mapstructure:"pre_signed_expiry"`
+		DisablePreSigned   bool          `mapstructure:"disable_pre_signed"`
+		DisablePreSignedUI bool          `mapstructure:"disable_pre_signed_ui"`
+	} `mapstructure:"gs"`
+	Encryption struct {
+		Enabled bool   `mapstructure:"enabled"`
+		KeyID   string `mapstructure:"key_id"`
+		Keys    []struct {
+			ID  string       `mapstructure:"id"`
+			Key SecureString `mapstructure:"key"`
+		} `mapstructure:"keys"`
+		ChunkSize int `mapstructure:"chunk_size"`
+	} `mapstructure:"encryption"`
+	Compression struct {
+		Enabled   bool `mapstructure:"enabled"`
+		FrameSize int  `mapstructure:"frame_size"`
+	} `mapstructure:"compression"`
+}
+
 // Config - Output struct of configuration, used to validate.  If you read a key using a viper accessor
 // rather than accessing a field of this struct, that key will *not* be validated.  So don't
 // do that.
@@ -220,61 +294,10 @@ type Config struct {
 			LogoutURL          string   `mapstructure:"logout_url"`
 		} `mapstructure:"ui_config"`
 	} `mapstructure:"auth"`
-	Blockstore struct {
-		Type                   string  `mapstructure:"type" validate:"required"`
-		DefaultNamespacePrefix *string `mapstructure:"default_namespace_prefix"`
-		Local                  *struct {
-			Path                    string   `mapstructure:"path"`
-			ImportEnabled           bool     `mapstructure:"import_enabled"`
-			ImportHidden            bool     `mapstructure:"import_hidden"`
-			AllowedExternalPrefixes []string `mapstructure:"allowed_external_prefixes"`
-		} `mapstructure:"local"`
-		S3 *struct {
-			S3AuthInfo                    `mapstructure:",squash"`
-			Region                        string        `mapstructure:"region"`
-			Endpoint                      string        `mapstructure:"endpoint"`
-			MaxRetries                    int           `mapstructure:"max_retries"`
-			ForcePathStyle                bool          `mapstructure:"force_path_style"`
-			DiscoverBucketRegion          bool          `mapstructure:"discover_bucket_region"`
-			SkipVerifyCertificateTestOnly bool          `mapstructure:"skip_verify_certificate_test_only"`
-			ServerSideEncryption          string        `mapstructure:"server_side_encryption"`
-			ServerSideEncryptionKmsKeyID  string        `mapstructure:"server_side_encryption_kms_key_id"`
-			PreSignedExpiry               time.Duration `mapstructure:"pre_signed_expiry"`
-			DisablePreSigned              bool          `mapstructure:"disable_pre_signed"`
-			DisablePreSignedUI            bool          `mapstructure:"disable_pre_signed_ui"`
-			DisablePreSignedMultipart     bool          `mapstructure:"disable_pre_signed_multipart"`
-			ClientLogRetries              bool          `mapstructure:"client_log_retries"`
-			ClientLogRequest              bool          `mapstructure:"client_log_request"`
-			WebIdentity                   *struct {
-				SessionDuration     time.Duration `mapstructure:"session_duration"`
-				SessionExpiryWindow time.Duration `mapstructure:"session_expiry_window"`
-			} `mapstructure:"web_identity"`
-		} `mapstructure:"s3"`
-		Azure *struct {
-			TryTimeout       time.Duration `mapstructure:"try_timeout"`
-			StorageAccount   string        `mapstructure:"storage_account"`
-			StorageAccessKey string        `mapstructure:"storage_access_key"`
-			// Deprecated: Value ignored
-			AuthMethod         string        `mapstructure:"auth_method"`
-			PreSignedExpiry    time.Duration `mapstructure:"pre_signed_expiry"`
-			DisablePreSigned   bool          `mapstructure:"disable_pre_signed"`
-			DisablePreSignedUI bool          `mapstructure:"disable_pre_signed_ui"`
-			// Deprecated: Value ignored
-			ChinaCloudDeprecated bool   `mapstructure:"china_cloud"`
-			TestEndpointURL      string `mapstructure:"test_endpoint_url"`
-			// Domain by default points to Azure default domain blob.core.windows.net, can be set to other Azure domains (China/Gov)
-			Domain string `mapstructure:"domain"`
-		} `mapstructure:"azure"`
-		GS *struct {
-			S3Endpoint         string        `mapstructure:"s3_endpoint"`
-			CredentialsFile    string        `mapstructure:"credentials_file"`
-			CredentialsJSON    string        `mapstructure:"credentials_json"`
-			PreSignedExpiry    time.Duration `mapstructure:"pre_signed_expiry"`
-			DisablePreSigned   bool          `mapstructure:"disable_pre_signed"`
-			DisablePreSignedUI bool          `mapstructure:"disable_pre_signed_ui"`
-		} `mapstructure:"gs"`
-	} `mapstructure:"blockstore"`
-	Committed struct {
+	Blockstore Blockstore `mapstructure:"blockstore"`
+	// Blockstores configures additional blockstores, each identified by a storage ID
+	Blockstores []Blockstore `mapstructure:"blockstores"`
+	Committed   struct {
 		LocalCache struct {
 			SizeBytes             int64   `mapstructure:"size_bytes"`
 			Dir                   string  `mapstructure:"dir"`
@@ -302,6 +325,7 @@ type Config struct {
 		EnsureReadableRootNamespace bool `mapstructure:"ensure_readable_root_namespace"`
 		BatchDBIOTransactionMarkers bool `mapstructure:"batch_dbio_transaction_markers"`
 		CompactionSensorThreshold   int  `mapstructure:"compaction_sensor_threshold"`
+		DedupUploads                bool `mapstructure:"dedup_uploads"`
 		RepositoryCache             struct {
 			Size   int           `mapstructure:"size"`
 			Expiry time.Duration `mapstructure:"expiry"`
@@ -315,6 +339,14 @@ type Config struct {
 		Background struct {
 			RateLimit int `mapstructure:"rate_limit"`
 		} `mapstructure:"background"`
+		Reflog struct {
+			// Retention is the age of branch reflog entries pruned when a branch head moves, 0 keeps them
+			Retention time.Duration `mapstructure:"retention"`
+		} `mapstructure:"reflog"`
+		CommitSigning struct {
+			PrivateKey  SecureString `mapstructure:"private_key"`
+			TrustedKeys Strings      `mapstructure:"trusted_keys"`
+		} `mapstructure:"commit_signing"`
 	} `mapstructure:"graveler"`
 	Gateways struct {
 		S3 struct {
@@ -322,6 +354,8 @@ type Config struct {
 			Region            string  `mapstructure:"region"`
 			FallbackURL       string  `mapstructure:"fallback_url"`
 			VerifyUnsupported bool    `mapstructure:"verify_unsupported"`
+			// MultipartUploadExpiry is the age of multipart uploads aborted in the background, 0 keeps them
+			MultipartUploadExpiry time.Duration `mapstructure:"multipart_upload_expiry"`
 		} `mapstructure:"s3"`
 	}
 	Stats struct {
@@ -435,94 +469,210 @@ func (c *Config) Validate() error {
 	if len(missingKeys) > 0 {
 		return fmt.Errorf("%w: %v", ErrMissingRequiredKeys, missingKeys)
 	}
-	return nil
+	return c.validateBlockstores()
 }
 
 func (c *Config) BlockstoreType() string {
-	return c.Blockstore.Type
+	return c.Blockstore.BlockstoreType()
 }
 
 func (c *Config) BlockstoreS3Params() (blockparams.S3, error) {
+	return c.Blockstore.BlockstoreS3Params()
+}
+
+func (c *Config) BlockstoreLocalParams() (blockparams.Local, error) {
+	return c.Blockstore.BlockstoreLocalParams()
+}
+
+func (c *Config) BlockstoreGSParams() (blockparams.GS, error) {
+	return c.Blockstore.BlockstoreGSParams()
+}
+
+func (c *Config) BlockstoreAzureParams() (blockparams.Azure, error) {
+	return c.Blockstore.BlockstoreAzureParams()
+}
+
+// StorageConfigs returns the configuration of all blockstores, the default blockstore first
+func (c *Config) StorageConfigs() []blockparams.StorageConfig {
+	configs := make([]blockparams.StorageConfig, 0, len(c.Blockstores)+1)
+	configs = append(configs, &c.Blockstore)
+	for i := range c.Blockstores {
+		configs = append(configs, &c.Blockstores[i])
+	}
+	return configs
+}
+
+// BlockstoreByID returns the configuration of the blockstore identified by id, the default blockstore for an empty id
+func (c *Config) BlockstoreByID(id string) (*Blockstore, bool) {
+	if id == "" || id == c.Blockstore.ID {
+		return &c.Blockstore, true
+	}
+	for i := range c.Blockstores {
+		if c.Blockstores[i].ID == id {
+			return &c.Blockstores[i], true
+		}
+	}
+	return nil, false
+}
+
+// validateBlockstores verifies that each additional blockstore has a type and a unique ID
+func (c *Config) validateBlockstores() error {
+	ids := map[string]struct{}{c.Blockstore.ID: {}}
+	for i, b := range c.Blockstores {
+		if b.ID == "" {
+			return fmt.Errorf("%w: blockstores[%d].id", ErrMissingRequiredKeys, i)
+		}
+		if b.Type == "" {
+			return fmt.Errorf("%w: blockstores[%d].type", ErrMissingRequiredKeys, i)
+		}
+		if !b.hasTypeSettings() {
+			return fmt.Errorf("%w: blockstores[%d].%s", ErrMissingRequiredKeys, i, b.Type)
+		}
+		if _, ok := ids[b.ID]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateBlockstoreID, b.ID)
+		}
+		ids[b.ID] = struct{}{}
+	}
+	return nil
+}
+
+// hasTypeSettings reports whether the settings section of the blockstore type is set. Defaults apply only to the
+// default blockstore, additional blockstores must set the section of their type.
+func (b *Blockstore) hasTypeSettings() bool {
+	switch b.Type {
+	case "local":
+		return b.Local != nil
+	case "s3":
+		return b.S3 != nil
+	case "gs":
+		return b.GS != nil
+	case "azure":
+		return b.Azure != nil
+	default:
+		return true
+	}
+}
+
+func (b *Blockstore) BlockstoreType() string {
+	return b.Type
+}
+
+func (b *Blockstore) BlockstoreID() string {
+	return b.ID
+}
+
+func (b *Blockstore) BlockstoreDescription() string {
+	return b.Description
+}
+
+func (b *Blockstore) BlockstoreEncryptionParams() (blockparams.Encryption, error) {
+	if !b.Encryption.Enabled {
+		return blockparams.Encryption{}, nil
+	}
+	keys := make(map[string][]byte, len(b.Encryption.Keys))
+	for _, k := range b.Encryption.Keys {
+		key, err := base64.StdEncoding.DecodeString(k.Key.SecureValue())
+		if err != nil {
+			return blockparams.Encryption{}, fmt.Errorf("decode encryption key '%s': %w", k.ID, err)
+		}
+		keys[k.ID] = key
+	}
+	return blockparams.Encryption{
+		Enabled:   true,
+		KeyID:     b.Encryption.KeyID,
+		Keys:      keys,
+		ChunkSize: b.Encryption.ChunkSize,
+	}, nil
+}
+
+func (b *Blockstore) BlockstoreCompressionParams() blockparams.Compression {
+	return blockparams.Compression{
+		Enabled:   b.Compression.Enabled,
+		FrameSize: b.Compression.FrameSize,
+	}
+}
+
+func (b *Blockstore) BlockstoreS3Params() (blockparams.S3, error) {
 	var webIdentity *blockparams.S3WebIdentity
-	if c.Blockstore.S3.WebIdentity != nil {
+	if b.S3.WebIdentity != nil {
 		webIdentity = &blockparams.S3WebIdentity{
-			SessionDuration:     c.Blockstore.S3.WebIdentity.SessionDuration,
-			SessionExpiryWindow: c.Blockstore.S3.WebIdentity.SessionExpiryWindow,
+			SessionDuration:     b.S3.WebIdentity.SessionDuration,
+			SessionExpiryWindow: b.S3.WebIdentity.SessionExpiryWindow,
 		}
 	}
 
 	var creds blockparams.S3Credentials
-	if c.Blockstore.S3.Credentials != nil {
-		creds.AccessKeyID = c.Blockstore.S3.Credentials.AccessKeyID.SecureValue()
-		creds.SecretAccessKey = c.Blockstore.S3.Credentials.SecretAccessKey.SecureValue()
-		creds.SessionToken = c.Blockstore.S3.Credentials.SessionToken.SecureValue()
+	if b.S3.Credentials != nil {
+		creds.AccessKeyID = b.S3.Credentials.AccessKeyID.SecureValue()
+		creds.SecretAccessKey = b.S3.Credentials.SecretAccessKey.SecureValue()
+		creds.SessionToken = b.S3.Credentials.SessionToken.SecureValue()
 	}
 
 	return blockparams.S3{
-		Region:                        c.Blockstore.S3.Region,
-		Profile:                       c.Blockstore.S3.Profile,
-		CredentialsFile:               c.Blockstore.S3.CredentialsFile,
+		Region:                        b.S3.Region,
+		Profile:                       b.S3.Profile,
+		CredentialsFile:               b.S3.CredentialsFile,
 		Credentials:                   creds,
-		MaxRetries:                    c.Blockstore.S3.MaxRetries,
-		Endpoint:                      c.Blockstore.S3.Endpoint,
-		ForcePathStyle:                c.Blockstore.S3.ForcePathStyle,
-		DiscoverBucketRegion:          c.Blockstore.S3.DiscoverBucketRegion,
-		SkipVerifyCertificateTestOnly: c.Blockstore.S3.SkipVerifyCertificateTestOnly,
-		ServerSideEncryption:          c.Blockstore.S3.ServerSideEncryption,
-		ServerSideEncryptionKmsKeyID:  c.Blockstore.S3.ServerSideEncryptionKmsKeyID,
-		PreSignedExpiry:               c.Blockstore.S3.PreSignedExpiry,
-		DisablePreSigned:              c.Blockstore.S3.DisablePreSigned,
-		DisablePreSignedUI:            c.Blockstore.S3.DisablePreSignedUI,
-		DisablePreSignedMultipart:     c.Blockstore.S3.DisablePreSignedMultipart,
-		ClientLogRetries:              c.Blockstore.S3.ClientLogRetries,
-		ClientLogRequest:              c.Blockstore.S3.ClientLogRequest,
+		MaxRetries:                    b.S3.MaxRetries,
+		Endpoint:                      b.S3.Endpoint,
+		ForcePathStyle:                b.S3.ForcePathStyle,
+		DiscoverBucketRegion:          b.S3.DiscoverBucketRegion,
+		SkipVerifyCertificateTestOnly: b.S3.SkipVerifyCertificateTestOnly,
+		ServerSideEncryption:          b.S3.ServerSideEncryption,
+		ServerSideEncryptionKmsKeyID:  b.S3.ServerSideEncryptionKmsKeyID,
+		PreSignedExpiry:               b.S3.PreSignedExpiry,
+		DisablePreSigned:              b.S3.DisablePreSigned,
+		DisablePreSignedUI:            b.S3.DisablePreSignedUI,
+		DisablePreSignedMultipart:     b.S3.DisablePreSignedMultipart,
+		ClientLogRetries:              b.S3.ClientLogRetries,
+		ClientLogRequest:              b.S3.ClientLogRequest,
 		WebIdentity:                   webIdentity,
 	}, nil
 }
 
-func (c *Config) BlockstoreLocalParams() (blockparams.Local, error) {
-	localPath := c.Blockstore.Local.Path
+func (b *Blockstore) BlockstoreLocalParams() (blockparams.Local, error) {
+	localPath := b.Local.Path
 	path, err := homedir.Expand(localPath)
 	if err != nil {
 		return blockparams.Local{}, fmt.Errorf("parse blockstore location URI %s: %w", localPath, err)
 	}
 
-	params := blockparams.Local(*c.Blockstore.Local)
+	params := blockparams.Local(*b.Local)
 	params.Path = path
 	return params, nil
 }
 
-func (c *Config) BlockstoreGSParams() (blockparams.GS, error) {
-	credPath, err := homedir.Expand(c.Blockstore.GS.CredentialsFile)
+func (b *Blockstore) BlockstoreGSParams() (blockparams.GS, error) {
+	credPath, err := homedir.Expand(b.GS.CredentialsFile)
 	if err != nil {
-		return blockparams.GS{}, fmt.Errorf("parse GS credentials path '%s': %w", c.Blockstore.GS.CredentialsFile, err)
+		return blockparams.GS{}, fmt.Errorf("parse GS credentials path '%s': %w", b.GS.CredentialsFile, err)
 	}
 	return blockparams.GS{
 		CredentialsFile:    credPath,
-		CredentialsJSON:    c.Blockstore.GS.CredentialsJSON,
-		PreSignedExpiry:    c.Blockstore.GS.PreSignedExpiry,
-		DisablePreSigned:   c.Blockstore.GS.DisablePreSigned,
-		DisablePreSignedUI: c.Blockstore.GS.DisablePreSignedUI,
+		CredentialsJSON:    b.GS.CredentialsJSON,
+		PreSignedExpiry:    b.GS.PreSignedExpiry,
+		DisablePreSigned:   b.GS.DisablePreSigned,
+		DisablePreSignedUI: b.GS.DisablePreSignedUI,
 	}, nil
 }
 
-func (c *Config) BlockstoreAzureParams() (blockparams.Azure, error) {
-	if c.Blockstore.Azure.AuthMethod != "" {
+func (b *Blockstore) BlockstoreAzureParams() (blockparams.Azure, error) {
+	if b.Azure.AuthMethod != "" {
 		logging.ContextUnavailable().Warn("blockstore.azure.auth_method is deprecated. Value is no longer used.")
 	}
-	if c.Blockstore.Azure.ChinaCloudDeprecated {
+	if b.Azure.ChinaCloudDeprecated {
 		logging.ContextUnavailable().Warn("blockstore.azure.china_cloud is deprecated. Value is no longer used. Please pass Domain = 'blob.core.chinacloudapi.cn'")
-		c.Blockstore.Azure.Domain = "blob.core.chinacloudapi.cn"
+		b.Azure.Domain = "blob.core.chinacloudapi.cn"
 	}
 	return blockparams.Azure{
-		StorageAccount:     c.Blockstore.Azure.StorageAccount,
-		StorageAccessKey:   c.Blockstore.Azure.StorageAccessKey,
-		TryTimeout:         c.Blockstore.Azure.TryTimeout,
-		PreSignedExpiry:    c.Blockstore.Azure.PreSignedExpiry,
-		TestEndpointURL:    c.Blockstore.Azure.TestEndpointURL,
-		Domain:             c.Blockstore.Azure.Domain,
-		DisablePreSigned:   c.Blockstore.Azure.DisablePreSigned,
-		DisablePreSignedUI: c.Blockstore.Azure.DisablePreSignedUI,
+		StorageAccount:     b.Azure.StorageAccount,
+		StorageAccessKey:   b.Azure.StorageAccessKey,
+		TryTimeout:         b.Azure.TryTimeout,
+		PreSignedExpiry:    b.Azure.PreSignedExpiry,
+		TestEndpointURL:    b.Azure.TestEndpointURL,
+		Domain:             b.Azure.Domain,
+		DisablePreSigned:   b.Azure.DisablePreSigned,
+		DisablePreSignedUI: b.Azure.DisablePreSignedUI,
 	}, nil
 }
 
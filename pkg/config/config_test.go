@@ -12,6 +12,8 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/go-test/deep"
 	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/encryption"
 	"github.com/treeverse/lakefs/pkg/block/factory"
 	"github.com/treeverse/lakefs/pkg/block/gs"
 	"github.com/treeverse/lakefs/pkg/block/local"
@@ -106,6 +108,20 @@ func TestConfig_DomainNamePrefix(t *testing.T) {
 	}
 }
 
+func TestConfig_DuplicateBlockstoreID(t *testing.T) {
+	_, err := newConfigFromFile("testdata/duplicate_blockstore_id.yaml")
+	if !errors.Is(err, config.ErrDuplicateBlockstoreID) {
+		t.Errorf("got error %s not %s", err, config.ErrDuplicateBlockstoreID)
+	}
+}
+
+func TestConfig_MissingBlockstoreSettings(t *testing.T) {
+	_, err := newConfigFromFile("testdata/missing_blockstore_settings.yaml")
+	if !errors.Is(err, config.ErrMissingRequiredKeys) {
+		t.Errorf("got error %s not %s", err, config.ErrMissingRequiredKeys)
+	}
+}
+
 func TestConfig_BuildBlockAdapter(t *testing.T) {
 	ctx := context.Background()
 	t.Run("local block adapter", func(t *testing.T) {
@@ -138,6 +154,33 @@ func TestConfig_BuildBlockAdapter(t *testing.T) {
 			t.Fatalf("expected an gs block adapter, got something else instead")
 		}
 	})
+
+	t.Run("encrypted block adapter", func(t *testing.T) {
+		c, err := newConfigFromFile("testdata/valid_encrypted_blockstore_config.yaml")
+		testutil.Must(t, err)
+		adapter, err := factory.BuildStorageAdapter(ctx, nil, c.StorageConfigs())
+		testutil.Must(t, err)
+		if _, ok := adapter.(*encryption.Adapter); !ok {
+			t.Fatalf("expected an encryption block adapter, got something else instead")
+		}
+	})
+
+	t.Run("multiple block adapters", func(t *testing.T) {
+		c, err := newConfigFromFile("testdata/valid_multiple_blockstores_config.yaml")
+		testutil.Must(t, err)
+		adapter, err := factory.BuildStorageAdapter(ctx, nil, c.StorageConfigs())
+		testutil.Must(t, err)
+		storages := block.GetStorages(adapter)
+		if len(storages) != 2 {
+			t.Fatalf("expected 2 blockstores, got %d", len(storages))
+		}
+		if _, ok := storages[0].Adapter.(*local.Adapter); !ok || storages[0].ID != "" {
+			t.Errorf("expected the default blockstore to be a local block adapter, got %+v", storages[0])
+		}
+		if storages[1].ID != "archive" || storages[1].Description != "archive blockstore" || storages[1].Adapter.BlockstoreType() != block.BlockstoreTypeMem {
+			t.Errorf("expected the archive blockstore to be a mem block adapter, got %+v", storages[1])
+		}
+	})
 }
 
 func TestConfig_JSONLogger(t *testing.T) {
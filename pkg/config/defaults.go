@@ -91,6 +91,7 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("gateways.s3.domain_name", "s3.local.lakefs.io")
 	viper.SetDefault("gateways.s3.region", "us-east-1")
 	viper.SetDefault("gateways.s3.verify_unsupported", true)
+	viper.SetDefault("gateways.s3.multipart_upload_expiry", 7*24*time.Hour)
 
 	viper.SetDefault("blockstore.gs.s3_endpoint", "https://storage.googleapis.com")
 	viper.SetDefault("blockstore.gs.pre_signed_expiry", 15*time.Minute)
@@ -133,6 +134,7 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("graveler.commit_cache.size", 50_000)
 	viper.SetDefault("graveler.commit_cache.expiry", 10*time.Minute)
 	viper.SetDefault("graveler.commit_cache.jitter", 2*time.Second)
+	viper.SetDefault("graveler.reflog.retention", 90*24*time.Hour)
 
 	viper.SetDefault("ugc.prepare_interval", time.Minute)
 	viper.SetDefault("ugc.prepare_max_file_size", 20*1024*1024)
@@ -33,11 +33,17 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 			return
 		}
 		o := ctx.Value(ContextKeyOperation).(*operations.Operation)
-		authenticator := sig.ChainedAuthenticator(
-			sig.NewV4Authenticator(req),
-			sig.NewV2SigAuthenticator(req, o.FQDN),
-			sig.NewJavaV2SigAuthenticator(req, o.FQDN),
-		)
+		var authenticator sig.SigAuthenticator
+		if o.PostForm != nil {
+			// form uploads are authenticated by the signature of their POST policy
+			authenticator = sig.NewPostPolicyAuthenticator(o.PostForm)
+		} else {
+			authenticator = sig.ChainedAuthenticator(
+				sig.NewV4Authenticator(req),
+				sig.NewV2SigAuthenticator(req, o.FQDN),
+				sig.NewJavaV2SigAuthenticator(req, o.FQDN),
+			)
+		}
 		authContext, err := authenticator.Parse()
 		if err != nil {
 			o.Log(req).WithError(err).Warn("failed to parse signature")
@@ -78,10 +84,40 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 	})
 }
 
+// EnrichWithPostForm reads the fields of form uploads, browser-based uploads POSTed to a bucket, into the operation
+func EnrichWithPostForm(bareDomains []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		parts := ParseRequestParts(req.Host, req.URL.Path, bareDomains)
+		if parts.Repository == "" || parts.Ref != "" || parts.Path != "" || !sig.IsPostFormRequest(req) {
+			next.ServeHTTP(w, req)
+			return
+		}
+		o := req.Context().Value(ContextKeyOperation).(*operations.Operation)
+		form, err := sig.ParsePostForm(req)
+		if err != nil {
+			o.Log(req).WithError(err).Debug("could not parse form upload")
+			_ = o.EncodeError(w, req, err, getAPIErrOrDefault(err, gatewayerrors.ErrMalformedPOSTRequest))
+			return
+		}
+		if _, pth, _ := strings.Cut(form.Key(), path.Separator); pth == "" {
+			// the key of the uploaded object holds its branch and path
+			o.Log(req).WithField("key", form.Key()).Debug("form upload key has no path")
+			_ = o.EncodeError(w, req, nil, gatewayerrors.ErrMissingFields.ToAPIErr())
+			return
+		}
+		o.PostForm = form
+		next.ServeHTTP(w, req)
+	})
+}
+
 func EnrichWithParts(bareDomains []string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		parts := ParseRequestParts(req.Host, req.URL.Path, bareDomains)
+		if o := ctx.Value(ContextKeyOperation).(*operations.Operation); o.PostForm != nil {
+			// the key of a form upload, holding its ref and path, is a form field
+			parts.Ref, parts.Path, _ = strings.Cut(o.PostForm.Key(), path.Separator)
+		}
 		ctx = context.WithValue(ctx, ContextKeyRepositoryID, parts.Repository)
 		ctx = context.WithValue(ctx, ContextKeyRef, parts.Ref)
 		ctx = context.WithValue(ctx, ContextKeyPath, parts.Path)
@@ -0,0 +1,70 @@
+package multipart
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// ExpireUploads aborts the multipart uploads created before olderThan and stops tracking them, returning the number of
+// uploads expired. An upload is no longer tracked even when its abort fails, as it can no longer be completed through
+// lakeFS. Uploads are aborted in the storage namespace recorded with them, which is looked up from their repository for
+// uploads tracked before it was recorded, and uploads tracked without their repository are aborted by their physical
+// address.
+func ExpireUploads(ctx context.Context, tracker Tracker, c *catalog.Catalog, adapter block.Adapter, olderThan time.Time) (int, error) {
+	uploads, err := tracker.List(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	log := logging.FromContext(ctx)
+	repositories := make(map[string]*catalog.Repository)
+	expired := 0
+	for _, upload := range uploads {
+		if !upload.CreationDate.Before(olderThan) {
+			continue
+		}
+		uploadLog := log.WithFields(logging.Fields{
+			logging.UploadIDFieldKey:   upload.UploadID,
+			logging.RepositoryFieldKey: upload.Repository,
+			"path":                     upload.Path,
+		})
+		obj := block.ObjectPointer{
+			StorageID:        upload.StorageID,
+			StorageNamespace: upload.StorageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       upload.PhysicalAddress,
+		}
+		if obj.StorageNamespace == "" && upload.Repository != "" {
+			repository, ok := repositories[upload.Repository]
+			if !ok {
+				repository, err = c.GetRepository(ctx, upload.Repository)
+				if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+					return expired, err
+				}
+				repositories[upload.Repository] = repository
+			}
+			if repository != nil {
+				obj.StorageID = repository.StorageID
+				obj.StorageNamespace = repository.StorageNamespace
+			}
+		}
+		if obj.StorageNamespace == "" {
+			// no storage namespace to resolve the address in, abort by the physical address as is
+			obj.IdentifierType = block.IdentifierTypeUnknownDeprecated
+		}
+		if err := adapter.AbortMultiPartUpload(ctx, obj, upload.UploadID); err != nil {
+			uploadLog.WithError(err).Warn("Could not abort expired multipart upload")
+		}
+		if err := tracker.Delete(ctx, upload.UploadID); err != nil && !errors.Is(err, ErrMultipartUploadNotFound) {
+			return expired, err
+		}
+		uploadLog.Debug("Expired multipart upload")
+		expired++
+	}
+	return expired, nil
+}
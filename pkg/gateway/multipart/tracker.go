@@ -10,7 +10,11 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-const storePartitionKey = "multiparts"
+const (
+	storePartitionKey             = "multiparts"
+	partsStorePartitionKey        = "multipart-parts"
+	repositoriesStorePartitionKey = "multipart-repositories"
+)
 
 type Metadata map[string]string
 
@@ -27,12 +31,41 @@ type Upload struct {
 	Metadata Metadata `db:"metadata"`
 	// ContentType Original file's content-type
 	ContentType string `db:"content_type"`
+	// Repository the upload is to, empty for uploads tracked before it was recorded
+	Repository string `db:"repository"`
+	// Branch the upload is to
+	Branch string `db:"branch"`
+	// StorageID of the repository storage the upload is to
+	StorageID string `db:"storage_id"`
+	// StorageNamespace of the repository the upload is to, used to abort the upload after the repository is deleted
+	StorageNamespace string `db:"storage_namespace"`
+}
+
+// Part is a part uploaded to a multipart upload
+type Part struct {
+	// UploadID of the upload of the part
+	UploadID string `db:"upload_id"`
+	// PartNumber of the part within the upload
+	PartNumber int `db:"part_number"`
+	// ETag of the part as returned by the storage
+	ETag string `db:"etag"`
+	// Size of the part in bytes
+	Size int64 `db:"size"`
+	// LastModified time the part was uploaded
+	LastModified time.Time `db:"last_modified"`
 }
 
 type Tracker interface {
 	Create(ctx context.Context, multipart Upload) error
 	Get(ctx context.Context, uploadID string) (*Upload, error)
 	Delete(ctx context.Context, uploadID string) error
+	// List returns the uploads to repositoryID ordered by upload ID, all uploads in case it is empty
+	List(ctx context.Context, repositoryID string) ([]Upload, error)
+	// CreatePart records a part uploaded to an upload, replacing a previous upload of the same part number
+	CreatePart(ctx context.Context, part Part) error
+	// ListParts returns up to maxParts parts of uploadID ordered by part number, after partNumberMarker, and
+	// whether there are more parts
+	ListParts(ctx context.Context, uploadID string, partNumberMarker, maxParts int) ([]Part, bool, error)
 }
 
 type tracker struct {
@@ -42,6 +75,7 @@ type tracker struct {
 var (
 	ErrMultipartUploadNotFound = errors.New("multipart upload not found")
 	ErrInvalidUploadID         = errors.New("invalid upload id")
+	ErrInvalidPartNumber       = errors.New("invalid part number")
 )
 
 func NewTracker(store kv.Store) Tracker {
@@ -52,31 +86,86 @@ func NewTracker(store kv.Store) Tracker {
 
 func multipartFromProto(pb *UploadData) *Upload {
 	return &Upload{
-		UploadID:        pb.UploadId,
-		Path:            pb.Path,
-		CreationDate:    pb.CreationDate.AsTime(),
-		PhysicalAddress: pb.PhysicalAddress,
-		Metadata:        pb.Metadata,
-		ContentType:     pb.ContentType,
+		UploadID:         pb.UploadId,
+		Path:             pb.Path,
+		CreationDate:     pb.CreationDate.AsTime(),
+		PhysicalAddress:  pb.PhysicalAddress,
+		Metadata:         pb.Metadata,
+		ContentType:      pb.ContentType,
+		Repository:       pb.RepositoryId,
+		Branch:           pb.BranchId,
+		StorageID:        pb.StorageId,
+		StorageNamespace: pb.StorageNamespace,
 	}
 }
 
 func protoFromMultipart(m *Upload) *UploadData {
 	return &UploadData{
-		UploadId:        m.UploadID,
-		Path:            m.Path,
-		CreationDate:    timestamppb.New(m.CreationDate),
-		PhysicalAddress: m.PhysicalAddress,
-		Metadata:        m.Metadata,
-		ContentType:     m.ContentType,
+		UploadId:         m.UploadID,
+		Path:             m.Path,
+		CreationDate:     timestamppb.New(m.CreationDate),
+		PhysicalAddress:  m.PhysicalAddress,
+		Metadata:         m.Metadata,
+		ContentType:      m.ContentType,
+		RepositoryId:     m.Repository,
+		BranchId:         m.Branch,
+		StorageId:        m.StorageID,
+		StorageNamespace: m.StorageNamespace,
 	}
 }
 
+func partFromProto(pb *UploadPartData) *Part {
+	return &Part{
+		UploadID:     pb.UploadId,
+		PartNumber:   int(pb.PartNumber),
+		ETag:         pb.Etag,
+		Size:         pb.Size,
+		LastModified: pb.LastModified.AsTime(),
+	}
+}
+
+func protoFromPart(p *Part) *UploadPartData {
+	return &UploadPartData{
+		UploadId:     p.UploadID,
+		PartNumber:   int32(p.PartNumber),
+		Etag:         p.ETag,
+		Size:         p.Size,
+		LastModified: timestamppb.New(p.LastModified),
+	}
+}
+
+// partsPrefix is the prefix of the keys of the parts of uploadID
+func partsPrefix(uploadID string) string {
+	return uploadID + kv.PathDelimiter
+}
+
+// repositoryPrefix is the prefix of the index keys of the uploads to repositoryID
+func repositoryPrefix(repositoryID string) string {
+	return repositoryID + kv.PathDelimiter
+}
+
+// repositoryKey is the index key of uploadID under the uploads to repositoryID
+func repositoryKey(repositoryID, uploadID string) []byte {
+	return []byte(repositoryPrefix(repositoryID) + uploadID)
+}
+
+// partKey is the key of a part, the part number is padded to order the parts of an upload by part number
+func partKey(uploadID string, partNumber int) []byte {
+	return []byte(fmt.Sprintf("%s%05d", partsPrefix(uploadID), partNumber))
+}
+
 func (m *tracker) Create(ctx context.Context, multipart Upload) error {
 	if multipart.UploadID == "" {
 		return ErrInvalidUploadID
 	}
-	return kv.SetMsgIf(ctx, m.store, storePartitionKey, []byte(multipart.UploadID), protoFromMultipart(&multipart), nil)
+	err := kv.SetMsgIf(ctx, m.store, storePartitionKey, []byte(multipart.UploadID), protoFromMultipart(&multipart), nil)
+	if err != nil {
+		return err
+	}
+	if multipart.Repository == "" {
+		return nil
+	}
+	return kv.SetMsg(ctx, m.store, repositoriesStorePartitionKey, repositoryKey(multipart.Repository, multipart.UploadID), &kv.SecondaryIndex{PrimaryKey: []byte(multipart.UploadID)})
 }
 
 func (m *tracker) Get(ctx context.Context, uploadID string) (*Upload, error) {
@@ -96,12 +185,118 @@ func (m *tracker) Delete(ctx context.Context, uploadID string) error {
 		return ErrInvalidUploadID
 	}
 	key := []byte(uploadID)
-	if _, err := m.store.Get(ctx, []byte(storePartitionKey), key); err != nil {
+	data := &UploadData{}
+	if _, err := kv.GetMsg(ctx, m.store, storePartitionKey, key, data); err != nil {
 		if errors.Is(err, kv.ErrNotFound) {
 			return fmt.Errorf("%w uploadID=%s", ErrMultipartUploadNotFound, uploadID)
 		}
 		return err
 	}
 
-	return m.store.Delete(ctx, []byte(storePartitionKey), key)
+	if err := m.deleteParts(ctx, uploadID); err != nil {
+		return err
+	}
+	if err := m.store.Delete(ctx, []byte(storePartitionKey), key); err != nil {
+		return err
+	}
+	if data.RepositoryId == "" {
+		return nil
+	}
+	return m.store.Delete(ctx, []byte(repositoriesStorePartitionKey), repositoryKey(data.RepositoryId, uploadID))
+}
+
+func (m *tracker) deleteParts(ctx context.Context, uploadID string) error {
+	prefix := []byte(partsPrefix(uploadID))
+	it, err := kv.NewPrimaryIterator(ctx, m.store, (&UploadPartData{}).ProtoReflect().Type(), partsStorePartitionKey, prefix, kv.IteratorOptionsFrom(prefix))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		if err := m.store.Delete(ctx, []byte(partsStorePartitionKey), it.Entry().Key); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func (m *tracker) List(ctx context.Context, repositoryID string) ([]Upload, error) {
+	if repositoryID != "" {
+		return m.listRepository(ctx, repositoryID)
+	}
+	it := kv.NewPartitionIterator(ctx, m.store, (&UploadData{}).ProtoReflect().Type(), storePartitionKey, 0)
+	defer it.Close()
+	var uploads []Upload
+	for it.Next() {
+		uploads = append(uploads, *multipartFromProto(it.Entry().Value.(*UploadData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// listRepository returns the uploads to repositoryID, scanning only its index keys
+func (m *tracker) listRepository(ctx context.Context, repositoryID string) ([]Upload, error) {
+	prefix := []byte(repositoryPrefix(repositoryID))
+	it, err := kv.NewPrimaryIterator(ctx, m.store, (&kv.SecondaryIndex{}).ProtoReflect().Type(), repositoriesStorePartitionKey, prefix, kv.IteratorOptionsFrom(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var uploads []Upload
+	for it.Next() {
+		index := it.Entry().Value.(*kv.SecondaryIndex)
+		data := &UploadData{}
+		_, err := kv.GetMsg(ctx, m.store, storePartitionKey, index.PrimaryKey, data)
+		if errors.Is(err, kv.ErrNotFound) {
+			// the upload was deleted after its index key was read
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, *multipartFromProto(data))
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+func (m *tracker) CreatePart(ctx context.Context, part Part) error {
+	if part.UploadID == "" {
+		return ErrInvalidUploadID
+	}
+	if part.PartNumber <= 0 {
+		return ErrInvalidPartNumber
+	}
+	return kv.SetMsg(ctx, m.store, partsStorePartitionKey, partKey(part.UploadID, part.PartNumber), protoFromPart(&part))
+}
+
+func (m *tracker) ListParts(ctx context.Context, uploadID string, partNumberMarker, maxParts int) ([]Part, bool, error) {
+	if uploadID == "" {
+		return nil, false, ErrInvalidUploadID
+	}
+	prefix := []byte(partsPrefix(uploadID))
+	options := kv.IteratorOptionsFrom(prefix)
+	if partNumberMarker > 0 {
+		options = kv.IteratorOptionsAfter(partKey(uploadID, partNumberMarker))
+	}
+	it, err := kv.NewPrimaryIterator(ctx, m.store, (&UploadPartData{}).ProtoReflect().Type(), partsStorePartitionKey, prefix, options)
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var parts []Part
+	for it.Next() {
+		if len(parts) == maxParts {
+			return parts, true, nil
+		}
+		parts = append(parts, *partFromProto(it.Entry().Value.(*UploadPartData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return parts, false, nil
 }
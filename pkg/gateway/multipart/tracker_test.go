@@ -0,0 +1,159 @@
+package multipart_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvparams"
+	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+func openStore(t *testing.T) kv.Store {
+	t.Helper()
+	store, err := kv.Open(context.Background(), kvparams.Config{Type: "mem"})
+	testutil.MustDo(t, "open kv store", err)
+	t.Cleanup(store.Close)
+	return store
+}
+
+func TestTracker_List(t *testing.T) {
+	ctx := context.Background()
+	tracker := multipart.NewTracker(openStore(t))
+	now := time.Now().UTC().Truncate(time.Second)
+	uploads := []multipart.Upload{
+		{UploadID: "upload1", Path: "a", CreationDate: now, PhysicalAddress: "addr1", Repository: "repo1", Branch: "main"},
+		{UploadID: "upload2", Path: "b", CreationDate: now, PhysicalAddress: "addr2", Repository: "repo2", Branch: "main"},
+		{UploadID: "upload3", Path: "c", CreationDate: now, PhysicalAddress: "addr3", Repository: "repo1", Branch: "dev"},
+	}
+	for _, u := range uploads {
+		testutil.MustDo(t, "create upload", tracker.Create(ctx, u))
+	}
+
+	list, err := tracker.List(ctx, "repo1")
+	require.NoError(t, err)
+	require.Equal(t, []multipart.Upload{uploads[0], uploads[2]}, list)
+
+	list, err = tracker.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, list, len(uploads))
+
+	testutil.MustDo(t, "delete upload", tracker.Delete(ctx, "upload1"))
+	list, err = tracker.List(ctx, "repo1")
+	require.NoError(t, err)
+	require.Equal(t, []multipart.Upload{uploads[2]}, list)
+}
+
+func TestTracker_ListParts(t *testing.T) {
+	ctx := context.Background()
+	tracker := multipart.NewTracker(openStore(t))
+	now := time.Now().UTC().Truncate(time.Second)
+	testutil.MustDo(t, "create upload", tracker.Create(ctx, multipart.Upload{UploadID: "upload1", Path: "a", CreationDate: now}))
+
+	// parts are listed by part number, not by the order they were uploaded in
+	for _, partNumber := range []int{10, 2, 1, 2} {
+		testutil.MustDo(t, "create part", tracker.CreatePart(ctx, multipart.Part{
+			UploadID:     "upload1",
+			PartNumber:   partNumber,
+			ETag:         "etag",
+			Size:         int64(partNumber),
+			LastModified: now,
+		}))
+	}
+	err := tracker.CreatePart(ctx, multipart.Part{UploadID: "upload1"})
+	require.ErrorIs(t, err, multipart.ErrInvalidPartNumber)
+
+	partNumbers := func(parts []multipart.Part) []int {
+		var numbers []int
+		for _, p := range parts {
+			numbers = append(numbers, p.PartNumber)
+		}
+		return numbers
+	}
+	parts, hasMore, err := tracker.ListParts(ctx, "upload1", 0, 2)
+	require.NoError(t, err)
+	require.True(t, hasMore)
+	require.Equal(t, []int{1, 2}, partNumbers(parts))
+
+	parts, hasMore, err = tracker.ListParts(ctx, "upload1", 2, 2)
+	require.NoError(t, err)
+	require.False(t, hasMore)
+	require.Equal(t, []int{10}, partNumbers(parts))
+
+	// deleting an upload deletes its parts
+	testutil.MustDo(t, "delete upload", tracker.Delete(ctx, "upload1"))
+	parts, _, err = tracker.ListParts(ctx, "upload1", 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, parts)
+}
+
+func TestExpireUploads(t *testing.T) {
+	ctx := context.Background()
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	store := openStore(t)
+	conf, err := config.NewConfig("")
+	testutil.MustDo(t, "config", err)
+	c, err := catalog.New(ctx, catalog.Config{
+		Config:       conf,
+		KVStore:      store,
+		PathProvider: upload.DefaultPathProvider,
+	})
+	testutil.MustDo(t, "build catalog", err)
+	t.Cleanup(func() { _ = c.Close() })
+	repository, err := c.CreateRepository(ctx, "repo", "", "mem://repo", "main", false)
+	testutil.MustDo(t, "create repository", err)
+
+	adapter := mem.New(ctx)
+	tracker := multipart.NewTracker(store)
+	now := time.Now()
+	createUpload := func(upload multipart.Upload, storageNamespace string) multipart.Upload {
+		t.Helper()
+		resp, err := adapter.CreateMultiPartUpload(ctx, block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       upload.Path,
+		}, nil, block.CreateMultiPartUploadOpts{})
+		testutil.MustDo(t, "create multipart upload", err)
+		upload.UploadID = resp.UploadID
+		testutil.MustDo(t, "track upload", tracker.Create(ctx, upload))
+		return upload
+	}
+	old := now.Add(-48 * time.Hour)
+	expiredUploads := []multipart.Upload{
+		// tracked before its storage namespace was recorded
+		createUpload(multipart.Upload{Path: "old", PhysicalAddress: "old", CreationDate: old, Repository: repository.Name, Branch: "main"}, repository.StorageNamespace),
+		// to a deleted repository
+		createUpload(multipart.Upload{Path: "deleted", PhysicalAddress: "deleted", CreationDate: old, Repository: "deleted", Branch: "main", StorageNamespace: "mem://deleted"}, "mem://deleted"),
+		// tracked without its repository
+		createUpload(multipart.Upload{Path: "legacy", PhysicalAddress: "mem://repo/legacy", CreationDate: old}, repository.StorageNamespace),
+	}
+	newUpload := createUpload(multipart.Upload{Path: "new", PhysicalAddress: "new", CreationDate: now, Repository: repository.Name, Branch: "main", StorageNamespace: repository.StorageNamespace}, repository.StorageNamespace)
+
+	expired, err := multipart.ExpireUploads(ctx, tracker, c, adapter, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, len(expiredUploads), expired)
+
+	uploads, err := tracker.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+	require.Equal(t, newUpload.UploadID, uploads[0].UploadID)
+
+	// the expired uploads were aborted
+	for _, upload := range expiredUploads {
+		err = adapter.AbortMultiPartUpload(ctx, block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       upload.Path,
+		}, upload.UploadID)
+		require.ErrorIs(t, err, mem.ErrMultiPartNotFound, upload.Path)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/catalog"
 	gwerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
 	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/sig"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/logging"
@@ -56,6 +57,8 @@ type Operation struct {
 	MatchedHost       bool
 	PathProvider      upload.PathProvider
 	VerifyUnsupported bool
+	// PostForm is the form of a browser-based upload, set for form uploads only
+	PostForm *sig.PostForm
 }
 
 func StorageClassFromHeader(header http.Header) *string {
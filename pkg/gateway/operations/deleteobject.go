@@ -13,10 +13,15 @@ import (
 
 type DeleteObject struct{}
 
-func (controller *DeleteObject) RequiredPermissions(_ *http.Request, repoID, _, path string) (permissions.Node, error) {
+func (controller *DeleteObject) RequiredPermissions(req *http.Request, repoID, _, path string) (permissions.Node, error) {
+	action := permissions.DeleteObjectAction
+	if req.URL.Query().Has(QueryParamTagging) {
+		// deleting the tags of an object writes the object
+		action = permissions.WriteObjectAction
+	}
 	return permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DeleteObjectAction,
+			Action:   action,
 			Resource: permissions.ObjectArn(repoID, path),
 		},
 	}, nil
@@ -42,6 +47,7 @@ func (controller *DeleteObject) HandleAbortMultipartUpload(w http.ResponseWriter
 
 	req = req.WithContext(logging.AddFields(ctx, logging.Fields{logging.UploadIDFieldKey: uploadID}))
 	err = o.BlockStore.AbortMultiPartUpload(ctx, block.ObjectPointer{
+		StorageID:        o.Repository.StorageID,
 		StorageNamespace: o.Repository.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       mpu.PhysicalAddress,
@@ -60,10 +66,14 @@ func (controller *DeleteObject) HandleAbortMultipartUpload(w http.ResponseWriter
 }
 
 func (controller *DeleteObject) Handle(w http.ResponseWriter, req *http.Request, o *PathOperation) {
-	if o.HandleUnsupported(w, req, "tagging", "acl", "torrent") {
+	if o.HandleUnsupported(w, req, "acl", "torrent") {
 		return
 	}
 	query := req.URL.Query()
+	if query.Has(QueryParamTagging) {
+		handleDeleteObjectTagging(w, req, o)
+		return
+	}
 	if query.Has(QueryParamUploadID) {
 		controller.HandleAbortMultipartUpload(w, req, o)
 		return
@@ -16,11 +16,14 @@ import (
 	"github.com/treeverse/lakefs/pkg/gateway/serde"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/permissions"
 )
 
 const (
+	ListPartsMaxParts = 1000
+
 	QueryParamMaxParts = "max-parts"
 	// QueryParamPartNumberMarker Specifies the part after which listing should begin. Only parts with higher part numbers will be listed.
 	QueryParamPartNumberMarker       = "part-number-marker"
@@ -55,8 +58,8 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 		return
 	}
 
-	if _, exists := query["tagging"]; exists {
-		o.EncodeResponse(w, req, serde.Tagging{}, http.StatusOK)
+	if query.Has(QueryParamTagging) {
+		handleGetObjectTagging(w, req, o)
 		return
 	}
 
@@ -66,8 +69,15 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 		return
 	}
 
+	versionID := query.Get(QueryParamVersionID)
+	ref, ok := objectVersionReference(o.Reference, versionID)
+	if !ok {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
+		return
+	}
+
 	beforeMeta := time.Now()
-	entry, err := o.Catalog.GetEntry(ctx, o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	entry, err := o.Catalog.GetEntry(ctx, o.Repository.Name, ref, o.Path, catalog.GetEntryParams{})
 	metaTook := time.Since(beforeMeta)
 	o.Log(req).
 		WithField("took", metaTook).
@@ -75,8 +85,12 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 		Debug("metadata operation to retrieve object done")
 
 	if errors.Is(err, graveler.ErrNotFound) {
+		code := gatewayerrors.ErrNoSuchKey
+		if ref != o.Reference {
+			code = gatewayerrors.ErrNoSuchVersion
+		}
 		// TODO: create distinction between missing repo & missing key
-		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchKey))
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(code))
 		return
 	}
 	if errors.Is(err, catalog.ErrExpired) {
@@ -110,9 +124,11 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 	contentLength := entry.Size
 	contentRange := ""
 	objectPointer := block.ObjectPointer{
+		StorageID:        o.Repository.StorageID,
 		StorageNamespace: o.Repository.StorageNamespace,
 		IdentifierType:   entry.AddressType.ToIdentifierType(),
 		Identifier:       entry.PhysicalAddress,
+		Compression:      entry.Metadata.Compression(),
 	}
 
 	if redirect {
@@ -139,8 +155,11 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 	}
 	if err != nil {
 		code := gatewayerrors.ErrInternalError
-		if errors.Is(err, block.ErrDataNotFound) {
+		switch {
+		case errors.Is(err, block.ErrDataNotFound):
 			code = gatewayerrors.ErrNoSuchVersion
+		case errors.Is(err, block.ErrRestoreRequired):
+			code = gatewayerrors.ErrInvalidObjectState
 		}
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(code))
 		return
@@ -150,6 +169,9 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 	o.SetHeader(w, "ETag", httputil.ETag(entry.Checksum))
 	o.SetHeader(w, "Content-Type", entry.ContentType)
 	o.SetHeader(w, "Accept-Ranges", "bytes")
+	if versionID != "" {
+		o.SetHeader(w, "X-Amz-Version-Id", versionID)
+	}
 	if contentRange != "" {
 		o.SetHeader(w, "Content-Range", contentRange)
 	}
@@ -158,6 +180,7 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 	o.SetHeader(w, "X-Frame-Options", "SAMEORIGIN")
 	o.SetHeader(w, "Content-Security-Policy", "default-src 'none'")
 	amzMetaWriteHeaders(w, entry.Metadata)
+	amzTaggingCountWriteHeader(w, entry.Metadata)
 	w.WriteHeader(statusCode)
 
 	defer func() {
@@ -174,26 +197,35 @@ func handleListParts(w http.ResponseWriter, req *http.Request, o *PathOperation)
 	query := req.URL.Query()
 	uploadID := query.Get(QueryParamUploadID)
 	maxPartsStr := query.Get(QueryParamMaxParts)
-	partNumberMarker := query.Get(QueryParamPartNumberMarker)
+	partNumberMarkerStr := query.Get(QueryParamPartNumberMarker)
 	resp := &serde.ListPartsOutput{
-		Bucket: o.Repository.Name,
-		Key:    path.WithRef(o.Path, o.Reference),
+		Bucket:       o.Repository.Name,
+		Key:          path.WithRef(o.Path, o.Reference),
+		UploadID:     uploadID,
+		StorageClass: "STANDARD",
+		MaxParts:     ListPartsMaxParts,
 	}
-	opts := block.ListPartsOpts{}
 	if maxPartsStr != "" {
 		maxParts, err := strconv.ParseInt(maxPartsStr, 10, 32)
-		if err != nil {
+		if err != nil || maxParts < 0 {
 			o.Log(req).WithField("uploadId", uploadID).
 				WithField("MaxParts", maxPartsStr).
-				WithError(err).Error("malformed query parameter 'MaxParts'")
-			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+				WithError(err).Debug("malformed query parameter 'MaxParts'")
+			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidMaxParts))
 			return
 		}
-		resp.MaxParts = int32(maxParts)
-		opts.MaxParts = &resp.MaxParts
+		resp.MaxParts = min(int32(maxParts), ListPartsMaxParts)
 	}
-	if partNumberMarker != "" {
-		opts.PartNumberMarker = &partNumberMarker
+	if partNumberMarkerStr != "" {
+		partNumberMarker, err := strconv.ParseInt(partNumberMarkerStr, 10, 32)
+		if err != nil || partNumberMarker < 0 {
+			o.Log(req).WithField("uploadId", uploadID).
+				WithField("PartNumberMarker", partNumberMarkerStr).
+				WithError(err).Debug("malformed query parameter 'PartNumberMarker'")
+			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidPartNumberMarker))
+			return
+		}
+		resp.PartNumberMarker = int32(partNumberMarker)
 	}
 
 	req = req.WithContext(logging.AddFields(req.Context(), logging.Fields{
@@ -201,44 +233,35 @@ func handleListParts(w http.ResponseWriter, req *http.Request, o *PathOperation)
 	}))
 
 	multiPart, err := o.MultipartTracker.Get(req.Context(), uploadID)
+	if errors.Is(err, kv.ErrNotFound) || (err == nil && multiPart.Path != o.Path) {
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchUpload))
+		return
+	}
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not read multipart record")
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
 		return
 	}
 
-	partsResp, err := o.BlockStore.ListParts(req.Context(), block.ObjectPointer{
-		StorageNamespace: o.Repository.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       multiPart.PhysicalAddress,
-	}, uploadID, opts)
+	uploadParts, hasMore, err := o.MultipartTracker.ListParts(req.Context(), uploadID, int(resp.PartNumberMarker), int(resp.MaxParts))
 	if err != nil {
-		o.Log(req).WithField("uploadId", uploadID).
-			WithError(err).Error("list parts failed")
+		o.Log(req).WithError(err).Error("list parts failed")
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
 		return
 	}
-	parts := make([]serde.MultipartUploadPart, len(partsResp.Parts))
-	for i, part := range partsResp.Parts {
+	parts := make([]serde.MultipartUploadPart, len(uploadParts))
+	for i, part := range uploadParts {
 		parts[i] = serde.MultipartUploadPart{
 			PartNumber:   int32(part.PartNumber),
-			ETag:         part.ETag,
+			ETag:         httputil.ETag(part.ETag),
 			LastModified: serde.Timestamp(part.LastModified),
 			Size:         part.Size,
 		}
 	}
-	resp.IsTruncated = partsResp.IsTruncated
+	resp.IsTruncated = hasMore
 	resp.Parts = parts
-	if partsResp.NextPartNumberMarker != nil {
-		marker, err := strconv.ParseInt(*partsResp.NextPartNumberMarker, 10, 32)
-		if err != nil {
-			o.Log(req).WithField("uploadId", uploadID).
-				WithField("NextPartNumberMarker", partsResp.NextPartNumberMarker).
-				WithError(err).Error("invalid response 'NextPartNumberMarker'")
-			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
-			return
-		}
-		resp.NextPartNumberMarker = int32(marker)
+	if len(parts) > 0 {
+		resp.NextPartNumberMarker = parts[len(parts)-1].PartNumber
 	}
 
 	o.EncodeResponse(w, req, resp, http.StatusOK)
@@ -25,11 +25,21 @@ func (controller *HeadObject) RequiredPermissions(_ *http.Request, repoID, _, pa
 
 func (controller *HeadObject) Handle(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 	o.Incr("stat_object", o.Principal, o.Repository.Name, o.Reference)
-	entry, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	versionID := req.URL.Query().Get(QueryParamVersionID)
+	ref, ok := objectVersionReference(o.Reference, versionID)
+	if !ok {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
+		return
+	}
+	entry, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, ref, o.Path, catalog.GetEntryParams{})
 	if errors.Is(err, graveler.ErrNotFound) {
+		code := gatewayerrors.ErrNoSuchKey
+		if ref != o.Reference {
+			code = gatewayerrors.ErrNoSuchVersion
+		}
 		// TODO: create distinction between missing repo & missing key
 		o.Log(req).Debug("path not found")
-		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchKey))
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(code))
 		return
 	}
 	if err != nil {
@@ -63,8 +73,12 @@ func (controller *HeadObject) Handle(w http.ResponseWriter, req *http.Request, o
 	o.SetHeader(w, "Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
 	o.SetHeader(w, "ETag", httputil.ETag(entry.Checksum))
 	o.SetHeader(w, "Content-Type", entry.ContentType)
+	if versionID != "" {
+		o.SetHeader(w, "X-Amz-Version-Id", versionID)
+	}
 
 	amzMetaWriteHeaders(w, entry.Metadata)
+	amzTaggingCountWriteHeader(w, entry.Metadata)
 	if rangeSpec != "" && rngErr == nil {
 		o.SetHeader(w, "Content-Length", fmt.Sprintf("%d", rng.Size()))
 		o.SetHeader(w, "Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
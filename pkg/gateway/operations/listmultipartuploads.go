@@ -0,0 +1,116 @@
+package operations
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/path"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+const (
+	ListMultipartUploadsMaxUploads = 1000
+
+	QueryParamMaxUploads     = "max-uploads"
+	QueryParamKeyMarker      = "key-marker"
+	QueryParamUploadIDMarker = "upload-id-marker"
+)
+
+// ListMultipartUploads lists the multipart uploads in progress to the branches of a repository, ordered by key and
+// then by the time they were initiated.
+func (controller *ListObjects) ListMultipartUploads(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("list_mpu", o.Principal, o.Repository.Name, "")
+	params := req.URL.Query()
+	prefix := params.Get("prefix")
+	delimiter := params.Get("delimiter")
+	keyMarker := params.Get(QueryParamKeyMarker)
+	uploadIDMarker := params.Get(QueryParamUploadIDMarker)
+	maxUploads := ListMultipartUploadsMaxUploads
+	if s := params.Get(QueryParamMaxUploads); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			o.Log(req).WithError(err).WithField("max_uploads", s).Debug("invalid max uploads")
+			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidMaxUploads))
+			return
+		}
+		maxUploads = min(n, ListMultipartUploadsMaxUploads)
+	}
+
+	uploads, err := o.MultipartTracker.List(req.Context(), o.Repository.Name)
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not list multipart uploads")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+	keys := make([]string, len(uploads))
+	for i, upload := range uploads {
+		keys[i] = path.WithRef(upload.Path, upload.Branch)
+	}
+	order := make([]int, len(uploads))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if keys[a] != keys[b] {
+			return keys[a] < keys[b]
+		}
+		return uploads[a].CreationDate.Before(uploads[b].CreationDate)
+	})
+
+	resp := serde.ListMultipartUploadsResult{
+		Bucket:         o.Repository.Name,
+		KeyMarker:      keyMarker,
+		UploadIDMarker: uploadIDMarker,
+		Prefix:         prefix,
+		Delimiter:      delimiter,
+		MaxUploads:     maxUploads,
+		Uploads:        make([]serde.MultipartUpload, 0),
+		CommonPrefixes: make([]serde.CommonPrefixes, 0),
+	}
+	var passedUploadIDMarker bool
+	var lastKey, lastUploadID, lastCommonPrefix string
+	for _, i := range order {
+		upload, key := uploads[i], keys[i]
+		if !strings.HasPrefix(key, prefix) || key < keyMarker {
+			continue
+		}
+		if key == keyMarker && !passedUploadIDMarker {
+			// the uploads of the key marker are listed after the upload ID marker, if it is set
+			passedUploadIDMarker = uploadIDMarker != "" && upload.UploadID == uploadIDMarker
+			continue
+		}
+		commonPrefix := ""
+		if delimiter != "" {
+			if idx := strings.Index(key[len(prefix):], delimiter); idx >= 0 {
+				commonPrefix = key[:len(prefix)+idx+len(delimiter)]
+			}
+		}
+		if commonPrefix != "" && (commonPrefix == lastCommonPrefix || commonPrefix <= keyMarker) {
+			continue
+		}
+		if len(resp.Uploads)+len(resp.CommonPrefixes) == maxUploads {
+			resp.IsTruncated = true
+			resp.NextKeyMarker = lastKey
+			resp.NextUploadIDMarker = lastUploadID
+			break
+		}
+		if commonPrefix != "" {
+			resp.CommonPrefixes = append(resp.CommonPrefixes, serde.CommonPrefixes{Prefix: commonPrefix})
+			lastCommonPrefix = commonPrefix
+			lastKey, lastUploadID = commonPrefix, ""
+			continue
+		}
+		resp.Uploads = append(resp.Uploads, serde.MultipartUpload{
+			Key:          key,
+			UploadID:     upload.UploadID,
+			Initiated:    serde.Timestamp(upload.CreationDate),
+			StorageClass: "STANDARD",
+		})
+		lastKey, lastUploadID = key, upload.UploadID
+	}
+	o.EncodeResponse(w, req, resp, http.StatusOK)
+}
@@ -0,0 +1,147 @@
+package operations_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+func TestListObjects_ListMultipartUploads(t *testing.T) {
+	op, repository := setupOperation(t)
+
+	createUpload := func(t *testing.T, path string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/repo/main/"+path+"?uploads", nil)
+		rr := httptest.NewRecorder()
+		controller := &operations.PostObject{}
+		controller.Handle(rr, req, newPathOperation(op, repository, path))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var result serde.InitiateMultipartUploadResult
+		require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &result))
+		return result.UploadID
+	}
+	listUploads := func(t *testing.T, query url.Values) *serde.ListMultipartUploadsResult {
+		t.Helper()
+		query.Set("uploads", "")
+		req := httptest.NewRequest(http.MethodGet, "/repo?"+query.Encode(), nil)
+		rr := httptest.NewRecorder()
+		controller := &operations.ListObjects{}
+		controller.Handle(rr, req, newRepoOperation(op, repository))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var result serde.ListMultipartUploadsResult
+		require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &result))
+		return &result
+	}
+	type upload struct {
+		Key      string
+		UploadID string
+	}
+	uploadsOf := func(result *serde.ListMultipartUploadsResult) []upload {
+		var uploads []upload
+		for _, u := range result.Uploads {
+			uploads = append(uploads, upload{Key: u.Key, UploadID: u.UploadID})
+		}
+		return uploads
+	}
+
+	uploadB1 := createUpload(t, "data/b")
+	uploadA := createUpload(t, "data/a")
+	uploadB2 := createUpload(t, "data/b")
+	uploadC := createUpload(t, "other/c")
+	expected := []upload{
+		{Key: "main/data/a", UploadID: uploadA},
+		{Key: "main/data/b", UploadID: uploadB1},
+		{Key: "main/data/b", UploadID: uploadB2},
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		result := listUploads(t, url.Values{"prefix": {"main/data/"}})
+		require.False(t, result.IsTruncated)
+		require.Equal(t, expected, uploadsOf(result))
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		var (
+			uploads        []upload
+			keyMarker      string
+			uploadIDMarker string
+			pages          int
+		)
+		for {
+			result := listUploads(t, url.Values{
+				"prefix":           {"main/data/"},
+				"max-uploads":      {"2"},
+				"key-marker":       {keyMarker},
+				"upload-id-marker": {uploadIDMarker},
+			})
+			pages++
+			uploads = append(uploads, uploadsOf(result)...)
+			if !result.IsTruncated {
+				break
+			}
+			keyMarker = result.NextKeyMarker
+			uploadIDMarker = result.NextUploadIDMarker
+		}
+		require.Equal(t, expected, uploads)
+		require.Equal(t, 2, pages)
+	})
+
+	t.Run("delimiter", func(t *testing.T) {
+		result := listUploads(t, url.Values{"prefix": {"main/"}, "delimiter": {"/"}})
+		require.Empty(t, result.Uploads)
+		require.Equal(t, []serde.CommonPrefixes{{Prefix: "main/data/"}, {Prefix: "main/other/"}}, result.CommonPrefixes)
+	})
+
+	t.Run("list_parts", func(t *testing.T) {
+		for partNumber := 1; partNumber <= 3; partNumber++ {
+			body := strings.Repeat("x", partNumber)
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/repo/main/other/c?partNumber=%d&uploadId=%s", partNumber, uploadC), strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			controller := &operations.PutObject{}
+			controller.Handle(rr, req, newPathOperation(op, repository, "other/c"))
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		}
+		listParts := func(t *testing.T, query string) *serde.ListPartsOutput {
+			t.Helper()
+			req := httptest.NewRequest(http.MethodGet, "/repo/main/other/c?uploadId="+uploadC+query, nil)
+			rr := httptest.NewRecorder()
+			controller := &operations.GetObject{}
+			controller.Handle(rr, req, newPathOperation(op, repository, "other/c"))
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			var result serde.ListPartsOutput
+			require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &result))
+			return &result
+		}
+		result := listParts(t, "&max-parts=2")
+		require.True(t, result.IsTruncated)
+		require.Len(t, result.Parts, 2)
+		require.Equal(t, int32(1), result.Parts[0].PartNumber)
+		require.Equal(t, int64(2), result.Parts[1].Size)
+		require.NotEmpty(t, result.Parts[0].ETag)
+
+		result = listParts(t, fmt.Sprintf("&part-number-marker=%d", result.NextPartNumberMarker))
+		require.False(t, result.IsTruncated)
+		require.Len(t, result.Parts, 1)
+		require.Equal(t, int32(3), result.Parts[0].PartNumber)
+		require.Equal(t, int64(3), result.Parts[0].Size)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/repo/main/data/a?uploadId="+uploadA, nil)
+		rr := httptest.NewRecorder()
+		controller := &operations.DeleteObject{}
+		controller.Handle(rr, req, newPathOperation(op, repository, "data/a"))
+		require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+		result := listUploads(t, url.Values{"prefix": {"main/data/"}})
+		require.Equal(t, expected[1:], uploadsOf(result))
+	})
+}
@@ -355,7 +355,7 @@ func (controller *ListObjects) Handle(w http.ResponseWriter, req *http.Request,
 	if o.HandleUnsupported(w, req, "inventory", "metrics", "publicAccessBlock", "ownershipControls",
 		"intelligent-tiering", "analytics", "policy", "lifecycle", "encryption", "object-lock", "replication",
 		"notification", "events", "acl", "cors", "website", "accelerate",
-		"requestPayment", "logging", "tagging", "uploads", "versions", "policyStatus") {
+		"requestPayment", "logging", "tagging", "policyStatus") {
 		return
 	}
 	query := req.URL.Query()
@@ -376,6 +376,18 @@ func (controller *ListObjects) Handle(w http.ResponseWriter, req *http.Request,
 		o.EncodeXMLBytes(w, req, []byte(serde.VersioningResponse), http.StatusOK)
 		return
 	}
+
+	// listobjectversions support
+	if query.Has("versions") {
+		controller.ListVersions(w, req, o)
+		return
+	}
+
+	// listmultipartuploads support
+	if query.Has(CreateMultipartUploadQueryParam) {
+		controller.ListMultipartUploads(w, req, o)
+		return
+	}
 	o.Incr("list_objects", o.Principal, o.Repository.Name, "")
 
 	// parse request parameters
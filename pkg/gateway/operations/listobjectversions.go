@@ -0,0 +1,174 @@
+package operations
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/path"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	QueryParamVersionID = "versionId"
+
+	// nullVersionID is the version of the object of a ref which is not the version of any commit, as when it was
+	// changed on a branch and not committed yet
+	nullVersionID = "null"
+)
+
+var commitIDRegexp = regexp.MustCompile("^[a-f0-9]{64}$")
+
+// objectVersionReference returns the reference to read the versionID of an object of ref from. The versions of an
+// object are the commits in which it changed, and the null version is the object of the ref itself.
+func objectVersionReference(ref, versionID string) (string, bool) {
+	if versionID == "" || versionID == nullVersionID {
+		return ref, true
+	}
+	if !commitIDRegexp.MatchString(versionID) {
+		return "", false
+	}
+	return versionID, true
+}
+
+// ListVersions lists the versions of the objects under a ref prefix. The versions of an object are the commits in
+// which it changed, newest first, identified by their commit ID. Commits which deleted the object are its delete
+// markers, and uncommitted changes of a branch are the null version of the object.
+func (controller *ListObjects) ListVersions(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("list_object_versions", o.Principal, o.Repository.Name, "")
+	ctx := req.Context()
+	params := req.URL.Query()
+	delimiter := params.Get("delimiter")
+	keyMarker := params.Get("key-marker")
+	versionIDMarker := params.Get("version-id-marker")
+	maxKeys := controller.getMaxKeys(req, o)
+
+	prefix, err := path.ResolvePath(params.Get("prefix"))
+	if err != nil {
+		o.Log(req).
+			WithError(err).
+			WithField("path", params.Get("prefix")).
+			Error("could not resolve path for prefix")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+		return
+	}
+
+	resp := serde.ListVersionsResult{
+		Name:            o.Repository.Name,
+		Prefix:          params.Get("prefix"),
+		KeyMarker:       keyMarker,
+		VersionIDMarker: versionIDMarker,
+		MaxKeys:         maxKeys,
+		Delimiter:       delimiter,
+		Versions:        make([]serde.ObjectVersion, 0),
+		CommonPrefixes:  make([]serde.CommonPrefixes, 0),
+	}
+
+	if !prefix.WithPath {
+		// versions are listed within a ref, list the branches as ListObjects does
+		branches, hasMore, err := o.Catalog.ListBranches(ctx, o.Repository.Name, prefix.Ref, maxKeys, keyMarker)
+		if err != nil {
+			o.Log(req).WithError(err).Error("could not list branches")
+			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+			return
+		}
+		dirs, lastKey := controller.serializeBranches(branches)
+		resp.CommonPrefixes = dirs
+		if hasMore {
+			resp.IsTruncated = true
+			resp.NextKeyMarker = lastKey
+		}
+		o.EncodeResponse(w, req, resp, http.StatusOK)
+		return
+	}
+
+	ref := prefix.Ref
+	var marker path.ResolvedPath
+	if keyMarker != "" {
+		marker, err = path.ResolvePath(keyMarker)
+		if err != nil || !strings.EqualFold(marker.Ref, ref) {
+			o.Log(req).WithError(err).WithFields(logging.Fields{
+				"branch":     ref,
+				"path":       prefix.Path,
+				"key_marker": keyMarker,
+			}).Error("invalid key marker - doesnt start with branch name")
+			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+			return
+		}
+	}
+	afterVersionID := versionIDMarker
+	if versionIDMarker == nullVersionID {
+		afterVersionID = catalog.UncommittedVersionID
+	}
+
+	versions, hasMore, err := o.Catalog.ListObjectVersions(ctx, o.Repository.Name, ref, catalog.ListObjectVersionsParams{
+		Prefix:         prefix.Path,
+		Delimiter:      delimiter,
+		After:          marker.Path,
+		AfterVersionID: afterVersionID,
+		Limit:          maxKeys,
+	})
+	if errors.Is(err, graveler.ErrNotFound) {
+		o.EncodeResponse(w, req, resp, http.StatusOK)
+		return
+	}
+	if err != nil {
+		o.Log(req).WithError(err).WithFields(logging.Fields{
+			"ref":  ref,
+			"path": prefix.Path,
+		}).Error("could not list object versions")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+	for _, version := range versions {
+		key := path.WithRef(version.Path, ref)
+		if version.CommonLevel {
+			resp.CommonPrefixes = append(resp.CommonPrefixes, serde.CommonPrefixes{Prefix: key})
+			continue
+		}
+		resp.Versions = append(resp.Versions, serializeObjectVersion(key, version))
+	}
+	if hasMore && len(versions) > 0 {
+		last := versions[len(versions)-1]
+		resp.IsTruncated = true
+		resp.NextKeyMarker = path.WithRef(last.Path, ref)
+		if !last.CommonLevel {
+			resp.NextVersionIDMarker = serializeObjectVersionID(last.VersionID)
+		}
+	}
+	o.EncodeResponse(w, req, resp, http.StatusOK)
+}
+
+func serializeObjectVersionID(versionID string) string {
+	if versionID == catalog.UncommittedVersionID {
+		return nullVersionID
+	}
+	return versionID
+}
+
+func serializeObjectVersion(key string, version catalog.ObjectVersion) serde.ObjectVersion {
+	if version.Entry == nil {
+		return serde.ObjectVersion{
+			DeleteMarker: true,
+			Key:          key,
+			VersionID:    serializeObjectVersionID(version.VersionID),
+			IsLatest:     version.IsLatest,
+			LastModified: serde.Timestamp(version.CreationDate),
+		}
+	}
+	return serde.ObjectVersion{
+		Key:          key,
+		VersionID:    serializeObjectVersionID(version.VersionID),
+		IsLatest:     version.IsLatest,
+		LastModified: serde.Timestamp(version.Entry.CreationDate),
+		ETag:         httputil.ETag(version.Entry.Checksum),
+		Size:         version.Entry.Size,
+		StorageClass: "STANDARD",
+	}
+}
@@ -0,0 +1,174 @@
+package operations_test
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/testutil"
+)
+
+type version struct {
+	Key       string
+	VersionID string
+	IsLatest  bool
+	Deleted   bool
+}
+
+// listVersionsResult decodes the versions and delete markers of a listing in order, among its other elements
+type listVersionsResult struct {
+	IsTruncated         bool
+	NextKeyMarker       string
+	NextVersionIDMarker string `xml:"NextVersionIdMarker"`
+	Elements            []struct {
+		XMLName   xml.Name
+		Key       string
+		VersionID string `xml:"VersionId"`
+		IsLatest  bool
+	} `xml:",any"`
+	CommonPrefixes []serde.CommonPrefixes
+}
+
+func TestListObjects_ListVersions(t *testing.T) {
+	ctx := context.Background()
+	c, repository := setupCatalog(t)
+	repositoryID := repository.Name
+
+	put := func(path, checksum string) {
+		t.Helper()
+		err := c.CreateEntry(ctx, repositoryID, "main", catalog.DBEntry{Path: path, PhysicalAddress: path + checksum, Checksum: checksum, Size: int64(len(checksum))})
+		testutil.MustDo(t, "create entry", err)
+	}
+	commit := func() string {
+		t.Helper()
+		commitLog, err := c.Commit(ctx, repositoryID, "main", "commit", "tester", nil, nil, nil, false, nil, nil)
+		testutil.MustDo(t, "commit", err)
+		return commitLog.Reference
+	}
+	put("data/a", "a1")
+	put("data/b", "b1")
+	put("data/d", "d1")
+	commitA1 := commit()
+	put("data/a", "a2")
+	commitA2 := commit()
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repositoryID, "main", "data/b"))
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repositoryID, "main", "data/d"))
+	commitDelete := commit()
+	put("data/b", "b2")
+	put("data/c", "c1")
+
+	listVersions := func(t *testing.T, query url.Values) *listVersionsResult {
+		t.Helper()
+		query.Set("versions", "")
+		req := httptest.NewRequest(http.MethodGet, "/repo?"+query.Encode(), nil)
+		rr := httptest.NewRecorder()
+		controller := &operations.ListObjects{}
+		controller.Handle(rr, req, &operations.RepoOperation{
+			AuthorizedOperation: &operations.AuthorizedOperation{
+				Operation: &operations.Operation{
+					Catalog: c,
+					Incr:    func(_, _, _, _ string) {},
+				},
+			},
+			Repository: repository,
+		})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var result listVersionsResult
+		require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &result))
+		return &result
+	}
+	versionsOf := func(result *listVersionsResult) []version {
+		var versions []version
+		for _, v := range result.Elements {
+			if v.XMLName.Local != "Version" && v.XMLName.Local != "DeleteMarker" {
+				continue
+			}
+			versions = append(versions, version{Key: v.Key, VersionID: v.VersionID, IsLatest: v.IsLatest, Deleted: v.XMLName.Local == "DeleteMarker"})
+		}
+		return versions
+	}
+	expected := []version{
+		{Key: "main/data/a", VersionID: commitA2, IsLatest: true},
+		{Key: "main/data/a", VersionID: commitA1},
+		{Key: "main/data/b", VersionID: "null", IsLatest: true},
+		{Key: "main/data/b", VersionID: commitDelete, Deleted: true},
+		{Key: "main/data/b", VersionID: commitA1},
+		{Key: "main/data/c", VersionID: "null", IsLatest: true},
+		// objects deleted from the ref are listed with their delete marker as latest version
+		{Key: "main/data/d", VersionID: commitDelete, IsLatest: true, Deleted: true},
+		{Key: "main/data/d", VersionID: commitA1},
+	}
+
+	t.Run("all", func(t *testing.T) {
+		result := listVersions(t, url.Values{"prefix": {"main/data/"}})
+		require.False(t, result.IsTruncated)
+		require.Equal(t, expected, versionsOf(result))
+	})
+
+	for _, maxKeys := range []int{1, 2, 3} {
+		t.Run(fmt.Sprintf("paginated_%d", maxKeys), func(t *testing.T) {
+			var (
+				versions        []version
+				keyMarker       string
+				versionIDMarker string
+				pages           int
+			)
+			for {
+				result := listVersions(t, url.Values{
+					"prefix":            {"main/data/"},
+					"max-keys":          {strconv.Itoa(maxKeys)},
+					"key-marker":        {keyMarker},
+					"version-id-marker": {versionIDMarker},
+				})
+				pages++
+				page := versionsOf(result)
+				require.LessOrEqual(t, len(page), maxKeys)
+				versions = append(versions, page...)
+				if !result.IsTruncated {
+					break
+				}
+				keyMarker = result.NextKeyMarker
+				versionIDMarker = result.NextVersionIDMarker
+			}
+			require.Equal(t, expected, versions)
+			require.Equal(t, (len(expected)+maxKeys-1)/maxKeys, pages)
+		})
+	}
+
+	t.Run("head_version", func(t *testing.T) {
+		headVersion := func(t *testing.T, versionID string) *http.Response {
+			t.Helper()
+			req := httptest.NewRequest(http.MethodHead, "/repo/main/data/a?versionId="+versionID, nil)
+			rr := httptest.NewRecorder()
+			controller := &operations.HeadObject{}
+			controller.Handle(rr, req, pathOperation(c, repository, "data/a"))
+			return rr.Result()
+		}
+		resp := headVersion(t, commitA1)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, `"a1"`, resp.Header["ETag"][0])
+		require.Equal(t, commitA1, resp.Header.Get("X-Amz-Version-Id"))
+
+		resp = headVersion(t, "null")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, `"a2"`, resp.Header["ETag"][0])
+
+		resp = headVersion(t, "main")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("delimiter", func(t *testing.T) {
+		result := listVersions(t, url.Values{"prefix": {"main/"}, "delimiter": {"/"}})
+		require.Empty(t, versionsOf(result))
+		require.Equal(t, []serde.CommonPrefixes{{Prefix: "main/data/"}}, result.CommonPrefixes)
+	})
+}
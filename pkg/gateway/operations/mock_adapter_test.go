@@ -55,7 +55,7 @@ func (a *mockAdapter) Get(_ context.Context, _ block.ObjectPointer, _ int64) (io
 	return nil, nil
 }
 
-func (a *mockAdapter) GetWalker(_ *url.URL) (block.Walker, error) {
+func (a *mockAdapter) GetWalker(_ string, _ *url.URL) (block.Walker, error) {
 	return nil, nil
 }
 
@@ -75,6 +75,14 @@ func (a *mockAdapter) Copy(_ context.Context, _, _ block.ObjectPointer) error {
 	return errors.New("copy method not implemented in mock adapter")
 }
 
+func (a *mockAdapter) SetStorageClass(_ context.Context, _ block.ObjectPointer, _ string) error {
+	return errors.New("set storage class method not implemented in mock adapter")
+}
+
+func (a *mockAdapter) Restore(_ context.Context, _ block.ObjectPointer, _ int) error {
+	return errors.New("restore method not implemented in mock adapter")
+}
+
 func (a *mockAdapter) CreateMultiPartUpload(_ context.Context, _ block.ObjectPointer, _ *http.Request, _ block.CreateMultiPartUploadOpts) (*block.CreateMultiPartUploadResponse, error) {
 	panic("try to create multipart in mock adapter")
 }
@@ -114,7 +122,7 @@ func (a *mockAdapter) GetStorageNamespaceInfo() block.StorageNamespaceInfo {
 	return info
 }
 
-func (a *mockAdapter) ResolveNamespace(storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
+func (a *mockAdapter) ResolveNamespace(_, storageNamespace, key string, identifierType block.IdentifierType) (block.QualifiedKey, error) {
 	return block.DefaultResolveNamespace(storageNamespace, key, identifierType)
 }
 
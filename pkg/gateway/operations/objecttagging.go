@@ -0,0 +1,164 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const (
+	QueryParamTagging  = "tagging"
+	TaggingHeader      = "x-amz-tagging"
+	TaggingCountHeader = "X-Amz-Tagging-Count"
+
+	maxObjectTags           = 10
+	maxObjectTagKeyLength   = 128
+	maxObjectTagValueLength = 256
+)
+
+var errInvalidTag = errors.New("invalid tag")
+
+// validateObjectTags verifies tags are within the limits of S3 object tags
+func validateObjectTags(tags map[string]string) error {
+	if len(tags) > maxObjectTags {
+		return fmt.Errorf("%w: more than %d tags", errInvalidTag, maxObjectTags)
+	}
+	for k, v := range tags {
+		if k == "" || utf8.RuneCountInString(k) > maxObjectTagKeyLength {
+			return fmt.Errorf("%w: key '%s'", errInvalidTag, k)
+		}
+		if utf8.RuneCountInString(v) > maxObjectTagValueLength {
+			return fmt.Errorf("%w: value of key '%s'", errInvalidTag, k)
+		}
+	}
+	return nil
+}
+
+// tagSetAsTags returns the tags of a tag set, which may not repeat a key
+func tagSetAsTags(tagSet serde.TagSet) (map[string]string, error) {
+	tags := make(map[string]string, len(tagSet.Tag))
+	for _, tag := range tagSet.Tag {
+		if _, ok := tags[tag.Key]; ok {
+			return nil, fmt.Errorf("%w: duplicate key '%s'", errInvalidTag, tag.Key)
+		}
+		tags[tag.Key] = tag.Value
+	}
+	return tags, validateObjectTags(tags)
+}
+
+// amzTaggingAsMetadata adds the tags of the tagging request header, encoded as URL query parameters, to metadata
+func amzTaggingAsMetadata(req *http.Request, metadata catalog.Metadata) (catalog.Metadata, error) {
+	tagging := req.Header.Get(TaggingHeader)
+	if tagging == "" {
+		return metadata, nil
+	}
+	values, err := url.ParseQuery(tagging)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidTag, err)
+	}
+	tags := make(map[string]string, len(values))
+	for k, v := range values {
+		if len(v) != 1 {
+			return nil, fmt.Errorf("%w: duplicate key '%s'", errInvalidTag, k)
+		}
+		tags[k] = v[0]
+	}
+	if err := validateObjectTags(tags); err != nil {
+		return nil, err
+	}
+	return metadata.WithObjectTags(tags), nil
+}
+
+// amzTaggingCountWriteHeader sets the number of object tags kept in metadata on the http response
+func amzTaggingCountWriteHeader(w http.ResponseWriter, metadata catalog.Metadata) {
+	if count := len(metadata.ObjectTags()); count > 0 {
+		w.Header()[TaggingCountHeader] = []string{strconv.Itoa(count)}
+	}
+}
+
+func handleGetObjectTagging(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("get_object_tagging", o.Principal, o.Repository.Name, o.Reference)
+	versionID := req.URL.Query().Get(QueryParamVersionID)
+	ref, ok := objectVersionReference(o.Reference, versionID)
+	if !ok {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
+		return
+	}
+	entry, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, ref, o.Path, catalog.GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		code := gatewayerrors.ErrNoSuchKey
+		if ref != o.Reference {
+			code = gatewayerrors.ErrNoSuchVersion
+		}
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(code))
+		return
+	}
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not get object tagging")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+
+	tags := entry.Metadata.ObjectTags()
+	tagging := serde.Tagging{TagSet: serde.TagSet{Tag: make([]serde.Tag, 0, len(tags))}}
+	for k, v := range tags {
+		tagging.TagSet.Tag = append(tagging.TagSet.Tag, serde.Tag{Key: k, Value: v})
+	}
+	sort.Slice(tagging.TagSet.Tag, func(i, j int) bool {
+		return tagging.TagSet.Tag[i].Key < tagging.TagSet.Tag[j].Key
+	})
+	if versionID != "" {
+		o.SetHeader(w, "X-Amz-Version-Id", versionID)
+	}
+	o.EncodeResponse(w, req, tagging, http.StatusOK)
+}
+
+func handlePutObjectTagging(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("put_object_tagging", o.Principal, o.Repository.Name, o.Reference)
+	var tagging serde.Tagging
+	if err := DecodeXMLBody(req.Body, &tagging); err != nil {
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrMalformedXML))
+		return
+	}
+	tags, err := tagSetAsTags(tagging.TagSet)
+	if err != nil {
+		o.Log(req).WithError(err).Debug("invalid object tagging")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidTag))
+		return
+	}
+	setObjectTags(w, req, o, tags, http.StatusOK)
+}
+
+func handleDeleteObjectTagging(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("delete_object_tagging", o.Principal, o.Repository.Name, o.Reference)
+	setObjectTags(w, req, o, nil, http.StatusNoContent)
+}
+
+func setObjectTags(w http.ResponseWriter, req *http.Request, o *PathOperation, tags map[string]string, statusCode int) {
+	err := o.Catalog.SetEntryTags(req.Context(), o.Repository.Name, o.Reference, o.Path, tags)
+	switch {
+	case errors.Is(err, graveler.ErrNotFound):
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchKey))
+	case errors.Is(err, graveler.ErrWriteToProtectedBranch):
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrWriteToProtectedBranch))
+	case errors.Is(err, graveler.ErrReadOnlyRepository):
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrReadOnlyRepository))
+	case errors.Is(err, graveler.ErrPreconditionFailed):
+		// the object kept changing while setting its tags
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrPreconditionFailed))
+	case err != nil:
+		o.Log(req).WithError(err).Error("could not set object tagging")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+	default:
+		w.WriteHeader(statusCode)
+	}
+}
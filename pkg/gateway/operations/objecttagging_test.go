@@ -0,0 +1,112 @@
+package operations_test
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+func TestObjectTagging(t *testing.T) {
+	ctx := context.Background()
+	c, repository := setupCatalog(t)
+
+	err := c.CreateEntry(ctx, repository.Name, "main", catalog.DBEntry{
+		Path:            "data/a",
+		PhysicalAddress: "data/a",
+		Checksum:        "a1",
+		Metadata:        catalog.Metadata{"X-Amz-Meta-Color": "red"},
+	})
+	require.NoError(t, err)
+
+	serve := func(t *testing.T, controller interface {
+		Handle(http.ResponseWriter, *http.Request, *operations.PathOperation)
+	}, req *http.Request, path string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		controller.Handle(rr, req, pathOperation(c, repository, path))
+		return rr
+	}
+	putTagging := func(t *testing.T, path string, tags ...serde.Tag) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := xml.Marshal(serde.Tagging{TagSet: serde.TagSet{Tag: tags}})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/repo/main/"+path+"?tagging", strings.NewReader(string(body)))
+		return serve(t, &operations.PutObject{}, req, path)
+	}
+	getTagging := func(t *testing.T, path string) []serde.Tag {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/repo/main/"+path+"?tagging", nil)
+		rr := serve(t, &operations.GetObject{}, req, path)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var tagging serde.Tagging
+		require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &tagging))
+		return tagging.TagSet.Tag
+	}
+	headTaggingCount := func(t *testing.T, path string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodHead, "/repo/main/"+path, nil)
+		rr := serve(t, &operations.HeadObject{}, req, path)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Header().Get(operations.TaggingCountHeader)
+	}
+
+	t.Run("put_get_delete", func(t *testing.T) {
+		require.Empty(t, getTagging(t, "data/a"))
+		require.Empty(t, headTaggingCount(t, "data/a"))
+
+		rr := putTagging(t, "data/a", serde.Tag{Key: "team", Value: "data"}, serde.Tag{Key: "env", Value: "prod"})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		require.Equal(t, []serde.Tag{{Key: "env", Value: "prod"}, {Key: "team", Value: "data"}}, getTagging(t, "data/a"))
+		require.Equal(t, "2", headTaggingCount(t, "data/a"))
+
+		// tags replace the previous tags and keep the user metadata
+		rr = putTagging(t, "data/a", serde.Tag{Key: "team", Value: "ml"})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		require.Equal(t, []serde.Tag{{Key: "team", Value: "ml"}}, getTagging(t, "data/a"))
+		entry, err := c.GetEntry(ctx, repository.Name, "main", "data/a", catalog.GetEntryParams{})
+		require.NoError(t, err)
+		require.Equal(t, "red", entry.Metadata["X-Amz-Meta-Color"])
+		require.Equal(t, "a1", entry.Checksum)
+
+		req := httptest.NewRequest(http.MethodDelete, "/repo/main/data/a?tagging", nil)
+		rr = serve(t, &operations.DeleteObject{}, req, "data/a")
+		require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+		require.Empty(t, getTagging(t, "data/a"))
+		_, err = c.GetEntry(ctx, repository.Name, "main", "data/a", catalog.GetEntryParams{})
+		require.NoError(t, err, "delete object tagging should keep the object")
+	})
+
+	t.Run("put_object_with_tagging", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/repo/main/data/b", strings.NewReader("content"))
+		req.Header.Set(operations.TaggingHeader, "team=data&env=dev")
+		rr := serve(t, &operations.PutObject{}, req, "data/b")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		require.Equal(t, []serde.Tag{{Key: "env", Value: "dev"}, {Key: "team", Value: "data"}}, getTagging(t, "data/b"))
+		require.Equal(t, "2", headTaggingCount(t, "data/b"))
+	})
+
+	t.Run("too_many_tags", func(t *testing.T) {
+		tags := make([]serde.Tag, 11)
+		for i := range tags {
+			tags[i] = serde.Tag{Key: fmt.Sprintf("key%d", i), Value: "value"}
+		}
+		rr := putTagging(t, "data/a", tags...)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "InvalidTag")
+	})
+
+	t.Run("no_such_key", func(t *testing.T) {
+		rr := putTagging(t, "data/missing", serde.Tag{Key: "team", Value: "data"})
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Contains(t, rr.Body.String(), "NoSuchKey")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/logging"
 )
 
@@ -32,7 +33,7 @@ func amzMetaWriteHeaders(w http.ResponseWriter, metadata catalog.Metadata) {
 	}
 }
 
-func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddress string, size int64, relative bool, metadata map[string]string, contentType string) error {
+func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddress string, size int64, relative bool, metadata map[string]string, contentType string, opts ...graveler.SetOptionsFunc) error {
 	// write metadata
 	writeTime := time.Now()
 	entry := catalog.NewDBEntryBuilder().
@@ -46,7 +47,7 @@ func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddres
 		ContentType(contentType).
 		Build()
 
-	err := o.Catalog.CreateEntry(req.Context(), o.Repository.Name, o.Reference, entry)
+	err := o.Catalog.CreateEntry(req.Context(), o.Repository.Name, o.Reference, entry, opts...)
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not update metadata")
 		return err
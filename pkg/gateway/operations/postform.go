@@ -0,0 +1,188 @@
+package operations
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayErrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/path"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+const (
+	PostFormSuccessActionRedirectField = "success_action_redirect"
+	PostFormSuccessActionStatusField   = "success_action_status"
+	// postFormRedirectField is the deprecated name of success_action_redirect
+	postFormRedirectField = "redirect"
+)
+
+// postFormHeader returns the fields of a form upload standing for request headers, such as Content-Type and
+// x-amz-meta-*, as an http header
+func postFormHeader(fields map[string]string) http.Header {
+	header := make(http.Header, len(fields))
+	for name, value := range fields {
+		header.Set(name, value)
+	}
+	return header
+}
+
+// handlePostFormUpload writes the file of a browser-based upload, once its fields are verified against the
+// conditions of its POST policy.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectPOST.html
+func handlePostFormUpload(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("post_object", o.Principal, o.Repository.Name, o.Reference)
+	form := o.PostForm
+	if err := form.Policy.VerifyFields(form.Fields, o.Repository.Name); err != nil {
+		o.Log(req).WithError(err).Debug("form upload does not meet its policy")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrAccessDenied))
+		return
+	}
+
+	// verify branch before we upload data - fail early
+	branchExists, err := o.Catalog.BranchExists(req.Context(), o.Repository.Name, o.Reference)
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not check if branch exists")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return
+	}
+	if !branchExists {
+		o.Log(req).Debug("branch not found")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNoSuchBucket))
+		return
+	}
+
+	header := postFormHeader(form.Fields)
+	metadata := make(catalog.Metadata)
+	for k := range header {
+		if strings.HasPrefix(k, amzMetaHeaderPrefix) {
+			metadata[k] = header.Get(k)
+		}
+	}
+	storageClass := StorageClassFromHeader(header)
+	contentType := header.Get("Content-Type")
+	codec, err := o.Catalog.CompressionCodec(req.Context(), o.Repository.Name, o.Path, contentType)
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not get compression rules")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return
+	}
+
+	// the size of the file is unknown until it is read, read no more than one byte past the policy's limit
+	body := form.File
+	minLength, maxLength, limited := form.Policy.ContentLengthRange()
+	if limited {
+		body = io.LimitReader(body, maxLength+1)
+	}
+	opts := block.PutOpts{StorageClass: storageClass, Compression: codec}
+	address := o.PathProvider.NewPath()
+	blob, err := upload.WriteBlob(req.Context(), o.BlockStore, o.Repository.StorageID, o.Repository.StorageNamespace, address, body, -1, opts)
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not write form file to block adapter")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return
+	}
+	if limited && (blob.Size > maxLength || blob.Size < minLength) {
+		// the file is not referenced by any entry, remove it instead of leaving it to garbage collection
+		o.removeBlob(req, blob)
+		o.Log(req).WithFields(logging.Fields{
+			"min_length": minLength,
+			"max_length": maxLength,
+			"size":       blob.Size,
+		}).Debug("form file outside policy content length range")
+		errCode := gatewayErrors.ErrEntityTooLarge
+		if blob.Size < minLength {
+			errCode = gatewayErrors.ErrEntityTooSmall
+		}
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(errCode))
+		return
+	}
+	// objects written with a storage class keep their own copy
+	if storageClass == nil {
+		blob, err = o.Catalog.DedupBlob(req.Context(), o.Repository.Name, o.Path, blob)
+		if err != nil {
+			o.Log(req).WithError(err).Error("could not deduplicate uploaded object")
+			_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+			return
+		}
+	}
+
+	// write metadata
+	err = o.finishUpload(req, blob.Checksum, blob.PhysicalAddress, blob.Size, true, metadata.WithCompression(blob.Compression), contentType)
+	if errors.Is(err, graveler.ErrWriteToProtectedBranch) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToProtectedBranch))
+		return
+	}
+	if errors.Is(err, graveler.ErrReadOnlyRepository) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrReadOnlyRepository))
+		return
+	}
+	if err != nil {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return
+	}
+	writePostFormResponse(w, req, o, httputil.ETag(blob.Checksum))
+}
+
+// removeBlob removes an uploaded blob that is not written to the branch
+func (o *PathOperation) removeBlob(req *http.Request, blob *upload.Blob) {
+	err := o.BlockStore.Remove(req.Context(), block.ObjectPointer{
+		StorageID:        o.Repository.StorageID,
+		StorageNamespace: o.Repository.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       blob.PhysicalAddress,
+	})
+	if err != nil {
+		o.Log(req).WithError(err).WithField("physical_address", blob.PhysicalAddress).Warn("Failed to remove rejected form file")
+	}
+}
+
+// writePostFormResponse responds to a successful form upload: a redirect in case the form requests one, otherwise
+// the status requested by the form, 204 by default.
+func writePostFormResponse(w http.ResponseWriter, req *http.Request, o *PathOperation, etag string) {
+	fields := o.PostForm.Fields
+	key := path.WithRef(o.Path, o.Reference)
+	location := objectLocation(req, o)
+	o.SetHeader(w, "ETag", etag)
+	o.SetHeader(w, "Location", location)
+
+	redirect := fields[PostFormSuccessActionRedirectField]
+	if redirect == "" {
+		redirect = fields[postFormRedirectField]
+	}
+	if redirect != "" {
+		// an invalid redirect is ignored, responding with the requested status
+		if u, err := url.Parse(redirect); err == nil {
+			query := u.Query()
+			query.Set("bucket", o.Repository.Name)
+			query.Set("key", key)
+			query.Set("etag", etag)
+			u.RawQuery = query.Encode()
+			o.SetHeader(w, "Location", u.String())
+			w.WriteHeader(http.StatusSeeOther)
+			return
+		}
+	}
+
+	switch fields[PostFormSuccessActionStatusField] {
+	case "200":
+		w.WriteHeader(http.StatusOK)
+	case "201":
+		o.EncodeResponse(w, req, &serde.PostResponse{
+			Location: location,
+			Bucket:   o.Repository.Name,
+			Key:      key,
+			ETag:     etag,
+		}, http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,150 @@
+package operations_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/sig"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+// recordingPathProvider records the last path it provides
+type recordingPathProvider struct {
+	upload.PathProvider
+	last string
+}
+
+func (p *recordingPathProvider) NewPath() string {
+	p.last = p.PathProvider.NewPath()
+	return p.last
+}
+
+func TestPostObject_FormUpload(t *testing.T) {
+	op, repository := setupOperation(t)
+	pathProvider := &recordingPathProvider{PathProvider: op.PathProvider}
+	op.PathProvider = pathProvider
+	expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	policy := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"expiration": %q, "conditions": [
+		{"bucket": "repo"},
+		["starts-with", "$key", "main/uploads/"],
+		["starts-with", "$Content-Type", "text/"],
+		["starts-with", "$success_action_status", ""],
+		["starts-with", "$success_action_redirect", ""],
+		["content-length-range", 1, 16]
+	]}`, expiration)))
+
+	// formUpload uploads content by a form of the fields, its policy already authenticated. Fields of the policy
+	// missing from fields are sent empty.
+	formUpload := func(t *testing.T, fields map[string]string, content string) *httptest.ResponseRecorder {
+		t.Helper()
+		formFields := map[string]string{
+			sig.PostFormPolicyField:                       policy,
+			operations.PostFormSuccessActionStatusField:   "",
+			operations.PostFormSuccessActionRedirectField: "",
+		}
+		for name, value := range fields {
+			formFields[name] = value
+		}
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for name, value := range formFields {
+			require.NoError(t, mw.WriteField(name, value))
+		}
+		fw, err := mw.CreateFormFile(sig.PostFormFileField, "hello.txt")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		req := httptest.NewRequest(http.MethodPost, "/repo", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+
+		form, err := sig.ParsePostForm(req)
+		require.NoError(t, err)
+		form.Policy, err = sig.ParsePostPolicy(policy)
+		require.NoError(t, err)
+		formOp := *op
+		formOp.PostForm = form
+		ref, pth, _ := strings.Cut(form.Key(), "/")
+		o := newPathOperation(&formOp, repository, pth)
+		o.Reference = ref
+
+		rr := httptest.NewRecorder()
+		controller := &operations.PostObject{}
+		controller.Handle(rr, req, o)
+		return rr
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		rr := formUpload(t, map[string]string{"key": "main/uploads/${filename}", "Content-Type": "text/plain"}, "hello world")
+		require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+		require.NotEmpty(t, rr.Header()["ETag"])
+		entry, err := op.Catalog.GetEntry(context.Background(), repository.Name, "main", "uploads/hello.txt", catalog.GetEntryParams{})
+		require.NoError(t, err)
+		require.Equal(t, int64(len("hello world")), entry.Size)
+		require.Equal(t, "text/plain", entry.ContentType)
+	})
+
+	t.Run("status_201", func(t *testing.T) {
+		rr := formUpload(t, map[string]string{"key": "main/uploads/created.txt", "Content-Type": "text/plain", "success_action_status": "201"}, "created")
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		require.Contains(t, rr.Body.String(), "<Key>main/uploads/created.txt</Key>")
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		rr := formUpload(t, map[string]string{"key": "main/uploads/redirect.txt", "Content-Type": "text/plain", "success_action_redirect": "https://example.com/done"}, "redirect")
+		require.Equal(t, http.StatusSeeOther, rr.Code, rr.Body.String())
+		location, err := url.Parse(rr.Header().Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "example.com", location.Host)
+		require.Equal(t, "main/uploads/redirect.txt", location.Query().Get("key"))
+		require.Equal(t, "repo", location.Query().Get("bucket"))
+	})
+
+	t.Run("policy_condition", func(t *testing.T) {
+		rr := formUpload(t, map[string]string{"key": "main/other/file.txt", "Content-Type": "text/plain"}, "hello")
+		require.Equal(t, http.StatusForbidden, rr.Code)
+		require.Contains(t, rr.Body.String(), "AccessDenied")
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		rr := formUpload(t, map[string]string{"key": "main/uploads/large.txt", "Content-Type": "text/plain"}, strings.Repeat("x", 17))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "EntityTooLarge")
+		_, err := op.Catalog.GetEntry(context.Background(), repository.Name, "main", "uploads/large.txt", catalog.GetEntryParams{})
+		require.Error(t, err)
+		requireBlobRemoved(t, op, repository, pathProvider.last)
+	})
+
+	t.Run("too_small", func(t *testing.T) {
+		rr := formUpload(t, map[string]string{"key": "main/uploads/empty.txt", "Content-Type": "text/plain"}, "")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "EntityTooSmall")
+		requireBlobRemoved(t, op, repository, pathProvider.last)
+	})
+}
+
+func requireBlobRemoved(t *testing.T, op *operations.Operation, repository *catalog.Repository, address string) {
+	t.Helper()
+	require.NotEmpty(t, address)
+	exists, err := op.BlockStore.Exists(context.Background(), block.ObjectPointer{
+		StorageID:        repository.StorageID,
+		StorageNamespace: repository.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       address,
+	})
+	require.NoError(t, err)
+	require.False(t, exists, "rejected form file %s not removed", address)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/gateway/multipart"
 	"github.com/treeverse/lakefs/pkg/gateway/path"
 	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/gateway/sig"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/logging"
@@ -27,10 +28,15 @@ const (
 
 type PostObject struct{}
 
-func (controller *PostObject) RequiredPermissions(_ *http.Request, repoID, _, path string) (permissions.Node, error) {
+func (controller *PostObject) RequiredPermissions(req *http.Request, repoID, _, path string) (permissions.Node, error) {
+	action := permissions.WriteObjectAction
+	if req.URL.Query().Has(QueryParamSelect) && !sig.IsPostFormRequest(req) {
+		// selecting the content of an object only reads it, form uploads write it whatever their query
+		action = permissions.ReadObjectAction
+	}
 	return permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
+			Action:   action,
 			Resource: permissions.ObjectArn(repoID, path),
 		},
 	}, nil
@@ -49,10 +55,17 @@ func (controller *PostObject) HandleCreateMultipartUpload(w http.ResponseWriter,
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNoSuchBucket))
 		return
 	}
+	metadata, err := amzTaggingAsMetadata(req, amzMetaAsMetadata(req))
+	if err != nil {
+		o.Log(req).WithError(err).Debug("invalid object tagging")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidTag))
+		return
+	}
 	address := o.PathProvider.NewPath()
 	storageClass := StorageClassFromHeader(req.Header)
 	opts := block.CreateMultiPartUploadOpts{StorageClass: storageClass}
 	resp, err := o.BlockStore.CreateMultiPartUpload(req.Context(), block.ObjectPointer{
+		StorageID:        o.Repository.StorageID,
 		StorageNamespace: o.Repository.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       address,
@@ -63,12 +76,16 @@ func (controller *PostObject) HandleCreateMultipartUpload(w http.ResponseWriter,
 		return
 	}
 	mpu := multipart.Upload{
-		UploadID:        resp.UploadID,
-		Path:            o.Path,
-		CreationDate:    time.Now(),
-		PhysicalAddress: address,
-		Metadata:        map[string]string(amzMetaAsMetadata(req)),
-		ContentType:     req.Header.Get("Content-Type"),
+		UploadID:         resp.UploadID,
+		Path:             o.Path,
+		CreationDate:     time.Now(),
+		PhysicalAddress:  address,
+		Metadata:         map[string]string(metadata),
+		ContentType:      req.Header.Get("Content-Type"),
+		Repository:       o.Repository.Name,
+		Branch:           o.Reference,
+		StorageID:        o.Repository.StorageID,
+		StorageNamespace: o.Repository.StorageNamespace,
 	}
 	err = o.MultipartTracker.Create(req.Context(), mpu)
 	if err != nil {
@@ -88,6 +105,10 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 	o.Incr("complete_mpu", o.Principal, o.Repository.Name, o.Reference)
 	uploadID := req.URL.Query().Get(CompleteMultipartUploadQueryParam)
 	req = req.WithContext(logging.AddFields(req.Context(), logging.Fields{logging.UploadIDFieldKey: uploadID}))
+	setOpts, ok := writePreconditions(w, req, o)
+	if !ok {
+		return
+	}
 	multiPart, err := o.MultipartTracker.Get(req.Context(), uploadID)
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not read multipart record")
@@ -112,6 +133,7 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 	normalizeMultipartUploadCompletion(&multipartList)
 	resp, err := o.BlockStore.CompleteMultiPartUpload(req.Context(),
 		block.ObjectPointer{
+			StorageID:        o.Repository.StorageID,
 			StorageNamespace: o.Repository.StorageNamespace,
 			IdentifierType:   block.IdentifierTypeRelative,
 			Identifier:       objName,
@@ -124,7 +146,7 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 		return
 	}
 	checksum := strings.Split(resp.ETag, "-")[0]
-	err = o.finishUpload(req, checksum, objName, resp.ContentLength, true, multiPart.Metadata, multiPart.ContentType)
+	err = o.finishUpload(req, checksum, objName, resp.ContentLength, true, multiPart.Metadata, multiPart.ContentType, setOpts...)
 	if errors.Is(err, graveler.ErrWriteToProtectedBranch) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToProtectedBranch))
 		return
@@ -133,6 +155,15 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrReadOnlyRepository))
 		return
 	}
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+		return
+	}
+	if errors.Is(err, graveler.ErrNotFound) && len(setOpts) > 0 {
+		// the object matched by If-Match was deleted since
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNoSuchKey))
+		return
+	}
 	if err != nil {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
@@ -142,22 +173,24 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 		o.Log(req).WithError(err).Warn("could not delete multipart record")
 	}
 
-	scheme := httputil.RequestScheme(req)
-	var location string
-	if o.MatchedHost {
-		location = fmt.Sprintf("%s://%s/%s/%s", scheme, req.Host, o.Reference, o.Path)
-	} else {
-		location = fmt.Sprintf("%s://%s/%s/%s/%s", scheme, req.Host, o.Repository.Name, o.Reference, o.Path)
-	}
 	o.SetHeaders(w, resp.ServerSideHeader)
 	o.EncodeResponse(w, req, &serde.CompleteMultipartUploadResult{
-		Location: location,
+		Location: objectLocation(req, o),
 		Bucket:   o.Repository.Name,
 		Key:      path.WithRef(o.Path, o.Reference),
 		ETag:     httputil.ETag(resp.ETag),
 	}, http.StatusOK)
 }
 
+// objectLocation returns the URL of the object of the operation, as addressed by the request
+func objectLocation(req *http.Request, o *PathOperation) string {
+	scheme := httputil.RequestScheme(req)
+	if o.MatchedHost {
+		return fmt.Sprintf("%s://%s/%s/%s", scheme, req.Host, o.Reference, o.Path)
+	}
+	return fmt.Sprintf("%s://%s/%s/%s/%s", scheme, req.Host, o.Repository.Name, o.Reference, o.Path)
+}
+
 // normalizeMultipartUploadCompletion normalization incoming multipart upload completion list.
 // we make sure that each part's ETag will be without the wrapping quotes
 func normalizeMultipartUploadCompletion(list *block.MultipartUploadCompletion) {
@@ -167,15 +200,21 @@ func normalizeMultipartUploadCompletion(list *block.MultipartUploadCompletion) {
 }
 
 func (controller *PostObject) Handle(w http.ResponseWriter, req *http.Request, o *PathOperation) {
-	if o.HandleUnsupported(w, req, "select", "restore") {
+	if o.HandleUnsupported(w, req, "restore") {
 		return
 	}
 
-	// POST is only supported for CreateMultipartUpload/CompleteMultipartUpload
+	// POST is only supported for CreateMultipartUpload/CompleteMultipartUpload, SelectObjectContent and form uploads
 	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_CreateMultipartUpload.html
 	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_CompleteMultipartUpload.html
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_SelectObjectContent.html
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectPOST.html
 	query := req.URL.Query()
 	switch {
+	case o.PostForm != nil:
+		handlePostFormUpload(w, req, o)
+	case query.Has(QueryParamSelect):
+		handleSelectObjectContent(w, req, o)
 	case query.Has(CreateMultipartUploadQueryParam):
 		controller.HandleCreateMultipartUpload(w, req, o)
 	case query.Has(CompleteMultipartUploadQueryParam):
@@ -4,12 +4,14 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	gatewayErrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/multipart"
 	"github.com/treeverse/lakefs/pkg/gateway/path"
 	"github.com/treeverse/lakefs/pkg/gateway/serde"
 	"github.com/treeverse/lakefs/pkg/graveler"
@@ -24,6 +26,8 @@ const (
 	CopySourceRangeHeader = "x-amz-copy-source-range"
 	QueryParamUploadID    = "uploadId"
 	QueryParamPartNumber  = "partNumber"
+	IfMatchHeader         = "If-Match"
+	IfNoneMatchHeader     = "If-None-Match"
 )
 
 type PutObject struct{}
@@ -103,8 +107,16 @@ func handleCopy(w http.ResponseWriter, req *http.Request, o *PathOperation, copy
 		return
 	}
 
+	setOpts, ok := writePreconditions(w, req, o)
+	if !ok {
+		return
+	}
 	ctx := req.Context()
-	entry, err := o.Catalog.CopyEntry(ctx, srcPath.Repo, srcPath.Reference, srcPath.Path, repository, branch, o.Path)
+	entry, err := o.Catalog.CopyEntry(ctx, srcPath.Repo, srcPath.Reference, srcPath.Path, repository, branch, o.Path, catalog.CopyEntryParams{}, setOpts...)
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+		return
+	}
 	if err != nil {
 		o.Log(req).WithError(err).Error("could create a copy")
 		apiErr := gatewayErrors.Codes.ToAPIErrWithInternalError(gatewayErrors.ErrInvalidCopyDest, err)
@@ -120,6 +132,12 @@ func handleCopy(w http.ResponseWriter, req *http.Request, o *PathOperation, copy
 
 func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 	o.Incr("put_mpu_part", o.Principal, o.Repository.Name, o.Reference)
+	if hasWritePreconditions(req) {
+		// parts are not objects, conditions on the object are set when the upload is completed
+		o.Log(req).Debug("conditional write headers on upload part")
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNotImplemented))
+		return
+	}
 	query := req.URL.Query()
 	uploadID := query.Get(QueryParamUploadID)
 	partNumberStr := query.Get(QueryParamPartNumber)
@@ -174,18 +192,22 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 		}
 
 		src := block.ObjectPointer{
+			StorageID:        srcRepo.StorageID,
 			StorageNamespace: srcRepo.StorageNamespace,
 			IdentifierType:   ent.AddressType.ToIdentifierType(),
 			Identifier:       ent.PhysicalAddress,
+			Compression:      ent.Metadata.Compression(),
 		}
 
 		dst := block.ObjectPointer{
+			StorageID:        o.Repository.StorageID,
 			StorageNamespace: o.Repository.StorageNamespace,
 			IdentifierType:   block.IdentifierTypeRelative,
 			Identifier:       multiPart.PhysicalAddress,
 		}
 
 		var resp *block.UploadPartResponse
+		partSize := ent.Size
 		if rang := req.Header.Get(CopySourceRangeHeader); rang != "" {
 			// if this is a copy part with a byte range:
 			parsedRange, parseErr := httputil.ParseRange(rang, ent.Size)
@@ -193,6 +215,7 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 				// invalid range will silently fall back to copying the entire object. ¯\_(ツ)_/¯
 				resp, err = o.BlockStore.UploadCopyPart(req.Context(), src, dst, uploadID, partNumber)
 			} else {
+				partSize = parsedRange.Size()
 				resp, err = o.BlockStore.UploadCopyPartRange(req.Context(), src, dst, uploadID, partNumber, parsedRange.StartOffset, parsedRange.EndOffset)
 			}
 		} else {
@@ -211,9 +234,13 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 			_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 			return
 		}
+		lastModified := time.Now()
+		if !trackUploadPart(w, req, o, uploadID, partNumber, resp.ETag, partSize, lastModified) {
+			return
+		}
 
 		o.EncodeResponse(w, req, &serde.CopyObjectResult{
-			LastModified: serde.Timestamp(time.Now()),
+			LastModified: serde.Timestamp(lastModified),
 			ETag:         httputil.ETag(resp.ETag),
 		}, http.StatusOK)
 		return
@@ -221,6 +248,7 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 
 	byteSize := req.ContentLength
 	resp, err := o.BlockStore.UploadPart(req.Context(), block.ObjectPointer{
+		StorageID:        o.Repository.StorageID,
 		StorageNamespace: o.Repository.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       multiPart.PhysicalAddress,
@@ -232,6 +260,9 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
 	}
+	if !trackUploadPart(w, req, o, uploadID, partNumber, resp.ETag, byteSize, time.Now()) {
+		return
+	}
 	o.SetHeaders(w, resp.ServerSideHeader)
 	o.SetHeader(w, "ETag", httputil.ETag(resp.ETag))
 	w.WriteHeader(http.StatusOK)
@@ -276,9 +307,8 @@ func (controller *PutObject) Handle(w http.ResponseWriter, req *http.Request, o
 		return
 	}
 
-	if query.Has("tagging") {
-		o.Log(req).Debug("put-object-tagging isn't supported yet")
-		o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ERRLakeFSNotSupported))
+	if query.Has(QueryParamTagging) {
+		handlePutObjectTagging(w, req, o)
 		return
 	}
 
@@ -286,22 +316,114 @@ func (controller *PutObject) Handle(w http.ResponseWriter, req *http.Request, o
 	handlePut(w, req, o)
 }
 
+// trackUploadPart records an uploaded part of a multipart upload for listing its parts, it returns false after
+// failing the request in case it could not
+func trackUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation, uploadID string, partNumber int, etag string, size int64, lastModified time.Time) bool {
+	err := o.MultipartTracker.CreatePart(req.Context(), multipart.Part{
+		UploadID:     uploadID,
+		PartNumber:   partNumber,
+		ETag:         etag,
+		Size:         size,
+		LastModified: lastModified,
+	})
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not write multipart upload part to DB")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return false
+	}
+	return true
+}
+
+// hasWritePreconditions reports whether req carries conditional write headers
+func hasWritePreconditions(req *http.Request) bool {
+	return req.Header.Get(IfNoneMatchHeader) != "" || req.Header.Get(IfMatchHeader) != ""
+}
+
+// writePreconditions returns the set options of the conditional write headers of req, If-None-Match: * and
+// If-Match. It returns false after failing the request in case the conditions are not supported or already do not
+// hold, before any data is uploaded. The conditions are verified again when the object is written. Used by the
+// requests writing an object: PutObject, CopyObject and CompleteMultipartUpload.
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/conditional-writes.html
+func writePreconditions(w http.ResponseWriter, req *http.Request, o *PathOperation) ([]graveler.SetOptionsFunc, bool) {
+	ifNoneMatch := req.Header.Get(IfNoneMatchHeader)
+	ifMatch := httputil.ParseETagList(req.Header.Get(IfMatchHeader))
+	if ifNoneMatch == "" && len(ifMatch) == 0 {
+		return nil, true
+	}
+	if ifNoneMatch != "" && ifNoneMatch != catalog.ETagMatchAny {
+		o.Log(req).WithField("if_none_match", ifNoneMatch).Debug("unsupported If-None-Match value")
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNotImplemented))
+		return nil, false
+	}
+
+	entry, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		o.Log(req).WithError(err).Error("could not get entry for conditional write")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return nil, false
+	}
+	var opts []graveler.SetOptionsFunc
+	if ifNoneMatch != "" {
+		if entry != nil {
+			_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+			return nil, false
+		}
+		opts = append(opts, graveler.WithIfAbsent(true))
+	}
+	if len(ifMatch) > 0 {
+		if entry == nil {
+			_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNoSuchKey))
+			return nil, false
+		}
+		if !slices.Contains(ifMatch, catalog.ETagMatchAny) && !slices.Contains(ifMatch, entry.Checksum) {
+			_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+			return nil, false
+		}
+		opts = append(opts, catalog.WithIfMatch(ifMatch...))
+	}
+	return opts, true
+}
+
 func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 	o.Incr("put_object", o.Principal, o.Repository.Name, o.Reference)
+	setOpts, ok := writePreconditions(w, req, o)
+	if !ok {
+		return
+	}
+	metadata, err := amzTaggingAsMetadata(req, amzMetaAsMetadata(req))
+	if err != nil {
+		o.Log(req).WithError(err).Debug("invalid object tagging")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidTag))
+		return
+	}
 	storageClass := StorageClassFromHeader(req.Header)
-	opts := block.PutOpts{StorageClass: storageClass}
+	contentType := req.Header.Get("Content-Type")
+	codec, err := o.Catalog.CompressionCodec(req.Context(), o.Repository.Name, o.Path, contentType)
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not get compression rules")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return
+	}
+	opts := block.PutOpts{StorageClass: storageClass, Compression: codec}
 	address := o.PathProvider.NewPath()
-	blob, err := upload.WriteBlob(req.Context(), o.BlockStore, o.Repository.StorageNamespace, address, req.Body, req.ContentLength, opts)
+	blob, err := upload.WriteBlob(req.Context(), o.BlockStore, o.Repository.StorageID, o.Repository.StorageNamespace, address, req.Body, req.ContentLength, opts)
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not write request body to block adapter")
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
 	}
+	// objects written with a storage class keep their own copy
+	if storageClass == nil {
+		blob, err = o.Catalog.DedupBlob(req.Context(), o.Repository.Name, o.Path, blob)
+		if err != nil {
+			o.Log(req).WithError(err).Error("could not deduplicate uploaded object")
+			_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+			return
+		}
+	}
 
 	// write metadata
-	metadata := amzMetaAsMetadata(req)
-	contentType := req.Header.Get("Content-Type")
-	err = o.finishUpload(req, blob.Checksum, blob.PhysicalAddress, blob.Size, true, metadata, contentType)
+	err = o.finishUpload(req, blob.Checksum, blob.PhysicalAddress, blob.Size, true, metadata.WithCompression(blob.Compression), contentType, setOpts...)
 	if errors.Is(err, graveler.ErrWriteToProtectedBranch) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToProtectedBranch))
 		return
@@ -310,6 +432,15 @@ func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrReadOnlyRepository))
 		return
 	}
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+		return
+	}
+	if errors.Is(err, graveler.ErrNotFound) && len(setOpts) > 0 {
+		// the object matched by If-Match was deleted since
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNoSuchKey))
+		return
+	}
 	if err != nil {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
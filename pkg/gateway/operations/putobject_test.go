@@ -5,11 +5,19 @@ import (
 	"context"
 	"crypto/md5" //nolint:gosec
 	"crypto/rand"
+	"encoding/xml"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
 	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
 	"github.com/treeverse/lakefs/pkg/upload"
 )
 
@@ -46,7 +54,7 @@ func TestWriteBlob(t *testing.T) {
 			adapter := newMockAdapter()
 			opts := block.PutOpts{StorageClass: tc.storageClass}
 			address := upload.DefaultPathProvider.NewPath()
-			blob, err := upload.WriteBlob(context.Background(), adapter, bucketName, address, reader, tc.size, opts)
+			blob, err := upload.WriteBlob(context.Background(), adapter, "", bucketName, address, reader, tc.size, opts)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -78,3 +86,119 @@ func TestWriteBlob(t *testing.T) {
 		})
 	}
 }
+
+func TestPutObject_Conditional(t *testing.T) {
+	op, repository := setupOperation(t)
+	put := func(t *testing.T, path, content string, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPut, "/repo/main/"+path, strings.NewReader(content))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rr := httptest.NewRecorder()
+		controller := &operations.PutObject{}
+		controller.Handle(rr, req, newPathOperation(op, repository, path))
+		return rr
+	}
+
+	t.Run("if_none_match", func(t *testing.T) {
+		header := http.Header{operations.IfNoneMatchHeader: {"*"}}
+		rr := put(t, "log/0001.json", "first", header)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		rr = put(t, "log/0001.json", "second", header)
+		require.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		require.Contains(t, rr.Body.String(), "PreconditionFailed")
+	})
+
+	t.Run("if_none_match_unsupported", func(t *testing.T) {
+		rr := put(t, "log/0002.json", "data", http.Header{operations.IfNoneMatchHeader: {`"etag"`}})
+		require.Equal(t, http.StatusNotImplemented, rr.Code)
+	})
+
+	t.Run("if_match", func(t *testing.T) {
+		rr := put(t, "data/object", "v1", nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		etag := rr.Header()["ETag"][0]
+
+		rr = put(t, "data/object", "v2", http.Header{operations.IfMatchHeader: {etag}})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		// the object changed, its previous ETag no longer matches
+		rr = put(t, "data/object", "v3", http.Header{operations.IfMatchHeader: {etag}})
+		require.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		rr = put(t, "data/object", "v3", http.Header{operations.IfMatchHeader: {"*"}})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+
+	t.Run("if_match_no_object", func(t *testing.T) {
+		rr := put(t, "data/missing", "data", http.Header{operations.IfMatchHeader: {"*"}})
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Contains(t, rr.Body.String(), "NoSuchKey")
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		rr := put(t, "copy/source", "source", nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		header := http.Header{
+			operations.IfNoneMatchHeader:                         {"*"},
+			http.CanonicalHeaderKey(operations.CopySourceHeader): {"repo/main/copy/source"},
+		}
+		rr = put(t, "copy/dest", "", header)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		rr = put(t, "copy/dest", "", header)
+		require.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	})
+
+	t.Run("multipart_upload", func(t *testing.T) {
+		const path = "multipart/object"
+		controller := &operations.PostObject{}
+		completeUpload := func(t *testing.T, header http.Header) *httptest.ResponseRecorder {
+			t.Helper()
+			req := httptest.NewRequest(http.MethodPost, "/repo/main/"+path+"?uploads", nil)
+			rr := httptest.NewRecorder()
+			controller.Handle(rr, req, newPathOperation(op, repository, path))
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			var created serde.InitiateMultipartUploadResult
+			require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &created))
+
+			rr = put(t, path+"?partNumber=1&uploadId="+created.UploadID, "part", header)
+			// conditions are set on completing the upload, not on its parts
+			require.Equal(t, http.StatusNotImplemented, rr.Code)
+			rr = put(t, path+"?partNumber=1&uploadId="+created.UploadID, "part", nil)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+			complete := fmt.Sprintf(`<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>%s</ETag></Part></CompleteMultipartUpload>`, rr.Header().Get("ETag"))
+			req = httptest.NewRequest(http.MethodPost, "/repo/main/"+path+"?uploadId="+created.UploadID, strings.NewReader(complete))
+			for k, v := range header {
+				req.Header[k] = v
+			}
+			rr = httptest.NewRecorder()
+			controller.Handle(rr, req, newPathOperation(op, repository, path))
+			return rr
+		}
+
+		header := http.Header{operations.IfNoneMatchHeader: {"*"}}
+		rr := completeUpload(t, header)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		rr = completeUpload(t, header)
+		require.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	})
+}
+
+func TestPutObject_ReservedMetadata(t *testing.T) {
+	op, repository := setupOperation(t)
+	const path = "data/object"
+	req := httptest.NewRequest(http.MethodPut, "/repo/main/"+path, strings.NewReader("data"))
+	// only amazon user metadata headers are kept, their keys never match the keys reserved by the catalog
+	req.Header[catalog.CompressionMetadataKey] = []string{"gzip"}
+	req.Header[catalog.ObjectTagMetadataPrefix+"env"] = []string{"prod"}
+	req.Header.Set(apiutil.LakeFSHeaderInternalPrefix+"compression", "gzip")
+	req.Header.Set("X-Amz-Meta-Color", "red")
+	rr := httptest.NewRecorder()
+	controller := &operations.PutObject{}
+	controller.Handle(rr, req, newPathOperation(op, repository, path))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	entry, err := op.Catalog.GetEntry(context.Background(), repository.Name, "main", path, catalog.GetEntryParams{})
+	require.NoError(t, err)
+	require.Equal(t, catalog.Metadata{"X-Amz-Meta-Color": "red"}, entry.Metadata)
+}
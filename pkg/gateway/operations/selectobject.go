@@ -0,0 +1,176 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/s3select"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const QueryParamSelect = "select"
+
+// selectReadAheadSize is the least size of a range read from the block adapter by selectObject.ReadAt. Parquet
+// readers make many small reads of the footer and of column chunks, each serving from a buffered range instead of a
+// separate block adapter request.
+const selectReadAheadSize = 1024 * 1024
+
+// selectObject is the content of an object read from the block adapter
+type selectObject struct {
+	ctx     context.Context
+	adapter block.Adapter
+	pointer block.ObjectPointer
+	size    int64
+
+	mu sync.Mutex
+	// buf holds the content of the object starting at bufOffset, read by the last range read
+	buf       []byte
+	bufOffset int64
+}
+
+func (s *selectObject) Open() (io.ReadCloser, error) {
+	return s.adapter.Get(s.ctx, s.pointer, s.size)
+}
+
+func (s *selectObject) ReadAt(p []byte, off int64) (int, error) {
+	if off >= s.size {
+		return 0, io.EOF
+	}
+	end := min(off+int64(len(p)), s.size)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if off < s.bufOffset || end > s.bufOffset+int64(len(s.buf)) {
+		if err := s.fill(off, end); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.buf[off-s.bufOffset:end-s.bufOffset])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fill reads a range of the object holding [off, end) into the buffer. Ranges are read ahead up to
+// selectReadAheadSize, a range ending at the end of the object is extended backwards so that the reads of the
+// Parquet footer are served by a single range read.
+func (s *selectObject) fill(off, end int64) error {
+	start := off
+	if end-start < selectReadAheadSize {
+		end = min(start+selectReadAheadSize, s.size)
+		start = max(0, min(start, end-selectReadAheadSize))
+	}
+	rc, err := s.adapter.GetRange(s.ctx, s.pointer, start, end-1)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	buf := make([]byte, end-start)
+	if _, err := io.ReadFull(rc, buf); err != nil {
+		return err
+	}
+	s.buf = buf
+	s.bufOffset = start
+	return nil
+}
+
+func (s *selectObject) Size() int64 {
+	return s.size
+}
+
+// selectErrorCode returns the code of the error response to a select request failing with err
+func selectErrorCode(err error) gatewayerrors.APIErrorCode {
+	switch {
+	case errors.Is(err, block.ErrRestoreRequired):
+		return gatewayerrors.ErrInvalidObjectState
+	case errors.Is(err, block.ErrDataNotFound):
+		return gatewayerrors.ErrNoSuchVersion
+	case errors.Is(err, s3select.ErrInvalidExpressionType):
+		return gatewayerrors.ErrInvalidExpressionType
+	case errors.Is(err, s3select.ErrSyntax):
+		return gatewayerrors.ErrUnsupportedSyntax
+	case errors.Is(err, s3select.ErrInvalidCompressionFormat):
+		return gatewayerrors.ErrInvalidCompressionFormat
+	case errors.Is(err, s3select.ErrInvalidDataSource):
+		return gatewayerrors.ErrInvalidDataSource
+	case errors.Is(err, s3select.ErrInvalidRequestParameter),
+		errors.Is(err, s3select.ErrCSVParsing),
+		errors.Is(err, s3select.ErrJSONParsing),
+		errors.Is(err, s3select.ErrParquetParsing):
+		return gatewayerrors.ErrInvalidRequestParameter
+	default:
+		return gatewayerrors.ErrInternalError
+	}
+}
+
+// handleSelectObjectContent evaluates the SQL expression of the request over the records of the object, streaming
+// the results as an event stream.
+func handleSelectObjectContent(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("select_object_content", o.Principal, o.Repository.Name, o.Reference)
+	var selectReq serde.SelectObjectContentRequest
+	if err := DecodeXMLBody(req.Body, &selectReq); err != nil {
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrMalformedXML))
+		return
+	}
+	selector, err := s3select.NewSelector(&selectReq)
+	if err != nil {
+		o.Log(req).WithError(err).Debug("invalid select request")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(selectErrorCode(err)))
+		return
+	}
+
+	ctx := req.Context()
+	ref, ok := objectVersionReference(o.Reference, req.URL.Query().Get(QueryParamVersionID))
+	if !ok {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
+		return
+	}
+	entry, err := o.Catalog.GetEntry(ctx, o.Repository.Name, ref, o.Path, catalog.GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		code := gatewayerrors.ErrNoSuchKey
+		if ref != o.Reference {
+			code = gatewayerrors.ErrNoSuchVersion
+		}
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(code))
+		return
+	}
+	if errors.Is(err, catalog.ErrExpired) {
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
+		return
+	}
+	if err != nil {
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+
+	results, err := selector.Select(&selectObject{
+		ctx:     ctx,
+		adapter: o.BlockStore,
+		pointer: block.ObjectPointer{
+			StorageID:        o.Repository.StorageID,
+			StorageNamespace: o.Repository.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+			Compression:      entry.Metadata.Compression(),
+		},
+		size: entry.Size,
+	})
+	if err != nil {
+		o.Log(req).WithError(err).Debug("could not read object for select")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(selectErrorCode(err)))
+		return
+	}
+	defer func() { _ = results.Close() }()
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := results.WriteTo(w); err != nil {
+		o.Log(req).WithError(err).Warn("select object content failed while streaming results")
+	}
+}
@@ -0,0 +1,156 @@
+package operations_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+// rangeCountingAdapter counts the range reads of the adapter
+type rangeCountingAdapter struct {
+	block.Adapter
+	rangeReads atomic.Int64
+}
+
+func (a *rangeCountingAdapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	a.rangeReads.Add(1)
+	return a.Adapter.GetRange(ctx, obj, startPosition, endPosition)
+}
+
+func TestSelectObjectContent(t *testing.T) {
+	op, repository := setupOperation(t)
+	adapter := &rangeCountingAdapter{Adapter: op.BlockStore}
+	op.BlockStore = adapter
+
+	putObject := func(t *testing.T, path string, data []byte) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPut, "/repo/main/"+path, bytes.NewReader(data))
+		rr := httptest.NewRecorder()
+		controller := &operations.PutObject{}
+		controller.Handle(rr, req, newPathOperation(op, repository, path))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+	selectObject := func(t *testing.T, path string, selectReq serde.SelectObjectContentRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := xml.Marshal(selectReq)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/repo/main/"+path+"?select&select-type=2", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+		controller := &operations.PostObject{}
+		controller.Handle(rr, req, newPathOperation(op, repository, path))
+		return rr
+	}
+	// readRecords returns the records of the event stream, which must end with an End event
+	readRecords := func(t *testing.T, rr *httptest.ResponseRecorder) string {
+		t.Helper()
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		decoder := eventstream.NewDecoder()
+		var records bytes.Buffer
+		for {
+			msg, err := decoder.Decode(rr.Body, nil)
+			require.NoError(t, err)
+			require.Equal(t, "event", msg.Headers.Get(":message-type").String(), msg.Headers.Get(":error-message"))
+			switch msg.Headers.Get(":event-type").String() {
+			case "Records":
+				records.Write(msg.Payload)
+			case "End":
+				return records.String()
+			}
+		}
+	}
+
+	putObject(t, "data/people.csv", []byte("name,age\nalice,34\nbob,27\ncarol,45\n"))
+	parquetData, err := os.ReadFile("../s3select/testdata/000.snappy.parquet")
+	require.NoError(t, err)
+	putObject(t, "data/places.parquet", parquetData)
+
+	t.Run("csv", func(t *testing.T) {
+		rr := selectObject(t, "data/people.csv", serde.SelectObjectContentRequest{
+			Expression:          "SELECT s.name FROM S3Object s WHERE CAST(s.age AS INT) > 30",
+			ExpressionType:      "SQL",
+			InputSerialization:  serde.InputSerialization{CSV: &serde.CSVInput{FileHeaderInfo: "USE"}},
+			OutputSerialization: serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+		})
+		require.Equal(t, "{\"name\":\"alice\"}\n{\"name\":\"carol\"}\n", readRecords(t, rr))
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		rr := selectObject(t, "data/places.parquet", serde.SelectObjectContentRequest{
+			Expression:          "SELECT COUNT(*) FROM S3Object s WHERE s.country_code = 'US'",
+			ExpressionType:      "SQL",
+			InputSerialization:  serde.InputSerialization{Parquet: &serde.ParquetInput{}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		})
+		require.Equal(t, "34\n", readRecords(t, rr))
+	})
+
+	t.Run("parquet_buffered_reads", func(t *testing.T) {
+		adapter.rangeReads.Store(0)
+		rr := selectObject(t, "data/places.parquet", serde.SelectObjectContentRequest{
+			Expression:          "SELECT s.country_code FROM S3Object s",
+			ExpressionType:      "SQL",
+			InputSerialization:  serde.InputSerialization{Parquet: &serde.ParquetInput{}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		})
+		readRecords(t, rr)
+		// the object is smaller than the read ahead size, its content is read once
+		require.Equal(t, int64(1), adapter.rangeReads.Load())
+	})
+
+	t.Run("unsupported_syntax", func(t *testing.T) {
+		rr := selectObject(t, "data/people.csv", serde.SelectObjectContentRequest{
+			Expression:          "SELECT * FROM people",
+			ExpressionType:      "SQL",
+			InputSerialization:  serde.InputSerialization{CSV: &serde.CSVInput{}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		})
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "UnsupportedSyntax")
+	})
+
+	t.Run("archived", func(t *testing.T) {
+		const path = "data/archived.csv"
+		putObject(t, path, []byte("name,age\nalice,34\n"))
+		entry, err := op.Catalog.GetEntry(context.Background(), repository.Name, "main", path, catalog.GetEntryParams{})
+		require.NoError(t, err)
+		require.NoError(t, op.BlockStore.SetStorageClass(context.Background(), block.ObjectPointer{
+			StorageID:        repository.StorageID,
+			StorageNamespace: repository.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		}, block.StorageClassGlacier))
+		// reading an archived object fails as it does for GetObject
+		rr := selectObject(t, path, serde.SelectObjectContentRequest{
+			Expression:          "SELECT * FROM S3Object",
+			ExpressionType:      "SQL",
+			InputSerialization:  serde.InputSerialization{CSV: &serde.CSVInput{}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		})
+		require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+		require.Contains(t, rr.Body.String(), "InvalidObjectState")
+	})
+
+	t.Run("no_such_key", func(t *testing.T) {
+		rr := selectObject(t, "data/missing.csv", serde.SelectObjectContentRequest{
+			Expression:          "SELECT * FROM S3Object",
+			ExpressionType:      "SQL",
+			InputSerialization:  serde.InputSerialization{CSV: &serde.CSVInput{}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		})
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Contains(t, rr.Body.String(), "NoSuchKey")
+	})
+}
@@ -0,0 +1,90 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvparams"
+	_ "github.com/treeverse/lakefs/pkg/kv/mem"
+	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+// openStore returns a mem kv store closed at the end of the test
+func openStore(t *testing.T) kv.Store {
+	t.Helper()
+	store, err := kv.Open(context.Background(), kvparams.Config{Type: "mem"})
+	testutil.MustDo(t, "open kv store", err)
+	t.Cleanup(store.Close)
+	return store
+}
+
+// setupCatalog returns a catalog over a mem kv store and block adapter, with a repository to test operations on
+func setupCatalog(t *testing.T) (*catalog.Catalog, *catalog.Repository) {
+	t.Helper()
+	ctx := context.Background()
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	conf, err := config.NewConfig("")
+	testutil.MustDo(t, "config", err)
+	c, err := catalog.New(ctx, catalog.Config{
+		Config:       conf,
+		KVStore:      openStore(t),
+		PathProvider: upload.DefaultPathProvider,
+	})
+	testutil.MustDo(t, "build catalog", err)
+	t.Cleanup(func() { _ = c.Close() })
+	repository, err := c.CreateRepository(ctx, "repo", "", "mem://repo", "main", false)
+	testutil.MustDo(t, "create repository", err)
+	return c, repository
+}
+
+// pathOperation returns an operation on path of the main branch of repository
+func pathOperation(c *catalog.Catalog, repository *catalog.Repository, path string) *operations.PathOperation {
+	return newPathOperation(&operations.Operation{
+		Catalog:      c,
+		BlockStore:   mem.New(context.Background()),
+		PathProvider: upload.DefaultPathProvider,
+		Incr:         func(_, _, _, _ string) {},
+	}, repository, path)
+}
+
+// setupOperation returns an operation on the catalog returned by setupCatalog, with a mem block adapter and a
+// multipart tracker over a mem kv store
+func setupOperation(t *testing.T) (*operations.Operation, *catalog.Repository) {
+	t.Helper()
+	c, repository := setupCatalog(t)
+	return &operations.Operation{
+		Catalog:          c,
+		MultipartTracker: multipart.NewTracker(openStore(t)),
+		BlockStore:       mem.New(context.Background()),
+		PathProvider:     upload.DefaultPathProvider,
+		Incr:             func(_, _, _, _ string) {},
+	}, repository
+}
+
+// newRepoOperation returns an operation of op on repository
+func newRepoOperation(op *operations.Operation, repository *catalog.Repository) *operations.RepoOperation {
+	return &operations.RepoOperation{
+		AuthorizedOperation: &operations.AuthorizedOperation{Operation: op},
+		Repository:          repository,
+	}
+}
+
+// newPathOperation returns an operation of op on path of the main branch of repository
+func newPathOperation(op *operations.Operation, repository *catalog.Repository, path string) *operations.PathOperation {
+	return &operations.PathOperation{
+		RefOperation: &operations.RefOperation{
+			RepoOperation: newRepoOperation(op, repository),
+			Reference:     "main",
+		},
+		Path: path,
+	}
+}
@@ -0,0 +1,646 @@
+package s3select
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrEvaluation = errors.New("evaluation error")
+
+// evaluator evaluates the expressions of a query over a record, keeping the state of its aggregate functions
+type evaluator struct {
+	query      *Query
+	aggregates map[*funcExpr]*aggregateState
+	likes      map[*likeExpr]*regexp.Regexp
+}
+
+type aggregateState struct {
+	count    int64
+	sum      any
+	min, max any
+}
+
+func newEvaluator(q *Query) *evaluator {
+	return &evaluator{
+		query:      q,
+		aggregates: make(map[*funcExpr]*aggregateState),
+		likes:      make(map[*likeExpr]*regexp.Regexp),
+	}
+}
+
+// match reports whether the record matches the WHERE condition of the query
+func (ev *evaluator) match(r *Record) (bool, error) {
+	if ev.query.where == nil {
+		return true, nil
+	}
+	v, err := ev.eval(ev.query.where, r)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	return ok && b, nil
+}
+
+// project returns the projection of the record, or the record itself when selecting all its fields
+func (ev *evaluator) project(r *Record) (*Record, error) {
+	if len(ev.query.projections) == 0 {
+		return r, nil
+	}
+	out := &Record{}
+	for _, proj := range ev.query.projections {
+		v, err := ev.eval(proj.expr, r)
+		if err != nil {
+			return nil, err
+		}
+		out.Add(proj.name, v)
+	}
+	return out, nil
+}
+
+// accumulate updates the aggregate functions of the query with the record
+func (ev *evaluator) accumulate(r *Record) error {
+	var err error
+	for _, proj := range ev.query.projections {
+		walk(proj.expr, func(e expr) bool {
+			f, ok := e.(*funcExpr)
+			if !ok || !f.isAggregate() || err != nil {
+				return err == nil
+			}
+			err = ev.accumulateFunc(f, r)
+			return false
+		})
+	}
+	return err
+}
+
+func (ev *evaluator) accumulateFunc(f *funcExpr, r *Record) error {
+	state := ev.aggregates[f]
+	if state == nil {
+		state = &aggregateState{}
+		ev.aggregates[f] = state
+	}
+	if f.star {
+		state.count++
+		return nil
+	}
+	v, err := ev.eval(f.args[0], r)
+	if err != nil || v == nil {
+		return err
+	}
+	state.count++
+	switch f.name {
+	case "SUM", "AVG":
+		n, ok := toNumber(v)
+		if !ok {
+			return fmt.Errorf("%w: %s of non-numeric value", ErrEvaluation, f.name)
+		}
+		if state.sum == nil {
+			state.sum = n
+		} else if state.sum, err = arithmetic("+", state.sum, n); err != nil {
+			return err
+		}
+	case "MIN", "MAX":
+		if s, ok := v.(string); ok {
+			if n, ok := toNumber(s); ok {
+				v = n
+			}
+		}
+		current := state.min
+		if f.name == "MAX" {
+			current = state.max
+		}
+		if current != nil {
+			c, ok := compare(v, current)
+			if !ok || (f.name == "MIN" && c >= 0) || (f.name == "MAX" && c <= 0) {
+				return nil
+			}
+		}
+		if f.name == "MIN" {
+			state.min = v
+		} else {
+			state.max = v
+		}
+	}
+	return nil
+}
+
+func (ev *evaluator) aggregateValue(f *funcExpr) any {
+	state := ev.aggregates[f]
+	if state == nil {
+		state = &aggregateState{}
+	}
+	switch f.name {
+	case "COUNT":
+		return state.count
+	case "SUM":
+		return state.sum
+	case "AVG":
+		if state.count == 0 {
+			return nil
+		}
+		sum, _ := toFloat(state.sum)
+		return sum / float64(state.count)
+	case "MIN":
+		return state.min
+	case "MAX":
+		return state.max
+	}
+	return nil
+}
+
+func (ev *evaluator) eval(e expr, r *Record) (any, error) {
+	switch e := e.(type) {
+	case *literal:
+		return e.value, nil
+	case *reference:
+		return ev.resolve(e, r), nil
+	case *unaryExpr:
+		v, err := ev.eval(e.operand, r)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		if e.op == "NOT" {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%w: NOT of non-boolean value", ErrEvaluation)
+			}
+			return !b, nil
+		}
+		return arithmetic("-", int64(0), v)
+	case *binaryExpr:
+		return ev.evalBinary(e, r)
+	case *isNullExpr:
+		v, err := ev.eval(e.operand, r)
+		if err != nil {
+			return nil, err
+		}
+		return (v == nil) != e.not, nil
+	case *likeExpr:
+		return ev.evalLike(e, r)
+	case *betweenExpr:
+		v, low, high, err := ev.eval3(e.operand, e.low, e.high, r)
+		if err != nil || v == nil || low == nil || high == nil {
+			return nil, err
+		}
+		c1, ok1 := compare(v, low)
+		c2, ok2 := compare(v, high)
+		if !ok1 || !ok2 {
+			return nil, nil
+		}
+		return (c1 >= 0 && c2 <= 0) != e.not, nil
+	case *inExpr:
+		v, err := ev.eval(e.operand, r)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		for _, item := range e.list {
+			iv, err := ev.eval(item, r)
+			if err != nil {
+				return nil, err
+			}
+			if c, ok := compare(v, iv); ok && c == 0 {
+				return !e.not, nil
+			}
+		}
+		return e.not, nil
+	case *castExpr:
+		v, err := ev.eval(e.operand, r)
+		if err != nil {
+			return nil, err
+		}
+		return cast(v, e.typ)
+	case *funcExpr:
+		if e.isAggregate() {
+			return ev.aggregateValue(e), nil
+		}
+		return ev.evalFunc(e, r)
+	}
+	return nil, fmt.Errorf("%w: unknown expression %T", ErrEvaluation, e)
+}
+
+func (ev *evaluator) eval3(a, b, c expr, r *Record) (any, any, any, error) {
+	va, err := ev.eval(a, r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vb, err := ev.eval(b, r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vc, err := ev.eval(c, r)
+	return va, vb, vc, err
+}
+
+// resolve returns the value referenced in the record, nil (MISSING) in case there is none
+func (ev *evaluator) resolve(ref *reference, r *Record) any {
+	path := ref.path
+	if len(path) > 1 && !path[0].isIndex && !path[0].quoted && strings.EqualFold(path[0].name, ev.query.alias) {
+		path = path[1:]
+	}
+	var v any = r
+	for _, elem := range path {
+		switch current := v.(type) {
+		case *Record:
+			if elem.isIndex {
+				return nil
+			}
+			var ok bool
+			if v, ok = current.Get(elem.name, elem.quoted); !ok {
+				return nil
+			}
+		case []any:
+			if !elem.isIndex || elem.index >= len(current) {
+				return nil
+			}
+			v = current[elem.index]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func (ev *evaluator) evalBinary(e *binaryExpr, r *Record) (any, error) {
+	left, err := ev.eval(e.left, r)
+	if err != nil {
+		return nil, err
+	}
+	if e.op == "AND" || e.op == "OR" {
+		// three-valued logic, evaluating the right side only when needed
+		lb, lok := left.(bool)
+		if lok && ((e.op == "AND" && !lb) || (e.op == "OR" && lb)) {
+			return lb, nil
+		}
+		right, err := ev.eval(e.right, r)
+		if err != nil {
+			return nil, err
+		}
+		rb, rok := right.(bool)
+		switch {
+		case rok && e.op == "AND" && !rb:
+			return false, nil
+		case rok && e.op == "OR" && rb:
+			return true, nil
+		case lok && rok:
+			return rb, nil
+		}
+		return nil, nil
+	}
+	right, err := ev.eval(e.right, r)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	switch e.op {
+	case "=", "!=", "<", "<=", ">", ">=":
+		c, ok := compare(left, right)
+		if !ok {
+			return nil, nil
+		}
+		switch e.op {
+		case "=":
+			return c == 0, nil
+		case "!=":
+			return c != 0, nil
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "||":
+		return toString(left) + toString(right), nil
+	}
+	return arithmetic(e.op, left, right)
+}
+
+func (ev *evaluator) evalLike(e *likeExpr, r *Record) (any, error) {
+	v, err := ev.eval(e.operand, r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	re := ev.likes[e]
+	if re == nil {
+		pattern, err := ev.eval(e.pattern, r)
+		if err != nil || pattern == nil {
+			return nil, err
+		}
+		escape := ""
+		if e.escape != nil {
+			ve, err := ev.eval(e.escape, r)
+			if err != nil {
+				return nil, err
+			}
+			escape = toString(ve)
+		}
+		re, err = likeRegexp(toString(pattern), escape)
+		if err != nil {
+			return nil, err
+		}
+		if isConstant(e.pattern) && (e.escape == nil || isConstant(e.escape)) {
+			ev.likes[e] = re
+		}
+	}
+	return re.MatchString(toString(v)) != e.not, nil
+}
+
+func isConstant(e expr) bool {
+	_, ok := e.(*literal)
+	return ok
+}
+
+// likeRegexp returns the regular expression matching a LIKE pattern, where % matches any sequence and _ any character
+func likeRegexp(pattern, escape string) (*regexp.Regexp, error) {
+	if utf8.RuneCountInString(escape) > 1 {
+		return nil, fmt.Errorf("%w: escape must be a single character", ErrEvaluation)
+	}
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+	escaped := false
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+			escaped = false
+		case escape != "" && string(c) == escape:
+			escaped = true
+		case c == '%':
+			sb.WriteString(".*")
+		case c == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+func (ev *evaluator) evalFunc(f *funcExpr, r *Record) (any, error) {
+	args := make([]any, len(f.args))
+	for i, arg := range f.args {
+		v, err := ev.eval(arg, r)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	switch f.name {
+	case "COALESCE":
+		for _, v := range args {
+			if v != nil {
+				return v, nil
+			}
+		}
+		return nil, nil
+	case "NULLIF":
+		if c, ok := compare(args[0], args[1]); ok && c == 0 {
+			return nil, nil
+		}
+		return args[0], nil
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	s := toString(args[0])
+	switch f.name {
+	case "LOWER":
+		return strings.ToLower(s), nil
+	case "UPPER":
+		return strings.ToUpper(s), nil
+	case "TRIM":
+		return strings.TrimSpace(s), nil
+	case "CHAR_LENGTH", "CHARACTER_LENGTH":
+		return int64(utf8.RuneCountInString(s)), nil
+	case "SUBSTRING":
+		runes := []rune(s)
+		start, ok := toNumber(args[1])
+		if !ok {
+			return nil, fmt.Errorf("%w: SUBSTRING start must be a number", ErrEvaluation)
+		}
+		from, _ := toFloat(start)
+		to := math.Inf(1)
+		if len(args) == 3 {
+			length, ok := toNumber(args[2])
+			if !ok {
+				return nil, fmt.Errorf("%w: SUBSTRING length must be a number", ErrEvaluation)
+			}
+			l, _ := toFloat(length)
+			to = from + l
+		}
+		// positions are 1-based, and parts of the range before the string are ignored
+		begin := int(math.Max(from, 1)) - 1
+		end := len(runes)
+		if to-1 < float64(end) {
+			end = int(math.Max(to-1, 0))
+		}
+		if begin >= end {
+			return "", nil
+		}
+		return string(runes[begin:end]), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported function %s", ErrEvaluation, f.name)
+}
+
+// toNumber returns v as an int64 or a float64, parsing strings
+func toNumber(v any) (any, bool) {
+	switch v := v.(type) {
+	case int64, float64:
+		return v, true
+	case string:
+		s := strings.TrimSpace(v)
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, true
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// toString formats a value the way it is output
+func toString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		b, _ := marshalJSON(v)
+		return string(b)
+	}
+}
+
+func arithmetic(op string, left, right any) (any, error) {
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%w: arithmetic on non-numeric value", ErrEvaluation)
+	}
+	li, lint := l.(int64)
+	ri, rint := r.(int64)
+	if lint && rint {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, fmt.Errorf("%w: division by zero", ErrEvaluation)
+			}
+			if op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+	lf, _ := toFloat(l)
+	rf, _ := toFloat(r)
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("%w: division by zero", ErrEvaluation)
+		}
+		return lf / rf, nil
+	case "%":
+		if rf == 0 {
+			return nil, fmt.Errorf("%w: division by zero", ErrEvaluation)
+		}
+		return math.Mod(lf, rf), nil
+	}
+	return nil, fmt.Errorf("%w: unknown operator %s", ErrEvaluation, op)
+}
+
+// compare compares two values, numbers numerically even when one of them is a numeric string. It returns false for
+// values that cannot be compared.
+func compare(left, right any) (int, bool) {
+	if left == nil || right == nil {
+		return 0, false
+	}
+	ls, lstr := left.(string)
+	rs, rstr := right.(string)
+	if lstr && rstr {
+		return strings.Compare(ls, rs), true
+	}
+	if lb, ok := left.(bool); ok {
+		rb, ok := right.(bool)
+		if !ok {
+			if s, isStr := right.(string); isStr {
+				parsed, err := strconv.ParseBool(s)
+				rb, ok = parsed, err == nil
+			}
+		}
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case lb == rb:
+			return 0, true
+		case !lb:
+			return -1, true
+		default:
+			return 1, true
+		}
+	}
+	if _, ok := right.(bool); ok {
+		c, ok := compare(right, left)
+		return -c, ok
+	}
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		return 0, false
+	}
+	li, lint := l.(int64)
+	ri, rint := r.(int64)
+	if lint && rint {
+		switch {
+		case li < ri:
+			return -1, true
+		case li > ri:
+			return 1, true
+		}
+		return 0, true
+	}
+	lf, _ := toFloat(l)
+	rf, _ := toFloat(r)
+	switch {
+	case lf < rf:
+		return -1, true
+	case lf > rf:
+		return 1, true
+	}
+	return 0, true
+}
+
+func cast(v any, typ string) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch typ {
+	case "INT", "INTEGER", "BIGINT", "SMALLINT":
+		n, ok := toNumber(v)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot cast '%s' to %s", ErrEvaluation, toString(v), typ)
+		}
+		if f, isFloat := n.(float64); isFloat {
+			return int64(f), nil
+		}
+		return n, nil
+	case "FLOAT", "REAL", "DOUBLE", "DECIMAL", "NUMERIC":
+		n, ok := toNumber(v)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot cast '%s' to %s", ErrEvaluation, toString(v), typ)
+		}
+		f, _ := toFloat(n)
+		return f, nil
+	case "STRING", "VARCHAR", "CHAR", "TEXT":
+		return toString(v), nil
+	case "BOOL", "BOOLEAN":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(toString(v)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot cast '%s' to %s", ErrEvaluation, toString(v), typ)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %s", ErrEvaluation, typ)
+}
@@ -0,0 +1,314 @@
+package s3select
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCSVParsing  = errors.New("csv parsing error")
+	ErrJSONParsing = errors.New("json parsing error")
+)
+
+const (
+	FileHeaderInfoNone   = "NONE"
+	FileHeaderInfoUse    = "USE"
+	FileHeaderInfoIgnore = "IGNORE"
+
+	JSONTypeDocument = "DOCUMENT"
+	JSONTypeLines    = "LINES"
+)
+
+// RecordReader reads the records of the selected object, returning io.EOF after the last one
+type RecordReader interface {
+	Read() (*Record, error)
+}
+
+// CSVInputConfig describes the format of a CSV object
+type CSVInputConfig struct {
+	FileHeaderInfo       string
+	Comments             string
+	QuoteEscapeCharacter string
+	RecordDelimiter      string
+	FieldDelimiter       string
+	QuoteCharacter       string
+}
+
+type csvReader struct {
+	r      *bufio.Reader
+	config CSVInputConfig
+	names  []string
+}
+
+func newCSVReader(r io.Reader, config CSVInputConfig) (*csvReader, error) {
+	cr := &csvReader{r: bufio.NewReader(r), config: config}
+	switch config.FileHeaderInfo {
+	case FileHeaderInfoNone:
+	case FileHeaderInfoUse, FileHeaderInfoIgnore:
+		header, err := cr.readFields()
+		if errors.Is(err, io.EOF) {
+			return cr, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if config.FileHeaderInfo == FileHeaderInfoUse {
+			cr.names = header
+		}
+	default:
+		return nil, fmt.Errorf("%w: FileHeaderInfo %s", ErrInvalidRequestParameter, config.FileHeaderInfo)
+	}
+	return cr, nil
+}
+
+func (cr *csvReader) Read() (*Record, error) {
+	fields, err := cr.readFields()
+	if err != nil {
+		return nil, err
+	}
+	record := &Record{Names: make([]string, len(fields)), Values: make([]any, len(fields))}
+	for i, field := range fields {
+		if i < len(cr.names) {
+			record.Names[i] = cr.names[i]
+		} else {
+			record.Names[i] = "_" + strconv.Itoa(i+1)
+		}
+		record.Values[i] = field
+	}
+	return record, nil
+}
+
+// hasPrefix reports whether the unread input starts with s, consuming it if it does
+func (cr *csvReader) hasPrefix(s string) (bool, error) {
+	b, err := cr.r.Peek(len(s))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	if string(b) != s {
+		return false, nil
+	}
+	_, err = cr.r.Discard(len(s))
+	return true, err
+}
+
+// readFields reads the fields of the next record, skipping comment lines
+func (cr *csvReader) readFields() ([]string, error) {
+	for {
+		if _, err := cr.r.Peek(1); err != nil {
+			return nil, err
+		}
+		if cr.config.Comments == "" {
+			return cr.readRecordFields()
+		}
+		isComment, err := cr.hasPrefix(cr.config.Comments)
+		if err != nil {
+			return nil, err
+		}
+		if !isComment {
+			return cr.readRecordFields()
+		}
+		if err := cr.skipRecord(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (cr *csvReader) skipRecord() error {
+	for {
+		found, err := cr.hasPrefix(cr.config.RecordDelimiter)
+		if err != nil || found {
+			return err
+		}
+		if _, err := cr.r.ReadByte(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (cr *csvReader) readRecordFields() ([]string, error) {
+	var (
+		fields []string
+		field  strings.Builder
+		quoted bool
+	)
+	atFieldStart := true
+	for {
+		if atFieldStart && !quoted {
+			atFieldStart = false
+			isQuote, err := cr.hasPrefix(cr.config.QuoteCharacter)
+			if err != nil {
+				return nil, err
+			}
+			if isQuote {
+				quoted = true
+				continue
+			}
+		}
+		if quoted {
+			if cr.config.QuoteEscapeCharacter != cr.config.QuoteCharacter {
+				escaped, err := cr.hasPrefix(cr.config.QuoteEscapeCharacter)
+				if err != nil {
+					return nil, err
+				}
+				if escaped {
+					c, err := cr.r.ReadByte()
+					if err != nil {
+						return nil, fmt.Errorf("%w: unterminated escape", ErrCSVParsing)
+					}
+					field.WriteByte(c)
+					continue
+				}
+			}
+			isQuote, err := cr.hasPrefix(cr.config.QuoteCharacter)
+			if err != nil {
+				return nil, err
+			}
+			if isQuote {
+				// a doubled quote character is a quote in the field
+				doubled, err := cr.hasPrefix(cr.config.QuoteCharacter)
+				if err != nil {
+					return nil, err
+				}
+				if doubled {
+					field.WriteString(cr.config.QuoteCharacter)
+				} else {
+					quoted = false
+				}
+				continue
+			}
+			c, err := cr.r.ReadByte()
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: unterminated quoted field", ErrCSVParsing)
+			}
+			if err != nil {
+				return nil, err
+			}
+			field.WriteByte(c)
+			continue
+		}
+		endOfField, err := cr.hasPrefix(cr.config.FieldDelimiter)
+		if err != nil {
+			return nil, err
+		}
+		if endOfField {
+			fields = append(fields, field.String())
+			field.Reset()
+			atFieldStart = true
+			continue
+		}
+		endOfRecord, err := cr.hasPrefix(cr.config.RecordDelimiter)
+		if err != nil {
+			return nil, err
+		}
+		if endOfRecord {
+			return append(fields, field.String()), nil
+		}
+		c, err := cr.r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return append(fields, field.String()), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		field.WriteByte(c)
+	}
+}
+
+type jsonReader struct {
+	decoder *json.Decoder
+	// pending holds the remaining records of a top-level array
+	pending []any
+}
+
+func newJSONReader(r io.Reader, jsonType string) (*jsonReader, error) {
+	if jsonType != JSONTypeDocument && jsonType != JSONTypeLines {
+		return nil, fmt.Errorf("%w: JSON Type %s", ErrInvalidRequestParameter, jsonType)
+	}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return &jsonReader{decoder: decoder}, nil
+}
+
+func (jr *jsonReader) Read() (*Record, error) {
+	for len(jr.pending) == 0 {
+		v, err := decodeJSONValue(jr.decoder)
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrJSONParsing, err)
+		}
+		if values, ok := v.([]any); ok {
+			jr.pending = values
+			continue
+		}
+		jr.pending = []any{v}
+	}
+	v := jr.pending[0]
+	jr.pending = jr.pending[1:]
+	if record, ok := v.(*Record); ok {
+		return record, nil
+	}
+	return &Record{Names: []string{"_1"}, Values: []any{v}}, nil
+}
+
+// decodeJSONValue decodes the next value, keeping the order of the fields of objects
+func decodeJSONValue(decoder *json.Decoder) (any, error) {
+	t, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := t.(type) {
+	case json.Delim:
+		if t == '[' {
+			values := []any{}
+			for decoder.More() {
+				v, err := decodeJSONValue(decoder)
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+				values = append(values, v)
+			}
+			_, err := decoder.Token()
+			return values, unexpectedEOF(err)
+		}
+		record := &Record{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			v, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			record.Add(key.(string), v)
+		}
+		_, err := decoder.Token()
+		return record, unexpectedEOF(err)
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n, nil
+		}
+		return t.Float64()
+	default:
+		// nil, bool or string
+		return t, nil
+	}
+}
+
+// unexpectedEOF converts io.EOF in the middle of a value to io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	QuoteFieldsAlways   = "ALWAYS"
+	QuoteFieldsAsNeeded = "ASNEEDED"
+)
+
+// RecordWriter formats records of the select results
+type RecordWriter interface {
+	Write(buf *bytes.Buffer, r *Record) error
+}
+
+// CSVOutputConfig describes the format of CSV results
+type CSVOutputConfig struct {
+	QuoteFields          string
+	QuoteEscapeCharacter string
+	RecordDelimiter      string
+	FieldDelimiter       string
+	QuoteCharacter       string
+}
+
+type csvWriter struct {
+	config CSVOutputConfig
+}
+
+func newCSVWriter(config CSVOutputConfig) (*csvWriter, error) {
+	if config.QuoteFields != QuoteFieldsAlways && config.QuoteFields != QuoteFieldsAsNeeded {
+		return nil, fmt.Errorf("%w: QuoteFields %s", ErrInvalidRequestParameter, config.QuoteFields)
+	}
+	return &csvWriter{config: config}, nil
+}
+
+func (cw *csvWriter) Write(buf *bytes.Buffer, r *Record) error {
+	for i, v := range r.Values {
+		if i > 0 {
+			buf.WriteString(cw.config.FieldDelimiter)
+		}
+		field := toString(v)
+		if cw.config.QuoteFields == QuoteFieldsAlways || cw.needsQuotes(field) {
+			buf.WriteString(cw.config.QuoteCharacter)
+			buf.WriteString(strings.ReplaceAll(field, cw.config.QuoteCharacter, cw.config.QuoteEscapeCharacter+cw.config.QuoteCharacter))
+			buf.WriteString(cw.config.QuoteCharacter)
+		} else {
+			buf.WriteString(field)
+		}
+	}
+	buf.WriteString(cw.config.RecordDelimiter)
+	return nil
+}
+
+func (cw *csvWriter) needsQuotes(field string) bool {
+	return strings.Contains(field, cw.config.FieldDelimiter) ||
+		strings.Contains(field, cw.config.RecordDelimiter) ||
+		strings.Contains(field, cw.config.QuoteCharacter) ||
+		strings.ContainsAny(field, "\r\n")
+}
+
+type jsonWriter struct {
+	recordDelimiter string
+}
+
+func (jw *jsonWriter) Write(buf *bytes.Buffer, r *Record) error {
+	if err := writeJSON(buf, r); err != nil {
+		return err
+	}
+	buf.WriteString(jw.recordDelimiter)
+	return nil
+}
+
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := writeJSON(&buf, v)
+	return buf.Bytes(), err
+}
+
+// writeJSON writes v as JSON, keeping the order of the fields of records
+func writeJSON(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case *Record:
+		buf.WriteByte('{')
+		for i, name := range v.Names {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, name); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSON(buf, v.Values[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return writeJSON(buf, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
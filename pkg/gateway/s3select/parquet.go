@@ -0,0 +1,142 @@
+package s3select
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+var ErrParquetParsing = errors.New("parquet parsing error")
+
+const parquetReadBatchSize = 1024
+
+// readerAtFile is a read-only source.ParquetFile over an io.ReaderAt
+type readerAtFile struct {
+	*io.SectionReader
+	r    io.ReaderAt
+	size int64
+}
+
+func newReaderAtFile(r io.ReaderAt, size int64) *readerAtFile {
+	return &readerAtFile{SectionReader: io.NewSectionReader(r, 0, size), r: r, size: size}
+}
+
+func (f *readerAtFile) Open(string) (source.ParquetFile, error) {
+	return newReaderAtFile(f.r, f.size), nil
+}
+
+func (f *readerAtFile) Create(string) (source.ParquetFile, error) {
+	return nil, fmt.Errorf("create parquet file: %w", errors.ErrUnsupported)
+}
+
+func (f *readerAtFile) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("write parquet file: %w", errors.ErrUnsupported)
+}
+
+func (f *readerAtFile) Close() error {
+	return nil
+}
+
+type parquetReader struct {
+	reader *reader.ParquetReader
+	// names maps the names of the fields of the rows to their names in the schema
+	names   map[string]string
+	rows    []any
+	numRows int64
+	read    int64
+}
+
+func newParquetReader(r io.ReaderAt, size int64) (*parquetReader, error) {
+	pr, err := reader.NewParquetReader(newReaderAtFile(r, size), nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrParquetParsing, err)
+	}
+	names := make(map[string]string, len(pr.SchemaHandler.Infos))
+	for _, info := range pr.SchemaHandler.Infos {
+		names[info.InName] = info.ExName
+	}
+	return &parquetReader{reader: pr, names: names, numRows: pr.GetNumRows()}, nil
+}
+
+func (pr *parquetReader) Read() (*Record, error) {
+	if len(pr.rows) == 0 {
+		if pr.read >= pr.numRows {
+			return nil, io.EOF
+		}
+		rows, err := pr.reader.ReadByNumber(int(min(parquetReadBatchSize, pr.numRows-pr.read)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrParquetParsing, err)
+		}
+		if len(rows) == 0 {
+			return nil, io.EOF
+		}
+		pr.read += int64(len(rows))
+		pr.rows = rows
+	}
+	row := pr.rows[0]
+	pr.rows = pr.rows[1:]
+	record, ok := pr.value(reflect.ValueOf(row)).(*Record)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected row %T", ErrParquetParsing, row)
+	}
+	return record, nil
+}
+
+// value converts a value read from the parquet file to a record value. Logical types are kept as their physical
+// values.
+func (pr *parquetReader) value(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return pr.value(v.Elem())
+	case reflect.Struct:
+		record := &Record{}
+		for i := 0; i < v.NumField(); i++ {
+			name := v.Type().Field(i).Name
+			if exName, ok := pr.names[name]; ok {
+				name = exName
+			}
+			record.Add(name, pr.value(v.Field(i)))
+		}
+		return record
+	case reflect.Slice, reflect.Array:
+		values := make([]any, v.Len())
+		for i := range values {
+			values[i] = pr.value(v.Index(i))
+		}
+		return values
+	case reflect.Map:
+		record := &Record{}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			record.Add(fmt.Sprint(key.Interface()), pr.value(v.MapIndex(key)))
+		}
+		return record
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func (pr *parquetReader) Close() error {
+	pr.reader.ReadStop()
+	return nil
+}
@@ -0,0 +1,34 @@
+package s3select
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Record is a record of the selected object, or an object nested in it, with its fields in order. The values of
+// fields are nil, bool, int64, float64, string, *Record or []any.
+type Record struct {
+	Names  []string
+	Values []any
+}
+
+func (r *Record) Add(name string, value any) {
+	r.Names = append(r.Names, name)
+	r.Values = append(r.Values, value)
+}
+
+// Get returns the value of the field name, matched case-insensitively unless caseSensitive. Fields with no name of
+// their own are also matched positionally by _1, _2, and so on.
+func (r *Record) Get(name string, caseSensitive bool) (any, bool) {
+	for i, n := range r.Names {
+		if n == name || (!caseSensitive && strings.EqualFold(n, name)) {
+			return r.Values[i], true
+		}
+	}
+	if position, ok := strings.CutPrefix(name, "_"); ok {
+		if n, err := strconv.Atoi(position); err == nil && n >= 1 && n <= len(r.Values) {
+			return r.Values[n-1], true
+		}
+	}
+	return nil, false
+}
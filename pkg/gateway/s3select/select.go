@@ -0,0 +1,414 @@
+// Package s3select implements S3 Select: a subset of SQL evaluated over the records of CSV, JSON and Parquet objects,
+// with its results returned in the AWS event stream format.
+package s3select
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+var (
+	ErrInvalidExpressionType    = errors.New("invalid expression type")
+	ErrInvalidRequestParameter  = errors.New("invalid request parameter")
+	ErrInvalidCompressionFormat = errors.New("invalid compression format")
+	ErrInvalidDataSource        = errors.New("invalid data source")
+)
+
+const (
+	ExpressionTypeSQL = "SQL"
+
+	CompressionTypeNone  = "NONE"
+	CompressionTypeGzip  = "GZIP"
+	CompressionTypeBzip2 = "BZIP2"
+
+	// recordsMessageSize is the size of the results buffered before sending them in a Records event
+	recordsMessageSize = 128 * 1024
+)
+
+// Object is the content of the selected object
+type Object interface {
+	// ReaderAt reads parts of the content, used for Parquet objects
+	io.ReaderAt
+	// Open returns a reader of the whole content, used for CSV and JSON objects
+	Open() (io.ReadCloser, error)
+	Size() int64
+}
+
+// Selector runs a select request
+type Selector struct {
+	query           *Query
+	progress        bool
+	compressionType string
+	csvInput        *CSVInputConfig
+	jsonInputType   string
+	parquetInput    bool
+	csvOutput       *CSVOutputConfig
+	jsonOutput      *serde.JSONOutput
+}
+
+// NewSelector validates the select request, filling in the defaults of its serialization parameters
+func NewSelector(req *serde.SelectObjectContentRequest) (*Selector, error) {
+	if !strings.EqualFold(req.ExpressionType, ExpressionTypeSQL) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExpressionType, req.ExpressionType)
+	}
+	query, err := Parse(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	s := &Selector{
+		query:           query,
+		progress:        req.RequestProgress.Enabled,
+		compressionType: strings.ToUpper(req.InputSerialization.CompressionType),
+	}
+
+	in := req.InputSerialization
+	switch {
+	case in.CSV != nil && in.JSON == nil && in.Parquet == nil:
+		s.csvInput = &CSVInputConfig{
+			FileHeaderInfo:       strings.ToUpper(withDefault(in.CSV.FileHeaderInfo, FileHeaderInfoNone)),
+			Comments:             in.CSV.Comments,
+			QuoteEscapeCharacter: withDefault(in.CSV.QuoteEscapeCharacter, `"`),
+			RecordDelimiter:      withDefault(in.CSV.RecordDelimiter, "\n"),
+			FieldDelimiter:       withDefault(in.CSV.FieldDelimiter, ","),
+			QuoteCharacter:       withDefault(in.CSV.QuoteCharacter, `"`),
+		}
+	case in.JSON != nil && in.CSV == nil && in.Parquet == nil:
+		s.jsonInputType = strings.ToUpper(withDefault(in.JSON.Type, JSONTypeDocument))
+	case in.Parquet != nil && in.CSV == nil && in.JSON == nil:
+		s.parquetInput = true
+	default:
+		return nil, fmt.Errorf("%w: exactly one of CSV, JSON or Parquet input is required", ErrInvalidDataSource)
+	}
+	switch s.compressionType {
+	case "":
+		s.compressionType = CompressionTypeNone
+	case CompressionTypeNone:
+	case CompressionTypeGzip, CompressionTypeBzip2:
+		if s.parquetInput {
+			return nil, fmt.Errorf("%w: Parquet objects cannot be compressed", ErrInvalidCompressionFormat)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCompressionFormat, s.compressionType)
+	}
+
+	out := req.OutputSerialization
+	switch {
+	case out.CSV != nil && out.JSON == nil:
+		s.csvOutput = &CSVOutputConfig{
+			QuoteFields:          strings.ToUpper(withDefault(out.CSV.QuoteFields, QuoteFieldsAsNeeded)),
+			QuoteEscapeCharacter: withDefault(out.CSV.QuoteEscapeCharacter, `"`),
+			RecordDelimiter:      withDefault(out.CSV.RecordDelimiter, "\n"),
+			FieldDelimiter:       withDefault(out.CSV.FieldDelimiter, ","),
+			QuoteCharacter:       withDefault(out.CSV.QuoteCharacter, `"`),
+		}
+	case out.JSON != nil && out.CSV == nil:
+		s.jsonOutput = &serde.JSONOutput{RecordDelimiter: withDefault(out.JSON.RecordDelimiter, "\n")}
+	default:
+		return nil, fmt.Errorf("%w: exactly one of CSV or JSON output is required", ErrInvalidRequestParameter)
+	}
+	return s, nil
+}
+
+func withDefault(s, defaultValue string) string {
+	if s == "" {
+		return defaultValue
+	}
+	return s
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r     io.Reader
+	count int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// countingReaderAt counts the bytes read through it
+type countingReaderAt struct {
+	r     io.ReaderAt
+	count int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.count += int64(n)
+	return n, err
+}
+
+// Results are the results of a select request over an object
+type Results struct {
+	selector  *Selector
+	records   RecordReader
+	writer    RecordWriter
+	closers   []io.Closer
+	scanned   func() int64
+	processed func() int64
+	returned  int64
+}
+
+// Select opens the records of obj. Errors reading the object format are returned here, before any result is
+// written.
+func (s *Selector) Select(obj Object) (*Results, error) {
+	res := &Results{selector: s}
+	if err := res.open(obj); err != nil {
+		_ = res.Close()
+		return nil, err
+	}
+	var err error
+	if s.csvOutput != nil {
+		res.writer, err = newCSVWriter(*s.csvOutput)
+	} else {
+		res.writer = &jsonWriter{recordDelimiter: s.jsonOutput.RecordDelimiter}
+	}
+	if err != nil {
+		_ = res.Close()
+		return nil, err
+	}
+	return res, nil
+}
+
+func (res *Results) open(obj Object) error {
+	s := res.selector
+	if s.parquetInput {
+		counter := &countingReaderAt{r: obj}
+		res.scanned = func() int64 { return counter.count }
+		res.processed = res.scanned
+		records, err := newParquetReader(counter, obj.Size())
+		if err != nil {
+			return err
+		}
+		res.closers = append(res.closers, records)
+		res.records = records
+		return nil
+	}
+
+	rc, err := obj.Open()
+	if err != nil {
+		return err
+	}
+	res.closers = append(res.closers, rc)
+	scanCounter := &countingReader{r: rc}
+	res.scanned = func() int64 { return scanCounter.count }
+	var r io.Reader = scanCounter
+	switch s.compressionType {
+	case CompressionTypeGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidCompressionFormat, err)
+		}
+		res.closers = append(res.closers, gz)
+		r = gz
+	case CompressionTypeBzip2:
+		r = bzip2.NewReader(r)
+	}
+	processCounter := &countingReader{r: r}
+	res.processed = func() int64 { return processCounter.count }
+
+	if s.csvInput != nil {
+		res.records, err = newCSVReader(processCounter, *s.csvInput)
+	} else {
+		res.records, err = newJSONReader(processCounter, s.jsonInputType)
+	}
+	return err
+}
+
+// Close releases the object readers
+func (res *Results) Close() error {
+	var errs []error
+	for i := len(res.closers) - 1; i >= 0; i-- {
+		errs = append(errs, res.closers[i].Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (res *Results) stats() *serde.SelectStats {
+	return &serde.SelectStats{
+		BytesScanned:   res.scanned(),
+		BytesProcessed: res.processed(),
+		BytesReturned:  res.returned,
+	}
+}
+
+// WriteTo evaluates the query over the records and writes the results as an event stream: Records events holding
+// the results, Progress events when requested, then Stats and End events. An error evaluating the query ends the
+// stream with an error event, and is returned.
+func (res *Results) WriteTo(w io.Writer) (int64, error) {
+	ew := &eventWriter{w: w, encoder: eventstream.NewEncoder()}
+	err := res.writeRecords(ew)
+	if err != nil {
+		if ew.err == nil {
+			_ = ew.writeError(errorCode(err), err.Error())
+		}
+		return ew.written, err
+	}
+	if err := ew.writeStats("Stats", res.stats()); err != nil {
+		return ew.written, err
+	}
+	return ew.written, ew.writeEvent("End", "", nil)
+}
+
+func (res *Results) writeRecords(ew *eventWriter) error {
+	var buf bytes.Buffer
+	flush := func() error {
+		if buf.Len() == 0 {
+			return nil
+		}
+		res.returned += int64(buf.Len())
+		if err := ew.writeEvent("Records", "application/octet-stream", buf.Bytes()); err != nil {
+			return err
+		}
+		buf.Reset()
+		if res.selector.progress {
+			return ew.writeStats("Progress", res.stats())
+		}
+		return nil
+	}
+
+	if err := res.evaluate(&buf, flush); err != nil {
+		// send the results up to the error before it
+		if flushErr := flush(); flushErr != nil {
+			return flushErr
+		}
+		return err
+	}
+	return flush()
+}
+
+// evaluate writes the results of the query over the records to buf, flushing it whenever it fills up
+func (res *Results) evaluate(buf *bytes.Buffer, flush func() error) error {
+	q := res.selector.query
+	ev := newEvaluator(q)
+	var count int64
+	for q.limit < 0 || count < q.limit || q.aggregate {
+		record, err := res.records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		matched, err := ev.match(record)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		if q.aggregate {
+			if err := ev.accumulate(record); err != nil {
+				return err
+			}
+			continue
+		}
+		projected, err := ev.project(record)
+		if err != nil {
+			return err
+		}
+		if err := res.writer.Write(buf, projected); err != nil {
+			return err
+		}
+		count++
+		if buf.Len() >= recordsMessageSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if q.aggregate && q.limit != 0 {
+		projected, err := ev.project(nil)
+		if err != nil {
+			return err
+		}
+		return res.writer.Write(buf, projected)
+	}
+	return nil
+}
+
+// errorCode returns the code of the error event reporting err
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrCSVParsing):
+		return "CSVParsingError"
+	case errors.Is(err, ErrJSONParsing):
+		return "JSONParsingError"
+	case errors.Is(err, ErrParquetParsing):
+		return "ParquetParsingError"
+	case errors.Is(err, ErrEvaluation):
+		return "EvaluatorInvalidArguments"
+	default:
+		return "InternalError"
+	}
+}
+
+// eventWriter writes the messages of an event stream, flushing each one in case w supports it
+type eventWriter struct {
+	w       io.Writer
+	encoder *eventstream.Encoder
+	written int64
+	// err is the error writing to w, after which nothing more is written
+	err error
+}
+
+func (ew *eventWriter) write(headers eventstream.Headers, payload []byte) error {
+	if ew.err != nil {
+		return ew.err
+	}
+	var buf bytes.Buffer
+	if err := ew.encoder.Encode(&buf, eventstream.Message{Headers: headers, Payload: payload}); err != nil {
+		return err
+	}
+	n, err := ew.w.Write(buf.Bytes())
+	ew.written += int64(n)
+	if err != nil {
+		ew.err = err
+		return err
+	}
+	if f, ok := ew.w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (ew *eventWriter) writeEvent(eventType, contentType string, payload []byte) error {
+	headers := eventstream.Headers{
+		{Name: ":message-type", Value: eventstream.StringValue("event")},
+		{Name: ":event-type", Value: eventstream.StringValue(eventType)},
+	}
+	if contentType != "" {
+		headers.Set(":content-type", eventstream.StringValue(contentType))
+	}
+	return ew.write(headers, payload)
+}
+
+func (ew *eventWriter) writeStats(eventType string, stats *serde.SelectStats) error {
+	payload, err := xml.Marshal(struct {
+		XMLName xml.Name
+		*serde.SelectStats
+	}{XMLName: xml.Name{Local: eventType}, SelectStats: stats})
+	if err != nil {
+		return err
+	}
+	return ew.writeEvent(eventType, "text/xml", payload)
+}
+
+func (ew *eventWriter) writeError(code, message string) error {
+	return ew.write(eventstream.Headers{
+		{Name: ":message-type", Value: eventstream.StringValue("error")},
+		{Name: ":error-code", Value: eventstream.StringValue(code)},
+		{Name: ":error-message", Value: eventstream.StringValue(message)},
+	}, nil)
+}
@@ -0,0 +1,440 @@
+package s3select_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/gateway/s3select"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+// memObject is an object held in memory
+type memObject struct {
+	*bytes.Reader
+	data []byte
+}
+
+func newMemObject(data []byte) *memObject {
+	return &memObject{Reader: bytes.NewReader(data), data: data}
+}
+
+func (m *memObject) Open() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.data)), nil
+}
+
+type selectResult struct {
+	records   string
+	stats     *serde.SelectStats
+	errorCode string
+	progress  int
+}
+
+// readEvents decodes the event stream of the results of a select request
+func readEvents(t *testing.T, r io.Reader) *selectResult {
+	t.Helper()
+	decoder := eventstream.NewDecoder()
+	result := &selectResult{}
+	var records bytes.Buffer
+	for {
+		msg, err := decoder.Decode(r, nil)
+		require.NoError(t, err)
+		if msg.Headers.Get(":message-type").String() == "error" {
+			result.errorCode = msg.Headers.Get(":error-code").String()
+			result.records = records.String()
+			return result
+		}
+		switch msg.Headers.Get(":event-type").String() {
+		case "Records":
+			records.Write(msg.Payload)
+		case "Progress":
+			result.progress++
+		case "Stats":
+			var stats serde.SelectStats
+			require.NoError(t, xml.Unmarshal(msg.Payload, &stats))
+			result.stats = &stats
+		case "End":
+			result.records = records.String()
+			return result
+		default:
+			t.Fatalf("unexpected event %s", msg.Headers.Get(":event-type"))
+		}
+	}
+}
+
+func runSelect(t *testing.T, req *serde.SelectObjectContentRequest, data []byte) *selectResult {
+	t.Helper()
+	if req.ExpressionType == "" {
+		req.ExpressionType = "SQL"
+	}
+	selector, err := s3select.NewSelector(req)
+	require.NoError(t, err)
+	results, err := selector.Select(newMemObject(data))
+	require.NoError(t, err)
+	defer func() { _ = results.Close() }()
+	var buf bytes.Buffer
+	_, err = results.WriteTo(&buf)
+	result := readEvents(t, &buf)
+	if result.errorCode == "" {
+		require.NoError(t, err)
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		"SELECT * FROM S3Object",
+		"select * from s3object[*] s where s.a = 1",
+		"SELECT s.a AS x, s.b y, \"c\" FROM S3Object AS s LIMIT 10",
+		"SELECT COUNT(*), AVG(CAST(_2 AS FLOAT)) FROM S3Object WHERE _1 LIKE 'a%' ESCAPE '\\'",
+		"SELECT UPPER(a) || '-' || SUBSTRING(b, 2, 3) FROM S3Object WHERE c NOT IN (1, 2) AND d BETWEEN 1 AND 5",
+		"SELECT s.arr[0].name FROM S3Object s WHERE s.x IS NOT NULL OR NOT s.y",
+	}
+	for _, expression := range valid {
+		_, err := s3select.Parse(expression)
+		require.NoError(t, err, expression)
+	}
+
+	invalid := []string{
+		"",
+		"SELECT FROM S3Object",
+		"SELECT * FROM table",
+		"SELECT * FROM S3Object WHERE",
+		"SELECT * FROM S3Object LIMIT -1",
+		"SELECT * FROM S3Object WHERE a = 'unterminated",
+		"SELECT a, COUNT(*) FROM S3Object",
+		"SELECT SUM(COUNT(*)) FROM S3Object",
+		"SELECT * FROM S3Object WHERE COUNT(*) > 1",
+		"SELECT UNKNOWN(a) FROM S3Object",
+		"SELECT * FROM S3Object extra tokens",
+	}
+	for _, expression := range invalid {
+		_, err := s3select.Parse(expression)
+		require.ErrorIs(t, err, s3select.ErrSyntax, expression)
+	}
+}
+
+func TestNewSelector(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         serde.SelectObjectContentRequest
+		expectedErr error
+	}{
+		{
+			name: "expression_type",
+			req: serde.SelectObjectContentRequest{
+				Expression:          "SELECT * FROM S3Object",
+				ExpressionType:      "JSONPath",
+				InputSerialization:  serde.InputSerialization{CSV: &serde.CSVInput{}},
+				OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			},
+			expectedErr: s3select.ErrInvalidExpressionType,
+		},
+		{
+			name: "no_input",
+			req: serde.SelectObjectContentRequest{
+				Expression:          "SELECT * FROM S3Object",
+				ExpressionType:      "SQL",
+				OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			},
+			expectedErr: s3select.ErrInvalidDataSource,
+		},
+		{
+			name: "compression",
+			req: serde.SelectObjectContentRequest{
+				Expression:          "SELECT * FROM S3Object",
+				ExpressionType:      "SQL",
+				InputSerialization:  serde.InputSerialization{CompressionType: "ZSTD", CSV: &serde.CSVInput{}},
+				OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			},
+			expectedErr: s3select.ErrInvalidCompressionFormat,
+		},
+		{
+			name: "compressed_parquet",
+			req: serde.SelectObjectContentRequest{
+				Expression:          "SELECT * FROM S3Object",
+				ExpressionType:      "SQL",
+				InputSerialization:  serde.InputSerialization{CompressionType: "GZIP", Parquet: &serde.ParquetInput{}},
+				OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			},
+			expectedErr: s3select.ErrInvalidCompressionFormat,
+		},
+		{
+			name: "no_output",
+			req: serde.SelectObjectContentRequest{
+				Expression:         "SELECT * FROM S3Object",
+				ExpressionType:     "SQL",
+				InputSerialization: serde.InputSerialization{JSON: &serde.JSONInput{}},
+			},
+			expectedErr: s3select.ErrInvalidRequestParameter,
+		},
+		{
+			name: "syntax",
+			req: serde.SelectObjectContentRequest{
+				Expression:          "SELECT * FROM",
+				ExpressionType:      "SQL",
+				InputSerialization:  serde.InputSerialization{JSON: &serde.JSONInput{}},
+				OutputSerialization: serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+			},
+			expectedErr: s3select.ErrSyntax,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s3select.NewSelector(&tt.req)
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+const csvData = `name,city,age
+alice,"Tel Aviv, Israel",34
+bob,London,27
+carol,"Quote ""here""",45
+dave,Paris,
+`
+
+func TestSelect_CSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		input      serde.CSVInput
+		output     serde.OutputSerialization
+		expected   string
+	}{
+		{
+			name:       "all",
+			expression: "SELECT * FROM S3Object",
+			input:      serde.CSVInput{FileHeaderInfo: "IGNORE"},
+			output:     serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			expected:   "alice,\"Tel Aviv, Israel\",34\nbob,London,27\ncarol,\"Quote \"\"here\"\"\",45\ndave,Paris,\n",
+		},
+		{
+			name:       "positional",
+			expression: "SELECT _1, _3 FROM S3Object WHERE _1 <> 'name' LIMIT 2",
+			input:      serde.CSVInput{FileHeaderInfo: "NONE"},
+			output:     serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			expected:   "alice,34\nbob,27\n",
+		},
+		{
+			name:       "where",
+			expression: "SELECT s.name, s.age FROM S3Object s WHERE s.age <> '' AND CAST(s.age AS INT) > 30",
+			input:      serde.CSVInput{FileHeaderInfo: "USE"},
+			output:     serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+			expected:   "{\"name\":\"alice\",\"age\":\"34\"}\n{\"name\":\"carol\",\"age\":\"45\"}\n",
+		},
+		{
+			name:       "numeric_string_comparison",
+			expression: "SELECT name FROM S3Object WHERE age < 30",
+			input:      serde.CSVInput{FileHeaderInfo: "USE"},
+			output:     serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			expected:   "bob\n",
+		},
+		{
+			name:       "functions",
+			expression: "SELECT UPPER(name) || '@' || SUBSTRING(city, 1, 3) AS label FROM S3Object WHERE city LIKE '%on%' OR name IN ('dave')",
+			input:      serde.CSVInput{FileHeaderInfo: "USE"},
+			output:     serde.OutputSerialization{JSON: &serde.JSONOutput{RecordDelimiter: ";"}},
+			expected:   "{\"label\":\"BOB@Lon\"};{\"label\":\"DAVE@Par\"};",
+		},
+		{
+			name:       "aggregate",
+			expression: "SELECT COUNT(*), SUM(CAST(age AS INT)), MIN(age), MAX(name), AVG(age) FROM S3Object WHERE age <> ''",
+			input:      serde.CSVInput{FileHeaderInfo: "USE"},
+			output:     serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			expected:   "3,106,27,carol,35.333333333333336\n",
+		},
+		{
+			name:       "delimiters",
+			expression: "SELECT name, city FROM S3Object WHERE name = 'carol'",
+			input:      serde.CSVInput{FileHeaderInfo: "USE"},
+			output:     serde.OutputSerialization{CSV: &serde.CSVOutput{QuoteFields: "ALWAYS", FieldDelimiter: "|", RecordDelimiter: "\r\n", QuoteCharacter: "'"}},
+			expected:   "'carol'|'Quote \"here\"'\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			result := runSelect(t, &serde.SelectObjectContentRequest{
+				Expression:          tt.expression,
+				InputSerialization:  serde.InputSerialization{CSV: &input},
+				OutputSerialization: tt.output,
+			}, []byte(csvData))
+			require.Empty(t, result.errorCode)
+			require.Equal(t, tt.expected, result.records)
+			require.Equal(t, int64(len(csvData)), result.stats.BytesScanned)
+			require.Equal(t, int64(len(tt.expected)), result.stats.BytesReturned)
+		})
+	}
+
+	t.Run("input_format", func(t *testing.T) {
+		data := "# comment\nname;note\nx;'a;b'\n# another\ny;'it''s'\n"
+		result := runSelect(t, &serde.SelectObjectContentRequest{
+			Expression: "SELECT note FROM S3Object",
+			InputSerialization: serde.InputSerialization{CSV: &serde.CSVInput{
+				FileHeaderInfo: "USE",
+				Comments:       "#",
+				FieldDelimiter: ";",
+				QuoteCharacter: "'",
+			}},
+			OutputSerialization: serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+		}, []byte(data))
+		require.Equal(t, "{\"note\":\"a;b\"}\n{\"note\":\"it's\"}\n", result.records)
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(csvData))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		result := runSelect(t, &serde.SelectObjectContentRequest{
+			Expression:          "SELECT COUNT(*) FROM S3Object",
+			RequestProgress:     serde.RequestProgress{Enabled: true},
+			InputSerialization:  serde.InputSerialization{CompressionType: "GZIP", CSV: &serde.CSVInput{FileHeaderInfo: "USE"}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		}, buf.Bytes())
+		require.Equal(t, "4\n", result.records)
+		require.Equal(t, int64(buf.Len()), result.stats.BytesScanned)
+		require.Equal(t, int64(len(csvData)), result.stats.BytesProcessed)
+		require.Equal(t, 1, result.progress)
+	})
+
+	t.Run("evaluation_error", func(t *testing.T) {
+		result := runSelect(t, &serde.SelectObjectContentRequest{
+			Expression:          "SELECT name FROM S3Object WHERE CAST(city AS INT) > 1",
+			InputSerialization:  serde.InputSerialization{CSV: &serde.CSVInput{FileHeaderInfo: "USE"}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		}, []byte(csvData))
+		require.Equal(t, "EvaluatorInvalidArguments", result.errorCode)
+	})
+}
+
+func TestSelect_JSON(t *testing.T) {
+	const lines = `{"id": 1, "user": {"name": "alice", "tags": ["a", "b"]}, "score": 9.5}
+{"id": 2, "user": {"name": "bob", "tags": []}, "score": null}
+{"id": 3, "user": {"name": "carol"}, "active": true}
+`
+	tests := []struct {
+		name       string
+		expression string
+		jsonType   string
+		data       string
+		output     serde.OutputSerialization
+		expected   string
+	}{
+		{
+			name:       "all",
+			expression: "SELECT * FROM S3Object s WHERE s.id = 2",
+			jsonType:   "LINES",
+			data:       lines,
+			output:     serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+			expected:   "{\"id\":2,\"user\":{\"name\":\"bob\",\"tags\":[]},\"score\":null}\n",
+		},
+		{
+			name:       "nested",
+			expression: "SELECT s.id, s.user.name, s.user.tags[1] AS tag FROM S3Object s WHERE s.user.tags[0] = 'a'",
+			jsonType:   "LINES",
+			data:       lines,
+			output:     serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+			expected:   "{\"id\":1,\"name\":\"alice\",\"tag\":\"b\"}\n",
+		},
+		{
+			name:       "missing",
+			expression: "SELECT s.id FROM S3Object s WHERE s.score IS NULL AND s.active",
+			jsonType:   "LINES",
+			data:       lines,
+			output:     serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			expected:   "3\n",
+		},
+		{
+			name:       "arithmetic",
+			expression: "SELECT s.id * 10 + 1, s.score / 2 FROM S3Object s WHERE s.score > 1",
+			jsonType:   "LINES",
+			data:       lines,
+			output:     serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			expected:   "11,4.75\n",
+		},
+		{
+			name:       "document",
+			expression: "SELECT s.id FROM S3Object[*] s WHERE s.id BETWEEN 2 AND 3",
+			jsonType:   "DOCUMENT",
+			data:       "[\n  {\"id\": 1},\n  {\"id\": 2}\n]\n{\"id\": 3}",
+			output:     serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+			expected:   "{\"id\":2}\n{\"id\":3}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runSelect(t, &serde.SelectObjectContentRequest{
+				Expression:          tt.expression,
+				InputSerialization:  serde.InputSerialization{JSON: &serde.JSONInput{Type: tt.jsonType}},
+				OutputSerialization: tt.output,
+			}, []byte(tt.data))
+			require.Empty(t, result.errorCode)
+			require.Equal(t, tt.expected, result.records)
+		})
+	}
+
+	t.Run("parsing_error", func(t *testing.T) {
+		result := runSelect(t, &serde.SelectObjectContentRequest{
+			Expression:          "SELECT * FROM S3Object",
+			InputSerialization:  serde.InputSerialization{JSON: &serde.JSONInput{Type: "LINES"}},
+			OutputSerialization: serde.OutputSerialization{JSON: &serde.JSONOutput{}},
+		}, []byte("{\"id\": 1}\n{\"id\": "))
+		require.Equal(t, "JSONParsingError", result.errorCode)
+		require.Equal(t, "{\"id\":1}\n", result.records)
+	})
+}
+
+func TestSelect_Parquet(t *testing.T) {
+	data, err := os.ReadFile("testdata/000.snappy.parquet")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{
+			name:       "limit",
+			expression: "SELECT s.name, s.country_code FROM S3Object s LIMIT 2",
+			expected:   "Ngchemiangel,PW\nKahuku,US\n",
+		},
+		{
+			name:       "aggregate",
+			expression: "SELECT COUNT(*), SUM(s.population), MAX(s.population) FROM S3Object s WHERE s.country_code = 'US'",
+			expected:   "34,265282,38635\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runSelect(t, &serde.SelectObjectContentRequest{
+				Expression:          tt.expression,
+				InputSerialization:  serde.InputSerialization{Parquet: &serde.ParquetInput{}},
+				OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+			}, data)
+			require.Empty(t, result.errorCode)
+			require.Equal(t, tt.expected, result.records)
+			require.Positive(t, result.stats.BytesScanned)
+		})
+	}
+
+	t.Run("not_parquet", func(t *testing.T) {
+		selector, err := s3select.NewSelector(&serde.SelectObjectContentRequest{
+			Expression:          "SELECT * FROM S3Object",
+			ExpressionType:      "SQL",
+			InputSerialization:  serde.InputSerialization{Parquet: &serde.ParquetInput{}},
+			OutputSerialization: serde.OutputSerialization{CSV: &serde.CSVOutput{}},
+		})
+		require.NoError(t, err)
+		_, err = selector.Select(newMemObject([]byte(csvData)))
+		require.True(t, errors.Is(err, s3select.ErrParquetParsing), err)
+	})
+}
@@ -0,0 +1,756 @@
+package s3select
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrSyntax = errors.New("syntax error")
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any // value of string and number tokens
+	pos   int
+}
+
+// is reports whether the token is the operator or the (case-insensitive) keyword s
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokenOperator:
+		return t.text == s
+	case tokenIdent:
+		return strings.EqualFold(t.text, s)
+	default:
+		return false
+	}
+}
+
+// operators ordered so that longer operators match first
+var operators = []string{"<=", ">=", "<>", "!=", "||", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ".", "[", "]"}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(s) {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'':
+			str, next, err := scanQuoted(s, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s[i:next], value: str, pos: i})
+			i = next
+		case c == '"':
+			str, next, err := scanQuoted(s, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: str, pos: i})
+			i = next
+		case c >= '0' && c <= '9':
+			j := i
+			isFloat := false
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' || s[j] == 'e' || s[j] == 'E' ||
+				(j > i && (s[j] == '+' || s[j] == '-') && (s[j-1] == 'e' || s[j-1] == 'E'))) {
+				if s[j] == '.' || s[j] == 'e' || s[j] == 'E' {
+					isFloat = true
+				}
+				j++
+			}
+			var value any
+			var err error
+			if isFloat {
+				value, err = strconv.ParseFloat(s[i:j], 64)
+			} else {
+				value, err = strconv.ParseInt(s[i:j], 10, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid number '%s' at %d", ErrSyntax, s[i:j], i)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: s[i:j], value: value, pos: i})
+			i = j
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(s) && (s[j] == '_' || s[j] >= '0' && s[j] <= '9' || unicode.IsLetter(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: s[i:j], pos: i})
+			i = j
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character '%c' at %d", ErrSyntax, c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(s)}), nil
+}
+
+// scanQuoted scans the quoted string starting at s[start], in which the quote is escaped by doubling it
+func scanQuoted(s string, start int, quote byte) (string, int, error) {
+	var sb strings.Builder
+	i := start + 1
+	for i < len(s) {
+		if s[i] == quote {
+			if i+1 < len(s) && s[i+1] == quote {
+				sb.WriteByte(quote)
+				i += 2
+				continue
+			}
+			return sb.String(), i + 1, nil
+		}
+		sb.WriteByte(s[i])
+		i++
+	}
+	return "", 0, fmt.Errorf("%w: unterminated quote at %d", ErrSyntax, start)
+}
+
+type expr interface{}
+
+type literal struct {
+	value any
+}
+
+type pathElement struct {
+	name   string
+	quoted bool
+	index  int
+	// isIndex is set for an array index element
+	isIndex bool
+}
+
+type reference struct {
+	path []pathElement
+}
+
+type unaryExpr struct {
+	op      string
+	operand expr
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+type isNullExpr struct {
+	operand expr
+	not     bool
+}
+
+type likeExpr struct {
+	operand, pattern, escape expr
+	not                      bool
+}
+
+type betweenExpr struct {
+	operand, low, high expr
+	not                bool
+}
+
+type inExpr struct {
+	operand expr
+	list    []expr
+	not     bool
+}
+
+type castExpr struct {
+	operand expr
+	typ     string
+}
+
+type funcExpr struct {
+	name string
+	args []expr
+	// star is set for COUNT(*)
+	star bool
+}
+
+var aggregateFunctions = map[string]bool{"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true}
+
+func (f *funcExpr) isAggregate() bool {
+	return aggregateFunctions[f.name]
+}
+
+type projection struct {
+	expr expr
+	name string
+}
+
+// Query is a parsed select expression:
+//
+//	SELECT * | expression [[AS] alias], ... FROM S3Object[[*]] [[AS] alias] [WHERE condition] [LIMIT number]
+type Query struct {
+	// projections is empty when selecting all the fields of a record
+	projections []projection
+	alias       string
+	where       expr
+	limit       int64
+	aggregate   bool
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a select expression
+func Parse(expression string) (*Query, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token in case it is s
+func (p *parser) accept(s string) bool {
+	if p.peek().is(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.unexpected(s)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("%w: expected %s at end of expression", ErrSyntax, expected)
+	}
+	return fmt.Errorf("%w: expected %s, found '%s' at %d", ErrSyntax, expected, t.text, t.pos)
+}
+
+var reservedWords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AS": true, "AND": true, "OR": true, "NOT": true,
+	"IS": true, "NULL": true, "LIKE": true, "ESCAPE": true, "BETWEEN": true, "IN": true, "TRUE": true, "FALSE": true,
+	"CAST": true,
+}
+
+func isReserved(t token) bool {
+	return t.kind == tokenIdent && reservedWords[strings.ToUpper(t.text)]
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	q := &Query{limit: -1}
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	if !p.accept("*") {
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			proj := projection{expr: e}
+			if p.accept("AS") {
+				t := p.next()
+				if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+					p.pos--
+					return nil, p.unexpected("alias")
+				}
+				proj.name = t.text
+			} else if t := p.peek(); (t.kind == tokenIdent && !isReserved(t)) || t.kind == tokenQuotedIdent {
+				proj.name = p.next().text
+			}
+			q.projections = append(q.projections, proj)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("S3Object"); err != nil {
+		return nil, err
+	}
+	if p.accept("[") {
+		if err := p.expect("*"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	q.alias = "S3Object"
+	if p.accept("AS") {
+		t := p.next()
+		if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+			p.pos--
+			return nil, p.unexpected("alias")
+		}
+		q.alias = t.text
+	} else if t := p.peek(); t.kind == tokenIdent && !isReserved(t) {
+		q.alias = p.next().text
+	}
+	if p.accept("WHERE") {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if containsAggregate(e) {
+			return nil, fmt.Errorf("%w: aggregate functions are not allowed in WHERE", ErrSyntax)
+		}
+		q.where = e
+	}
+	if p.accept("LIMIT") {
+		t := p.next()
+		limit, ok := t.value.(int64)
+		if t.kind != tokenNumber || !ok || limit < 0 {
+			p.pos--
+			return nil, p.unexpected("limit")
+		}
+		q.limit = limit
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected("end of expression")
+	}
+
+	for i, proj := range q.projections {
+		if containsAggregate(proj.expr) {
+			q.aggregate = true
+		}
+		if proj.name == "" {
+			if ref, ok := proj.expr.(*reference); ok && !ref.path[len(ref.path)-1].isIndex {
+				proj.name = ref.path[len(ref.path)-1].name
+			} else {
+				proj.name = "_" + strconv.Itoa(i+1)
+			}
+			q.projections[i] = proj
+		}
+	}
+	if q.aggregate {
+		for _, proj := range q.projections {
+			if hasReferenceOutsideAggregate(proj.expr) {
+				return nil, fmt.Errorf("%w: cannot mix aggregate and non-aggregate projections", ErrSyntax)
+			}
+		}
+	}
+	return q, nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.accept("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "NOT", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			if op == "<>" {
+				op = "!="
+			}
+			return &binaryExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	if p.accept("IS") {
+		not := p.accept("NOT")
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{operand: left, not: not}, nil
+	}
+	not := p.accept("NOT")
+	switch {
+	case p.accept("LIKE"):
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		e := &likeExpr{operand: left, pattern: pattern, not: not}
+		if p.accept("ESCAPE") {
+			if e.escape, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+		}
+		return e, nil
+	case p.accept("BETWEEN"):
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &betweenExpr{operand: left, low: low, high: high, not: not}, nil
+	case p.accept("IN"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &inExpr{operand: left, list: list, not: not}, nil
+	case not:
+		return nil, p.unexpected("LIKE, BETWEEN or IN")
+	}
+	return left, nil
+}
+
+// parseList parses a list of expressions up to its closing parenthesis
+func (p *parser) parseList() ([]expr, error) {
+	var list []expr
+	if p.accept(")") {
+		return list, nil
+	}
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		if p.accept(")") {
+			return list, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if !op.is("+") && !op.is("-") && !op.is("||") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if !op.is("*") && !op.is("/") && !op.is("%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenNumber || t.kind == tokenString:
+		p.next()
+		return &literal{value: t.value}, nil
+	case t.is("TRUE"):
+		p.next()
+		return &literal{value: true}, nil
+	case t.is("FALSE"):
+		p.next()
+		return &literal{value: false}, nil
+	case t.is("NULL"):
+		p.next()
+		return &literal{value: nil}, nil
+	case t.is("("):
+		p.next()
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case t.is("CAST"):
+		p.next()
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AS"); err != nil {
+			return nil, err
+		}
+		typ := p.next()
+		if typ.kind != tokenIdent {
+			p.pos--
+			return nil, p.unexpected("type")
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &castExpr{operand: operand, typ: strings.ToUpper(typ.text)}, nil
+	case t.kind == tokenIdent && !isReserved(t) && p.tokens[p.pos+1].is("("):
+		p.next()
+		p.next()
+		f := &funcExpr{name: strings.ToUpper(t.text)}
+		if f.name == "COUNT" && p.accept("*") {
+			f.star = true
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return f, nil
+		}
+		args, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		f.args = args
+		if err := checkFunction(f); err != nil {
+			return nil, err
+		}
+		return f, nil
+	case (t.kind == tokenIdent && !isReserved(t)) || t.kind == tokenQuotedIdent:
+		return p.parseReference()
+	}
+	return nil, p.unexpected("expression")
+}
+
+func (p *parser) parseReference() (expr, error) {
+	t := p.next()
+	ref := &reference{path: []pathElement{{name: t.text, quoted: t.kind == tokenQuotedIdent}}}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+				p.pos--
+				return nil, p.unexpected("name")
+			}
+			ref.path = append(ref.path, pathElement{name: t.text, quoted: t.kind == tokenQuotedIdent})
+		case p.accept("["):
+			t := p.next()
+			index, ok := t.value.(int64)
+			if t.kind != tokenNumber || !ok || index < 0 {
+				p.pos--
+				return nil, p.unexpected("array index")
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			ref.path = append(ref.path, pathElement{index: int(index), isIndex: true})
+		default:
+			return ref, nil
+		}
+	}
+}
+
+// functionArgs holds the minimum and maximum number of arguments of the supported functions, -1 is unlimited
+var functionArgs = map[string][2]int{
+	"COUNT":            {1, 1},
+	"SUM":              {1, 1},
+	"AVG":              {1, 1},
+	"MIN":              {1, 1},
+	"MAX":              {1, 1},
+	"LOWER":            {1, 1},
+	"UPPER":            {1, 1},
+	"TRIM":             {1, 1},
+	"CHAR_LENGTH":      {1, 1},
+	"CHARACTER_LENGTH": {1, 1},
+	"SUBSTRING":        {2, 3},
+	"COALESCE":         {1, -1},
+	"NULLIF":           {2, 2},
+}
+
+func checkFunction(f *funcExpr) error {
+	limits, ok := functionArgs[f.name]
+	if !ok {
+		return fmt.Errorf("%w: unsupported function %s", ErrSyntax, f.name)
+	}
+	if len(f.args) < limits[0] || (limits[1] >= 0 && len(f.args) > limits[1]) {
+		return fmt.Errorf("%w: wrong number of arguments to %s", ErrSyntax, f.name)
+	}
+	if f.isAggregate() && containsAggregate(f.args[0]) {
+		return fmt.Errorf("%w: nested aggregate functions", ErrSyntax)
+	}
+	return nil
+}
+
+// walk calls fn on e and its sub-expressions until fn returns false
+func walk(e expr, fn func(expr) bool) {
+	if !fn(e) {
+		return
+	}
+	switch e := e.(type) {
+	case *unaryExpr:
+		walk(e.operand, fn)
+	case *binaryExpr:
+		walk(e.left, fn)
+		walk(e.right, fn)
+	case *isNullExpr:
+		walk(e.operand, fn)
+	case *likeExpr:
+		walk(e.operand, fn)
+		walk(e.pattern, fn)
+		if e.escape != nil {
+			walk(e.escape, fn)
+		}
+	case *betweenExpr:
+		walk(e.operand, fn)
+		walk(e.low, fn)
+		walk(e.high, fn)
+	case *inExpr:
+		walk(e.operand, fn)
+		for _, item := range e.list {
+			walk(item, fn)
+		}
+	case *castExpr:
+		walk(e.operand, fn)
+	case *funcExpr:
+		for _, arg := range e.args {
+			walk(arg, fn)
+		}
+	}
+}
+
+func containsAggregate(e expr) bool {
+	found := false
+	walk(e, func(e expr) bool {
+		if f, ok := e.(*funcExpr); ok && f.isAggregate() {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// hasReferenceOutsideAggregate reports whether e references the record outside an aggregate function
+func hasReferenceOutsideAggregate(e expr) bool {
+	found := false
+	walk(e, func(e expr) bool {
+		switch e := e.(type) {
+		case *funcExpr:
+			return !e.isAggregate()
+		case *reference:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
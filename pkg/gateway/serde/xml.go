@@ -89,6 +89,44 @@ type ListBucketResult struct {
 	Contents       []Contents       `xml:"Contents"`
 }
 
+// ObjectVersion is either a version or a delete marker of a key, encoded as a Version or a DeleteMarker element
+// respectively, so that versions and delete markers keep their order within a listing.
+type ObjectVersion struct {
+	DeleteMarker bool   `xml:"-"`
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag,omitempty"`
+	Size         int64  `xml:"Size,omitempty"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+}
+
+func (v ObjectVersion) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if v.DeleteMarker {
+		start.Name.Local = "DeleteMarker"
+	} else {
+		start.Name.Local = "Version"
+	}
+	type objectVersion ObjectVersion
+	return e.EncodeElement(objectVersion(v), start)
+}
+
+type ListVersionsResult struct {
+	XMLName             xml.Name         `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult"`
+	Name                string           `xml:"Name"`
+	Prefix              string           `xml:"Prefix"`
+	KeyMarker           string           `xml:"KeyMarker"`
+	VersionIDMarker     string           `xml:"VersionIdMarker"`
+	NextKeyMarker       string           `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string           `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int              `xml:"MaxKeys"`
+	Delimiter           string           `xml:"Delimiter,omitempty"`
+	IsTruncated         bool             `xml:"IsTruncated"`
+	Versions            []ObjectVersion  `xml:"Version"`
+	CommonPrefixes      []CommonPrefixes `xml:"CommonPrefixes"`
+}
+
 type Object struct {
 	Key       string `xml:"Key"`
 	VersionID string `xml:"VersionId,omitempty"`
@@ -154,11 +192,36 @@ type ListPartsOutput struct {
 	Bucket               string                `xml:"Bucket"`
 	IsTruncated          bool                  `xml:"IsTruncated"`
 	Key                  string                `xml:"Key"`
+	UploadID             string                `xml:"UploadId"`
+	StorageClass         string                `xml:"StorageClass"`
 	MaxParts             int32                 `xml:"MaxParts"`
+	PartNumberMarker     int32                 `xml:"PartNumberMarker"`
 	NextPartNumberMarker int32                 `xml:"NextPartNumberMarker"`
 	Parts                []MultipartUploadPart `xml:"Part"`
 }
 
+type MultipartUpload struct {
+	Key          string `xml:"Key"`
+	UploadID     string `xml:"UploadId"`
+	Initiated    string `xml:"Initiated"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type ListMultipartUploadsResult struct {
+	XMLName            xml.Name          `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListMultipartUploadsResult"`
+	Bucket             string            `xml:"Bucket"`
+	KeyMarker          string            `xml:"KeyMarker"`
+	UploadIDMarker     string            `xml:"UploadIdMarker"`
+	NextKeyMarker      string            `xml:"NextKeyMarker,omitempty"`
+	NextUploadIDMarker string            `xml:"NextUploadIdMarker,omitempty"`
+	Prefix             string            `xml:"Prefix"`
+	Delimiter          string            `xml:"Delimiter,omitempty"`
+	MaxUploads         int               `xml:"MaxUploads"`
+	IsTruncated        bool              `xml:"IsTruncated"`
+	Uploads            []MultipartUpload `xml:"Upload"`
+	CommonPrefixes     []CommonPrefixes  `xml:"CommonPrefixes"`
+}
+
 type VersioningConfiguration struct {
 	Enabled bool `xml:"Enabled,omitempty"`
 }
@@ -177,7 +240,75 @@ type Tagging struct {
 	TagSet  TagSet   `xml:"TagSet"`
 }
 
+type CSVInput struct {
+	FileHeaderInfo             string `xml:"FileHeaderInfo"`
+	Comments                   string `xml:"Comments"`
+	QuoteEscapeCharacter       string `xml:"QuoteEscapeCharacter"`
+	RecordDelimiter            string `xml:"RecordDelimiter"`
+	FieldDelimiter             string `xml:"FieldDelimiter"`
+	QuoteCharacter             string `xml:"QuoteCharacter"`
+	AllowQuotedRecordDelimiter bool   `xml:"AllowQuotedRecordDelimiter"`
+}
+
+type JSONInput struct {
+	Type string `xml:"Type"`
+}
+
+type ParquetInput struct{}
+
+type InputSerialization struct {
+	CompressionType string        `xml:"CompressionType"`
+	CSV             *CSVInput     `xml:"CSV"`
+	JSON            *JSONInput    `xml:"JSON"`
+	Parquet         *ParquetInput `xml:"Parquet"`
+}
+
+type CSVOutput struct {
+	QuoteFields          string `xml:"QuoteFields"`
+	QuoteEscapeCharacter string `xml:"QuoteEscapeCharacter"`
+	RecordDelimiter      string `xml:"RecordDelimiter"`
+	FieldDelimiter       string `xml:"FieldDelimiter"`
+	QuoteCharacter       string `xml:"QuoteCharacter"`
+}
+
+type JSONOutput struct {
+	RecordDelimiter string `xml:"RecordDelimiter"`
+}
+
+type OutputSerialization struct {
+	CSV  *CSVOutput  `xml:"CSV"`
+	JSON *JSONOutput `xml:"JSON"`
+}
+
+type RequestProgress struct {
+	Enabled bool `xml:"Enabled"`
+}
+
+type SelectObjectContentRequest struct {
+	XMLName             xml.Name            `xml:"SelectObjectContentRequest"`
+	Expression          string              `xml:"Expression"`
+	ExpressionType      string              `xml:"ExpressionType"`
+	RequestProgress     RequestProgress     `xml:"RequestProgress"`
+	InputSerialization  InputSerialization  `xml:"InputSerialization"`
+	OutputSerialization OutputSerialization `xml:"OutputSerialization"`
+}
+
+type SelectStats struct {
+	BytesScanned   int64 `xml:"BytesScanned"`
+	BytesProcessed int64 `xml:"BytesProcessed"`
+	BytesReturned  int64 `xml:"BytesReturned"`
+}
+
 type LocationResponse struct {
 	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
 	Location string   `xml:",chardata"`
 }
+
+// PostResponse is the response to a form upload requesting success_action_status 201
+type PostResponse struct {
+	XMLName  xml.Name `xml:"PostResponse"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
@@ -45,3 +45,26 @@ func TestMarshal(t *testing.T) {
 		t.Fatalf("expected a buckets array")
 	}
 }
+
+func TestMarshalListVersions(t *testing.T) {
+	response := serde.ListVersionsResult{
+		Name: "repo",
+		Versions: []serde.ObjectVersion{
+			{Key: "main/a", VersionID: "null", IsLatest: true, ETag: `"etag1"`, Size: 1},
+			{Key: "main/a", VersionID: "c2", DeleteMarker: true},
+			{Key: "main/a", VersionID: "c1", ETag: `"etag2"`, Size: 2},
+		},
+	}
+	data, err := xml.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(data)
+	// versions and delete markers keep their order
+	first := strings.Index(s, "<Version><Key>main/a</Key><VersionId>null</VersionId>")
+	marker := strings.Index(s, "<DeleteMarker><Key>main/a</Key><VersionId>c2</VersionId>")
+	last := strings.Index(s, "<Version><Key>main/a</Key><VersionId>c1</VersionId>")
+	if first < 0 || marker < first || last < marker {
+		t.Fatalf("unexpected versions order: %s", s)
+	}
+}
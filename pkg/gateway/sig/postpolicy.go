@@ -0,0 +1,402 @@
+package sig
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/gateway/errors"
+)
+
+const (
+	PostFormFileField      = "file"
+	PostFormKeyField       = "key"
+	PostFormPolicyField    = "policy"
+	PostFormFileNameMarker = "${filename}"
+
+	postFormAlgorithmField   = "x-amz-algorithm"
+	postFormCredentialField  = "x-amz-credential"
+	postFormDateField        = "x-amz-date"
+	postFormSignatureField   = "x-amz-signature"
+	postFormV2AccessKeyField = "awsaccesskeyid"
+	postFormV2SignatureField = "signature"
+	postFormIgnorePrefix     = "x-ignore-"
+
+	// maxPostFormFieldsSize limits the size of the fields preceding the file of a form upload
+	maxPostFormFieldsSize = 1 << 20
+
+	// postPolicyService is the service of the credential scope of a policy signed with signature version 4
+	postPolicyService = "s3"
+
+	postPolicyConditionEq                 = "eq"
+	postPolicyConditionStartsWith         = "starts-with"
+	postPolicyConditionContentLengthRange = "content-length-range"
+)
+
+var (
+	ErrPostPolicyConditionFailed = goerrors.New("post policy condition failed")
+
+	postPolicyRegionRegexp     = regexp.MustCompile(`^[\w\-]+$`)
+	postPolicyCredentialRegexp = regexp.MustCompile(`^` + V4CredentialScopeRegexp.String() + `$`)
+)
+
+// PostForm is a browser-based upload: a multipart/form-data POST request, with its fields followed by the
+// content of the uploaded file.
+type PostForm struct {
+	// Fields are the form fields preceding the file, keyed by their lower-cased names
+	Fields map[string]string
+	// File reads the content of the uploaded file from the request body
+	File     io.Reader
+	FileName string
+	// Policy is the POST policy of the form, set once it is parsed by the authenticator
+	Policy *PostPolicy
+}
+
+// IsPostFormRequest reports whether req is a form upload
+func IsPostFormRequest(req *http.Request) bool {
+	if req.Method != http.MethodPost {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// ParsePostForm reads the fields of a form upload up to its file, leaving the request body positioned at the
+// content of the file. Fields following the file are ignored.
+func ParsePostForm(req *http.Request) (*PostForm, error) {
+	mr, err := req.MultipartReader()
+	if err != nil {
+		return nil, errors.ErrMalformedPOSTRequest
+	}
+	form := &PostForm{Fields: make(map[string]string)}
+	remaining := int64(maxPostFormFieldsSize)
+	for {
+		part, err := mr.NextPart()
+		if goerrors.Is(err, io.EOF) {
+			return nil, errors.ErrPOSTFileRequired
+		}
+		if err != nil {
+			return nil, errors.ErrMalformedPOSTRequest
+		}
+		name := strings.ToLower(part.FormName())
+		if name == "" {
+			continue
+		}
+		if name == PostFormFileField {
+			form.File = part
+			form.FileName = part.FileName()
+			return form, nil
+		}
+		value, err := io.ReadAll(io.LimitReader(part, remaining+1))
+		if err != nil {
+			return nil, errors.ErrMalformedPOSTRequest
+		}
+		remaining -= int64(len(value))
+		if remaining < 0 {
+			return nil, errors.ErrPolicyTooLarge
+		}
+		if _, ok := form.Fields[name]; ok {
+			return nil, errors.ErrMalformedPOSTRequest
+		}
+		form.Fields[name] = string(value)
+	}
+}
+
+// Key returns the key of the uploaded object, with the name of the uploaded file in place of ${filename}
+func (f *PostForm) Key() string {
+	return strings.ReplaceAll(f.Fields[PostFormKeyField], PostFormFileNameMarker, f.FileName)
+}
+
+type postPolicyCondition struct {
+	operator string
+	// field is the lower-cased name of the form field the condition applies to, without its '$' prefix
+	field    string
+	value    string
+	min, max int64
+}
+
+// PostPolicy is the policy of a form upload, holding the conditions its fields must meet
+type PostPolicy struct {
+	Expiration time.Time
+	conditions []postPolicyCondition
+}
+
+// ParsePostPolicy parses a base64 encoded POST policy document
+func ParsePostPolicy(encoded string) (*PostPolicy, error) {
+	document, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.ErrMalformedPolicy
+	}
+	var raw struct {
+		Expiration string            `json:"expiration"`
+		Conditions []json.RawMessage `json:"conditions"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, errors.ErrMalformedPolicy
+	}
+	expiration, err := time.Parse(time.RFC3339Nano, raw.Expiration)
+	if err != nil {
+		return nil, errors.ErrMalformedPolicy
+	}
+	policy := &PostPolicy{Expiration: expiration}
+	for _, rawCondition := range raw.Conditions {
+		conditions, err := parsePostPolicyCondition(rawCondition)
+		if err != nil {
+			return nil, err
+		}
+		policy.conditions = append(policy.conditions, conditions...)
+	}
+	return policy, nil
+}
+
+// parsePostPolicyCondition parses an exact match object, {"field": "value"}, or an array condition:
+// ["eq", "$field", "value"], ["starts-with", "$field", "prefix"] or ["content-length-range", min, max]
+func parsePostPolicyCondition(raw json.RawMessage) ([]postPolicyCondition, error) {
+	var object map[string]string
+	if err := json.Unmarshal(raw, &object); err == nil {
+		conditions := make([]postPolicyCondition, 0, len(object))
+		for field, value := range object {
+			conditions = append(conditions, postPolicyCondition{
+				operator: postPolicyConditionEq,
+				field:    strings.ToLower(strings.TrimPrefix(field, "$")),
+				value:    value,
+			})
+		}
+		return conditions, nil
+	}
+	var array []any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&array); err != nil || len(array) != 3 {
+		return nil, errors.ErrMalformedPolicy
+	}
+	operator, _ := array[0].(string)
+	operator = strings.ToLower(operator)
+	switch operator {
+	case postPolicyConditionEq, postPolicyConditionStartsWith:
+		field, fieldOK := array[1].(string)
+		value, valueOK := array[2].(string)
+		if !fieldOK || !valueOK || !strings.HasPrefix(field, "$") {
+			return nil, errors.ErrMalformedPolicy
+		}
+		return []postPolicyCondition{{
+			operator: operator,
+			field:    strings.ToLower(strings.TrimPrefix(field, "$")),
+			value:    value,
+		}}, nil
+	case postPolicyConditionContentLengthRange:
+		minLength, err := strconv.ParseInt(fmt.Sprint(array[1]), 10, 64)
+		if err != nil {
+			return nil, errors.ErrMalformedPolicy
+		}
+		maxLength, err := strconv.ParseInt(fmt.Sprint(array[2]), 10, 64)
+		if err != nil || minLength < 0 || maxLength < minLength {
+			return nil, errors.ErrMalformedPolicy
+		}
+		return []postPolicyCondition{{
+			operator: operator,
+			min:      minLength,
+			max:      maxLength,
+		}}, nil
+	default:
+		return nil, errors.ErrMalformedPolicy
+	}
+}
+
+// isExemptPostFormField reports whether a form field need not be covered by a condition of the policy
+func isExemptPostFormField(field string) bool {
+	switch field {
+	case PostFormPolicyField, PostFormFileField, postFormSignatureField, postFormV2SignatureField, postFormV2AccessKeyField:
+		return true
+	}
+	return strings.HasPrefix(field, postFormIgnorePrefix)
+}
+
+// VerifyFields verifies the fields of the form and the bucket it uploads to meet the conditions of the policy, and
+// that every field of the form is covered by a condition. The content length range is verified separately, by
+// ContentLengthRange, once the size of the file is known.
+func (p *PostPolicy) VerifyFields(fields map[string]string, bucket string) error {
+	covered := make(map[string]bool)
+	for _, c := range p.conditions {
+		if c.operator == postPolicyConditionContentLengthRange {
+			continue
+		}
+		covered[c.field] = true
+		value, ok := fields[c.field]
+		if c.field == "bucket" {
+			value, ok = bucket, true
+		}
+		if !ok {
+			return fmt.Errorf("%w: missing field %s", ErrPostPolicyConditionFailed, c.field)
+		}
+		switch c.operator {
+		case postPolicyConditionEq:
+			if value != c.value {
+				return fmt.Errorf("%w: [\"eq\", \"$%s\", \"%s\"]", ErrPostPolicyConditionFailed, c.field, c.value)
+			}
+		case postPolicyConditionStartsWith:
+			if !strings.HasPrefix(value, c.value) {
+				return fmt.Errorf("%w: [\"starts-with\", \"$%s\", \"%s\"]", ErrPostPolicyConditionFailed, c.field, c.value)
+			}
+		}
+	}
+	for field := range fields {
+		if !covered[field] && !isExemptPostFormField(field) {
+			return fmt.Errorf("%w: extra input field %s", ErrPostPolicyConditionFailed, field)
+		}
+	}
+	return nil
+}
+
+// ContentLengthRange returns the range of the size of the uploaded file allowed by the policy, if it limits it
+func (p *PostPolicy) ContentLengthRange() (int64, int64, bool) {
+	for _, c := range p.conditions {
+		if c.operator == postPolicyConditionContentLengthRange {
+			return c.min, c.max, true
+		}
+	}
+	return 0, 0, false
+}
+
+type postPolicyContext struct {
+	accessKeyID string
+	// v4 is set for a policy signed with signature version 4, holding its credential scope
+	v4        *V4Auth
+	signature string
+}
+
+func (c postPolicyContext) GetAccessKeyID() string {
+	return c.accessKeyID
+}
+
+// PostPolicyAuthenticator authenticates a form upload by the signature of its POST policy
+type PostPolicyAuthenticator struct {
+	form   *PostForm
+	sigCtx postPolicyContext
+}
+
+func NewPostPolicyAuthenticator(form *PostForm) *PostPolicyAuthenticator {
+	return &PostPolicyAuthenticator{form: form}
+}
+
+func (a *PostPolicyAuthenticator) Parse() (SigContext, error) {
+	fields := a.form.Fields
+	encodedPolicy, ok := fields[PostFormPolicyField]
+	if !ok {
+		return nil, errors.ErrMissingFields
+	}
+	var sigCtx postPolicyContext
+	switch {
+	case fields[postFormAlgorithmField] != "":
+		if !strings.EqualFold(fields[postFormAlgorithmField], V4authHeaderPrefix) {
+			return nil, errors.ErrSignatureVersionNotSupported
+		}
+		v4, err := parsePostPolicyCredential(fields[postFormCredentialField])
+		if err != nil {
+			return nil, err
+		}
+		amzDate, err := time.Parse(v4timeFormat, fields[postFormDateField])
+		if err != nil {
+			return nil, errors.ErrMalformedDate
+		}
+		if amzDate.Format(v4shortTimeFormat) != v4.Date {
+			return nil, errors.ErrMalformedCredentialDate
+		}
+		if fields[postFormSignatureField] == "" {
+			return nil, errors.ErrMissingSignTag
+		}
+		sigCtx = postPolicyContext{
+			accessKeyID: v4.AccessKeyID,
+			v4:          v4,
+			signature:   fields[postFormSignatureField],
+		}
+	case fields[postFormV2AccessKeyField] != "":
+		if fields[postFormV2SignatureField] == "" {
+			return nil, errors.ErrMissingSignTag
+		}
+		sigCtx = postPolicyContext{
+			accessKeyID: fields[postFormV2AccessKeyField],
+			signature:   fields[postFormV2SignatureField],
+		}
+	default:
+		return nil, errors.ErrMissingFields
+	}
+
+	policy, err := ParsePostPolicy(encodedPolicy)
+	if err != nil {
+		return nil, err
+	}
+	a.form.Policy = policy
+	a.sigCtx = sigCtx
+	return sigCtx, nil
+}
+
+// parsePostPolicyCredential parses the credential scope of a policy signed with signature version 4:
+// <access key ID>/<date>/<region>/s3/aws4_request
+func parsePostPolicyCredential(credential string) (*V4Auth, error) {
+	if credential == "" {
+		return nil, errors.ErrMissingCredTag
+	}
+	const scopeParts = 5
+	parts := strings.Split(credential, "/")
+	if len(parts) != scopeParts {
+		return nil, errors.ErrCredMalformed
+	}
+	if !postPolicyRegionRegexp.MatchString(parts[2]) {
+		return nil, errors.ErrMalformedCredentialRegion
+	}
+	if parts[3] != postPolicyService {
+		return nil, errors.ErrInvalidService
+	}
+	if parts[4] != v4scopeTerminator {
+		return nil, errors.ErrInvalidRequestVersion
+	}
+	if !postPolicyCredentialRegexp.MatchString(credential) {
+		return nil, errors.ErrCredMalformed
+	}
+	return &V4Auth{
+		AccessKeyID: parts[0],
+		Date:        parts[1],
+		Region:      parts[2],
+		Service:     parts[3],
+	}, nil
+}
+
+func (a *PostPolicyAuthenticator) Verify(creds *model.Credential) error {
+	encodedPolicy := a.form.Fields[PostFormPolicyField]
+	var signature string
+	if v4 := a.sigCtx.v4; v4 != nil {
+		signingKey := createSignature(creds.SecretAccessKey, v4.Date, v4.Region, v4.Service)
+		signature = hex.EncodeToString(sign(signingKey, encodedPolicy))
+	} else {
+		h := hmac.New(sha1.New, []byte(creds.SecretAccessKey))
+		_, _ = h.Write([]byte(encodedPolicy))
+		signature = base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	if !Equal([]byte(signature), []byte(a.sigCtx.signature)) {
+		return errors.ErrSignatureDoesNotMatch
+	}
+	if time.Now().After(a.form.Policy.Expiration) {
+		return errors.ErrExpiredPresignRequest
+	}
+	return nil
+}
+
+func (a *PostPolicyAuthenticator) String() string {
+	return "post policy"
+}
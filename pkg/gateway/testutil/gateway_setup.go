@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/kv/kvparams"
 	_ "github.com/treeverse/lakefs/pkg/kv/mem"
 	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/permissions"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/treeverse/lakefs/pkg/upload"
@@ -60,7 +62,7 @@ func GetBasicHandler(t *testing.T, authService *FakeAuthService, repoName string
 		storageNamespace = "replay"
 	}
 
-	_, err = c.CreateRepository(ctx, repoName, storageNamespace, "main", false)
+	_, err = c.CreateRepository(ctx, repoName, "", storageNamespace, "main", false)
 	testutil.Must(t, err)
 
 	handler := gateway.NewHandler(authService.Region, c, multipartTracker, blockAdapter, authService, []string{authService.BareDomain}, &stats.NullCollector{}, upload.DefaultPathProvider, nil, config.DefaultLoggingAuditLogLevel, true, false)
@@ -78,6 +80,8 @@ type FakeAuthService struct {
 	SecretAccessKey string `json:"access_secret_key"`
 	UserID          string `json:"user_id"`
 	Region          string `json:"region"`
+	// ReadOnly allows the user only actions reading or listing resources
+	ReadOnly bool `json:"read_only"`
 }
 
 func (m *FakeAuthService) GetCredentials(_ context.Context, accessKey string) (*model.Credential, error) {
@@ -98,6 +102,25 @@ func (m *FakeAuthService) GetUser(_ context.Context, _ string) (*model.User, err
 	}, nil
 }
 
-func (m *FakeAuthService) Authorize(_ context.Context, _ *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+func (m *FakeAuthService) Authorize(_ context.Context, req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+	if m.ReadOnly && !isReadOnlyNode(req.RequiredPermissions) {
+		return &auth.AuthorizationResponse{Allowed: false, Error: auth.ErrInsufficientPermissions}, nil
+	}
 	return &auth.AuthorizationResponse{Allowed: true}, nil
 }
+
+// isReadOnlyNode reports whether all the actions of node read or list resources
+func isReadOnlyNode(node permissions.Node) bool {
+	if node.Permission.Action != "" {
+		_, action, _ := strings.Cut(node.Permission.Action, ":")
+		if !strings.HasPrefix(action, "Read") && !strings.HasPrefix(action, "List") && !strings.HasPrefix(action, "Get") {
+			return false
+		}
+	}
+	for _, n := range node.Nodes {
+		if !isReadOnlyNode(n) {
+			return false
+		}
+	}
+	return true
+}
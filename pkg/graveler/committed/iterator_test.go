@@ -171,12 +171,12 @@ func TestIterator(t *testing.T) {
 					key = committed.Key(p.Keys[len(p.Keys)-1])
 				}
 				manager.EXPECT().
-					NewRangeIterator(gomock.Any(), gomock.Eq(namespace), committed.ID(key)).
+					NewRangeIterator(gomock.Any(), gomock.Eq(graveler.StorageID("")), gomock.Eq(namespace), committed.ID(key)).
 					Return(makeRangeIterator(p.Keys), nil)
 				lastKey = key
 			}
 			rangesIt := testutil.NewCommittedValueIteratorFake(makeRangeRecords(tt.PK))
-			pvi := committed.NewIterator(ctx, manager, namespace, rangesIt)
+			pvi := committed.NewIterator(ctx, manager, "", namespace, rangesIt)
 			defer pvi.Close()
 			assert.Equal(t, tt.PK, keysByRanges(t, pvi))
 			assert.False(t, pvi.NextRange())
@@ -195,13 +195,13 @@ func TestIterator(t *testing.T) {
 					key = committed.Key(p.Keys[len(p.Keys)-1])
 				}
 				manager.EXPECT().
-					NewRangeIterator(gomock.Any(), gomock.Eq(namespace), committed.ID(key)).
+					NewRangeIterator(gomock.Any(), gomock.Eq(graveler.StorageID("")), gomock.Eq(namespace), committed.ID(key)).
 					Return(makeRangeIterator(p.Keys), nil).
 					AnyTimes()
 				lastKey = key
 			}
 			rangesIt := testutil.NewCommittedValueIteratorFake(makeRangeRecords(tt.PK))
-			pvi := committed.NewIterator(ctx, manager, namespace, rangesIt)
+			pvi := committed.NewIterator(ctx, manager, "", namespace, rangesIt)
 			defer pvi.Close()
 
 			if len(tt.PK) == 0 {
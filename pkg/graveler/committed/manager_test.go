@@ -56,7 +56,7 @@ func TestManager_WriteRange(t *testing.T) {
 			rangeWriter := mock.NewMockRangeWriter(ctrl)
 
 			rangeWriter.EXPECT().Abort().Return(nil)
-			rangeManager.EXPECT().GetWriter(context.Background(), committed.Namespace(ns), nil).Return(rangeWriter, nil)
+			rangeManager.EXPECT().GetWriter(context.Background(), graveler.StorageID(""), committed.Namespace(ns), nil).Return(rangeWriter, nil)
 
 			sut := committed.NewCommittedManager(metarangeManager, rangeManager, params)
 
@@ -69,7 +69,7 @@ func TestManager_WriteRange(t *testing.T) {
 			rangeWriter.EXPECT().SetMetadata(committed.MetadataTypeKey, committed.MetadataRangesType)
 
 			it := testutils.NewFakeValueIterator(tt.records)
-			rangeInfo, err := sut.WriteRange(context.Background(), ns, it)
+			rangeInfo, err := sut.WriteRange(context.Background(), "", ns, it)
 			require.NoError(t, err)
 			require.Equal(t, &graveler.RangeInfo{
 				ID:                      graveler.RangeID(writeResult.RangeID),
@@ -126,7 +126,7 @@ func TestManager_WriteMetaRange(t *testing.T) {
 			metarangeWriter := mock.NewMockMetaRangeWriter(ctrl)
 
 			minKey := ""
-			metarangeManager.EXPECT().NewWriter(context.Background(), graveler.StorageNamespace(ns), nil).Return(metarangeWriter)
+			metarangeManager.EXPECT().NewWriter(context.Background(), graveler.StorageID(""), graveler.StorageNamespace(ns), nil).Return(metarangeWriter)
 			metarangeWriter.EXPECT().WriteRange(gomock.Any()).Return(nil).
 				DoAndReturn(func(info committed.Range) error {
 					if string(info.MinKey) < minKey {
@@ -139,7 +139,7 @@ func TestManager_WriteMetaRange(t *testing.T) {
 			metarangeWriter.EXPECT().Abort().Return(nil)
 			sut := committed.NewCommittedManager(metarangeManager, rangeManager, params)
 
-			actualMetarangeID, err := sut.WriteMetaRange(context.Background(), ns, tt.records)
+			actualMetarangeID, err := sut.WriteMetaRange(context.Background(), "", ns, tt.records)
 			require.NoError(t, err)
 			require.Equal(t, &graveler.MetaRangeInfo{ID: expectedMetarangeID}, actualMetarangeID)
 		})
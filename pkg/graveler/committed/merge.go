@@ -19,6 +19,7 @@ type merger struct {
 	dest                 Iterator
 	haveSource, haveDest bool
 	strategy             graveler.MergeStrategy
+	resolver             graveler.MergeResolver
 }
 
 // getNextGEKey moves base iterator from its current position to the next greater equal value
@@ -96,6 +97,22 @@ func (m *merger) writeRecord(writeValue *graveler.ValueRecord) error {
 	return nil
 }
 
+// resolveConflict passes a conflict on a single key to the configured resolver and writes the resolved record.
+// Conflicts are reported as graveler.ErrConflictFound when no resolver is configured.
+func (m *merger) resolveConflict(baseValue, sourceValue, destValue *graveler.ValueRecord) error {
+	if m.resolver == nil {
+		return graveler.ErrConflictFound
+	}
+	value, err := m.resolver.Resolve(m.ctx, baseValue, sourceValue, destValue)
+	if err != nil {
+		return err
+	}
+	if value == nil { // resolver dropped the key
+		return nil
+	}
+	return m.writeRecord(value)
+}
+
 func (m *merger) destBeforeSource(destValue *graveler.ValueRecord) error {
 	baseValue, err := m.getNextGEKey(destValue.Key)
 	if err != nil {
@@ -112,7 +129,11 @@ func (m *merger) destBeforeSource(destValue *graveler.ValueRecord) error {
 				m.haveDest = m.dest.Next()
 				return nil
 			default: // graveler.MergeStrategyNone
-				return graveler.ErrConflictFound
+				if err := m.resolveConflict(baseValue, nil, destValue); err != nil {
+					return err
+				}
+				m.haveDest = m.dest.Next()
+				return nil
 			}
 		}
 		// dest added this record
@@ -141,7 +162,11 @@ func (m *merger) sourceBeforeDest(sourceValue *graveler.ValueRecord) error {
 			case graveler.MergeStrategySrc:
 				break
 			default: // graveler.MergeStrategyNone
-				return graveler.ErrConflictFound
+				if err := m.resolveConflict(baseValue, sourceValue, nil); err != nil {
+					return err
+				}
+				m.haveSource = m.source.Next()
+				return nil
 			}
 		}
 		// source added this record
@@ -161,7 +186,7 @@ func (m *merger) sourceBeforeDest(sourceValue *graveler.ValueRecord) error {
 // is compared to the given strategyToInclude, and if they match - the conflict will
 // be resolved by taking the value from the given range. If not and the configured
 // m.strategy is other than MergeStrategyNone, the record is ignored. If m.strategy is
-// MergeStrategyNone - a conflict will be reported
+// MergeStrategyNone - the conflict is passed to the resolver, and reported in case it cannot be resolved
 func (m *merger) handleAll(iter Iterator, strategyToInclude graveler.MergeStrategy) error {
 	for {
 		select {
@@ -195,12 +220,19 @@ func (m *merger) handleAll(iter Iterator, strategyToInclude graveler.MergeStrate
 			if baseValue == nil || !bytes.Equal(baseValue.Identity, iterValue.Identity) {
 				shouldWriteRecord := true
 				if baseValue != nil && bytes.Equal(baseValue.Key, iterValue.Key) { // deleted by one changed by iter
-					if m.strategy == graveler.MergeStrategyNone { // conflict is only reported if no strategy is selected
-						return graveler.ErrConflictFound
-					}
-					// In case of conflict, if the strategy favors the given iter we
-					// still want to write the record. Otherwise, it will be ignored.
-					if m.strategy != strategyToInclude {
+					switch {
+					case m.strategy == graveler.MergeStrategyNone: // conflict is only reported if no strategy is selected
+						sourceValue, destValue := iterValue, (*graveler.ValueRecord)(nil)
+						if strategyToInclude == graveler.MergeStrategyDest {
+							sourceValue, destValue = nil, iterValue
+						}
+						if err := m.resolveConflict(baseValue, sourceValue, destValue); err != nil {
+							return err
+						}
+						shouldWriteRecord = false
+					case m.strategy != strategyToInclude:
+						// In case of conflict, if the strategy favors the given iter we
+						// still want to write the record. Otherwise, it will be ignored.
 						shouldWriteRecord = false
 					}
 				}
@@ -299,7 +331,7 @@ func (m *merger) handleBothRanges(sourceRange *Range, destRange *Range) error {
 	return nil
 }
 
-func (m *merger) handleConflict(sourceValue *graveler.ValueRecord, destValue *graveler.ValueRecord) error {
+func (m *merger) handleConflict(baseValue, sourceValue, destValue *graveler.ValueRecord) error {
 	switch m.strategy {
 	case graveler.MergeStrategyDest:
 		err := m.writeRecord(destValue)
@@ -312,7 +344,9 @@ func (m *merger) handleConflict(sourceValue *graveler.ValueRecord, destValue *gr
 			return fmt.Errorf("write record: %w", err)
 		}
 	default: // graveler.MergeStrategyNone
-		return graveler.ErrConflictFound
+		if err := m.resolveConflict(baseValue, sourceValue, destValue); err != nil {
+			return err
+		}
 	}
 	m.haveSource = m.source.Next()
 	m.haveDest = m.dest.Next()
@@ -341,7 +375,10 @@ func (m *merger) handleBothKeys(sourceValue *graveler.ValueRecord, destValue *gr
 				case bytes.Equal(destValue.Identity, baseValue.Identity):
 					err = m.writeRecord(sourceValue)
 				default: // both changed the same key
-					return m.handleConflict(sourceValue, destValue)
+					if !bytes.Equal(baseValue.Key, destValue.Key) { // both added the same key
+						baseValue = nil
+					}
+					return m.handleConflict(baseValue, sourceValue, destValue)
 				}
 				if err != nil {
 					return fmt.Errorf("write record: %w", err)
@@ -350,7 +387,7 @@ func (m *merger) handleBothKeys(sourceValue *graveler.ValueRecord, destValue *gr
 				m.haveDest = m.dest.Next()
 				return nil
 			} else { // both added the same key with different identity
-				return m.handleConflict(sourceValue, destValue)
+				return m.handleConflict(nil, sourceValue, destValue)
 			}
 		}
 		// record hasn't changed or both added the same record
@@ -490,7 +527,9 @@ func (m *merger) validWritingRange(it Iterator) bool {
 	}
 }
 
-func Merge(ctx context.Context, writer MetaRangeWriter, base Iterator, source Iterator, destination Iterator, strategy graveler.MergeStrategy) error {
+// Merge writes the three-way merge of source and destination over base using writer.
+// Conflicts not handled by strategy are passed to resolver, when one is given.
+func Merge(ctx context.Context, writer MetaRangeWriter, base Iterator, source Iterator, destination Iterator, strategy graveler.MergeStrategy, resolver graveler.MergeResolver) error {
 	m := merger{
 		ctx:      ctx,
 		logger:   logging.FromContext(ctx),
@@ -499,6 +538,7 @@ func Merge(ctx context.Context, writer MetaRangeWriter, base Iterator, source It
 		source:   source,
 		dest:     destination,
 		strategy: strategy,
+		resolver: resolver,
 	}
 	return m.merge()
 }
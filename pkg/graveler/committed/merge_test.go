@@ -1715,13 +1715,13 @@ func runMergeTests(tests testCases, t *testing.T) {
 						}
 					}
 					metaRangeManager := mock.NewMockMetaRangeManager(ctrl)
-					metaRangeManager.EXPECT().NewWriter(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(writer)
+					metaRangeManager.EXPECT().NewWriter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(writer)
 					sourceMetaRangeID := tst.sourceRange.GetMetaRangeID()
 					destMetaRangeID := tst.destRange.GetMetaRangeID()
 					baseMetaRangeID := tst.baseRange.GetMetaRangeID()
-					metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), baseMetaRangeID).AnyTimes().Return(createIter(tst.baseRange), nil)
-					metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), sourceMetaRangeID).AnyTimes().Return(createIter(tst.sourceRange), nil)
-					metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), destMetaRangeID).AnyTimes().Return(createIter(tst.destRange), nil)
+					metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), gomock.Any(), baseMetaRangeID).AnyTimes().Return(createIter(tst.baseRange), nil)
+					metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), gomock.Any(), sourceMetaRangeID).AnyTimes().Return(createIter(tst.sourceRange), nil)
+					metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), gomock.Any(), destMetaRangeID).AnyTimes().Return(createIter(tst.destRange), nil)
 
 					rangeManager := mock.NewMockRangeManager(ctrl)
 
@@ -1729,7 +1729,7 @@ func runMergeTests(tests testCases, t *testing.T) {
 					metaRangeId := graveler.MetaRangeID("merge")
 					writer.EXPECT().Close(gomock.Any()).Return(&metaRangeId, nil).AnyTimes()
 					committedManager := committed.NewCommittedManager(metaRangeManager, rangeManager, params)
-					_, err := committedManager.Merge(ctx, "ns", destMetaRangeID, sourceMetaRangeID, baseMetaRangeID, mergeStrategy)
+					_, err := committedManager.Merge(ctx, "", "ns", destMetaRangeID, sourceMetaRangeID, baseMetaRangeID, mergeStrategy, nil)
 					if !errors.Is(err, expectedResult.expectedErr) {
 						t.Fatalf("Merge error='%v', expected='%v'", err, expectedResult.expectedErr)
 					}
@@ -1754,7 +1754,7 @@ func TestMergeCancelContext(t *testing.T) {
 		writer := mock.NewMockMetaRangeWriter(ctrl)
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone)
+		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone, nil)
 		assert.True(t, errors.Is(err, context.Canceled), "context canceled error")
 	})
 
@@ -1769,7 +1769,7 @@ func TestMergeCancelContext(t *testing.T) {
 		writer := mock.NewMockMetaRangeWriter(ctrl)
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone)
+		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone, nil)
 		assert.True(t, errors.Is(err, context.Canceled), "context canceled error")
 	})
 
@@ -1786,7 +1786,7 @@ func TestMergeCancelContext(t *testing.T) {
 		writer := mock.NewMockMetaRangeWriter(ctrl)
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone)
+		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone, nil)
 		assert.True(t, errors.Is(err, context.Canceled), "context canceled error")
 	})
 
@@ -1801,7 +1801,55 @@ func TestMergeCancelContext(t *testing.T) {
 		writer := mock.NewMockMetaRangeWriter(ctrl)
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone)
+		err := committed.Merge(ctx, writer, base, source, destination, graveler.MergeStrategyNone, nil)
 		assert.True(t, errors.Is(err, context.Canceled), "context canceled error")
 	})
 }
+
+func TestMergeResolver(t *testing.T) {
+	newIterators := func() (committed.Iterator, committed.Iterator, committed.Iterator) {
+		base := testutil.NewFakeIterator().
+			AddRange(&committed.Range{ID: "base", MinKey: committed.Key("a"), MaxKey: committed.Key("c"), Count: 3}).
+			AddValueRecords(makeV("a", "base:a"), makeV("b", "base:b"), makeV("c", "base:c"))
+		source := testutil.NewFakeIterator().
+			AddRange(&committed.Range{ID: "source", MinKey: committed.Key("a"), MaxKey: committed.Key("c"), Count: 2}).
+			AddValueRecords(makeV("a", "source:a"), makeV("c", "source:c"))
+		destination := testutil.NewFakeIterator().
+			AddRange(&committed.Range{ID: "dest", MinKey: committed.Key("a"), MaxKey: committed.Key("c"), Count: 3}).
+			AddValueRecords(makeV("a", "dest:a"), makeV("b", "base:b"), makeV("c", "dest:c"))
+		return base, source, destination
+	}
+
+	t.Run("resolved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := mock.NewMockMetaRangeWriter(ctrl)
+		writer.EXPECT().WriteRecord(newRecordMatcher("a", "source:a"))
+		writer.EXPECT().WriteRecord(newRecordMatcher("c", "dest:c"))
+		var resolved []string
+		resolver := graveler.MergeResolverFunc(func(_ context.Context, base, source, dest *graveler.ValueRecord) (*graveler.ValueRecord, error) {
+			if base == nil || source == nil || dest == nil {
+				t.Fatalf("Resolve(%v, %v, %v) expected all values", base, source, dest)
+			}
+			resolved = append(resolved, string(base.Key))
+			if string(source.Key) == "a" {
+				return source, nil
+			}
+			return dest, nil
+		})
+		base, source, destination := newIterators()
+		err := committed.Merge(context.Background(), writer, base, source, destination, graveler.MergeStrategyNone, resolver)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "c"}, resolved)
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := mock.NewMockMetaRangeWriter(ctrl)
+		resolver := graveler.MergeResolverFunc(func(context.Context, *graveler.ValueRecord, *graveler.ValueRecord, *graveler.ValueRecord) (*graveler.ValueRecord, error) {
+			return nil, graveler.ErrConflictFound
+		})
+		base, source, destination := newIterators()
+		err := committed.Merge(context.Background(), writer, base, source, destination, graveler.MergeStrategyNone, resolver)
+		assert.ErrorIs(t, err, graveler.ErrConflictFound)
+	})
+}
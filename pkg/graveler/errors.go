@@ -15,10 +15,12 @@ var (
 	ErrNotFound                     = wrapError(ErrUserVisible, "not found")
 	ErrNotUnique                    = wrapError(ErrUserVisible, "not unique")
 	ErrPreconditionFailed           = errors.New("precondition failed")
+	ErrBranchHeadMismatch           = wrapError(ErrPreconditionFailed, "branch head does not match expected commit")
 	ErrProtectedBranch              = errors.New("protected branch")
 	ErrWriteToProtectedBranch       = wrapError(ErrProtectedBranch, "cannot write to protected branch")
 	ErrReadingFromStore             = errors.New("cannot read from store")
 	ErrCommitToProtectedBranch      = wrapError(ErrProtectedBranch, "cannot commit to protected branch")
+	ErrUnsignedCommit               = wrapError(ErrProtectedBranch, "protected branch requires signed commits")
 	ErrInvalidValue                 = fmt.Errorf("invalid value: %w", ErrInvalid)
 	ErrInvalidMergeBase             = fmt.Errorf("only 2 commits allowed in FindMergeBase: %w", ErrInvalidValue)
 	ErrNoCommitGeneration           = errors.New("no commit generation")
@@ -26,6 +28,7 @@ var (
 	ErrInvalidMergeStrategy         = wrapError(ErrUserVisible, "invalid merge strategy")
 	ErrInvalidRef                   = fmt.Errorf("ref: %w", ErrInvalidValue)
 	ErrInvalidCommitID              = fmt.Errorf("commit id: %w", ErrInvalidValue)
+	ErrInvalidSignature             = fmt.Errorf("commit signature: %w", ErrInvalidValue)
 	ErrInvalidBranchID              = fmt.Errorf("branch id: %w", ErrInvalidValue)
 	ErrInvalidTagID                 = fmt.Errorf("tag id: %w", ErrInvalidValue)
 	ErrInvalid                      = errors.New("validation error")
@@ -46,11 +49,14 @@ var (
 	ErrCommitAlreadyExists          = fmt.Errorf("commit already exists: %w", ErrNotUnique)
 	ErrLinkAddressNotFound          = fmt.Errorf("address token %w", ErrNotFound)
 	ErrLinkAddressExpired           = errors.New("address token has expired")
+	ErrReflogEntryNotFound          = fmt.Errorf("reflog entry %w", ErrNotFound)
+	ErrReflogNoPreviousHead         = fmt.Errorf("reflog entry has no previous head: %w", ErrInvalidValue)
 	ErrDirtyBranch                  = wrapError(ErrUserVisible, "uncommitted changes (dirty branch)")
 	ErrMetaRangeNotFound            = errors.New("metarange not found")
 	ErrLockNotAcquired              = errors.New("lock not acquired")
 	ErrRevertMergeNoParent          = wrapError(ErrUserVisible, "must specify 1-based parent number for reverting merge commit")
 	ErrCherryPickMergeNoParent      = wrapError(ErrUserVisible, "must specify 1-based parent number for cherry-picking merge commit")
+	ErrRebaseMergeBase              = wrapError(ErrUserVisible, "merge base is not on the first-parent history of the branch")
 	ErrAddCommitNoParent            = errors.New("added commit must have a parent")
 	ErrMultipleParents              = errors.New("cannot have more than a single parent")
 	ErrParentOutOfRange             = errors.New("given commit does not have the given parent number")
@@ -138,6 +138,65 @@ var mergeStrategyString = []string{
 	MergeStrategySrcWinsStr,
 }
 
+// MergeResolver resolves a conflict found on a single key during merge.
+// base, source and dest hold the values of the key on the merge base, the source and the destination;
+// a nil record means the key does not exist on that side.
+// Resolve returns the record to write, nil in order to drop the key, or ErrConflictFound when the conflict cannot be
+// resolved.
+type MergeResolver interface {
+	Resolve(ctx context.Context, base, source, dest *ValueRecord) (*ValueRecord, error)
+}
+
+// MergeResolverFunc is an adapter to allow the use of an ordinary function as a MergeResolver
+type MergeResolverFunc func(ctx context.Context, base, source, dest *ValueRecord) (*ValueRecord, error)
+
+func (f MergeResolverFunc) Resolve(ctx context.Context, base, source, dest *ValueRecord) (*ValueRecord, error) {
+	return f(ctx, base, source, dest)
+}
+
+// MergeResolverRule resolves conflicts on keys starting with Prefix using Resolver
+type MergeResolverRule struct {
+	Prefix   Key
+	Resolver MergeResolver
+}
+
+// MergeResolverRules is a MergeResolver passing each conflict to the resolver of the first rule matching its key.
+// Conflicts on keys no rule matches are not resolved.
+type MergeResolverRules []MergeResolverRule
+
+func (r MergeResolverRules) Resolve(ctx context.Context, base, source, dest *ValueRecord) (*ValueRecord, error) {
+	var key Key
+	for _, record := range []*ValueRecord{source, dest, base} {
+		if record != nil {
+			key = record.Key
+			break
+		}
+	}
+	for _, rule := range r {
+		if bytes.HasPrefix(key, rule.Prefix) {
+			return rule.Resolver.Resolve(ctx, base, source, dest)
+		}
+	}
+	return nil, ErrConflictFound
+}
+
+// NewStrategyMergeResolver returns a MergeResolver resolving every conflict as the named merge strategy does, or nil
+// in case the strategy does not resolve conflicts.
+func NewStrategyMergeResolver(strategy string) MergeResolver {
+	switch strategy {
+	case MergeStrategyDestWinsStr:
+		return MergeResolverFunc(func(_ context.Context, _, _, dest *ValueRecord) (*ValueRecord, error) {
+			return dest, nil
+		})
+	case MergeStrategySrcWinsStr:
+		return MergeResolverFunc(func(_ context.Context, _, source, _ *ValueRecord) (*ValueRecord, error) {
+			return source, nil
+		})
+	default:
+		return nil
+	}
+}
+
 // MetaRangeAddress is the URI of a metarange file.
 type MetaRangeAddress string
 
@@ -186,13 +245,25 @@ func WithStageOnly(v bool) GetOptionsFunc {
 
 type SetOptions struct {
 	IfAbsent bool
+	// Condition, if set, is checked by Set against the current value of the key, staged or committed. The set fails
+	// with the error it returns, typically ErrPreconditionFailed.
+	Condition ConditionFunc
 	// MaxTries set number of times we try to perform the operation before we fail with BranchWriteMaxTries.
 	// By default, 0 - we try BranchWriteMaxTries
 	MaxTries int
 	// Force set to true will bypass repository read-only protection.
 	Force bool
+	// ExpectedCommitID, if set, fails operations updating the branch with ErrBranchHeadMismatch unless the branch
+	// head is this commit.
+	ExpectedCommitID CommitID
+	// User is the user running the operation, recorded in the reflog of the branches it moves. Operations creating
+	// a commit record its committer instead.
+	User string
 }
 
+// ConditionFunc checks the current value of a key, nil if the key does not exist, before the key is set
+type ConditionFunc func(currentValue *Value) error
+
 type SetOptionsFunc func(opts *SetOptions)
 
 func WithIfAbsent(v bool) SetOptionsFunc {
@@ -201,17 +272,58 @@ func WithIfAbsent(v bool) SetOptionsFunc {
 	}
 }
 
+func WithCondition(condition ConditionFunc) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.Condition = condition
+	}
+}
+
+// checkPreconditions verifies the staged value of a key, nil if the key does not exist, meets the preconditions of
+// setting it
+func (o *SetOptions) checkPreconditions(currentValue *Value) error {
+	if o.IfAbsent && currentValue != nil {
+		return ErrPreconditionFailed
+	}
+	if o.Condition != nil {
+		return o.Condition(currentValue)
+	}
+	return nil
+}
+
+func WithUser(user string) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.User = user
+	}
+}
+
 func WithForce(v bool) SetOptionsFunc {
 	return func(opts *SetOptions) {
 		opts.Force = v
 	}
 }
 
+func WithExpectedCommitID(id CommitID) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.ExpectedCommitID = id
+	}
+}
+
+// checkExpectedCommitID verifies the branch head matches the expected commit ID option, if set
+func checkExpectedCommitID(options *SetOptions, branch *Branch) error {
+	if options.ExpectedCommitID == "" || options.ExpectedCommitID == branch.CommitID {
+		return nil
+	}
+	return fmt.Errorf("head %s, expected %s: %w", branch.CommitID, options.ExpectedCommitID, ErrBranchHeadMismatch)
+}
+
 // function/methods receiving the following basic types could assume they passed validation
 
 // StorageNamespace is the URI to the storage location
 type StorageNamespace string
 
+// StorageID identifies the blockstore holding the repository data, empty for the default blockstore
+type StorageID string
+
 // RepositoryID is an identifier for a repo
 type RepositoryID string
 
@@ -262,6 +374,7 @@ type Metadata map[string]string
 
 // Repository represents repository metadata
 type Repository struct {
+	StorageID        StorageID
 	StorageNamespace StorageNamespace
 	CreationDate     time.Time
 	DefaultBranchID  BranchID
@@ -281,8 +394,9 @@ type RepositoryMetadata map[string]string
 
 const MetadataKeyLastImportTimeStamp = ".lakefs.last.import.timestamp"
 
-func NewRepository(storageNamespace StorageNamespace, defaultBranchID BranchID, readOnly bool) Repository {
+func NewRepository(storageID StorageID, storageNamespace StorageNamespace, defaultBranchID BranchID, readOnly bool) Repository {
 	return Repository{
+		StorageID:        storageID,
 		StorageNamespace: storageNamespace,
 		CreationDate:     time.Now().UTC(),
 		DefaultBranchID:  defaultBranchID,
@@ -367,6 +481,9 @@ type Commit struct {
 	Parents      CommitParents
 	Metadata     Metadata
 	Generation   CommitGeneration
+	// Signature is a detached signature over the commit Identity. It is not part of the identity itself, so signing
+	// does not change the commit ID.
+	Signature []byte
 }
 
 func NewCommit() Commit {
@@ -420,6 +537,24 @@ type TagRecord struct {
 	CommitID CommitID
 }
 
+// ReflogEntry records a movement of a branch head
+type ReflogEntry struct {
+	ID           string
+	BranchID     BranchID
+	OldCommitID  CommitID
+	NewCommitID  CommitID
+	Operation    string
+	User         string
+	CreationDate time.Time
+}
+
+// ReflogRecord is recorded in the reflog of a branch whose head is moved: the operation moving it and the user
+// running the operation
+type ReflogRecord struct {
+	Operation string
+	User      string
+}
+
 // Diff represents a change in value based on key
 type Diff struct {
 	Type         DiffType
@@ -428,6 +563,15 @@ type Diff struct {
 	LeftIdentity []byte // the Identity of the value on the left side of the diff
 }
 
+// MergeConflict holds the values of a key that conflicts when merging a source into a destination.
+// A nil value means the key does not exist on that side.
+type MergeConflict struct {
+	Key         Key
+	Base        *Value
+	Source      *Value
+	Destination *Value
+}
+
 func (d *Diff) Copy() *Diff {
 	return &Diff{
 		Type:         d.Type,
@@ -451,6 +595,19 @@ type CommitParams struct {
 	// SourceMetaRange - If exists, use it directly. Fail if branch has uncommitted changes
 	SourceMetaRange *MetaRangeID
 	AllowEmpty      bool
+	// Signature, if set, is the detached signature of the new commit. It requires Date and SourceMetaRange, and the
+	// commit fails with ErrInvalidSignature unless the signature was made by a trusted key.
+	Signature []byte
+	// Prefixes, if set, limits the commit to the staged changes under these prefixes, other changes remain staged
+	Prefixes []Prefix
+}
+
+// MergeOptions are the options of a merge, in addition to its strategy
+type MergeOptions struct {
+	// Resolver, if set, resolves the conflicts not handled by the merge strategy. The strategy then only names it.
+	Resolver MergeResolver
+	// Squash set to true creates a merge commit with the destination as its only parent
+	Squash bool
 }
 
 type GarbageCollectionRunMetadata struct {
@@ -494,11 +651,12 @@ type VersionController interface {
 	// GetRepository returns the Repository metadata object for the given RepositoryID
 	GetRepository(ctx context.Context, repositoryID RepositoryID) (*RepositoryRecord, error)
 
-	// CreateRepository stores a new Repository under RepositoryID with the given Branch as default branch
-	CreateRepository(ctx context.Context, repositoryID RepositoryID, storageNamespace StorageNamespace, branchID BranchID, readOnly bool) (*RepositoryRecord, error)
+	// CreateRepository stores a new Repository under RepositoryID with the given Branch as default branch. The
+	// storage namespace is on the blockstore of storageID, empty for the default blockstore.
+	CreateRepository(ctx context.Context, repositoryID RepositoryID, storageID StorageID, storageNamespace StorageNamespace, branchID BranchID, readOnly bool) (*RepositoryRecord, error)
 
 	// CreateBareRepository stores a new Repository under RepositoryID with no initial branch or commit
-	CreateBareRepository(ctx context.Context, repositoryID RepositoryID, storageNamespace StorageNamespace, defaultBranchID BranchID, readOnly bool) (*RepositoryRecord, error)
+	CreateBareRepository(ctx context.Context, repositoryID RepositoryID, storageID StorageID, storageNamespace StorageNamespace, defaultBranchID BranchID, readOnly bool) (*RepositoryRecord, error)
 
 	// ListRepositories returns iterator to scan repositories
 	ListRepositories(ctx context.Context) (RepositoryIterator, error)
@@ -533,8 +691,9 @@ type VersionController interface {
 	// ListTags lists tags on a repository
 	ListTags(ctx context.Context, repository *RepositoryRecord) (TagIterator, error)
 
-	// Log returns an iterator starting at commit ID up to repository root
-	Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since *time.Time) (CommitIterator, error)
+	// Log returns an iterator starting at commit ID up to repository root.
+	// Commits created before 'since' or after 'until' are skipped, a nil value means no bound.
+	Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since, until *time.Time) (CommitIterator, error)
 
 	// ListBranches lists branches on repositories
 	ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error)
@@ -542,6 +701,12 @@ type VersionController interface {
 	// DeleteBranch deletes branch from repository
 	DeleteBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) error
 
+	// ListReflog lists the movements of the branch head, newest first
+	ListReflog(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (ReflogIterator, error)
+
+	// RestoreReflog points the branch back at the head it had before the movement recorded by the reflog entry
+	RestoreReflog(ctx context.Context, repository *RepositoryRecord, branchID BranchID, entryID string, opts ...SetOptionsFunc) (*Branch, error)
+
 	// Commit the staged data and returns a commit ID that references that change
 	//   ErrNothingToCommit in case there is no data in stage
 	Commit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, commitParams CommitParams, opts ...SetOptionsFunc) (CommitID, error)
@@ -560,6 +725,9 @@ type VersionController interface {
 	// GetCommit returns the Commit metadata object for the given CommitID
 	GetCommit(ctx context.Context, repository *RepositoryRecord, commitID CommitID) (*Commit, error)
 
+	// VerifyCommitSignature reports whether the commit is signed by a trusted key
+	VerifyCommitSignature(commit Commit) bool
+
 	// Dereference returns the resolved ref information based on 'ref' reference
 	Dereference(ctx context.Context, repository *RepositoryRecord, ref Ref) (*ResolvedRef, error)
 
@@ -587,8 +755,23 @@ type VersionController interface {
 	// CherryPick creates a patch to the commit given as 'ref', and applies it as a new commit on the given branch.
 	CherryPick(ctx context.Context, repository *RepositoryRecord, id BranchID, reference Ref, number *int, committer string, opts ...SetOptionsFunc) (CommitID, error)
 
+	// Transaction applies the operations on the branches of the repository in case all preconditions are met.
+	// Either all operations are applied or none, and readers observe either all of the branches moved or none of
+	// them. It returns the new head commit of each branch changed by the transaction.
+	Transaction(ctx context.Context, repository *RepositoryRecord, params TransactionParams, opts ...SetOptionsFunc) (map[BranchID]CommitID, error)
+
+	// Rebase replays the first-parent commits of the branch since its merge base with 'onto', on top of 'onto'.
+	// It returns the commit id of the new branch head.
+	Rebase(ctx context.Context, repository *RepositoryRecord, branchID BranchID, onto Ref, committer string, opts ...SetOptionsFunc) (CommitID, error)
+
 	// Merge merges 'source' into 'destination' and returns the commit id for the created merge commit.
-	Merge(ctx context.Context, repository *RepositoryRecord, destination BranchID, source Ref, commitParams CommitParams, strategy string, opts ...SetOptionsFunc) (CommitID, error)
+	// A squash merge creates a commit with the destination as its single parent.
+	Merge(ctx context.Context, repository *RepositoryRecord, destination BranchID, source Ref, commitParams CommitParams, strategy string, mergeOptions MergeOptions, opts ...SetOptionsFunc) (CommitID, error)
+
+	// MergeConflicts lists the keys conflicting when merging 'source' into 'destination' using 'strategy' and
+	// 'resolver', without merging. It returns up to 'limit' conflicts with keys greater than 'after', and whether
+	// there are more conflicts.
+	MergeConflicts(ctx context.Context, repository *RepositoryRecord, destination BranchID, source Ref, strategy string, resolver MergeResolver, after Key, limit int) ([]*MergeConflict, bool, error)
 
 	// Import creates a merge-commit in the destination branch using the source MetaRangeID, overriding any destination
 	// range keys that have the same prefix as the source range keys.
@@ -633,6 +816,10 @@ type VersionController interface {
 
 	GCNewRunID() string
 
+	// GetExpiredCommits splits the commits of the repository by rules, the same way garbage collection does, into
+	// active commits and expired commits. Both map commit IDs to their metarange IDs.
+	GetExpiredCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) (active, expired map[CommitID]MetaRangeID, err error)
+
 	// GetBranchProtectionRules return all branch protection rules for the repository.
 	// The returned checksum represents the current state of the rules, and can be passed to SetBranchProtectionRules for a conditional update.
 	GetBranchProtectionRules(ctx context.Context, repository *RepositoryRecord) (*BranchProtectionRules, *string, error)
@@ -670,6 +857,12 @@ type Plumbing interface {
 	GetMetaRange(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) (MetaRangeAddress, error)
 	// GetRange returns information where rangeID is stored.
 	GetRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (RangeAddress, error)
+	// ListRanges returns the ranges of the metarange, ordered by their keys.
+	ListRanges(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) ([]*RangeInfo, error)
+	// ListRange returns a ValueIterator over the values of the range.
+	ListRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (ValueIterator, error)
+	// ListCommits returns an iterator over all known commits, ordered by their commit ID
+	ListCommits(ctx context.Context, repository *RepositoryRecord) (CommitIterator, error)
 	// WriteRange creates a new Range from the iterator values.
 	// Keeps Range closing logic, so might not flush all values to the range.
 	// Returns the created range info and in addition a list of records which were skipped due to out of order listing
@@ -760,6 +953,15 @@ type TagIterator interface {
 	Close()
 }
 
+// ReflogIterator iterates over the reflog of a branch, newest entry first
+type ReflogIterator interface {
+	Next() bool
+	SeekGE(id string)
+	Value() *ReflogEntry
+	Err() error
+	Close()
+}
+
 type CommitIterator interface {
 	Next() bool
 	SeekGE(id CommitID)
@@ -812,16 +1014,29 @@ type RefManager interface {
 	GetBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*Branch, error)
 
 	// CreateBranch creates a branch with the given id and Branch metadata
-	CreateBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, branch Branch) error
+	CreateBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, branch Branch, record ReflogRecord) error
 
 	// SetBranch points the given BranchID at the given Branch metadata
-	SetBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, branch Branch) error
+	SetBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, branch Branch, record ReflogRecord) error
+
+	// BranchUpdate Conditional set of branch with validation callback. A moved branch head is recorded in the
+	// branch reflog with record, failing to record it returns an error although the branch has moved.
+	BranchUpdate(ctx context.Context, repository *RepositoryRecord, branchID BranchID, record ReflogRecord, f BranchUpdateFunc) error
 
-	// BranchUpdate Conditional set of branch with validation callback
-	BranchUpdate(ctx context.Context, repository *RepositoryRecord, branchID BranchID, f BranchUpdateFunc) error
+	// BranchesUpdate conditionally sets multiple branches at once, readers see either all of them updated or none
+	// of them. Each branch is passed to its validation callback, which returns nil to keep the branch as is. The
+	// update fails with kv.ErrPredicateFailed if any of the branches is updated concurrently, before the branches
+	// are set. Moved branch heads are recorded in the branch reflog like BranchUpdate.
+	BranchesUpdate(ctx context.Context, repository *RepositoryRecord, record ReflogRecord, updates map[BranchID]BranchUpdateFunc) error
 
 	// DeleteBranch deletes the branch
-	DeleteBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error
+	DeleteBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, record ReflogRecord) error
+
+	// ListReflog lists the reflog entries of the branch, newest first
+	ListReflog(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (ReflogIterator, error)
+
+	// GetReflogEntry returns the reflog entry of the branch by its ID
+	GetReflogEntry(ctx context.Context, repository *RepositoryRecord, branchID BranchID, entryID string) (*ReflogEntry, error)
 
 	// ListBranches lists branches
 	ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error)
@@ -863,8 +1078,9 @@ type RefManager interface {
 	// and internally: https://github.com/treeverse/lakeFS/blob/09954804baeb36ada74fa17d8fdc13a38552394e/index/dag/commits.go
 	FindMergeBase(ctx context.Context, repository *RepositoryRecord, commitIDs ...CommitID) (*Commit, error)
 
-	// Log returns an iterator starting at commit ID up to repository root
-	Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since *time.Time) (CommitIterator, error)
+	// Log returns an iterator starting at commit ID up to repository root.
+	// Commits created before 'since' or after 'until' are skipped, a nil value means no bound.
+	Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since, until *time.Time) (CommitIterator, error)
 
 	// ListCommits returns an iterator over all known commits, ordered by their commit ID
 	ListCommits(ctx context.Context, repository *RepositoryRecord) (CommitIterator, error)
@@ -896,53 +1112,59 @@ type RefManager interface {
 // it is responsible for de-duping them, persisting them and providing basic diff, merge and list capabilities
 type CommittedManager interface {
 	// Get returns the provided key, if exists, from the provided MetaRangeID
-	Get(ctx context.Context, ns StorageNamespace, rangeID MetaRangeID, key Key) (*Value, error)
+	Get(ctx context.Context, storageID StorageID, ns StorageNamespace, rangeID MetaRangeID, key Key) (*Value, error)
 
 	// Exists returns true if a MetaRange matching ID exists in namespace ns.
-	Exists(ctx context.Context, ns StorageNamespace, id MetaRangeID) (bool, error)
+	Exists(ctx context.Context, storageID StorageID, ns StorageNamespace, id MetaRangeID) (bool, error)
 
 	// WriteMetaRangeByIterator flushes the iterator to a new MetaRange and returns the created ID.
-	WriteMetaRangeByIterator(ctx context.Context, ns StorageNamespace, it ValueIterator, metadata Metadata) (*MetaRangeID, error)
+	WriteMetaRangeByIterator(ctx context.Context, storageID StorageID, ns StorageNamespace, it ValueIterator, metadata Metadata) (*MetaRangeID, error)
 
 	// WriteRange creates a new Range from the iterator values.
 	// Keeps Range closing logic, so might not exhaust the iterator.
-	WriteRange(ctx context.Context, ns StorageNamespace, it ValueIterator) (*RangeInfo, error)
+	WriteRange(ctx context.Context, storageID StorageID, ns StorageNamespace, it ValueIterator) (*RangeInfo, error)
 
 	// WriteMetaRange creates a new MetaRange from the given Ranges.
-	WriteMetaRange(ctx context.Context, ns StorageNamespace, ranges []*RangeInfo) (*MetaRangeInfo, error)
+	WriteMetaRange(ctx context.Context, storageID StorageID, ns StorageNamespace, ranges []*RangeInfo) (*MetaRangeInfo, error)
 
 	// List takes a given tree and returns an ValueIterator
-	List(ctx context.Context, ns StorageNamespace, rangeID MetaRangeID) (ValueIterator, error)
+	List(ctx context.Context, storageID StorageID, ns StorageNamespace, rangeID MetaRangeID) (ValueIterator, error)
 
 	// Diff receives two metaRanges and returns a DiffIterator describing all differences between them.
 	// This is similar to a two-dot diff in git (left..right)
-	Diff(ctx context.Context, ns StorageNamespace, left, right MetaRangeID) (DiffIterator, error)
+	Diff(ctx context.Context, storageID StorageID, ns StorageNamespace, left, right MetaRangeID) (DiffIterator, error)
 
 	// Compare returns the difference between 'source' and 'destination', relative to a merge base 'base'.
 	// This is similar to a three-dot diff in git.
-	Compare(ctx context.Context, ns StorageNamespace, destination, source, base MetaRangeID) (DiffIterator, error)
+	Compare(ctx context.Context, storageID StorageID, ns StorageNamespace, destination, source, base MetaRangeID) (DiffIterator, error)
 
 	// Merge applies changes from 'source' to 'destination', relative to a merge base 'base' and
 	// returns the ID of the new metarange. This is similar to a git merge operation.
 	// The resulting tree is expected to be immediately addressable.
-	Merge(ctx context.Context, ns StorageNamespace, destination, source, base MetaRangeID, strategy MergeStrategy, opts ...SetOptionsFunc) (MetaRangeID, error)
+	Merge(ctx context.Context, storageID StorageID, ns StorageNamespace, destination, source, base MetaRangeID, strategy MergeStrategy, resolver MergeResolver, opts ...SetOptionsFunc) (MetaRangeID, error)
 
 	// Import sync changes from 'source' to 'destination'. All the given prefixes are completely overridden on the resulting metarange. Returns the ID of the new
 	// metarange.
-	Import(ctx context.Context, ns StorageNamespace, destination, source MetaRangeID, prefixes []Prefix, opts ...SetOptionsFunc) (MetaRangeID, error)
+	Import(ctx context.Context, storageID StorageID, ns StorageNamespace, destination, source MetaRangeID, prefixes []Prefix, opts ...SetOptionsFunc) (MetaRangeID, error)
 
 	// Commit is the act of taking an existing metaRange (snapshot) and applying a set of changes to it.
 	// A change is either an entity to write/overwrite, or a tombstone to mark a deletion
 	// it returns a new MetaRangeID that is expected to be immediately addressable
-	Commit(ctx context.Context, ns StorageNamespace, baseMetaRangeID MetaRangeID, changes ValueIterator, allowEmpty bool, opts ...SetOptionsFunc) (MetaRangeID, DiffSummary, error)
+	Commit(ctx context.Context, storageID StorageID, ns StorageNamespace, baseMetaRangeID MetaRangeID, changes ValueIterator, allowEmpty bool, opts ...SetOptionsFunc) (MetaRangeID, DiffSummary, error)
 
 	// GetMetaRange returns information where metarangeID is stored.
-	GetMetaRange(ctx context.Context, ns StorageNamespace, metaRangeID MetaRangeID) (MetaRangeAddress, error)
+	GetMetaRange(ctx context.Context, storageID StorageID, ns StorageNamespace, metaRangeID MetaRangeID) (MetaRangeAddress, error)
 	// GetRange returns information where rangeID is stored.
-	GetRange(ctx context.Context, ns StorageNamespace, rangeID RangeID) (RangeAddress, error)
+	GetRange(ctx context.Context, storageID StorageID, ns StorageNamespace, rangeID RangeID) (RangeAddress, error)
 
 	// GetRangeIDByKey returns the RangeID that contains the given key.
-	GetRangeIDByKey(ctx context.Context, ns StorageNamespace, id MetaRangeID, key Key) (RangeID, error)
+	GetRangeIDByKey(ctx context.Context, storageID StorageID, ns StorageNamespace, id MetaRangeID, key Key) (RangeID, error)
+
+	// ListRanges returns the ranges of the metarange, ordered by their keys.
+	ListRanges(ctx context.Context, storageID StorageID, ns StorageNamespace, metaRangeID MetaRangeID) ([]*RangeInfo, error)
+
+	// ListRange returns a ValueIterator over the values of the range.
+	ListRange(ctx context.Context, storageID StorageID, ns StorageNamespace, rangeID RangeID) (ValueIterator, error)
 }
 
 // StagingManager manages entries in a staging area, denoted by a staging token
@@ -992,6 +1214,10 @@ func (ns StorageNamespace) String() string {
 	return string(ns)
 }
 
+func (id StorageID) String() string {
+	return string(id)
+}
+
 func (id BranchID) String() string {
 	return string(id)
 }
@@ -1051,6 +1277,8 @@ type Graveler struct {
 	logger              logging.Logger
 	BranchUpdateBackOff backoff.BackOff
 	deleteSensor        *DeleteSensor
+	signer              CommitSigner
+	branchLocker        BranchLocker
 }
 
 func NewGraveler(committedManager CommittedManager, stagingManager StagingManager, refManager RefManager, gcManager GarbageCollectionManager, protectedBranchesManager ProtectedBranchesManager, deleteSensor *DeleteSensor) *Graveler {
@@ -1078,13 +1306,13 @@ func (g *Graveler) GetRepository(ctx context.Context, repositoryID RepositoryID)
 	return g.RefManager.GetRepository(ctx, repositoryID)
 }
 
-func (g *Graveler) CreateRepository(ctx context.Context, repositoryID RepositoryID, storageNamespace StorageNamespace, branchID BranchID, readOnly bool) (*RepositoryRecord, error) {
+func (g *Graveler) CreateRepository(ctx context.Context, repositoryID RepositoryID, storageID StorageID, storageNamespace StorageNamespace, branchID BranchID, readOnly bool) (*RepositoryRecord, error) {
 	_, err := g.RefManager.GetRepository(ctx, repositoryID)
 	if err != nil && !errors.Is(err, ErrRepositoryNotFound) {
 		return nil, err
 	}
 
-	repo := NewRepository(storageNamespace, branchID, readOnly)
+	repo := NewRepository(storageID, storageNamespace, branchID, readOnly)
 	repository, err := g.RefManager.CreateRepository(ctx, repositoryID, repo)
 	if err != nil {
 		return nil, err
@@ -1092,13 +1320,13 @@ func (g *Graveler) CreateRepository(ctx context.Context, repositoryID Repository
 	return repository, nil
 }
 
-func (g *Graveler) CreateBareRepository(ctx context.Context, repositoryID RepositoryID, storageNamespace StorageNamespace, defaultBranchID BranchID, readOnly bool) (*RepositoryRecord, error) {
+func (g *Graveler) CreateBareRepository(ctx context.Context, repositoryID RepositoryID, storageID StorageID, storageNamespace StorageNamespace, defaultBranchID BranchID, readOnly bool) (*RepositoryRecord, error) {
 	_, err := g.RefManager.GetRepository(ctx, repositoryID)
 	if err != nil && !errors.Is(err, ErrRepositoryNotFound) {
 		return nil, err
 	}
 
-	repo := NewRepository(storageNamespace, defaultBranchID, readOnly)
+	repo := NewRepository(storageID, storageNamespace, defaultBranchID, readOnly)
 	repository, err := g.RefManager.CreateBareRepository(ctx, repositoryID, repo)
 	if err != nil {
 		return nil, err
@@ -1130,7 +1358,7 @@ func (g *Graveler) WriteRange(ctx context.Context, repository *RepositoryRecord,
 	if repository.ReadOnly && !options.Force {
 		return nil, ErrReadOnlyRepository
 	}
-	return g.CommittedManager.WriteRange(ctx, repository.StorageNamespace, it)
+	return g.CommittedManager.WriteRange(ctx, repository.StorageID, repository.StorageNamespace, it)
 }
 
 func (g *Graveler) WriteMetaRange(ctx context.Context, repository *RepositoryRecord, ranges []*RangeInfo, opts ...SetOptionsFunc) (*MetaRangeInfo, error) {
@@ -1141,7 +1369,7 @@ func (g *Graveler) WriteMetaRange(ctx context.Context, repository *RepositoryRec
 	if repository.ReadOnly && !options.Force {
 		return nil, ErrReadOnlyRepository
 	}
-	return g.CommittedManager.WriteMetaRange(ctx, repository.StorageNamespace, ranges)
+	return g.CommittedManager.WriteMetaRange(ctx, repository.StorageID, repository.StorageNamespace, ranges)
 }
 
 func (g *Graveler) StageObject(ctx context.Context, stagingToken string, object ValueRecord) error {
@@ -1156,7 +1384,7 @@ func (g *Graveler) WriteMetaRangeByIterator(ctx context.Context, repository *Rep
 	if repository.ReadOnly && !options.Force {
 		return nil, ErrReadOnlyRepository
 	}
-	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageNamespace, it, nil)
+	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageID, repository.StorageNamespace, it, nil)
 }
 
 func (g *Graveler) GetCommit(ctx context.Context, repository *RepositoryRecord, commitID CommitID) (*Commit, error) {
@@ -1203,6 +1431,7 @@ func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecor
 		preRunID = g.hooks.NewRunID()
 		err = g.hooks.PreCreateBranchHook(ctx, HookRecord{
 			RunID:            preRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePreCreateBranch,
 			SourceRef:        ref,
@@ -1219,7 +1448,7 @@ func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecor
 		}
 	}
 
-	err = g.RefManager.CreateBranch(ctx, repository, branchID, newBranch)
+	err = g.RefManager.CreateBranch(ctx, repository, branchID, newBranch, ReflogRecord{Operation: "create_branch", User: options.User})
 	if err != nil {
 		return nil, fmt.Errorf("set branch '%s' to '%v': %w", branchID, newBranch, err)
 	}
@@ -1227,6 +1456,7 @@ func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecor
 		postRunID := g.hooks.NewRunID()
 		g.hooks.PostCreateBranchHook(ctx, HookRecord{
 			RunID:            postRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePostCreateBranch,
 			SourceRef:        ref,
@@ -1241,6 +1471,11 @@ func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecor
 }
 
 func (g *Graveler) UpdateBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, opts ...SetOptionsFunc) (*Branch, error) {
+	return g.updateBranch(ctx, repository, branchID, ref, "update_branch", opts...)
+}
+
+// updateBranch points the branch at ref, recording operation in the branch reflog
+func (g *Graveler) updateBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, operation string, opts ...SetOptionsFunc) (*Branch, error) {
 	options := &SetOptions{}
 	for _, opt := range opts {
 		opt(options)
@@ -1256,14 +1491,18 @@ func (g *Graveler) UpdateBranch(ctx context.Context, repository *RepositoryRecor
 		return nil, fmt.Errorf("reference '%s': %w", ref, ErrDereferenceCommitWithStaging)
 	}
 
-	err = g.prepareForCommitIDUpdate(ctx, repository, branchID, "update_branch")
+	record := ReflogRecord{Operation: operation, User: options.User}
+	err = g.prepareForCommitIDUpdate(ctx, repository, branchID, record)
 	if err != nil {
 		return nil, err
 	}
 
 	var tokensToDrop []StagingToken
 	var newBranch *Branch
-	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(currBranch *Branch) (*Branch, error) {
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, record, func(currBranch *Branch) (*Branch, error) {
+		if err := checkExpectedCommitID(options, currBranch); err != nil {
+			return nil, err
+		}
 		// TODO(Guys) return error only on conflicts, currently returns error for any changes on staging
 		empty, err := g.isSealedEmpty(ctx, repository, currBranch)
 		if err != nil {
@@ -1312,7 +1551,7 @@ func (g *Graveler) monitorRetries(ctx context.Context, retries int, repositoryID
 // a new staging token. It is best to use it before changing the branch HEAD
 // as a preparation for deleting the staging area.  See issue #3771 for more
 // information on the algorithm used.
-func (g *Graveler) prepareForCommitIDUpdate(ctx context.Context, repository *RepositoryRecord, branchID BranchID, operation string) error {
+func (g *Graveler) prepareForCommitIDUpdate(ctx context.Context, repository *RepositoryRecord, branchID BranchID, record ReflogRecord) error {
 	return g.retryBranchUpdate(ctx, repository, branchID, func(currBranch *Branch) (*Branch, error) {
 		empty, err := g.isStagingEmpty(ctx, repository, currBranch)
 		if err != nil {
@@ -1325,7 +1564,7 @@ func (g *Graveler) prepareForCommitIDUpdate(ctx context.Context, repository *Rep
 		currBranch.SealedTokens = append([]StagingToken{currBranch.StagingToken}, currBranch.SealedTokens...)
 		currBranch.StagingToken = GenerateStagingToken(repository.RepositoryID, branchID)
 		return currBranch, nil
-	}, operation)
+	}, record)
 }
 
 func (g *Graveler) GetBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*Branch, error) {
@@ -1362,6 +1601,7 @@ func (g *Graveler) CreateTag(ctx context.Context, repository *RepositoryRecord,
 		preRunID = g.hooks.NewRunID()
 		err = g.hooks.PreCreateTagHook(ctx, HookRecord{
 			RunID:            preRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePreCreateTag,
 			RepositoryID:     repository.RepositoryID,
@@ -1386,6 +1626,7 @@ func (g *Graveler) CreateTag(ctx context.Context, repository *RepositoryRecord,
 		postRunID := g.hooks.NewRunID()
 		g.hooks.PostCreateTagHook(ctx, HookRecord{
 			RunID:            postRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePostCreateTag,
 			RepositoryID:     repository.RepositoryID,
@@ -1420,6 +1661,7 @@ func (g *Graveler) DeleteTag(ctx context.Context, repository *RepositoryRecord,
 		preRunID = g.hooks.NewRunID()
 		err = g.hooks.PreDeleteTagHook(ctx, HookRecord{
 			RunID:            preRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePreDeleteTag,
 			RepositoryID:     repository.RepositoryID,
@@ -1445,6 +1687,7 @@ func (g *Graveler) DeleteTag(ctx context.Context, repository *RepositoryRecord,
 		postRunID := g.hooks.NewRunID()
 		g.hooks.PostDeleteTagHook(ctx, HookRecord{
 			RunID:            postRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePostDeleteTag,
 			RepositoryID:     repository.RepositoryID,
@@ -1477,8 +1720,8 @@ func (g *Graveler) ResolveRawRef(ctx context.Context, repository *RepositoryReco
 	return g.RefManager.ResolveRawRef(ctx, repository, rawRef)
 }
 
-func (g *Graveler) Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since *time.Time) (CommitIterator, error) {
-	return g.RefManager.Log(ctx, repository, commitID, firstParent, since)
+func (g *Graveler) Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since, until *time.Time) (CommitIterator, error) {
+	return g.RefManager.Log(ctx, repository, commitID, firstParent, since, until)
 }
 
 func (g *Graveler) ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error) {
@@ -1508,6 +1751,7 @@ func (g *Graveler) DeleteBranch(ctx context.Context, repository *RepositoryRecor
 		preRunID = g.hooks.NewRunID()
 		preHookRecord := HookRecord{
 			RunID:            preRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePreDeleteBranch,
 			RepositoryID:     repository.RepositoryID,
@@ -1525,7 +1769,7 @@ func (g *Graveler) DeleteBranch(ctx context.Context, repository *RepositoryRecor
 	}
 
 	// Delete branch first - afterwards remove tokens
-	err = g.RefManager.DeleteBranch(ctx, repository, branchID)
+	err = g.RefManager.DeleteBranch(ctx, repository, branchID, ReflogRecord{Operation: "delete_branch", User: options.User})
 	if err != nil { // Don't perform post action hook if operation finished with error
 		return err
 	}
@@ -1538,6 +1782,7 @@ func (g *Graveler) DeleteBranch(ctx context.Context, repository *RepositoryRecor
 		postRunID := g.hooks.NewRunID()
 		g.hooks.PostDeleteBranchHook(ctx, HookRecord{
 			RunID:            postRunID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			EventType:        EventTypePostDeleteBranch,
 			RepositoryID:     repository.RepositoryID,
@@ -1559,7 +1804,7 @@ func (g *Graveler) GetStagingToken(ctx context.Context, repository *RepositoryRe
 }
 
 func (g *Graveler) getGarbageCollectionRules(ctx context.Context, repository *RepositoryRecord) (*GarbageCollectionRules, error) {
-	return g.garbageCollectionManager.GetRules(ctx, repository.StorageNamespace)
+	return g.garbageCollectionManager.GetRules(ctx, repository.StorageID, repository.StorageNamespace)
 }
 
 func (g *Graveler) GetGarbageCollectionRules(ctx context.Context, repository *RepositoryRecord) (*GarbageCollectionRules, error) {
@@ -1567,7 +1812,7 @@ func (g *Graveler) GetGarbageCollectionRules(ctx context.Context, repository *Re
 }
 
 func (g *Graveler) SetGarbageCollectionRules(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) error {
-	return g.garbageCollectionManager.SaveRules(ctx, repository.StorageNamespace, rules)
+	return g.garbageCollectionManager.SaveRules(ctx, repository.StorageID, repository.StorageNamespace, rules)
 }
 
 func (g *Graveler) SaveGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord) (*GarbageCollectionRunMetadata, error) {
@@ -1580,11 +1825,11 @@ func (g *Graveler) SaveGarbageCollectionCommits(ctx context.Context, repository
 	if err != nil {
 		return nil, fmt.Errorf("save garbage collection commits: %w", err)
 	}
-	commitsLocation, err := g.garbageCollectionManager.GetCommitsCSVLocation(runID, repository.StorageNamespace)
+	commitsLocation, err := g.garbageCollectionManager.GetCommitsCSVLocation(runID, repository.StorageID, repository.StorageNamespace)
 	if err != nil {
 		return nil, err
 	}
-	addressLocation, err := g.garbageCollectionManager.GetAddressesLocation(repository.StorageNamespace)
+	addressLocation, err := g.garbageCollectionManager.GetAddressesLocation(repository.StorageID, repository.StorageNamespace)
 	if err != nil {
 		return nil, err
 	}
@@ -1597,13 +1842,36 @@ func (g *Graveler) SaveGarbageCollectionCommits(ctx context.Context, repository
 }
 
 func (g *Graveler) GCGetUncommittedLocation(repository *RepositoryRecord, runID string) (string, error) {
-	return g.garbageCollectionManager.GetUncommittedLocation(runID, repository.StorageNamespace)
+	return g.garbageCollectionManager.GetUncommittedLocation(runID, repository.StorageID, repository.StorageNamespace)
 }
 
 func (g *Graveler) GCNewRunID() string {
 	return g.garbageCollectionManager.NewID()
 }
 
+func (g *Graveler) GetExpiredCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) (map[CommitID]MetaRangeID, map[CommitID]MetaRangeID, error) {
+	active, err := g.garbageCollectionManager.GetActiveCommits(ctx, repository, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	it, err := g.RefManager.ListCommits(ctx, repository)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	expired := make(map[CommitID]MetaRangeID)
+	for it.Next() {
+		commit := it.Value()
+		if _, ok := active[commit.CommitID]; !ok {
+			expired[commit.CommitID] = commit.MetaRangeID
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, nil, err
+	}
+	return active, expired, nil
+}
+
 func (g *Graveler) GetBranchProtectionRules(ctx context.Context, repository *RepositoryRecord) (*BranchProtectionRules, *string, error) {
 	return g.protectedBranchesManager.GetRules(ctx, repository)
 }
@@ -1669,7 +1937,7 @@ func (g *Graveler) Get(ctx context.Context, repository *RepositoryRecord, ref Re
 	if err != nil {
 		return nil, err
 	}
-	return g.CommittedManager.Get(ctx, repository.StorageNamespace, commit.MetaRangeID, key)
+	return g.CommittedManager.Get(ctx, repository.StorageID, repository.StorageNamespace, commit.MetaRangeID, key)
 }
 
 func (g *Graveler) GetByCommitID(ctx context.Context, repository *RepositoryRecord, commitID CommitID, key Key) (*Value, error) {
@@ -1678,7 +1946,7 @@ func (g *Graveler) GetByCommitID(ctx context.Context, repository *RepositoryReco
 	if err != nil {
 		return nil, err
 	}
-	return g.CommittedManager.Get(ctx, repository.StorageNamespace, commit.MetaRangeID, key)
+	return g.CommittedManager.Get(ctx, repository.StorageID, repository.StorageNamespace, commit.MetaRangeID, key)
 }
 
 func (g *Graveler) GetRangeIDByKey(ctx context.Context, repository *RepositoryRecord, commitID CommitID, key Key) (RangeID, error) {
@@ -1686,7 +1954,7 @@ func (g *Graveler) GetRangeIDByKey(ctx context.Context, repository *RepositoryRe
 	if err != nil {
 		return "", err
 	}
-	return g.CommittedManager.GetRangeIDByKey(ctx, repository.StorageNamespace, commit.MetaRangeID, key)
+	return g.CommittedManager.GetRangeIDByKey(ctx, repository.StorageID, repository.StorageNamespace, commit.MetaRangeID, key)
 }
 
 func (g *Graveler) Set(ctx context.Context, repository *RepositoryRecord, branchID BranchID, key Key, value Value, opts ...SetOptionsFunc) error {
@@ -1708,26 +1976,47 @@ func (g *Graveler) Set(ctx context.Context, repository *RepositoryRecord, branch
 
 	log := g.log(ctx).WithFields(logging.Fields{"key": key, "operation": "set"})
 	err = g.safeBranchWrite(ctx, log, repository, branchID, safeBranchWriteOptions{MaxTries: options.MaxTries}, func(branch *Branch) error {
-		if !options.IfAbsent {
+		if !options.IfAbsent && options.Condition == nil {
 			return g.StagingManager.Set(ctx, branch.StagingToken, key, &value, false)
 		}
 
-		// verify the key not found
-		_, err := g.Get(ctx, repository, Ref(branchID), key)
-		if err == nil { // Entry found, return precondition failed
-			return ErrPreconditionFailed
-		}
-		if !errors.Is(err, ErrNotFound) {
+		// verify the preconditions on the current value of the key, staged or committed
+		currentValue, err := g.Get(ctx, repository, Ref(branchID), key)
+		switch {
+		case err == nil:
+			if options.IfAbsent {
+				return ErrPreconditionFailed
+			}
+		case errors.Is(err, ErrNotFound):
+			currentValue = nil
+		default:
 			return err
 		}
+		if options.Condition != nil {
+			if err := options.Condition(currentValue); err != nil {
+				return err
+			}
+		}
 
-		// update stage with new value only if key not found or tombstone
-		return g.StagingManager.Update(ctx, branch.StagingToken, key, func(currentValue *Value) (*Value, error) {
-			if currentValue == nil || currentValue.Identity == nil {
+		// update stage with new value, verifying the preconditions again in case a value was staged since. The
+		// update fails if the staged value changes concurrently.
+		err = g.StagingManager.Update(ctx, branch.StagingToken, key, func(stagedValue *Value) (*Value, error) {
+			if stagedValue == nil {
 				return &value, nil
 			}
-			return nil, ErrSkipValueUpdate
+			if stagedValue.Identity == nil {
+				// tombstone
+				stagedValue = nil
+			}
+			if err := options.checkPreconditions(stagedValue); err != nil {
+				return nil, err
+			}
+			return &value, nil
 		})
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			return ErrPreconditionFailed
+		}
+		return err
 	}, "set")
 	return err
 }
@@ -1863,7 +2152,7 @@ func (g *Graveler) deleteUnsafe(ctx context.Context, repository *RepositoryRecor
 		metaRangeID = commit.MetaRangeID
 	}
 
-	_, err = g.CommittedManager.Get(ctx, repository.StorageNamespace, metaRangeID, key)
+	_, err = g.CommittedManager.Get(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID, key)
 	if err == nil {
 		// found in committed, set tombstone
 		return g.deleteAndNotify(ctx, repository.RepositoryID, branchRecord, key, false)
@@ -1943,7 +2232,7 @@ func (g *Graveler) List(ctx context.Context, repository *RepositoryRecord, ref R
 		metaRangeID = commit.MetaRangeID
 	}
 
-	listing, err := g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
+	listing, err := g.CommittedManager.List(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID)
 	if err != nil {
 		return nil, err
 	}
@@ -1965,6 +2254,7 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 	var newCommitID CommitID
 	var storageNamespace StorageNamespace
 	var sealedToDrop []StagingToken
+	var sealedToFilter []StagingToken
 
 	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_COMMIT)
 	if err != nil {
@@ -1981,9 +2271,21 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 	if repository.ReadOnly && !options.Force {
 		return "", ErrReadOnlyRepository
 	}
+	// a client signs the commit content in advance, it is known only when the commit date and metarange are given
+	if params.Signature != nil && (params.SourceMetaRange == nil || params.Date == nil) {
+		return "", fmt.Errorf("commit signature requires a date and a source metarange: %w", ErrInvalidValue)
+	}
 	storageNamespace = repository.StorageNamespace
 
-	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+	if params.SourceMetaRange != nil && len(params.Prefixes) > 0 {
+		return "", fmt.Errorf("source metarange with prefixes: %w", ErrInvalidValue)
+	}
+
+	record := ReflogRecord{Operation: "commit", User: params.Committer}
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, record, func(branch *Branch) (*Branch, error) {
+		if err := checkExpectedCommitID(options, branch); err != nil {
+			return nil, err
+		}
 		if params.SourceMetaRange != nil {
 			empty, err := g.isStagingEmpty(ctx, repository, branch)
 			if err != nil {
@@ -2002,6 +2304,9 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 	}
 
 	err = g.retryBranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+		if err := checkExpectedCommitID(options, branch); err != nil {
+			return nil, err
+		}
 		// fill commit information - use for pre-commit and after adding the commit information used by commit
 		commit = NewCommit()
 
@@ -2022,6 +2327,7 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 				EventType:        EventTypePreCommit,
 				SourceRef:        branchID.Ref(),
 				RepositoryID:     repository.RepositoryID,
+				StorageID:        repository.StorageID,
 				StorageNamespace: storageNamespace,
 				BranchID:         branchID,
 				Commit:           commit,
@@ -2060,14 +2366,26 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 			if err != nil {
 				return nil, err
 			}
+			if len(params.Prefixes) > 0 {
+				changes = NewPrefixFilterIterator(changes, params.Prefixes)
+			}
 			defer changes.Close()
 			// returns err if the commit is empty (no changes)
-			commit.MetaRangeID, _, err = g.CommittedManager.Commit(ctx, storageNamespace, branchMetaRangeID, changes, params.AllowEmpty)
+			commit.MetaRangeID, _, err = g.CommittedManager.Commit(ctx, repository.StorageID, storageNamespace, branchMetaRangeID, changes, params.AllowEmpty)
 			if err != nil {
 				return nil, fmt.Errorf("commit: %w", err)
 			}
 		}
-		sealedToDrop = branch.SealedTokens
+
+		if params.Signature != nil {
+			commit.Signature = params.Signature
+			if !g.VerifyCommitSignature(commit) {
+				return nil, ErrInvalidSignature
+			}
+		}
+		if err := g.signCommit(ctx, repository, branchID, &commit); err != nil {
+			return nil, err
+		}
 
 		// add commit
 		newCommitID, err = g.RefManager.AddCommit(ctx, repository, commit)
@@ -2076,14 +2394,31 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 		}
 
 		branch.CommitID = newCommitID
+		if len(params.Prefixes) > 0 {
+			// changes outside the prefixes remain staged on the sealed tokens
+			sealedToFilter = branch.SealedTokens
+			return branch, nil
+		}
+		sealedToDrop = branch.SealedTokens
 		branch.SealedTokens = make([]StagingToken, 0)
 		return branch, nil
-	}, "commit")
+	}, record)
 	if err != nil {
 		return "", err
 	}
 
 	g.dropTokens(ctx, sealedToDrop...)
+	if err := g.dropTokensPrefixes(ctx, sealedToFilter, params.Prefixes); err != nil {
+		// the commit landed, the committed changes are still staged and show as uncommitted until they are reset
+		g.log(ctx).WithError(err).WithFields(logging.Fields{
+			"branch":    branchID,
+			"commit_id": newCommitID,
+		}).Error("Failed to drop committed changes from staging")
+	} else if len(sealedToFilter) > 0 {
+		if err := g.compactSealedTokens(ctx, repository, branchID, sealedToFilter); err != nil {
+			g.log(ctx).WithError(err).WithField("branch", branchID).Warn("Failed to compact sealed staging tokens")
+		}
+	}
 
 	if !repository.ReadOnly {
 		postRunID := g.hooks.NewRunID()
@@ -2091,6 +2426,7 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 			EventType:        EventTypePostCommit,
 			RunID:            postRunID,
 			RepositoryID:     repository.RepositoryID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			SourceRef:        newCommitID.Ref(),
 			BranchID:         branchID,
@@ -2125,15 +2461,15 @@ func (g *Graveler) CreateCommitRecord(ctx context.Context, repository *Repositor
 // to BranchUpdateMaxTries times, and never sleeps than for more than
 // BranchUpdateMaxInterval.  It returns the number of times it tried --
 // between 1 and BranchUpdateMaxTries.
-func (g *Graveler) retryBranchUpdate(ctx context.Context, repository *RepositoryRecord, branchID BranchID, f BranchUpdateFunc, operation string) error {
+func (g *Graveler) retryBranchUpdate(ctx context.Context, repository *RepositoryRecord, branchID BranchID, f BranchUpdateFunc, record ReflogRecord) error {
 	tries := 0
 	defer func() {
-		g.monitorRetries(ctx, tries-1, repository.RepositoryID, branchID, operation)
+		g.monitorRetries(ctx, tries-1, repository.RepositoryID, branchID, record.Operation)
 	}()
 	err := backoff.Retry(func() error {
 		// TODO(eden) issue 3586 - if the branch commit id hasn't changed, update the fields instead of fail
 		tries += 1
-		err := g.RefManager.BranchUpdate(ctx, repository, branchID, f)
+		err := g.RefManager.BranchUpdate(ctx, repository, branchID, record, f)
 		if errors.Is(err, kv.ErrPredicateFailed) && tries < BranchUpdateMaxTries {
 			g.log(ctx).WithField("try", tries).
 				WithField("branchID", branchID).
@@ -2205,6 +2541,9 @@ func (g *Graveler) AddCommit(ctx context.Context, repository *RepositoryRecord,
 		return commitID, nil
 	}
 
+	if err := g.signCommit(ctx, repository, "", &commit); err != nil {
+		return "", err
+	}
 	commitID, err = g.addCommitNoLock(ctx, repository, commit)
 	if err != nil {
 		return "", fmt.Errorf("adding commit: %w", err)
@@ -2216,7 +2555,7 @@ func (g *Graveler) AddCommit(ctx context.Context, repository *RepositoryRecord,
 // addCommitNoLock lower API used to add commit into a repository. It will verify that the commit meta-range is accessible but will not lock any metadata update.
 func (g *Graveler) addCommitNoLock(ctx context.Context, repository *RepositoryRecord, commit Commit) (CommitID, error) {
 	// verify access to meta range
-	ok, err := g.CommittedManager.Exists(ctx, repository.StorageNamespace, commit.MetaRangeID)
+	ok, err := g.CommittedManager.Exists(ctx, repository.StorageID, repository.StorageNamespace, commit.MetaRangeID)
 	if err != nil {
 		return "", fmt.Errorf("checking for meta range %s: %w", commit.MetaRangeID, err)
 	}
@@ -2250,7 +2589,7 @@ func (g *Graveler) checkEmpty(ctx context.Context, repository *RepositoryRecord,
 	if err != nil {
 		return false, err
 	}
-	committedList, err := g.CommittedManager.List(ctx, repository.StorageNamespace, commit.MetaRangeID)
+	committedList, err := g.CommittedManager.List(ctx, repository.StorageID, repository.StorageNamespace, commit.MetaRangeID)
 	if err != nil {
 		return false, err
 	}
@@ -2283,6 +2622,60 @@ func (g *Graveler) dropTokens(ctx context.Context, tokens ...StagingToken) {
 	}
 }
 
+// dropTokensPrefixes deletes the entries under the given prefixes from the staging area of the tokens
+func (g *Graveler) dropTokensPrefixes(ctx context.Context, tokens []StagingToken, prefixes []Prefix) error {
+	for _, token := range tokens {
+		for _, prefix := range prefixes {
+			if err := g.StagingManager.DropByPrefix(ctx, token, Key(prefix)); err != nil {
+				return fmt.Errorf("staging token %s prefix '%s': %w", token, prefix, err)
+			}
+		}
+	}
+	return nil
+}
+
+// compactSealedTokens removes the tokens left without entries from the sealed tokens of the branch, and drops them
+func (g *Graveler) compactSealedTokens(ctx context.Context, repository *RepositoryRecord, branchID BranchID, tokens []StagingToken) error {
+	drained := make(map[StagingToken]struct{})
+	for _, token := range tokens {
+		it := g.StagingManager.List(ctx, token, 1)
+		hasEntries := it.Next()
+		err := it.Err()
+		it.Close()
+		if err != nil {
+			return err
+		}
+		if !hasEntries {
+			drained[token] = struct{}{}
+		}
+	}
+	if len(drained) == 0 {
+		return nil
+	}
+	var dropped []StagingToken
+	err := g.retryBranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+		dropped = nil
+		sealed := make([]StagingToken, 0, len(branch.SealedTokens))
+		for _, token := range branch.SealedTokens {
+			if _, ok := drained[token]; ok {
+				dropped = append(dropped, token)
+				continue
+			}
+			sealed = append(sealed, token)
+		}
+		if len(dropped) == 0 {
+			return nil, nil
+		}
+		branch.SealedTokens = sealed
+		return branch, nil
+	}, ReflogRecord{Operation: "compact sealed tokens"})
+	if err != nil {
+		return err
+	}
+	g.dropTokens(ctx, dropped...)
+	return nil
+}
+
 func (g *Graveler) ResetHard(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, opts ...SetOptionsFunc) error {
 	isProtectedCommit, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_COMMIT)
 	if err != nil {
@@ -2310,6 +2703,9 @@ func (g *Graveler) ResetHard(ctx context.Context, repository *RepositoryRecord,
 
 	// TODO(ariels): up to here.  Verify staging is empty!
 	err = g.retryBranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+		if err := checkExpectedCommitID(options, branch); err != nil {
+			return nil, err
+		}
 		if empty, err := g.isSealedEmpty(ctx, repository, branch); err != nil {
 			return nil, fmt.Errorf("%s: check if dirty: %w", branchID, err)
 		} else if !empty {
@@ -2343,7 +2739,7 @@ func (g *Graveler) ResetHard(ctx context.Context, repository *RepositoryRecord,
 		}
 		branch.CommitID = commitRecord.CommitID
 		return branch, nil
-	}, "reset_hard")
+	}, ReflogRecord{Operation: "reset_hard", User: options.User})
 	return err
 }
 
@@ -2365,7 +2761,10 @@ func (g *Graveler) Reset(ctx context.Context, repository *RepositoryRecord, bran
 	}
 
 	tokensToDrop := make([]StagingToken, 0)
-	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, ReflogRecord{Operation: "reset", User: options.User}, func(branch *Branch) (*Branch, error) {
+		if err := checkExpectedCommitID(options, branch); err != nil {
+			return nil, err
+		}
 		// Save current branch tokens for drop
 		tokensToDrop = append(tokensToDrop, branch.StagingToken)
 		tokensToDrop = append(tokensToDrop, branch.SealedTokens...)
@@ -2487,7 +2886,10 @@ func (g *Graveler) ResetPrefix(ctx context.Context, repository *RepositoryRecord
 	newSealedTokens := make([]StagingToken, 0)
 	newStagingToken := GenerateStagingToken(repository.RepositoryID, branchID)
 
-	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, ReflogRecord{Operation: "reset_prefix", User: options.User}, func(branch *Branch) (*Branch, error) {
+		if err := checkExpectedCommitID(options, branch); err != nil {
+			return nil, err
+		}
 		newSealedTokens = []StagingToken{branch.StagingToken}
 		newSealedTokens = append(newSealedTokens, branch.SealedTokens...)
 
@@ -2563,14 +2965,15 @@ func (g *Graveler) Revert(ctx context.Context, repository *RepositoryRecord, bra
 		parentNumber--
 	}
 
-	err = g.prepareForCommitIDUpdate(ctx, repository, branchID, "revert")
+	record := ReflogRecord{Operation: "revert", User: commitParams.Committer}
+	err = g.prepareForCommitIDUpdate(ctx, repository, branchID, record)
 	if err != nil {
 		return "", err
 	}
 
 	var commitID CommitID
 	var tokensToDrop []StagingToken
-	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, record, func(branch *Branch) (*Branch, error) {
 		if empty, err := g.isSealedEmpty(ctx, repository, branch); err != nil {
 			return nil, err
 		} else if !empty {
@@ -2589,7 +2992,7 @@ func (g *Graveler) Revert(ctx context.Context, repository *RepositoryRecord, bra
 			return nil, fmt.Errorf("get commit from ref %s: %w", branch.CommitID, err)
 		}
 		// merge from the parent to the top of the branch, with the given ref as the merge base:
-		metaRangeID, err := g.CommittedManager.Merge(ctx, repository.StorageNamespace, branchCommit.MetaRangeID, parentMetaRangeID, commitRecord.MetaRangeID, MergeStrategyNone)
+		metaRangeID, err := g.CommittedManager.Merge(ctx, repository.StorageID, repository.StorageNamespace, branchCommit.MetaRangeID, parentMetaRangeID, commitRecord.MetaRangeID, MergeStrategyNone, nil)
 		if err != nil {
 			if !errors.Is(err, ErrUserVisible) {
 				err = fmt.Errorf("merge: %w", err)
@@ -2606,6 +3009,9 @@ func (g *Graveler) Revert(ctx context.Context, repository *RepositoryRecord, bra
 		commit.Parents = []CommitID{branch.CommitID}
 		commit.Metadata = commitParams.Metadata
 		commit.Generation = branchCommit.Generation + 1
+		if err := g.signCommit(ctx, repository, branchID, &commit); err != nil {
+			return nil, err
+		}
 		commitID, err = g.RefManager.AddCommit(ctx, repository, commit)
 		if err != nil {
 			return nil, fmt.Errorf("add commit: %w", err)
@@ -2654,7 +3060,8 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 	}
 	pn--
 
-	err = g.prepareForCommitIDUpdate(ctx, repository, branchID, "cherrypick")
+	record := ReflogRecord{Operation: "cherrypick", User: committer}
+	err = g.prepareForCommitIDUpdate(ctx, repository, branchID, record)
 	if err != nil {
 		return "", err
 	}
@@ -2670,7 +3077,7 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 
 	var commitID CommitID
 	var tokensToDrop []StagingToken
-	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, record, func(branch *Branch) (*Branch, error) {
 		if empty, err := g.isSealedEmpty(ctx, repository, branch); err != nil {
 			return nil, err
 		} else if !empty {
@@ -2681,31 +3088,164 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 		if err != nil {
 			return nil, fmt.Errorf("get commit from ref %s: %w", branch.CommitID, err)
 		}
-		// merge from the parent to the top of the branch, with the given ref as the merge base:
-		metaRangeID, err := g.CommittedManager.Merge(ctx, repository.StorageNamespace, branchCommit.MetaRangeID, commitRecord.MetaRangeID, parentMetaRangeID, MergeStrategyNone)
+		pickedCommit, err := g.cherryPickCommit(ctx, repository, branchID, branchCommit, commitRecord, parentMetaRangeID, committer)
 		if err != nil {
-			if !errors.Is(err, ErrUserVisible) {
-				err = fmt.Errorf("merge: %w", err)
-			}
 			return nil, err
 		}
-		commit := NewCommit()
-		commit.Committer = committer
-		commit.Message = commitRecord.Message
-		commit.MetaRangeID = metaRangeID
-		commit.Parents = []CommitID{branch.CommitID}
-		commit.Generation = branchCommit.Generation + 1
+		commitID = pickedCommit.CommitID
 
-		commit.Metadata = commitRecord.Metadata
-		if commit.Metadata == nil {
-			commit.Metadata = make(map[string]string)
+		tokensToDrop = branch.SealedTokens
+		branch.SealedTokens = []StagingToken{}
+		branch.CommitID = commitID
+		return branch, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("update branch: %w", err)
+	}
+
+	g.dropTokens(ctx, tokensToDrop...)
+	return commitID, nil
+}
+
+// cherryPickCommit applies the changes of commitRecord relative to the parent metarange on top of the 'onto' commit,
+// and returns the new commit.
+func (g *Graveler) cherryPickCommit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, onto *CommitRecord, commitRecord *CommitRecord, parentMetaRangeID MetaRangeID, committer string) (*CommitRecord, error) {
+	// merge from the parent to the top of the branch, with the given ref as the merge base:
+	metaRangeID, err := g.CommittedManager.Merge(ctx, repository.StorageID, repository.StorageNamespace, onto.MetaRangeID, commitRecord.MetaRangeID, parentMetaRangeID, MergeStrategyNone, nil)
+	if err != nil {
+		if !errors.Is(err, ErrUserVisible) {
+			err = fmt.Errorf("merge: %w", err)
 		}
-		commit.Metadata["cherry-pick-origin"] = string(commitRecord.CommitID)
-		commit.Metadata["cherry-pick-committer"] = commitRecord.Committer
+		return nil, err
+	}
+	commit := NewCommit()
+	commit.Committer = committer
+	commit.Message = commitRecord.Message
+	commit.MetaRangeID = metaRangeID
+	commit.Parents = []CommitID{onto.CommitID}
+	commit.Generation = onto.Generation + 1
 
-		commitID, err = g.RefManager.AddCommit(ctx, repository, commit)
+	commit.Metadata = make(map[string]string, len(commitRecord.Metadata)+2)
+	for k, v := range commitRecord.Metadata {
+		commit.Metadata[k] = v
+	}
+	commit.Metadata["cherry-pick-origin"] = string(commitRecord.CommitID)
+	commit.Metadata["cherry-pick-committer"] = commitRecord.Committer
+
+	if err := g.signCommit(ctx, repository, branchID, &commit); err != nil {
+		return nil, err
+	}
+	commitID, err := g.RefManager.AddCommit(ctx, repository, commit)
+	if err != nil {
+		return nil, fmt.Errorf("add commit: %w", err)
+	}
+	return &CommitRecord{CommitID: commitID, Commit: &commit}, nil
+}
+
+// Rebase replays the commits of the branch since its merge base with onto on top of onto. Rebasing commits to the
+// branch, so it is blocked on branches protected from commits, and runs the merge hooks of bringing onto into it.
+func (g *Graveler) Rebase(ctx context.Context, repository *RepositoryRecord, branchID BranchID, onto Ref, committer string, opts ...SetOptionsFunc) (CommitID, error) {
+	options := &SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if repository.ReadOnly && !options.Force {
+		return "", ErrReadOnlyRepository
+	}
+
+	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_COMMIT)
+	if err != nil {
+		return "", err
+	}
+	if isProtected {
+		return "", ErrCommitToProtectedBranch
+	}
+
+	ontoCommit, err := g.dereferenceCommit(ctx, repository, onto)
+	if err != nil {
+		return "", fmt.Errorf("get commit from ref %s: %w", onto, err)
+	}
+
+	record := ReflogRecord{Operation: "rebase", User: committer}
+	err = g.prepareForCommitIDUpdate(ctx, repository, branchID, record)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		preRunID     string
+		commitID     CommitID
+		headCommit   Commit
+		tokensToDrop []StagingToken
+	)
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, record, func(branch *Branch) (*Branch, error) {
+		if empty, err := g.isSealedEmpty(ctx, repository, branch); err != nil {
+			return nil, err
+		} else if !empty {
+			return nil, fmt.Errorf("%s: %w", branchID, ErrDirtyBranch)
+		}
+
+		baseCommit, err := g.RefManager.FindMergeBase(ctx, repository, branch.CommitID, ontoCommit.CommitID)
 		if err != nil {
-			return nil, fmt.Errorf("add commit: %w", err)
+			return nil, err
+		}
+		if baseCommit == nil {
+			return nil, ErrNoMergeBase
+		}
+		baseCommitID := CommitID(ident.NewHexAddressProvider().ContentAddress(baseCommit))
+		if baseCommitID == ontoCommit.CommitID {
+			// branch already contains 'onto', nothing to replay
+			commitID = branch.CommitID
+			return nil, nil
+		}
+
+		commits, err := g.firstParentCommitsSince(ctx, repository, branch.CommitID, baseCommitID)
+		if err != nil {
+			return nil, err
+		}
+		head := ontoCommit
+		// replay commits from the oldest
+		for i := len(commits) - 1; i >= 0; i-- {
+			var parentMetaRangeID MetaRangeID
+			if len(commits[i].Parents) > 0 {
+				parentCommit, err := g.dereferenceCommit(ctx, repository, commits[i].Parents[0].Ref())
+				if err != nil {
+					return nil, fmt.Errorf("get commit from ref %s: %w", commits[i].Parents[0], err)
+				}
+				parentMetaRangeID = parentCommit.MetaRangeID
+			}
+			pickedCommit, err := g.cherryPickCommit(ctx, repository, branchID, head, commits[i], parentMetaRangeID, committer)
+			if errors.Is(err, ErrNoChanges) {
+				// changes already exist on the new base
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("replay commit %s: %w", commits[i].CommitID, err)
+			}
+			head = pickedCommit
+		}
+		commitID = head.CommitID
+		headCommit = *head.Commit
+
+		if !repository.ReadOnly {
+			preRunID = g.hooks.NewRunID()
+			err = g.hooks.PreMergeHook(ctx, HookRecord{
+				EventType:        EventTypePreMerge,
+				RunID:            preRunID,
+				RepositoryID:     repository.RepositoryID,
+				StorageID:        repository.StorageID,
+				StorageNamespace: repository.StorageNamespace,
+				BranchID:         branchID,
+				SourceRef:        ontoCommit.CommitID.Ref(),
+				Commit:           headCommit,
+			})
+			if err != nil {
+				return nil, &HookAbortError{
+					EventType: EventTypePreMerge,
+					RunID:     preRunID,
+					Err:       err,
+				}
+			}
 		}
 
 		tokensToDrop = branch.SealedTokens
@@ -2718,10 +3258,55 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 	}
 
 	g.dropTokens(ctx, tokensToDrop...)
+	if preRunID != "" {
+		// the branch moved, it is not run on branches already containing onto
+		postRunID := g.hooks.NewRunID()
+		err = g.hooks.PostMergeHook(ctx, HookRecord{
+			EventType:        EventTypePostMerge,
+			RunID:            postRunID,
+			RepositoryID:     repository.RepositoryID,
+			StorageID:        repository.StorageID,
+			StorageNamespace: repository.StorageNamespace,
+			BranchID:         branchID,
+			SourceRef:        commitID.Ref(),
+			Commit:           headCommit,
+			CommitID:         commitID,
+			PreRunID:         preRunID,
+		})
+		if err != nil {
+			g.log(ctx).
+				WithError(err).
+				WithField("run_id", postRunID).
+				WithField("pre_run_id", preRunID).
+				Error("Post-merge hook of rebase failed")
+		}
+	}
 	return commitID, nil
 }
 
-func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, destination BranchID, source Ref, commitParams CommitParams, strategy string, opts ...SetOptionsFunc) (CommitID, error) {
+// firstParentCommitsSince returns the first-parent history of 'from' down to 'since' (excluded), newest first.
+// Fails with ErrRebaseMergeBase in case 'since' is not on the first-parent history.
+func (g *Graveler) firstParentCommitsSince(ctx context.Context, repository *RepositoryRecord, from, since CommitID) ([]*CommitRecord, error) {
+	it, err := g.RefManager.Log(ctx, repository, from, true, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var commits []*CommitRecord
+	for it.Next() {
+		commit := it.Value()
+		if commit.CommitID == since {
+			return commits, nil
+		}
+		commits = append(commits, commit)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrRebaseMergeBase
+}
+
+func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, destination BranchID, source Ref, commitParams CommitParams, strategy string, mergeOptions MergeOptions, opts ...SetOptionsFunc) (CommitID, error) {
 	options := &SetOptions{}
 	for _, opt := range opts {
 		opt(options)
@@ -2737,7 +3322,8 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 	)
 
 	storageNamespace := repository.StorageNamespace
-	err := g.prepareForCommitIDUpdate(ctx, repository, destination, "merge")
+	record := ReflogRecord{Operation: "merge", User: commitParams.Committer}
+	err := g.prepareForCommitIDUpdate(ctx, repository, destination, record)
 	if err != nil {
 		return "", err
 	}
@@ -2760,6 +3346,9 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 	// or some other branch changing operation. If commit is in-progress, then staging area wasn't empty after we checked so not retrying is ok.
 	// If another commit/merge succeeded, then the user should decide whether to retry the merge.
 	err = g.retryBranchUpdate(ctx, repository, destination, func(branch *Branch) (*Branch, error) {
+		if err := checkExpectedCommitID(options, branch); err != nil {
+			return nil, err
+		}
 		empty, err := g.isSealedEmpty(ctx, repository, branch)
 		if err != nil {
 			return nil, fmt.Errorf("check if staging empty: %w", err)
@@ -2777,19 +3366,12 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 			"base_meta_range":        baseCommit.MetaRangeID,
 		}).Trace("Merge")
 
-		var mergeStrategy MergeStrategy
-		switch strategy {
-		case MergeStrategyDestWinsStr:
-			mergeStrategy = MergeStrategyDest
-		case MergeStrategySrcWinsStr:
-			mergeStrategy = MergeStrategySrc
-		case "":
-			mergeStrategy = MergeStrategyNone
-		default:
-			return nil, ErrInvalidMergeStrategy
+		mergeStrategy, err := parseMergeStrategy(strategy, mergeOptions.Resolver)
+		if err != nil {
+			return nil, err
 		}
 
-		metaRangeID, err := g.CommittedManager.Merge(ctx, storageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID, mergeStrategy)
+		metaRangeID, err := g.CommittedManager.Merge(ctx, repository.StorageID, storageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID, mergeStrategy, mergeOptions.Resolver)
 		if err != nil {
 			if !errors.Is(err, ErrUserVisible) {
 				err = fmt.Errorf("merge in CommitManager: %w", err)
@@ -2800,13 +3382,22 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 		commit.Committer = commitParams.Committer
 		commit.Message = commitParams.Message
 		commit.MetaRangeID = metaRangeID
-		commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
-		if toCommit.Generation > fromCommit.Generation {
+		switch {
+		case mergeOptions.Squash:
+			commit.Parents = []CommitID{toCommit.CommitID}
 			commit.Generation = toCommit.Generation + 1
-		} else {
+		case toCommit.Generation > fromCommit.Generation:
+			commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
+			commit.Generation = toCommit.Generation + 1
+		default:
+			commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
 			commit.Generation = fromCommit.Generation + 1
 		}
-		metadata[MergeStrategyMetadataKey] = mergeStrategyString[mergeStrategy]
+		if mergeOptions.Resolver != nil && strategy != "" {
+			metadata[MergeStrategyMetadataKey] = strategy
+		} else {
+			metadata[MergeStrategyMetadataKey] = mergeStrategyString[mergeStrategy]
+		}
 		commit.Metadata = metadata
 		if !repository.ReadOnly {
 			preRunID = g.hooks.NewRunID()
@@ -2814,6 +3405,7 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 				EventType:        EventTypePreMerge,
 				RunID:            preRunID,
 				RepositoryID:     repository.RepositoryID,
+				StorageID:        repository.StorageID,
 				StorageNamespace: storageNamespace,
 				BranchID:         destination,
 				SourceRef:        fromCommit.CommitID.Ref(),
@@ -2827,6 +3419,9 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 				}
 			}
 		}
+		if err := g.signCommit(ctx, repository, destination, &commit); err != nil {
+			return nil, err
+		}
 		commitID, err = g.RefManager.AddCommit(ctx, repository, commit)
 		if err != nil {
 			return nil, fmt.Errorf("add commit: %w", err)
@@ -2836,7 +3431,7 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 		branch.SealedTokens = []StagingToken{}
 		branch.CommitID = commitID
 		return branch, nil
-	}, "merge")
+	}, record)
 	if err != nil {
 		return "", fmt.Errorf("update branch %s: %w", destination, err)
 	}
@@ -2848,6 +3443,7 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 			EventType:        EventTypePostMerge,
 			RunID:            postRunID,
 			RepositoryID:     repository.RepositoryID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			BranchID:         destination,
 
@@ -2867,6 +3463,24 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 	return commitID, nil
 }
 
+// parseMergeStrategy returns the merge strategy matching the strategy name. Conflicts are passed to the resolver when
+// one is given, the strategy name then only names the resolver.
+func parseMergeStrategy(strategy string, resolver MergeResolver) (MergeStrategy, error) {
+	if resolver != nil {
+		return MergeStrategyNone, nil
+	}
+	switch strategy {
+	case MergeStrategyDestWinsStr:
+		return MergeStrategyDest, nil
+	case MergeStrategySrcWinsStr:
+		return MergeStrategySrc, nil
+	case "":
+		return MergeStrategyNone, nil
+	default:
+		return MergeStrategyNone, ErrInvalidMergeStrategy
+	}
+}
+
 func (g *Graveler) retryRepoMetadataUpdate(ctx context.Context, repository *RepositoryRecord, f RepoMetadataUpdateFunc) error {
 	bo := backoff.NewExponentialBackOff()
 	bo.MaxInterval = RepoMetadataUpdateMaxInterval
@@ -2910,7 +3524,8 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 	)
 
 	storageNamespace := repository.StorageNamespace
-	err := g.prepareForCommitIDUpdate(ctx, repository, destination, "import")
+	record := ReflogRecord{Operation: "import", User: commitParams.Committer}
+	err := g.prepareForCommitIDUpdate(ctx, repository, destination, record)
 	if err != nil {
 		return "", err
 	}
@@ -2939,7 +3554,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 			"destination_meta_range": toCommit.MetaRangeID,
 		}).Trace("Import")
 
-		metaRangeID, err := g.CommittedManager.Import(ctx, storageNamespace, toCommit.MetaRangeID, source, prefixes)
+		metaRangeID, err := g.CommittedManager.Import(ctx, repository.StorageID, storageNamespace, toCommit.MetaRangeID, source, prefixes)
 		if err != nil {
 			if !errors.Is(err, ErrUserVisible) {
 				err = fmt.Errorf("merge in CommitManager: %w", err)
@@ -2964,6 +3579,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 				EventType:        EventTypePreCommit,
 				SourceRef:        destination.Ref(),
 				RepositoryID:     repository.RepositoryID,
+				StorageID:        repository.StorageID,
 				StorageNamespace: storageNamespace,
 				BranchID:         destination,
 				Commit:           commit,
@@ -2977,6 +3593,9 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 			}
 		}
 
+		if err := g.signCommit(ctx, repository, destination, &commit); err != nil {
+			return nil, err
+		}
 		commitID, err = g.RefManager.AddCommit(ctx, repository, commit)
 		if err != nil {
 			return nil, fmt.Errorf("add commit: %w", err)
@@ -2986,7 +3605,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 		branch.SealedTokens = []StagingToken{}
 		branch.CommitID = commitID
 		return branch, nil
-	}, "import")
+	}, record)
 	if err != nil {
 		return "", fmt.Errorf("update branch %s: %w", destination, err)
 	}
@@ -2998,6 +3617,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 			EventType:        EventTypePostCommit,
 			RunID:            postRunID,
 			RepositoryID:     repository.RepositoryID,
+			StorageID:        repository.StorageID,
 			StorageNamespace: storageNamespace,
 			SourceRef:        commitID.Ref(),
 			BranchID:         destination,
@@ -3044,7 +3664,7 @@ func (g *Graveler) DiffUncommitted(ctx context.Context, repository *RepositoryRe
 	}
 	var committedValueIterator ValueIterator
 	if metaRangeID != "" {
-		committedValueIterator, err = g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
+		committedValueIterator, err = g.CommittedManager.List(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID)
 		if err != nil {
 			valueIterator.Close()
 			return nil, err
@@ -3087,14 +3707,14 @@ func (g *Graveler) Diff(ctx context.Context, repository *RepositoryRecord, left,
 	if err != nil {
 		return nil, err
 	}
-	diff, err := g.CommittedManager.Diff(ctx, repository.StorageNamespace, leftCommit.MetaRangeID, rightCommit.MetaRangeID)
+	diff, err := g.CommittedManager.Diff(ctx, repository.StorageID, repository.StorageNamespace, leftCommit.MetaRangeID, rightCommit.MetaRangeID)
 	if err != nil {
 		return nil, err
 	}
 	if rightRawRef.ResolvedBranchModifier != ResolvedBranchModifierStaging {
 		return diff, nil
 	}
-	leftValueIterator, err := g.CommittedManager.List(ctx, repository.StorageNamespace, leftCommit.MetaRangeID)
+	leftValueIterator, err := g.CommittedManager.List(ctx, repository.StorageID, repository.StorageNamespace, leftCommit.MetaRangeID)
 	if err != nil {
 		return nil, err
 	}
@@ -3136,7 +3756,84 @@ func (g *Graveler) Compare(ctx context.Context, repository *RepositoryRecord, le
 	if err != nil {
 		return nil, err
 	}
-	return g.CommittedManager.Compare(ctx, repository.StorageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID)
+	return g.CommittedManager.Compare(ctx, repository.StorageID, repository.StorageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID)
+}
+
+func (g *Graveler) MergeConflicts(ctx context.Context, repository *RepositoryRecord, destination BranchID, source Ref, strategy string, resolver MergeResolver, after Key, limit int) ([]*MergeConflict, bool, error) {
+	mergeStrategy, err := parseMergeStrategy(strategy, resolver)
+	if err != nil {
+		return nil, false, err
+	}
+	if mergeStrategy != MergeStrategyNone {
+		// every conflict is resolved by the strategy
+		return nil, false, nil
+	}
+
+	fromCommit, toCommit, baseCommit, err := g.FindMergeBase(ctx, repository, source, Ref(destination))
+	if err != nil {
+		return nil, false, err
+	}
+	it, err := g.CommittedManager.Compare(ctx, repository.StorageID, repository.StorageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID)
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	it.SeekGE(after)
+
+	var conflicts []*MergeConflict
+	for it.Next() {
+		d := it.Value()
+		if d.Type != DiffTypeConflict || bytes.Equal(d.Key, after) {
+			continue
+		}
+		conflict := &MergeConflict{Key: d.Key.Copy()}
+		if conflict.Base, err = g.getCommittedValue(ctx, repository, baseCommit.MetaRangeID, conflict.Key); err != nil {
+			return nil, false, fmt.Errorf("get base value: %w", err)
+		}
+		if conflict.Source, err = g.getCommittedValue(ctx, repository, fromCommit.MetaRangeID, conflict.Key); err != nil {
+			return nil, false, fmt.Errorf("get source value: %w", err)
+		}
+		if conflict.Destination, err = g.getCommittedValue(ctx, repository, toCommit.MetaRangeID, conflict.Key); err != nil {
+			return nil, false, fmt.Errorf("get destination value: %w", err)
+		}
+		if resolver != nil {
+			_, err := resolver.Resolve(ctx, newValueRecord(conflict.Key, conflict.Base), newValueRecord(conflict.Key, conflict.Source), newValueRecord(conflict.Key, conflict.Destination))
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, ErrConflictFound) {
+				return nil, false, err
+			}
+		}
+		if len(conflicts) >= limit {
+			return conflicts, true, nil
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return conflicts, false, nil
+}
+
+// newValueRecord returns a record of key with value, or nil if there is no value
+func newValueRecord(key Key, value *Value) *ValueRecord {
+	if value == nil {
+		return nil
+	}
+	return &ValueRecord{Key: key, Value: value}
+}
+
+// getCommittedValue returns the value of key in the given metarange, or nil if the key does not exist
+func (g *Graveler) getCommittedValue(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID, key Key) (*Value, error) {
+	if metaRangeID == "" {
+		return nil, nil
+	}
+	value, err := g.CommittedManager.Get(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID, key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return value, err
 }
 
 func (g *Graveler) SetHooksHandler(handler HooksHandler) {
@@ -3156,7 +3853,7 @@ func (g *Graveler) LoadCommits(ctx context.Context, repository *RepositoryRecord
 		return ErrReadOnlyRepository
 	}
 
-	iter, err := g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
+	iter, err := g.CommittedManager.List(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID)
 	if err != nil {
 		return err
 	}
@@ -3184,6 +3881,7 @@ func (g *Graveler) LoadCommits(ctx context.Context, repository *RepositoryRecord
 			Parents:      parents,
 			Metadata:     commit.GetMetadata(),
 			Generation:   CommitGeneration(commit.GetGeneration()),
+			Signature:    commit.GetSignature(),
 		})
 		if err != nil {
 			return err
@@ -3207,7 +3905,7 @@ func (g *Graveler) LoadBranches(ctx context.Context, repository *RepositoryRecor
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
-	iter, err := g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
+	iter, err := g.CommittedManager.List(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID)
 	if err != nil {
 		return err
 	}
@@ -3224,7 +3922,7 @@ func (g *Graveler) LoadBranches(ctx context.Context, repository *RepositoryRecor
 			CommitID:     CommitID(branch.CommitId),
 			StagingToken: GenerateStagingToken(repository.RepositoryID, branchID),
 			SealedTokens: make([]StagingToken, 0),
-		})
+		}, ReflogRecord{Operation: "load_branches", User: options.User})
 		if err != nil {
 			return err
 		}
@@ -3243,7 +3941,7 @@ func (g *Graveler) LoadTags(ctx context.Context, repository *RepositoryRecord, m
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
-	iter, err := g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
+	iter, err := g.CommittedManager.List(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID)
 	if err != nil {
 		return err
 	}
@@ -3265,11 +3963,23 @@ func (g *Graveler) LoadTags(ctx context.Context, repository *RepositoryRecord, m
 }
 
 func (g *Graveler) GetMetaRange(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) (MetaRangeAddress, error) {
-	return g.CommittedManager.GetMetaRange(ctx, repository.StorageNamespace, metaRangeID)
+	return g.CommittedManager.GetMetaRange(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID)
 }
 
 func (g *Graveler) GetRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (RangeAddress, error) {
-	return g.CommittedManager.GetRange(ctx, repository.StorageNamespace, rangeID)
+	return g.CommittedManager.GetRange(ctx, repository.StorageID, repository.StorageNamespace, rangeID)
+}
+
+func (g *Graveler) ListRanges(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) ([]*RangeInfo, error) {
+	return g.CommittedManager.ListRanges(ctx, repository.StorageID, repository.StorageNamespace, metaRangeID)
+}
+
+func (g *Graveler) ListRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (ValueIterator, error) {
+	return g.CommittedManager.ListRange(ctx, repository.StorageID, repository.StorageNamespace, rangeID)
+}
+
+func (g *Graveler) ListCommits(ctx context.Context, repository *RepositoryRecord) (CommitIterator, error) {
+	return g.RefManager.ListCommits(ctx, repository)
 }
 
 func (g *Graveler) DumpCommits(ctx context.Context, repository *RepositoryRecord) (*MetaRangeID, error) {
@@ -3282,7 +3992,7 @@ func (g *Graveler) DumpCommits(ctx context.Context, repository *RepositoryRecord
 	if err != nil {
 		return nil, err
 	}
-	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageNamespace,
+	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageID, repository.StorageNamespace,
 		commitsToValueIterator(iter),
 		Metadata{
 			EntityTypeKey:             EntityTypeCommit,
@@ -3302,7 +4012,7 @@ func (g *Graveler) DumpBranches(ctx context.Context, repository *RepositoryRecor
 	if err != nil {
 		return nil, err
 	}
-	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageNamespace,
+	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageID, repository.StorageNamespace,
 		branchesToValueIterator(iter),
 		Metadata{
 			EntityTypeKey:             EntityTypeBranch,
@@ -3322,7 +4032,7 @@ func (g *Graveler) DumpTags(ctx context.Context, repository *RepositoryRecord) (
 	if err != nil {
 		return nil, err
 	}
-	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageNamespace,
+	return g.CommittedManager.WriteMetaRangeByIterator(ctx, repository.StorageID, repository.StorageNamespace,
 		tagsToValueIterator(iter),
 		Metadata{
 			EntityTypeKey:             EntityTypeTag,
@@ -3516,6 +4226,7 @@ func (c *commitValueIterator) setValue() bool {
 		Metadata:     commit.Metadata,
 		Parents:      commit.Parents.AsStringSlice(),
 		Generation:   int32(commit.Generation),
+		Signature:    commit.Signature,
 	})
 	if err != nil {
 		c.err = err
@@ -3553,14 +4264,15 @@ func (c *commitValueIterator) Close() {
 }
 
 type GarbageCollectionManager interface {
-	GetRules(ctx context.Context, storageNamespace StorageNamespace) (*GarbageCollectionRules, error)
-	SaveRules(ctx context.Context, storageNamespace StorageNamespace, rules *GarbageCollectionRules) error
+	GetRules(ctx context.Context, storageID StorageID, storageNamespace StorageNamespace) (*GarbageCollectionRules, error)
+	SaveRules(ctx context.Context, storageID StorageID, storageNamespace StorageNamespace, rules *GarbageCollectionRules) error
 
+	GetActiveCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) (map[CommitID]MetaRangeID, error)
 	SaveGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) (string, error)
-	GetCommitsCSVLocation(runID string, sn StorageNamespace) (string, error)
+	GetCommitsCSVLocation(runID string, storageID StorageID, sn StorageNamespace) (string, error)
 	SaveGarbageCollectionUncommitted(ctx context.Context, repository *RepositoryRecord, filename, runID string) error
-	GetUncommittedLocation(runID string, sn StorageNamespace) (string, error)
-	GetAddressesLocation(sn StorageNamespace) (string, error)
+	GetUncommittedLocation(runID string, storageID StorageID, sn StorageNamespace) (string, error)
+	GetAddressesLocation(storageID StorageID, sn StorageNamespace) (string, error)
 	NewID() string
 }
 
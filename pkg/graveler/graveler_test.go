@@ -23,6 +23,7 @@ type Hooks struct {
 	Err              error
 	RunID            string
 	RepositoryID     graveler.RepositoryID
+	StorageID        graveler.StorageID
 	StorageNamespace graveler.StorageNamespace
 	BranchID         graveler.BranchID
 	SourceRef        graveler.Ref
@@ -36,6 +37,7 @@ var ErrGravelerUpdate = errors.New("test update error")
 func (h *Hooks) PreCommitHook(_ context.Context, record graveler.HookRecord) error {
 	h.Called = true
 	h.RepositoryID = record.RepositoryID
+	h.StorageID = record.StorageID
 	h.StorageNamespace = record.StorageNamespace
 	h.BranchID = record.BranchID
 	h.Commit = record.Commit
@@ -54,6 +56,7 @@ func (h *Hooks) PostCommitHook(_ context.Context, record graveler.HookRecord) er
 func (h *Hooks) PreMergeHook(_ context.Context, record graveler.HookRecord) error {
 	h.Called = true
 	h.RepositoryID = record.RepositoryID
+	h.StorageID = record.StorageID
 	h.StorageNamespace = record.StorageNamespace
 	h.BranchID = record.BranchID
 	h.SourceRef = record.SourceRef
@@ -363,6 +366,85 @@ func TestGraveler_Set(t *testing.T) {
 	}
 }
 
+func TestGravelerSet_Condition(t *testing.T) {
+	newSetVal := &graveler.ValueRecord{Key: []byte("data/object"), Value: &graveler.Value{Data: []byte("newValue"), Identity: []byte("newIdentity")}}
+	committedVal := &graveler.Value{Identity: []byte("committedIdentity"), Data: []byte("committedValue")}
+	stagedVal := &graveler.Value{Identity: []byte("stagedIdentity"), Data: []byte("stagedValue")}
+	// identityCondition passes only when the current value has identity, or does not exist for an empty identity
+	identityCondition := func(identity string) graveler.ConditionFunc {
+		return func(currentValue *graveler.Value) error {
+			current := ""
+			if currentValue != nil {
+				current = string(currentValue.Identity)
+			}
+			if current != identity {
+				return graveler.ErrPreconditionFailed
+			}
+			return nil
+		}
+	}
+	tests := []struct {
+		name         string
+		condition    graveler.ConditionFunc
+		expectedErr  error
+		committedMgr *testutil.CommittedFake
+		stagingMgr   *testutil.StagingFake
+	}{
+		{
+			name:         "committed value matches",
+			condition:    identityCondition("committedIdentity"),
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"data/object": committedVal}},
+			stagingMgr:   &testutil.StagingFake{},
+		},
+		{
+			name:         "committed value mismatch",
+			condition:    identityCondition("otherIdentity"),
+			expectedErr:  graveler.ErrPreconditionFailed,
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"data/object": committedVal}},
+			stagingMgr:   &testutil.StagingFake{},
+		},
+		{
+			name:         "staged value overrides committed",
+			condition:    identityCondition("stagedIdentity"),
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"data/object": committedVal}},
+			stagingMgr:   &testutil.StagingFake{Values: map[string]map[string]*graveler.Value{"st": {"data/object": stagedVal}}},
+		},
+		{
+			name:         "staged value mismatch",
+			condition:    identityCondition("committedIdentity"),
+			expectedErr:  graveler.ErrPreconditionFailed,
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"data/object": committedVal}},
+			stagingMgr:   &testutil.StagingFake{Values: map[string]map[string]*graveler.Value{"st": {"data/object": stagedVal}}},
+		},
+		{
+			name:         "no value",
+			condition:    identityCondition(""),
+			committedMgr: &testutil.CommittedFake{Err: graveler.ErrNotFound},
+			stagingMgr:   &testutil.StagingFake{},
+		},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refMgr := &testutil.RefsFake{
+				RefType:      graveler.ReferenceTypeBranch,
+				CommitID:     "commit1",
+				StagingToken: "st",
+				Branch:       &graveler.Branch{CommitID: "commit1", StagingToken: "st"},
+				Commits:      map[graveler.CommitID]*graveler.Commit{"commit1": {}},
+			}
+			store := newGraveler(t, tt.committedMgr, tt.stagingMgr, refMgr, nil, testutil.NewProtectedBranchesManagerFake())
+			err := store.Set(ctx, repository, "branch-1", newSetVal.Key, *newSetVal.Value, graveler.WithCondition(tt.condition))
+			require.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Equal(t, newSetVal, tt.stagingMgr.LastSetValueRecord)
+			} else {
+				require.Nil(t, tt.stagingMgr.LastSetValueRecord)
+			}
+		})
+	}
+}
+
 func TestGravelerSet_Advanced(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	ctx := context.Background()
@@ -1087,6 +1169,13 @@ func TestGraveler_UpdateBranch(t *testing.T) {
 		&testutil.RefsFake{Branch: &graveler.Branch{StagingToken: "st1", CommitID: "commit1"}, Commits: map[graveler.CommitID]*graveler.Commit{"commit1": {}}}, nil, nil)
 	_, err = gravel.UpdateBranch(context.Background(), repository, "", "")
 	require.NoError(t, err)
+
+	gravel = newGraveler(t, &testutil.CommittedFake{ValueIterator: testutil.NewValueIteratorFake([]graveler.ValueRecord{})}, &testutil.StagingFake{ValueIterator: testutil.NewValueIteratorFake([]graveler.ValueRecord{})},
+		&testutil.RefsFake{Branch: &graveler.Branch{StagingToken: "st1", CommitID: "commit1"}, Commits: map[graveler.CommitID]*graveler.Commit{"commit1": {}}}, nil, nil)
+	_, err = gravel.UpdateBranch(context.Background(), repository, "", "", graveler.WithExpectedCommitID("commit2"))
+	require.ErrorIs(t, err, graveler.ErrBranchHeadMismatch)
+	_, err = gravel.UpdateBranch(context.Background(), repository, "", "", graveler.WithExpectedCommitID("commit1"))
+	require.NoError(t, err)
 }
 
 func TestGravelerCommit(t *testing.T) {
@@ -1390,7 +1479,7 @@ func TestGraveler_MergeInvalidRef(t *testing.T) {
 		Committer: commitCommitter,
 		Message:   mergeMessage,
 		Metadata:  graveler.Metadata{"key1": "val1"},
-	}, "")
+	}, "", graveler.MergeOptions{})
 	if !errors.Is(err, graveler.ErrInvalidRef) {
 		t.Fatalf("Merge failed with err=%v, expected ErrInvalidRef", err)
 	}
@@ -1876,7 +1965,7 @@ func TestGraveler_PreMergeHook(t *testing.T) {
 				Committer: commitCommitter,
 				Message:   mergeMessage,
 				Metadata:  mergeMetadata,
-			}, "", graveler.WithForce(tt.readOnlyRepo))
+			}, "", graveler.MergeOptions{}, graveler.WithForce(tt.readOnlyRepo))
 			// verify we got an error
 			if !errors.Is(err, tt.err) {
 				t.Fatalf("Merge err=%v, pre-merge error expected=%v", err, tt.err)
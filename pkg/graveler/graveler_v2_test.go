@@ -68,6 +68,18 @@ var (
 	value2        = &graveler.Value{Identity: []byte("id2"), Data: []byte("data2")}
 )
 
+// reflogOperation matches a reflog record of the operation
+type reflogOperation string
+
+func (m reflogOperation) Matches(x interface{}) bool {
+	record, ok := x.(graveler.ReflogRecord)
+	return ok && record.Operation == string(m)
+}
+
+func (m reflogOperation) String() string {
+	return "reflog record of operation " + string(m)
+}
+
 func TestGravelerGet(t *testing.T) {
 	ctx := context.Background()
 	setupGetFromBranch := func(test *testutil.GravelerTest) {
@@ -102,7 +114,7 @@ func TestGravelerGet(t *testing.T) {
 		test.StagingManager.EXPECT().Get(ctx, stagingToken3, key1).Times(1).Return(nil, graveler.ErrNotFound)
 
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().Get(ctx, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(value1, nil)
+		test.CommittedManager.EXPECT().Get(ctx, repository.StorageID, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(value1, nil)
 
 		val, err := test.Sut.Get(ctx, repository, graveler.Ref(branch1ID), key1)
 
@@ -133,7 +145,7 @@ func TestGravelerGet(t *testing.T) {
 		test.StagingManager.EXPECT().Get(ctx, stagingToken3, key1).Times(1).Return(nil, graveler.ErrNotFound)
 
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().Get(ctx, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(nil, graveler.ErrNotFound)
+		test.CommittedManager.EXPECT().Get(ctx, repository.StorageID, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(nil, graveler.ErrNotFound)
 
 		val, err := test.Sut.Get(ctx, repository, graveler.Ref(branch1ID), key1)
 
@@ -146,7 +158,7 @@ func TestGravelerGet(t *testing.T) {
 		setupGetFromCommit(test)
 
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().Get(ctx, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(value1, nil)
+		test.CommittedManager.EXPECT().Get(ctx, repository.StorageID, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(value1, nil)
 
 		val, err := test.Sut.Get(ctx, repository, graveler.Ref(commit1ID), key1)
 
@@ -160,7 +172,7 @@ func TestGravelerGet(t *testing.T) {
 		setupGetFromCommit(test)
 
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().Get(ctx, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(nil, graveler.ErrNotFound)
+		test.CommittedManager.EXPECT().Get(ctx, repository.StorageID, repository.StorageNamespace, commit1.MetaRangeID, key1).Times(1).Return(nil, graveler.ErrNotFound)
 
 		val, err := test.Sut.Get(ctx, repository, graveler.Ref(commit1ID), key1)
 
@@ -173,8 +185,8 @@ func TestGravelerMerge(t *testing.T) {
 	ctx := context.Background()
 
 	firstUpdateBranch := func(test *testutil.GravelerTest) {
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("merge"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				require.NoError(t, err)
@@ -200,20 +212,21 @@ func TestGravelerMerge(t *testing.T) {
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(branch2ID)).Times(1).Return(rawRefCommit2, nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(branch1ID)).Times(1).Return(rawRefCommit1, nil)
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit2).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit2ID}}}, nil)
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit1).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit1ID}}}, nil)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit2ID).Times(1).Return(&commit2, nil)
 		test.RefManager.EXPECT().FindMergeBase(ctx, repository, commit2ID, commit1ID).Times(1).Return(&commit3, nil)
-		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageNamespace, mr1ID, mr2ID, mr3ID, graveler.MergeStrategyNone, []graveler.SetOptionsFunc{}).Times(1).Return(mr4ID, nil)
+		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageID, repository.StorageNamespace, mr1ID, mr2ID, mr3ID, graveler.MergeStrategyNone, nil, []graveler.SetOptionsFunc{}).Times(1).Return(mr4ID, nil)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT).Return(false, nil)
 		test.RefManager.EXPECT().AddCommit(ctx, repository, gomock.Any()).DoAndReturn(func(ctx context.Context, repository *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
 			require.Equal(t, mr4ID, commit.MetaRangeID)
 			return commit4ID, nil
 		}).Times(1)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("merge"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := &graveler.Branch{StagingToken: stagingToken4, CommitID: commit1ID, SealedTokens: []graveler.StagingToken{stagingToken1, stagingToken2, stagingToken3}}
 				updatedBranch, err := f(branchTest)
 				require.NoError(t, err)
@@ -226,7 +239,7 @@ func TestGravelerMerge(t *testing.T) {
 		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken2).Times(1)
 		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken3).Times(1)
 
-		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "")
+		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "", graveler.MergeOptions{})
 
 		require.NoError(t, err)
 		require.NotNil(t, val)
@@ -235,8 +248,8 @@ func TestGravelerMerge(t *testing.T) {
 
 	t.Run("merge dirty destination while updating tokens", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("merge"), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				require.Error(t, err)
@@ -257,9 +270,9 @@ func TestGravelerMerge(t *testing.T) {
 			Value: value1,
 		}}))
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(1).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(1).Return(testutils.NewFakeValueIterator(nil), nil)
 
-		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "")
+		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "", graveler.MergeOptions{})
 		require.Equal(t, graveler.ErrDirtyBranch, err)
 		require.Equal(t, graveler.CommitID(""), val)
 	})
@@ -274,24 +287,25 @@ func TestGravelerMerge(t *testing.T) {
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(branch2ID)).Times(1).Return(rawRefCommit2, nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(branch1ID)).Times(1).Return(rawRefCommit1, nil)
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit2).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit2ID}}}, nil)
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit1).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit1ID}}}, nil)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit2ID).Times(1).Return(&commit2, nil)
 		test.RefManager.EXPECT().FindMergeBase(ctx, repository, commit2ID, commit1ID).Times(1).Return(&commit3, nil)
-		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageNamespace, mr1ID, mr2ID, mr3ID, graveler.MergeStrategyNone, []graveler.SetOptionsFunc{}).Times(1).Return(mr4ID, nil)
+		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageID, repository.StorageNamespace, mr1ID, mr2ID, mr3ID, graveler.MergeStrategyNone, nil, []graveler.SetOptionsFunc{}).Times(1).Return(mr4ID, nil)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT).Return(false, nil)
 		test.RefManager.EXPECT().AddCommit(ctx, repository, gomock.Any()).DoAndReturn(func(ctx context.Context, repository *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
 			require.Equal(t, mr4ID, commit.MetaRangeID)
 			return commit4ID, nil
 		}).Times(1)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("merge"), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				return kv.ErrPredicateFailed
 			}).Times(graveler.BranchUpdateMaxTries - 1)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("merge"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := &graveler.Branch{StagingToken: stagingToken4, CommitID: commit1ID, SealedTokens: []graveler.StagingToken{stagingToken1, stagingToken2, stagingToken3}}
 				updatedBranch, err := f(branchTest)
 				require.NoError(t, err)
@@ -304,7 +318,7 @@ func TestGravelerMerge(t *testing.T) {
 		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken2).Times(1)
 		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken3).Times(1)
 
-		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "")
+		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "", graveler.MergeOptions{})
 
 		require.NoError(t, err)
 		require.NotNil(t, val)
@@ -316,13 +330,13 @@ func TestGravelerMerge(t *testing.T) {
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 1)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(1).Return(testutils.NewFakeValueIterator(nil), nil)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(1).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("merge"), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				return kv.ErrPredicateFailed
 			}).Times(graveler.BranchUpdateMaxTries)
 
-		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "")
+		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "", graveler.MergeOptions{})
 
 		require.ErrorIs(t, err, graveler.ErrTooManyTries)
 		require.Empty(t, val)
@@ -333,8 +347,8 @@ func TestGravelerRevert(t *testing.T) {
 	ctx := context.Background()
 
 	firstUpdateBranch := func(test *testutil.GravelerTest) {
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("revert"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				require.NoError(t, err)
@@ -368,7 +382,7 @@ func TestGravelerRevert(t *testing.T) {
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit2ID)).Times(1).Return(rawRefCommit2, nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit1ID)).Times(1).Return(rawRefCommit1, nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit4ID)).Times(1).Return(rawRefCommit4, nil)
@@ -377,13 +391,14 @@ func TestGravelerRevert(t *testing.T) {
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit4).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit4ID}}}, nil)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit2ID).Times(1).Return(&commit2, nil)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit4ID).Times(1).Return(&commit4, nil)
-		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageNamespace, mr1ID, mr4ID, mr2ID, graveler.MergeStrategyNone, []graveler.SetOptionsFunc{}).Times(1).Return(mr3ID, nil)
+		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageID, repository.StorageNamespace, mr1ID, mr4ID, mr2ID, graveler.MergeStrategyNone, nil, []graveler.SetOptionsFunc{}).Times(1).Return(mr3ID, nil)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT).Return(false, nil)
 		test.RefManager.EXPECT().AddCommit(ctx, repository, gomock.Any()).DoAndReturn(func(ctx context.Context, repository *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
 			require.Equal(t, mr3ID, commit.MetaRangeID)
 			return commit3ID, nil
 		}).Times(1)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("revert"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := &graveler.Branch{StagingToken: stagingToken4, CommitID: commit1ID, SealedTokens: []graveler.StagingToken{stagingToken1, stagingToken2, stagingToken3}}
 				updatedBranch, err := f(branchTest)
 				require.NoError(t, err)
@@ -410,12 +425,12 @@ func TestGravelerRevert(t *testing.T) {
 		dirtyStagingTokenCombo(test)
 
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(2).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit2ID)).Times(1).Return(rawRefCommit2, nil)
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit2).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit2ID}}}, nil)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit2ID).Times(1).Return(&commit2, nil)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("revert"), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := &graveler.Branch{StagingToken: stagingToken4, CommitID: commit1ID, SealedTokens: []graveler.StagingToken{stagingToken1, stagingToken2, stagingToken3}}
 				updatedBranch, err := f(branchTest)
 				require.True(t, errors.Is(err, graveler.ErrDirtyBranch))
@@ -434,8 +449,8 @@ func TestGravelerCherryPick(t *testing.T) {
 	ctx := context.Background()
 
 	firstUpdateBranch := func(test *testutil.GravelerTest) {
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("cherrypick"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				require.NoError(t, err)
@@ -461,7 +476,7 @@ func TestGravelerCherryPick(t *testing.T) {
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit2ID)).Times(1).Return(rawRefCommit2, nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit1ID)).Times(1).Return(rawRefCommit1, nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit4ID)).Times(1).Return(rawRefCommit4, nil)
@@ -470,13 +485,14 @@ func TestGravelerCherryPick(t *testing.T) {
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit4).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit4ID}}}, nil)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit2ID).Times(1).Return(&commit2, nil)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit4ID).Times(1).Return(&commit4, nil)
-		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageNamespace, mr1ID, mr2ID, mr4ID, graveler.MergeStrategyNone, []graveler.SetOptionsFunc{}).Times(1).Return(mr3ID, nil)
+		test.CommittedManager.EXPECT().Merge(ctx, repository.StorageID, repository.StorageNamespace, mr1ID, mr2ID, mr4ID, graveler.MergeStrategyNone, nil, []graveler.SetOptionsFunc{}).Times(1).Return(mr3ID, nil)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT).Return(false, nil)
 		test.RefManager.EXPECT().AddCommit(ctx, repository, gomock.Any()).DoAndReturn(func(ctx context.Context, repository *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
 			require.Equal(t, mr3ID, commit.MetaRangeID)
 			return commit3ID, nil
 		}).Times(1)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("cherrypick"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := &graveler.Branch{StagingToken: stagingToken4, CommitID: commit1ID, SealedTokens: []graveler.StagingToken{stagingToken1, stagingToken2, stagingToken3}}
 				updatedBranch, err := f(branchTest)
 				require.NoError(t, err)
@@ -505,8 +521,8 @@ func TestGravelerCommit_v2(t *testing.T) {
 		var updatedSealedBranch graveler.Branch
 		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
 
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				updatedSealedBranch = *updatedBranch
@@ -517,8 +533,8 @@ func TestGravelerCommit_v2(t *testing.T) {
 				return nil
 			}).Times(1)
 
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("commit"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				updatedBranch, err := f(&updatedSealedBranch)
 				require.NoError(t, err)
 				require.Equal(t, []graveler.StagingToken{}, updatedBranch.SealedTokens)
@@ -530,7 +546,8 @@ func TestGravelerCommit_v2(t *testing.T) {
 		test.StagingManager.EXPECT().List(ctx, stagingToken1, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{}))
 		test.StagingManager.EXPECT().List(ctx, stagingToken2, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{}))
 		test.StagingManager.EXPECT().List(ctx, stagingToken3, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{}))
-		test.CommittedManager.EXPECT().Commit(ctx, repository.StorageNamespace, mr1ID, gomock.Any(), false, []graveler.SetOptionsFunc{}).Times(1).Return(graveler.MetaRangeID(""), graveler.DiffSummary{}, nil)
+		test.CommittedManager.EXPECT().Commit(ctx, repository.StorageID, repository.StorageNamespace, mr1ID, gomock.Any(), false, []graveler.SetOptionsFunc{}).Times(1).Return(graveler.MetaRangeID(""), graveler.DiffSummary{}, nil)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT).Return(false, nil)
 		test.RefManager.EXPECT().AddCommit(ctx, repository, gomock.Any()).Return(graveler.CommitID(""), nil)
 		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken1).Return(nil)
 		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken2).Return(nil)
@@ -547,8 +564,8 @@ func TestGravelerCommit_v2(t *testing.T) {
 		var updatedSealedBranch graveler.Branch
 		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
 
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				updatedSealedBranch = *updatedBranch
@@ -559,8 +576,8 @@ func TestGravelerCommit_v2(t *testing.T) {
 				return nil
 			}).Times(1)
 
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("commit"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				updatedBranch, err := f(&updatedSealedBranch)
 				require.Error(t, err)
 				require.True(t, errors.Is(err, graveler.ErrNoChanges))
@@ -572,7 +589,7 @@ func TestGravelerCommit_v2(t *testing.T) {
 		test.StagingManager.EXPECT().List(ctx, stagingToken1, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{}))
 		test.StagingManager.EXPECT().List(ctx, stagingToken2, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{}))
 		test.StagingManager.EXPECT().List(ctx, stagingToken3, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{}))
-		test.CommittedManager.EXPECT().Commit(ctx, repository.StorageNamespace, mr1ID, gomock.Any(), false, []graveler.SetOptionsFunc{}).Times(1).Return(graveler.MetaRangeID(""), graveler.DiffSummary{}, graveler.ErrNoChanges)
+		test.CommittedManager.EXPECT().Commit(ctx, repository.StorageID, repository.StorageNamespace, mr1ID, gomock.Any(), false, []graveler.SetOptionsFunc{}).Times(1).Return(graveler.MetaRangeID(""), graveler.DiffSummary{}, graveler.ErrNoChanges)
 
 		val, err := test.Sut.Commit(ctx, repository, branch1ID, graveler.CommitParams{})
 
@@ -585,8 +602,8 @@ func TestGravelerCommit_v2(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
 		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
 
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				require.NoError(t, err)
@@ -596,7 +613,7 @@ func TestGravelerCommit_v2(t *testing.T) {
 				return nil
 			}).Times(1)
 
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).Times(graveler.BranchUpdateMaxTries).Return(kv.ErrPredicateFailed)
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("commit"), gomock.Any()).Times(graveler.BranchUpdateMaxTries).Return(kv.ErrPredicateFailed)
 
 		val, err := test.Sut.Commit(ctx, repository, branch1ID, graveler.CommitParams{})
 
@@ -630,8 +647,8 @@ func TestGravelerImport(t *testing.T) {
 	ctx := context.Background()
 
 	firstUpdateBranch := func(test *testutil.GravelerTest) {
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("import"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
 				updatedBranch, err := f(&branchTest)
 				require.NoError(t, err)
@@ -658,16 +675,17 @@ func TestGravelerImport(t *testing.T) {
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
-		test.CommittedManager.EXPECT().List(ctx, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
+		test.CommittedManager.EXPECT().List(ctx, repository.StorageID, repository.StorageNamespace, mr1ID).Times(2).Return(testutils.NewFakeValueIterator(nil), nil)
 		test.RefManager.EXPECT().ParseRef(graveler.Ref(branch1ID)).Times(1).Return(rawRefCommit1, nil)
 		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefCommit1).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit1ID}}}, nil)
-		test.CommittedManager.EXPECT().Import(ctx, repository.StorageNamespace, mr1ID, mr2ID, nil, []graveler.SetOptionsFunc{}).Times(1).Return(mr4ID, nil)
+		test.CommittedManager.EXPECT().Import(ctx, repository.StorageID, repository.StorageNamespace, mr1ID, mr2ID, nil, []graveler.SetOptionsFunc{}).Times(1).Return(mr4ID, nil)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_UNSIGNED_COMMIT).Return(false, nil)
 		test.RefManager.EXPECT().AddCommit(ctx, repository, gomock.Any()).DoAndReturn(func(ctx context.Context, repository *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
 			require.Equal(t, mr4ID, commit.MetaRangeID)
 			return commit4ID, nil
 		}).Times(1)
-		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
-			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, reflogOperation("import"), gomock.Any()).
+			Do(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 				branchTest := &graveler.Branch{StagingToken: stagingToken4, CommitID: commit1ID, SealedTokens: []graveler.StagingToken{stagingToken1, stagingToken2, stagingToken3}}
 				updatedBranch, err := f(branchTest)
 				require.NoError(t, err)
@@ -33,6 +33,7 @@ type HookRecord struct {
 	RunID            string
 	EventType        EventType
 	RepositoryID     RepositoryID
+	StorageID        StorageID
 	StorageNamespace StorageNamespace
 	// The reference which the actions files are read from
 	SourceRef Ref
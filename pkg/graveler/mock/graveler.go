@@ -13,6 +13,44 @@ import (
 	graveler "github.com/treeverse/lakefs/pkg/graveler"
 )
 
+// MockMergeResolver is a mock of MergeResolver interface.
+type MockMergeResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockMergeResolverMockRecorder
+}
+
+// MockMergeResolverMockRecorder is the mock recorder for MockMergeResolver.
+type MockMergeResolverMockRecorder struct {
+	mock *MockMergeResolver
+}
+
+// NewMockMergeResolver creates a new mock instance.
+func NewMockMergeResolver(ctrl *gomock.Controller) *MockMergeResolver {
+	mock := &MockMergeResolver{ctrl: ctrl}
+	mock.recorder = &MockMergeResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMergeResolver) EXPECT() *MockMergeResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockMergeResolver) Resolve(ctx context.Context, base, source, dest *graveler.ValueRecord) (*graveler.ValueRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, base, source, dest)
+	ret0, _ := ret[0].(*graveler.ValueRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockMergeResolverMockRecorder) Resolve(ctx, base, source, dest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockMergeResolver)(nil).Resolve), ctx, base, source, dest)
+}
+
 // MockKeyValueStore is a mock of KeyValueStore interface.
 type MockKeyValueStore struct {
 	ctrl     *gomock.Controller
@@ -272,18 +310,18 @@ func (mr *MockVersionControllerMockRecorder) Compare(ctx, repository, left, righ
 }
 
 // CreateBareRepository mocks base method.
-func (m *MockVersionController) CreateBareRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageNamespace graveler.StorageNamespace, defaultBranchID graveler.BranchID, readOnly bool) (*graveler.RepositoryRecord, error) {
+func (m *MockVersionController) CreateBareRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageID graveler.StorageID, storageNamespace graveler.StorageNamespace, defaultBranchID graveler.BranchID, readOnly bool) (*graveler.RepositoryRecord, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBareRepository", ctx, repositoryID, storageNamespace, defaultBranchID, readOnly)
+	ret := m.ctrl.Call(m, "CreateBareRepository", ctx, repositoryID, storageID, storageNamespace, defaultBranchID, readOnly)
 	ret0, _ := ret[0].(*graveler.RepositoryRecord)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateBareRepository indicates an expected call of CreateBareRepository.
-func (mr *MockVersionControllerMockRecorder) CreateBareRepository(ctx, repositoryID, storageNamespace, defaultBranchID, readOnly interface{}) *gomock.Call {
+func (mr *MockVersionControllerMockRecorder) CreateBareRepository(ctx, repositoryID, storageID, storageNamespace, defaultBranchID, readOnly interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBareRepository", reflect.TypeOf((*MockVersionController)(nil).CreateBareRepository), ctx, repositoryID, storageNamespace, defaultBranchID, readOnly)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBareRepository", reflect.TypeOf((*MockVersionController)(nil).CreateBareRepository), ctx, repositoryID, storageID, storageNamespace, defaultBranchID, readOnly)
 }
 
 // CreateBranch mocks base method.
@@ -326,18 +364,18 @@ func (mr *MockVersionControllerMockRecorder) CreateCommitRecord(ctx, repository,
 }
 
 // CreateRepository mocks base method.
-func (m *MockVersionController) CreateRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageNamespace graveler.StorageNamespace, branchID graveler.BranchID, readOnly bool) (*graveler.RepositoryRecord, error) {
+func (m *MockVersionController) CreateRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageID graveler.StorageID, storageNamespace graveler.StorageNamespace, branchID graveler.BranchID, readOnly bool) (*graveler.RepositoryRecord, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRepository", ctx, repositoryID, storageNamespace, branchID, readOnly)
+	ret := m.ctrl.Call(m, "CreateRepository", ctx, repositoryID, storageID, storageNamespace, branchID, readOnly)
 	ret0, _ := ret[0].(*graveler.RepositoryRecord)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateRepository indicates an expected call of CreateRepository.
-func (mr *MockVersionControllerMockRecorder) CreateRepository(ctx, repositoryID, storageNamespace, branchID, readOnly interface{}) *gomock.Call {
+func (mr *MockVersionControllerMockRecorder) CreateRepository(ctx, repositoryID, storageID, storageNamespace, branchID, readOnly interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRepository", reflect.TypeOf((*MockVersionController)(nil).CreateRepository), ctx, repositoryID, storageNamespace, branchID, readOnly)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRepository", reflect.TypeOf((*MockVersionController)(nil).CreateRepository), ctx, repositoryID, storageID, storageNamespace, branchID, readOnly)
 }
 
 // CreateTag mocks base method.
@@ -581,6 +619,22 @@ func (mr *MockVersionControllerMockRecorder) GetCommit(ctx, repository, commitID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommit", reflect.TypeOf((*MockVersionController)(nil).GetCommit), ctx, repository, commitID)
 }
 
+// GetExpiredCommits mocks base method.
+func (m *MockVersionController) GetExpiredCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (map[graveler.CommitID]graveler.MetaRangeID, map[graveler.CommitID]graveler.MetaRangeID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredCommits", ctx, repository, rules)
+	ret0, _ := ret[0].(map[graveler.CommitID]graveler.MetaRangeID)
+	ret1, _ := ret[1].(map[graveler.CommitID]graveler.MetaRangeID)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetExpiredCommits indicates an expected call of GetExpiredCommits.
+func (mr *MockVersionControllerMockRecorder) GetExpiredCommits(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredCommits", reflect.TypeOf((*MockVersionController)(nil).GetExpiredCommits), ctx, repository, rules)
+}
+
 // GetGarbageCollectionRules mocks base method.
 func (m *MockVersionController) GetGarbageCollectionRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.GarbageCollectionRules, error) {
 	m.ctrl.T.Helper()
@@ -721,6 +775,21 @@ func (mr *MockVersionControllerMockRecorder) ListLinkAddresses(ctx, repository i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLinkAddresses", reflect.TypeOf((*MockVersionController)(nil).ListLinkAddresses), ctx, repository)
 }
 
+// ListReflog mocks base method.
+func (m *MockVersionController) ListReflog(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (graveler.ReflogIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReflog", ctx, repository, branchID)
+	ret0, _ := ret[0].(graveler.ReflogIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReflog indicates an expected call of ListReflog.
+func (mr *MockVersionControllerMockRecorder) ListReflog(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReflog", reflect.TypeOf((*MockVersionController)(nil).ListReflog), ctx, repository, branchID)
+}
+
 // ListRepositories mocks base method.
 func (m *MockVersionController) ListRepositories(ctx context.Context) (graveler.RepositoryIterator, error) {
 	m.ctrl.T.Helper()
@@ -752,24 +821,24 @@ func (mr *MockVersionControllerMockRecorder) ListTags(ctx, repository interface{
 }
 
 // Log mocks base method.
-func (m *MockVersionController) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since *time.Time) (graveler.CommitIterator, error) {
+func (m *MockVersionController) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since, until *time.Time) (graveler.CommitIterator, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Log", ctx, repository, commitID, firstParent, since)
+	ret := m.ctrl.Call(m, "Log", ctx, repository, commitID, firstParent, since, until)
 	ret0, _ := ret[0].(graveler.CommitIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Log indicates an expected call of Log.
-func (mr *MockVersionControllerMockRecorder) Log(ctx, repository, commitID, firstParent, since interface{}) *gomock.Call {
+func (mr *MockVersionControllerMockRecorder) Log(ctx, repository, commitID, firstParent, since, until interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockVersionController)(nil).Log), ctx, repository, commitID, firstParent, since)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockVersionController)(nil).Log), ctx, repository, commitID, firstParent, since, until)
 }
 
 // Merge mocks base method.
-func (m *MockVersionController) Merge(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, source graveler.Ref, commitParams graveler.CommitParams, strategy string, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
+func (m *MockVersionController) Merge(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, source graveler.Ref, commitParams graveler.CommitParams, strategy string, mergeOptions graveler.MergeOptions, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, repository, destination, source, commitParams, strategy}
+	varargs := []interface{}{ctx, repository, destination, source, commitParams, strategy, mergeOptions}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
@@ -780,12 +849,28 @@ func (m *MockVersionController) Merge(ctx context.Context, repository *graveler.
 }
 
 // Merge indicates an expected call of Merge.
-func (mr *MockVersionControllerMockRecorder) Merge(ctx, repository, destination, source, commitParams, strategy interface{}, opts ...interface{}) *gomock.Call {
+func (mr *MockVersionControllerMockRecorder) Merge(ctx, repository, destination, source, commitParams, strategy, mergeOptions interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, repository, destination, source, commitParams, strategy}, opts...)
+	varargs := append([]interface{}{ctx, repository, destination, source, commitParams, strategy, mergeOptions}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockVersionController)(nil).Merge), varargs...)
 }
 
+// MergeConflicts mocks base method.
+func (m *MockVersionController) MergeConflicts(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, source graveler.Ref, strategy string, resolver graveler.MergeResolver, after graveler.Key, limit int) ([]*graveler.MergeConflict, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeConflicts", ctx, repository, destination, source, strategy, resolver, after, limit)
+	ret0, _ := ret[0].([]*graveler.MergeConflict)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MergeConflicts indicates an expected call of MergeConflicts.
+func (mr *MockVersionControllerMockRecorder) MergeConflicts(ctx, repository, destination, source, strategy, resolver, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeConflicts", reflect.TypeOf((*MockVersionController)(nil).MergeConflicts), ctx, repository, destination, source, strategy, resolver, after, limit)
+}
+
 // ParseRef mocks base method.
 func (m *MockVersionController) ParseRef(ref graveler.Ref) (graveler.RawRef, error) {
 	m.ctrl.T.Helper()
@@ -801,6 +886,26 @@ func (mr *MockVersionControllerMockRecorder) ParseRef(ref interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseRef", reflect.TypeOf((*MockVersionController)(nil).ParseRef), ref)
 }
 
+// Rebase mocks base method.
+func (m *MockVersionController) Rebase(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, onto graveler.Ref, committer string, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID, onto, committer}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Rebase", varargs...)
+	ret0, _ := ret[0].(graveler.CommitID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebase indicates an expected call of Rebase.
+func (mr *MockVersionControllerMockRecorder) Rebase(ctx, repository, branchID, onto, committer interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID, onto, committer}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebase", reflect.TypeOf((*MockVersionController)(nil).Rebase), varargs...)
+}
+
 // Reset mocks base method.
 func (m *MockVersionController) Reset(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, opts ...graveler.SetOptionsFunc) error {
 	m.ctrl.T.Helper()
@@ -892,6 +997,26 @@ func (mr *MockVersionControllerMockRecorder) ResolveRawRef(ctx, repository, rawR
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRawRef", reflect.TypeOf((*MockVersionController)(nil).ResolveRawRef), ctx, repository, rawRef)
 }
 
+// RestoreReflog mocks base method.
+func (m *MockVersionController) RestoreReflog(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, entryID string, opts ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID, entryID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreReflog", varargs...)
+	ret0, _ := ret[0].(*graveler.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreReflog indicates an expected call of RestoreReflog.
+func (mr *MockVersionControllerMockRecorder) RestoreReflog(ctx, repository, branchID, entryID interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID, entryID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreReflog", reflect.TypeOf((*MockVersionController)(nil).RestoreReflog), varargs...)
+}
+
 // Revert mocks base method.
 func (m *MockVersionController) Revert(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, ref graveler.Ref, parentNumber int, commitParams graveler.CommitParams, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
 	m.ctrl.T.Helper()
@@ -995,6 +1120,26 @@ func (mr *MockVersionControllerMockRecorder) SetRepositoryMetadata(ctx, reposito
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepositoryMetadata", reflect.TypeOf((*MockVersionController)(nil).SetRepositoryMetadata), ctx, repository, updateFunc)
 }
 
+// Transaction mocks base method.
+func (m *MockVersionController) Transaction(ctx context.Context, repository *graveler.RepositoryRecord, params graveler.TransactionParams, opts ...graveler.SetOptionsFunc) (map[graveler.BranchID]graveler.CommitID, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, params}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Transaction", varargs...)
+	ret0, _ := ret[0].(map[graveler.BranchID]graveler.CommitID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockVersionControllerMockRecorder) Transaction(ctx, repository, params interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, params}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockVersionController)(nil).Transaction), varargs...)
+}
+
 // UpdateBranch mocks base method.
 func (m *MockVersionController) UpdateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, ref graveler.Ref, opts ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
 	m.ctrl.T.Helper()
@@ -1015,6 +1160,20 @@ func (mr *MockVersionControllerMockRecorder) UpdateBranch(ctx, repository, branc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranch", reflect.TypeOf((*MockVersionController)(nil).UpdateBranch), varargs...)
 }
 
+// VerifyCommitSignature mocks base method.
+func (m *MockVersionController) VerifyCommitSignature(commit graveler.Commit) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCommitSignature", commit)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// VerifyCommitSignature indicates an expected call of VerifyCommitSignature.
+func (mr *MockVersionControllerMockRecorder) VerifyCommitSignature(commit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCommitSignature", reflect.TypeOf((*MockVersionController)(nil).VerifyCommitSignature), commit)
+}
+
 // VerifyLinkAddress mocks base method.
 func (m *MockVersionController) VerifyLinkAddress(ctx context.Context, repository *graveler.RepositoryRecord, physicalAddress string) error {
 	m.ctrl.T.Helper()
@@ -1102,6 +1261,51 @@ func (mr *MockPlumbingMockRecorder) GetRange(ctx, repository, rangeID interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockPlumbing)(nil).GetRange), ctx, repository, rangeID)
 }
 
+// ListCommits mocks base method.
+func (m *MockPlumbing) ListCommits(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.CommitIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommits", ctx, repository)
+	ret0, _ := ret[0].(graveler.CommitIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommits indicates an expected call of ListCommits.
+func (mr *MockPlumbingMockRecorder) ListCommits(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockPlumbing)(nil).ListCommits), ctx, repository)
+}
+
+// ListRange mocks base method.
+func (m *MockPlumbing) ListRange(ctx context.Context, repository *graveler.RepositoryRecord, rangeID graveler.RangeID) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, repository, rangeID)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockPlumbingMockRecorder) ListRange(ctx, repository, rangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockPlumbing)(nil).ListRange), ctx, repository, rangeID)
+}
+
+// ListRanges mocks base method.
+func (m *MockPlumbing) ListRanges(ctx context.Context, repository *graveler.RepositoryRecord, metaRangeID graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRanges", ctx, repository, metaRangeID)
+	ret0, _ := ret[0].([]*graveler.RangeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRanges indicates an expected call of ListRanges.
+func (mr *MockPlumbingMockRecorder) ListRanges(ctx, repository, metaRangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRanges", reflect.TypeOf((*MockPlumbing)(nil).ListRanges), ctx, repository, metaRangeID)
+}
+
 // StageObject mocks base method.
 func (m *MockPlumbing) StageObject(ctx context.Context, stagingToken string, object graveler.ValueRecord) error {
 	m.ctrl.T.Helper()
@@ -1749,6 +1953,95 @@ func (mr *MockTagIteratorMockRecorder) Value() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockTagIterator)(nil).Value))
 }
 
+// MockReflogIterator is a mock of ReflogIterator interface.
+type MockReflogIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReflogIteratorMockRecorder
+}
+
+// MockReflogIteratorMockRecorder is the mock recorder for MockReflogIterator.
+type MockReflogIteratorMockRecorder struct {
+	mock *MockReflogIterator
+}
+
+// NewMockReflogIterator creates a new mock instance.
+func NewMockReflogIterator(ctrl *gomock.Controller) *MockReflogIterator {
+	mock := &MockReflogIterator{ctrl: ctrl}
+	mock.recorder = &MockReflogIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReflogIterator) EXPECT() *MockReflogIteratorMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockReflogIterator) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockReflogIteratorMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockReflogIterator)(nil).Close))
+}
+
+// Err mocks base method.
+func (m *MockReflogIterator) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockReflogIteratorMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockReflogIterator)(nil).Err))
+}
+
+// Next mocks base method.
+func (m *MockReflogIterator) Next() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockReflogIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockReflogIterator)(nil).Next))
+}
+
+// SeekGE mocks base method.
+func (m *MockReflogIterator) SeekGE(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SeekGE", id)
+}
+
+// SeekGE indicates an expected call of SeekGE.
+func (mr *MockReflogIteratorMockRecorder) SeekGE(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekGE", reflect.TypeOf((*MockReflogIterator)(nil).SeekGE), id)
+}
+
+// Value mocks base method.
+func (m *MockReflogIterator) Value() *graveler.ReflogEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Value")
+	ret0, _ := ret[0].(*graveler.ReflogEntry)
+	return ret0
+}
+
+// Value indicates an expected call of Value.
+func (mr *MockReflogIteratorMockRecorder) Value() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockReflogIterator)(nil).Value))
+}
+
 // MockCommitIterator is a mock of CommitIterator interface.
 type MockCommitIterator struct {
 	ctrl     *gomock.Controller
@@ -1966,17 +2259,31 @@ func (mr *MockRefManagerMockRecorder) AddCommit(ctx, repository, commit interfac
 }
 
 // BranchUpdate mocks base method.
-func (m *MockRefManager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, f graveler.BranchUpdateFunc) error {
+func (m *MockRefManager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, record graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BranchUpdate", ctx, repository, branchID, f)
+	ret := m.ctrl.Call(m, "BranchUpdate", ctx, repository, branchID, record, f)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BranchUpdate indicates an expected call of BranchUpdate.
-func (mr *MockRefManagerMockRecorder) BranchUpdate(ctx, repository, branchID, f interface{}) *gomock.Call {
+func (mr *MockRefManagerMockRecorder) BranchUpdate(ctx, repository, branchID, record, f interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchUpdate", reflect.TypeOf((*MockRefManager)(nil).BranchUpdate), ctx, repository, branchID, f)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchUpdate", reflect.TypeOf((*MockRefManager)(nil).BranchUpdate), ctx, repository, branchID, record, f)
+}
+
+// BranchesUpdate mocks base method.
+func (m *MockRefManager) BranchesUpdate(ctx context.Context, repository *graveler.RepositoryRecord, record graveler.ReflogRecord, updates map[graveler.BranchID]graveler.BranchUpdateFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BranchesUpdate", ctx, repository, record, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BranchesUpdate indicates an expected call of BranchesUpdate.
+func (mr *MockRefManagerMockRecorder) BranchesUpdate(ctx, repository, record, updates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchesUpdate", reflect.TypeOf((*MockRefManager)(nil).BranchesUpdate), ctx, repository, record, updates)
 }
 
 // CreateBareRepository mocks base method.
@@ -1995,17 +2302,17 @@ func (mr *MockRefManagerMockRecorder) CreateBareRepository(ctx, repositoryID, re
 }
 
 // CreateBranch mocks base method.
-func (m *MockRefManager) CreateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
+func (m *MockRefManager) CreateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch, record graveler.ReflogRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBranch", ctx, repository, branchID, branch)
+	ret := m.ctrl.Call(m, "CreateBranch", ctx, repository, branchID, branch, record)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateBranch indicates an expected call of CreateBranch.
-func (mr *MockRefManagerMockRecorder) CreateBranch(ctx, repository, branchID, branch interface{}) *gomock.Call {
+func (mr *MockRefManagerMockRecorder) CreateBranch(ctx, repository, branchID, branch, record interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBranch", reflect.TypeOf((*MockRefManager)(nil).CreateBranch), ctx, repository, branchID, branch)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBranch", reflect.TypeOf((*MockRefManager)(nil).CreateBranch), ctx, repository, branchID, branch, record)
 }
 
 // CreateCommitRecord mocks base method.
@@ -2052,17 +2359,17 @@ func (mr *MockRefManagerMockRecorder) CreateTag(ctx, repository, tagID, commitID
 }
 
 // DeleteBranch mocks base method.
-func (m *MockRefManager) DeleteBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+func (m *MockRefManager) DeleteBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, record graveler.ReflogRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBranch", ctx, repository, branchID)
+	ret := m.ctrl.Call(m, "DeleteBranch", ctx, repository, branchID, record)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBranch indicates an expected call of DeleteBranch.
-func (mr *MockRefManagerMockRecorder) DeleteBranch(ctx, repository, branchID interface{}) *gomock.Call {
+func (mr *MockRefManagerMockRecorder) DeleteBranch(ctx, repository, branchID, record interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBranch", reflect.TypeOf((*MockRefManager)(nil).DeleteBranch), ctx, repository, branchID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBranch", reflect.TypeOf((*MockRefManager)(nil).DeleteBranch), ctx, repository, branchID, record)
 }
 
 // DeleteExpiredImports mocks base method.
@@ -2221,6 +2528,21 @@ func (mr *MockRefManagerMockRecorder) GetCommitByPrefix(ctx, repository, prefix
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommitByPrefix", reflect.TypeOf((*MockRefManager)(nil).GetCommitByPrefix), ctx, repository, prefix)
 }
 
+// GetReflogEntry mocks base method.
+func (m *MockRefManager) GetReflogEntry(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, entryID string) (*graveler.ReflogEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReflogEntry", ctx, repository, branchID, entryID)
+	ret0, _ := ret[0].(*graveler.ReflogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReflogEntry indicates an expected call of GetReflogEntry.
+func (mr *MockRefManagerMockRecorder) GetReflogEntry(ctx, repository, branchID, entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReflogEntry", reflect.TypeOf((*MockRefManager)(nil).GetReflogEntry), ctx, repository, branchID, entryID)
+}
+
 // GetRepository mocks base method.
 func (m *MockRefManager) GetRepository(ctx context.Context, repositoryID graveler.RepositoryID) (*graveler.RepositoryRecord, error) {
 	m.ctrl.T.Helper()
@@ -2326,6 +2648,21 @@ func (mr *MockRefManagerMockRecorder) ListLinkAddresses(ctx, repository interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLinkAddresses", reflect.TypeOf((*MockRefManager)(nil).ListLinkAddresses), ctx, repository)
 }
 
+// ListReflog mocks base method.
+func (m *MockRefManager) ListReflog(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (graveler.ReflogIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReflog", ctx, repository, branchID)
+	ret0, _ := ret[0].(graveler.ReflogIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReflog indicates an expected call of ListReflog.
+func (mr *MockRefManagerMockRecorder) ListReflog(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReflog", reflect.TypeOf((*MockRefManager)(nil).ListReflog), ctx, repository, branchID)
+}
+
 // ListRepositories mocks base method.
 func (m *MockRefManager) ListRepositories(ctx context.Context) (graveler.RepositoryIterator, error) {
 	m.ctrl.T.Helper()
@@ -2357,18 +2694,18 @@ func (mr *MockRefManagerMockRecorder) ListTags(ctx, repository interface{}) *gom
 }
 
 // Log mocks base method.
-func (m *MockRefManager) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since *time.Time) (graveler.CommitIterator, error) {
+func (m *MockRefManager) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since, until *time.Time) (graveler.CommitIterator, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Log", ctx, repository, commitID, firstParent, since)
+	ret := m.ctrl.Call(m, "Log", ctx, repository, commitID, firstParent, since, until)
 	ret0, _ := ret[0].(graveler.CommitIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Log indicates an expected call of Log.
-func (mr *MockRefManagerMockRecorder) Log(ctx, repository, commitID, firstParent, since interface{}) *gomock.Call {
+func (mr *MockRefManagerMockRecorder) Log(ctx, repository, commitID, firstParent, since, until interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockRefManager)(nil).Log), ctx, repository, commitID, firstParent, since)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockRefManager)(nil).Log), ctx, repository, commitID, firstParent, since, until)
 }
 
 // ParseRef mocks base method.
@@ -2416,17 +2753,17 @@ func (mr *MockRefManagerMockRecorder) ResolveRawRef(ctx, repository, rawRef inte
 }
 
 // SetBranch mocks base method.
-func (m *MockRefManager) SetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
+func (m *MockRefManager) SetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch, record graveler.ReflogRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetBranch", ctx, repository, branchID, branch)
+	ret := m.ctrl.Call(m, "SetBranch", ctx, repository, branchID, branch, record)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetBranch indicates an expected call of SetBranch.
-func (mr *MockRefManagerMockRecorder) SetBranch(ctx, repository, branchID, branch interface{}) *gomock.Call {
+func (mr *MockRefManagerMockRecorder) SetBranch(ctx, repository, branchID, branch, record interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBranch", reflect.TypeOf((*MockRefManager)(nil).SetBranch), ctx, repository, branchID, branch)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBranch", reflect.TypeOf((*MockRefManager)(nil).SetBranch), ctx, repository, branchID, branch, record)
 }
 
 // SetLinkAddress mocks base method.
@@ -2495,9 +2832,9 @@ func (m *MockCommittedManager) EXPECT() *MockCommittedManagerMockRecorder {
 }
 
 // Commit mocks base method.
-func (m *MockCommittedManager) Commit(ctx context.Context, ns graveler.StorageNamespace, baseMetaRangeID graveler.MetaRangeID, changes graveler.ValueIterator, allowEmpty bool, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, graveler.DiffSummary, error) {
+func (m *MockCommittedManager) Commit(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, baseMetaRangeID graveler.MetaRangeID, changes graveler.ValueIterator, allowEmpty bool, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, graveler.DiffSummary, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, ns, baseMetaRangeID, changes, allowEmpty}
+	varargs := []interface{}{ctx, storageID, ns, baseMetaRangeID, changes, allowEmpty}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
@@ -2509,121 +2846,121 @@ func (m *MockCommittedManager) Commit(ctx context.Context, ns graveler.StorageNa
 }
 
 // Commit indicates an expected call of Commit.
-func (mr *MockCommittedManagerMockRecorder) Commit(ctx, ns, baseMetaRangeID, changes, allowEmpty interface{}, opts ...interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) Commit(ctx, storageID, ns, baseMetaRangeID, changes, allowEmpty interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, ns, baseMetaRangeID, changes, allowEmpty}, opts...)
+	varargs := append([]interface{}{ctx, storageID, ns, baseMetaRangeID, changes, allowEmpty}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockCommittedManager)(nil).Commit), varargs...)
 }
 
 // Compare mocks base method.
-func (m *MockCommittedManager) Compare(ctx context.Context, ns graveler.StorageNamespace, destination, source, base graveler.MetaRangeID) (graveler.DiffIterator, error) {
+func (m *MockCommittedManager) Compare(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, destination, source, base graveler.MetaRangeID) (graveler.DiffIterator, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Compare", ctx, ns, destination, source, base)
+	ret := m.ctrl.Call(m, "Compare", ctx, storageID, ns, destination, source, base)
 	ret0, _ := ret[0].(graveler.DiffIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Compare indicates an expected call of Compare.
-func (mr *MockCommittedManagerMockRecorder) Compare(ctx, ns, destination, source, base interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) Compare(ctx, storageID, ns, destination, source, base interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockCommittedManager)(nil).Compare), ctx, ns, destination, source, base)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockCommittedManager)(nil).Compare), ctx, storageID, ns, destination, source, base)
 }
 
 // Diff mocks base method.
-func (m *MockCommittedManager) Diff(ctx context.Context, ns graveler.StorageNamespace, left, right graveler.MetaRangeID) (graveler.DiffIterator, error) {
+func (m *MockCommittedManager) Diff(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, left, right graveler.MetaRangeID) (graveler.DiffIterator, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Diff", ctx, ns, left, right)
+	ret := m.ctrl.Call(m, "Diff", ctx, storageID, ns, left, right)
 	ret0, _ := ret[0].(graveler.DiffIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Diff indicates an expected call of Diff.
-func (mr *MockCommittedManagerMockRecorder) Diff(ctx, ns, left, right interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) Diff(ctx, storageID, ns, left, right interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockCommittedManager)(nil).Diff), ctx, ns, left, right)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockCommittedManager)(nil).Diff), ctx, storageID, ns, left, right)
 }
 
 // Exists mocks base method.
-func (m *MockCommittedManager) Exists(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) (bool, error) {
+func (m *MockCommittedManager) Exists(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, id graveler.MetaRangeID) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Exists", ctx, ns, id)
+	ret := m.ctrl.Call(m, "Exists", ctx, storageID, ns, id)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Exists indicates an expected call of Exists.
-func (mr *MockCommittedManagerMockRecorder) Exists(ctx, ns, id interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) Exists(ctx, storageID, ns, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockCommittedManager)(nil).Exists), ctx, ns, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockCommittedManager)(nil).Exists), ctx, storageID, ns, id)
 }
 
 // Get mocks base method.
-func (m *MockCommittedManager) Get(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID, key graveler.Key) (*graveler.Value, error) {
+func (m *MockCommittedManager) Get(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID, key graveler.Key) (*graveler.Value, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, ns, rangeID, key)
+	ret := m.ctrl.Call(m, "Get", ctx, storageID, ns, rangeID, key)
 	ret0, _ := ret[0].(*graveler.Value)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockCommittedManagerMockRecorder) Get(ctx, ns, rangeID, key interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) Get(ctx, storageID, ns, rangeID, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCommittedManager)(nil).Get), ctx, ns, rangeID, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCommittedManager)(nil).Get), ctx, storageID, ns, rangeID, key)
 }
 
 // GetMetaRange mocks base method.
-func (m *MockCommittedManager) GetMetaRange(ctx context.Context, ns graveler.StorageNamespace, metaRangeID graveler.MetaRangeID) (graveler.MetaRangeAddress, error) {
+func (m *MockCommittedManager) GetMetaRange(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, metaRangeID graveler.MetaRangeID) (graveler.MetaRangeAddress, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMetaRange", ctx, ns, metaRangeID)
+	ret := m.ctrl.Call(m, "GetMetaRange", ctx, storageID, ns, metaRangeID)
 	ret0, _ := ret[0].(graveler.MetaRangeAddress)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMetaRange indicates an expected call of GetMetaRange.
-func (mr *MockCommittedManagerMockRecorder) GetMetaRange(ctx, ns, metaRangeID interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) GetMetaRange(ctx, storageID, ns, metaRangeID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetaRange", reflect.TypeOf((*MockCommittedManager)(nil).GetMetaRange), ctx, ns, metaRangeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetaRange", reflect.TypeOf((*MockCommittedManager)(nil).GetMetaRange), ctx, storageID, ns, metaRangeID)
 }
 
 // GetRange mocks base method.
-func (m *MockCommittedManager) GetRange(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.RangeID) (graveler.RangeAddress, error) {
+func (m *MockCommittedManager) GetRange(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, rangeID graveler.RangeID) (graveler.RangeAddress, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRange", ctx, ns, rangeID)
+	ret := m.ctrl.Call(m, "GetRange", ctx, storageID, ns, rangeID)
 	ret0, _ := ret[0].(graveler.RangeAddress)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRange indicates an expected call of GetRange.
-func (mr *MockCommittedManagerMockRecorder) GetRange(ctx, ns, rangeID interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) GetRange(ctx, storageID, ns, rangeID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockCommittedManager)(nil).GetRange), ctx, ns, rangeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockCommittedManager)(nil).GetRange), ctx, storageID, ns, rangeID)
 }
 
 // GetRangeIDByKey mocks base method.
-func (m *MockCommittedManager) GetRangeIDByKey(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID, key graveler.Key) (graveler.RangeID, error) {
+func (m *MockCommittedManager) GetRangeIDByKey(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, id graveler.MetaRangeID, key graveler.Key) (graveler.RangeID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRangeIDByKey", ctx, ns, id, key)
+	ret := m.ctrl.Call(m, "GetRangeIDByKey", ctx, storageID, ns, id, key)
 	ret0, _ := ret[0].(graveler.RangeID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRangeIDByKey indicates an expected call of GetRangeIDByKey.
-func (mr *MockCommittedManagerMockRecorder) GetRangeIDByKey(ctx, ns, id, key interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) GetRangeIDByKey(ctx, storageID, ns, id, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRangeIDByKey", reflect.TypeOf((*MockCommittedManager)(nil).GetRangeIDByKey), ctx, ns, id, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRangeIDByKey", reflect.TypeOf((*MockCommittedManager)(nil).GetRangeIDByKey), ctx, storageID, ns, id, key)
 }
 
 // Import mocks base method.
-func (m *MockCommittedManager) Import(ctx context.Context, ns graveler.StorageNamespace, destination, source graveler.MetaRangeID, prefixes []graveler.Prefix, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, error) {
+func (m *MockCommittedManager) Import(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, destination, source graveler.MetaRangeID, prefixes []graveler.Prefix, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, ns, destination, source, prefixes}
+	varargs := []interface{}{ctx, storageID, ns, destination, source, prefixes}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
@@ -2634,31 +2971,61 @@ func (m *MockCommittedManager) Import(ctx context.Context, ns graveler.StorageNa
 }
 
 // Import indicates an expected call of Import.
-func (mr *MockCommittedManagerMockRecorder) Import(ctx, ns, destination, source, prefixes interface{}, opts ...interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) Import(ctx, storageID, ns, destination, source, prefixes interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, ns, destination, source, prefixes}, opts...)
+	varargs := append([]interface{}{ctx, storageID, ns, destination, source, prefixes}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockCommittedManager)(nil).Import), varargs...)
 }
 
 // List mocks base method.
-func (m *MockCommittedManager) List(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID) (graveler.ValueIterator, error) {
+func (m *MockCommittedManager) List(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID) (graveler.ValueIterator, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, ns, rangeID)
+	ret := m.ctrl.Call(m, "List", ctx, storageID, ns, rangeID)
 	ret0, _ := ret[0].(graveler.ValueIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockCommittedManagerMockRecorder) List(ctx, ns, rangeID interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) List(ctx, storageID, ns, rangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCommittedManager)(nil).List), ctx, storageID, ns, rangeID)
+}
+
+// ListRange mocks base method.
+func (m *MockCommittedManager) ListRange(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, rangeID graveler.RangeID) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, storageID, ns, rangeID)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockCommittedManagerMockRecorder) ListRange(ctx, storageID, ns, rangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockCommittedManager)(nil).ListRange), ctx, storageID, ns, rangeID)
+}
+
+// ListRanges mocks base method.
+func (m *MockCommittedManager) ListRanges(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, metaRangeID graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRanges", ctx, storageID, ns, metaRangeID)
+	ret0, _ := ret[0].([]*graveler.RangeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRanges indicates an expected call of ListRanges.
+func (mr *MockCommittedManagerMockRecorder) ListRanges(ctx, storageID, ns, metaRangeID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCommittedManager)(nil).List), ctx, ns, rangeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRanges", reflect.TypeOf((*MockCommittedManager)(nil).ListRanges), ctx, storageID, ns, metaRangeID)
 }
 
 // Merge mocks base method.
-func (m *MockCommittedManager) Merge(ctx context.Context, ns graveler.StorageNamespace, destination, source, base graveler.MetaRangeID, strategy graveler.MergeStrategy, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, error) {
+func (m *MockCommittedManager) Merge(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, destination, source, base graveler.MetaRangeID, strategy graveler.MergeStrategy, resolver graveler.MergeResolver, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, ns, destination, source, base, strategy}
+	varargs := []interface{}{ctx, storageID, ns, destination, source, base, strategy, resolver}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
@@ -2669,55 +3036,55 @@ func (m *MockCommittedManager) Merge(ctx context.Context, ns graveler.StorageNam
 }
 
 // Merge indicates an expected call of Merge.
-func (mr *MockCommittedManagerMockRecorder) Merge(ctx, ns, destination, source, base, strategy interface{}, opts ...interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) Merge(ctx, storageID, ns, destination, source, base, strategy, resolver interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, ns, destination, source, base, strategy}, opts...)
+	varargs := append([]interface{}{ctx, storageID, ns, destination, source, base, strategy, resolver}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockCommittedManager)(nil).Merge), varargs...)
 }
 
 // WriteMetaRange mocks base method.
-func (m *MockCommittedManager) WriteMetaRange(ctx context.Context, ns graveler.StorageNamespace, ranges []*graveler.RangeInfo) (*graveler.MetaRangeInfo, error) {
+func (m *MockCommittedManager) WriteMetaRange(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, ranges []*graveler.RangeInfo) (*graveler.MetaRangeInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WriteMetaRange", ctx, ns, ranges)
+	ret := m.ctrl.Call(m, "WriteMetaRange", ctx, storageID, ns, ranges)
 	ret0, _ := ret[0].(*graveler.MetaRangeInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // WriteMetaRange indicates an expected call of WriteMetaRange.
-func (mr *MockCommittedManagerMockRecorder) WriteMetaRange(ctx, ns, ranges interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) WriteMetaRange(ctx, storageID, ns, ranges interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMetaRange", reflect.TypeOf((*MockCommittedManager)(nil).WriteMetaRange), ctx, ns, ranges)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMetaRange", reflect.TypeOf((*MockCommittedManager)(nil).WriteMetaRange), ctx, storageID, ns, ranges)
 }
 
 // WriteMetaRangeByIterator mocks base method.
-func (m *MockCommittedManager) WriteMetaRangeByIterator(ctx context.Context, ns graveler.StorageNamespace, it graveler.ValueIterator, metadata graveler.Metadata) (*graveler.MetaRangeID, error) {
+func (m *MockCommittedManager) WriteMetaRangeByIterator(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, it graveler.ValueIterator, metadata graveler.Metadata) (*graveler.MetaRangeID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WriteMetaRangeByIterator", ctx, ns, it, metadata)
+	ret := m.ctrl.Call(m, "WriteMetaRangeByIterator", ctx, storageID, ns, it, metadata)
 	ret0, _ := ret[0].(*graveler.MetaRangeID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // WriteMetaRangeByIterator indicates an expected call of WriteMetaRangeByIterator.
-func (mr *MockCommittedManagerMockRecorder) WriteMetaRangeByIterator(ctx, ns, it, metadata interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) WriteMetaRangeByIterator(ctx, storageID, ns, it, metadata interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMetaRangeByIterator", reflect.TypeOf((*MockCommittedManager)(nil).WriteMetaRangeByIterator), ctx, ns, it, metadata)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMetaRangeByIterator", reflect.TypeOf((*MockCommittedManager)(nil).WriteMetaRangeByIterator), ctx, storageID, ns, it, metadata)
 }
 
 // WriteRange mocks base method.
-func (m *MockCommittedManager) WriteRange(ctx context.Context, ns graveler.StorageNamespace, it graveler.ValueIterator) (*graveler.RangeInfo, error) {
+func (m *MockCommittedManager) WriteRange(ctx context.Context, storageID graveler.StorageID, ns graveler.StorageNamespace, it graveler.ValueIterator) (*graveler.RangeInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WriteRange", ctx, ns, it)
+	ret := m.ctrl.Call(m, "WriteRange", ctx, storageID, ns, it)
 	ret0, _ := ret[0].(*graveler.RangeInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // WriteRange indicates an expected call of WriteRange.
-func (mr *MockCommittedManagerMockRecorder) WriteRange(ctx, ns, it interface{}) *gomock.Call {
+func (mr *MockCommittedManagerMockRecorder) WriteRange(ctx, storageID, ns, it interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRange", reflect.TypeOf((*MockCommittedManager)(nil).WriteRange), ctx, ns, it)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRange", reflect.TypeOf((*MockCommittedManager)(nil).WriteRange), ctx, storageID, ns, it)
 }
 
 // MockStagingManager is a mock of StagingManager interface.
@@ -2932,64 +3299,79 @@ func (m *MockGarbageCollectionManager) EXPECT() *MockGarbageCollectionManagerMoc
 	return m.recorder
 }
 
+// GetActiveCommits mocks base method.
+func (m *MockGarbageCollectionManager) GetActiveCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (map[graveler.CommitID]graveler.MetaRangeID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveCommits", ctx, repository, rules)
+	ret0, _ := ret[0].(map[graveler.CommitID]graveler.MetaRangeID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveCommits indicates an expected call of GetActiveCommits.
+func (mr *MockGarbageCollectionManagerMockRecorder) GetActiveCommits(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveCommits", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetActiveCommits), ctx, repository, rules)
+}
+
 // GetAddressesLocation mocks base method.
-func (m *MockGarbageCollectionManager) GetAddressesLocation(sn graveler.StorageNamespace) (string, error) {
+func (m *MockGarbageCollectionManager) GetAddressesLocation(storageID graveler.StorageID, sn graveler.StorageNamespace) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAddressesLocation", sn)
+	ret := m.ctrl.Call(m, "GetAddressesLocation", storageID, sn)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAddressesLocation indicates an expected call of GetAddressesLocation.
-func (mr *MockGarbageCollectionManagerMockRecorder) GetAddressesLocation(sn interface{}) *gomock.Call {
+func (mr *MockGarbageCollectionManagerMockRecorder) GetAddressesLocation(storageID, sn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressesLocation", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetAddressesLocation), sn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressesLocation", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetAddressesLocation), storageID, sn)
 }
 
 // GetCommitsCSVLocation mocks base method.
-func (m *MockGarbageCollectionManager) GetCommitsCSVLocation(runID string, sn graveler.StorageNamespace) (string, error) {
+func (m *MockGarbageCollectionManager) GetCommitsCSVLocation(runID string, storageID graveler.StorageID, sn graveler.StorageNamespace) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCommitsCSVLocation", runID, sn)
+	ret := m.ctrl.Call(m, "GetCommitsCSVLocation", runID, storageID, sn)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCommitsCSVLocation indicates an expected call of GetCommitsCSVLocation.
-func (mr *MockGarbageCollectionManagerMockRecorder) GetCommitsCSVLocation(runID, sn interface{}) *gomock.Call {
+func (mr *MockGarbageCollectionManagerMockRecorder) GetCommitsCSVLocation(runID, storageID, sn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommitsCSVLocation", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetCommitsCSVLocation), runID, sn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommitsCSVLocation", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetCommitsCSVLocation), runID, storageID, sn)
 }
 
 // GetRules mocks base method.
-func (m *MockGarbageCollectionManager) GetRules(ctx context.Context, storageNamespace graveler.StorageNamespace) (*graveler.GarbageCollectionRules, error) {
+func (m *MockGarbageCollectionManager) GetRules(ctx context.Context, storageID graveler.StorageID, storageNamespace graveler.StorageNamespace) (*graveler.GarbageCollectionRules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRules", ctx, storageNamespace)
+	ret := m.ctrl.Call(m, "GetRules", ctx, storageID, storageNamespace)
 	ret0, _ := ret[0].(*graveler.GarbageCollectionRules)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRules indicates an expected call of GetRules.
-func (mr *MockGarbageCollectionManagerMockRecorder) GetRules(ctx, storageNamespace interface{}) *gomock.Call {
+func (mr *MockGarbageCollectionManagerMockRecorder) GetRules(ctx, storageID, storageNamespace interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetRules), ctx, storageNamespace)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetRules), ctx, storageID, storageNamespace)
 }
 
 // GetUncommittedLocation mocks base method.
-func (m *MockGarbageCollectionManager) GetUncommittedLocation(runID string, sn graveler.StorageNamespace) (string, error) {
+func (m *MockGarbageCollectionManager) GetUncommittedLocation(runID string, storageID graveler.StorageID, sn graveler.StorageNamespace) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUncommittedLocation", runID, sn)
+	ret := m.ctrl.Call(m, "GetUncommittedLocation", runID, storageID, sn)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUncommittedLocation indicates an expected call of GetUncommittedLocation.
-func (mr *MockGarbageCollectionManagerMockRecorder) GetUncommittedLocation(runID, sn interface{}) *gomock.Call {
+func (mr *MockGarbageCollectionManagerMockRecorder) GetUncommittedLocation(runID, storageID, sn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUncommittedLocation", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetUncommittedLocation), runID, sn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUncommittedLocation", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetUncommittedLocation), runID, storageID, sn)
 }
 
 // NewID mocks base method.
@@ -3036,17 +3418,17 @@ func (mr *MockGarbageCollectionManagerMockRecorder) SaveGarbageCollectionUncommi
 }
 
 // SaveRules mocks base method.
-func (m *MockGarbageCollectionManager) SaveRules(ctx context.Context, storageNamespace graveler.StorageNamespace, rules *graveler.GarbageCollectionRules) error {
+func (m *MockGarbageCollectionManager) SaveRules(ctx context.Context, storageID graveler.StorageID, storageNamespace graveler.StorageNamespace, rules *graveler.GarbageCollectionRules) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveRules", ctx, storageNamespace, rules)
+	ret := m.ctrl.Call(m, "SaveRules", ctx, storageID, storageNamespace, rules)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveRules indicates an expected call of SaveRules.
-func (mr *MockGarbageCollectionManagerMockRecorder) SaveRules(ctx, storageNamespace, rules interface{}) *gomock.Call {
+func (mr *MockGarbageCollectionManagerMockRecorder) SaveRules(ctx, storageID, storageNamespace, rules interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRules", reflect.TypeOf((*MockGarbageCollectionManager)(nil).SaveRules), ctx, storageNamespace, rules)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRules", reflect.TypeOf((*MockGarbageCollectionManager)(nil).SaveRules), ctx, storageID, storageNamespace, rules)
 }
 
 // MockProtectedBranchesManager is a mock of ProtectedBranchesManager interface.
@@ -8,16 +8,19 @@ import (
 )
 
 const (
-	gravelerPartition      = "graveler"
-	cleanupTokensPartition = "cleanup-tokens"
-	reposPrefix            = "repos"
-	tagsPrefix             = "tags"
-	branchesPrefix         = "branches"
-	commitsPrefix          = "commits"
-	settingsPrefix         = "settings"
-	addressesPrefix        = "link-addresses"
-	importsPrefix          = "imports"
-	repoMetadataPrefix     = "repo-metadata"
+	gravelerPartition        = "graveler"
+	cleanupTokensPartition   = "cleanup-tokens"
+	reposPrefix              = "repos"
+	tagsPrefix               = "tags"
+	branchesPrefix           = "branches"
+	commitsPrefix            = "commits"
+	settingsPrefix           = "settings"
+	addressesPrefix          = "link-addresses"
+	importsPrefix            = "imports"
+	reflogPrefix             = "reflog"
+	branchLocksPrefix        = "branch-locks"
+	branchTransactionsPrefix = "branch-transactions"
+	repoMetadataPrefix       = "repo-metadata"
 )
 
 //nolint:gochecknoinits
@@ -26,6 +29,9 @@ func init() {
 	kv.MustRegisterType("*", "branches", (&BranchData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "commits", (&CommitData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "tags", (&TagData{}).ProtoReflect().Type())
+	kv.MustRegisterType("*", reflogPrefix, (&ReflogEntryData{}).ProtoReflect().Type())
+	kv.MustRegisterType("*", branchLocksPrefix, (&BranchLockData{}).ProtoReflect().Type())
+	kv.MustRegisterType("*", branchTransactionsPrefix, (&BranchTransactionData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "*", (&StagedEntryData{}).ProtoReflect().Type())
 }
 
@@ -77,6 +83,25 @@ func ImportsPath(key string) string {
 	return kv.FormatPath(importsPrefix, key)
 }
 
+// ReflogPath returns the path of a branch reflog entry. An empty entryID returns the prefix of the branch reflog
+// entries, and an empty branchID returns the prefix of all the reflog entries.
+func ReflogPath(branchID BranchID, entryID string) string {
+	if branchID == "" {
+		return kv.FormatPath(reflogPrefix, "")
+	}
+	return kv.FormatPath(reflogPrefix, branchID.String(), entryID)
+}
+
+func BranchLockPath(branchID BranchID) string {
+	return kv.FormatPath(branchLocksPrefix, branchID.String())
+}
+
+// BranchTransactionPath returns the path of a branch transaction. An empty transactionID returns the prefix of all
+// the branch transactions.
+func BranchTransactionPath(transactionID string) string {
+	return kv.FormatPath(branchTransactionsPrefix, transactionID)
+}
+
 func RepoMetadataPath() string {
 	return repoMetadataPrefix
 }
@@ -96,6 +121,7 @@ func CommitFromProto(pb *CommitData) *Commit {
 		Parents:      parents,
 		Metadata:     pb.Metadata,
 		Generation:   CommitGeneration(pb.Generation),
+		Signature:    pb.Signature,
 	}
 }
 
@@ -116,6 +142,7 @@ func ProtoFromCommit(commitID CommitID, c *Commit) *CommitData {
 		Parents:      parents,
 		Version:      int32(c.Version),
 		Generation:   int32(c.Generation),
+		Signature:    c.Signature,
 	}
 }
 
@@ -123,6 +150,7 @@ func RepoFromProto(pb *RepositoryData) *RepositoryRecord {
 	return &RepositoryRecord{
 		RepositoryID: RepositoryID(pb.Id),
 		Repository: &Repository{
+			StorageID:        StorageID(pb.StorageId),
 			StorageNamespace: StorageNamespace(pb.StorageNamespace),
 			DefaultBranchID:  BranchID(pb.DefaultBranchId),
 			CreationDate:     pb.CreationDate.AsTime(),
@@ -136,6 +164,7 @@ func RepoFromProto(pb *RepositoryData) *RepositoryRecord {
 func ProtoFromRepo(repo *RepositoryRecord) *RepositoryData {
 	return &RepositoryData{
 		Id:               repo.RepositoryID.String(),
+		StorageId:        repo.Repository.StorageID.String(),
 		StorageNamespace: repo.Repository.StorageNamespace.String(),
 		DefaultBranchId:  repo.Repository.DefaultBranchID.String(),
 		CreationDate:     timestamppb.New(repo.Repository.CreationDate),
@@ -167,6 +196,30 @@ func TagFromProto(pb *TagData) *TagRecord {
 	}
 }
 
+func ReflogEntryFromProto(pb *ReflogEntryData) *ReflogEntry {
+	return &ReflogEntry{
+		ID:           pb.Id,
+		BranchID:     BranchID(pb.BranchId),
+		OldCommitID:  CommitID(pb.OldCommitId),
+		NewCommitID:  CommitID(pb.NewCommitId),
+		Operation:    pb.Operation,
+		User:         pb.User,
+		CreationDate: pb.CreationDate.AsTime(),
+	}
+}
+
+func ProtoFromReflogEntry(entry *ReflogEntry) *ReflogEntryData {
+	return &ReflogEntryData{
+		Id:           entry.ID,
+		BranchId:     entry.BranchID.String(),
+		OldCommitId:  entry.OldCommitID.String(),
+		NewCommitId:  entry.NewCommitID.String(),
+		Operation:    entry.Operation,
+		User:         entry.User,
+		CreationDate: timestamppb.New(entry.CreationDate),
+	}
+}
+
 func ImportStatusFromProto(pb *ImportStatusData) *ImportStatus {
 	var commit *CommitRecord
 	if pb.Commit != nil {
@@ -1,6 +1,10 @@
 package graveler
 
-import "math"
+import (
+	"math"
+	"sort"
+	"strings"
+)
 
 // UpperBoundForPrefix returns, given a prefix `p`, a slice 'q' such that a byte slice `s` starts with `p`
 // if and only if p <= s < q. Namely, it returns an exclusive upper bound for the set of all byte arrays
@@ -19,3 +23,57 @@ func UpperBoundForPrefix(prefix []byte) []byte {
 	upperBound[idx]++
 	return upperBound
 }
+
+// prefixFilterIterator iterates over the values of the underlying iterator with a key starting with one of the prefixes
+type prefixFilterIterator struct {
+	it ValueIterator
+	// prefixes sorted, without prefixes covered by another prefix
+	prefixes []Prefix
+}
+
+// NewPrefixFilterIterator returns an iterator over the values of 'it' with a key starting with one of 'prefixes'
+func NewPrefixFilterIterator(it ValueIterator, prefixes []Prefix) ValueIterator {
+	sorted := make([]Prefix, len(prefixes))
+	copy(sorted, prefixes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	filtered := make([]Prefix, 0, len(sorted))
+	for _, p := range sorted {
+		if len(filtered) > 0 && strings.HasPrefix(string(p), string(filtered[len(filtered)-1])) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	return &prefixFilterIterator{it: it, prefixes: filtered}
+}
+
+func (pi *prefixFilterIterator) Next() bool {
+	for pi.it.Next() {
+		key := string(pi.it.Value().Key)
+		// index of the first prefix greater than the key, only the prefix before it may match the key
+		idx := sort.Search(len(pi.prefixes), func(i int) bool { return string(pi.prefixes[i]) > key })
+		if idx > 0 && strings.HasPrefix(key, string(pi.prefixes[idx-1])) {
+			return true
+		}
+		if idx == len(pi.prefixes) {
+			return false
+		}
+		pi.it.SeekGE(Key(pi.prefixes[idx]))
+	}
+	return false
+}
+
+func (pi *prefixFilterIterator) SeekGE(id Key) {
+	pi.it.SeekGE(id)
+}
+
+func (pi *prefixFilterIterator) Value() *ValueRecord {
+	return pi.it.Value()
+}
+
+func (pi *prefixFilterIterator) Err() error {
+	return pi.it.Err()
+}
+
+func (pi *prefixFilterIterator) Close() {
+	pi.it.Close()
+}
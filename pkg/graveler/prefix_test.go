@@ -0,0 +1,139 @@
+package graveler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/batch"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/graveler/staging"
+	"github.com/treeverse/lakefs/pkg/graveler/testutil"
+	"github.com/treeverse/lakefs/pkg/ident"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"go.uber.org/ratelimit"
+)
+
+func TestPrefixFilterIterator(t *testing.T) {
+	keys := []string{"a/1", "a/2", "b/1", "b/c/1", "c", "d/1", "d/2", "e/1"}
+	records := make([]graveler.ValueRecord, 0, len(keys))
+	for _, k := range keys {
+		records = append(records, graveler.ValueRecord{Key: graveler.Key(k), Value: &graveler.Value{}})
+	}
+	tests := []struct {
+		name     string
+		prefixes []graveler.Prefix
+		expected []string
+	}{
+		{name: "single", prefixes: []graveler.Prefix{"b/"}, expected: []string{"b/1", "b/c/1"}},
+		{name: "multiple", prefixes: []graveler.Prefix{"d/", "a/"}, expected: []string{"a/1", "a/2", "d/1", "d/2"}},
+		{name: "overlapping", prefixes: []graveler.Prefix{"b/c/", "b/"}, expected: []string{"b/1", "b/c/1"}},
+		{name: "key", prefixes: []graveler.Prefix{"c", "e/1"}, expected: []string{"c", "e/1"}},
+		{name: "no match", prefixes: []graveler.Prefix{"f/", "0"}, expected: nil},
+		{name: "all", prefixes: []graveler.Prefix{""}, expected: keys},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := graveler.NewPrefixFilterIterator(testutil.NewValueIteratorFake(records), tt.prefixes)
+			defer it.Close()
+			var result []string
+			for it.Next() {
+				result = append(result, string(it.Value().Key))
+			}
+			if it.Err() != nil {
+				t.Fatalf("unexpected error: %s", it.Err())
+			}
+			if diff := deep.Equal(result, tt.expected); diff != nil {
+				t.Fatal("filtered keys not as expected:", diff)
+			}
+		})
+	}
+}
+
+func TestGraveler_CommitPrefixes(t *testing.T) {
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	storeLimited := kv.NewStoreLimiter(kvStore, ratelimit.NewUnlimited())
+	refManager := ref.NewRefManager(ref.ManagerConfig{
+		Executor:        batch.NopExecutor(),
+		KVStore:         kvStore,
+		KVStoreLimited:  storeLimited,
+		AddressProvider: ident.NewHexAddressProvider(),
+	})
+	stagingManager := staging.NewManager(ctx, kvStore, storeLimited, false, batch.NopExecutor())
+	g := graveler.NewGraveler(&testutil.CommittedFake{}, stagingManager, refManager, nil, testutil.NewProtectedBranchesManagerFake(), nil)
+	repository, err := g.CreateRepository(ctx, "repo", "", "mem://repo", "main", false)
+	require.NoError(t, err)
+	for _, key := range []string{"a/1", "b/1"} {
+		require.NoError(t, g.Set(ctx, repository, "main", graveler.Key(key), graveler.Value{Identity: []byte(key), Data: []byte(key)}))
+	}
+
+	_, err = g.Commit(ctx, repository, "main", graveler.CommitParams{
+		Committer: "committer",
+		Message:   "a",
+		Prefixes:  []graveler.Prefix{"a/"},
+	})
+	require.NoError(t, err)
+	branch, err := g.GetBranch(ctx, repository, "main")
+	require.NoError(t, err)
+	// changes outside the prefix remain staged on the sealed token
+	require.Len(t, branch.SealedTokens, 1)
+	_, err = stagingManager.Get(ctx, branch.SealedTokens[0], graveler.Key("a/1"))
+	require.ErrorIs(t, err, graveler.ErrNotFound)
+	_, err = stagingManager.Get(ctx, branch.SealedTokens[0], graveler.Key("b/1"))
+	require.NoError(t, err)
+
+	_, err = g.Commit(ctx, repository, "main", graveler.CommitParams{
+		Committer: "committer",
+		Message:   "b",
+		Prefixes:  []graveler.Prefix{"b/"},
+	})
+	require.NoError(t, err)
+	branch, err = g.GetBranch(ctx, repository, "main")
+	require.NoError(t, err)
+	// drained sealed tokens are compacted
+	require.Empty(t, branch.SealedTokens)
+}
+
+// dropByPrefixFailingStagingManager fails dropping entries by prefix
+type dropByPrefixFailingStagingManager struct {
+	graveler.StagingManager
+}
+
+func (m *dropByPrefixFailingStagingManager) DropByPrefix(context.Context, graveler.StagingToken, graveler.Key) error {
+	return errors.New("drop by prefix failed")
+}
+
+func TestGraveler_CommitPrefixesDropFailure(t *testing.T) {
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	storeLimited := kv.NewStoreLimiter(kvStore, ratelimit.NewUnlimited())
+	refManager := ref.NewRefManager(ref.ManagerConfig{
+		Executor:        batch.NopExecutor(),
+		KVStore:         kvStore,
+		KVStoreLimited:  storeLimited,
+		AddressProvider: ident.NewHexAddressProvider(),
+	})
+	stagingManager := &dropByPrefixFailingStagingManager{
+		StagingManager: staging.NewManager(ctx, kvStore, storeLimited, false, batch.NopExecutor()),
+	}
+	g := graveler.NewGraveler(&testutil.CommittedFake{}, stagingManager, refManager, nil, testutil.NewProtectedBranchesManagerFake(), nil)
+	repository, err := g.CreateRepository(ctx, "repo", "", "mem://repo", "main", false)
+	require.NoError(t, err)
+	require.NoError(t, g.Set(ctx, repository, "main", graveler.Key("a/1"), graveler.Value{Identity: []byte("a/1"), Data: []byte("a/1")}))
+
+	// the commit landed, failing to drop the committed changes does not fail it
+	commitID, err := g.Commit(ctx, repository, "main", graveler.CommitParams{
+		Committer: "committer",
+		Message:   "a",
+		Prefixes:  []graveler.Prefix{"a/"},
+	})
+	require.NoError(t, err)
+	branch, err := g.GetBranch(ctx, repository, "main")
+	require.NoError(t, err)
+	require.Equal(t, commitID, branch.CommitID)
+}
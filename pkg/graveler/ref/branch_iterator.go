@@ -47,26 +47,37 @@ func (bi *BranchSimpleIterator) Next() bool {
 	if bi.Err() != nil {
 		return false
 	}
-	if !bi.itr.Next() {
-		bi.value = nil
-		return false
-	}
-	entry := bi.itr.Entry()
-	if entry == nil {
-		bi.err = graveler.ErrInvalid
-		return false
-	}
-	value, ok := entry.Value.(*graveler.BranchData)
-	if !ok {
-		bi.err = graveler.ErrReadingFromStore
-		return false
-	}
+	for {
+		if !bi.itr.Next() {
+			bi.value = nil
+			return false
+		}
+		entry := bi.itr.Entry()
+		if entry == nil {
+			bi.err = graveler.ErrInvalid
+			return false
+		}
+		value, ok := entry.Value.(*graveler.BranchData)
+		if !ok {
+			bi.err = graveler.ErrReadingFromStore
+			return false
+		}
+		value, err := resolveBranchData(bi.ctx, bi.store, bi.repoPartition, value)
+		if errors.Is(err, kv.ErrNotFound) {
+			// deleted while claimed by a transaction
+			continue
+		}
+		if err != nil {
+			bi.err = err
+			return false
+		}
 
-	bi.value = &graveler.BranchRecord{
-		BranchID: graveler.BranchID(value.Id),
-		Branch:   branchFromProto(value),
+		bi.value = &graveler.BranchRecord{
+			BranchID: graveler.BranchID(value.Id),
+			Branch:   branchFromProto(value),
+		}
+		return true
 	}
-	return true
 }
 
 func (bi *BranchSimpleIterator) SeekGE(id graveler.BranchID) {
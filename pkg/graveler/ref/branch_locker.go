@@ -0,0 +1,134 @@
+package ref
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultBranchLockTTL is the time a branch lock is held before it is considered abandoned by its owner, and can
+	// be taken by another owner
+	DefaultBranchLockTTL = time.Minute
+	// DefaultBranchLockWait is the time to wait for a locked branch before failing with graveler.ErrLockNotAcquired
+	DefaultBranchLockWait   = 10 * time.Second
+	branchLockRetryInterval = 50 * time.Millisecond
+)
+
+// BranchLocker locks branches by records kept in the kv store, so it locks them across lakeFS instances.
+// Only operations that take the lock are excluded from each other, it does not block branch updates that do not.
+type BranchLocker struct {
+	store kv.Store
+	ttl   time.Duration
+	wait  time.Duration
+}
+
+type BranchLockerOption func(l *BranchLocker)
+
+// WithBranchLockTTL sets the time a branch lock is held before it is considered abandoned
+func WithBranchLockTTL(ttl time.Duration) BranchLockerOption {
+	return func(l *BranchLocker) {
+		l.ttl = ttl
+	}
+}
+
+// WithBranchLockWait sets the time to wait for a locked branch
+func WithBranchLockWait(wait time.Duration) BranchLockerOption {
+	return func(l *BranchLocker) {
+		l.wait = wait
+	}
+}
+
+func NewBranchLocker(store kv.Store, opts ...BranchLockerOption) *BranchLocker {
+	l := &BranchLocker{
+		store: store,
+		ttl:   DefaultBranchLockTTL,
+		wait:  DefaultBranchLockWait,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Writer calls lockedFn as is. Staging writes do not update the branch record, they are ordered with branch updates
+// by sealing staging tokens.
+func (l *BranchLocker) Writer(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, lockedFn graveler.BranchLockerFunc) (interface{}, error) {
+	return lockedFn()
+}
+
+// MetadataUpdater calls lockedFn holding the exclusive lock of the branch
+func (l *BranchLocker) MetadataUpdater(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, lockedFn graveler.BranchLockerFunc) (interface{}, error) {
+	owner := xid.New().String()
+	if err := l.lock(ctx, repository, branchID, owner); err != nil {
+		return nil, err
+	}
+	defer l.unlock(ctx, repository, branchID, owner)
+	return lockedFn()
+}
+
+func (l *BranchLocker) lock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, owner string) error {
+	partition := graveler.RepoPartition(repository)
+	key := []byte(graveler.BranchLockPath(branchID))
+	deadline := time.Now().Add(l.wait)
+	var predicate kv.Predicate
+	for {
+		data := &graveler.BranchLockData{
+			Owner:     owner,
+			ExpiresAt: timestamppb.New(time.Now().Add(l.ttl)),
+		}
+		err := kv.SetMsgIf(ctx, l.store, partition, key, data, predicate)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, kv.ErrPredicateFailed) {
+			return fmt.Errorf("lock branch %s: %w", branchID, err)
+		}
+
+		var current graveler.BranchLockData
+		predicate, err = kv.GetMsg(ctx, l.store, partition, key, &current)
+		switch {
+		case errors.Is(err, kv.ErrNotFound):
+			// released meanwhile
+			predicate = nil
+			continue
+		case err != nil:
+			return fmt.Errorf("lock branch %s: %w", branchID, err)
+		case time.Now().After(current.ExpiresAt.AsTime()):
+			// abandoned, take it over by its predicate
+			continue
+		}
+		// held by another owner, wait for it to be released
+		predicate = nil
+		if time.Now().After(deadline) {
+			return fmt.Errorf("branch %s: %w", branchID, graveler.ErrLockNotAcquired)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(branchLockRetryInterval):
+		}
+	}
+}
+
+// unlock releases the lock of the branch, unless it was taken over after it expired
+func (l *BranchLocker) unlock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, owner string) {
+	partition := graveler.RepoPartition(repository)
+	key := []byte(graveler.BranchLockPath(branchID))
+	var current graveler.BranchLockData
+	pred, err := kv.GetMsg(ctx, l.store, partition, key, &current)
+	if err == nil && current.Owner == owner {
+		// delete only the lock read above, it may be taken over once it expires
+		err = l.store.DeleteIf(ctx, []byte(partition), key, pred)
+	}
+	if err != nil && !errors.Is(err, kv.ErrNotFound) && !errors.Is(err, kv.ErrPredicateFailed) {
+		logging.FromContext(ctx).WithError(err).WithField("branch", branchID).Warn("Failed to release branch lock, it is released once it expires")
+	}
+}
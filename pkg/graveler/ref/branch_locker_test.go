@@ -0,0 +1,109 @@
+package ref_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func TestBranchLocker_MetadataUpdater(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	repository := &graveler.RepositoryRecord{RepositoryID: "repo", Repository: &graveler.Repository{InstanceUID: "uid"}}
+
+	// holdLock holds the lock of the branch until release is closed
+	holdLock := func(locker *ref.BranchLocker, branchID graveler.BranchID) (release chan struct{}, done chan error) {
+		locked := make(chan struct{})
+		release = make(chan struct{})
+		done = make(chan error, 1)
+		go func() {
+			_, err := locker.MetadataUpdater(ctx, repository, branchID, func() (interface{}, error) {
+				close(locked)
+				<-release
+				return nil, nil
+			})
+			done <- err
+		}()
+		select {
+		case <-locked:
+		case err := <-done:
+			t.Fatalf("hold lock: %s", err)
+		}
+		return release, done
+	}
+	lockNow := func(locker *ref.BranchLocker, branchID graveler.BranchID) error {
+		_, err := locker.MetadataUpdater(ctx, repository, branchID, func() (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	t.Run("exclusive", func(t *testing.T) {
+		locker := ref.NewBranchLocker(store, ref.WithBranchLockWait(100*time.Millisecond))
+		release, done := holdLock(locker, "exclusive")
+		if err := lockNow(locker, "exclusive"); !errors.Is(err, graveler.ErrLockNotAcquired) {
+			t.Fatalf("lock held branch: got error %v, expected %v", err, graveler.ErrLockNotAcquired)
+		}
+		// other branches are not locked
+		if err := lockNow(locker, "other"); err != nil {
+			t.Fatalf("lock other branch: %s", err)
+		}
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("hold lock: %s", err)
+		}
+		if err := lockNow(locker, "exclusive"); err != nil {
+			t.Fatalf("lock released branch: %s", err)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		locker := ref.NewBranchLocker(store, ref.WithBranchLockWait(5*time.Second))
+		release, done := holdLock(locker, "wait")
+		time.AfterFunc(100*time.Millisecond, func() { close(release) })
+		if err := lockNow(locker, "wait"); err != nil {
+			t.Fatalf("lock branch once released: %s", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("hold lock: %s", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		abandoned := ref.NewBranchLocker(store, ref.WithBranchLockTTL(time.Millisecond))
+		release, done := holdLock(abandoned, "expired")
+		defer func() {
+			close(release)
+			<-done
+		}()
+		locker := ref.NewBranchLocker(store, ref.WithBranchLockWait(time.Second))
+		if err := lockNow(locker, "expired"); err != nil {
+			t.Fatalf("lock branch with expired lock: %s", err)
+		}
+	})
+
+	t.Run("taken_over", func(t *testing.T) {
+		abandoned := ref.NewBranchLocker(store, ref.WithBranchLockTTL(time.Millisecond))
+		abandonedRelease, abandonedDone := holdLock(abandoned, "taken_over")
+		locker := ref.NewBranchLocker(store, ref.WithBranchLockWait(time.Second))
+		release, done := holdLock(locker, "taken_over")
+		defer func() {
+			close(release)
+			<-done
+		}()
+		// releasing the abandoned lock keeps the lock of its new owner
+		close(abandonedRelease)
+		if err := <-abandonedDone; err != nil {
+			t.Fatalf("hold abandoned lock: %s", err)
+		}
+		other := ref.NewBranchLocker(store, ref.WithBranchLockWait(100*time.Millisecond))
+		if err := lockNow(other, "taken_over"); !errors.Is(err, graveler.ErrLockNotAcquired) {
+			t.Fatalf("lock taken over branch: got error %v, expected %v", err, graveler.ErrLockNotAcquired)
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package ref
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rs/xid"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"google.golang.org/protobuf/proto"
+)
+
+// Branches are updated together by a branch transaction record, holding the updated branches. The transaction claims
+// each of its branches by a conditional update setting the branch transaction ID, and is committed by a single
+// conditional update of its status. Readers of a claimed branch read it through its transaction: as updated by a
+// committed transaction, and as is otherwise. So all the branches of a transaction are seen updated from the moment it
+// is committed.
+// Once committed, the claimed branches are set as updated by the transaction and released. Updates of a claimed
+// branch release it first, aborting the transaction in case it is still pending, so a transaction that is not
+// completed, for example by a failed lakeFS instance, does not block its branches.
+// A transaction record is deleted once all its branches are released, so a branch claimed by a transaction whose
+// record is not found was released meanwhile.
+
+// resolveBranchData returns the branch as seen by readers, resolving the branch claimed by a transaction
+func resolveBranchData(ctx context.Context, store kv.Store, repoPartition string, data *graveler.BranchData) (*graveler.BranchData, error) {
+	for data.TransactionId != "" {
+		tx := graveler.BranchTransactionData{}
+		_, err := kv.GetMsg(ctx, store, repoPartition, []byte(graveler.BranchTransactionPath(data.TransactionId)), &tx)
+		if errors.Is(err, kv.ErrNotFound) {
+			// the branch was released meanwhile, read it again
+			current := &graveler.BranchData{}
+			if _, err := kv.GetMsg(ctx, store, repoPartition, []byte(graveler.BranchPath(graveler.BranchID(data.Id))), current); err != nil {
+				return nil, err
+			}
+			if current.TransactionId == data.TransactionId {
+				// claimed by a transaction that no longer exists, the branch was not updated by it
+				tx.Status = graveler.BranchTransactionStatus_ABORTED
+				return releasedBranchData(&tx, current)
+			}
+			data = current
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get branch transaction %s: %w", data.TransactionId, err)
+		}
+		return releasedBranchData(&tx, data)
+	}
+	return data, nil
+}
+
+// releasedBranchData returns the branch claimed by the transaction as it is once released: as updated by the
+// transaction if it is committed, and as it was before the transaction otherwise
+func releasedBranchData(tx *graveler.BranchTransactionData, data *graveler.BranchData) (*graveler.BranchData, error) {
+	if tx.Status != graveler.BranchTransactionStatus_COMMITTED {
+		released := proto.Clone(data).(*graveler.BranchData)
+		released.TransactionId = ""
+		return released, nil
+	}
+	for _, branch := range tx.Branches {
+		if branch.Id == data.Id {
+			return branch, nil
+		}
+	}
+	return nil, fmt.Errorf("branch %s not in transaction %s: %w", data.Id, tx.Id, graveler.ErrInvalid)
+}
+
+// getReleasedBranch reads the branch in order to update it, releasing it first in case it is claimed by a
+// transaction. The returned predicate is of the released branch.
+func (m *Manager) getReleasedBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchData, kv.Predicate, error) {
+	for {
+		data, pred, err := m.getBranchDataWithPredicate(ctx, repository, branchID)
+		if err != nil || data.TransactionId == "" {
+			return data, pred, err
+		}
+		if err := m.releaseBranch(ctx, graveler.RepoPartition(repository), data, pred); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// releaseBranch releases the branch claimed by a transaction, aborting the transaction in case it is pending. It
+// returns without an error when the branch or the transaction change meanwhile, the caller reads the branch again.
+func (m *Manager) releaseBranch(ctx context.Context, repoPartition string, data *graveler.BranchData, pred kv.Predicate) error {
+	txKey := []byte(graveler.BranchTransactionPath(data.TransactionId))
+	tx := graveler.BranchTransactionData{}
+	txPred, err := kv.GetMsg(ctx, m.kvStore, repoPartition, txKey, &tx)
+	if errors.Is(err, kv.ErrNotFound) {
+		// the branch was released meanwhile, unless it is claimed by a transaction that no longer exists
+		current := &graveler.BranchData{}
+		pred, err = kv.GetMsg(ctx, m.kvStore, repoPartition, []byte(graveler.BranchPath(graveler.BranchID(data.Id))), current)
+		if errors.Is(err, kv.ErrNotFound) || (err == nil && current.TransactionId != data.TransactionId) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		data = current
+		tx.Status = graveler.BranchTransactionStatus_ABORTED
+	} else if err != nil {
+		return fmt.Errorf("get branch transaction %s: %w", data.TransactionId, err)
+	}
+	if tx.Status == graveler.BranchTransactionStatus_PENDING {
+		tx.Status = graveler.BranchTransactionStatus_ABORTED
+		err := kv.SetMsgIf(ctx, m.kvStore, repoPartition, txKey, &tx, txPred)
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("abort branch transaction %s: %w", data.TransactionId, err)
+		}
+	}
+	released, err := releasedBranchData(&tx, data)
+	if err != nil {
+		return err
+	}
+	err = kv.SetMsgIf(ctx, m.kvStore, repoPartition, []byte(graveler.BranchPath(graveler.BranchID(data.Id))), released, pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("release branch %s: %w", data.Id, err)
+	}
+	return nil
+}
+
+// releaseTransaction releases the branches claimed by the transaction, and deletes the transaction once none of
+// them is claimed
+func (m *Manager) releaseTransaction(ctx context.Context, repository *graveler.RepositoryRecord, txID string, branchIDs []graveler.BranchID) {
+	repoPartition := graveler.RepoPartition(repository)
+	log := logging.FromContext(ctx).WithFields(logging.Fields{
+		"repository":     repository.RepositoryID,
+		"transaction_id": txID,
+	})
+	for _, branchID := range branchIDs {
+		for {
+			data, pred, err := m.getBranchDataWithPredicate(ctx, repository, branchID)
+			if errors.Is(err, graveler.ErrBranchNotFound) || (err == nil && data.TransactionId != txID) {
+				break
+			}
+			if err == nil {
+				err = m.releaseBranch(ctx, repoPartition, data, pred)
+			}
+			if err != nil {
+				// the branch is released by its next update, keep the transaction until then
+				log.WithError(err).WithField("branch", branchID).Warn("Failed to release branch claimed by transaction")
+				return
+			}
+		}
+	}
+	if err := m.kvStore.Delete(ctx, []byte(repoPartition), []byte(graveler.BranchTransactionPath(txID))); err != nil {
+		log.WithError(err).Warn("Failed to delete branch transaction")
+	}
+}
+
+// commitTransaction commits the pending transaction. It fails with kv.ErrPredicateFailed in case the transaction
+// was aborted.
+func (m *Manager) commitTransaction(ctx context.Context, repoPartition string, txID string) error {
+	txKey := []byte(graveler.BranchTransactionPath(txID))
+	tx := graveler.BranchTransactionData{}
+	txPred, err := kv.GetMsg(ctx, m.kvStore, repoPartition, txKey, &tx)
+	if err != nil {
+		return fmt.Errorf("get branch transaction %s: %w", txID, err)
+	}
+	if tx.Status != graveler.BranchTransactionStatus_PENDING {
+		return fmt.Errorf("branch transaction %s aborted: %w", txID, kv.ErrPredicateFailed)
+	}
+	tx.Status = graveler.BranchTransactionStatus_COMMITTED
+	return kv.SetMsgIf(ctx, m.kvStore, repoPartition, txKey, &tx, txPred)
+}
+
+// isTransactionCommitted reports whether the transaction is known to be committed
+func (m *Manager) isTransactionCommitted(ctx context.Context, repoPartition string, txID string) bool {
+	tx := graveler.BranchTransactionData{}
+	_, err := kv.GetMsg(ctx, m.kvStore, repoPartition, []byte(graveler.BranchTransactionPath(txID)), &tx)
+	return err == nil && tx.Status == graveler.BranchTransactionStatus_COMMITTED
+}
+
+func (m *Manager) BranchesUpdate(ctx context.Context, repository *graveler.RepositoryRecord, record graveler.ReflogRecord, updates map[graveler.BranchID]graveler.BranchUpdateFunc) error {
+	// claim branches in a stable order
+	branchIDs := make([]graveler.BranchID, 0, len(updates))
+	for branchID := range updates {
+		branchIDs = append(branchIDs, branchID)
+	}
+	sort.Slice(branchIDs, func(i, j int) bool { return branchIDs[i] < branchIDs[j] })
+
+	type claim struct {
+		data *graveler.BranchData
+		pred kv.Predicate
+	}
+	claims := make([]claim, len(branchIDs))
+	tx := &graveler.BranchTransactionData{
+		Id:     xid.New().String(),
+		Status: graveler.BranchTransactionStatus_PENDING,
+	}
+	for i, branchID := range branchIDs {
+		data, pred, err := m.getReleasedBranch(ctx, repository, branchID)
+		if err != nil {
+			return err
+		}
+		// branches kept as is are claimed as well, so they are not updated until the transaction is committed
+		updated := data
+		newBranch, err := updates[branchID](branchFromProto(data))
+		if err != nil {
+			return err
+		}
+		if newBranch != nil {
+			updated = protoFromBranch(branchID, newBranch)
+		}
+		claims[i] = claim{data: data, pred: pred}
+		tx.Branches = append(tx.Branches, updated)
+	}
+
+	repoPartition := graveler.RepoPartition(repository)
+	err := kv.SetMsgIf(ctx, m.kvStore, repoPartition, []byte(graveler.BranchTransactionPath(tx.Id)), tx, nil)
+	if err != nil {
+		return fmt.Errorf("add branch transaction: %w", err)
+	}
+	for i, branchID := range branchIDs {
+		claimed := proto.Clone(claims[i].data).(*graveler.BranchData)
+		claimed.TransactionId = tx.Id
+		err = kv.SetMsgIf(ctx, m.kvStore, repoPartition, []byte(graveler.BranchPath(branchID)), claimed, claims[i].pred)
+		if err != nil {
+			err = fmt.Errorf("claim branch %s: %w", branchID, err)
+			break
+		}
+	}
+	if err == nil {
+		err = m.commitTransaction(ctx, repoPartition, tx.Id)
+		if err != nil && !errors.Is(err, kv.ErrPredicateFailed) && m.isTransactionCommitted(ctx, repoPartition, tx.Id) {
+			// the commit failed after it was applied
+			err = nil
+		}
+	}
+	// releasing the branches aborts the transaction unless it was committed
+	m.releaseTransaction(ctx, repository, tx.Id, branchIDs)
+	if err != nil {
+		return err
+	}
+
+	var reflogErr error
+	for i, branchID := range branchIDs {
+		err := m.addReflogEntry(ctx, repoPartition, branchID, graveler.CommitID(claims[i].data.CommitId), graveler.CommitID(tx.Branches[i].CommitId), record)
+		if reflogErr == nil {
+			reflogErr = err
+		}
+	}
+	return reflogErr
+}
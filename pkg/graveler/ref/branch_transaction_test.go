@@ -0,0 +1,240 @@
+package ref_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/batch"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/ident"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+// interceptingStore calls beforeSetIf before each conditional set, with the number of conditional sets of the key so
+// far
+type interceptingStore struct {
+	kv.Store
+	setIfs      map[string]int
+	beforeSetIf func(key string, n int)
+}
+
+func (s *interceptingStore) SetIf(ctx context.Context, partitionKey, key, value []byte, valuePredicate kv.Predicate) error {
+	s.setIfs[string(key)]++
+	if s.beforeSetIf != nil {
+		s.beforeSetIf(string(key), s.setIfs[string(key)])
+	}
+	return s.Store.SetIf(ctx, partitionKey, key, value, valuePredicate)
+}
+
+type branchesUpdateTest struct {
+	r          graveler.RefManager
+	store      *interceptingStore
+	repository *graveler.RepositoryRecord
+}
+
+func newBranchesUpdateTest(t *testing.T) *branchesUpdateTest {
+	t.Helper()
+	ctx := context.Background()
+	store := &interceptingStore{Store: kvtest.GetStore(ctx, t), setIfs: make(map[string]int)}
+	r := ref.NewRefManager(ref.ManagerConfig{
+		Executor:              batch.NopExecutor(),
+		KVStore:               store,
+		AddressProvider:       ident.NewHexAddressProvider(),
+		RepositoryCacheConfig: testRepoCacheConfig,
+		CommitCacheConfig:     testCommitCacheConfig,
+	})
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	require.NoError(t, err)
+	for _, branchID := range []graveler.BranchID{"a", "b"} {
+		require.NoError(t, r.CreateBranch(ctx, repository, branchID, graveler.Branch{CommitID: "c1", StagingToken: "st"}, graveler.ReflogRecord{}))
+	}
+	store.setIfs = make(map[string]int)
+	return &branchesUpdateTest{r: r, store: store, repository: repository}
+}
+
+// moveBoth updates a to c2 and b to c3
+func moveBoth() map[graveler.BranchID]graveler.BranchUpdateFunc {
+	moveTo := func(commitID graveler.CommitID) graveler.BranchUpdateFunc {
+		return func(branch *graveler.Branch) (*graveler.Branch, error) {
+			branch.CommitID = commitID
+			return branch, nil
+		}
+	}
+	return map[graveler.BranchID]graveler.BranchUpdateFunc{"a": moveTo("c2"), "b": moveTo("c3")}
+}
+
+func (bt *branchesUpdateTest) requireHeads(t *testing.T, a, b graveler.CommitID) {
+	t.Helper()
+	ctx := context.Background()
+	for branchID, commitID := range map[graveler.BranchID]graveler.CommitID{"a": a, "b": b} {
+		branch, err := bt.r.GetBranch(ctx, bt.repository, branchID)
+		require.NoError(t, err)
+		require.Equal(t, commitID, branch.CommitID, "head of branch %s", branchID)
+	}
+	itr, err := bt.r.ListBranches(ctx, bt.repository)
+	require.NoError(t, err)
+	defer itr.Close()
+	listed := make(map[graveler.BranchID]graveler.CommitID)
+	for itr.Next() {
+		listed[itr.Value().BranchID] = itr.Value().CommitID
+	}
+	require.NoError(t, itr.Err())
+	require.Equal(t, a, listed["a"], "listed head of branch a")
+	require.Equal(t, b, listed["b"], "listed head of branch b")
+}
+
+// requireReleased verifies that no branch is claimed and no transaction is kept
+func (bt *branchesUpdateTest) requireReleased(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	repoPartition := graveler.RepoPartition(bt.repository)
+	for _, branchID := range []graveler.BranchID{"a", "b"} {
+		data := graveler.BranchData{}
+		_, err := kv.GetMsg(ctx, bt.store, repoPartition, []byte(graveler.BranchPath(branchID)), &data)
+		require.NoError(t, err)
+		require.Empty(t, data.TransactionId, "transaction of branch %s", branchID)
+	}
+	itr, err := kv.ScanPrefix(ctx, bt.store, []byte(repoPartition), []byte(graveler.BranchTransactionPath("")), nil)
+	require.NoError(t, err)
+	defer itr.Close()
+	require.False(t, itr.Next(), "branch transaction kept")
+	require.NoError(t, itr.Err())
+}
+
+// setTransaction stores a transaction claiming branch a, as left by a transaction that was not completed
+func (bt *branchesUpdateTest) setTransaction(t *testing.T, status graveler.BranchTransactionStatus, keepRecord bool) {
+	t.Helper()
+	ctx := context.Background()
+	repoPartition := graveler.RepoPartition(bt.repository)
+	const txID = "tx1"
+	if keepRecord {
+		require.NoError(t, kv.SetMsg(ctx, bt.store, repoPartition, []byte(graveler.BranchTransactionPath(txID)), &graveler.BranchTransactionData{
+			Id:       txID,
+			Status:   status,
+			Branches: []*graveler.BranchData{{Id: "a", CommitId: "c2", StagingToken: "st"}},
+		}))
+	}
+	require.NoError(t, kv.SetMsg(ctx, bt.store, repoPartition, []byte(graveler.BranchPath("a")), &graveler.BranchData{
+		Id:            "a",
+		CommitId:      "c1",
+		StagingToken:  "st",
+		TransactionId: txID,
+	}))
+}
+
+func TestManager_BranchesUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("updated", func(t *testing.T) {
+		bt := newBranchesUpdateTest(t)
+		require.NoError(t, bt.r.BranchesUpdate(ctx, bt.repository, graveler.ReflogRecord{Operation: "transaction"}, moveBoth()))
+		bt.requireHeads(t, "c2", "c3")
+		bt.requireReleased(t)
+		for branchID, commitID := range map[graveler.BranchID]graveler.CommitID{"a": "c2", "b": "c3"} {
+			itr, err := bt.r.ListReflog(ctx, bt.repository, branchID)
+			require.NoError(t, err)
+			require.True(t, itr.Next())
+			require.Equal(t, commitID, itr.Value().NewCommitID)
+			require.Equal(t, "transaction", itr.Value().Operation)
+			itr.Close()
+		}
+	})
+
+	t.Run("failed_validation", func(t *testing.T) {
+		bt := newBranchesUpdateTest(t)
+		updates := moveBoth()
+		updates["b"] = func(*graveler.Branch) (*graveler.Branch, error) {
+			return nil, graveler.ErrPreconditionFailed
+		}
+		err := bt.r.BranchesUpdate(ctx, bt.repository, graveler.ReflogRecord{}, updates)
+		require.ErrorIs(t, err, graveler.ErrPreconditionFailed)
+		bt.requireHeads(t, "c1", "c1")
+		bt.requireReleased(t)
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		bt := newBranchesUpdateTest(t)
+		checked := 0
+		bt.store.beforeSetIf = func(key string, n int) {
+			switch {
+			case key == graveler.BranchPath("b") && n == 1:
+				// a is claimed, the transaction is not committed yet
+				bt.requireHeads(t, "c1", "c1")
+				checked++
+			case key == graveler.BranchPath("a") && n == 2:
+				// the transaction is committed, none of the branches is released yet
+				bt.requireHeads(t, "c2", "c3")
+				checked++
+			}
+		}
+		require.NoError(t, bt.r.BranchesUpdate(ctx, bt.repository, graveler.ReflogRecord{}, moveBoth()))
+		require.Equal(t, 2, checked)
+		bt.requireHeads(t, "c2", "c3")
+		bt.requireReleased(t)
+	})
+
+	t.Run("concurrent_update", func(t *testing.T) {
+		bt := newBranchesUpdateTest(t)
+		moved := false
+		bt.store.beforeSetIf = func(key string, n int) {
+			if key != graveler.BranchPath("b") || moved {
+				return
+			}
+			// a is claimed by the transaction when it is updated, before b is claimed
+			moved = true
+			require.NoError(t, bt.r.BranchUpdate(ctx, bt.repository, "a", graveler.ReflogRecord{}, func(branch *graveler.Branch) (*graveler.Branch, error) {
+				require.Equal(t, graveler.CommitID("c1"), branch.CommitID)
+				branch.CommitID = "c4"
+				return branch, nil
+			}))
+		}
+		err := bt.r.BranchesUpdate(ctx, bt.repository, graveler.ReflogRecord{}, moveBoth())
+		require.ErrorIs(t, err, kv.ErrPredicateFailed)
+		bt.requireHeads(t, "c4", "c1")
+		bt.requireReleased(t)
+	})
+
+	t.Run("pending_transaction", func(t *testing.T) {
+		bt := newBranchesUpdateTest(t)
+		bt.setTransaction(t, graveler.BranchTransactionStatus_PENDING, true)
+		bt.requireHeads(t, "c1", "c1")
+		// an update aborts the pending transaction
+		require.NoError(t, bt.r.BranchesUpdate(ctx, bt.repository, graveler.ReflogRecord{}, moveBoth()))
+		bt.requireHeads(t, "c2", "c3")
+		tx := graveler.BranchTransactionData{}
+		_, err := kv.GetMsg(ctx, bt.store, graveler.RepoPartition(bt.repository), []byte(graveler.BranchTransactionPath("tx1")), &tx)
+		require.NoError(t, err)
+		require.Equal(t, graveler.BranchTransactionStatus_ABORTED, tx.Status)
+	})
+
+	t.Run("committed_transaction", func(t *testing.T) {
+		bt := newBranchesUpdateTest(t)
+		bt.setTransaction(t, graveler.BranchTransactionStatus_COMMITTED, true)
+		bt.requireHeads(t, "c2", "c1")
+		// an update releases the branch as updated by the committed transaction
+		require.NoError(t, bt.r.BranchUpdate(ctx, bt.repository, "a", graveler.ReflogRecord{}, func(branch *graveler.Branch) (*graveler.Branch, error) {
+			require.Equal(t, graveler.CommitID("c2"), branch.CommitID)
+			return nil, nil
+		}))
+		require.NoError(t, bt.r.DeleteBranch(ctx, bt.repository, "b", graveler.ReflogRecord{}))
+		_, err := bt.r.GetBranch(ctx, bt.repository, "b")
+		require.ErrorIs(t, err, graveler.ErrBranchNotFound)
+	})
+
+	t.Run("missing_transaction", func(t *testing.T) {
+		bt := newBranchesUpdateTest(t)
+		bt.setTransaction(t, graveler.BranchTransactionStatus_COMMITTED, false)
+		bt.requireHeads(t, "c1", "c1")
+		require.NoError(t, bt.r.BranchesUpdate(ctx, bt.repository, graveler.ReflogRecord{}, moveBoth()))
+		bt.requireHeads(t, "c2", "c3")
+		bt.requireReleased(t)
+	})
+}
@@ -19,6 +19,7 @@ type CommitIterator struct {
 	visit       map[graveler.CommitID]struct{}
 	state       commitIteratorState
 	since       *time.Time
+	until       *time.Time
 	err         error
 }
 
@@ -68,6 +69,7 @@ type CommitIteratorConfig struct {
 	firstParent bool
 	manager     graveler.RefManager
 	since       *time.Time
+	until       *time.Time
 }
 
 // NewCommitIterator returns an iterator over all commits in the given repository.
@@ -82,6 +84,7 @@ func NewCommitIterator(ctx context.Context, config *CommitIteratorConfig) *Commi
 		manager:     config.manager,
 		firstParent: config.firstParent,
 		since:       config.since,
+		until:       config.until,
 	}
 }
 
@@ -116,16 +119,27 @@ func (ci *CommitIterator) Next() bool {
 		}
 	}
 
-	if ci.queue.Len() == 0 {
-		ci.value = nil
-		ci.state = commitIteratorStateDone
-		return false
+	for ci.queue.Len() > 0 {
+		// as long as we have something in the queue we will
+		// set it as the current value and push the current commits parents to the queue
+		ci.value = heap.Pop(&ci.queue).(*graveler.CommitRecord)
+		if err := ci.pushParents(ci.value); err != nil {
+			ci.value = nil
+			ci.err = err
+			return false
+		}
+		// skip commits that are newer than until time, their parents are still walked
+		if ci.until == nil || !ci.value.Commit.CreationDate.After(*ci.until) {
+			return true
+		}
 	}
+	ci.value = nil
+	ci.state = commitIteratorStateDone
+	return false
+}
 
-	// as long as we have something in the queue we will
-	// set it as the current value and push the current commits parents to the queue
-	ci.value = heap.Pop(&ci.queue).(*graveler.CommitRecord)
-	parents := ci.value.Parents
+func (ci *CommitIterator) pushParents(commit *graveler.CommitRecord) error {
+	parents := commit.Parents
 	if ci.firstParent && len(parents) > 1 {
 		parents = parents[:1]
 	}
@@ -137,9 +151,7 @@ func (ci *CommitIterator) Next() bool {
 
 		rec, err := ci.getCommitRecord(p)
 		if err != nil {
-			ci.value = nil
-			ci.err = err
-			return false
+			return err
 		}
 		ci.visit[rec.CommitID] = struct{}{}
 
@@ -150,7 +162,7 @@ func (ci *CommitIterator) Next() bool {
 
 		heap.Push(&ci.queue, rec)
 	}
-	return true
+	return nil
 }
 
 // SeekGE skip under the point of 'id' commit ID based on a new
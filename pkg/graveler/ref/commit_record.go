@@ -21,6 +21,7 @@ func CommitDataToCommitRecord(c *graveler.CommitData) *graveler.CommitRecord {
 			Parents:      parents,
 			Version:      graveler.CommitVersion(c.Version),
 			Generation:   graveler.CommitGeneration(c.Generation),
+			Signature:    c.Signature,
 		},
 	}
 }
@@ -44,6 +44,22 @@ func testRefManager(t testing.TB) (graveler.RefManager, kv.Store) {
 	return ref.NewRefManager(cfg), kvStore
 }
 
+func testRefManagerWithReflogRetention(t testing.TB, reflogRetention time.Duration) graveler.RefManager {
+	t.Helper()
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	cfg := ref.ManagerConfig{
+		Executor:              batch.NopExecutor(),
+		KVStore:               kvStore,
+		KVStoreLimited:        kv.NewStoreLimiter(kvStore, ratelimit.NewUnlimited()),
+		AddressProvider:       ident.NewHexAddressProvider(),
+		RepositoryCacheConfig: testRepoCacheConfig,
+		CommitCacheConfig:     testCommitCacheConfig,
+		ReflogRetention:       reflogRetention,
+	}
+	return ref.NewRefManager(cfg)
+}
+
 func testRefManagerWithAddressProvider(t testing.TB, addressProvider ident.AddressProvider) (graveler.RefManager, kv.Store) {
 	t.Helper()
 	ctx := context.Background()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path"
 	"strings"
 	"time"
@@ -48,6 +49,7 @@ type Manager struct {
 	batchExecutor   batch.Batcher
 	repoCache       cache.Cache
 	commitCache     cache.Cache
+	reflogRetention time.Duration
 }
 
 func branchFromProto(pb *graveler.BranchData) *graveler.Branch {
@@ -84,6 +86,8 @@ type ManagerConfig struct {
 	AddressProvider       ident.AddressProvider
 	RepositoryCacheConfig CacheConfig
 	CommitCacheConfig     CacheConfig
+	// ReflogRetention is the age of branch reflog entries pruned when the reflog is listed, 0 keeps them
+	ReflogRetention time.Duration
 }
 
 func NewRefManager(cfg ManagerConfig) *Manager {
@@ -94,6 +98,7 @@ func NewRefManager(cfg ManagerConfig) *Manager {
 		batchExecutor:   cfg.Executor,
 		repoCache:       newCache(cfg.RepositoryCacheConfig),
 		commitCache:     newCache(cfg.CommitCacheConfig),
+		reflogRetention: cfg.ReflogRetention,
 	}
 }
 
@@ -180,7 +185,7 @@ func (m *Manager) CreateRepository(ctx context.Context, repositoryID graveler.Re
 		StagingToken: graveler.GenerateStagingToken(repositoryID, repository.DefaultBranchID),
 		SealedTokens: nil,
 	}
-	err = m.createBranch(ctx, graveler.RepoPartition(repo), repository.DefaultBranchID, branch)
+	err = m.createBranch(ctx, graveler.RepoPartition(repo), repository.DefaultBranchID, branch, graveler.ReflogRecord{Operation: "create_repository"})
 	if err != nil {
 		return nil, err
 	}
@@ -210,11 +215,12 @@ func (m *Manager) deleteRepositoryBranches(ctx context.Context, repository *grav
 		return err
 	}
 	defer itr.Close()
+	repoPartition := graveler.RepoPartition(repository)
 	var wg multierror.Group
 	for itr.Next() {
 		b := itr.Value()
 		wg.Go(func() error {
-			return m.DeleteBranch(ctx, repository, b.BranchID)
+			return m.kvStore.Delete(ctx, []byte(repoPartition), []byte(graveler.BranchPath(b.BranchID)))
 		})
 	}
 	return wg.Wait().ErrorOrNil()
@@ -252,6 +258,22 @@ func (m *Manager) deleteRepositoryCommits(ctx context.Context, repository *grave
 	return wg.Wait().ErrorOrNil()
 }
 
+// deleteRepositoryPrefix deletes the keys of the repository under prefix
+func (m *Manager) deleteRepositoryPrefix(ctx context.Context, repository *graveler.RepositoryRecord, prefix string) error {
+	repoPartition := []byte(graveler.RepoPartition(repository))
+	itr, err := kv.ScanPrefix(ctx, m.kvStore, repoPartition, []byte(prefix), nil)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for itr.Next() {
+		if err := m.kvStore.Delete(ctx, repoPartition, itr.Entry().Key); err != nil {
+			return err
+		}
+	}
+	return itr.Err()
+}
+
 func (m *Manager) deleteRepositoryMetadata(ctx context.Context, repository *graveler.RepositoryRecord) error {
 	return m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(graveler.RepoMetadataPath()))
 }
@@ -271,6 +293,12 @@ func (m *Manager) deleteRepository(ctx context.Context, repo *graveler.Repositor
 	wg.Go(func() error {
 		return m.deleteRepositoryMetadata(ctx, repo)
 	})
+	wg.Go(func() error {
+		return m.deleteRepositoryPrefix(ctx, repo, graveler.ReflogPath("", ""))
+	})
+	wg.Go(func() error {
+		return m.deleteRepositoryPrefix(ctx, repo, graveler.BranchTransactionPath(""))
+	})
 
 	if err := wg.Wait().ErrorOrNil(); err != nil {
 		return err
@@ -357,10 +385,11 @@ func (m *Manager) ResolveRawRef(ctx context.Context, repository *graveler.Reposi
 	return ResolveRawRef(ctx, m, m.addressProvider, repository, raw)
 }
 
-func (m *Manager) getBranchWithPredicate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.Branch, kv.Predicate, error) {
+// getBranchDataWithPredicate reads the branch as stored, a branch claimed by a transaction is returned claimed
+func (m *Manager) getBranchDataWithPredicate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchData, kv.Predicate, error) {
 	key := fmt.Sprintf("GetBranch:%s:%s", repository.RepositoryID, branchID)
 	type branchPred struct {
-		*graveler.Branch
+		*graveler.BranchData
 		kv.Predicate
 	}
 	result, err := m.batchExecutor.BatchFor(ctx, key, MaxBatchDelay, batch.ExecuterFunc(func() (interface{}, error) {
@@ -370,7 +399,7 @@ func (m *Manager) getBranchWithPredicate(ctx context.Context, repository *gravel
 		if err != nil {
 			return nil, err
 		}
-		return &branchPred{Branch: branchFromProto(&data), Predicate: pred}, nil
+		return &branchPred{BranchData: &data, Predicate: pred}, nil
 	}))
 	if errors.Is(err, kv.ErrNotFound) {
 		err = graveler.ErrBranchNotFound
@@ -379,49 +408,166 @@ func (m *Manager) getBranchWithPredicate(ctx context.Context, repository *gravel
 		return nil, nil, err
 	}
 	branchWithPred := result.(*branchPred)
-	return branchWithPred.Branch, branchWithPred.Predicate, nil
+	return branchWithPred.BranchData, branchWithPred.Predicate, nil
 }
 
 func (m *Manager) GetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.Branch, error) {
-	branch, _, err := m.getBranchWithPredicate(ctx, repository, branchID)
-	return branch, err
+	data, _, err := m.getBranchDataWithPredicate(ctx, repository, branchID)
+	if err != nil {
+		return nil, err
+	}
+	data, err = resolveBranchData(ctx, m.kvStore, graveler.RepoPartition(repository), data)
+	if errors.Is(err, kv.ErrNotFound) {
+		err = graveler.ErrBranchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return branchFromProto(data), nil
 }
 
-func (m *Manager) createBranch(ctx context.Context, repositoryPartition string, branchID graveler.BranchID, branch graveler.Branch) error {
+func (m *Manager) createBranch(ctx context.Context, repositoryPartition string, branchID graveler.BranchID, branch graveler.Branch, record graveler.ReflogRecord) error {
 	err := kv.SetMsgIf(ctx, m.kvStore, repositoryPartition, []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, &branch), nil)
 	if errors.Is(err, kv.ErrPredicateFailed) {
 		err = graveler.ErrBranchExists
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return m.addReflogEntry(ctx, repositoryPartition, branchID, "", branch.CommitID, record)
 }
 
-func (m *Manager) CreateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
-	return m.createBranch(ctx, graveler.RepoPartition(repository), branchID, branch)
+func (m *Manager) CreateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch, record graveler.ReflogRecord) error {
+	return m.createBranch(ctx, graveler.RepoPartition(repository), branchID, branch, record)
 }
 
-func (m *Manager) SetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
-	return kv.SetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, &branch))
+// SetBranch overwrites the branch without reading it, so its reflog entry records no previous head. It is used to
+// load the branches of a restored repository.
+func (m *Manager) SetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch, record graveler.ReflogRecord) error {
+	repoPartition := graveler.RepoPartition(repository)
+	err := kv.SetMsg(ctx, m.kvStore, repoPartition, []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, &branch))
+	if err != nil {
+		return err
+	}
+	return m.addReflogEntry(ctx, repoPartition, branchID, "", branch.CommitID, record)
 }
 
-func (m *Manager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, f graveler.BranchUpdateFunc) error {
-	b, pred, err := m.getBranchWithPredicate(ctx, repository, branchID)
+func (m *Manager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, record graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
+	data, pred, err := m.getReleasedBranch(ctx, repository, branchID)
 	if err != nil {
 		return err
 	}
+	b := branchFromProto(data)
+	// keep the current head, f may update the branch in place
+	oldCommitID := b.CommitID
 	newBranch, err := f(b)
 	// return on error or nothing to update
 	if err != nil || newBranch == nil {
 		return err
 	}
-	return kv.SetMsgIf(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, newBranch), pred)
+	repoPartition := graveler.RepoPartition(repository)
+	err = kv.SetMsgIf(ctx, m.kvStore, repoPartition, []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, newBranch), pred)
+	if err != nil {
+		return err
+	}
+	return m.addReflogEntry(ctx, repoPartition, branchID, oldCommitID, newBranch.CommitID, record)
 }
 
-func (m *Manager) DeleteBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
-	_, err := m.GetBranch(ctx, repository, branchID)
+func (m *Manager) DeleteBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, record graveler.ReflogRecord) error {
+	repoPartition := graveler.RepoPartition(repository)
+	for {
+		data, pred, err := m.getReleasedBranch(ctx, repository, branchID)
+		if err != nil {
+			return err
+		}
+		// delete the branch only once it is released, a transaction may claim it meanwhile
+		err = m.kvStore.DeleteIf(ctx, []byte(repoPartition), []byte(graveler.BranchPath(branchID)), pred)
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return m.addReflogEntry(ctx, repoPartition, branchID, graveler.CommitID(data.CommitId), "", record)
+	}
+}
+
+// addReflogEntry records a movement of the branch head from oldCommitID to newCommitID, if there is one, in the
+// branch reflog. It is called once the branch is updated, so only movements that happened are recorded. The KV store
+// cannot write the branch and its entry together, so failing to record the entry is returned as the error of the
+// update although the branch has moved.
+func (m *Manager) addReflogEntry(ctx context.Context, repoPartition string, branchID graveler.BranchID, oldCommitID, newCommitID graveler.CommitID, record graveler.ReflogRecord) error {
+	if oldCommitID == newCommitID {
+		return nil
+	}
+	now := time.Now()
+	entry := &graveler.ReflogEntry{
+		ID:           reflogEntryID(now),
+		BranchID:     branchID,
+		OldCommitID:  oldCommitID,
+		NewCommitID:  newCommitID,
+		Operation:    record.Operation,
+		User:         record.User,
+		CreationDate: now,
+	}
+	err := kv.SetMsg(ctx, m.kvStore, repoPartition, []byte(graveler.ReflogPath(branchID, entry.ID)), graveler.ProtoFromReflogEntry(entry))
+	if err != nil {
+		return fmt.Errorf("branch %s moved from %s to %s, add reflog entry: %w", branchID, oldCommitID, newCommitID, err)
+	}
+	return nil
+}
+
+// reflogEntryTime returns the reversed time t, the prefix of the IDs of the reflog entries created at t so entries
+// sort newest first
+func reflogEntryTime(t time.Time) string {
+	return fmt.Sprintf("%016x", math.MaxInt64-t.UnixNano())
+}
+
+// reflogEntryID returns a unique ID of a reflog entry created at t. Entries created at the same time, possibly by
+// different lakeFS instances, are told apart by a globally unique suffix.
+func reflogEntryID(t time.Time) string {
+	return reflogEntryTime(t) + xid.New().String()
+}
+
+// pruneReflog deletes the reflog entries of the branch older than the reflog retention. Entries sort newest first, so
+// the scan starts at the first expired entry and usually finds none.
+func (m *Manager) pruneReflog(ctx context.Context, repoPartition string, branchID graveler.BranchID, now time.Time) error {
+	if m.reflogRetention <= 0 {
+		return nil
+	}
+	from := []byte(graveler.ReflogPath(branchID, reflogEntryTime(now.Add(-m.reflogRetention))))
+	itr, err := kv.ScanPrefix(ctx, m.kvStore, []byte(repoPartition), []byte(graveler.ReflogPath(branchID, "")), from)
 	if err != nil {
 		return err
 	}
-	return m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(graveler.BranchPath(branchID)))
+	defer itr.Close()
+	for itr.Next() {
+		if err := m.kvStore.Delete(ctx, []byte(repoPartition), itr.Entry().Key); err != nil {
+			return err
+		}
+	}
+	return itr.Err()
+}
+
+// ListReflog lists the reflog of the branch, pruning its expired entries first. Branch updates do not prune the
+// reflog, it is pruned lazily as it is read.
+func (m *Manager) ListReflog(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (graveler.ReflogIterator, error) {
+	if err := m.pruneReflog(ctx, graveler.RepoPartition(repository), branchID, time.Now()); err != nil {
+		return nil, fmt.Errorf("prune branch reflog: %w", err)
+	}
+	return NewReflogIterator(ctx, m.kvStore, repository, branchID)
+}
+
+func (m *Manager) GetReflogEntry(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, entryID string) (*graveler.ReflogEntry, error) {
+	data := graveler.ReflogEntryData{}
+	_, err := kv.GetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.ReflogPath(branchID, entryID)), &data)
+	if errors.Is(err, kv.ErrNotFound) {
+		err = graveler.ErrReflogEntryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return graveler.ReflogEntryFromProto(&data), nil
 }
 
 func (m *Manager) ListBranches(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.BranchIterator, error) {
@@ -598,12 +744,13 @@ func (m *Manager) FindMergeBase(ctx context.Context, repository *graveler.Reposi
 	return FindMergeBase(ctx, m, repository, commitIDs[0], commitIDs[1])
 }
 
-func (m *Manager) Log(ctx context.Context, repository *graveler.RepositoryRecord, from graveler.CommitID, firstParent bool, since *time.Time) (graveler.CommitIterator, error) {
+func (m *Manager) Log(ctx context.Context, repository *graveler.RepositoryRecord, from graveler.CommitID, firstParent bool, since, until *time.Time) (graveler.CommitIterator, error) {
 	return NewCommitIterator(ctx, &CommitIteratorConfig{
 		repository:  repository,
 		start:       from,
 		firstParent: firstParent,
 		since:       since,
+		until:       until,
 		manager:     m,
 	}), nil
 }
@@ -247,7 +247,7 @@ func TestManager_DeleteRepository(t *testing.T) {
 
 		// Create repository entities and ensure their deletion afterwards
 		testutil.Must(t, r.CreateTag(ctx, repository, "v1.0", "c1"))
-		testutil.Must(t, r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c1", StagingToken: "s1"}))
+		testutil.Must(t, r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c1", StagingToken: "s1"}, graveler.ReflogRecord{}))
 		c := graveler.Commit{
 			Committer:    "user1",
 			Message:      "message1",
@@ -340,7 +340,7 @@ func TestManager_CreateBranch(t *testing.T) {
 	})
 	testutil.Must(t, err)
 
-	err = r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c1", StagingToken: "s1"})
+	err = r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c1", StagingToken: "s1"}, graveler.ReflogRecord{})
 	testutil.MustDo(t, "create branch f1", err)
 
 	br, err := r.GetBranch(ctx, repository, "f1")
@@ -353,15 +353,15 @@ func TestManager_CreateBranch(t *testing.T) {
 	}
 
 	// check we can't create existing
-	err = r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c2", StagingToken: "s2"})
+	err = r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c2", StagingToken: "s2"}, graveler.ReflogRecord{})
 	if !errors.Is(err, graveler.ErrBranchExists) {
 		t.Fatalf("CreateBranch() err = %s, expected already exists", err)
 	}
 	// overwrite by delete and create
-	err = r.DeleteBranch(ctx, repository, "f1")
+	err = r.DeleteBranch(ctx, repository, "f1", graveler.ReflogRecord{})
 	testutil.MustDo(t, "delete branch f1", err)
 
-	err = r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c2", StagingToken: "s2"})
+	err = r.CreateBranch(ctx, repository, "f1", graveler.Branch{CommitID: "c2", StagingToken: "s2"}, graveler.ReflogRecord{})
 	testutil.MustDo(t, "create branch f1", err)
 
 	br, err = r.GetBranch(ctx, repository, "f1")
@@ -386,7 +386,7 @@ func TestManager_SetBranch(t *testing.T) {
 
 	testutil.Must(t, r.SetBranch(context.Background(), repository, "branch2", graveler.Branch{
 		CommitID: "c2",
-	}))
+	}, graveler.ReflogRecord{}))
 
 	b, err := r.GetBranch(context.Background(), repository, "branch2")
 	if err != nil {
@@ -400,7 +400,7 @@ func TestManager_SetBranch(t *testing.T) {
 	// overwrite
 	testutil.Must(t, r.SetBranch(context.Background(), repository, "branch2", graveler.Branch{
 		CommitID: "c3",
-	}))
+	}, graveler.ReflogRecord{}))
 
 	b, err = r.GetBranch(context.Background(), repository, "branch2")
 	if err != nil {
@@ -451,7 +451,7 @@ func TestManager_BranchUpdate(t *testing.T) {
 				b := graveler.Branch{
 					CommitID: "Another commit during validation",
 				}
-				_ = r.SetBranch(ctx, repository, branchID, b)
+				_ = r.SetBranch(ctx, repository, branchID, b, graveler.ReflogRecord{})
 				return &b, nil
 			},
 			err:            kv.ErrPredicateFailed,
@@ -470,9 +470,9 @@ func TestManager_BranchUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			testutil.Must(t, r.SetBranch(context.Background(), repository, branchID, graveler.Branch{
 				CommitID: commitID1,
-			}))
+			}, graveler.ReflogRecord{}))
 
-			err := r.BranchUpdate(ctx, repository, branchID, tt.f)
+			err := r.BranchUpdate(ctx, repository, branchID, graveler.ReflogRecord{}, tt.f)
 			require.ErrorIs(t, err, tt.err)
 
 			b, err := r.GetBranch(context.Background(), repository, branchID)
@@ -494,9 +494,9 @@ func TestManager_DeleteBranch(t *testing.T) {
 
 	testutil.Must(t, r.SetBranch(ctx, repository, "branch2", graveler.Branch{
 		CommitID: "c2",
-	}))
+	}, graveler.ReflogRecord{}))
 
-	testutil.Must(t, r.DeleteBranch(ctx, repository, "branch2"))
+	testutil.Must(t, r.DeleteBranch(ctx, repository, "branch2", graveler.ReflogRecord{}))
 
 	_, err = r.GetBranch(ctx, repository, "branch2")
 	if !errors.Is(err, graveler.ErrBranchNotFound) {
@@ -504,6 +504,164 @@ func TestManager_DeleteBranch(t *testing.T) {
 	}
 }
 
+func TestManager_Reflog(t *testing.T) {
+	r, _ := testRefManager(t)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+	main, err := r.GetBranch(ctx, repository, "main")
+	testutil.Must(t, err)
+
+	// SetBranch does not read the branch, it records no previous head
+	testutil.Must(t, r.SetBranch(ctx, repository, "main", graveler.Branch{CommitID: "c2"}, graveler.ReflogRecord{Operation: "load_branches", User: "user1"}))
+	testutil.Must(t, r.BranchUpdate(ctx, repository, "main", graveler.ReflogRecord{Operation: "update_branch", User: "user1"}, func(b *graveler.Branch) (*graveler.Branch, error) {
+		b.CommitID = "c3"
+		return b, nil
+	}))
+	// updates that keep the branch head are not recorded
+	testutil.Must(t, r.BranchUpdate(ctx, repository, "main", graveler.ReflogRecord{Operation: "reset", User: "user1"}, func(b *graveler.Branch) (*graveler.Branch, error) {
+		b.StagingToken = "token"
+		return b, nil
+	}))
+	// failed updates are not recorded
+	err = r.CreateBranch(ctx, repository, "main", graveler.Branch{CommitID: "c4"}, graveler.ReflogRecord{Operation: "create_branch", User: "user1"})
+	if !errors.Is(err, graveler.ErrBranchExists) {
+		t.Fatalf("CreateBranch() err=%v, expected=%s", err, graveler.ErrBranchExists)
+	}
+	err = r.BranchUpdate(ctx, repository, "main", graveler.ReflogRecord{Operation: "commit", User: "user1"}, func(b *graveler.Branch) (*graveler.Branch, error) {
+		b.CommitID = "c5"
+		return b, r.SetBranch(ctx, repository, "main", *b, graveler.ReflogRecord{})
+	})
+	if !errors.Is(err, kv.ErrPredicateFailed) {
+		t.Fatalf("BranchUpdate() err=%v, expected=%s", err, kv.ErrPredicateFailed)
+	}
+	testutil.Must(t, r.DeleteBranch(ctx, repository, "main", graveler.ReflogRecord{Operation: "delete_branch", User: "user2"}))
+
+	it, err := r.ListReflog(ctx, repository, "main")
+	testutil.Must(t, err)
+	defer it.Close()
+	var entries []*graveler.ReflogEntry
+	for it.Next() {
+		entries = append(entries, it.Value())
+	}
+	testutil.Must(t, it.Err())
+
+	type reflogMove struct {
+		Old, New  graveler.CommitID
+		Operation string
+		User      string
+	}
+	moves := make([]reflogMove, 0, len(entries))
+	for _, e := range entries {
+		moves = append(moves, reflogMove{Old: e.OldCommitID, New: e.NewCommitID, Operation: e.Operation, User: e.User})
+	}
+	expected := []reflogMove{
+		{Old: "c5", New: "", Operation: "delete_branch", User: "user2"},
+		{Old: "", New: "c5"},
+		{Old: "c2", New: "c3", Operation: "update_branch", User: "user1"},
+		{Old: "", New: "c2", Operation: "load_branches", User: "user1"},
+		{Old: "", New: main.CommitID, Operation: "create_repository"},
+	}
+	if diff := deep.Equal(moves, expected); diff != nil {
+		t.Fatal("Reflog entries diff:", diff)
+	}
+
+	entry, err := r.GetReflogEntry(ctx, repository, "main", entries[1].ID)
+	testutil.Must(t, err)
+	if diff := deep.Equal(entry, entries[1]); diff != nil {
+		t.Fatal("Reflog entry diff:", diff)
+	}
+	_, err = r.GetReflogEntry(ctx, repository, "main", "missing")
+	if !errors.Is(err, graveler.ErrReflogEntryNotFound) {
+		t.Fatalf("GetReflogEntry() err=%v, expected=%s", err, graveler.ErrReflogEntryNotFound)
+	}
+
+	it.SeekGE(entries[2].ID)
+	if !it.Next() || it.Value().ID != entries[2].ID {
+		t.Fatalf("SeekGE(%s) expected to find the entry, got %v", entries[2].ID, it.Value())
+	}
+}
+
+// failingReflogStore fails writing reflog entries
+type failingReflogStore struct {
+	kv.Store
+}
+
+var errReflogWrite = errors.New("reflog write failed")
+
+func (s *failingReflogStore) Set(ctx context.Context, partitionKey, key, value []byte) error {
+	if strings.HasPrefix(string(key), graveler.ReflogPath("", "")) {
+		return errReflogWrite
+	}
+	return s.Store.Set(ctx, partitionKey, key, value)
+}
+
+func TestManager_ReflogEntryFailure(t *testing.T) {
+	r, kvStore := testRefManager(t)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	// a reflog entry that is not recorded fails the update, although the branch has moved
+	r = ref.NewRefManager(ref.ManagerConfig{
+		Executor:              batch.NopExecutor(),
+		KVStore:               &failingReflogStore{Store: kvStore},
+		AddressProvider:       ident.NewHexAddressProvider(),
+		RepositoryCacheConfig: testRepoCacheConfig,
+		CommitCacheConfig:     testCommitCacheConfig,
+	})
+	err = r.BranchUpdate(ctx, repository, "main", graveler.ReflogRecord{Operation: "commit"}, func(b *graveler.Branch) (*graveler.Branch, error) {
+		b.CommitID = "c2"
+		return b, nil
+	})
+	if !errors.Is(err, errReflogWrite) {
+		t.Fatalf("BranchUpdate() err=%v, expected=%s", err, errReflogWrite)
+	}
+	branch, err := r.GetBranch(ctx, repository, "main")
+	testutil.Must(t, err)
+	if branch.CommitID != "c2" {
+		t.Fatalf("branch commit ID=%s, expected c2", branch.CommitID)
+	}
+}
+
+func TestManager_ReflogRetention(t *testing.T) {
+	const retention = 100 * time.Millisecond
+	r := testRefManagerWithReflogRetention(t, retention)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+	for _, commitID := range []graveler.CommitID{"c1", "c2"} {
+		testutil.Must(t, r.SetBranch(ctx, repository, "main", graveler.Branch{CommitID: commitID}, graveler.ReflogRecord{}))
+	}
+	time.Sleep(2 * retention)
+	// listing the reflog prunes the expired entries
+	testutil.Must(t, r.SetBranch(ctx, repository, "main", graveler.Branch{CommitID: "c3"}, graveler.ReflogRecord{}))
+
+	it, err := r.ListReflog(ctx, repository, "main")
+	testutil.Must(t, err)
+	defer it.Close()
+	var newCommitIDs []graveler.CommitID
+	for it.Next() {
+		newCommitIDs = append(newCommitIDs, it.Value().NewCommitID)
+	}
+	testutil.Must(t, it.Err())
+	if diff := deep.Equal(newCommitIDs, []graveler.CommitID{"c3"}); diff != nil {
+		t.Fatal("Reflog entries diff:", diff)
+	}
+}
+
 func TestManager_ListBranches(t *testing.T) {
 	r, _ := testRefManager(t)
 	repository, err := r.CreateRepository(context.Background(), "repo1", graveler.Repository{
@@ -516,7 +674,7 @@ func TestManager_ListBranches(t *testing.T) {
 	for _, b := range []graveler.BranchID{"a", "aa", "c", "b", "z", "f"} {
 		testutil.Must(t, r.SetBranch(context.Background(), repository, b, graveler.Branch{
 			CommitID: "c2",
-		}))
+		}, graveler.ReflogRecord{}))
 	}
 
 	iter, err := r.ListBranches(context.Background(), repository)
@@ -748,7 +906,7 @@ func TestManager_Log(t *testing.T) {
 		ts = ts.Add(time.Second)
 	}
 
-	iter, err := r.Log(ctx, repository, previous, false, nil)
+	iter, err := r.Log(ctx, repository, previous, false, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -814,6 +972,7 @@ func TestManager_LogGraph(t *testing.T) {
 		seek        string
 		start       string
 		since       time.Time
+		until       time.Time
 		expected    []string
 	}{
 		/*
@@ -850,6 +1009,23 @@ func TestManager_LogGraph(t *testing.T) {
 			since:    time.Date(2020, time.December, 1, 15, 5, 0, 0, time.UTC),
 			expected: []string{"c8", "c7", "c6", "c5"},
 		},
+		"until": {
+			start:    "c8",
+			until:    time.Date(2020, time.December, 1, 15, 6, 0, 0, time.UTC),
+			expected: []string{"c6", "c5", "c4", "c3", "c2", "c1"},
+		},
+		"until_first_parent": {
+			start:       "c8",
+			firstParent: true,
+			until:       time.Date(2020, time.December, 1, 15, 4, 0, 0, time.UTC),
+			expected:    []string{"c3", "c1"},
+		},
+		"since_until": {
+			start:    "c8",
+			since:    time.Date(2020, time.December, 1, 15, 3, 0, 0, time.UTC),
+			until:    time.Date(2020, time.December, 1, 15, 7, 0, 0, time.UTC),
+			expected: []string{"c7", "c6", "c5", "c4", "c3"},
+		},
 	}
 	for name, tst := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -888,8 +1064,12 @@ func TestManager_LogGraph(t *testing.T) {
 			if !tst.since.IsZero() {
 				since = &tst.since
 			}
+			var until *time.Time
+			if !tst.until.IsZero() {
+				until = &tst.until
+			}
 
-			it, err := r.Log(ctx, repository, commitNameToID[tst.start], tst.firstParent, since)
+			it, err := r.Log(ctx, repository, commitNameToID[tst.start], tst.firstParent, since, until)
 			if err != nil {
 				t.Fatal("Error during create Log iterator", err)
 			}
@@ -0,0 +1,95 @@
+package ref
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+// ReflogIterator iterates over the reflog entries of a branch. Entry IDs sort newest first.
+type ReflogIterator struct {
+	ctx           context.Context
+	it            kv.MessageIterator
+	err           error
+	value         *graveler.ReflogEntry
+	repoPartition string
+	branchID      graveler.BranchID
+	store         kv.Store
+	closed        bool
+}
+
+func NewReflogIterator(ctx context.Context, store kv.Store, repo *graveler.RepositoryRecord, branchID graveler.BranchID) (*ReflogIterator, error) {
+	repoPartition := graveler.RepoPartition(repo)
+	it, err := kv.NewPrimaryIterator(ctx, store, (&graveler.ReflogEntryData{}).ProtoReflect().Type(),
+		repoPartition, []byte(graveler.ReflogPath(branchID, "")), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return nil, err
+	}
+	return &ReflogIterator{
+		ctx:           ctx,
+		it:            it,
+		store:         store,
+		repoPartition: repoPartition,
+		branchID:      branchID,
+	}, nil
+}
+
+func (i *ReflogIterator) Next() bool {
+	if i.Err() != nil || i.closed {
+		return false
+	}
+	if !i.it.Next() {
+		i.value = nil
+		return false
+	}
+	e := i.it.Entry()
+	if e == nil {
+		i.err = graveler.ErrReadingFromStore
+		return false
+	}
+	entry, ok := e.Value.(*graveler.ReflogEntryData)
+	if !ok {
+		i.err = graveler.ErrReadingFromStore
+		return false
+	}
+	i.value = graveler.ReflogEntryFromProto(entry)
+	return true
+}
+
+func (i *ReflogIterator) SeekGE(id string) {
+	if i.Err() != nil {
+		return
+	}
+	i.Close()
+	i.it, i.err = kv.NewPrimaryIterator(i.ctx, i.store, (&graveler.ReflogEntryData{}).ProtoReflect().Type(),
+		i.repoPartition,
+		[]byte(graveler.ReflogPath(i.branchID, "")), kv.IteratorOptionsFrom([]byte(graveler.ReflogPath(i.branchID, id))))
+	i.value = nil
+	i.closed = i.err != nil
+}
+
+func (i *ReflogIterator) Value() *graveler.ReflogEntry {
+	if i.Err() != nil {
+		return nil
+	}
+	return i.value
+}
+
+func (i *ReflogIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	if !i.closed {
+		return i.it.Err()
+	}
+	return nil
+}
+
+func (i *ReflogIterator) Close() {
+	if i.closed {
+		return
+	}
+	i.it.Close()
+	i.closed = true
+}
@@ -51,7 +51,7 @@ func TestResolveRawRef(t *testing.T) {
 		ts = ts.Add(time.Minute)
 	}
 
-	iter, err := r.Log(ctx, repository, previous, false, nil)
+	iter, err := r.Log(ctx, repository, previous, false, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -99,13 +99,13 @@ func TestResolveRawRef(t *testing.T) {
 	testutil.Must(t, r.SetBranch(ctx, repository, "branch1", graveler.Branch{
 		CommitID:     branch1CommitID,
 		StagingToken: "token1",
-	}))
+	}, graveler.ReflogRecord{}))
 
 	branch2CommitID := commitLog[16]
 	testutil.Must(t, r.SetBranch(ctx, repository, "branch2", graveler.Branch{
 		CommitID:     branch2CommitID,
 		StagingToken: "token2",
-	}))
+	}, graveler.ReflogRecord{}))
 
 	tagCommitID := commitLog[9]
 	testutil.Must(t, r.CreateTag(ctx, repository, "v1.0", tagCommitID))
@@ -118,7 +118,7 @@ func TestResolveRawRef(t *testing.T) {
 	testutil.Must(t, r.SetBranch(ctx, repository, graveler.BranchID(branch3Name), graveler.Branch{
 		CommitID:     branch3CommitID,
 		StagingToken: "token3",
-	}))
+	}, graveler.ReflogRecord{}))
 
 	tag2Name := string(commitLog[6])[:10]
 	tag2CommitID := commitLog[8]
@@ -307,7 +307,7 @@ func TestResolveRef_SameDate(t *testing.T) {
 	c4 := addCommit("c4", c3)
 	c5 := addCommit("c5", c4, c2)
 
-	it, err := r.Log(ctx, repository, c5, false, nil)
+	it, err := r.Log(ctx, repository, c5, false, nil, nil)
 	testutil.MustDo(t, "Log request", err)
 	var commitIDs []graveler.CommitID
 	for it.Next() {
@@ -0,0 +1,20 @@
+package graveler
+
+import (
+	"context"
+)
+
+func (g *Graveler) ListReflog(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (ReflogIterator, error) {
+	return g.RefManager.ListReflog(ctx, repository, branchID)
+}
+
+func (g *Graveler) RestoreReflog(ctx context.Context, repository *RepositoryRecord, branchID BranchID, entryID string, opts ...SetOptionsFunc) (*Branch, error) {
+	entry, err := g.RefManager.GetReflogEntry(ctx, repository, branchID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.OldCommitID == "" {
+		return nil, ErrReflogNoPreviousHead
+	}
+	return g.updateBranch(ctx, repository, branchID, Ref(entry.OldCommitID), "restore", opts...)
+}
@@ -34,18 +34,18 @@ type GarbageCollectionManager struct {
 	committedBlockStoragePrefix string
 }
 
-func (m *GarbageCollectionManager) GetCommitsCSVLocation(runID string, sn graveler.StorageNamespace) (string, error) {
+func (m *GarbageCollectionManager) GetCommitsCSVLocation(runID string, storageID graveler.StorageID, sn graveler.StorageNamespace) (string, error) {
 	key := fmt.Sprintf(commitsFileSuffixTemplate, m.committedBlockStoragePrefix, runID)
-	qk, err := m.blockAdapter.ResolveNamespace(sn.String(), key, block.IdentifierTypeRelative)
+	qk, err := m.blockAdapter.ResolveNamespace(storageID.String(), sn.String(), key, block.IdentifierTypeRelative)
 	if err != nil {
 		return "", err
 	}
 	return qk.Format(), nil
 }
 
-func (m *GarbageCollectionManager) GetAddressesLocation(sn graveler.StorageNamespace) (string, error) {
+func (m *GarbageCollectionManager) GetAddressesLocation(storageID graveler.StorageID, sn graveler.StorageNamespace) (string, error) {
 	key := fmt.Sprintf(addressesFilePrefixTemplate, m.committedBlockStoragePrefix)
-	qk, err := m.blockAdapter.ResolveNamespace(sn.String(), key, block.IdentifierTypeRelative)
+	qk, err := m.blockAdapter.ResolveNamespace(storageID.String(), sn.String(), key, block.IdentifierTypeRelative)
 	if err != nil {
 		return "", err
 	}
@@ -53,9 +53,9 @@ func (m *GarbageCollectionManager) GetAddressesLocation(sn graveler.StorageNames
 }
 
 // GetUncommittedLocation return full path to underlying storage path to store uncommitted information
-func (m *GarbageCollectionManager) GetUncommittedLocation(runID string, sn graveler.StorageNamespace) (string, error) {
+func (m *GarbageCollectionManager) GetUncommittedLocation(runID string, storageID graveler.StorageID, sn graveler.StorageNamespace) (string, error) {
 	key := fmt.Sprintf(uncommittedFilePrefixTemplate, m.committedBlockStoragePrefix, runID)
-	qk, err := m.blockAdapter.ResolveNamespace(sn.String(), key, block.IdentifierTypeRelative)
+	qk, err := m.blockAdapter.ResolveNamespace(storageID.String(), sn.String(), key, block.IdentifierTypeRelative)
 	if err != nil {
 		return "", err
 	}
@@ -63,7 +63,7 @@ func (m *GarbageCollectionManager) GetUncommittedLocation(runID string, sn grave
 }
 
 func (m *GarbageCollectionManager) SaveGarbageCollectionUncommitted(ctx context.Context, repository *graveler.RepositoryRecord, filename, runID string) error {
-	location, err := m.GetUncommittedLocation(runID, repository.StorageNamespace)
+	location, err := m.GetUncommittedLocation(runID, repository.StorageID, repository.StorageNamespace)
 	if err != nil {
 		return err
 	}
@@ -82,6 +82,7 @@ func (m *GarbageCollectionManager) SaveGarbageCollectionUncommitted(ctx context.
 	}
 	location += filename
 	return m.blockAdapter.Put(ctx, block.ObjectPointer{
+		StorageID:      repository.StorageID.String(),
 		Identifier:     location,
 		IdentifierType: block.IdentifierTypeFull,
 	}, stat.Size(), fd, block.PutOpts{})
@@ -104,8 +105,9 @@ func NewGarbageCollectionManager(blockAdapter block.Adapter, refManager graveler
 	}
 }
 
-func (m *GarbageCollectionManager) GetRules(ctx context.Context, storageNamespace graveler.StorageNamespace) (*graveler.GarbageCollectionRules, error) {
+func (m *GarbageCollectionManager) GetRules(ctx context.Context, storageID graveler.StorageID, storageNamespace graveler.StorageNamespace) (*graveler.GarbageCollectionRules, error) {
 	objectPointer := block.ObjectPointer{
+		StorageID:        storageID.String(),
 		StorageNamespace: string(storageNamespace),
 		Identifier:       fmt.Sprintf(configFileSuffixTemplate, m.committedBlockStoragePrefix),
 		IdentifierType:   block.IdentifierTypeRelative,
@@ -136,39 +138,49 @@ func (m *GarbageCollectionManager) GetRules(ctx context.Context, storageNamespac
 	return &rules, nil
 }
 
-func (m *GarbageCollectionManager) SaveRules(ctx context.Context, storageNamespace graveler.StorageNamespace, rules *graveler.GarbageCollectionRules) error {
+func (m *GarbageCollectionManager) SaveRules(ctx context.Context, storageID graveler.StorageID, storageNamespace graveler.StorageNamespace, rules *graveler.GarbageCollectionRules) error {
 	rulesBytes, err := proto.Marshal(rules)
 	if err != nil {
 		return err
 	}
 	return m.blockAdapter.Put(ctx, block.ObjectPointer{
+		StorageID:        storageID.String(),
 		StorageNamespace: string(storageNamespace),
 		Identifier:       fmt.Sprintf(configFileSuffixTemplate, m.committedBlockStoragePrefix),
 		IdentifierType:   block.IdentifierTypeRelative,
 	}, int64(len(rulesBytes)), bytes.NewReader(rulesBytes), block.PutOpts{})
 }
 
-func (m *GarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (string, error) {
+// GetActiveCommits returns the commits retained by the rules, mapped to their metarange IDs
+func (m *GarbageCollectionManager) GetActiveCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (map[graveler.CommitID]graveler.MetaRangeID, error) {
 	commitGetter := &RepositoryCommitGetter{
 		refManager: m.refManager,
 		repository: repository,
 	}
 	branchIterator, err := m.refManager.GCBranchIterator(ctx, repository)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer branchIterator.Close()
 	// get all commits that are not the first parent of any commit:
 	commitIterator, err := m.refManager.GCCommitIterator(ctx, repository)
 	if err != nil {
-		return "", fmt.Errorf("create kv orderd commit iterator commits: %w", err)
+		return nil, fmt.Errorf("create kv orderd commit iterator commits: %w", err)
 	}
 	defer commitIterator.Close()
 	startingPointIterator := NewGCStartingPointIterator(commitIterator, branchIterator)
 	defer startingPointIterator.Close()
-	gcCommits, err := GetGarbageCollectionCommits(ctx, startingPointIterator, commitGetter, rules)
+	activeCommits, err := GetGarbageCollectionCommits(ctx, startingPointIterator, commitGetter, rules)
 	if err != nil {
-		return "", fmt.Errorf("find expired commits: %w", err)
+		return nil, fmt.Errorf("find expired commits: %w", err)
+	}
+	return activeCommits, nil
+}
+
+func (m *GarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (string, error) {
+	gcCommits, err := m.GetActiveCommits(ctx, repository, rules)
+	if err != nil {
+		return "", err
 	}
 	b := &strings.Builder{}
 	csvWriter := csv.NewWriter(b)
@@ -190,11 +202,12 @@ func (m *GarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Cont
 	}
 	commitsStr := b.String()
 	runID := m.NewID()
-	csvLocation, err := m.GetCommitsCSVLocation(runID, repository.StorageNamespace)
+	csvLocation, err := m.GetCommitsCSVLocation(runID, repository.StorageID, repository.StorageNamespace)
 	if err != nil {
 		return "", err
 	}
 	err = m.blockAdapter.Put(ctx, block.ObjectPointer{
+		StorageID:      repository.StorageID.String(),
 		Identifier:     csvLocation,
 		IdentifierType: block.IdentifierTypeFull,
 	}, int64(len(commitsStr)), strings.NewReader(commitsStr), block.PutOpts{})
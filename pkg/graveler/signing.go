@@ -0,0 +1,119 @@
+package graveler
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+)
+
+// CommitSigner signs new commits and verifies commit signatures. The signed content is the commit Identity, the same
+// canonical content the commit ID is derived from.
+type CommitSigner interface {
+	// Sign returns a detached signature of the commit, or nil in case no signing key is configured
+	Sign(commit Commit) ([]byte, error)
+	// Verify reports whether the commit signature was made by one of the trusted keys
+	Verify(commit Commit) bool
+}
+
+// Ed25519CommitSigner signs commits using an optional ed25519 private key and verifies signatures made by the
+// trusted public keys. The public key of the signing key is always trusted.
+type Ed25519CommitSigner struct {
+	privateKey  ed25519.PrivateKey
+	trustedKeys []ed25519.PublicKey
+}
+
+func NewEd25519CommitSigner(privateKey ed25519.PrivateKey, trustedKeys []ed25519.PublicKey) *Ed25519CommitSigner {
+	keys := make([]ed25519.PublicKey, 0, len(trustedKeys)+1)
+	if privateKey != nil {
+		keys = append(keys, privateKey.Public().(ed25519.PublicKey))
+	}
+	keys = append(keys, trustedKeys...)
+	return &Ed25519CommitSigner{
+		privateKey:  privateKey,
+		trustedKeys: keys,
+	}
+}
+
+func (s *Ed25519CommitSigner) Sign(commit Commit) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, nil
+	}
+	return ed25519.Sign(s.privateKey, commit.Identity()), nil
+}
+
+func (s *Ed25519CommitSigner) Verify(commit Commit) bool {
+	if len(commit.Signature) != ed25519.SignatureSize {
+		return false
+	}
+	identity := commit.Identity()
+	for _, key := range s.trustedKeys {
+		if ed25519.Verify(key, identity, commit.Signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEd25519PrivateKey parses a base64 encoded ed25519 private key, either the 32 bytes seed or the full 64 bytes key
+func ParseEd25519PrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode private key: %w", err)
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, fmt.Errorf("private key size %d: %w", len(b), ErrInvalidValue)
+	}
+}
+
+// ParseEd25519PublicKey parses a base64 encoded ed25519 public key
+func ParseEd25519PublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key: %w", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key size %d: %w", len(b), ErrInvalidValue)
+	}
+	return b, nil
+}
+
+// SetCommitSigner sets the signer used to sign new commits and verify commit signatures
+func (g *Graveler) SetCommitSigner(signer CommitSigner) {
+	g.signer = signer
+}
+
+func (g *Graveler) VerifyCommitSignature(commit Commit) bool {
+	if g.signer == nil || commit.Signature == nil {
+		return false
+	}
+	return g.signer.Verify(commit)
+}
+
+// signCommit signs the commit using the server signing key, unless the commit is already signed. A commit that remains
+// unsigned is rejected in case the branch protection rules require signed commits on the branch.
+func (g *Graveler) signCommit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, commit *Commit) error {
+	if commit.Signature == nil && g.signer != nil {
+		signature, err := g.signer.Sign(*commit)
+		if err != nil {
+			return fmt.Errorf("sign commit: %w", err)
+		}
+		commit.Signature = signature
+	}
+	if commit.Signature != nil || branchID == "" {
+		return nil
+	}
+	requireSigned, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_UNSIGNED_COMMIT)
+	if err != nil {
+		return err
+	}
+	if requireSigned {
+		return ErrUnsignedCommit
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package graveler_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestEd25519CommitSigner(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustedPublicKey, trustedPrivateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := graveler.Commit{
+		Committer:    "committer",
+		Message:      "message",
+		MetaRangeID:  "metarange",
+		CreationDate: time.Unix(1700000000, 0),
+		Parents:      graveler.CommitParents{"parent"},
+		Metadata:     graveler.Metadata{"key": "value"},
+	}
+
+	signer := graveler.NewEd25519CommitSigner(privateKey, []ed25519.PublicKey{trustedPublicKey})
+	signature, err := signer.Sign(commit)
+	if err != nil {
+		t.Fatalf("Sign() error: %s", err)
+	}
+	if !ed25519.Verify(publicKey, commit.Identity(), signature) {
+		t.Fatal("Sign() signature does not match the commit identity")
+	}
+
+	tests := []struct {
+		name      string
+		signature []byte
+		modify    func(c *graveler.Commit)
+		expected  bool
+	}{
+		{name: "signing_key", signature: signature, expected: true},
+		{name: "trusted_key", signature: ed25519.Sign(trustedPrivateKey, commit.Identity()), expected: true},
+		{name: "unsigned", signature: nil, expected: false},
+		{name: "modified_message", signature: signature, modify: func(c *graveler.Commit) { c.Message = "other" }, expected: false},
+		{name: "modified_metadata", signature: signature, modify: func(c *graveler.Commit) { c.Metadata = graveler.Metadata{"key": "other"} }, expected: false},
+		{name: "generation_not_signed", signature: signature, modify: func(c *graveler.Commit) { c.Generation = 10 }, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := commit
+			c.Signature = tt.signature
+			if tt.modify != nil {
+				tt.modify(&c)
+			}
+			if verified := signer.Verify(c); verified != tt.expected {
+				t.Fatalf("Verify() = %t, expected %t", verified, tt.expected)
+			}
+		})
+	}
+
+	t.Run("verify_only", func(t *testing.T) {
+		verifier := graveler.NewEd25519CommitSigner(nil, []ed25519.PublicKey{trustedPublicKey})
+		signature, err := verifier.Sign(commit)
+		if err != nil || signature != nil {
+			t.Fatalf("Sign() without key = %v, %v, expected no signature", signature, err)
+		}
+		c := commit
+		c.Signature = ed25519.Sign(privateKey, commit.Identity())
+		if verifier.Verify(c) {
+			t.Fatal("Verify() accepted signature of untrusted key")
+		}
+	})
+}
+
+func TestParseEd25519Keys(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, encoded := range []string{
+		base64.StdEncoding.EncodeToString(privateKey.Seed()),
+		base64.StdEncoding.EncodeToString(privateKey),
+	} {
+		key, err := graveler.ParseEd25519PrivateKey(encoded)
+		if err != nil {
+			t.Fatalf("ParseEd25519PrivateKey() error: %s", err)
+		}
+		if !key.Equal(privateKey) {
+			t.Fatal("ParseEd25519PrivateKey() returned a different key")
+		}
+	}
+	key, err := graveler.ParseEd25519PublicKey(base64.StdEncoding.EncodeToString(publicKey))
+	if err != nil {
+		t.Fatalf("ParseEd25519PublicKey() error: %s", err)
+	}
+	if !key.Equal(publicKey) {
+		t.Fatal("ParseEd25519PublicKey() returned a different key")
+	}
+	if _, err := graveler.ParseEd25519PublicKey(base64.StdEncoding.EncodeToString([]byte("short"))); !errors.Is(err, graveler.ErrInvalidValue) {
+		t.Fatalf("ParseEd25519PublicKey() error = %v, expected %s", err, graveler.ErrInvalidValue)
+	}
+}
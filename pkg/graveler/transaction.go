@@ -0,0 +1,469 @@
+package graveler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+	"golang.org/x/exp/slices"
+)
+
+type TransactionOperationType string
+
+const (
+	// TransactionOperationCommit commits the uncommitted changes of the branch
+	TransactionOperationCommit TransactionOperationType = "commit"
+	// TransactionOperationMerge merges Ref into the branch
+	TransactionOperationMerge TransactionOperationType = "merge"
+	// TransactionOperationReset hard resets the branch to Ref
+	TransactionOperationReset TransactionOperationType = "reset"
+)
+
+// TransactionOperation is a single branch changing operation applied as part of a transaction
+type TransactionOperation struct {
+	Type     TransactionOperationType
+	BranchID BranchID
+	// Ref is the merge source or the reset target. A ref to a branch modified earlier in the same transaction
+	// resolves to the branch head as of that point in the transaction.
+	Ref Ref
+	// CommitParams holds the commit information of commit and merge operations
+	CommitParams CommitParams
+	// Strategy is the merge strategy of merge operations
+	Strategy string
+	// MergeResolver resolves the conflicts of merge operations, Strategy then only names it
+	MergeResolver MergeResolver
+}
+
+// TransactionParams describes a set of operations that are applied on the branches of a repository all together or
+// not at all.
+type TransactionParams struct {
+	// Preconditions maps branches to the commit ID expected as their head when the transaction is applied
+	Preconditions map[BranchID]CommitID
+	// Operations to apply, in order
+	Operations []TransactionOperation
+}
+
+// txBranch tracks a branch changed by a transaction
+type txBranch struct {
+	// branch is the branch as read before computing the transaction
+	branch *Branch
+	// head is the head commit of the branch after the operations computed so far
+	head *CommitRecord
+	// dirty is set while the branch has sealed changes that were not committed by the transaction
+	dirty bool
+	// committed is set once the sealed changes of the branch were committed by the transaction
+	committed bool
+	// hasOps is set for branches changed by the transaction, branches with only a precondition are verified
+	hasOps bool
+}
+
+func (g *Graveler) Transaction(ctx context.Context, repository *RepositoryRecord, params TransactionParams, opts ...SetOptionsFunc) (map[BranchID]CommitID, error) {
+	options := &SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if repository.ReadOnly && !options.Force {
+		return nil, ErrReadOnlyRepository
+	}
+	if len(params.Operations) == 0 {
+		return nil, fmt.Errorf("transaction operations: %w", ErrInvalidValue)
+	}
+	record := ReflogRecord{Operation: "transaction", User: options.User}
+
+	branches := make(map[BranchID]*txBranch)
+	for _, op := range params.Operations {
+		if err := g.checkTransactionOperation(ctx, repository, op); err != nil {
+			return nil, err
+		}
+		branches[op.BranchID] = &txBranch{hasOps: true}
+	}
+	for branchID := range params.Preconditions {
+		if _, ok := branches[branchID]; !ok {
+			branches[branchID] = &txBranch{}
+		}
+	}
+	// update branches in a stable order
+	branchIDs := make([]BranchID, 0, len(branches))
+	for branchID := range branches {
+		branchIDs = append(branchIDs, branchID)
+	}
+	sort.Slice(branchIDs, func(i, j int) bool { return branchIDs[i] < branchIDs[j] })
+
+	// seal the staging area of the changed branches, so the transaction applies to a fixed set of changes
+	for _, branchID := range branchIDs {
+		tx := branches[branchID]
+		err := g.retryBranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+			if err := checkTransactionPrecondition(params.Preconditions, branchID, branch); err != nil {
+				return nil, err
+			}
+			if !tx.hasOps {
+				return nil, nil
+			}
+			branch.SealedTokens = append([]StagingToken{branch.StagingToken}, branch.SealedTokens...)
+			branch.StagingToken = GenerateStagingToken(repository.RepositoryID, branchID)
+			return branch, nil
+		}, record)
+		if err != nil {
+			return nil, err
+		}
+
+		branch, err := g.RefManager.GetBranch(ctx, repository, branchID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTransactionPrecondition(params.Preconditions, branchID, branch); err != nil {
+			return nil, err
+		}
+		tx.branch = branch
+		tx.head, err = g.dereferenceCommit(ctx, repository, branch.CommitID.Ref())
+		if err != nil {
+			return nil, fmt.Errorf("get commit from ref %s: %w", branch.CommitID, err)
+		}
+		empty, err := g.isSealedEmpty(ctx, repository, branch)
+		if err != nil {
+			return nil, fmt.Errorf("%s: check if dirty: %w", branchID, err)
+		}
+		tx.dirty = !empty
+	}
+
+	// compute the new head of each branch, new commits are not referenced until the transaction is applied
+	var postHooks []HookRecord
+	for _, op := range params.Operations {
+		postHook, err := g.computeTransactionOperation(ctx, repository, branches, op)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Type, op.BranchID, err)
+		}
+		if postHook != nil {
+			postHooks = append(postHooks, *postHook)
+		}
+	}
+
+	err := g.lockBranches(ctx, repository, branchIDs, func() error {
+		return g.applyTransaction(ctx, repository, branchIDs, branches, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[BranchID]CommitID)
+	for _, branchID := range branchIDs {
+		tx := branches[branchID]
+		if !tx.hasOps {
+			continue
+		}
+		result[branchID] = tx.head.CommitID
+		if tx.committed {
+			g.dropTokens(ctx, tx.branch.SealedTokens...)
+		}
+	}
+	g.runTransactionPostHooks(ctx, postHooks)
+	return result, nil
+}
+
+func checkTransactionPrecondition(preconditions map[BranchID]CommitID, branchID BranchID, branch *Branch) error {
+	expected, ok := preconditions[branchID]
+	if !ok || expected == branch.CommitID {
+		return nil
+	}
+	return fmt.Errorf("branch %s head is %s, expected %s: %w", branchID, branch.CommitID, expected, ErrBranchHeadMismatch)
+}
+
+// checkTransactionOperation validates the operation and verifies that the branch protection rules allow it
+func (g *Graveler) checkTransactionOperation(ctx context.Context, repository *RepositoryRecord, op TransactionOperation) error {
+	if err := ValidateBranchID(op.BranchID); err != nil {
+		return err
+	}
+	blockedActions := []BranchProtectionBlockedAction{BranchProtectionBlockedAction_COMMIT}
+	switch op.Type {
+	case TransactionOperationCommit:
+	case TransactionOperationMerge, TransactionOperationReset:
+		if err := ValidateRef(op.Ref); err != nil {
+			return err
+		}
+		if op.Type == TransactionOperationReset {
+			blockedActions = append(blockedActions, BranchProtectionBlockedAction_STAGING_WRITE)
+		}
+	default:
+		return fmt.Errorf("transaction operation type '%s': %w", op.Type, ErrInvalidValue)
+	}
+	for _, action := range blockedActions {
+		isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, op.BranchID, action)
+		if err != nil {
+			return err
+		}
+		if isProtected {
+			return fmt.Errorf("%s: %w", op.BranchID, ErrProtectedBranch)
+		}
+	}
+	return nil
+}
+
+// transactionRef dereferences ref to a commit, using the transaction state for branches changed by the transaction
+func (g *Graveler) transactionRef(ctx context.Context, repository *RepositoryRecord, branches map[BranchID]*txBranch, ref Ref) (*CommitRecord, error) {
+	if tx, ok := branches[BranchID(ref)]; ok && tx.hasOps {
+		return tx.head, nil
+	}
+	commit, err := g.dereferenceCommit(ctx, repository, ref)
+	if err != nil {
+		return nil, fmt.Errorf("get commit from ref %s: %w", ref, err)
+	}
+	return commit, nil
+}
+
+// computeTransactionOperation updates the branch state with the result of the operation. It returns the post hook to
+// run in case the transaction is applied.
+func (g *Graveler) computeTransactionOperation(ctx context.Context, repository *RepositoryRecord, branches map[BranchID]*txBranch, op TransactionOperation) (*HookRecord, error) {
+	tx := branches[op.BranchID]
+	switch op.Type {
+	case TransactionOperationCommit:
+		return g.computeTransactionCommit(ctx, repository, tx, op)
+	case TransactionOperationMerge:
+		if tx.dirty {
+			return nil, ErrDirtyBranch
+		}
+		return g.computeTransactionMerge(ctx, repository, branches, tx, op)
+	case TransactionOperationReset:
+		if tx.dirty {
+			return nil, ErrDirtyBranch
+		}
+		commit, err := g.transactionRef(ctx, repository, branches, op.Ref)
+		if err != nil {
+			return nil, err
+		}
+		tx.head = commit
+		return nil, nil
+	default:
+		return nil, ErrInvalidValue
+	}
+}
+
+func (g *Graveler) computeTransactionCommit(ctx context.Context, repository *RepositoryRecord, tx *txBranch, op TransactionOperation) (*HookRecord, error) {
+	commit := NewCommit()
+	if op.CommitParams.Date != nil {
+		commit.CreationDate = time.Unix(*op.CommitParams.Date, 0)
+	}
+	commit.Committer = op.CommitParams.Committer
+	commit.Message = op.CommitParams.Message
+	commit.Metadata = op.CommitParams.Metadata
+	commit.Parents = CommitParents{tx.head.CommitID}
+	commit.Generation = tx.head.Generation + 1
+
+	preRunID, err := g.runTransactionPreHook(ctx, repository, HookRecord{
+		EventType: EventTypePreCommit,
+		SourceRef: op.BranchID.Ref(),
+		BranchID:  op.BranchID,
+		Commit:    commit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tx.dirty:
+		changes, err := g.sealedTokensIterator(ctx, tx.branch, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer changes.Close()
+		commit.MetaRangeID, _, err = g.CommittedManager.Commit(ctx, repository.StorageID, repository.StorageNamespace, tx.head.MetaRangeID, changes, op.CommitParams.AllowEmpty)
+		if err != nil {
+			return nil, fmt.Errorf("commit: %w", err)
+		}
+	case op.CommitParams.AllowEmpty:
+		commit.MetaRangeID = tx.head.MetaRangeID
+	default:
+		return nil, ErrNoChanges
+	}
+	if err := g.signCommit(ctx, repository, op.BranchID, &commit); err != nil {
+		return nil, err
+	}
+	commitID, err := g.RefManager.AddCommit(ctx, repository, commit)
+	if err != nil {
+		return nil, fmt.Errorf("add commit: %w", err)
+	}
+	tx.head = &CommitRecord{CommitID: commitID, Commit: &commit}
+	tx.committed = tx.committed || tx.dirty
+	tx.dirty = false
+	return g.transactionPostHook(repository, EventTypePostCommit, op.BranchID, commitID, commit, preRunID), nil
+}
+
+func (g *Graveler) computeTransactionMerge(ctx context.Context, repository *RepositoryRecord, branches map[BranchID]*txBranch, tx *txBranch, op TransactionOperation) (*HookRecord, error) {
+	fromCommit, err := g.transactionRef(ctx, repository, branches, op.Ref)
+	if err != nil {
+		return nil, err
+	}
+	toCommit := tx.head
+	baseCommit, err := g.RefManager.FindMergeBase(ctx, repository, fromCommit.CommitID, toCommit.CommitID)
+	if err != nil {
+		return nil, fmt.Errorf("find merge base: %w", err)
+	}
+	if baseCommit == nil {
+		return nil, ErrNoMergeBase
+	}
+	mergeStrategy, err := parseMergeStrategy(op.Strategy, op.MergeResolver)
+	if err != nil {
+		return nil, err
+	}
+	metaRangeID, err := g.CommittedManager.Merge(ctx, repository.StorageID, repository.StorageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID, mergeStrategy, op.MergeResolver)
+	if err != nil {
+		if !errors.Is(err, ErrUserVisible) {
+			err = fmt.Errorf("merge in CommitManager: %w", err)
+		}
+		return nil, err
+	}
+
+	commit := NewCommit()
+	commit.Committer = op.CommitParams.Committer
+	commit.Message = op.CommitParams.Message
+	if commit.Message == "" {
+		commit.Message = fmt.Sprintf("Merge '%s' into '%s'", op.Ref, op.BranchID)
+	}
+	commit.MetaRangeID = metaRangeID
+	commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
+	if toCommit.Generation > fromCommit.Generation {
+		commit.Generation = toCommit.Generation + 1
+	} else {
+		commit.Generation = fromCommit.Generation + 1
+	}
+	commit.Metadata = make(map[string]string, len(op.CommitParams.Metadata)+1)
+	for k, v := range op.CommitParams.Metadata {
+		commit.Metadata[k] = v
+	}
+	if op.MergeResolver != nil && op.Strategy != "" {
+		commit.Metadata[MergeStrategyMetadataKey] = op.Strategy
+	} else {
+		commit.Metadata[MergeStrategyMetadataKey] = mergeStrategyString[mergeStrategy]
+	}
+
+	preRunID, err := g.runTransactionPreHook(ctx, repository, HookRecord{
+		EventType: EventTypePreMerge,
+		SourceRef: fromCommit.CommitID.Ref(),
+		BranchID:  op.BranchID,
+		Commit:    commit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := g.signCommit(ctx, repository, op.BranchID, &commit); err != nil {
+		return nil, err
+	}
+	commitID, err := g.RefManager.AddCommit(ctx, repository, commit)
+	if err != nil {
+		return nil, fmt.Errorf("add commit: %w", err)
+	}
+	tx.head = &CommitRecord{CommitID: commitID, Commit: &commit}
+	return g.transactionPostHook(repository, EventTypePostMerge, op.BranchID, commitID, commit, preRunID), nil
+}
+
+// runTransactionPreHook runs the pre-commit or pre-merge hook described by record, and returns its run ID
+func (g *Graveler) runTransactionPreHook(ctx context.Context, repository *RepositoryRecord, record HookRecord) (string, error) {
+	if repository.ReadOnly {
+		return "", nil
+	}
+	record.RunID = g.hooks.NewRunID()
+	record.RepositoryID = repository.RepositoryID
+	record.StorageNamespace = repository.StorageNamespace
+	record.StorageID = repository.StorageID
+	var err error
+	if record.EventType == EventTypePreMerge {
+		err = g.hooks.PreMergeHook(ctx, record)
+	} else {
+		err = g.hooks.PreCommitHook(ctx, record)
+	}
+	if err != nil {
+		return "", &HookAbortError{
+			EventType: record.EventType,
+			RunID:     record.RunID,
+			Err:       err,
+		}
+	}
+	return record.RunID, nil
+}
+
+func (g *Graveler) transactionPostHook(repository *RepositoryRecord, eventType EventType, branchID BranchID, commitID CommitID, commit Commit, preRunID string) *HookRecord {
+	if repository.ReadOnly {
+		return nil
+	}
+	return &HookRecord{
+		EventType:        eventType,
+		RepositoryID:     repository.RepositoryID,
+		StorageID:        repository.StorageID,
+		StorageNamespace: repository.StorageNamespace,
+		SourceRef:        commitID.Ref(),
+		BranchID:         branchID,
+		Commit:           commit,
+		CommitID:         commitID,
+		PreRunID:         preRunID,
+	}
+}
+
+func (g *Graveler) runTransactionPostHooks(ctx context.Context, postHooks []HookRecord) {
+	for _, record := range postHooks {
+		record.RunID = g.hooks.NewRunID()
+		var err error
+		if record.EventType == EventTypePostMerge {
+			err = g.hooks.PostMergeHook(ctx, record)
+		} else {
+			err = g.hooks.PostCommitHook(ctx, record)
+		}
+		if err != nil {
+			g.log(ctx).
+				WithError(err).
+				WithField("run_id", record.RunID).
+				WithField("pre_run_id", record.PreRunID).
+				Errorf("Transaction %s hook failed", record.EventType)
+		}
+	}
+}
+
+// SetBranchLocker sets the locker of the branches updated by transactions. The lock only excludes other transactions,
+// commits and merges do not take it, and it expires after its TTL. Without a locker, transactions rely on conditional
+// branch updates only.
+func (g *Graveler) SetBranchLocker(locker BranchLocker) {
+	g.branchLocker = locker
+}
+
+// lockBranches calls fn holding the locks of the branches, taken in the order of branchIDs. Concurrent transactions
+// take the locks of their branches in the same order, so they do not deadlock.
+func (g *Graveler) lockBranches(ctx context.Context, repository *RepositoryRecord, branchIDs []BranchID, fn func() error) error {
+	if g.branchLocker == nil || len(branchIDs) == 0 {
+		return fn()
+	}
+	_, err := g.branchLocker.MetadataUpdater(ctx, repository, branchIDs[0], func() (interface{}, error) {
+		return nil, g.lockBranches(ctx, repository, branchIDs[1:], fn)
+	})
+	return err
+}
+
+// applyTransaction moves the branches to their new heads at once. In case any of the branches changed since it was
+// read no branch is moved, and the transaction fails with ErrPreconditionFailed.
+// The branches are locked by the caller, branch updates that do not take the lock are detected by the conditional
+// update of the branches.
+func (g *Graveler) applyTransaction(ctx context.Context, repository *RepositoryRecord, branchIDs []BranchID, branches map[BranchID]*txBranch, record ReflogRecord) error {
+	updates := make(map[BranchID]BranchUpdateFunc, len(branchIDs))
+	for _, branchID := range branchIDs {
+		branchID := branchID
+		tx := branches[branchID]
+		updates[branchID] = func(branch *Branch) (*Branch, error) {
+			if branch.CommitID != tx.branch.CommitID || !slices.Equal(branch.SealedTokens, tx.branch.SealedTokens) {
+				return nil, fmt.Errorf("branch %s changed during transaction: %w", branchID, ErrPreconditionFailed)
+			}
+			if !tx.hasOps {
+				return nil, nil
+			}
+			branch.CommitID = tx.head.CommitID
+			if tx.committed {
+				branch.SealedTokens = []StagingToken{}
+			}
+			return branch, nil
+		}
+	}
+	err := g.RefManager.BranchesUpdate(ctx, repository, record, updates)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return fmt.Errorf("branches changed during transaction: %w (%s)", ErrPreconditionFailed, err)
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package graveler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/batch"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/graveler/staging"
+	"github.com/treeverse/lakefs/pkg/graveler/testutil"
+	"github.com/treeverse/lakefs/pkg/ident"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"go.uber.org/ratelimit"
+)
+
+// interceptingRefManager calls beforeUpdate before each branch update, with the number of updates of the branch
+// so far, and beforeApply before the branches are updated together
+type interceptingRefManager struct {
+	graveler.RefManager
+	updates      map[graveler.BranchID]int
+	beforeUpdate func(branchID graveler.BranchID, n int)
+	beforeApply  func()
+}
+
+func (m *interceptingRefManager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, record graveler.ReflogRecord, f graveler.BranchUpdateFunc) error {
+	m.updates[branchID]++
+	if m.beforeUpdate != nil {
+		m.beforeUpdate(branchID, m.updates[branchID])
+	}
+	return m.RefManager.BranchUpdate(ctx, repository, branchID, record, f)
+}
+
+func (m *interceptingRefManager) BranchesUpdate(ctx context.Context, repository *graveler.RepositoryRecord, record graveler.ReflogRecord, updates map[graveler.BranchID]graveler.BranchUpdateFunc) error {
+	if m.beforeApply != nil {
+		m.beforeApply()
+	}
+	return m.RefManager.BranchesUpdate(ctx, repository, record, updates)
+}
+
+type transactionTest struct {
+	g          *graveler.Graveler
+	refManager *interceptingRefManager
+	kvStore    kv.Store
+	repository *graveler.RepositoryRecord
+	// base is the head of the branches, target and other are commits on top of it
+	base, target, other graveler.CommitID
+}
+
+func newTransactionTest(t *testing.T) *transactionTest {
+	t.Helper()
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	storeLimited := kv.NewStoreLimiter(kvStore, ratelimit.NewUnlimited())
+	refManager := &interceptingRefManager{
+		RefManager: ref.NewRefManager(ref.ManagerConfig{
+			Executor:        batch.NopExecutor(),
+			KVStore:         kvStore,
+			KVStoreLimited:  storeLimited,
+			AddressProvider: ident.NewHexAddressProvider(),
+		}),
+		updates: make(map[graveler.BranchID]int),
+	}
+	stagingManager := staging.NewManager(ctx, kvStore, storeLimited, false, batch.NopExecutor())
+	g := graveler.NewGraveler(&testutil.CommittedFake{}, stagingManager, refManager, nil, testutil.NewProtectedBranchesManagerFake(), nil)
+
+	repository, err := g.CreateRepository(ctx, "repo", "", "mem://repo", "main", false)
+	require.NoError(t, err)
+	mainBranch, err := g.GetBranch(ctx, repository, "main")
+	require.NoError(t, err)
+	addCommit := func(message string) graveler.CommitID {
+		commit := graveler.NewCommit()
+		commit.Message = message
+		commit.Parents = graveler.CommitParents{mainBranch.CommitID}
+		commit.Generation = 2
+		commitID, err := refManager.AddCommit(ctx, repository, commit)
+		require.NoError(t, err)
+		return commitID
+	}
+	test := &transactionTest{
+		g:          g,
+		refManager: refManager,
+		kvStore:    kvStore,
+		repository: repository,
+		base:       mainBranch.CommitID,
+		target:     addCommit("target"),
+		other:      addCommit("other"),
+	}
+	for _, branchID := range []graveler.BranchID{"a", "b"} {
+		_, err := g.CreateBranch(ctx, repository, branchID, "main")
+		require.NoError(t, err)
+	}
+	return test
+}
+
+// resetBoth is a transaction resetting branches a and b to the target commit
+func (tt *transactionTest) resetBoth() graveler.TransactionParams {
+	return graveler.TransactionParams{
+		Operations: []graveler.TransactionOperation{
+			{Type: graveler.TransactionOperationReset, BranchID: "a", Ref: graveler.Ref(tt.target)},
+			{Type: graveler.TransactionOperationReset, BranchID: "b", Ref: graveler.Ref(tt.target)},
+		},
+	}
+}
+
+// moveBranch moves the head of the branch, as a concurrent update
+func (tt *transactionTest) moveBranch(t *testing.T, branchID graveler.BranchID, commitID graveler.CommitID) {
+	t.Helper()
+	err := tt.refManager.RefManager.BranchUpdate(context.Background(), tt.repository, branchID, graveler.ReflogRecord{}, func(branch *graveler.Branch) (*graveler.Branch, error) {
+		branch.CommitID = commitID
+		return branch, nil
+	})
+	require.NoError(t, err)
+}
+
+func (tt *transactionTest) requireHead(t *testing.T, branchID graveler.BranchID, commitID graveler.CommitID) {
+	t.Helper()
+	branch, err := tt.g.GetBranch(context.Background(), tt.repository, branchID)
+	require.NoError(t, err)
+	require.Equal(t, commitID, branch.CommitID, "head of branch %s", branchID)
+}
+
+func TestGraveler_Transaction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("applied", func(t *testing.T) {
+		tt := newTransactionTest(t)
+		tt.g.SetBranchLocker(ref.NewBranchLocker(tt.kvStore))
+		heads, err := tt.g.Transaction(ctx, tt.repository, tt.resetBoth())
+		require.NoError(t, err)
+		require.Equal(t, map[graveler.BranchID]graveler.CommitID{"a": tt.target, "b": tt.target}, heads)
+		tt.requireHead(t, "a", tt.target)
+		tt.requireHead(t, "b", tt.target)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		tt := newTransactionTest(t)
+		// b moves after the transaction was computed, before it is applied
+		tt.refManager.beforeApply = func() {
+			tt.moveBranch(t, "b", tt.other)
+		}
+		_, err := tt.g.Transaction(ctx, tt.repository, tt.resetBoth())
+		require.ErrorIs(t, err, graveler.ErrPreconditionFailed)
+		// no branch is moved by the transaction
+		tt.requireHead(t, "a", tt.base)
+		tt.requireHead(t, "b", tt.other)
+	})
+
+	t.Run("unchanged_precondition", func(t *testing.T) {
+		tt := newTransactionTest(t)
+		// c is only verified by the transaction, and moves before it is applied
+		_, err := tt.g.CreateBranch(ctx, tt.repository, "c", "main")
+		require.NoError(t, err)
+		tt.refManager.beforeApply = func() {
+			tt.moveBranch(t, "c", tt.other)
+		}
+		params := tt.resetBoth()
+		params.Preconditions = map[graveler.BranchID]graveler.CommitID{"c": tt.base}
+		_, err = tt.g.Transaction(ctx, tt.repository, params)
+		require.ErrorIs(t, err, graveler.ErrPreconditionFailed)
+		tt.requireHead(t, "a", tt.base)
+		tt.requireHead(t, "b", tt.base)
+	})
+
+	t.Run("pre_hook", func(t *testing.T) {
+		tt := newTransactionTest(t)
+		tt.repository.StorageID = "storage"
+		hooks := &Hooks{}
+		tt.g.SetHooksHandler(hooks)
+		_, err := tt.g.Transaction(ctx, tt.repository, graveler.TransactionParams{
+			Operations: []graveler.TransactionOperation{
+				{Type: graveler.TransactionOperationCommit, BranchID: "a", CommitParams: graveler.CommitParams{AllowEmpty: true}},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, hooks.Called)
+		require.Equal(t, tt.repository.RepositoryID, hooks.RepositoryID)
+		require.Equal(t, tt.repository.StorageID, hooks.StorageID)
+		require.Equal(t, tt.repository.StorageNamespace, hooks.StorageNamespace)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		tt := newTransactionTest(t)
+		locker := ref.NewBranchLocker(tt.kvStore, ref.WithBranchLockWait(100*time.Millisecond))
+		tt.g.SetBranchLocker(locker)
+		// b is locked by another transaction while this one applies
+		_, err := locker.MetadataUpdater(ctx, tt.repository, "b", func() (interface{}, error) {
+			return tt.g.Transaction(ctx, tt.repository, tt.resetBoth())
+		})
+		require.ErrorIs(t, err, graveler.ErrLockNotAcquired)
+		tt.requireHead(t, "a", tt.base)
+		tt.requireHead(t, "b", tt.base)
+	})
+}
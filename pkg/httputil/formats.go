@@ -26,3 +26,16 @@ func StripQuotesAndSpaces(etag string) string {
 		return r == '"' || r == ' '
 	})
 }
+
+// ParseETagList returns the ETags of an If-Match or If-None-Match header value, a comma separated list of ETags or
+// "*", without their quotes. Weak ETags are returned as their opaque value.
+func ParseETagList(value string) []string {
+	var etags []string
+	for _, etag := range strings.Split(value, ",") {
+		etag = StripQuotesAndSpaces(strings.TrimPrefix(strings.TrimSpace(etag), "W/"))
+		if etag != "" {
+			etags = append(etags, etag)
+		}
+	}
+	return etags
+}
@@ -348,6 +348,36 @@ func (s *Store) Delete(ctx context.Context, partitionKey, key []byte) error {
 	return nil
 }
 
+func (s *Store) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	if len(partitionKey) == 0 {
+		return kv.ErrMissingPartitionKey
+	}
+	if len(key) == 0 {
+		return kv.ErrMissingKey
+	}
+	pk := azcosmos.NewPartitionKeyString(encoding.EncodeToString(partitionKey))
+
+	itemOptions := azcosmos.ItemOptions{}
+	switch valuePredicate {
+	case nil: // a missing key can't be deleted
+		return kv.ErrPredicateFailed
+	case kv.PrecondConditionalExists:
+	default:
+		pred, ok := valuePredicate.([]byte)
+		if !ok {
+			return kv.ErrPredicateFailed
+		}
+		etag := azcore.ETag(pred)
+		itemOptions.IfMatchEtag = &etag
+	}
+	_, err := s.containerClient.DeleteItem(ctx, pk, s.hashID(key), &itemOptions)
+	err = convertError(err)
+	if errors.Is(err, kv.ErrNotFound) {
+		return kv.ErrPredicateFailed
+	}
+	return err
+}
+
 func (s *Store) Scan(ctx context.Context, partitionKey []byte, options kv.ScanOptions) (kv.EntriesIterator, error) {
 	if len(partitionKey) == 0 {
 		return nil, kv.ErrMissingPartitionKey
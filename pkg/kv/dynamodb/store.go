@@ -339,6 +339,14 @@ func (s *Store) setWithOptionalPredicate(ctx context.Context, partitionKey, key,
 }
 
 func (s *Store) Delete(ctx context.Context, partitionKey, key []byte) error {
+	return s.deleteWithOptionalPredicate(ctx, partitionKey, key, nil, false)
+}
+
+func (s *Store) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	return s.deleteWithOptionalPredicate(ctx, partitionKey, key, valuePredicate, true)
+}
+
+func (s *Store) deleteWithOptionalPredicate(ctx context.Context, partitionKey, key []byte, valuePredicate kv.Predicate, usePredicate bool) error {
 	if len(partitionKey) == 0 {
 		return kv.ErrMissingPartitionKey
 	}
@@ -346,13 +354,42 @@ func (s *Store) Delete(ctx context.Context, partitionKey, key []byte) error {
 		return kv.ErrMissingKey
 	}
 
-	resp, err := s.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+	input := &dynamodb.DeleteItemInput{
 		TableName:              aws.String(s.params.TableName),
 		Key:                    s.bytesKeyToDynamoKey(partitionKey, key),
 		ReturnConsumedCapacity: types.ReturnConsumedCapacityTotal,
-	})
+	}
+	if usePredicate {
+		switch valuePredicate {
+		case nil: // a missing key can't be deleted
+			return kv.ErrPredicateFailed
+
+		case kv.PrecondConditionalExists: // delete only if exists
+			input.ConditionExpression = aws.String("attribute_exists(" + ItemValue + ")")
+
+		default: // delete only if predicate matches the current stored value
+			pred, ok := valuePredicate.([]byte)
+			if !ok {
+				return kv.ErrPredicateFailed
+			}
+			predicateCondition := expression.Name(ItemValue).Equal(expression.Value(pred))
+			conditionExpression, err := expression.NewBuilder().WithCondition(predicateCondition).Build()
+			if err != nil {
+				return fmt.Errorf("build condition expression: %w", err)
+			}
+			input.ExpressionAttributeNames = conditionExpression.Names()
+			input.ExpressionAttributeValues = conditionExpression.Values()
+			input.ConditionExpression = conditionExpression.Condition()
+		}
+	}
+
+	resp, err := s.svc.DeleteItem(ctx, input)
 	const operation = "DeleteItem"
 	if err != nil {
+		var errConditionalCheckFailed *types.ConditionalCheckFailedException
+		if usePredicate && errors.As(err, &errConditionalCheckFailed) {
+			return kv.ErrPredicateFailed
+		}
 		if s.isSlowDownErr(err) {
 			s.logger.WithField("partition_key", partitionKey).WithContext(ctx).Error("delete item: %w", kv.ErrSlowDown)
 			dynamoSlowdown.WithLabelValues(operation).Inc()
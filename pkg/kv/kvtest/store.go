@@ -56,6 +56,7 @@ func DriverTest(t *testing.T, ms MakeStore) {
 	t.Run("Store_SetGet", func(t *testing.T) { testStoreSetGet(t, ms) })
 	t.Run("Store_SetIf", func(t *testing.T) { testStoreSetIf(t, ms) })
 	t.Run("Store_Delete", func(t *testing.T) { testStoreDelete(t, ms) })
+	t.Run("Store_DeleteIf", func(t *testing.T) { testStoreDeleteIf(t, ms) })
 	t.Run("Store_Scan", func(t *testing.T) { testStoreScan(t, ms) })
 	t.Run("Store_MissingArgument", func(t *testing.T) { testStoreMissingArgument(t, ms) })
 	t.Run("Store_ContextCancelled", func(t *testing.T) { testStoreContextCancelled(t, ms) })
@@ -230,6 +231,83 @@ func testStoreDelete(t *testing.T, ms MakeStore) {
 	})
 }
 
+func testStoreDeleteIf(t *testing.T, ms MakeStore) {
+	ctx := context.Background()
+	store := ms(t, ctx)
+	defer store.Close()
+
+	t.Run("delete_predicate_value", func(t *testing.T) {
+		key := uniqueKey("delete-if-value")
+		val := []byte("v")
+		err := store.Set(ctx, []byte(testPartitionKey), key, val)
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s value=%s: %s", key, val, err)
+		}
+		res, err := store.Get(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Get while testing DeleteIf - key=%s: %s", key, err)
+		}
+		err = store.DeleteIf(ctx, []byte(testPartitionKey), key, res.Predicate)
+		if err != nil {
+			t.Fatalf("DeleteIf with current value - key=%s pred=%s: %s", key, val, err)
+		}
+		_, err = store.Get(ctx, []byte(testPartitionKey), key)
+		if !errors.Is(err, kv.ErrNotFound) {
+			t.Fatalf("Get after DeleteIf - key=%s: err=%v, expected %s", key, err, kv.ErrNotFound)
+		}
+	})
+
+	t.Run("fail_predicate_changed", func(t *testing.T) {
+		key := uniqueKey("delete-if-changed")
+		val1 := []byte("v1")
+		err := store.Set(ctx, []byte(testPartitionKey), key, val1)
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s value=%s: %s", key, val1, err)
+		}
+		res, err := store.Get(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Get while testing DeleteIf - key=%s: %s", key, err)
+		}
+		val2 := []byte("v2")
+		err = store.Set(ctx, []byte(testPartitionKey), key, val2)
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s value=%s: %s", key, val2, err)
+		}
+		err = store.DeleteIf(ctx, []byte(testPartitionKey), key, res.Predicate)
+		if !errors.Is(err, kv.ErrPredicateFailed) {
+			t.Fatalf("DeleteIf err=%v - key=%s, pred=%s, expected err=%s", err, key, val1, kv.ErrPredicateFailed)
+		}
+		res, err = store.Get(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Get after failed DeleteIf - key=%s: %s", key, err)
+		}
+		if !bytes.Equal(res.Value, val2) {
+			t.Fatalf("Get after failed DeleteIf - key=%s: value=%s, expected=%s", key, res.Value, val2)
+		}
+	})
+
+	t.Run("fail_predicate_missing", func(t *testing.T) {
+		key := uniqueKey("delete-if-missing")
+		err := store.DeleteIf(ctx, []byte(testPartitionKey), key, kv.PrecondConditionalExists)
+		if !errors.Is(err, kv.ErrPredicateFailed) {
+			t.Fatalf("DeleteIf err=%v - key=%s, expected err=%s", err, key, kv.ErrPredicateFailed)
+		}
+	})
+
+	t.Run("delete_if_exists", func(t *testing.T) {
+		key := uniqueKey("delete-if-exists")
+		val := []byte("v")
+		err := store.Set(ctx, []byte(testPartitionKey), key, val)
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s value=%s: %s", key, val, err)
+		}
+		err = store.DeleteIf(ctx, []byte(testPartitionKey), key, kv.PrecondConditionalExists)
+		if err != nil {
+			t.Fatalf("DeleteIf exists - key=%s: %s", key, err)
+		}
+	})
+}
+
 func testStoreSetIf(t *testing.T, ms MakeStore) {
 	ctx := context.Background()
 	store := ms(t, ctx)
@@ -190,6 +190,52 @@ func (s *Store) Delete(ctx context.Context, partitionKey, key []byte) error {
 	return nil
 }
 
+func (s *Store) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	k := composeKey(partitionKey, key)
+	start := time.Now()
+	log := s.logger.WithField("key", string(k)).WithField("op", "delete_if").WithContext(ctx)
+	log.Trace("performing operation")
+	if len(partitionKey) == 0 {
+		log.WithError(kv.ErrMissingPartitionKey).Warn("got empty partition key")
+		return kv.ErrMissingPartitionKey
+	}
+	if len(key) == 0 {
+		log.WithError(kv.ErrMissingKey).Warn("got empty key")
+		return kv.ErrMissingKey
+	}
+
+	err := s.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(k)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			log.Trace("predicate condition failed (key not found)")
+			return kv.ErrPredicateFailed
+		}
+		if err != nil {
+			log.WithError(err).Error("could not get key for predicate")
+			return err
+		}
+		if valuePredicate != kv.PrecondConditionalExists {
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				log.WithError(err).Error("could not get byte value for predicate")
+				return err
+			}
+			pred, ok := valuePredicate.([]byte)
+			if !ok || !bytes.Equal(val, pred) {
+				log.WithField("predicate", valuePredicate).WithField("value", val).Trace("predicate condition failed")
+				return kv.ErrPredicateFailed
+			}
+		}
+		return txn.Delete(k)
+	})
+	if errors.Is(err, badger.ErrConflict) { // Return predicate failed on transaction conflict - to retry
+		log.WithError(err).Trace("transaction conflict")
+		err = kv.ErrPredicateFailed
+	}
+	log.WithField("took", time.Since(start)).Trace("operation complete")
+	return err
+}
+
 func (s *Store) Scan(ctx context.Context, partitionKey []byte, options kv.ScanOptions) (kv.EntriesIterator, error) {
 	log := s.logger.WithFields(logging.Fields{
 		"partition_key": string(partitionKey),
@@ -140,6 +140,31 @@ func (s *Store) SetIf(_ context.Context, partitionKey, key, value []byte, valueP
 	return nil
 }
 
+func (s *Store) DeleteIf(_ context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	if len(partitionKey) == 0 {
+		return kv.ErrMissingPartitionKey
+	}
+	if len(key) == 0 {
+		return kv.ErrMissingKey
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sKey := encodeKey(key)
+	curr, currOK := s.m[string(partitionKey)][sKey]
+	if !currOK {
+		return fmt.Errorf("key=%v: %w", key, kv.ErrPredicateFailed)
+	}
+	if valuePredicate != kv.PrecondConditionalExists {
+		pred, ok := valuePredicate.([]byte)
+		if !ok || !bytes.Equal(pred, curr.Value) {
+			return fmt.Errorf("%w: partition=%s, key=%v, encoding=%s", kv.ErrPredicateFailed, partitionKey, key, sKey)
+		}
+	}
+	delete(s.m[string(partitionKey)], sKey)
+	return nil
+}
+
 func (s *Store) Delete(_ context.Context, partitionKey, key []byte) error {
 	if len(partitionKey) == 0 {
 		return kv.ErrMissingPartitionKey
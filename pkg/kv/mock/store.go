@@ -123,6 +123,20 @@ func (mr *MockStoreMockRecorder) Delete(ctx, partitionKey, key interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStore)(nil).Delete), ctx, partitionKey, key)
 }
 
+// DeleteIf mocks base method.
+func (m *MockStore) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIf", ctx, partitionKey, key, valuePredicate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIf indicates an expected call of DeleteIf.
+func (mr *MockStoreMockRecorder) DeleteIf(ctx, partitionKey, key, valuePredicate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIf", reflect.TypeOf((*MockStore)(nil).DeleteIf), ctx, partitionKey, key, valuePredicate)
+}
+
 // Get mocks base method.
 func (m *MockStore) Get(ctx context.Context, partitionKey, key []byte) (*kv.ValueWithPredicate, error) {
 	m.ctrl.T.Helper()
@@ -313,6 +313,41 @@ func (s *Store) Delete(ctx context.Context, partitionKey, key []byte) error {
 	return nil
 }
 
+func (s *Store) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	if len(partitionKey) == 0 {
+		return kv.ErrMissingPartitionKey
+	}
+	if len(key) == 0 {
+		return kv.ErrMissingKey
+	}
+
+	var (
+		res pgconn.CommandTag
+		err error
+	)
+	switch valuePredicate {
+	case nil: // a missing key can't be deleted
+		return kv.ErrPredicateFailed
+
+	case kv.PrecondConditionalExists: // delete only if exists
+		res, err = s.Pool.Exec(ctx, `DELETE FROM `+s.Params.SanitizedTableName+` WHERE partition_key=$1 AND key=$2`, partitionKey, key)
+
+	default: // delete just in case the current value is same as predicate value
+		pred, ok := valuePredicate.([]byte)
+		if !ok {
+			return kv.ErrPredicateFailed
+		}
+		res, err = s.Pool.Exec(ctx, `DELETE FROM `+s.Params.SanitizedTableName+` WHERE partition_key=$1 AND key=$2 AND value=$3`, partitionKey, key, pred)
+	}
+	if err != nil {
+		return fmt.Errorf("postgres deleteIf: %w", err)
+	}
+	if res.RowsAffected() != 1 {
+		return kv.ErrPredicateFailed
+	}
+	return nil
+}
+
 func (s *Store) Scan(ctx context.Context, partitionKey []byte, options kv.ScanOptions) (kv.EntriesIterator, error) {
 	if len(partitionKey) == 0 {
 		return nil, kv.ErrMissingPartitionKey
@@ -100,6 +100,10 @@ type Store interface {
 	// Delete will delete the key, no error in if key doesn't exist
 	Delete(ctx context.Context, partitionKey, key []byte) error
 
+	// DeleteIf deletes the key only if its current value matches valuePredicate, a predicate returned by Get.
+	//  It returns an ErrPredicateFailed error if the key was changed or no longer exists.
+	DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate Predicate) error
+
 	// Scan returns entries that can be read by key order
 	// partitionKey is optional, passing it might increase performance.
 	// 'options' holds optional parameters to control the batch size and the key to start the scan with.
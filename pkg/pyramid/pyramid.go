@@ -11,21 +11,22 @@ import (
 // FS is pyramid abstraction of filesystem where the persistent storage-layer is the block storage.
 // Files on the local disk are transient and might be cleaned up by the eviction policy.
 // File structure under a namespace and namespace itself are flat (no directories).
+// The storageID identifies the blockstore of the namespace, empty for the default blockstore.
 type FS interface {
 	// Create creates a new file in the FS.
 	// It will only be persistent after the returned file is stored.
-	Create(ctx context.Context, namespace string) (StoredFile, error)
+	Create(ctx context.Context, storageID, namespace string) (StoredFile, error)
 
 	// Open finds the referenced file and returns its read-only File.
 	// If file isn't in the local disk, it is fetched from the block storage.
-	Open(ctx context.Context, namespace, filename string) (File, error)
+	Open(ctx context.Context, storageID, namespace, filename string) (File, error)
 
 	// Exists returns true if filename currently exists on block storage.
-	Exists(ctx context.Context, namespace, filename string) (bool, error)
+	Exists(ctx context.Context, storageID, namespace, filename string) (bool, error)
 
 	// GetRemoteURI returns the URI for filename on block storage.  That URI might not
 	// resolve if filename does not exist.
-	GetRemoteURI(ctx context.Context, namespace, filename string) (string, error)
+	GetRemoteURI(ctx context.Context, storageID, namespace, filename string) (string, error)
 }
 
 // File is pyramid abstraction for an os.File
@@ -81,7 +81,7 @@ func PopulateSampleRepo(ctx context.Context, repo *catalog.Repository, cat *cata
 
 		// write file to storage
 		address := pathProvider.NewPath()
-		blob, err := upload.WriteBlob(ctx, blockAdapter, repo.StorageNamespace, address, contentReader, contentSize, block.PutOpts{})
+		blob, err := upload.WriteBlob(ctx, blockAdapter, repo.StorageID, repo.StorageNamespace, address, contentReader, contentSize, block.PutOpts{})
 		if err != nil {
 			return err
 		}
@@ -112,7 +112,7 @@ func PopulateSampleRepo(ctx context.Context, repo *catalog.Repository, cat *cata
 	// if we succeeded, commit the changes
 	// commit changes
 	_, err = cat.Commit(ctx, repo.Name, repo.DefaultBranch, sampleRepoCommitMsg,
-		user.Username, map[string]string{}, swag.Int64(time.Now().Unix()), nil, false)
+		user.Username, map[string]string{}, swag.Int64(time.Now().Unix()), nil, false, nil, nil)
 
 	return err
 }
@@ -12,13 +12,17 @@ type Blob struct {
 	PhysicalAddress string
 	RelativePath    bool
 	Checksum        string
+	Sha256          string
 	Size            int64
+	// Compression is the codec the blob was compressed with, empty for blobs stored as is
+	Compression string
 }
 
-func WriteBlob(ctx context.Context, adapter block.Adapter, bucketName, address string, body io.Reader, contentLength int64, opts block.PutOpts) (*Blob, error) {
+func WriteBlob(ctx context.Context, adapter block.Adapter, storageID, bucketName, address string, body io.Reader, contentLength int64, opts block.PutOpts) (*Blob, error) {
 	// handle the upload itself
 	hashReader := block.NewHashingReader(body, block.HashFunctionMD5, block.HashFunctionSHA256)
 	err := adapter.Put(ctx, block.ObjectPointer{
+		StorageID:        storageID,
 		StorageNamespace: bucketName,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       address,
@@ -31,6 +35,8 @@ func WriteBlob(ctx context.Context, adapter block.Adapter, bucketName, address s
 		PhysicalAddress: address,
 		RelativePath:    true,
 		Checksum:        checksum,
+		Sha256:          hex.EncodeToString(hashReader.Sha256.Sum(nil)),
 		Size:            hashReader.CopiedSize,
+		Compression:     opts.Compression,
 	}, nil
 }